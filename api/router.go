@@ -18,7 +18,9 @@ limitations under the License.
 package api
 
 import (
+	"bytes"
 	"fmt"
+	"io"
 	"net/http"
 	"strings"
 
@@ -35,6 +37,7 @@ import (
 	"github.com/apache/incubator-devlake/plugins/core"
 	"github.com/apache/incubator-devlake/services"
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
 )
 
 func RegisterRouter(r *gin.Engine) {
@@ -105,11 +108,16 @@ func handlePluginCall(pluginName string, handler core.ApiResourceHandler) func(c
 			if strings.HasPrefix(c.Request.Header.Get("Content-Type"), "multipart/form-data;") {
 				input.Request = c.Request
 			} else {
-				err = c.ShouldBindJSON(&input.Body)
+				err = c.ShouldBindBodyWith(&input.Body, binding.JSON)
 				if err != nil && err.Error() != "EOF" {
 					shared.ApiOutputError(c, err)
 					return
 				}
+				// keep the raw body readable for the handlers verifying request signatures
+				if rawBody, ok := c.Get(gin.BodyBytesKey); ok {
+					c.Request.Body = io.NopCloser(bytes.NewReader(rawBody.([]byte)))
+				}
+				input.Request = c.Request
 			}
 		}
 		output, err := handler(input)
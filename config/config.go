@@ -71,6 +71,7 @@ func setDefaultValue(v *viper.Viper) {
 	v.SetDefault("PLUGIN_DIR", "bin/plugins")
 	v.SetDefault("TEMPORAL_TASK_QUEUE", "DEVLAKE_TASK_QUEUE")
 	v.SetDefault("TAP_PROPERTIES_DIR", "config/tap")
+	v.SetDefault("CDC_RETENTION", "168h")
 }
 
 // replaceNewEnvItemInOldContent replace old config to new config in env file content
@@ -0,0 +1,181 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cdc
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/apache/incubator-devlake/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func testEvents() []*models.CdcEvent {
+	return []*models.CdcEvent{
+		{ID: 1, PipelineId: 7, DomainTable: "pull_requests", EntityId: "github:GithubPullRequest:1:2", Operation: models.CDC_INSERT},
+		{ID: 2, PipelineId: 7, DomainTable: "cicd_tasks", EntityId: "github:GithubRun:1:3", Operation: models.CDC_DELETE},
+	}
+}
+
+func TestNewPublisher(t *testing.T) {
+	p, err := NewPublisher("https://example.com/hook")
+	assert.Nil(t, err)
+	assert.IsType(t, &WebhookPublisher{}, p)
+
+	p, err = NewPublisher("nats://localhost/devlake/changes")
+	assert.Nil(t, err)
+	assert.Equal(t, "localhost:4222", p.(*NatsPublisher).addr)
+	assert.Equal(t, "devlake.changes", p.(*NatsPublisher).subject)
+
+	_, err = NewPublisher("kafka://localhost:9092")
+	assert.NotNil(t, err)
+
+	publishers, err := NewPublishers(" , nats://localhost:4223")
+	assert.Nil(t, err)
+	assert.Len(t, publishers, 1)
+}
+
+func TestWebhookPublisher(t *testing.T) {
+	var received []*models.CdcEvent
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		body, _ := io.ReadAll(r.Body)
+		assert.Nil(t, json.Unmarshal(body, &received))
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	p, err := NewPublisher(server.URL + "/cdc")
+	assert.Nil(t, err)
+	assert.Nil(t, p.Publish(context.Background(), testEvents()))
+	assert.Equal(t, testEvents(), received)
+
+	status = http.StatusInternalServerError
+	assert.NotNil(t, p.Publish(context.Background(), testEvents()))
+}
+
+func TestNdjsonPublisher(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "out", "cdc.ndjson")
+	p, err := NewPublisher("file://" + path)
+	assert.Nil(t, err)
+	assert.Nil(t, p.Publish(context.Background(), testEvents()))
+	assert.Nil(t, p.Publish(context.Background(), testEvents()[:1]))
+	assert.Nil(t, p.Close())
+
+	content, readErr := os.ReadFile(path)
+	assert.Nil(t, readErr)
+	lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+	assert.Len(t, lines, 3)
+	var event models.CdcEvent
+	assert.Nil(t, json.Unmarshal([]byte(lines[1]), &event))
+	assert.Equal(t, "cicd_tasks", event.DomainTable)
+	assert.Equal(t, models.CDC_DELETE, event.Operation)
+}
+
+// fakeNatsServer speaks just enough of the NATS client protocol to accept PUB messages
+func fakeNatsServer(t *testing.T, published chan<- string) net.Listener {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func(conn net.Conn) {
+				defer conn.Close()
+				fmt.Fprint(conn, "INFO {\"server_id\":\"fake\"}\r\n")
+				reader := bufio.NewReader(conn)
+				for {
+					line, err := reader.ReadString('\n')
+					if err != nil {
+						return
+					}
+					fields := strings.Fields(line)
+					switch fields[0] {
+					case "CONNECT":
+						if !strings.Contains(line, `"user":"lake"`) {
+							fmt.Fprint(conn, "-ERR 'Authorization Violation'\r\n")
+							return
+						}
+					case "PING":
+						fmt.Fprint(conn, "PONG\r\n")
+					case "PUB":
+						size, _ := strconv.Atoi(fields[2])
+						payload := make([]byte, size+2)
+						if _, err := io.ReadFull(reader, payload); err != nil {
+							return
+						}
+						published <- fields[1] + " " + string(payload[:size])
+					}
+				}
+			}(conn)
+		}
+	}()
+	return listener
+}
+
+func TestNatsPublisher(t *testing.T) {
+	published := make(chan string, 10)
+	listener := fakeNatsServer(t, published)
+	defer listener.Close()
+
+	p, err := NewPublisher(fmt.Sprintf("nats://lake:secret@%s", listener.Addr().String()))
+	assert.Nil(t, err)
+	defer p.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	assert.Nil(t, p.Publish(ctx, testEvents()))
+
+	first := <-published
+	assert.True(t, strings.HasPrefix(first, "devlake.cdc.pull_requests {"))
+	second := <-published
+	assert.True(t, strings.HasPrefix(second, "devlake.cdc.cicd_tasks {"))
+	var event models.CdcEvent
+	assert.Nil(t, json.Unmarshal([]byte(strings.SplitN(second, " ", 2)[1]), &event))
+	assert.Equal(t, uint64(7), event.PipelineId)
+
+	// the connection should be re-established after the server dropped it
+	p.(*NatsPublisher).conn.Close()
+	assert.NotNil(t, p.Publish(ctx, testEvents()[:1]))
+	assert.Nil(t, p.Publish(ctx, testEvents()[:1]))
+	assert.True(t, strings.HasPrefix(<-published, "devlake.cdc.pull_requests"))
+}
+
+func TestNatsPublisherAuthError(t *testing.T) {
+	listener := fakeNatsServer(t, make(chan string, 10))
+	defer listener.Close()
+
+	p, err := NewPublisher(fmt.Sprintf("nats://%s/devlake", listener.Addr().String()))
+	assert.Nil(t, err)
+	defer p.Close()
+	assert.NotNil(t, p.Publish(context.Background(), testEvents()))
+}
@@ -0,0 +1,85 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cdc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/apache/incubator-devlake/errors"
+	"github.com/apache/incubator-devlake/models"
+)
+
+// NdjsonPublisher appends the events to a local file, one json document per line
+type NdjsonPublisher struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+// NewNdjsonPublisher opens (or creates) the file specified by the `file://` url in append mode
+func NewNdjsonPublisher(u *url.URL) (Publisher, errors.Error) {
+	path := u.Path
+	if u.Host != "" {
+		// relative paths like file://logs/cdc.ndjson
+		path = filepath.Join(u.Host, u.Path)
+	}
+	if path == "" {
+		return nil, errors.BadInput.New(fmt.Sprintf("file url %s has no path", u.String()))
+	}
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return nil, errors.Convert(err)
+		}
+	}
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, errors.Convert(err)
+	}
+	return &NdjsonPublisher{file: file}, nil
+}
+
+// Publish writes all events and syncs the file so they survive a crash once acknowledged
+func (p *NdjsonPublisher) Publish(_ context.Context, events []*models.CdcEvent) errors.Error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var buf []byte
+	for _, event := range events {
+		line, err := json.Marshal(event)
+		if err != nil {
+			return errors.Convert(err)
+		}
+		buf = append(buf, line...)
+		buf = append(buf, '\n')
+	}
+	if _, err := p.file.Write(buf); err != nil {
+		return errors.Convert(err)
+	}
+	return errors.Convert(p.file.Sync())
+}
+
+// Close closes the underlying file
+func (p *NdjsonPublisher) Close() errors.Error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return errors.Convert(p.file.Close())
+}
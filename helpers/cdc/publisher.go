@@ -0,0 +1,103 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cdc
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/apache/incubator-devlake/errors"
+	"github.com/apache/incubator-devlake/models"
+)
+
+// Publisher delivers the change events recorded in the outbox to a downstream system
+type Publisher interface {
+	Publish(ctx context.Context, events []*models.CdcEvent) errors.Error
+	Close() errors.Error
+}
+
+// PublisherFactory creates a Publisher from the url configured in `CDC_PUBLISHERS`
+type PublisherFactory func(u *url.URL) (Publisher, errors.Error)
+
+var publishers = make(map[string]PublisherFactory)
+var publishersMu sync.RWMutex
+
+// RegisterPublisher makes a Publisher available for the urls with the given scheme
+func RegisterPublisher(scheme string, factory PublisherFactory) {
+	publishersMu.Lock()
+	defer publishersMu.Unlock()
+	publishers[strings.ToLower(scheme)] = factory
+}
+
+// Schemes returns the url schemes of all registered publishers
+func Schemes() []string {
+	publishersMu.RLock()
+	defer publishersMu.RUnlock()
+	schemes := make([]string, 0, len(publishers))
+	for scheme := range publishers {
+		schemes = append(schemes, scheme)
+	}
+	sort.Strings(schemes)
+	return schemes
+}
+
+// NewPublisher creates a Publisher by the scheme of the rawUrl, i.e.
+// `https://example.com/hook`, `file:///var/log/devlake/cdc.ndjson` or `nats://localhost:4222/devlake.cdc`
+func NewPublisher(rawUrl string) (Publisher, errors.Error) {
+	u, err := url.Parse(strings.TrimSpace(rawUrl))
+	if err != nil {
+		return nil, errors.BadInput.Wrap(err, fmt.Sprintf("invalid publisher url %s", rawUrl))
+	}
+	publishersMu.RLock()
+	factory, ok := publishers[strings.ToLower(u.Scheme)]
+	publishersMu.RUnlock()
+	if !ok {
+		return nil, errors.BadInput.New(fmt.Sprintf("unsupported publisher scheme %s, available: %s", u.Scheme, strings.Join(Schemes(), ",")))
+	}
+	return factory(u)
+}
+
+// NewPublishers creates Publishers for a comma separated list of urls
+func NewPublishers(rawUrls string) ([]Publisher, errors.Error) {
+	var result []Publisher
+	for _, rawUrl := range strings.Split(rawUrls, ",") {
+		if strings.TrimSpace(rawUrl) == "" {
+			continue
+		}
+		publisher, err := NewPublisher(rawUrl)
+		if err != nil {
+			for _, p := range result {
+				_ = p.Close()
+			}
+			return nil, err
+		}
+		result = append(result, publisher)
+	}
+	return result, nil
+}
+
+func init() {
+	RegisterPublisher("http", NewWebhookPublisher)
+	RegisterPublisher("https", NewWebhookPublisher)
+	RegisterPublisher("file", NewNdjsonPublisher)
+	RegisterPublisher("nats", NewNatsPublisher)
+}
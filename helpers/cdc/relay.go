@@ -0,0 +1,151 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cdc
+
+import (
+	"context"
+	"time"
+
+	"github.com/apache/incubator-devlake/errors"
+	"github.com/apache/incubator-devlake/models"
+	"github.com/apache/incubator-devlake/plugins/core"
+	"github.com/apache/incubator-devlake/plugins/core/dal"
+)
+
+const defaultRelayBatchSize = 500
+
+// Relay moves the unpublished events from the outbox to the publishers. Events are marked as published
+// only after all publishers accepted them, so the delivery is at-least-once. The published events are purged
+// once they are older than the retention
+type Relay struct {
+	db         dal.Dal
+	logger     core.Logger
+	publishers []Publisher
+	batchSize  int
+	retention  time.Duration
+}
+
+// NewRelay creates a Relay for the publishers, a retention of 0 keeps the events forever
+func NewRelay(db dal.Dal, logger core.Logger, publishers []Publisher, batchSize int, retention time.Duration) *Relay {
+	if batchSize <= 0 {
+		batchSize = defaultRelayBatchSize
+	}
+	return &Relay{
+		db:         db,
+		logger:     logger,
+		publishers: publishers,
+		batchSize:  batchSize,
+		retention:  retention,
+	}
+}
+
+// PublishPending publishes the outbox events in order until none is left, returns the number of published events
+func (r *Relay) PublishPending(ctx context.Context) (int, errors.Error) {
+	total := 0
+	if len(r.publishers) == 0 {
+		return total, nil
+	}
+	for {
+		if ctx.Err() != nil {
+			return total, errors.Convert(ctx.Err())
+		}
+		var events []*models.CdcEvent
+		err := r.db.All(
+			&events,
+			dal.Where("published_at IS NULL"),
+			dal.Orderby("id"),
+			dal.Limit(r.batchSize),
+		)
+		if err != nil {
+			return total, err
+		}
+		if len(events) == 0 {
+			return total, nil
+		}
+		for _, publisher := range r.publishers {
+			err = publisher.Publish(ctx, events)
+			if err != nil {
+				return total, err
+			}
+		}
+		ids := make([]uint64, len(events))
+		for i, event := range events {
+			ids[i] = event.ID
+		}
+		err = r.db.UpdateColumn(&models.CdcEvent{}, "published_at", time.Now(), dal.Where("id IN ?", ids))
+		if err != nil {
+			return total, err
+		}
+		total += len(events)
+		if len(events) < r.batchSize {
+			return total, nil
+		}
+	}
+}
+
+// Purge deletes the events published before the retention, the unpublished ones are kept unless there is no
+// publisher to deliver them. It deletes the snapshots left by the subtasks which never ran again as well
+func (r *Relay) Purge() errors.Error {
+	if r.retention <= 0 {
+		return nil
+	}
+	before := time.Now().Add(-r.retention)
+	where := dal.Where("published_at < ?", before)
+	if len(r.publishers) == 0 {
+		where = dal.Where("created_at < ?", before)
+	}
+	err := r.db.Delete(&models.CdcEvent{}, where)
+	if err != nil {
+		return err
+	}
+	return r.db.Delete(&models.CdcSnapshot{}, dal.Where("created_at < ?", before))
+}
+
+// Run publishes the pending events and purges the outdated ones periodically until the context is done,
+// the publishers are closed on exit
+func (r *Relay) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	defer r.Close()
+	for {
+		count, err := r.PublishPending(ctx)
+		if err != nil && ctx.Err() == nil {
+			r.logger.Error(err, "failed to publish cdc events")
+		} else if count > 0 {
+			r.logger.Debug("published %d cdc events", count)
+		}
+		err = r.Purge()
+		if err != nil && ctx.Err() == nil {
+			r.logger.Error(err, "failed to purge cdc events")
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Close closes all publishers
+func (r *Relay) Close() {
+	for _, publisher := range r.publishers {
+		if err := publisher.Close(); err != nil {
+			r.logger.Warn(err, "failed to close cdc publisher")
+		}
+	}
+}
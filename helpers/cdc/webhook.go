@@ -0,0 +1,77 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cdc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/apache/incubator-devlake/errors"
+	"github.com/apache/incubator-devlake/models"
+)
+
+// WebhookPublisher posts the events as a json array to an http endpoint
+type WebhookPublisher struct {
+	endpoint string
+	client   *http.Client
+}
+
+// NewWebhookPublisher creates a WebhookPublisher for the http(s) url
+func NewWebhookPublisher(u *url.URL) (Publisher, errors.Error) {
+	if u.Host == "" {
+		return nil, errors.BadInput.New(fmt.Sprintf("webhook url %s has no host", u.String()))
+	}
+	return &WebhookPublisher{
+		endpoint: u.String(),
+		client:   &http.Client{Timeout: 30 * time.Second},
+	}, nil
+}
+
+// Publish sends all events in one request, any non-2xx response is treated as a failure
+func (p *WebhookPublisher) Publish(ctx context.Context, events []*models.CdcEvent) errors.Error {
+	body, err := json.Marshal(events)
+	if err != nil {
+		return errors.Convert(err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return errors.Convert(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	res, err := p.client.Do(req)
+	if err != nil {
+		return errors.Default.Wrap(err, "failed to post cdc events")
+	}
+	defer res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		resBody, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+		return errors.HttpStatus(res.StatusCode).New(fmt.Sprintf("webhook responded %d: %s", res.StatusCode, resBody))
+	}
+	return nil
+}
+
+// Close does nothing for the webhook publisher
+func (p *WebhookPublisher) Close() errors.Error {
+	return nil
+}
@@ -0,0 +1,233 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sink
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/apache/incubator-devlake/errors"
+	"github.com/apache/incubator-devlake/plugins/core"
+	"github.com/apache/incubator-devlake/plugins/helper"
+)
+
+func init() {
+	RegisterDriver("clickhouse", func(options map[string]interface{}, logger core.Logger) (Driver, errors.Error) {
+		config := &ClickHouseConfig{}
+		err := helper.Decode(options, config, nil)
+		if err != nil {
+			return nil, err
+		}
+		return NewClickHouseDriver(config, logger), nil
+	})
+}
+
+// ClickHouseConfig points to the http interface of ClickHouse, i.e. http://localhost:8123
+type ClickHouseConfig struct {
+	Endpoint string
+	User     string
+	Password string
+	Database string
+	// Extra replaces the engine clause of the create table statement of the tables
+	Extra map[string]string
+}
+
+// ClickHouseDriver talks to the http interface, so no native driver is needed
+type ClickHouseDriver struct {
+	config *ClickHouseConfig
+	client *http.Client
+	logger core.Logger
+}
+
+func NewClickHouseDriver(config *ClickHouseConfig, logger core.Logger) *ClickHouseDriver {
+	if config.Endpoint == "" {
+		config.Endpoint = "http://localhost:8123"
+	}
+	if config.Database == "" {
+		config.Database = "default"
+	}
+	return &ClickHouseDriver{
+		config: config,
+		client: &http.Client{Timeout: 10 * time.Minute},
+		logger: logger,
+	}
+}
+
+// MapType analysis and return the data type of ClickHouse, the columns are made Nullable by CreateTable
+func (d *ClickHouseDriver) MapType(dataType string) string {
+	dataType = strings.ToLower(dataType)
+	switch {
+	case strings.HasSuffix(dataType, "[]"):
+		return fmt.Sprintf("Array(%s)", d.MapType(strings.TrimSuffix(dataType, "[]")))
+	case hasPrefixes(dataType, "datetime", "timestamp"):
+		return "DateTime64(3)"
+	case dataType == "date":
+		return "Date32"
+	case hasPrefixes(dataType, "bigint", "bigserial"):
+		return "Int64"
+	case stringIn(dataType, "tinyint(1)", "boolean", "bool"):
+		return "Bool"
+	case hasPrefixes(dataType, "smallint", "smallserial", "tinyint"):
+		return "Int16"
+	case hasPrefixes(dataType, "int", "integer", "serial", "mediumint"):
+		return "Int32"
+	case hasPrefixes(dataType, "real", "float"):
+		return "Float32"
+	case hasPrefixes(dataType, "double", "numeric"):
+		return "Float64"
+	case strings.HasPrefix(dataType, "decimal"):
+		return "Decimal(38, 10)"
+	case dataType == "uuid":
+		return "UUID"
+	}
+	return "String"
+}
+
+func (d *ClickHouseDriver) quote(name string) string {
+	return fmt.Sprintf("`%s`.`%s`", d.config.Database, name)
+}
+
+func (d *ClickHouseDriver) query(query string, body []byte) (string, errors.Error) {
+	params := url.Values{}
+	params.Set("database", d.config.Database)
+	// the rows are encoded with encoding/json, i.e. 2023-01-02T03:04:05.678Z
+	params.Set("date_time_input_format", "best_effort")
+	var reader io.Reader
+	if body == nil {
+		reader = strings.NewReader(query)
+	} else {
+		params.Set("query", query)
+		reader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s/?%s", strings.TrimSuffix(d.config.Endpoint, "/"), params.Encode()), reader)
+	if err != nil {
+		return "", errors.Convert(err)
+	}
+	if d.config.User != "" {
+		req.SetBasicAuth(d.config.User, d.config.Password)
+	}
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return "", errors.Convert(err)
+	}
+	defer resp.Body.Close()
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", errors.Convert(err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", errors.HttpStatus(resp.StatusCode).New(fmt.Sprintf("clickhouse query failed: %s", string(b)))
+	}
+	return string(b), nil
+}
+
+func (d *ClickHouseDriver) Watermark(table *Table, column string) (*time.Time, errors.Error) {
+	result, err := d.query(fmt.Sprintf("EXISTS TABLE %s", d.quote(table.Name)), nil)
+	if err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(result) != "1" {
+		return nil, nil
+	}
+	result, err = d.query(fmt.Sprintf("SELECT toUnixTimestamp64Milli(max(`%s`)) FROM %s", column, d.quote(table.Name)), nil)
+	if err != nil {
+		return nil, err
+	}
+	millis, e := strconv.ParseInt(strings.TrimSpace(result), 10, 64)
+	if e != nil {
+		return nil, errors.Convert(e)
+	}
+	updatedTo := time.UnixMilli(millis)
+	return &updatedTo, nil
+}
+
+func (d *ClickHouseDriver) CreateTable(table *Table, staging string) errors.Error {
+	isPrimaryKey := make(map[string]bool)
+	var pks []string
+	for _, pk := range table.PrimaryKeys() {
+		isPrimaryKey[pk] = true
+		pks = append(pks, fmt.Sprintf("`%s`", pk))
+	}
+	var columns []string
+	for _, c := range table.Columns {
+		columnType := c.Type
+		// the sorting key can not be Nullable and neither can arrays
+		if !isPrimaryKey[c.Name] && !strings.HasPrefix(columnType, "Array(") {
+			columnType = fmt.Sprintf("Nullable(%s)", columnType)
+		}
+		columns = append(columns, fmt.Sprintf("`%s` %s", c.Name, columnType))
+	}
+	extra := fmt.Sprintf("ENGINE = MergeTree ORDER BY (%s)", strings.Join(pks, ", "))
+	if v, ok := d.config.Extra[table.Source]; ok {
+		extra = v
+	}
+	_, err := d.query(fmt.Sprintf("DROP TABLE IF EXISTS %s", d.quote(staging)), nil)
+	if err != nil {
+		return err
+	}
+	tableSql := fmt.Sprintf("CREATE TABLE %s ( %s ) %s", d.quote(staging), strings.Join(columns, ", "), extra)
+	d.logger.Debug(tableSql)
+	_, err = d.query(tableSql, nil)
+	return err
+}
+
+func (d *ClickHouseDriver) Write(table *Table, staging string, rows []map[string]interface{}) errors.Error {
+	var body bytes.Buffer
+	encoder := json.NewEncoder(&body)
+	for _, row := range rows {
+		err := encoder.Encode(row)
+		if err != nil {
+			return errors.Convert(err)
+		}
+	}
+	_, err := d.query(fmt.Sprintf("INSERT INTO %s FORMAT JSONEachRow", d.quote(staging)), body.Bytes())
+	return err
+}
+
+// Swap exchanges the tables atomically, which requires the Atomic database engine, the default one since 20.10
+func (d *ClickHouseDriver) Swap(table *Table, staging string) errors.Error {
+	_, err := d.query(fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s AS %s", d.quote(table.Name), d.quote(staging)), nil)
+	if err != nil {
+		return err
+	}
+	_, err = d.query(fmt.Sprintf("EXCHANGE TABLES %s AND %s", d.quote(staging), d.quote(table.Name)), nil)
+	if err != nil {
+		return err
+	}
+	_, err = d.query(fmt.Sprintf("DROP TABLE IF EXISTS %s", d.quote(staging)), nil)
+	return err
+}
+
+func (d *ClickHouseDriver) Count(table *Table) (int64, errors.Error) {
+	result, err := d.query(fmt.Sprintf("SELECT count() FROM %s", d.quote(table.Name)), nil)
+	if err != nil {
+		return 0, err
+	}
+	return errors.Convert01(strconv.ParseInt(strings.TrimSpace(result), 10, 64))
+}
+
+func (d *ClickHouseDriver) Close() errors.Error {
+	return nil
+}
@@ -0,0 +1,403 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sink
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/apache/incubator-devlake/errors"
+	"github.com/apache/incubator-devlake/impl/dalgorm"
+	"github.com/apache/incubator-devlake/plugins/core"
+	"github.com/apache/incubator-devlake/plugins/core/dal"
+	"github.com/lib/pq"
+	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// Options selects the source tables and controls how they are loaded, they are shared by all targets
+type Options struct {
+	// SourceType and SourceDsn point to another database than the one of DevLake, mysql and postgres are supported
+	SourceType string `mapstructure:"source_type"`
+	SourceDsn  string `mapstructure:"source_dsn"`
+	// UpdateColumn is the watermark column, the tables whose latest value is already in the target are skipped
+	UpdateColumn string `mapstructure:"update_column"`
+	// Tables are regular expressions matching the source tables, all tables are loaded when both Tables
+	// and DomainLayer are empty
+	Tables      []string
+	DomainLayer string            `mapstructure:"domain_layer"`
+	BatchSize   int               `mapstructure:"batch_size"`
+	OrderBy     map[string]string `mapstructure:"order_by"`
+}
+
+const defaultBatchSize = 1000
+
+type sourceTable struct {
+	name string
+}
+
+func (t *sourceTable) TableName() string {
+	return t.name
+}
+
+// OpenSource connects to the source database given by the options, the close function is nil when the
+// database of DevLake is used
+func OpenSource(db dal.Dal, options *Options) (dal.Dal, func() error, errors.Error) {
+	if options.SourceDsn == "" || options.SourceType == "" {
+		return db, nil, nil
+	}
+	var dialector gorm.Dialector
+	switch options.SourceType {
+	case "mysql":
+		dialector = mysql.Open(options.SourceDsn)
+	case "postgres":
+		dialector = postgres.Open(options.SourceDsn)
+	default:
+		return nil, nil, errors.NotFound.New(fmt.Sprintf("unsupported source type %s", options.SourceType))
+	}
+	o, err := gorm.Open(dialector)
+	if err != nil {
+		return nil, nil, errors.Convert(err)
+	}
+	sqlDB, err := o.DB()
+	if err != nil {
+		return nil, nil, errors.Convert(err)
+	}
+	return dalgorm.NewDalgorm(o), sqlDB.Close, nil
+}
+
+// SelectTables returns the source tables to be loaded
+func SelectTables(db dal.Dal, options *Options) ([]string, errors.Error) {
+	if options.DomainLayer != "" {
+		tables := GetTablesByDomainLayer(options.DomainLayer)
+		if tables == nil {
+			return nil, errors.NotFound.New(fmt.Sprintf("no table found by domain layer: %s", options.DomainLayer))
+		}
+		return tables, nil
+	}
+	allTables, err := db.AllTables()
+	if err != nil {
+		return nil, err
+	}
+	return MatchTables(allTables, options.Tables)
+}
+
+// MatchTables filters the tables by the regular expressions, all tables are returned when there is none
+func MatchTables(allTables []string, patterns []string) ([]string, errors.Error) {
+	if len(patterns) == 0 {
+		return allTables, nil
+	}
+	regexps := make([]*regexp.Regexp, len(patterns))
+	for i, pattern := range patterns {
+		r, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, errors.BadInput.Wrap(err, fmt.Sprintf("invalid table pattern %s", pattern))
+		}
+		regexps[i] = r
+	}
+	var tables []string
+	for _, table := range allTables {
+		for _, r := range regexps {
+			if r.MatchString(table) {
+				tables = append(tables, table)
+				break
+			}
+		}
+	}
+	return tables, nil
+}
+
+// Loader copies the source tables into the target of the driver
+type Loader struct {
+	db      dal.Dal
+	driver  Driver
+	logger  core.Logger
+	options *Options
+}
+
+func NewLoader(db dal.Dal, driver Driver, logger core.Logger, options *Options) *Loader {
+	if options.BatchSize <= 0 {
+		options.BatchSize = defaultBatchSize
+	}
+	return &Loader{
+		db:      db,
+		driver:  driver,
+		logger:  logger,
+		options: options,
+	}
+}
+
+// Load copies every table into a staging table of the target and swaps it with the target table once all rows
+// are written, the tables whose watermark did not move since the last load are skipped
+func (l *Loader) Load(ctx context.Context, tables []string) errors.Error {
+	for _, name := range tables {
+		select {
+		case <-ctx.Done():
+			return errors.Convert(ctx.Err())
+		default:
+		}
+		table, err := l.MapTable(name)
+		if err != nil {
+			return err
+		}
+		upToDate, err := l.isUpToDate(table)
+		if err != nil {
+			return err
+		}
+		if upToDate {
+			l.logger.Info("table %s is up to date, so skip it", name)
+			continue
+		}
+		err = l.loadTable(table)
+		if err != nil {
+			return errors.Default.Wrap(err, fmt.Sprintf("failed to load table %s", name))
+		}
+	}
+	return nil
+}
+
+// MapTable reads the columns of the source table and maps their types with the driver
+func (l *Loader) MapTable(name string) (*Table, errors.Error) {
+	columnMetas, err := l.db.GetColumns(&sourceTable{name: name}, nil)
+	if err != nil {
+		if !strings.Contains(err.Error(), "cached plan must not change result type") {
+			return nil, err
+		}
+		l.logger.Warn(err, "skip err: cached plan must not change result type")
+		columnMetas, err = l.db.GetColumns(&sourceTable{name: name}, nil)
+		if err != nil {
+			return nil, err
+		}
+	}
+	table := &Table{
+		Source: name,
+		Name:   strings.TrimLeft(name, "_"),
+	}
+	for _, cm := range columnMetas {
+		columnType, ok := cm.ColumnType()
+		if !ok {
+			return nil, errors.Default.New(fmt.Sprintf("Get [%s] ColumeType Failed", cm.Name()))
+		}
+		isPrimaryKey, ok := cm.PrimaryKey()
+		table.Columns = append(table.Columns, &Column{
+			Name:       cm.Name(),
+			SourceType: columnType,
+			Type:       l.driver.MapType(columnType),
+			PrimaryKey: isPrimaryKey && ok,
+		})
+	}
+	return table, nil
+}
+
+func (l *Loader) isUpToDate(table *Table) (bool, errors.Error) {
+	updateColumn := l.options.UpdateColumn
+	if updateColumn == "" {
+		return false, nil
+	}
+	hasColumn := false
+	for _, c := range table.Columns {
+		if c.Name == updateColumn {
+			hasColumn = true
+			break
+		}
+	}
+	if !hasColumn {
+		return false, nil
+	}
+	rows, err := l.db.Cursor(
+		dal.From(table.Source),
+		dal.Select(updateColumn),
+		dal.Limit(1),
+		dal.Orderby(fmt.Sprintf("%s desc", updateColumn)),
+	)
+	if err != nil {
+		return false, err
+	}
+	defer rows.Close()
+	var updatedFrom time.Time
+	if rows.Next() {
+		err = errors.Convert(rows.Scan(&updatedFrom))
+		if err != nil {
+			return false, err
+		}
+	}
+	updatedTo, err := l.driver.Watermark(table, updateColumn)
+	if err != nil {
+		return false, err
+	}
+	return updatedTo != nil && updatedFrom.Equal(*updatedTo), nil
+}
+
+func (l *Loader) orderBy(table *Table) (string, errors.Error) {
+	if v, ok := l.options.OrderBy[table.Source]; ok {
+		return v, nil
+	}
+	var separator string
+	switch l.db.Dialect() {
+	case "postgres":
+		separator = "\""
+	case "mysql":
+		separator = "`"
+	default:
+		return "", errors.NotFound.New(fmt.Sprintf("unsupported dialect %s", l.db.Dialect()))
+	}
+	var orders []string
+	for _, pk := range table.PrimaryKeys() {
+		orders = append(orders, fmt.Sprintf("%s%s%s", separator, pk, separator))
+	}
+	return strings.Join(orders, ", "), nil
+}
+
+// beginSnapshot reads all batches of a table in a repeatable read transaction, so the rows do not move between pages
+func (l *Loader) beginSnapshot() errors.Error {
+	switch l.db.Dialect() {
+	case "postgres":
+		return l.db.Exec("begin transaction isolation level repeatable read")
+	case "mysql":
+		err := l.db.Exec("set session transaction isolation level repeatable read")
+		if err != nil {
+			return err
+		}
+		return l.db.Exec("start transaction")
+	default:
+		return errors.NotFound.New(fmt.Sprintf("unsupported dialect %s", l.db.Dialect()))
+	}
+}
+
+func (l *Loader) loadTable(table *Table) errors.Error {
+	orderBy, err := l.orderBy(table)
+	if err != nil {
+		return err
+	}
+	staging := fmt.Sprintf("%s_tmp", table.Name)
+	err = l.driver.CreateTable(table, staging)
+	if err != nil {
+		return err
+	}
+	err = l.beginSnapshot()
+	if err != nil {
+		return err
+	}
+	sourceCount, err := l.copyRows(table, staging, orderBy)
+	if err != nil {
+		_ = l.db.Exec("rollback")
+		return err
+	}
+	err = l.db.Exec("commit")
+	if err != nil {
+		return err
+	}
+	err = l.driver.Swap(table, staging)
+	if err != nil {
+		return err
+	}
+	targetCount, err := l.driver.Count(table)
+	if err != nil {
+		return err
+	}
+	if sourceCount != targetCount {
+		l.logger.Warn(nil, "source count %d not equal to target count %d of table %s", sourceCount, targetCount, table.Source)
+	}
+	l.logger.Info("load %s success", table.Source)
+	return nil
+}
+
+func (l *Loader) copyRows(table *Table, staging string, orderBy string) (int64, errors.Error) {
+	columns := make(map[string]*Column, len(table.Columns))
+	for _, c := range table.Columns {
+		columns[c.Name] = c
+	}
+	var total int64
+	for offset := 0; ; {
+		clauses := []dal.Clause{
+			dal.From(table.Source),
+			dal.Limit(l.options.BatchSize),
+			dal.Offset(offset),
+		}
+		if orderBy != "" {
+			clauses = append(clauses, dal.Orderby(orderBy))
+		}
+		data, err := l.readBatch(columns, clauses)
+		if err != nil {
+			return 0, err
+		}
+		if len(data) == 0 {
+			break
+		}
+		err = l.driver.Write(table, staging, data)
+		if err != nil {
+			return 0, err
+		}
+		l.logger.Debug("load %s, limit: %d, offset: %d", table.Source, l.options.BatchSize, offset)
+		offset += len(data)
+		total += int64(len(data))
+	}
+	return total, nil
+}
+
+func (l *Loader) readBatch(columns map[string]*Column, clauses []dal.Clause) ([]map[string]interface{}, errors.Error) {
+	rows, err := l.db.Cursor(clauses...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	cols, e := rows.Columns()
+	if e != nil {
+		return nil, errors.Convert(e)
+	}
+	var data []map[string]interface{}
+	for rows.Next() {
+		values := make([]interface{}, len(cols))
+		pointers := make([]interface{}, len(cols))
+		for i := range values {
+			if c, ok := columns[cols[i]]; ok && c.IsArray() {
+				var arr []string
+				values[i] = &arr
+				pointers[i] = pq.Array(&arr)
+			} else {
+				pointers[i] = &values[i]
+			}
+		}
+		e = rows.Scan(pointers...)
+		if e != nil {
+			return nil, errors.Convert(e)
+		}
+		row := make(map[string]interface{}, len(cols))
+		for i, name := range cols {
+			row[name] = normalizeValue(values[i])
+		}
+		data = append(data, row)
+	}
+	return data, nil
+}
+
+// normalizeValue turns the raw bytes returned by the mysql driver into strings, they would be base64 encoded otherwise
+func normalizeValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case []byte:
+		return string(v)
+	case *[]string:
+		return *v
+	default:
+		return value
+	}
+}
@@ -0,0 +1,428 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sink
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/apache/incubator-devlake/errors"
+	"github.com/apache/incubator-devlake/plugins/core"
+	"github.com/apache/incubator-devlake/plugins/helper"
+)
+
+func init() {
+	RegisterDriver("parquet", func(options map[string]interface{}, logger core.Logger) (Driver, errors.Error) {
+		config := &ParquetConfig{}
+		err := helper.Decode(options, config, nil)
+		if err != nil {
+			return nil, err
+		}
+		return NewParquetDriver(config, logger), nil
+	})
+}
+
+// ParquetConfig points to the local directory receiving a <table>.parquet file per table
+type ParquetConfig struct {
+	Directory string
+	// RowGroupSize is the number of rows buffered before they are written as a row group
+	RowGroupSize int `mapstructure:"row_group_size"`
+}
+
+const defaultRowGroupSize = 100000
+
+// parquetWatermarkPrefix prefixes the key value metadata holding the latest value of the timestamp columns
+const parquetWatermarkPrefix = "devlake.max."
+
+type parquetFile struct {
+	file      *os.File
+	offset    int64
+	rowGroups []*parquetRowGroup
+	pending   [][]interface{}
+	maxima    map[string]time.Time
+}
+
+// ParquetDriver writes uncompressed parquet files, the staging file is renamed over the target file once complete
+type ParquetDriver struct {
+	config *ParquetConfig
+	files  map[string]*parquetFile
+	logger core.Logger
+}
+
+func NewParquetDriver(config *ParquetConfig, logger core.Logger) *ParquetDriver {
+	if config.Directory == "" {
+		config.Directory = "parquet"
+	}
+	if config.RowGroupSize <= 0 {
+		config.RowGroupSize = defaultRowGroupSize
+	}
+	return &ParquetDriver{
+		config: config,
+		files:  make(map[string]*parquetFile),
+		logger: logger,
+	}
+}
+
+// MapType analysis and return the parquet type, arrays are encoded as json since the files have no nested columns
+func (d *ParquetDriver) MapType(dataType string) string {
+	dataType = strings.ToLower(dataType)
+	switch {
+	case strings.HasSuffix(dataType, "[]"), stringIn(dataType, "json", "jsonb"):
+		return "JSON"
+	case hasPrefixes(dataType, "datetime", "timestamp"):
+		return "TIMESTAMP_MILLIS"
+	case dataType == "date":
+		return "DATE"
+	case hasPrefixes(dataType, "bigint", "bigserial"):
+		return "INT64"
+	case stringIn(dataType, "tinyint(1)", "boolean", "bool"):
+		return "BOOLEAN"
+	case hasPrefixes(dataType, "smallint", "smallserial", "tinyint", "int", "integer", "serial", "mediumint"):
+		if strings.Contains(dataType, "unsigned") {
+			return "INT64"
+		}
+		return "INT32"
+	case hasPrefixes(dataType, "real", "float"):
+		return "FLOAT"
+	case hasPrefixes(dataType, "double", "numeric", "decimal"):
+		return "DOUBLE"
+	}
+	return "STRING"
+}
+
+func (d *ParquetDriver) path(name string) string {
+	return filepath.Join(d.config.Directory, fmt.Sprintf("%s.parquet", name))
+}
+
+// readFooter returns the number of rows and the key value metadata of the file, nil when it does not exist
+func (d *ParquetDriver) readFooter(name string) (*int64, map[string]string, errors.Error) {
+	file, err := os.Open(d.path(name))
+	if os.IsNotExist(err) {
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, errors.Convert(err)
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return nil, nil, errors.Convert(err)
+	}
+	tail := make([]byte, 8)
+	if info.Size() < int64(2*len(parquetMagic)+len(tail)) {
+		return nil, nil, errors.Default.New(fmt.Sprintf("%s is not a parquet file", d.path(name)))
+	}
+	_, err = file.ReadAt(tail, info.Size()-int64(len(tail)))
+	if err != nil {
+		return nil, nil, errors.Convert(err)
+	}
+	if string(tail[4:]) != parquetMagic {
+		return nil, nil, errors.Default.New(fmt.Sprintf("%s is not a parquet file", d.path(name)))
+	}
+	footer := make([]byte, binary.LittleEndian.Uint32(tail))
+	_, err = file.ReadAt(footer, info.Size()-int64(len(tail)+len(footer)))
+	if err != nil && err != io.EOF {
+		return nil, nil, errors.Convert(err)
+	}
+	numRows, metadata, err := readFileMetaData(footer)
+	if err != nil {
+		return nil, nil, errors.Default.Wrap(errors.Convert(err), fmt.Sprintf("failed to read the footer of %s", d.path(name)))
+	}
+	return &numRows, metadata, nil
+}
+
+func (d *ParquetDriver) Watermark(table *Table, column string) (*time.Time, errors.Error) {
+	numRows, metadata, err := d.readFooter(table.Name)
+	if err != nil || numRows == nil {
+		return nil, err
+	}
+	updatedTo := time.Time{}
+	if v, ok := metadata[parquetWatermarkPrefix+column]; ok {
+		updatedTo, err = errors.Convert01(time.Parse(time.RFC3339Nano, v))
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &updatedTo, nil
+}
+
+func (d *ParquetDriver) CreateTable(table *Table, staging string) errors.Error {
+	if f, ok := d.files[staging]; ok {
+		_ = f.file.Close()
+		delete(d.files, staging)
+	}
+	err := os.MkdirAll(d.config.Directory, 0755)
+	if err != nil {
+		return errors.Convert(err)
+	}
+	file, err := os.Create(d.path(staging))
+	if err != nil {
+		return errors.Convert(err)
+	}
+	_, err = file.WriteString(parquetMagic)
+	if err != nil {
+		_ = file.Close()
+		return errors.Convert(err)
+	}
+	d.files[staging] = &parquetFile{
+		file:   file,
+		offset: int64(len(parquetMagic)),
+		maxima: make(map[string]time.Time),
+	}
+	return nil
+}
+
+func (d *ParquetDriver) Write(table *Table, staging string, rows []map[string]interface{}) errors.Error {
+	f, ok := d.files[staging]
+	if !ok {
+		return errors.Default.New(fmt.Sprintf("staging file %s is not created", staging))
+	}
+	for _, row := range rows {
+		values := make([]interface{}, len(table.Columns))
+		for i, c := range table.Columns {
+			value := row[c.Name]
+			if value == nil {
+				continue
+			}
+			if c.Type == "TIMESTAMP_MILLIS" {
+				t, err := toTime(value)
+				if err != nil {
+					return errors.BadInput.Wrap(err, fmt.Sprintf("invalid value of column %s", c.Name))
+				}
+				if latest, ok := f.maxima[c.Name]; !ok || t.After(latest) {
+					f.maxima[c.Name] = t
+				}
+				values[i] = t.UnixMilli()
+				continue
+			}
+			v, err := parquetValue(c.Type, value)
+			if err != nil {
+				return errors.BadInput.Wrap(err, fmt.Sprintf("invalid value of column %s", c.Name))
+			}
+			values[i] = v
+		}
+		f.pending = append(f.pending, values)
+		if len(f.pending) >= d.config.RowGroupSize {
+			err := d.flush(table, f)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// flush writes the pending rows as a row group made of one data page per column
+func (d *ParquetDriver) flush(table *Table, f *parquetFile) errors.Error {
+	if len(f.pending) == 0 {
+		return nil
+	}
+	rowGroup := &parquetRowGroup{numRows: int64(len(f.pending))}
+	for i, c := range table.Columns {
+		physicalType, _ := parquetTypes(c.Type)
+		defined := make([]bool, len(f.pending))
+		var values []interface{}
+		for j, row := range f.pending {
+			if row[i] != nil {
+				defined[j] = true
+				values = append(values, row[i])
+			}
+		}
+		var page bytes.Buffer
+		encodeDefinitionLevels(&page, defined)
+		encodePlain(&page, physicalType, values)
+		header := &thriftWriter{}
+		writePageHeader(header, page.Len(), len(defined))
+		chunk := &parquetColumnChunk{
+			path:             c.Name,
+			physicalType:     physicalType,
+			offset:           f.offset,
+			numValues:        int64(len(defined)),
+			uncompressedSize: int64(header.buf.Len() + page.Len()),
+		}
+		_, err := f.file.Write(header.buf.Bytes())
+		if err != nil {
+			return errors.Convert(err)
+		}
+		_, err = f.file.Write(page.Bytes())
+		if err != nil {
+			return errors.Convert(err)
+		}
+		f.offset += chunk.uncompressedSize
+		rowGroup.columns = append(rowGroup.columns, chunk)
+	}
+	f.rowGroups = append(f.rowGroups, rowGroup)
+	f.pending = nil
+	return nil
+}
+
+// Swap completes the staging file with its footer and renames it over the target file
+func (d *ParquetDriver) Swap(table *Table, staging string) errors.Error {
+	f, ok := d.files[staging]
+	if !ok {
+		return errors.Default.New(fmt.Sprintf("staging file %s is not created", staging))
+	}
+	delete(d.files, staging)
+	err := d.flush(table, f)
+	if err != nil {
+		_ = f.file.Close()
+		return err
+	}
+	var metadata []parquetKeyValue
+	for column, latest := range f.maxima {
+		metadata = append(metadata, parquetKeyValue{key: parquetWatermarkPrefix + column, value: latest.Format(time.RFC3339Nano)})
+	}
+	sort.Slice(metadata, func(i, j int) bool {
+		return metadata[i].key < metadata[j].key
+	})
+	footer := &thriftWriter{}
+	writeFileMetaData(footer, table.Columns, f.rowGroups, metadata)
+	_ = binary.Write(&footer.buf, binary.LittleEndian, uint32(footer.buf.Len()))
+	footer.buf.WriteString(parquetMagic)
+	_, e := f.file.Write(footer.buf.Bytes())
+	if e != nil {
+		_ = f.file.Close()
+		return errors.Convert(e)
+	}
+	e = f.file.Close()
+	if e != nil {
+		return errors.Convert(e)
+	}
+	return errors.Convert(os.Rename(d.path(staging), d.path(table.Name)))
+}
+
+func (d *ParquetDriver) Count(table *Table) (int64, errors.Error) {
+	numRows, _, err := d.readFooter(table.Name)
+	if err != nil || numRows == nil {
+		return 0, err
+	}
+	return *numRows, nil
+}
+
+// Close removes the staging files which were not swapped
+func (d *ParquetDriver) Close() errors.Error {
+	for staging, f := range d.files {
+		_ = f.file.Close()
+		_ = os.Remove(d.path(staging))
+		delete(d.files, staging)
+	}
+	return nil
+}
+
+// parquetValue converts a value read from the source into the one encodePlain expects for the column type
+func parquetValue(columnType string, value interface{}) (interface{}, error) {
+	switch columnType {
+	case "BOOLEAN":
+		if v, ok := value.(bool); ok {
+			return v, nil
+		}
+		if s, ok := value.(string); ok {
+			return strconv.ParseBool(s)
+		}
+		i, err := toInt64(value)
+		return i != 0, err
+	case "INT32":
+		i, err := toInt64(value)
+		return int32(i), err
+	case "INT64":
+		return toInt64(value)
+	case "FLOAT":
+		f, err := toFloat64(value)
+		return float32(f), err
+	case "DOUBLE":
+		return toFloat64(value)
+	case "DATE":
+		t, err := toTime(value)
+		if err != nil {
+			return nil, err
+		}
+		return int32(time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC).Unix() / 86400), nil
+	case "JSON":
+		if s, ok := value.(string); ok {
+			return s, nil
+		}
+		b, err := json.Marshal(value)
+		return string(b), err
+	}
+	switch v := value.(type) {
+	case string:
+		return v, nil
+	case time.Time:
+		return v.Format(time.RFC3339Nano), nil
+	}
+	return fmt.Sprint(value), nil
+}
+
+func toInt64(value interface{}) (int64, error) {
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int(), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return int64(v.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		return int64(v.Float()), nil
+	case reflect.Bool:
+		if v.Bool() {
+			return 1, nil
+		}
+		return 0, nil
+	case reflect.String:
+		return strconv.ParseInt(strings.TrimSpace(v.String()), 10, 64)
+	}
+	return 0, fmt.Errorf("%T is not an integer", value)
+}
+
+func toFloat64(value interface{}) (float64, error) {
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), nil
+	case reflect.String:
+		return strconv.ParseFloat(strings.TrimSpace(v.String()), 64)
+	}
+	i, err := toInt64(value)
+	return float64(i), err
+}
+
+// toTime takes the times scanned by the drivers or the strings mysql returns without parseTime=True
+func toTime(value interface{}) (time.Time, error) {
+	switch v := value.(type) {
+	case time.Time:
+		return v, nil
+	case string:
+		for _, layout := range []string{time.RFC3339Nano, "2006-01-02 15:04:05.999999999", "2006-01-02"} {
+			t, err := time.Parse(layout, v)
+			if err == nil {
+				return t, nil
+			}
+		}
+		return time.Time{}, fmt.Errorf("%s is not a time", v)
+	}
+	return time.Time{}, fmt.Errorf("%T is not a time", value)
+}
@@ -0,0 +1,512 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sink
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+)
+
+// the subset of the parquet format written by ParquetDriver, the metadata is encoded with the thrift
+// compact protocol, see https://github.com/apache/parquet-format/blob/master/src/main/thrift/parquet.thrift
+
+const parquetMagic = "PAR1"
+
+// physical types
+const (
+	parquetBoolean   int32 = 0
+	parquetInt32     int32 = 1
+	parquetInt64     int32 = 2
+	parquetFloat     int32 = 4
+	parquetDouble    int32 = 5
+	parquetByteArray int32 = 6
+)
+
+// converted types
+const (
+	parquetUtf8            int32 = 0
+	parquetDate            int32 = 6
+	parquetTimestampMillis int32 = 9
+	parquetJson            int32 = 19
+)
+
+const (
+	parquetOptional      int32 = 1
+	parquetDataPage      int32 = 0
+	parquetPlain         int32 = 0
+	parquetRle           int32 = 3
+	parquetUncompressed  int32 = 0
+	parquetFormatVersion int32 = 1
+)
+
+// thrift compact protocol types
+const (
+	thriftStop   byte = 0
+	thriftTrue   byte = 1
+	thriftFalse  byte = 2
+	thriftByte   byte = 3
+	thriftI16    byte = 4
+	thriftI32    byte = 5
+	thriftI64    byte = 6
+	thriftDouble byte = 7
+	thriftBinary byte = 8
+	thriftList   byte = 9
+	thriftSet    byte = 10
+	thriftMap    byte = 11
+	thriftStruct byte = 12
+)
+
+type thriftWriter struct {
+	buf     bytes.Buffer
+	lastIds []int16
+	lastId  int16
+	scratch [binary.MaxVarintLen64]byte
+}
+
+func (w *thriftWriter) uvarint(v uint64) {
+	n := binary.PutUvarint(w.scratch[:], v)
+	w.buf.Write(w.scratch[:n])
+}
+
+func (w *thriftWriter) field(id int16, fieldType byte) {
+	delta := id - w.lastId
+	if delta > 0 && delta <= 15 {
+		w.buf.WriteByte(byte(delta)<<4 | fieldType)
+	} else {
+		w.buf.WriteByte(fieldType)
+		w.uvarint(uint64((int64(id) << 1) ^ (int64(id) >> 63)))
+	}
+	w.lastId = id
+}
+
+func (w *thriftWriter) i32(v int32) {
+	w.uvarint(uint64(uint32((v << 1) ^ (v >> 31))))
+}
+
+func (w *thriftWriter) i64(v int64) {
+	w.uvarint(uint64((v << 1) ^ (v >> 63)))
+}
+
+func (w *thriftWriter) binary(v string) {
+	w.uvarint(uint64(len(v)))
+	w.buf.WriteString(v)
+}
+
+func (w *thriftWriter) list(elemType byte, size int) {
+	if size < 15 {
+		w.buf.WriteByte(byte(size)<<4 | elemType)
+	} else {
+		w.buf.WriteByte(0xf0 | elemType)
+		w.uvarint(uint64(size))
+	}
+}
+
+func (w *thriftWriter) fieldI32(id int16, v int32) {
+	w.field(id, thriftI32)
+	w.i32(v)
+}
+
+func (w *thriftWriter) fieldI64(id int16, v int64) {
+	w.field(id, thriftI64)
+	w.i64(v)
+}
+
+func (w *thriftWriter) fieldBinary(id int16, v string) {
+	w.field(id, thriftBinary)
+	w.binary(v)
+}
+
+// beginStruct starts a nested struct, the ids of its fields are relative to the struct
+func (w *thriftWriter) beginStruct() {
+	w.lastIds = append(w.lastIds, w.lastId)
+	w.lastId = 0
+}
+
+func (w *thriftWriter) endStruct() {
+	w.buf.WriteByte(thriftStop)
+	w.lastId = w.lastIds[len(w.lastIds)-1]
+	w.lastIds = w.lastIds[:len(w.lastIds)-1]
+}
+
+func (w *thriftWriter) fieldStruct(id int16) {
+	w.field(id, thriftStruct)
+	w.beginStruct()
+}
+
+type parquetColumnChunk struct {
+	path             string
+	physicalType     int32
+	offset           int64
+	numValues        int64
+	uncompressedSize int64
+}
+
+type parquetRowGroup struct {
+	columns []*parquetColumnChunk
+	numRows int64
+}
+
+func writePageHeader(w *thriftWriter, size int, numValues int) {
+	w.beginStruct()
+	w.fieldI32(1, parquetDataPage)
+	w.fieldI32(2, int32(size))
+	w.fieldI32(3, int32(size))
+	w.fieldStruct(5)
+	w.fieldI32(1, int32(numValues))
+	w.fieldI32(2, parquetPlain)
+	w.fieldI32(3, parquetRle)
+	w.fieldI32(4, parquetRle)
+	w.endStruct()
+	w.endStruct()
+}
+
+type parquetKeyValue struct {
+	key   string
+	value string
+}
+
+func writeFileMetaData(w *thriftWriter, columns []*Column, rowGroups []*parquetRowGroup, metadata []parquetKeyValue) {
+	var numRows int64
+	for _, rowGroup := range rowGroups {
+		numRows += rowGroup.numRows
+	}
+	w.beginStruct()
+	w.fieldI32(1, parquetFormatVersion)
+	w.field(2, thriftList)
+	w.list(thriftStruct, len(columns)+1)
+	w.beginStruct()
+	w.fieldBinary(4, "schema")
+	w.fieldI32(5, int32(len(columns)))
+	w.endStruct()
+	for _, c := range columns {
+		physicalType, convertedType := parquetTypes(c.Type)
+		w.beginStruct()
+		w.fieldI32(1, physicalType)
+		w.fieldI32(3, parquetOptional)
+		w.fieldBinary(4, c.Name)
+		if convertedType >= 0 {
+			w.fieldI32(6, convertedType)
+		}
+		w.endStruct()
+	}
+	w.fieldI64(3, numRows)
+	w.field(4, thriftList)
+	w.list(thriftStruct, len(rowGroups))
+	for _, rowGroup := range rowGroups {
+		var totalSize int64
+		w.beginStruct()
+		w.field(1, thriftList)
+		w.list(thriftStruct, len(rowGroup.columns))
+		for _, chunk := range rowGroup.columns {
+			totalSize += chunk.uncompressedSize
+			w.beginStruct()
+			w.fieldI64(2, chunk.offset)
+			w.fieldStruct(3)
+			w.fieldI32(1, chunk.physicalType)
+			w.field(2, thriftList)
+			w.list(thriftI32, 2)
+			w.i32(parquetPlain)
+			w.i32(parquetRle)
+			w.field(3, thriftList)
+			w.list(thriftBinary, 1)
+			w.binary(chunk.path)
+			w.fieldI32(4, parquetUncompressed)
+			w.fieldI64(5, chunk.numValues)
+			w.fieldI64(6, chunk.uncompressedSize)
+			w.fieldI64(7, chunk.uncompressedSize)
+			w.fieldI64(9, chunk.offset)
+			w.endStruct()
+			w.endStruct()
+		}
+		w.fieldI64(2, totalSize)
+		w.fieldI64(3, rowGroup.numRows)
+		w.endStruct()
+	}
+	if len(metadata) > 0 {
+		w.field(5, thriftList)
+		w.list(thriftStruct, len(metadata))
+		for _, kv := range metadata {
+			w.beginStruct()
+			w.fieldBinary(1, kv.key)
+			w.fieldBinary(2, kv.value)
+			w.endStruct()
+		}
+	}
+	w.fieldBinary(6, "devlake sink")
+	w.endStruct()
+}
+
+// parquetTypes returns the physical and the converted type of a column mapped by ParquetDriver.MapType,
+// the converted type is -1 when there is none
+func parquetTypes(columnType string) (int32, int32) {
+	switch columnType {
+	case "BOOLEAN":
+		return parquetBoolean, -1
+	case "INT32":
+		return parquetInt32, -1
+	case "INT64":
+		return parquetInt64, -1
+	case "FLOAT":
+		return parquetFloat, -1
+	case "DOUBLE":
+		return parquetDouble, -1
+	case "DATE":
+		return parquetInt32, parquetDate
+	case "TIMESTAMP_MILLIS":
+		return parquetInt64, parquetTimestampMillis
+	case "JSON":
+		return parquetByteArray, parquetJson
+	}
+	return parquetByteArray, parquetUtf8
+}
+
+// encodeDefinitionLevels writes the levels of an optional column as bit-packed runs of the RLE hybrid encoding,
+// prefixed with their length
+func encodeDefinitionLevels(buf *bytes.Buffer, defined []bool) {
+	groups := (len(defined) + 7) / 8
+	var header [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(header[:], uint64(groups<<1|1))
+	packed := make([]byte, groups)
+	for i, d := range defined {
+		if d {
+			packed[i/8] |= 1 << (i % 8)
+		}
+	}
+	_ = binary.Write(buf, binary.LittleEndian, uint32(n+groups))
+	buf.Write(header[:n])
+	buf.Write(packed)
+}
+
+// encodePlain writes the non-null values of a column, they are converted by parquetValue beforehand
+func encodePlain(buf *bytes.Buffer, physicalType int32, values []interface{}) {
+	switch physicalType {
+	case parquetBoolean:
+		packed := make([]byte, (len(values)+7)/8)
+		for i, v := range values {
+			if v.(bool) {
+				packed[i/8] |= 1 << (i % 8)
+			}
+		}
+		buf.Write(packed)
+	case parquetByteArray:
+		for _, v := range values {
+			s := v.(string)
+			_ = binary.Write(buf, binary.LittleEndian, uint32(len(s)))
+			buf.WriteString(s)
+		}
+	case parquetFloat:
+		for _, v := range values {
+			_ = binary.Write(buf, binary.LittleEndian, math.Float32bits(v.(float32)))
+		}
+	case parquetDouble:
+		for _, v := range values {
+			_ = binary.Write(buf, binary.LittleEndian, math.Float64bits(v.(float64)))
+		}
+	default:
+		for _, v := range values {
+			_ = binary.Write(buf, binary.LittleEndian, v)
+		}
+	}
+}
+
+type thriftReader struct {
+	r       io.ByteReader
+	lastIds []int16
+	lastId  int16
+}
+
+func (r *thriftReader) uvarint() (uint64, error) {
+	return binary.ReadUvarint(r.r)
+}
+
+func (r *thriftReader) i64() (int64, error) {
+	v, err := r.uvarint()
+	return int64(v>>1) ^ -int64(v&1), err
+}
+
+func (r *thriftReader) binary() (string, error) {
+	size, err := r.uvarint()
+	if err != nil {
+		return "", err
+	}
+	b := make([]byte, size)
+	for i := range b {
+		b[i], err = r.r.ReadByte()
+		if err != nil {
+			return "", err
+		}
+	}
+	return string(b), nil
+}
+
+// field returns the id and the type of the next field, the type is thriftStop at the end of the struct
+func (r *thriftReader) field() (int16, byte, error) {
+	b, err := r.r.ReadByte()
+	if err != nil || b == thriftStop {
+		return 0, thriftStop, err
+	}
+	fieldType := b & 0x0f
+	if delta := int16(b >> 4); delta != 0 {
+		r.lastId += delta
+	} else {
+		id, err := r.i64()
+		if err != nil {
+			return 0, 0, err
+		}
+		r.lastId = int16(id)
+	}
+	return r.lastId, fieldType, nil
+}
+
+func (r *thriftReader) list() (byte, int, error) {
+	b, err := r.r.ReadByte()
+	if err != nil {
+		return 0, 0, err
+	}
+	size := int(b >> 4)
+	if size == 15 {
+		s, err := r.uvarint()
+		if err != nil {
+			return 0, 0, err
+		}
+		size = int(s)
+	}
+	return b & 0x0f, size, nil
+}
+
+func (r *thriftReader) beginStruct() {
+	r.lastIds = append(r.lastIds, r.lastId)
+	r.lastId = 0
+}
+
+func (r *thriftReader) endStruct() {
+	r.lastId = r.lastIds[len(r.lastIds)-1]
+	r.lastIds = r.lastIds[:len(r.lastIds)-1]
+}
+
+// skip reads over a value of the type, it is the way to ignore the fields ParquetDriver does not need
+func (r *thriftReader) skip(fieldType byte) error {
+	var err error
+	switch fieldType {
+	case thriftTrue, thriftFalse:
+	case thriftByte:
+		_, err = r.r.ReadByte()
+	case thriftI16, thriftI32, thriftI64:
+		_, err = r.uvarint()
+	case thriftDouble:
+		for i := 0; i < 8 && err == nil; i++ {
+			_, err = r.r.ReadByte()
+		}
+	case thriftBinary:
+		_, err = r.binary()
+	case thriftList, thriftSet:
+		var elemType byte
+		var size int
+		elemType, size, err = r.list()
+		for i := 0; i < size && err == nil; i++ {
+			if elemType == thriftTrue || elemType == thriftFalse {
+				_, err = r.r.ReadByte()
+			} else {
+				err = r.skip(elemType)
+			}
+		}
+	case thriftMap:
+		var size uint64
+		size, err = r.uvarint()
+		if err != nil || size == 0 {
+			return err
+		}
+		var types byte
+		types, err = r.r.ReadByte()
+		for i := uint64(0); i < size && err == nil; i++ {
+			err = r.skip(types >> 4)
+			if err == nil {
+				err = r.skip(types & 0x0f)
+			}
+		}
+	case thriftStruct:
+		r.beginStruct()
+		for {
+			var t byte
+			_, t, err = r.field()
+			if err != nil || t == thriftStop {
+				break
+			}
+			err = r.skip(t)
+			if err != nil {
+				break
+			}
+		}
+		r.endStruct()
+	default:
+		err = fmt.Errorf("unknown thrift type %d", fieldType)
+	}
+	return err
+}
+
+// readFileMetaData reads the number of rows and the key value metadata of a parquet file footer
+func readFileMetaData(footer []byte) (int64, map[string]string, error) {
+	r := &thriftReader{r: bytes.NewReader(footer)}
+	var numRows int64
+	metadata := make(map[string]string)
+	for {
+		id, fieldType, err := r.field()
+		if err != nil {
+			return 0, nil, err
+		}
+		switch {
+		case fieldType == thriftStop:
+			return numRows, metadata, nil
+		case id == 3 && fieldType == thriftI64:
+			numRows, err = r.i64()
+		case id == 5 && fieldType == thriftList:
+			var size int
+			_, size, err = r.list()
+			for i := 0; i < size && err == nil; i++ {
+				var key, value string
+				r.beginStruct()
+				for err == nil {
+					var kvId int16
+					var kvType byte
+					kvId, kvType, err = r.field()
+					if err != nil || kvType == thriftStop {
+						break
+					}
+					switch {
+					case kvId == 1 && kvType == thriftBinary:
+						key, err = r.binary()
+					case kvId == 2 && kvType == thriftBinary:
+						value, err = r.binary()
+					default:
+						err = r.skip(kvType)
+					}
+				}
+				r.endStruct()
+				metadata[key] = value
+			}
+		default:
+			err = r.skip(fieldType)
+		}
+		if err != nil {
+			return 0, nil, err
+		}
+	}
+}
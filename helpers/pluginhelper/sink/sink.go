@@ -0,0 +1,126 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sink
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/apache/incubator-devlake/errors"
+	"github.com/apache/incubator-devlake/plugins/core"
+)
+
+// Column is a column of a source table along with its type in the target
+type Column struct {
+	Name       string
+	SourceType string
+	Type       string
+	PrimaryKey bool
+}
+
+// IsArray tells whether the source column is an array, only postgres has them
+func (c *Column) IsArray() bool {
+	return len(c.SourceType) > 2 && c.SourceType[len(c.SourceType)-2:] == "[]"
+}
+
+// Table is the schema of a source table mapped to the target
+type Table struct {
+	// Source is the name of the table in the source database
+	Source string
+	// Name is the name of the table in the target, the leading underscores of the source name are trimmed
+	Name    string
+	Columns []*Column
+}
+
+// PrimaryKeys returns the primary key columns, the first column is used when the source table has none
+func (t *Table) PrimaryKeys() []string {
+	var pks []string
+	for _, c := range t.Columns {
+		if c.PrimaryKey {
+			pks = append(pks, c.Name)
+		}
+	}
+	if len(pks) == 0 && len(t.Columns) > 0 {
+		pks = append(pks, t.Columns[0].Name)
+	}
+	return pks
+}
+
+// Driver writes tables into an analytics target. The loader maps the schema, checks the watermarks and fills
+// a staging table which replaces the target table once it is complete, so drivers only deal with their target.
+type Driver interface {
+	// MapType translates the column type of the source database, i.e. varchar(255) or timestamp, into the target one
+	MapType(sourceType string) string
+	// Watermark returns the latest value of the column in the target table, nil when the table does not exist yet
+	Watermark(table *Table, column string) (*time.Time, errors.Error)
+	// CreateTable drops the leftovers of the staging table and creates it with the mapped columns
+	CreateTable(table *Table, staging string) errors.Error
+	// Write appends a batch of rows to the staging table
+	Write(table *Table, staging string, rows []map[string]interface{}) errors.Error
+	// Swap replaces the target table with the staging one
+	Swap(table *Table, staging string) errors.Error
+	// Count returns the number of rows in the target table
+	Count(table *Table) (int64, errors.Error)
+	Close() errors.Error
+}
+
+// DriverFactory creates a driver from the task options, each driver decodes the options it needs
+type DriverFactory func(options map[string]interface{}, logger core.Logger) (Driver, errors.Error)
+
+var drivers = make(map[string]DriverFactory)
+var driversLock sync.RWMutex
+
+// RegisterDriver makes a target available to the sink plugin, every driver registers itself in its init function
+func RegisterDriver(name string, factory DriverFactory) {
+	driversLock.Lock()
+	defer driversLock.Unlock()
+	drivers[name] = factory
+}
+
+// HasDriver tells whether the target is registered
+func HasDriver(name string) bool {
+	driversLock.RLock()
+	defer driversLock.RUnlock()
+	_, ok := drivers[name]
+	return ok
+}
+
+// NewDriver creates the driver of the target
+func NewDriver(name string, options map[string]interface{}, logger core.Logger) (Driver, errors.Error) {
+	driversLock.RLock()
+	factory, ok := drivers[name]
+	driversLock.RUnlock()
+	if !ok {
+		return nil, errors.BadInput.New(fmt.Sprintf("unsupported target %s, available ones are %v", name, Drivers()))
+	}
+	return factory(options, logger)
+}
+
+// Drivers returns the names of the registered targets
+func Drivers() []string {
+	driversLock.RLock()
+	defer driversLock.RUnlock()
+	names := make([]string, 0, len(drivers))
+	for name := range drivers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,265 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sink
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/apache/incubator-devlake/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchTables(t *testing.T) {
+	tables, err := MatchTables([]string{"issues", "_tool_jira_issues", "commits"}, []string{"^issues$", "^_tool_"})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"issues", "_tool_jira_issues"}, tables)
+
+	tables, err = MatchTables([]string{"issues", "commits"}, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"issues", "commits"}, tables)
+
+	_, err = MatchTables([]string{"issues"}, []string{"("})
+	assert.NotNil(t, err)
+}
+
+func TestTablePrimaryKeys(t *testing.T) {
+	table := &Table{Columns: []*Column{{Name: "id"}, {Name: "name"}}}
+	assert.Equal(t, []string{"id"}, table.PrimaryKeys())
+	table.Columns[1].PrimaryKey = true
+	assert.Equal(t, []string{"name"}, table.PrimaryKeys())
+	assert.True(t, (&Column{SourceType: "varchar[]"}).IsArray())
+	assert.False(t, (&Column{SourceType: "varchar(255)"}).IsArray())
+}
+
+func TestMapType(t *testing.T) {
+	assert.Equal(t, "datetime", GetStarRocksDataType("datetime(3)"))
+	assert.Equal(t, "array<string>", GetStarRocksDataType("varchar[]"))
+
+	clickhouse := &ClickHouseDriver{}
+	assert.Equal(t, "DateTime64(3)", clickhouse.MapType("timestamp with time zone"))
+	assert.Equal(t, "Int64", clickhouse.MapType("bigint unsigned"))
+	assert.Equal(t, "Bool", clickhouse.MapType("tinyint(1)"))
+	assert.Equal(t, "Int32", clickhouse.MapType("int"))
+	assert.Equal(t, "Array(String)", clickhouse.MapType("text[]"))
+	assert.Equal(t, "String", clickhouse.MapType("varchar(255)"))
+
+	mysql := &SqlDriver{dialect: "mysql"}
+	postgres := &SqlDriver{dialect: "postgres"}
+	assert.Equal(t, "varchar(255)", mysql.MapType("character varying(255)"))
+	assert.Equal(t, "varchar(255)", postgres.MapType("varchar(255)"))
+	assert.Equal(t, "datetime(3)", mysql.MapType("timestamp with time zone"))
+	assert.Equal(t, "timestamptz", postgres.MapType("datetime(3)"))
+	assert.Equal(t, "boolean", postgres.MapType("tinyint(1)"))
+	assert.Equal(t, "bigint", postgres.MapType("bigint unsigned"))
+	assert.Equal(t, "integer", postgres.MapType("int"))
+	assert.Equal(t, "longtext", mysql.MapType("text"))
+	assert.Equal(t, "text", postgres.MapType("longtext"))
+	assert.Equal(t, "json", mysql.MapType("text[]"))
+	assert.Equal(t, "text[]", postgres.MapType("text[]"))
+
+	parquet := &ParquetDriver{}
+	assert.Equal(t, "TIMESTAMP_MILLIS", parquet.MapType("timestamp with time zone"))
+	assert.Equal(t, "INT64", parquet.MapType("bigint unsigned"))
+	assert.Equal(t, "INT64", parquet.MapType("int unsigned"))
+	assert.Equal(t, "INT32", parquet.MapType("int"))
+	assert.Equal(t, "BOOLEAN", parquet.MapType("tinyint(1)"))
+	assert.Equal(t, "JSON", parquet.MapType("text[]"))
+	assert.Equal(t, "STRING", parquet.MapType("varchar(255)"))
+}
+
+func TestDrivers(t *testing.T) {
+	assert.Equal(t, []string{"clickhouse", "mysql", "parquet", "postgres", "starrocks"}, Drivers())
+	assert.True(t, HasDriver("clickhouse"))
+	_, err := NewDriver("duckdb", nil, nil)
+	assert.NotNil(t, err)
+}
+
+func TestClickHouseDriver(t *testing.T) {
+	var queries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		query := r.URL.Query().Get("query")
+		if query == "" {
+			query = string(body)
+		} else {
+			query = query + "\n" + string(body)
+		}
+		queries = append(queries, query)
+		switch {
+		case strings.HasPrefix(query, "EXISTS TABLE"):
+			_, _ = w.Write([]byte("1\n"))
+		case strings.HasPrefix(query, "SELECT toUnixTimestamp64Milli"):
+			_, _ = w.Write([]byte("1672531200000\n"))
+		case strings.HasPrefix(query, "SELECT count()"):
+			_, _ = w.Write([]byte("2\n"))
+		case strings.HasPrefix(query, "INSERT"):
+			if !strings.Contains(query, `"id":"1"`) {
+				w.WriteHeader(http.StatusBadRequest)
+			}
+		}
+	}))
+	defer server.Close()
+
+	driver := NewClickHouseDriver(&ClickHouseConfig{Endpoint: server.URL, Database: "lake"}, nil)
+	table := &Table{Source: "_tool_issues", Name: "tool_issues", Columns: []*Column{{Name: "id", Type: "String", PrimaryKey: true}}}
+
+	watermark, err := driver.Watermark(table, "updated_at")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1672531200), watermark.Unix())
+
+	err = driver.Write(table, "tool_issues_tmp", []map[string]interface{}{{"id": "1"}, {"id": "2"}})
+	assert.Nil(t, err)
+	assert.Equal(t, "INSERT INTO `lake`.`tool_issues_tmp` FORMAT JSONEachRow\n{\"id\":\"1\"}\n{\"id\":\"2\"}\n", queries[len(queries)-1])
+	err = driver.Write(table, "tool_issues_tmp", []map[string]interface{}{{"id": "3"}})
+	assert.NotNil(t, err)
+
+	queries = nil
+	err = driver.Swap(table, "tool_issues_tmp")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{
+		"CREATE TABLE IF NOT EXISTS `lake`.`tool_issues` AS `lake`.`tool_issues_tmp`",
+		"EXCHANGE TABLES `lake`.`tool_issues_tmp` AND `lake`.`tool_issues`",
+		"DROP TABLE IF EXISTS `lake`.`tool_issues_tmp`",
+	}, queries)
+
+	count, err := driver.Count(table)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), count)
+}
+
+func TestParquetDriver(t *testing.T) {
+	dir := t.TempDir()
+	driver := NewParquetDriver(&ParquetConfig{Directory: dir, RowGroupSize: 2}, nil)
+	defer driver.Close()
+	table := &Table{Source: "_tool_issues", Name: "tool_issues", Columns: []*Column{
+		{Name: "id", Type: "STRING", PrimaryKey: true},
+		{Name: "updated_at", Type: "TIMESTAMP_MILLIS"},
+		{Name: "points", Type: "INT32"},
+		{Name: "done", Type: "BOOLEAN"},
+		{Name: "labels", Type: "JSON"},
+	}}
+
+	watermark, err := driver.Watermark(table, "updated_at")
+	assert.Nil(t, err)
+	assert.Nil(t, watermark)
+
+	updatedAt := time.Date(2023, 1, 2, 3, 4, 5, 678000000, time.UTC)
+	err = driver.CreateTable(table, "tool_issues_tmp")
+	assert.Nil(t, err)
+	err = driver.Write(table, "tool_issues_tmp", []map[string]interface{}{
+		{"id": "1", "updated_at": updatedAt, "points": int64(3), "done": int64(1), "labels": []string{"bug"}},
+		{"id": "2", "updated_at": "2023-01-01 00:00:00", "points": nil, "done": false},
+		{"id": "3", "updated_at": nil, "points": "5", "done": true},
+	})
+	assert.Nil(t, err)
+	err = driver.Write(table, "tool_issues_tmp", []map[string]interface{}{{"id": "4", "points": "x"}})
+	assert.NotNil(t, err)
+	err = driver.Write(table, "tool_issues_tmp", []map[string]interface{}{{"id": "4"}})
+	assert.Nil(t, err)
+	err = driver.Swap(table, "tool_issues_tmp")
+	assert.Nil(t, err)
+
+	_, statErr := os.Stat(driver.path("tool_issues_tmp"))
+	assert.True(t, os.IsNotExist(statErr))
+	count, err := driver.Count(table)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(4), count)
+	watermark, err = driver.Watermark(table, "updated_at")
+	assert.Nil(t, err)
+	assert.True(t, updatedAt.Equal(*watermark))
+	watermark, err = driver.Watermark(table, "created_at")
+	assert.Nil(t, err)
+	assert.True(t, watermark.IsZero())
+
+	// the first row group is flushed once it reaches 2 rows, its first column chunk holds a data page with the
+	// definition levels and the plain values of the first 2 ids
+	content, e := os.ReadFile(driver.path("tool_issues"))
+	assert.Nil(t, e)
+	assert.Equal(t, parquetMagic, string(content[:4]))
+	assert.Equal(t, parquetMagic, string(content[len(content)-4:]))
+	reader := bytes.NewReader(content[4:])
+	assert.Nil(t, (&thriftReader{r: reader}).skip(thriftStruct))
+	page := content[len(content)-reader.Len():]
+	assert.Equal(t, uint32(2), binary.LittleEndian.Uint32(page))
+	assert.Equal(t, []byte{0x03, 0x03}, page[4:6])
+	assert.Equal(t, []byte{1, 0, 0, 0, '1', 1, 0, 0, 0, '2'}, page[6:16])
+	// the first row group is followed by the page header of the next chunk
+	assert.Equal(t, byte(0x15), page[16])
+}
+
+// writeParquetGolden writes the rows of testdata/tool_issues.parquet
+func writeParquetGolden(dir string) (string, errors.Error) {
+	driver := NewParquetDriver(&ParquetConfig{Directory: dir, RowGroupSize: 2}, nil)
+	defer driver.Close()
+	table := &Table{Source: "_tool_issues", Name: "tool_issues", Columns: []*Column{
+		{Name: "id", Type: "STRING", PrimaryKey: true},
+		{Name: "updated_at", Type: "TIMESTAMP_MILLIS"},
+		{Name: "due_date", Type: "DATE"},
+		{Name: "story_point", Type: "DOUBLE"},
+		{Name: "ratio", Type: "FLOAT"},
+		{Name: "points", Type: "INT32"},
+		{Name: "lead_time_minutes", Type: "INT64"},
+		{Name: "done", Type: "BOOLEAN"},
+		{Name: "labels", Type: "JSON"},
+	}}
+	err := driver.CreateTable(table, "tool_issues_tmp")
+	if err != nil {
+		return "", err
+	}
+	err = driver.Write(table, "tool_issues_tmp", []map[string]interface{}{
+		{
+			"id": "1", "updated_at": time.Date(2023, 1, 2, 3, 4, 5, 678000000, time.UTC), "due_date": "2023-01-31",
+			"story_point": 1.5, "ratio": 0.25, "points": 3, "lead_time_minutes": int64(1440), "done": true, "labels": []string{"bug"},
+		},
+		{"id": "2", "updated_at": "2023-01-01 00:00:00", "points": nil, "done": false},
+		{"id": "3", "story_point": 8, "ratio": -1, "points": "5", "lead_time_minutes": "-60", "labels": map[string]int{"a": 1}},
+	})
+	if err != nil {
+		return "", err
+	}
+	err = driver.Swap(table, "tool_issues_tmp")
+	if err != nil {
+		return "", err
+	}
+	return driver.path("tool_issues"), nil
+}
+
+// TestParquetGolden keeps the output identical to testdata/tool_issues.parquet, which has to be checked with the
+// readers the README points to whenever it changes, e.g.
+// `duckdb -c "DESCRIBE SELECT * FROM 'testdata/tool_issues.parquet'; SELECT * FROM 'testdata/tool_issues.parquet'"`
+// and `pyarrow.parquet.read_table('testdata/tool_issues.parquet')`
+func TestParquetGolden(t *testing.T) {
+	path, err := writeParquetGolden(t.TempDir())
+	assert.Nil(t, err)
+	content, e := os.ReadFile(path)
+	assert.Nil(t, e)
+	if os.Getenv("UPDATE_GOLDEN") != "" {
+		assert.Nil(t, os.WriteFile("testdata/tool_issues.parquet", content, 0644))
+	}
+	golden, e := os.ReadFile("testdata/tool_issues.parquet")
+	assert.Nil(t, e)
+	assert.Equal(t, golden, content)
+}
@@ -0,0 +1,294 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sink
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/apache/incubator-devlake/errors"
+	"github.com/apache/incubator-devlake/impl/dalgorm"
+	"github.com/apache/incubator-devlake/plugins/core"
+	"github.com/apache/incubator-devlake/plugins/core/dal"
+	"github.com/apache/incubator-devlake/plugins/helper"
+	"github.com/lib/pq"
+	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func init() {
+	for _, dialect := range []string{"mysql", "postgres"} {
+		dialect := dialect
+		RegisterDriver(dialect, func(options map[string]interface{}, logger core.Logger) (Driver, errors.Error) {
+			config := &SqlConfig{}
+			err := helper.Decode(options, config, nil)
+			if err != nil {
+				return nil, err
+			}
+			return NewSqlDriver(dialect, config.Dsn, logger)
+		})
+	}
+}
+
+// SqlConfig points to a MySQL or PostgreSQL database serving as a warehouse
+type SqlConfig struct {
+	Dsn string
+}
+
+// SqlDriver writes into another MySQL or PostgreSQL database
+type SqlDriver struct {
+	dialect string
+	db      dal.Dal
+	close   func() error
+	logger  core.Logger
+}
+
+func NewSqlDriver(dialect string, dsn string, logger core.Logger) (*SqlDriver, errors.Error) {
+	var dialector gorm.Dialector
+	switch dialect {
+	case "mysql":
+		dialector = mysql.Open(dsn)
+	case "postgres":
+		dialector = postgres.Open(dsn)
+	default:
+		return nil, errors.BadInput.New(fmt.Sprintf("unsupported dialect %s", dialect))
+	}
+	o, err := gorm.Open(dialector)
+	if err != nil {
+		return nil, errors.Convert(err)
+	}
+	sqlDB, err := o.DB()
+	if err != nil {
+		return nil, errors.Convert(err)
+	}
+	return &SqlDriver{
+		dialect: dialect,
+		db:      dalgorm.NewDalgorm(o),
+		close:   sqlDB.Close,
+		logger:  logger,
+	}, nil
+}
+
+// MapType keeps the types both databases understand as they are, the others are mapped to the closest ones
+func (d *SqlDriver) MapType(dataType string) string {
+	dataType = strings.ToLower(dataType)
+	mysql := d.dialect == "mysql"
+	switch {
+	case strings.HasSuffix(dataType, "[]"):
+		if mysql {
+			return "json"
+		}
+		return dataType
+	case hasPrefixes(dataType, "datetime", "timestamp"):
+		if mysql {
+			return "datetime(3)"
+		}
+		return "timestamptz"
+	case hasPrefixes(dataType, "varchar", "char", "character varying", "decimal", "numeric(", "date", "bigint", "smallint"):
+		if strings.HasPrefix(dataType, "character varying") {
+			return strings.Replace(dataType, "character varying", "varchar", 1)
+		}
+		if strings.HasPrefix(dataType, "bigint") {
+			return "bigint"
+		}
+		if strings.HasPrefix(dataType, "smallint") {
+			return "smallint"
+		}
+		return dataType
+	case stringIn(dataType, "bigserial"):
+		return "bigint"
+	case stringIn(dataType, "smallserial"):
+		return "smallint"
+	case stringIn(dataType, "tinyint(1)", "boolean", "bool"):
+		return "boolean"
+	case hasPrefixes(dataType, "int", "integer", "serial", "mediumint", "tinyint"):
+		if mysql {
+			return "int"
+		}
+		return "integer"
+	case hasPrefixes(dataType, "real", "float"):
+		if mysql {
+			return "float"
+		}
+		return "real"
+	case hasPrefixes(dataType, "double", "numeric"):
+		if mysql {
+			return "double"
+		}
+		return "double precision"
+	case stringIn(dataType, "json", "jsonb"):
+		if mysql {
+			return "json"
+		}
+		return dataType
+	case dataType == "uuid":
+		if mysql {
+			return "char(36)"
+		}
+		return "uuid"
+	}
+	if mysql {
+		return "longtext"
+	}
+	return "text"
+}
+
+func (d *SqlDriver) quote(name string) string {
+	if d.dialect == "mysql" {
+		return fmt.Sprintf("`%s`", name)
+	}
+	return fmt.Sprintf(`"%s"`, name)
+}
+
+func (d *SqlDriver) hasTable(name string) (bool, errors.Error) {
+	tables, err := d.db.AllTables()
+	if err != nil {
+		return false, err
+	}
+	for _, table := range tables {
+		if table == name {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (d *SqlDriver) Watermark(table *Table, column string) (*time.Time, errors.Error) {
+	exists, err := d.hasTable(table.Name)
+	if err != nil || !exists {
+		return nil, err
+	}
+	var updatedTo *time.Time
+	rows, err := d.db.Cursor(dal.Select(fmt.Sprintf("max(%s)", d.quote(column))), dal.From(table.Name))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	if rows.Next() {
+		err = errors.Convert(rows.Scan(&updatedTo))
+		if err != nil {
+			return nil, err
+		}
+	}
+	if updatedTo == nil {
+		// an empty table matches an empty source
+		updatedTo = &time.Time{}
+	}
+	return updatedTo, nil
+}
+
+func (d *SqlDriver) CreateTable(table *Table, staging string) errors.Error {
+	var columns []string
+	for _, c := range table.Columns {
+		columns = append(columns, fmt.Sprintf("%s %s", d.quote(c.Name), c.Type))
+	}
+	var pks []string
+	for _, c := range table.Columns {
+		if c.PrimaryKey {
+			pks = append(pks, d.quote(c.Name))
+		}
+	}
+	if len(pks) > 0 {
+		columns = append(columns, fmt.Sprintf("PRIMARY KEY (%s)", strings.Join(pks, ", ")))
+	}
+	err := d.db.Exec(fmt.Sprintf("DROP TABLE IF EXISTS %s", d.quote(staging)))
+	if err != nil {
+		return err
+	}
+	tableSql := fmt.Sprintf("CREATE TABLE %s ( %s )", d.quote(staging), strings.Join(columns, ", "))
+	d.logger.Debug(tableSql)
+	return d.db.Exec(tableSql)
+}
+
+func (d *SqlDriver) Write(table *Table, staging string, rows []map[string]interface{}) errors.Error {
+	types := make(map[string]string, len(table.Columns))
+	for _, c := range table.Columns {
+		types[c.Name] = c.Type
+	}
+	for _, row := range rows {
+		for name, value := range row {
+			v, err := d.convertValue(types[name], value)
+			if err != nil {
+				return err
+			}
+			row[name] = v
+		}
+	}
+	return d.db.Create(&rows, dal.From(staging))
+}
+
+// convertValue adapts the values which the target would not take as they are, i.e. mysql booleans are integers
+func (d *SqlDriver) convertValue(columnType string, value interface{}) (interface{}, errors.Error) {
+	switch v := value.(type) {
+	case []string:
+		if d.dialect == "postgres" {
+			return pq.Array(v), nil
+		}
+		b, err := json.Marshal(v)
+		if err != nil {
+			return nil, errors.Convert(err)
+		}
+		return string(b), nil
+	case int64:
+		if columnType == "boolean" && d.dialect == "postgres" {
+			return v != 0, nil
+		}
+	}
+	return value, nil
+}
+
+// Swap renames the tables in one statement on mysql and in one transaction on postgres
+func (d *SqlDriver) Swap(table *Table, staging string) errors.Error {
+	exists, err := d.hasTable(table.Name)
+	if err != nil {
+		return err
+	}
+	old := fmt.Sprintf("%s_old", table.Name)
+	if d.dialect == "mysql" {
+		if !exists {
+			return d.db.Exec(fmt.Sprintf("RENAME TABLE %s TO %s", d.quote(staging), d.quote(table.Name)))
+		}
+		err = d.db.Exec(fmt.Sprintf("DROP TABLE IF EXISTS %s", d.quote(old)))
+		if err != nil {
+			return err
+		}
+		err = d.db.Exec(fmt.Sprintf(
+			"RENAME TABLE %s TO %s, %s TO %s",
+			d.quote(table.Name), d.quote(old), d.quote(staging), d.quote(table.Name),
+		))
+		if err != nil {
+			return err
+		}
+		return d.db.Exec(fmt.Sprintf("DROP TABLE %s", d.quote(old)))
+	}
+	return d.db.Exec(fmt.Sprintf(
+		"BEGIN; DROP TABLE IF EXISTS %s; ALTER TABLE %s RENAME TO %s; COMMIT;",
+		d.quote(table.Name), d.quote(staging), d.quote(table.Name),
+	))
+}
+
+func (d *SqlDriver) Count(table *Table) (int64, errors.Error) {
+	return d.db.Count(dal.From(table.Name))
+}
+
+func (d *SqlDriver) Close() errors.Error {
+	return errors.Convert(d.close())
+}
@@ -0,0 +1,263 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sink
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/apache/incubator-devlake/errors"
+	"github.com/apache/incubator-devlake/plugins/core"
+	"github.com/apache/incubator-devlake/plugins/helper"
+)
+
+func init() {
+	RegisterDriver("starrocks", func(options map[string]interface{}, logger core.Logger) (Driver, errors.Error) {
+		config := &StarRocksConfig{}
+		err := helper.Decode(options, config, nil)
+		if err != nil {
+			return nil, err
+		}
+		return NewStarRocksDriver(config, logger)
+	})
+}
+
+// StarRocksConfig points to the frontend for the queries and to the backend for the stream loads
+type StarRocksConfig struct {
+	Host     string
+	Port     int
+	User     string
+	Password string
+	Database string
+	BeHost   string `mapstructure:"be_host"`
+	BePort   int    `mapstructure:"be_port"`
+	// Extra replaces the properties of the create table statement of the tables
+	Extra map[string]string
+}
+
+// StarRocksDriver loads the rows with the stream load api of the backend
+type StarRocksDriver struct {
+	config *StarRocksConfig
+	db     *sql.DB
+	client *http.Client
+	logger core.Logger
+}
+
+func NewStarRocksDriver(config *StarRocksConfig, logger core.Logger) (*StarRocksDriver, errors.Error) {
+	if config.BeHost == "" {
+		config.BeHost = config.Host
+	}
+	db, err := sql.Open("mysql", fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?charset=utf8mb4&parseTime=True&loc=Local", config.User, config.Password, config.Host, config.Port, config.Database))
+	if err != nil {
+		return nil, errors.Convert(err)
+	}
+	return &StarRocksDriver{
+		config: config,
+		db:     db,
+		client: &http.Client{
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		logger: logger,
+	}, nil
+}
+
+func hasPrefixes(s string, prefixes ...string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(s, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func stringIn(s string, l ...string) bool {
+	for _, item := range l {
+		if s == item {
+			return true
+		}
+	}
+	return false
+}
+
+// MapType analysis and return the data type of StarRocks
+func (d *StarRocksDriver) MapType(dataType string) string {
+	return GetStarRocksDataType(dataType)
+}
+
+// GetStarRocksDataType analysis and return the data type of StarRocks
+func GetStarRocksDataType(dataType string) string {
+	dataType = strings.ToLower(dataType)
+	starrocksDatatype := "string"
+	if hasPrefixes(dataType, "datetime", "timestamp") {
+		starrocksDatatype = "datetime"
+	} else if stringIn(dataType, "date") {
+		starrocksDatatype = "date"
+	} else if strings.HasPrefix(dataType, "bigint") || stringIn(dataType, "bigserial") {
+		starrocksDatatype = "bigint"
+	} else if stringIn(dataType, "char") {
+		starrocksDatatype = "char"
+	} else if stringIn(dataType, "int", "integer", "serial") {
+		starrocksDatatype = "int"
+	} else if stringIn(dataType, "tinyint(1)", "boolean") {
+		starrocksDatatype = "boolean"
+	} else if stringIn(dataType, "smallint", "smallserial") {
+		starrocksDatatype = "smallint"
+	} else if stringIn(dataType, "real") {
+		starrocksDatatype = "float"
+	} else if stringIn(dataType, "numeric", "double precision") {
+		starrocksDatatype = "double"
+	} else if stringIn(dataType, "decimal") {
+		starrocksDatatype = "decimal"
+	} else if stringIn(dataType, "json", "jsonb") {
+		starrocksDatatype = "json"
+	} else if dataType == "uuid" {
+		starrocksDatatype = "char(36)"
+	} else if strings.HasSuffix(dataType, "[]") {
+		starrocksDatatype = fmt.Sprintf("array<%s>", GetStarRocksDataType(strings.Split(dataType, "[]")[0]))
+	}
+	return starrocksDatatype
+}
+
+func (d *StarRocksDriver) Watermark(table *Table, column string) (*time.Time, errors.Error) {
+	rows, err := d.db.Query(fmt.Sprintf("select `%s` from `%s` order by `%s` desc limit 1", column, table.Name, column))
+	if err != nil {
+		if strings.Contains(err.Error(), "Unknown table") {
+			return nil, nil
+		}
+		return nil, errors.Convert(err)
+	}
+	defer rows.Close()
+	var updatedTo time.Time
+	if rows.Next() {
+		err = rows.Scan(&updatedTo)
+		if err != nil {
+			return nil, errors.Convert(err)
+		}
+	}
+	return &updatedTo, nil
+}
+
+func (d *StarRocksDriver) CreateTable(table *Table, staging string) errors.Error {
+	var columns []string
+	for _, c := range table.Columns {
+		columns = append(columns, fmt.Sprintf("`%s` %s", c.Name, c.Type))
+	}
+	var pks []string
+	for _, pk := range table.PrimaryKeys() {
+		pks = append(pks, fmt.Sprintf("`%s`", pk))
+	}
+	extra := fmt.Sprintf(`engine=olap distributed by hash(%s) properties("replication_num" = "1")`, strings.Join(pks, ", "))
+	if v, ok := d.config.Extra[table.Source]; ok {
+		extra = v
+	}
+	tableSql := fmt.Sprintf("drop table if exists %s; create table if not exists `%s` ( %s ) %s", staging, staging, strings.Join(columns, ","), extra)
+	d.logger.Debug(tableSql)
+	_, err := d.db.Exec(tableSql)
+	return errors.Convert(err)
+}
+
+func (d *StarRocksDriver) Write(table *Table, staging string, rows []map[string]interface{}) errors.Error {
+	loadURL := fmt.Sprintf("http://%s:%d/api/%s/%s/_stream_load", d.config.BeHost, d.config.BePort, d.config.Database, staging)
+	jsonData, err := json.Marshal(rows)
+	if err != nil {
+		return errors.Convert(err)
+	}
+	resp, err := d.streamLoad(loadURL, jsonData)
+	if err != nil {
+		return errors.Convert(err)
+	}
+	if resp.StatusCode == http.StatusTemporaryRedirect {
+		var location *url.URL
+		location, err = resp.Location()
+		if err != nil {
+			return errors.Convert(err)
+		}
+		resp, err = d.streamLoad(location.String(), jsonData)
+		if err != nil {
+			return errors.Convert(err)
+		}
+	}
+	defer resp.Body.Close()
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return errors.Convert(err)
+	}
+	var result map[string]interface{}
+	err = json.Unmarshal(b, &result)
+	if err != nil {
+		return errors.Convert(err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		d.logger.Error(nil, "[%d]: %s", resp.StatusCode, string(b))
+	}
+	if result["Status"] != "Success" {
+		d.logger.Error(nil, "load %s failed: %s", table.Source, string(b))
+	} else {
+		d.logger.Debug("load %s success: %s", table.Source, b)
+	}
+	return nil
+}
+
+func (d *StarRocksDriver) streamLoad(loadURL string, jsonData []byte) (*http.Response, error) {
+	req, err := http.NewRequest(http.MethodPut, loadURL, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, err
+	}
+	req.SetBasicAuth(d.config.User, d.config.Password)
+	headers := map[string]string{
+		"format":            "json",
+		"strip_outer_array": "true",
+		"Expect":            "100-continue",
+		"ignore_json_size":  "true",
+		"Connection":        "close",
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	return d.client.Do(req)
+}
+
+func (d *StarRocksDriver) Swap(table *Table, staging string) errors.Error {
+	// drop old table
+	_, err := d.db.Exec(fmt.Sprintf("drop table if exists %s", table.Name))
+	if err != nil {
+		return errors.Convert(err)
+	}
+	// rename tmp table to old table
+	_, err = d.db.Exec(fmt.Sprintf("alter table %s rename %s", staging, table.Name))
+	return errors.Convert(err)
+}
+
+func (d *StarRocksDriver) Count(table *Table) (int64, errors.Error) {
+	var count int64
+	err := d.db.QueryRow(fmt.Sprintf("select count(*) from %s", table.Name)).Scan(&count)
+	return count, errors.Convert(err)
+}
+
+func (d *StarRocksDriver) Close() errors.Error {
+	return errors.Convert(d.db.Close())
+}
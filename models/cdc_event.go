@@ -0,0 +1,57 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import "time"
+
+const (
+	CDC_INSERT = "INSERT"
+	CDC_UPDATE = "UPDATE"
+	CDC_DELETE = "DELETE"
+)
+
+// CdcEvent is an outbox record of a write made by the framework to a domain layer table,
+// it would be delivered to the configured publishers and marked as published afterward
+type CdcEvent struct {
+	ID          uint64     `gorm:"primaryKey" json:"id"`
+	PipelineId  uint64     `gorm:"index" json:"pipelineId"`
+	DomainTable string     `gorm:"type:varchar(255)" json:"table"`
+	EntityId    string     `gorm:"type:varchar(500)" json:"entityId"`
+	Operation   string     `gorm:"type:varchar(20)" json:"operation"`
+	CreatedAt   time.Time  `gorm:"index" json:"createdAt"`
+	PublishedAt *time.Time `gorm:"index" json:"publishedAt"`
+}
+
+func (CdcEvent) TableName() string {
+	return "_devlake_cdc_events"
+}
+
+// CdcSnapshot keeps the fingerprint of a domain layer record deleted by a subtask before it saves the records of
+// its scope again, the snapshots left when the subtask is done are recorded as DELETE events
+type CdcSnapshot struct {
+	// Scope identifies the domain table and the raw data params the subtask works on
+	Scope       string    `gorm:"primaryKey;type:varchar(64)"`
+	EntityId    string    `gorm:"primaryKey;type:varchar(500)"`
+	DomainTable string    `gorm:"type:varchar(255)"`
+	Fingerprint string    `gorm:"type:varchar(64)"`
+	CreatedAt   time.Time `gorm:"index"`
+}
+
+func (CdcSnapshot) TableName() string {
+	return "_devlake_cdc_snapshots"
+}
@@ -0,0 +1,77 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package code
+
+import (
+	"github.com/apache/incubator-devlake/models/common"
+)
+
+const (
+	CODE_PATH_FILE      = "FILE"
+	CODE_PATH_DIRECTORY = "DIRECTORY"
+	// CODE_PATH_ROOT is the path of the root directory of a repo
+	CODE_PATH_ROOT = "."
+)
+
+// CodeOwnership is the number of lines each author owns in a file or a directory at the snapshot commit,
+// a line is owned by the author of the commit which changed it last
+type CodeOwnership struct {
+	common.NoPKModel
+	RepoId            string `gorm:"primaryKey;type:varchar(255)"`
+	SnapshotCommitSha string `gorm:"primaryKey;type:varchar(40)"`
+	Path              string `gorm:"primaryKey;type:varchar(255)"`
+	AuthorId          string `gorm:"primaryKey;type:varchar(190)"`
+	PathType          string `gorm:"type:varchar(20)"`
+	AuthorName        string `gorm:"type:varchar(255)"`
+	LineCount         int
+	// Ownership is the share of the lines in the path owned by the author, between 0 and 1
+	Ownership float64
+}
+
+func (CodeOwnership) TableName() string {
+	return "code_ownerships"
+}
+
+// CodeHotspot summarizes the ownership of a file or a directory at the snapshot commit along with its churn in
+// the window of WindowDays days before the snapshot commit, there is one record per path for each window
+type CodeHotspot struct {
+	common.NoPKModel
+	RepoId            string `gorm:"primaryKey;type:varchar(255)"`
+	SnapshotCommitSha string `gorm:"primaryKey;type:varchar(40)"`
+	Path              string `gorm:"primaryKey;type:varchar(255)"`
+	WindowDays        int    `gorm:"primaryKey"`
+	PathType          string `gorm:"type:varchar(20)"`
+	LineCount         int
+	FileCount         int
+	AuthorCount       int
+	// BusFactor is the least number of authors owning more than half of the lines
+	BusFactor          int
+	TopAuthorId        string `gorm:"type:varchar(255)"`
+	TopAuthorOwnership float64
+	CommitCount        int
+	Additions          int
+	Deletions          int
+	// Churn is the sum of the additions and deletions in the window
+	Churn int
+	// HotspotScore is the churn multiplied by the size in lines
+	HotspotScore float64
+}
+
+func (CodeHotspot) TableName() string {
+	return "code_hotspots"
+}
@@ -85,12 +85,14 @@ func (CommitLineChange) TableName() string {
 	return "commit_line_change"
 }
 
+// RepoSnapshot is the commit which changed each line last at the head of a branch
 type RepoSnapshot struct {
 	common.NoPKModel
 	RepoId    string `gorm:"primaryKey;type:varchar(255)"`
-	CommitSha string `gorm:"primaryKey;type:varchar(40);"`
+	Branch    string `gorm:"primaryKey;type:varchar(255)"`
 	FilePath  string `gorm:"primaryKey;type:varchar(255);"`
 	LineNo    int    `gorm:"primaryKey;type:int;"`
+	CommitSha string `gorm:"type:varchar(40);"`
 }
 
 func (RepoSnapshot) TableName() string {
@@ -0,0 +1,32 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package code
+
+import "github.com/apache/incubator-devlake/models/common"
+
+// CommitBranch relates a commit to each of the collected branches it is reachable from
+type CommitBranch struct {
+	RepoId    string `json:"repoId" gorm:"primaryKey;type:varchar(255)"`
+	CommitSha string `json:"commitSha" gorm:"primaryKey;type:varchar(40)"`
+	Branch    string `json:"branch" gorm:"primaryKey;type:varchar(255)"`
+	common.NoPKModel
+}
+
+func (CommitBranch) TableName() string {
+	return "commit_branches"
+}
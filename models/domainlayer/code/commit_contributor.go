@@ -0,0 +1,42 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package code
+
+import "github.com/apache/incubator-devlake/models/common"
+
+const (
+	COMMIT_AUTHOR    = "AUTHOR"
+	COMMIT_CO_AUTHOR = "CO_AUTHOR"
+	COMMIT_REVIEWER  = "REVIEWER"
+	COMMIT_SIGNER    = "SIGNER"
+)
+
+// CommitContributor records everyone who contributed to a commit, the author and the people mentioned in the
+// Co-authored-by, Reviewed-by and Signed-off-by trailers, with the identities canonicalized by the .mailmap
+type CommitContributor struct {
+	common.NoPKModel
+	CommitSha string `json:"commitSha" gorm:"primaryKey;type:varchar(40);comment:commit hash"`
+	Email     string `json:"email" gorm:"primaryKey;type:varchar(255)"`
+	Role      string `json:"role" gorm:"primaryKey;type:varchar(50)"`
+	Name      string `json:"name" gorm:"type:varchar(255)"`
+	AccountId string `json:"accountId" gorm:"index;type:varchar(255)"`
+}
+
+func (CommitContributor) TableName() string {
+	return "commit_contributors"
+}
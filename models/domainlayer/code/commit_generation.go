@@ -0,0 +1,29 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package code
+
+// CommitGeneration is the generation number of a commit, i.e. the length of the longest path to a root commit,
+// it is maintained incrementally from commit_parents and lets graph walks stop before reaching the roots
+type CommitGeneration struct {
+	CommitSha  string `gorm:"primaryKey;type:varchar(40);comment:commit hash"`
+	Generation int    `gorm:"index;comment:1 for root commits, otherwise 1 + the max generation of the parents"`
+}
+
+func (CommitGeneration) TableName() string {
+	return "commit_generations"
+}
@@ -21,10 +21,20 @@ import (
 	"github.com/apache/incubator-devlake/models/domainlayer"
 )
 
+// the confidence of the attribution of an incident to a deployment
+const (
+	ATTRIBUTION_CONFIDENCE_HIGH   = "HIGH"
+	ATTRIBUTION_CONFIDENCE_MEDIUM = "MEDIUM"
+	ATTRIBUTION_CONFIDENCE_LOW    = "LOW"
+)
+
 type ProjectIssueMetric struct {
 	domainlayer.DomainEntity
 	ProjectName  string `gorm:"primaryKey;type:varchar(100)"`
 	DeploymentId string
+	// AttributionStrategy is the strategy which attributed the incident to the deployment
+	AttributionStrategy   string `gorm:"type:varchar(50)"`
+	AttributionConfidence string `gorm:"type:varchar(20)"`
 }
 
 func (ProjectIssueMetric) TableName() string {
@@ -17,7 +17,11 @@ limitations under the License.
 
 package crossdomain
 
-import "github.com/apache/incubator-devlake/models/common"
+import (
+	"time"
+
+	"github.com/apache/incubator-devlake/models/common"
+)
 
 type ProjectMapping struct {
 	ProjectName string `gorm:"primaryKey;type:varchar(255)"`
@@ -29,3 +33,22 @@ type ProjectMapping struct {
 func (ProjectMapping) TableName() string {
 	return "project_mapping"
 }
+
+// UnboundedValidFrom is the ValidFrom of the first mapping of a row to a project, which covers the events before the
+// row was mapped as well. The periods of the later mappings of the row start when they are made
+var UnboundedValidFrom = time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// ProjectMappingHistory keeps every period in which the row belonged to the project, so metrics could be calculated
+// with the mapping valid at the date of the event. The current mappings have no ValidTo and match `project_mapping`
+type ProjectMappingHistory struct {
+	ProjectName string    `gorm:"primaryKey;type:varchar(255)"`
+	Table       string    `gorm:"primaryKey;type:varchar(255)"`
+	RowId       string    `gorm:"primaryKey;type:varchar(255)"`
+	ValidFrom   time.Time `gorm:"primaryKey"`
+	ValidTo     *time.Time
+	common.NoPKModel
+}
+
+func (ProjectMappingHistory) TableName() string {
+	return "project_mapping_history"
+}
@@ -0,0 +1,37 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package crossdomain
+
+import "github.com/apache/incubator-devlake/models/common"
+
+// VersionsIssuesDiffs reconciles the issues a ticket version claims to ship with the issues
+// refdiff found between the pair of refs matching the version
+type VersionsIssuesDiffs struct {
+	VersionId   string `gorm:"primaryKey;type:varchar(255)"`
+	NewRefId    string `gorm:"primaryKey;type:varchar(255)"`
+	OldRefId    string `gorm:"primaryKey;type:varchar(255)"`
+	IssueId     string `gorm:"primaryKey;type:varchar(255)"`
+	IssueNumber string `gorm:"type:varchar(255)"`
+	InVersion   bool
+	InRefsDiff  bool
+	common.NoPKModel
+}
+
+func (VersionsIssuesDiffs) TableName() string {
+	return "versions_issues_diffs"
+}
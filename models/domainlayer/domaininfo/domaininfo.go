@@ -31,10 +31,15 @@ type Tabler interface {
 func GetDomainTablesInfo() []Tabler {
 	return []Tabler{
 		// code
+		&code.CodeHotspot{},
+		&code.CodeOwnership{},
 		&code.Commit{},
+		&code.CommitBranch{},
 		&code.CommitFile{},
 		&code.CommitFileComponent{},
+		&code.CommitContributor{},
 		&code.CommitParent{},
+		&code.CommitGeneration{},
 		&code.Component{},
 		&code.PullRequest{},
 		&code.PullRequestComment{},
@@ -54,12 +59,14 @@ func GetDomainTablesInfo() []Tabler {
 		&crossdomain.IssueCommit{},
 		&crossdomain.IssueRepoCommit{},
 		&crossdomain.ProjectMapping{},
+		&crossdomain.ProjectMappingHistory{},
 		&crossdomain.PullRequestIssue{},
 		&crossdomain.RefsIssuesDiffs{},
 		&crossdomain.Team{},
 		&crossdomain.TeamUser{},
 		&crossdomain.User{},
 		&crossdomain.UserAccount{},
+		&crossdomain.VersionsIssuesDiffs{},
 		// devops
 		&devops.CICDPipeline{},
 		&devops.CICDTask{},
@@ -71,9 +78,11 @@ func GetDomainTablesInfo() []Tabler {
 		&ticket.Issue{},
 		&ticket.IssueChangelogs{},
 		&ticket.IssueComment{},
+		&ticket.IssueFixVersion{},
 		&ticket.IssueLabel{},
 		&ticket.IssueWorklog{},
 		&ticket.Sprint{},
 		&ticket.SprintIssue{},
+		&ticket.Version{},
 	}
 }
@@ -0,0 +1,57 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ticket
+
+import (
+	"time"
+
+	"github.com/apache/incubator-devlake/models/common"
+	"github.com/apache/incubator-devlake/models/domainlayer"
+)
+
+const (
+	VersionReleased   = "RELEASED"
+	VersionUnreleased = "UNRELEASED"
+	VersionArchived   = "ARCHIVED"
+)
+
+// Version is a planned or shipped release of a project, e.g. a fixVersion in Jira
+type Version struct {
+	domainlayer.DomainEntity
+	Name            string `gorm:"type:varchar(255)"`
+	Description     string
+	Url             string `gorm:"type:varchar(255)"`
+	Status          string `gorm:"type:varchar(100)"`
+	StartedDate     *time.Time
+	ReleasedDate    *time.Time
+	OriginalProject string `gorm:"type:varchar(255)"`
+}
+
+func (Version) TableName() string {
+	return "versions"
+}
+
+type IssueFixVersion struct {
+	common.NoPKModel
+	IssueId   string `gorm:"primaryKey;type:varchar(255)"`
+	VersionId string `gorm:"primaryKey;type:varchar(255)"`
+}
+
+func (IssueFixVersion) TableName() string {
+	return "issue_fix_versions"
+}
@@ -0,0 +1,88 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migrationscripts
+
+import (
+	"time"
+
+	"github.com/apache/incubator-devlake/errors"
+	"github.com/apache/incubator-devlake/helpers/migrationhelper"
+	"github.com/apache/incubator-devlake/models/migrationscripts/archived"
+	"github.com/apache/incubator-devlake/plugins/core"
+)
+
+var _ core.MigrationScript = (*addVersions20230103)(nil)
+
+type version20230103 struct {
+	archived.DomainEntity
+	Name            string `gorm:"type:varchar(255)"`
+	Description     string
+	Url             string `gorm:"type:varchar(255)"`
+	Status          string `gorm:"type:varchar(100)"`
+	StartedDate     *time.Time
+	ReleasedDate    *time.Time
+	OriginalProject string `gorm:"type:varchar(255)"`
+}
+
+func (version20230103) TableName() string {
+	return "versions"
+}
+
+type issueFixVersion20230103 struct {
+	archived.NoPKModel
+	IssueId   string `gorm:"primaryKey;type:varchar(255)"`
+	VersionId string `gorm:"primaryKey;type:varchar(255)"`
+}
+
+func (issueFixVersion20230103) TableName() string {
+	return "issue_fix_versions"
+}
+
+type versionsIssuesDiff20230103 struct {
+	VersionId   string `gorm:"primaryKey;type:varchar(255)"`
+	NewRefId    string `gorm:"primaryKey;type:varchar(255)"`
+	OldRefId    string `gorm:"primaryKey;type:varchar(255)"`
+	IssueId     string `gorm:"primaryKey;type:varchar(255)"`
+	IssueNumber string `gorm:"type:varchar(255)"`
+	InVersion   bool
+	InRefsDiff  bool
+	archived.NoPKModel
+}
+
+func (versionsIssuesDiff20230103) TableName() string {
+	return "versions_issues_diffs"
+}
+
+type addVersions20230103 struct{}
+
+func (script *addVersions20230103) Up(basicRes core.BasicRes) errors.Error {
+	return migrationhelper.AutoMigrateTables(
+		basicRes,
+		&version20230103{},
+		&issueFixVersion20230103{},
+		&versionsIssuesDiff20230103{},
+	)
+}
+
+func (*addVersions20230103) Version() uint64 {
+	return 20230103101316
+}
+
+func (*addVersions20230103) Name() string {
+	return "add versions, issue_fix_versions and versions_issues_diffs"
+}
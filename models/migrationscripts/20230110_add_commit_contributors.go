@@ -0,0 +1,57 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migrationscripts
+
+import (
+	"github.com/apache/incubator-devlake/errors"
+	"github.com/apache/incubator-devlake/helpers/migrationhelper"
+	"github.com/apache/incubator-devlake/models/migrationscripts/archived"
+	"github.com/apache/incubator-devlake/plugins/core"
+)
+
+var _ core.MigrationScript = (*addCommitContributors20230110)(nil)
+
+type commitContributor20230110 struct {
+	archived.NoPKModel
+	CommitSha string `gorm:"primaryKey;type:varchar(40);comment:commit hash"`
+	Email     string `gorm:"primaryKey;type:varchar(255)"`
+	Role      string `gorm:"primaryKey;type:varchar(50)"`
+	Name      string `gorm:"type:varchar(255)"`
+	AccountId string `gorm:"index;type:varchar(255)"`
+}
+
+func (commitContributor20230110) TableName() string {
+	return "commit_contributors"
+}
+
+type addCommitContributors20230110 struct{}
+
+func (script *addCommitContributors20230110) Up(basicRes core.BasicRes) errors.Error {
+	return migrationhelper.AutoMigrateTables(
+		basicRes,
+		&commitContributor20230110{},
+	)
+}
+
+func (*addCommitContributors20230110) Version() uint64 {
+	return 20230110143209
+}
+
+func (*addCommitContributors20230110) Name() string {
+	return "add commit_contributors table"
+}
@@ -0,0 +1,85 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migrationscripts
+
+import (
+	"github.com/apache/incubator-devlake/errors"
+	"github.com/apache/incubator-devlake/helpers/migrationhelper"
+	"github.com/apache/incubator-devlake/models/migrationscripts/archived"
+	"github.com/apache/incubator-devlake/plugins/core"
+)
+
+var _ core.MigrationScript = (*addCodeOwnerships20230112)(nil)
+
+type codeOwnership20230112 struct {
+	archived.NoPKModel
+	RepoId            string `gorm:"primaryKey;type:varchar(255)"`
+	SnapshotCommitSha string `gorm:"primaryKey;type:varchar(40)"`
+	Path              string `gorm:"primaryKey;type:varchar(255)"`
+	AuthorId          string `gorm:"primaryKey;type:varchar(190)"`
+	PathType          string `gorm:"type:varchar(20)"`
+	AuthorName        string `gorm:"type:varchar(255)"`
+	LineCount         int
+	Ownership         float64
+}
+
+func (codeOwnership20230112) TableName() string {
+	return "code_ownerships"
+}
+
+type codeHotspot20230112 struct {
+	archived.NoPKModel
+	RepoId             string `gorm:"primaryKey;type:varchar(255)"`
+	SnapshotCommitSha  string `gorm:"primaryKey;type:varchar(40)"`
+	Path               string `gorm:"primaryKey;type:varchar(255)"`
+	WindowDays         int    `gorm:"primaryKey"`
+	PathType           string `gorm:"type:varchar(20)"`
+	LineCount          int
+	FileCount          int
+	AuthorCount        int
+	BusFactor          int
+	TopAuthorId        string `gorm:"type:varchar(255)"`
+	TopAuthorOwnership float64
+	CommitCount        int
+	Additions          int
+	Deletions          int
+	Churn              int
+	HotspotScore       float64
+}
+
+func (codeHotspot20230112) TableName() string {
+	return "code_hotspots"
+}
+
+type addCodeOwnerships20230112 struct{}
+
+func (script *addCodeOwnerships20230112) Up(basicRes core.BasicRes) errors.Error {
+	return migrationhelper.AutoMigrateTables(
+		basicRes,
+		&codeOwnership20230112{},
+		&codeHotspot20230112{},
+	)
+}
+
+func (*addCodeOwnerships20230112) Version() uint64 {
+	return 20230112101532
+}
+
+func (*addCodeOwnerships20230112) Name() string {
+	return "add code_ownerships and code_hotspots tables"
+}
@@ -0,0 +1,71 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migrationscripts
+
+import (
+	"github.com/apache/incubator-devlake/errors"
+	"github.com/apache/incubator-devlake/helpers/migrationhelper"
+	"github.com/apache/incubator-devlake/models/migrationscripts/archived"
+	"github.com/apache/incubator-devlake/plugins/core"
+)
+
+var _ core.MigrationScript = (*addBranchToDiffLines20230113)(nil)
+
+type commitLineChange20230113 struct {
+	Branch string `gorm:"type:varchar(255)"`
+}
+
+func (commitLineChange20230113) TableName() string {
+	return "commit_line_change"
+}
+
+type repoSnapshot20230113 struct {
+	archived.NoPKModel
+	RepoId    string `gorm:"primaryKey;type:varchar(255)"`
+	Branch    string `gorm:"primaryKey;type:varchar(255)"`
+	FilePath  string `gorm:"primaryKey;type:varchar(255);"`
+	LineNo    int    `gorm:"primaryKey;type:int;"`
+	CommitSha string `gorm:"type:varchar(40);"`
+}
+
+func (repoSnapshot20230113) TableName() string {
+	return "repo_snapshot"
+}
+
+type addBranchToDiffLines20230113 struct{}
+
+func (script *addBranchToDiffLines20230113) Up(basicRes core.BasicRes) errors.Error {
+	// the snapshot is rebuilt by gitextractor on every run, so the table is recreated instead of changing the primary key
+	err := basicRes.GetDal().DropTables(&repoSnapshot20230113{})
+	if err != nil {
+		return err
+	}
+	return migrationhelper.AutoMigrateTables(
+		basicRes,
+		&commitLineChange20230113{},
+		&repoSnapshot20230113{},
+	)
+}
+
+func (*addBranchToDiffLines20230113) Version() uint64 {
+	return 20230113152047
+}
+
+func (*addBranchToDiffLines20230113) Name() string {
+	return "add branch to commit_line_change and repo_snapshot"
+}
@@ -0,0 +1,79 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migrationscripts
+
+import (
+	"time"
+
+	"github.com/apache/incubator-devlake/errors"
+	"github.com/apache/incubator-devlake/helpers/migrationhelper"
+	"github.com/apache/incubator-devlake/models/migrationscripts/archived"
+	"github.com/apache/incubator-devlake/plugins/core"
+)
+
+var _ core.MigrationScript = (*addProjectMappingHistory)(nil)
+
+type projectMappingHistory20230117 struct {
+	ProjectName string    `gorm:"primaryKey;type:varchar(255)"`
+	Table       string    `gorm:"primaryKey;type:varchar(255)"`
+	RowId       string    `gorm:"primaryKey;type:varchar(255)"`
+	ValidFrom   time.Time `gorm:"primaryKey"`
+	ValidTo     *time.Time
+	archived.NoPKModel
+}
+
+func (projectMappingHistory20230117) TableName() string {
+	return "project_mapping_history"
+}
+
+type addProjectMappingHistory struct{}
+
+// Up creates `project_mapping_history` with the current mappings, which are the first ones of the rows, so they are
+// valid for the events before they were created as well
+func (script *addProjectMappingHistory) Up(basicRes core.BasicRes) errors.Error {
+	db := basicRes.GetDal()
+	err := migrationhelper.AutoMigrateTables(basicRes, &projectMappingHistory20230117{})
+	if err != nil {
+		return err
+	}
+	var mappings []archived.ProjectMapping
+	err = db.All(&mappings)
+	if err != nil {
+		return err
+	}
+	for _, mapping := range mappings {
+		err = db.Create(&projectMappingHistory20230117{
+			ProjectName: mapping.ProjectName,
+			Table:       mapping.Table,
+			RowId:       mapping.RowId,
+			ValidFrom:   time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (*addProjectMappingHistory) Version() uint64 {
+	return 20230117093012
+}
+
+func (*addProjectMappingHistory) Name() string {
+	return "add project_mapping_history"
+}
@@ -0,0 +1,53 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migrationscripts
+
+import (
+	"github.com/apache/incubator-devlake/errors"
+	"github.com/apache/incubator-devlake/helpers/migrationhelper"
+	"github.com/apache/incubator-devlake/plugins/core"
+)
+
+var _ core.MigrationScript = (*addCommitGenerations)(nil)
+
+type commitGeneration20230119 struct {
+	CommitSha  string `gorm:"primaryKey;type:varchar(40)"`
+	Generation int    `gorm:"index"`
+}
+
+func (commitGeneration20230119) TableName() string {
+	return "commit_generations"
+}
+
+type addCommitGenerations struct{}
+
+func (script *addCommitGenerations) Up(basicRes core.BasicRes) errors.Error {
+	// the generations are filled by gitextractor and refdiff on their next runs
+	return migrationhelper.AutoMigrateTables(
+		basicRes,
+		&commitGeneration20230119{},
+	)
+}
+
+func (*addCommitGenerations) Version() uint64 {
+	return 20230119093512
+}
+
+func (*addCommitGenerations) Name() string {
+	return "add commit_generations table"
+}
@@ -0,0 +1,59 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migrationscripts
+
+import (
+	"time"
+
+	"github.com/apache/incubator-devlake/errors"
+	"github.com/apache/incubator-devlake/helpers/migrationhelper"
+	"github.com/apache/incubator-devlake/plugins/core"
+)
+
+var _ core.MigrationScript = (*addCdcEvents)(nil)
+
+type cdcEvent20230121 struct {
+	ID          uint64 `gorm:"primaryKey"`
+	PipelineId  uint64 `gorm:"index"`
+	DomainTable string `gorm:"type:varchar(255)"`
+	EntityId    string `gorm:"type:varchar(500)"`
+	Operation   string `gorm:"type:varchar(20)"`
+	CreatedAt   time.Time
+	PublishedAt *time.Time `gorm:"index"`
+}
+
+func (cdcEvent20230121) TableName() string {
+	return "_devlake_cdc_events"
+}
+
+type addCdcEvents struct{}
+
+func (script *addCdcEvents) Up(basicRes core.BasicRes) errors.Error {
+	return migrationhelper.AutoMigrateTables(
+		basicRes,
+		&cdcEvent20230121{},
+	)
+}
+
+func (*addCdcEvents) Version() uint64 {
+	return 20230121101530
+}
+
+func (*addCdcEvents) Name() string {
+	return "add _devlake_cdc_events table"
+}
@@ -0,0 +1,61 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migrationscripts
+
+import (
+	"github.com/apache/incubator-devlake/errors"
+	"github.com/apache/incubator-devlake/helpers/migrationhelper"
+	"github.com/apache/incubator-devlake/models/migrationscripts/archived"
+	"github.com/apache/incubator-devlake/plugins/core"
+)
+
+var _ core.MigrationScript = (*addCommitBranches)(nil)
+
+type commitBranch20230128 struct {
+	RepoId    string `gorm:"primaryKey;type:varchar(255)"`
+	CommitSha string `gorm:"primaryKey;type:varchar(40)"`
+	Branch    string `gorm:"primaryKey;type:varchar(255)"`
+	archived.NoPKModel
+}
+
+func (commitBranch20230128) TableName() string {
+	return "commit_branches"
+}
+
+type addCommitBranches struct{}
+
+func (script *addCommitBranches) Up(basicRes core.BasicRes) errors.Error {
+	// a commit may be on several branches, so the single branch of commit_line_change is replaced by the relation
+	// table, which is filled by gitextractor on its next run
+	err := basicRes.GetDal().DropColumns("commit_line_change", "branch")
+	if err != nil {
+		return err
+	}
+	return migrationhelper.AutoMigrateTables(
+		basicRes,
+		&commitBranch20230128{},
+	)
+}
+
+func (*addCommitBranches) Version() uint64 {
+	return 20230128102415
+}
+
+func (*addCommitBranches) Name() string {
+	return "add commit_branches table and drop branch from commit_line_change"
+}
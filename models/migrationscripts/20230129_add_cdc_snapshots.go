@@ -0,0 +1,72 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migrationscripts
+
+import (
+	"time"
+
+	"github.com/apache/incubator-devlake/errors"
+	"github.com/apache/incubator-devlake/helpers/migrationhelper"
+	"github.com/apache/incubator-devlake/plugins/core"
+)
+
+var _ core.MigrationScript = (*addCdcSnapshots)(nil)
+
+type cdcEvent20230129 struct {
+	ID          uint64     `gorm:"primaryKey"`
+	PipelineId  uint64     `gorm:"index"`
+	DomainTable string     `gorm:"type:varchar(255)"`
+	EntityId    string     `gorm:"type:varchar(500)"`
+	Operation   string     `gorm:"type:varchar(20)"`
+	CreatedAt   time.Time  `gorm:"index"`
+	PublishedAt *time.Time `gorm:"index"`
+}
+
+func (cdcEvent20230129) TableName() string {
+	return "_devlake_cdc_events"
+}
+
+type cdcSnapshot20230129 struct {
+	Scope       string    `gorm:"primaryKey;type:varchar(64)"`
+	EntityId    string    `gorm:"primaryKey;type:varchar(500)"`
+	DomainTable string    `gorm:"type:varchar(255)"`
+	Fingerprint string    `gorm:"type:varchar(64)"`
+	CreatedAt   time.Time `gorm:"index"`
+}
+
+func (cdcSnapshot20230129) TableName() string {
+	return "_devlake_cdc_snapshots"
+}
+
+type addCdcSnapshots struct{}
+
+func (script *addCdcSnapshots) Up(basicRes core.BasicRes) errors.Error {
+	return migrationhelper.AutoMigrateTables(
+		basicRes,
+		&cdcEvent20230129{},
+		&cdcSnapshot20230129{},
+	)
+}
+
+func (*addCdcSnapshots) Version() uint64 {
+	return 20230129093042
+}
+
+func (*addCdcSnapshots) Name() string {
+	return "add _devlake_cdc_snapshots table and index the creation time of _devlake_cdc_events"
+}
@@ -69,5 +69,15 @@ func All() []core.MigrationScript {
 		new(encryptTask221221),
 		new(renameProjectMetrics),
 		new(addOriginalTypeToIssue221230),
+		new(addVersions20230103),
+		new(addCommitContributors20230110),
+		new(addCodeOwnerships20230112),
+		new(addBranchToDiffLines20230113),
+		new(addAttributionToProjectIssueMetrics),
+		new(addProjectMappingHistory),
+		new(addCommitGenerations),
+		new(addCdcEvents),
+		new(addCommitBranches),
+		new(addCdcSnapshots),
 	}
 }
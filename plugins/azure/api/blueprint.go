@@ -21,9 +21,9 @@ import (
 	"encoding/json"
 
 	"github.com/apache/incubator-devlake/errors"
+	"github.com/apache/incubator-devlake/plugins/azure/tasks"
 	"github.com/apache/incubator-devlake/plugins/core"
 	"github.com/apache/incubator-devlake/plugins/helper"
-	"github.com/apache/incubator-devlake/plugins/jenkins/tasks"
 )
 
 func MakePipelinePlan(subtaskMetas []core.SubTaskMeta, connectionId uint64, scope []*core.BlueprintScopeV100) (core.PipelinePlan, errors.Error) {
@@ -41,7 +41,7 @@ func MakePipelinePlan(subtaskMetas []core.SubTaskMeta, connectionId uint64, scop
 		if err != nil {
 			return nil, err
 		}
-		_, err = tasks.ValidateTaskOptions(op)
+		err = tasks.ValidateTaskOptions(op)
 		if err != nil {
 			return nil, err
 		}
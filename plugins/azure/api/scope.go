@@ -0,0 +1,219 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/apache/incubator-devlake/errors"
+	"github.com/apache/incubator-devlake/plugins/azure/models"
+	"github.com/apache/incubator-devlake/plugins/core"
+	"github.com/apache/incubator-devlake/plugins/core/dal"
+	"github.com/apache/incubator-devlake/plugins/helper"
+	"github.com/mitchellh/mapstructure"
+)
+
+type apiRepo struct {
+	models.AzureRepo
+	TransformationRuleName string `json:"transformationRuleName,omitempty"`
+}
+
+type req struct {
+	Data []*models.AzureRepo `json:"data"`
+}
+
+// PutScope create or update azure repo
+// @Summary create or update azure repo
+// @Description Create or update azure repo
+// @Tags plugins/azure
+// @Accept application/json
+// @Param connectionId path int true "connection ID"
+// @Param scope body req true "json"
+// @Success 200  {object} []models.AzureRepo
+// @Failure 400  {object} shared.ApiBody "Bad Request"
+// @Failure 500  {object} shared.ApiBody "Internal Error"
+// @Router /plugins/azure/connections/{connectionId}/scopes [PUT]
+func PutScope(input *core.ApiResourceInput) (*core.ApiResourceOutput, errors.Error) {
+	connectionId, _ := extractParam(input.Params)
+	if connectionId == 0 {
+		return nil, errors.BadInput.New("invalid connectionId")
+	}
+	var repos req
+	err := errors.Convert(mapstructure.Decode(input.Body, &repos))
+	if err != nil {
+		return nil, errors.BadInput.Wrap(err, "decoding Azure repo error")
+	}
+	keeper := make(map[string]struct{})
+	for _, repo := range repos.Data {
+		if _, ok := keeper[repo.AzureId]; ok {
+			return nil, errors.BadInput.New("duplicated item")
+		} else {
+			keeper[repo.AzureId] = struct{}{}
+		}
+		repo.ConnectionId = connectionId
+		err = verifyRepo(repo)
+		if err != nil {
+			return nil, err
+		}
+	}
+	err = basicRes.GetDal().CreateOrUpdate(repos.Data)
+	if err != nil {
+		return nil, errors.Default.Wrap(err, "error on saving AzureRepo")
+	}
+	return &core.ApiResourceOutput{Body: repos.Data, Status: http.StatusOK}, nil
+}
+
+// UpdateScope patch to azure repo
+// @Summary patch to azure repo
+// @Description patch to azure repo
+// @Tags plugins/azure
+// @Accept application/json
+// @Param connectionId path int true "connection ID"
+// @Param repoId path string true "repo ID"
+// @Param scope body models.AzureRepo true "json"
+// @Success 200  {object} models.AzureRepo
+// @Failure 400  {object} shared.ApiBody "Bad Request"
+// @Failure 500  {object} shared.ApiBody "Internal Error"
+// @Router /plugins/azure/connections/{connectionId}/scopes/{repoId} [PATCH]
+func UpdateScope(input *core.ApiResourceInput) (*core.ApiResourceOutput, errors.Error) {
+	connectionId, repoId := extractParam(input.Params)
+	if connectionId == 0 || repoId == "" {
+		return nil, errors.BadInput.New("invalid connectionId or repoId")
+	}
+	var repo models.AzureRepo
+	err := basicRes.GetDal().First(&repo, dal.Where("connection_id = ? AND azure_id = ?", connectionId, repoId))
+	if err != nil {
+		return nil, errors.Default.Wrap(err, "getting AzureRepo error")
+	}
+	err = helper.DecodeMapStruct(input.Body, &repo)
+	if err != nil {
+		return nil, errors.Default.Wrap(err, "patch azure repo error")
+	}
+	// the primary key should not be changed
+	repo.ConnectionId = connectionId
+	repo.AzureId = repoId
+	err = verifyRepo(&repo)
+	if err != nil {
+		return nil, err
+	}
+	err = basicRes.GetDal().Update(repo)
+	if err != nil {
+		return nil, errors.Default.Wrap(err, "error on saving AzureRepo")
+	}
+	return &core.ApiResourceOutput{Body: repo, Status: http.StatusOK}, nil
+}
+
+// GetScopeList get Azure repos
+// @Summary get Azure repos
+// @Description get Azure repos
+// @Tags plugins/azure
+// @Param connectionId path int true "connection ID"
+// @Param pageSize query int false "page size, default 50"
+// @Param page query int false "page size, default 1"
+// @Success 200  {object} []apiRepo
+// @Failure 400  {object} shared.ApiBody "Bad Request"
+// @Failure 500  {object} shared.ApiBody "Internal Error"
+// @Router /plugins/azure/connections/{connectionId}/scopes/ [GET]
+func GetScopeList(input *core.ApiResourceInput) (*core.ApiResourceOutput, errors.Error) {
+	var repos []models.AzureRepo
+	connectionId, _ := extractParam(input.Params)
+	if connectionId == 0 {
+		return nil, errors.BadInput.New("invalid path params")
+	}
+	limit, offset := helper.GetLimitOffset(input.Query, "pageSize", "page")
+	err := basicRes.GetDal().All(&repos, dal.Where("connection_id = ?", connectionId), dal.Limit(limit), dal.Offset(offset))
+	if err != nil {
+		return nil, err
+	}
+	var ruleIds []uint64
+	for _, repo := range repos {
+		if repo.TransformationRuleId > 0 {
+			ruleIds = append(ruleIds, repo.TransformationRuleId)
+		}
+	}
+	var rules []models.AzureTransformationRule
+	if len(ruleIds) > 0 {
+		err = basicRes.GetDal().All(&rules, dal.Where("id IN (?)", ruleIds))
+		if err != nil {
+			return nil, err
+		}
+	}
+	names := make(map[uint64]string)
+	for _, rule := range rules {
+		names[rule.ID] = rule.Name
+	}
+	var apiRepos []apiRepo
+	for _, repo := range repos {
+		apiRepos = append(apiRepos, apiRepo{repo, names[repo.TransformationRuleId]})
+	}
+	return &core.ApiResourceOutput{Body: apiRepos, Status: http.StatusOK}, nil
+}
+
+// GetScope get one Azure repo
+// @Summary get one Azure repo
+// @Description get one Azure repo
+// @Tags plugins/azure
+// @Param connectionId path int true "connection ID"
+// @Param repoId path string true "repo ID"
+// @Success 200  {object} apiRepo
+// @Failure 400  {object} shared.ApiBody "Bad Request"
+// @Failure 500  {object} shared.ApiBody "Internal Error"
+// @Router /plugins/azure/connections/{connectionId}/scopes/{repoId} [GET]
+func GetScope(input *core.ApiResourceInput) (*core.ApiResourceOutput, errors.Error) {
+	var repo models.AzureRepo
+	connectionId, repoId := extractParam(input.Params)
+	if connectionId == 0 || repoId == "" {
+		return nil, errors.BadInput.New("invalid path params")
+	}
+	db := basicRes.GetDal()
+	err := db.First(&repo, dal.Where("connection_id = ? AND azure_id = ?", connectionId, repoId))
+	if db.IsErrorNotFound(err) {
+		return nil, errors.NotFound.New("record not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+	var rule models.AzureTransformationRule
+	if repo.TransformationRuleId > 0 {
+		err = basicRes.GetDal().First(&rule, dal.Where("id = ?", repo.TransformationRuleId))
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &core.ApiResourceOutput{Body: apiRepo{repo, rule.Name}, Status: http.StatusOK}, nil
+}
+
+func extractParam(params map[string]string) (uint64, string) {
+	connectionId, _ := strconv.ParseUint(params["connectionId"], 10, 64)
+	repoId := params["repoId"]
+	return connectionId, repoId
+}
+
+func verifyRepo(repo *models.AzureRepo) errors.Error {
+	if repo.ConnectionId == 0 {
+		return errors.BadInput.New("invalid connectionId")
+	}
+	if repo.AzureId == "" {
+		return errors.BadInput.New("invalid id of the repo")
+	}
+	if repo.ProjectId == "" {
+		return errors.BadInput.New("invalid projectId of the repo")
+	}
+	return nil
+}
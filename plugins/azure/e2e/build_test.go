@@ -0,0 +1,143 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package e2e
+
+import (
+	"testing"
+
+	"github.com/apache/incubator-devlake/helpers/e2ehelper"
+	"github.com/apache/incubator-devlake/models/domainlayer/devops"
+	"github.com/apache/incubator-devlake/plugins/azure/impl"
+	"github.com/apache/incubator-devlake/plugins/azure/models"
+	"github.com/apache/incubator-devlake/plugins/azure/tasks"
+)
+
+func TestAzureBuildDataFlow(t *testing.T) {
+	var azure impl.Azure
+	dataflowTester := e2ehelper.NewDataFlowTester(t, "azure", azure)
+
+	taskData := &tasks.AzureTaskData{
+		Options: &tasks.AzureOptions{
+			ConnectionId: 1,
+			Project:      "test",
+			RepositoryId: "5dc348ab-98a9-4c49-95da-b70b24a62932",
+			AzureTransformationRule: &models.AzureTransformationRule{
+				DeploymentPattern: "(?i)deploy",
+			},
+		},
+	}
+
+	// import raw data table
+	dataflowTester.ImportCsvIntoRawTable("./raw_tables/_raw_azure_api_builds.csv", "_raw_azure_api_builds")
+	dataflowTester.ImportCsvIntoRawTable("./raw_tables/_raw_azure_api_timelines.csv", "_raw_azure_api_timelines")
+
+	// verify extraction
+	dataflowTester.FlushTabler(&models.AzureBuild{})
+	dataflowTester.FlushTabler(&models.AzureTimelineRecord{})
+	dataflowTester.Subtask(tasks.ExtractApiBuildsMeta, taskData)
+	dataflowTester.Subtask(tasks.ExtractApiTimelinesMeta, taskData)
+	dataflowTester.VerifyTable(
+		models.AzureBuild{},
+		"./snapshot_tables/_tool_azure_builds.csv",
+		e2ehelper.ColumnWithRawData(
+			"connection_id",
+			"azure_id",
+			"repository_id",
+			"definition_id",
+			"definition_name",
+			"build_number",
+			"status",
+			"result",
+			"reason",
+			"source_branch",
+			"source_version",
+			"queue_time",
+			"start_time",
+			"finish_time",
+			"url",
+		),
+	)
+	dataflowTester.VerifyTable(
+		models.AzureTimelineRecord{},
+		"./snapshot_tables/_tool_azure_timeline_records.csv",
+		e2ehelper.ColumnWithRawData(
+			"connection_id",
+			"record_id",
+			"build_id",
+			"parent_id",
+			"type",
+			"name",
+			"stage_name",
+			"state",
+			"result",
+			"attempt",
+			"start_time",
+			"finish_time",
+		),
+	)
+
+	// verify conversion
+	dataflowTester.FlushTabler(&devops.CICDPipeline{})
+	dataflowTester.FlushTabler(&devops.CiCDPipelineCommit{})
+	dataflowTester.FlushTabler(&devops.CICDTask{})
+	dataflowTester.Subtask(tasks.ConvertBuildsMeta, taskData)
+	dataflowTester.Subtask(tasks.ConvertTimelinesMeta, taskData)
+	dataflowTester.VerifyTable(
+		devops.CICDPipeline{},
+		"./snapshot_tables/cicd_pipelines.csv",
+		e2ehelper.ColumnWithRawData(
+			"id",
+			"name",
+			"result",
+			"status",
+			"type",
+			"duration_sec",
+			"environment",
+			"created_date",
+			"finished_date",
+			"cicd_scope_id",
+		),
+	)
+	dataflowTester.VerifyTable(
+		devops.CiCDPipelineCommit{},
+		"./snapshot_tables/cicd_pipeline_commits.csv",
+		e2ehelper.ColumnWithRawData(
+			"pipeline_id",
+			"commit_sha",
+			"branch",
+			"repo_id",
+		),
+	)
+	dataflowTester.VerifyTable(
+		devops.CICDTask{},
+		"./snapshot_tables/cicd_tasks.csv",
+		e2ehelper.ColumnWithRawData(
+			"id",
+			"name",
+			"pipeline_id",
+			"result",
+			"status",
+			"type",
+			"environment",
+			"duration_sec",
+			"started_date",
+			"finished_date",
+			"cicd_scope_id",
+		),
+	)
+}
@@ -0,0 +1,129 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package e2e
+
+import (
+	"testing"
+
+	"github.com/apache/incubator-devlake/helpers/e2ehelper"
+	"github.com/apache/incubator-devlake/models/domainlayer/code"
+	"github.com/apache/incubator-devlake/plugins/azure/impl"
+	"github.com/apache/incubator-devlake/plugins/azure/models"
+	"github.com/apache/incubator-devlake/plugins/azure/tasks"
+)
+
+func TestAzurePullRequestDataFlow(t *testing.T) {
+	var azure impl.Azure
+	dataflowTester := e2ehelper.NewDataFlowTester(t, "azure", azure)
+
+	taskData := &tasks.AzureTaskData{
+		Options: &tasks.AzureOptions{
+			ConnectionId:            1,
+			Project:                 "test",
+			RepositoryId:            "5dc348ab-98a9-4c49-95da-b70b24a62932",
+			AzureTransformationRule: new(models.AzureTransformationRule),
+		},
+	}
+
+	// import raw data table
+	dataflowTester.ImportCsvIntoRawTable("./raw_tables/_raw_azure_api_pull_requests.csv", "_raw_azure_api_pull_requests")
+
+	// verify extraction
+	dataflowTester.FlushTabler(&models.AzurePullRequest{})
+	dataflowTester.FlushTabler(&models.AzurePrReviewer{})
+	dataflowTester.FlushTabler(&models.AzureAccount{})
+	dataflowTester.Subtask(tasks.ExtractApiPullRequestsMeta, taskData)
+	dataflowTester.VerifyTable(
+		models.AzurePullRequest{},
+		"./snapshot_tables/_tool_azure_pull_requests.csv",
+		e2ehelper.ColumnWithRawData(
+			"connection_id",
+			"azure_id",
+			"repository_id",
+			"title",
+			"description",
+			"status",
+			"merge_status",
+			"is_draft",
+			"created_by_id",
+			"created_by_name",
+			"creation_date",
+			"closed_date",
+			"source_ref_name",
+			"target_ref_name",
+			"last_merge_source_commit",
+			"last_merge_target_commit",
+			"last_merge_commit",
+			"url",
+		),
+	)
+	dataflowTester.VerifyTable(
+		models.AzurePrReviewer{},
+		"./snapshot_tables/_tool_azure_pull_request_reviewers.csv",
+		e2ehelper.ColumnWithRawData(
+			"connection_id",
+			"pull_request_id",
+			"reviewer_id",
+			"display_name",
+			"unique_name",
+			"vote",
+			"is_required",
+			"has_declined",
+		),
+	)
+	dataflowTester.VerifyTable(
+		models.AzureAccount{},
+		"./snapshot_tables/_tool_azure_accounts_in_pr.csv",
+		e2ehelper.ColumnWithRawData(
+			"connection_id",
+			"account_id",
+			"display_name",
+			"unique_name",
+			"image_url",
+		),
+	)
+
+	// verify conversion
+	dataflowTester.ImportCsvIntoTabler("./snapshot_tables/_tool_azure_repos.csv", &models.AzureRepo{})
+	dataflowTester.FlushTabler(&code.PullRequest{})
+	dataflowTester.Subtask(tasks.ConvertPullRequestsMeta, taskData)
+	dataflowTester.VerifyTable(
+		code.PullRequest{},
+		"./snapshot_tables/pull_requests.csv",
+		e2ehelper.ColumnWithRawData(
+			"id",
+			"base_repo_id",
+			"head_repo_id",
+			"status",
+			"title",
+			"description",
+			"url",
+			"author_name",
+			"author_id",
+			"pull_request_key",
+			"created_date",
+			"merged_date",
+			"closed_date",
+			"merge_commit_sha",
+			"head_ref",
+			"base_ref",
+			"base_commit_sha",
+			"head_commit_sha",
+		),
+	)
+}
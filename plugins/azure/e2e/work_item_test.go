@@ -0,0 +1,120 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package e2e
+
+import (
+	"testing"
+
+	"github.com/apache/incubator-devlake/helpers/e2ehelper"
+	"github.com/apache/incubator-devlake/models/domainlayer/ticket"
+	"github.com/apache/incubator-devlake/plugins/azure/impl"
+	"github.com/apache/incubator-devlake/plugins/azure/models"
+	"github.com/apache/incubator-devlake/plugins/azure/tasks"
+)
+
+func TestAzureWorkItemDataFlow(t *testing.T) {
+	var azure impl.Azure
+	dataflowTester := e2ehelper.NewDataFlowTester(t, "azure", azure)
+
+	taskData := &tasks.AzureTaskData{
+		Options: &tasks.AzureOptions{
+			ConnectionId:            1,
+			Project:                 "test",
+			RepositoryId:            "5dc348ab-98a9-4c49-95da-b70b24a62932",
+			AzureTransformationRule: new(models.AzureTransformationRule),
+		},
+	}
+
+	// import raw data table
+	dataflowTester.ImportCsvIntoRawTable("./raw_tables/_raw_azure_api_work_items.csv", "_raw_azure_api_work_items")
+
+	// verify extraction
+	dataflowTester.FlushTabler(&models.AzureWorkItem{})
+	dataflowTester.FlushTabler(&models.AzureAccount{})
+	dataflowTester.Subtask(tasks.ExtractApiWorkItemsMeta, taskData)
+	dataflowTester.VerifyTable(
+		models.AzureWorkItem{},
+		"./snapshot_tables/_tool_azure_work_items.csv",
+		e2ehelper.ColumnWithRawData(
+			"connection_id",
+			"azure_id",
+			"project_name",
+			"rev",
+			"title",
+			"type",
+			"state",
+			"reason",
+			"area_path",
+			"iteration_path",
+			"priority",
+			"severity",
+			"story_points",
+			"parent_id",
+			"assigned_to_id",
+			"assigned_to_name",
+			"created_by_id",
+			"created_by_name",
+			"created_date",
+			"changed_date",
+			"resolved_date",
+			"closed_date",
+			"url",
+		),
+	)
+
+	// verify conversion
+	dataflowTester.FlushTabler(&ticket.Issue{})
+	dataflowTester.FlushTabler(&ticket.BoardIssue{})
+	dataflowTester.Subtask(tasks.ConvertWorkItemsMeta, taskData)
+	dataflowTester.VerifyTable(
+		ticket.Issue{},
+		"./snapshot_tables/issues.csv",
+		e2ehelper.ColumnWithRawData(
+			"id",
+			"url",
+			"issue_key",
+			"title",
+			"type",
+			"original_type",
+			"status",
+			"original_status",
+			"story_point",
+			"resolution_date",
+			"created_date",
+			"updated_date",
+			"lead_time_minutes",
+			"parent_issue_id",
+			"priority",
+			"severity",
+			"component",
+			"creator_id",
+			"creator_name",
+			"assignee_id",
+			"assignee_name",
+			"original_project",
+		),
+	)
+	dataflowTester.VerifyTable(
+		ticket.BoardIssue{},
+		"./snapshot_tables/board_issues.csv",
+		e2ehelper.ColumnWithRawData(
+			"board_id",
+			"issue_id",
+		),
+	)
+}
@@ -19,6 +19,7 @@ package impl
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/apache/incubator-devlake/errors"
 	"github.com/apache/incubator-devlake/plugins/azure/api"
@@ -38,14 +39,29 @@ var _ core.PluginApi = (*Azure)(nil)
 var _ core.PluginModel = (*Azure)(nil)
 var _ core.CloseablePluginTask = (*Azure)(nil)
 var _ core.PluginMigration = (*Azure)(nil)
+var _ core.PluginBlueprintV100 = (*Azure)(nil)
+var _ core.DataSourcePluginBlueprintV200 = (*Azure)(nil)
+var _ core.PluginSource = (*Azure)(nil)
 
 // PluginEntry exports for Framework to search and load
 var PluginEntry Azure //nolint
 
 type Azure struct{}
 
+func (plugin Azure) Connection() interface{} {
+	return &models.AzureConnection{}
+}
+
+func (plugin Azure) Scope() interface{} {
+	return &models.AzureRepo{}
+}
+
+func (plugin Azure) TransformationRule() interface{} {
+	return &models.AzureTransformationRule{}
+}
+
 func (plugin Azure) Description() string {
-	return "collect some Azure data"
+	return "To collect and enrich data from Azure DevOps"
 }
 
 func (plugin Azure) Init(basicRes core.BasicRes) errors.Error {
@@ -55,10 +71,20 @@ func (plugin Azure) Init(basicRes core.BasicRes) errors.Error {
 
 func (plugin Azure) GetTablesInfo() []dal.Tabler {
 	return []dal.Tabler{
+		&models.AzureAccount{},
 		&models.AzureBuild{},
 		&models.AzureBuildDefinition{},
+		&models.AzureCommit{},
 		&models.AzureConnection{},
+		&models.AzurePrComment{},
+		&models.AzurePrCommit{},
+		&models.AzurePrReviewer{},
+		&models.AzurePullRequest{},
 		&models.AzureRepo{},
+		&models.AzureRepoCommit{},
+		&models.AzureTimelineRecord{},
+		&models.AzureTransformationRule{},
+		&models.AzureWorkItem{},
 	}
 }
 
@@ -68,6 +94,37 @@ func (plugin Azure) SubTaskMetas() []core.SubTaskMeta {
 		tasks.ExtractApiRepoMeta,
 		tasks.CollectApiBuildDefinitionMeta,
 		tasks.ExtractApiBuildDefinitionMeta,
+
+		tasks.CollectApiPullRequestsMeta,
+		tasks.ExtractApiPullRequestsMeta,
+
+		tasks.CollectApiPrThreadsMeta,
+		tasks.ExtractApiPrThreadsMeta,
+
+		tasks.CollectApiPrCommitsMeta,
+		tasks.ExtractApiPrCommitsMeta,
+
+		tasks.CollectApiCommitsMeta,
+		tasks.ExtractApiCommitsMeta,
+
+		tasks.CollectApiBuildsMeta,
+		tasks.ExtractApiBuildsMeta,
+
+		tasks.CollectApiTimelinesMeta,
+		tasks.ExtractApiTimelinesMeta,
+
+		tasks.CollectApiWorkItemsMeta,
+		tasks.ExtractApiWorkItemsMeta,
+
+		tasks.ConvertRepoMeta,
+		tasks.ConvertAccountsMeta,
+		tasks.ConvertPullRequestsMeta,
+		tasks.ConvertPrCommentsMeta,
+		tasks.ConvertPrCommitsMeta,
+		tasks.ConvertCommitsMeta,
+		tasks.ConvertBuildsMeta,
+		tasks.ConvertTimelinesMeta,
+		tasks.ConvertWorkItemsMeta,
 	}
 }
 
@@ -76,8 +133,9 @@ func (plugin Azure) PrepareTaskData(taskCtx core.TaskContext, options map[string
 	if err != nil {
 		return nil, err
 	}
-	if op.ConnectionId == 0 {
-		return nil, errors.Default.New("connectionId is invalid")
+	err = EnrichOptions(taskCtx, op)
+	if err != nil {
+		return nil, err
 	}
 
 	connection := &models.AzureConnection{}
@@ -85,9 +143,6 @@ func (plugin Azure) PrepareTaskData(taskCtx core.TaskContext, options map[string
 		taskCtx,
 		nil,
 	)
-	if err != nil {
-		return nil, err
-	}
 	err = connectionHelper.FirstById(connection, op.ConnectionId)
 	if err != nil {
 		return nil, err
@@ -97,11 +152,20 @@ func (plugin Azure) PrepareTaskData(taskCtx core.TaskContext, options map[string
 	if err != nil {
 		return nil, err
 	}
-	return &tasks.AzureTaskData{
+	taskData := &tasks.AzureTaskData{
 		Options:    op,
 		ApiClient:  apiClient,
 		Connection: connection,
-	}, nil
+	}
+	if op.CreatedDateAfter != "" {
+		var createdDateAfter time.Time
+		createdDateAfter, err = errors.Convert01(time.Parse(time.RFC3339, op.CreatedDateAfter))
+		if err != nil {
+			return nil, errors.BadInput.Wrap(err, "invalid value for `createdDateAfter`")
+		}
+		taskData.CreatedDateAfter = &createdDateAfter
+	}
+	return taskData, nil
 }
 
 // PkgPath information lost when compiled as plugin(.so)
@@ -123,6 +187,25 @@ func (plugin Azure) ApiResources() map[string]map[string]core.ApiResourceHandler
 			"PATCH":  api.PatchConnection,
 			"DELETE": api.DeleteConnection,
 		},
+		"connections/:connectionId/scopes/:repoId": {
+			"GET":   api.GetScope,
+			"PATCH": api.UpdateScope,
+		},
+		"connections/:connectionId/scopes": {
+			"GET": api.GetScopeList,
+			"PUT": api.PutScope,
+		},
+		"transformation_rules": {
+			"POST": api.CreateTransformationRule,
+			"GET":  api.GetTransformationRuleList,
+		},
+		"transformation_rules/:id": {
+			"PATCH": api.UpdateTransformationRule,
+			"GET":   api.GetTransformationRule,
+		},
+		"connections/:connectionId/proxy/rest/*path": {
+			"GET": api.Proxy,
+		},
 	}
 }
 
@@ -130,6 +213,14 @@ func (plugin Azure) MigrationScripts() []core.MigrationScript {
 	return migrationscripts.All()
 }
 
+func (plugin Azure) MakePipelinePlan(connectionId uint64, scope []*core.BlueprintScopeV100) (core.PipelinePlan, errors.Error) {
+	return api.MakePipelinePlan(plugin.SubTaskMetas(), connectionId, scope)
+}
+
+func (plugin Azure) MakeDataSourcePipelinePlanV200(connectionId uint64, scopes []*core.BlueprintScopeV200, syncPolicy core.BlueprintSyncPolicy) (pp core.PipelinePlan, sc []core.Scope, err errors.Error) {
+	return api.MakeDataSourcePipelinePlanV200(plugin.SubTaskMetas(), connectionId, scopes, &syncPolicy)
+}
+
 func (plugin Azure) Close(taskCtx core.TaskContext) errors.Error {
 	data, ok := taskCtx.GetData().(*tasks.AzureTaskData)
 	if !ok {
@@ -138,3 +229,28 @@ func (plugin Azure) Close(taskCtx core.TaskContext) errors.Error {
 	data.ApiClient.Release()
 	return nil
 }
+
+// EnrichOptions loads the repo and its transformation rule when they are not given in the options
+func EnrichOptions(taskCtx core.TaskContext, op *tasks.AzureOptions) errors.Error {
+	db := taskCtx.GetDal()
+	var repo models.AzureRepo
+	err := db.First(&repo, dal.Where("connection_id = ? AND azure_id = ?", op.ConnectionId, op.RepositoryId))
+	if err != nil && !db.IsErrorNotFound(err) {
+		return errors.Default.Wrap(err, fmt.Sprintf("fail to find repo %s", op.RepositoryId))
+	}
+	if op.TransformationRuleId == 0 {
+		op.TransformationRuleId = repo.TransformationRuleId
+	}
+	if op.AzureTransformationRule == nil && op.TransformationRuleId != 0 {
+		var transformationRule models.AzureTransformationRule
+		err = db.First(&transformationRule, dal.Where("id = ?", op.TransformationRuleId))
+		if err != nil && !db.IsErrorNotFound(err) {
+			return errors.BadInput.Wrap(err, "fail to get transformationRule")
+		}
+		op.AzureTransformationRule = &transformationRule
+	}
+	if op.AzureTransformationRule == nil {
+		op.AzureTransformationRule = new(models.AzureTransformationRule)
+	}
+	return nil
+}
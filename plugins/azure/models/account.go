@@ -17,23 +17,19 @@ limitations under the License.
 
 package models
 
-import git "github.com/libgit2/git2go/v33"
-
-type DiffLines []git.DiffLine
-
-// Len return length of diffLines
-func (diffLines DiffLines) Len() int {
-	return len(diffLines)
-}
-
-// Less function
-func (diffLines DiffLines) Less(i, j int) bool {
-	return diffLines[i].OldLineno > diffLines[j].OldLineno
+import (
+	"github.com/apache/incubator-devlake/models/common"
+)
+
+type AzureAccount struct {
+	ConnectionId uint64 `gorm:"primaryKey"`
+	AccountId    string `gorm:"primaryKey;type:varchar(255)"`
+	DisplayName  string `gorm:"type:varchar(255)"`
+	UniqueName   string `gorm:"type:varchar(255)"`
+	ImageUrl     string `gorm:"type:varchar(255)"`
+	common.NoPKModel
 }
 
-// Swap function
-func (diffLines DiffLines) Swap(i, j int) {
-	temp := diffLines[i]
-	diffLines[i] = diffLines[j]
-	diffLines[j] = temp
+func (AzureAccount) TableName() string {
+	return "_tool_azure_accounts"
 }
@@ -0,0 +1,62 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migrationscripts
+
+import (
+	"github.com/apache/incubator-devlake/errors"
+	"github.com/apache/incubator-devlake/helpers/migrationhelper"
+	"github.com/apache/incubator-devlake/plugins/azure/models/migrationscripts/archived"
+	"github.com/apache/incubator-devlake/plugins/core"
+)
+
+type azureRepo20230107 struct {
+	ProjectName          string `gorm:"type:varchar(255)"`
+	TransformationRuleId uint64
+}
+
+func (azureRepo20230107) TableName() string {
+	return "_tool_azure_repos"
+}
+
+type addAzureDevopsTables20230107 struct{}
+
+func (*addAzureDevopsTables20230107) Up(basicRes core.BasicRes) errors.Error {
+	return migrationhelper.AutoMigrateTables(
+		basicRes,
+		&azureRepo20230107{},
+		&archived.AzureTransformationRule{},
+		&archived.AzureBuild{},
+		&archived.AzureTimelineRecord{},
+		&archived.AzurePullRequest{},
+		&archived.AzurePrReviewer{},
+		&archived.AzurePrComment{},
+		&archived.AzurePrCommit{},
+		&archived.AzureCommit{},
+		&archived.AzureRepoCommit{},
+		&archived.AzureWorkItem{},
+		&archived.AzureAccount{},
+	)
+}
+
+func (*addAzureDevopsTables20230107) Version() uint64 {
+	return 20230107153012
+}
+
+func (*addAzureDevopsTables20230107) Name() string {
+	return "add azure devops builds, timelines, pull requests, commits, work items, accounts and transformation rules"
+}
@@ -25,5 +25,6 @@ import (
 func All() []core.MigrationScript {
 	return []core.MigrationScript{
 		new(addInitTables20220825),
+		new(addAzureDevopsTables20230107),
 	}
 }
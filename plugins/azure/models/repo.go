@@ -22,18 +22,20 @@ import (
 )
 
 type AzureRepo struct {
-	ConnectionId  uint64 `gorm:"primaryKey"`
-	AzureId       string `gorm:"primaryKey;type:varchar(255)" json:"id"`
-	Name          string `gorm:"type:varchar(255)" json:"name"`
-	Url           string `gorm:"type:varchar(255)" json:"url"`
-	ProjectId     string `gorm:"type:varchar(255);index"`
-	DefaultBranch string `json:"defaultBranch"`
-	Size          int    `json:"size"`
-	RemoteURL     string `json:"remoteUrl"`
-	SshUrl        string `gorm:"type:varchar(255)" json:"sshUrl"`
-	WebUrl        string `gorm:"type:varchar(255)" json:"webUrl"`
-	IsDisabled    bool   `json:"isDisabled"`
-	common.NoPKModel
+	ConnectionId         uint64 `json:"connectionId" mapstructure:"connectionId" gorm:"primaryKey"`
+	AzureId              string `json:"id" mapstructure:"id" gorm:"primaryKey;type:varchar(255)"`
+	Name                 string `json:"name" mapstructure:"name" gorm:"type:varchar(255)"`
+	Url                  string `json:"url" mapstructure:"url" gorm:"type:varchar(255)"`
+	ProjectId            string `json:"projectId" mapstructure:"projectId" gorm:"type:varchar(255);index"`
+	ProjectName          string `json:"projectName" mapstructure:"projectName" gorm:"type:varchar(255)"`
+	DefaultBranch        string `json:"defaultBranch" mapstructure:"defaultBranch"`
+	Size                 int    `json:"size" mapstructure:"size"`
+	RemoteURL            string `json:"remoteUrl" mapstructure:"remoteUrl"`
+	SshUrl               string `json:"sshUrl" mapstructure:"sshUrl" gorm:"type:varchar(255)"`
+	WebUrl               string `json:"webUrl" mapstructure:"webUrl" gorm:"type:varchar(255)"`
+	IsDisabled           bool   `json:"isDisabled" mapstructure:"isDisabled"`
+	TransformationRuleId uint64 `json:"transformationRuleId,omitempty" mapstructure:"transformationRuleId,omitempty"`
+	common.NoPKModel     `json:"-" mapstructure:"-"`
 }
 
 func (AzureRepo) TableName() string {
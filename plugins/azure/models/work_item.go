@@ -0,0 +1,56 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import (
+	"time"
+
+	"github.com/apache/incubator-devlake/models/common"
+)
+
+type AzureWorkItem struct {
+	ConnectionId   uint64 `gorm:"primaryKey"`
+	AzureId        int    `gorm:"primaryKey"`
+	ProjectName    string `gorm:"type:varchar(255);index"`
+	Rev            int
+	Title          string
+	Description    string
+	Type           string `gorm:"type:varchar(100)"`
+	State          string `gorm:"type:varchar(100)"`
+	Reason         string `gorm:"type:varchar(255)"`
+	AreaPath       string `gorm:"type:varchar(255)"`
+	IterationPath  string `gorm:"type:varchar(255)"`
+	Priority       string `gorm:"type:varchar(100)"`
+	Severity       string `gorm:"type:varchar(100)"`
+	StoryPoints    float64
+	ParentId       int
+	AssignedToId   string `gorm:"type:varchar(255)"`
+	AssignedToName string `gorm:"type:varchar(255)"`
+	CreatedById    string `gorm:"type:varchar(255)"`
+	CreatedByName  string `gorm:"type:varchar(255)"`
+	CreatedDate    *time.Time
+	ChangedDate    *time.Time
+	ResolvedDate   *time.Time
+	ClosedDate     *time.Time
+	Url            string `gorm:"type:varchar(255)"`
+	common.NoPKModel
+}
+
+func (AzureWorkItem) TableName() string {
+	return "_tool_azure_work_items"
+}
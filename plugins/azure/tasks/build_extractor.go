@@ -0,0 +1,99 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/apache/incubator-devlake/errors"
+	"github.com/apache/incubator-devlake/plugins/azure/models"
+	"github.com/apache/incubator-devlake/plugins/core"
+	"github.com/apache/incubator-devlake/plugins/helper"
+)
+
+type AzureApiBuild struct {
+	Id          int    `json:"id"`
+	BuildNumber string `json:"buildNumber"`
+	Status      string `json:"status"`
+	Result      string `json:"result"`
+	Reason      string `json:"reason"`
+	Definition  struct {
+		Id   int    `json:"id"`
+		Name string `json:"name"`
+	} `json:"definition"`
+	Repository struct {
+		Id string `json:"id"`
+	} `json:"repository"`
+	SourceBranch  string     `json:"sourceBranch"`
+	SourceVersion string     `json:"sourceVersion"`
+	QueueTime     *time.Time `json:"queueTime"`
+	StartTime     *time.Time `json:"startTime"`
+	FinishTime    *time.Time `json:"finishTime"`
+	Links         struct {
+		Web struct {
+			Href string `json:"href"`
+		} `json:"web"`
+	} `json:"_links"`
+}
+
+var ExtractApiBuildsMeta = core.SubTaskMeta{
+	Name:             "extractApiBuilds",
+	EntryPoint:       ExtractApiBuilds,
+	EnabledByDefault: true,
+	Description:      "Extract raw builds data into tool layer table azure_builds",
+	DomainTypes:      []string{core.DOMAIN_TYPE_CICD},
+}
+
+func ExtractApiBuilds(taskCtx core.SubTaskContext) errors.Error {
+	rawDataSubTaskArgs, data := CreateRawDataSubTaskArgs(taskCtx, RAW_BUILD_TABLE)
+	extractor, err := helper.NewApiExtractor(helper.ApiExtractorArgs{
+		RawDataSubTaskArgs: *rawDataSubTaskArgs,
+		Extract: func(row *helper.RawData) ([]interface{}, errors.Error) {
+			apiBuild := &AzureApiBuild{}
+			err := errors.Convert(json.Unmarshal(row.Data, apiBuild))
+			if err != nil {
+				return nil, err
+			}
+			return []interface{}{
+				&models.AzureBuild{
+					ConnectionId:   data.Options.ConnectionId,
+					AzureId:        apiBuild.Id,
+					RepositoryId:   apiBuild.Repository.Id,
+					DefinitionId:   apiBuild.Definition.Id,
+					DefinitionName: apiBuild.Definition.Name,
+					BuildNumber:    apiBuild.BuildNumber,
+					Status:         apiBuild.Status,
+					Result:         apiBuild.Result,
+					Reason:         apiBuild.Reason,
+					SourceBranch:   apiBuild.SourceBranch,
+					SourceVersion:  apiBuild.SourceVersion,
+					QueueTime:      apiBuild.QueueTime,
+					StartTime:      apiBuild.StartTime,
+					FinishTime:     apiBuild.FinishTime,
+					Url:            apiBuild.Links.Web.Href,
+				},
+			}, nil
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	return extractor.Execute()
+}
@@ -0,0 +1,72 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/apache/incubator-devlake/errors"
+	"github.com/apache/incubator-devlake/plugins/core"
+	"github.com/apache/incubator-devlake/plugins/helper"
+)
+
+const RAW_COMMIT_TABLE = "azure_api_commits"
+
+var CollectApiCommitsMeta = core.SubTaskMeta{
+	Name:             "collectApiCommits",
+	EntryPoint:       CollectApiCommits,
+	EnabledByDefault: false,
+	Description:      "Collect commits data from Azure api",
+	DomainTypes:      []string{core.DOMAIN_TYPE_CODE},
+}
+
+func CollectApiCommits(taskCtx core.SubTaskContext) errors.Error {
+	rawDataSubTaskArgs, data := CreateRawDataSubTaskArgs(taskCtx, RAW_COMMIT_TABLE)
+	collectorWithState, err := helper.NewApiCollectorWithState(*rawDataSubTaskArgs, data.CreatedDateAfter)
+	if err != nil {
+		return err
+	}
+
+	incremental := collectorWithState.IsIncremental()
+	err = collectorWithState.InitCollector(helper.ApiCollectorArgs{
+		ApiClient:   data.ApiClient,
+		PageSize:    100,
+		Incremental: incremental,
+		UrlTemplate: "{{ .Params.Project }}/_apis/git/repositories/{{ .Params.RepositoryId }}/commits",
+		Query: func(reqData *helper.RequestData) (url.Values, errors.Error) {
+			query := url.Values{}
+			query.Set("api-version", "7.1-preview.1")
+			if incremental {
+				query.Set("searchCriteria.fromDate", collectorWithState.LatestState.LatestSuccessStart.Format(time.RFC3339))
+			} else if data.CreatedDateAfter != nil {
+				query.Set("searchCriteria.fromDate", data.CreatedDateAfter.Format(time.RFC3339))
+			}
+			query.Set("searchCriteria.$top", fmt.Sprintf("%v", reqData.Pager.Size))
+			query.Set("searchCriteria.$skip", fmt.Sprintf("%v", reqData.Pager.Skip))
+			return query, nil
+		},
+		ResponseParser: ParseRawMessageFromValue,
+	})
+	if err != nil {
+		return err
+	}
+
+	return collectorWithState.Execute()
+}
@@ -0,0 +1,91 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"reflect"
+
+	"github.com/apache/incubator-devlake/errors"
+	"github.com/apache/incubator-devlake/models/domainlayer/code"
+	"github.com/apache/incubator-devlake/models/domainlayer/didgen"
+	"github.com/apache/incubator-devlake/plugins/azure/models"
+	"github.com/apache/incubator-devlake/plugins/core"
+	"github.com/apache/incubator-devlake/plugins/core/dal"
+	"github.com/apache/incubator-devlake/plugins/helper"
+)
+
+var ConvertCommitsMeta = core.SubTaskMeta{
+	Name:             "convertApiCommits",
+	EntryPoint:       ConvertApiCommits,
+	EnabledByDefault: false,
+	Description:      "Convert tool layer table azure_commits into domain layer table commits and repo_commits",
+	DomainTypes:      []string{core.DOMAIN_TYPE_CODE},
+}
+
+func ConvertApiCommits(taskCtx core.SubTaskContext) errors.Error {
+	rawDataSubTaskArgs, data := CreateRawDataSubTaskArgs(taskCtx, RAW_COMMIT_TABLE)
+	db := taskCtx.GetDal()
+	cursor, err := db.Cursor(
+		dal.Select("c.*"),
+		dal.From("_tool_azure_commits c"),
+		dal.Join(`left join _tool_azure_repo_commits rc on (
+			rc.commit_sha = c.sha
+		)`),
+		dal.Where("rc.repository_id = ? AND rc.connection_id = ?", data.Options.RepositoryId, data.Options.ConnectionId),
+	)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close()
+
+	domainRepoId := didgen.NewDomainIdGenerator(&models.AzureRepo{}).Generate(data.Options.ConnectionId, data.Options.RepositoryId)
+
+	converter, err := helper.NewDataConverter(helper.DataConverterArgs{
+		InputRowType:       reflect.TypeOf(models.AzureCommit{}),
+		Input:              cursor,
+		RawDataSubTaskArgs: *rawDataSubTaskArgs,
+		Convert: func(inputRow interface{}) ([]interface{}, errors.Error) {
+			commit := inputRow.(*models.AzureCommit)
+			domainCommit := &code.Commit{
+				Sha:            commit.Sha,
+				Message:        commit.Message,
+				AuthorId:       commit.AuthorEmail,
+				AuthorName:     commit.AuthorName,
+				AuthorEmail:    commit.AuthorEmail,
+				AuthoredDate:   commit.AuthoredDate,
+				CommitterId:    commit.CommitterEmail,
+				CommitterName:  commit.CommitterName,
+				CommitterEmail: commit.CommitterEmail,
+				CommittedDate:  commit.CommittedDate,
+			}
+			repoCommit := &code.RepoCommit{
+				RepoId:    domainRepoId,
+				CommitSha: commit.Sha,
+			}
+			return []interface{}{
+				domainCommit,
+				repoCommit,
+			}, nil
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	return converter.Execute()
+}
@@ -0,0 +1,87 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"encoding/json"
+
+	"github.com/apache/incubator-devlake/errors"
+	"github.com/apache/incubator-devlake/plugins/azure/models"
+	"github.com/apache/incubator-devlake/plugins/core"
+	"github.com/apache/incubator-devlake/plugins/helper"
+)
+
+type AzureApiCommit struct {
+	CommitId     string          `json:"commitId"`
+	Author       AzureApiGitUser `json:"author"`
+	Committer    AzureApiGitUser `json:"committer"`
+	Comment      string          `json:"comment"`
+	ChangeCounts struct {
+		Add    int `json:"Add"`
+		Edit   int `json:"Edit"`
+		Delete int `json:"Delete"`
+	} `json:"changeCounts"`
+	Url string `json:"url"`
+}
+
+var ExtractApiCommitsMeta = core.SubTaskMeta{
+	Name:             "extractApiCommits",
+	EntryPoint:       ExtractApiCommits,
+	EnabledByDefault: false,
+	Description:      "Extract raw commits data into tool layer table azure_commits and azure_repo_commits",
+	DomainTypes:      []string{core.DOMAIN_TYPE_CODE},
+}
+
+func ExtractApiCommits(taskCtx core.SubTaskContext) errors.Error {
+	rawDataSubTaskArgs, data := CreateRawDataSubTaskArgs(taskCtx, RAW_COMMIT_TABLE)
+	extractor, err := helper.NewApiExtractor(helper.ApiExtractorArgs{
+		RawDataSubTaskArgs: *rawDataSubTaskArgs,
+		Extract: func(row *helper.RawData) ([]interface{}, errors.Error) {
+			apiCommit := &AzureApiCommit{}
+			err := errors.Convert(json.Unmarshal(row.Data, apiCommit))
+			if err != nil {
+				return nil, err
+			}
+			commit := &models.AzureCommit{
+				Sha:            apiCommit.CommitId,
+				Message:        apiCommit.Comment,
+				AuthorName:     apiCommit.Author.Name,
+				AuthorEmail:    apiCommit.Author.Email,
+				AuthoredDate:   apiCommit.Author.Date,
+				CommitterName:  apiCommit.Committer.Name,
+				CommitterEmail: apiCommit.Committer.Email,
+				CommittedDate:  apiCommit.Committer.Date,
+				Additions:      apiCommit.ChangeCounts.Add,
+				Edits:          apiCommit.ChangeCounts.Edit,
+				Deletions:      apiCommit.ChangeCounts.Delete,
+				Url:            apiCommit.Url,
+			}
+			repoCommit := &models.AzureRepoCommit{
+				ConnectionId: data.Options.ConnectionId,
+				RepositoryId: data.Options.RepositoryId,
+				CommitSha:    apiCommit.CommitId,
+			}
+			return []interface{}{commit, repoCommit}, nil
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	return extractor.Execute()
+}
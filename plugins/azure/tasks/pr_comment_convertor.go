@@ -0,0 +1,122 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/apache/incubator-devlake/errors"
+	"github.com/apache/incubator-devlake/models/domainlayer"
+	"github.com/apache/incubator-devlake/models/domainlayer/code"
+	"github.com/apache/incubator-devlake/models/domainlayer/didgen"
+	"github.com/apache/incubator-devlake/plugins/azure/models"
+	"github.com/apache/incubator-devlake/plugins/core"
+	"github.com/apache/incubator-devlake/plugins/core/dal"
+	"github.com/apache/incubator-devlake/plugins/helper"
+)
+
+// THREAD_TYPE_VOTE_UPDATE is the type of threads recording votes of reviewers
+const THREAD_TYPE_VOTE_UPDATE = "VoteUpdate"
+
+var ConvertPrCommentsMeta = core.SubTaskMeta{
+	Name:             "convertPullRequestComments",
+	EntryPoint:       ConvertPullRequestComments,
+	EnabledByDefault: true,
+	Description:      "Convert tool layer table azure_pull_request_comments into domain layer table pull_request_comments",
+	DomainTypes:      []string{core.DOMAIN_TYPE_CODE_REVIEW},
+}
+
+func ConvertPullRequestComments(taskCtx core.SubTaskContext) errors.Error {
+	rawDataSubTaskArgs, data := CreateRawDataSubTaskArgs(taskCtx, RAW_PULL_REQUEST_THREAD_TABLE)
+	db := taskCtx.GetDal()
+	cursor, err := db.Cursor(
+		dal.Select("c.*"),
+		dal.From("_tool_azure_pull_request_comments c"),
+		dal.Join(`left join _tool_azure_pull_requests pr on (
+			pr.azure_id = c.pull_request_id AND pr.connection_id = c.connection_id
+		)`),
+		dal.Where("pr.repository_id = ? AND pr.connection_id = ?", data.Options.RepositoryId, data.Options.ConnectionId),
+	)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close()
+
+	commentIdGen := didgen.NewDomainIdGenerator(&models.AzurePrComment{})
+	prIdGen := didgen.NewDomainIdGenerator(&models.AzurePullRequest{})
+	accountIdGen := didgen.NewDomainIdGenerator(&models.AzureAccount{})
+
+	converter, err := helper.NewDataConverter(helper.DataConverterArgs{
+		InputRowType:       reflect.TypeOf(models.AzurePrComment{}),
+		Input:              cursor,
+		RawDataSubTaskArgs: *rawDataSubTaskArgs,
+		Convert: func(inputRow interface{}) ([]interface{}, errors.Error) {
+			comment := inputRow.(*models.AzurePrComment)
+			domainComment := &code.PullRequestComment{
+				DomainEntity: domainlayer.DomainEntity{
+					Id: commentIdGen.Generate(data.Options.ConnectionId, comment.PullRequestId, comment.ThreadId, comment.CommentId),
+				},
+				PullRequestId: prIdGen.Generate(data.Options.ConnectionId, comment.PullRequestId),
+				Body:          comment.Content,
+				CreatedDate:   comment.PublishedDate,
+				Position:      comment.Line,
+				ReviewId:      fmt.Sprintf("%d", comment.ThreadId),
+				Status:        comment.ThreadStatus,
+			}
+			if comment.AuthorId != "" {
+				domainComment.AccountId = accountIdGen.Generate(data.Options.ConnectionId, comment.AuthorId)
+			}
+			switch {
+			case comment.ThreadType == THREAD_TYPE_VOTE_UPDATE:
+				domainComment.Type = code.REVIEW
+				domainComment.Status = voteToReviewStatus(comment.Vote)
+			case comment.CommentType == models.COMMENT_TYPE_SYSTEM:
+				// other system comments are just activities of the pull request, like pushes and status changes
+				return nil, nil
+			case comment.FilePath != "":
+				domainComment.Type = code.DIFF_COMMENT
+			default:
+				domainComment.Type = code.NORMAL_COMMENT
+			}
+			return []interface{}{
+				domainComment,
+			}, nil
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	return converter.Execute()
+}
+
+func voteToReviewStatus(vote int) string {
+	switch vote {
+	case models.VOTE_APPROVED:
+		return "APPROVED"
+	case models.VOTE_APPROVED_WITH_SUGGESTION:
+		return "APPROVED_WITH_SUGGESTIONS"
+	case models.VOTE_WAITING_FOR_AUTHOR:
+		return "WAITING_FOR_AUTHOR"
+	case models.VOTE_REJECTED:
+		return "REJECTED"
+	default:
+		return "NO_VOTE"
+	}
+}
@@ -0,0 +1,121 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/apache/incubator-devlake/errors"
+	"github.com/apache/incubator-devlake/models/domainlayer"
+	"github.com/apache/incubator-devlake/models/domainlayer/code"
+	"github.com/apache/incubator-devlake/models/domainlayer/didgen"
+	"github.com/apache/incubator-devlake/plugins/azure/models"
+	"github.com/apache/incubator-devlake/plugins/core"
+	"github.com/apache/incubator-devlake/plugins/core/dal"
+	"github.com/apache/incubator-devlake/plugins/helper"
+)
+
+// statuses of azure pull requests
+const (
+	PR_STATUS_ACTIVE    = "active"
+	PR_STATUS_ABANDONED = "abandoned"
+	PR_STATUS_COMPLETED = "completed"
+)
+
+var ConvertPullRequestsMeta = core.SubTaskMeta{
+	Name:             "convertPullRequests",
+	EntryPoint:       ConvertPullRequests,
+	EnabledByDefault: true,
+	Description:      "Convert tool layer table azure_pull_requests into domain layer table pull_requests",
+	DomainTypes:      []string{core.DOMAIN_TYPE_CODE_REVIEW},
+}
+
+func ConvertPullRequests(taskCtx core.SubTaskContext) errors.Error {
+	rawDataSubTaskArgs, data := CreateRawDataSubTaskArgs(taskCtx, RAW_PULL_REQUEST_TABLE)
+	db := taskCtx.GetDal()
+	repo := &models.AzureRepo{}
+	err := db.First(repo, dal.Where("azure_id = ? AND connection_id = ?", data.Options.RepositoryId, data.Options.ConnectionId))
+	if err != nil {
+		return errors.Default.Wrap(err, fmt.Sprintf("fail to find repo %s", data.Options.RepositoryId))
+	}
+
+	cursor, err := db.Cursor(
+		dal.From(&models.AzurePullRequest{}),
+		dal.Where("repository_id = ? AND connection_id = ?", data.Options.RepositoryId, data.Options.ConnectionId),
+	)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close()
+
+	prIdGen := didgen.NewDomainIdGenerator(&models.AzurePullRequest{})
+	repoIdGen := didgen.NewDomainIdGenerator(&models.AzureRepo{})
+	accountIdGen := didgen.NewDomainIdGenerator(&models.AzureAccount{})
+	domainRepoId := repoIdGen.Generate(data.Options.ConnectionId, data.Options.RepositoryId)
+
+	converter, err := helper.NewDataConverter(helper.DataConverterArgs{
+		InputRowType:       reflect.TypeOf(models.AzurePullRequest{}),
+		Input:              cursor,
+		RawDataSubTaskArgs: *rawDataSubTaskArgs,
+		Convert: func(inputRow interface{}) ([]interface{}, errors.Error) {
+			pr := inputRow.(*models.AzurePullRequest)
+			domainPr := &code.PullRequest{
+				DomainEntity: domainlayer.DomainEntity{
+					Id: prIdGen.Generate(data.Options.ConnectionId, pr.AzureId),
+				},
+				BaseRepoId:     domainRepoId,
+				HeadRepoId:     domainRepoId,
+				Title:          pr.Title,
+				Description:    pr.Description,
+				Url:            fmt.Sprintf("%s/pullrequest/%d", repo.WebUrl, pr.AzureId),
+				AuthorName:     pr.CreatedByName,
+				PullRequestKey: pr.AzureId,
+				CreatedDate:    pr.CreationDate,
+				ClosedDate:     pr.ClosedDate,
+				MergeCommitSha: pr.LastMergeCommit,
+				HeadRef:        pr.SourceRefName,
+				BaseRef:        pr.TargetRefName,
+				HeadCommitSha:  pr.LastMergeSourceCommit,
+				BaseCommitSha:  pr.LastMergeTargetCommit,
+			}
+			if pr.CreatedById != "" {
+				domainPr.AuthorId = accountIdGen.Generate(data.Options.ConnectionId, pr.CreatedById)
+			}
+			switch pr.Status {
+			case PR_STATUS_ACTIVE:
+				domainPr.Status = "OPEN"
+			case PR_STATUS_ABANDONED:
+				domainPr.Status = "CLOSED"
+			case PR_STATUS_COMPLETED:
+				domainPr.Status = "MERGED"
+				domainPr.MergedDate = pr.ClosedDate
+			default:
+				domainPr.Status = pr.Status
+			}
+			return []interface{}{
+				domainPr,
+			}, nil
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	return converter.Execute()
+}
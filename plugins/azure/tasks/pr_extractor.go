@@ -0,0 +1,129 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/apache/incubator-devlake/errors"
+	"github.com/apache/incubator-devlake/plugins/azure/models"
+	"github.com/apache/incubator-devlake/plugins/core"
+	"github.com/apache/incubator-devlake/plugins/helper"
+)
+
+type AzureApiCommitRef struct {
+	CommitId string `json:"commitId"`
+}
+
+type AzureApiPullRequest struct {
+	PullRequestId int `json:"pullRequestId"`
+	Repository    struct {
+		Id string `json:"id"`
+	} `json:"repository"`
+	Status                string            `json:"status"`
+	CreatedBy             *AzureApiIdentity `json:"createdBy"`
+	CreationDate          time.Time         `json:"creationDate"`
+	ClosedDate            *time.Time        `json:"closedDate"`
+	Title                 string            `json:"title"`
+	Description           string            `json:"description"`
+	SourceRefName         string            `json:"sourceRefName"`
+	TargetRefName         string            `json:"targetRefName"`
+	MergeStatus           string            `json:"mergeStatus"`
+	IsDraft               bool              `json:"isDraft"`
+	LastMergeSourceCommit AzureApiCommitRef `json:"lastMergeSourceCommit"`
+	LastMergeTargetCommit AzureApiCommitRef `json:"lastMergeTargetCommit"`
+	LastMergeCommit       AzureApiCommitRef `json:"lastMergeCommit"`
+	Reviewers             []struct {
+		AzureApiIdentity
+		Vote        int  `json:"vote"`
+		IsRequired  bool `json:"isRequired"`
+		HasDeclined bool `json:"hasDeclined"`
+	} `json:"reviewers"`
+	Url string `json:"url"`
+}
+
+var ExtractApiPullRequestsMeta = core.SubTaskMeta{
+	Name:             "extractApiPullRequests",
+	EntryPoint:       ExtractApiPullRequests,
+	EnabledByDefault: true,
+	Description:      "Extract raw pull requests data into tool layer table azure_pull_requests and azure_pull_request_reviewers",
+	DomainTypes:      []string{core.DOMAIN_TYPE_CODE_REVIEW},
+}
+
+func ExtractApiPullRequests(taskCtx core.SubTaskContext) errors.Error {
+	rawDataSubTaskArgs, data := CreateRawDataSubTaskArgs(taskCtx, RAW_PULL_REQUEST_TABLE)
+	extractor, err := helper.NewApiExtractor(helper.ApiExtractorArgs{
+		RawDataSubTaskArgs: *rawDataSubTaskArgs,
+		Extract: func(row *helper.RawData) ([]interface{}, errors.Error) {
+			apiPr := &AzureApiPullRequest{}
+			err := errors.Convert(json.Unmarshal(row.Data, apiPr))
+			if err != nil {
+				return nil, err
+			}
+			results := make([]interface{}, 0, len(apiPr.Reviewers)*2+2)
+			pr := &models.AzurePullRequest{
+				ConnectionId:          data.Options.ConnectionId,
+				AzureId:               apiPr.PullRequestId,
+				RepositoryId:          apiPr.Repository.Id,
+				Title:                 apiPr.Title,
+				Description:           apiPr.Description,
+				Status:                apiPr.Status,
+				MergeStatus:           apiPr.MergeStatus,
+				IsDraft:               apiPr.IsDraft,
+				CreationDate:          apiPr.CreationDate,
+				ClosedDate:            apiPr.ClosedDate,
+				SourceRefName:         apiPr.SourceRefName,
+				TargetRefName:         apiPr.TargetRefName,
+				LastMergeSourceCommit: apiPr.LastMergeSourceCommit.CommitId,
+				LastMergeTargetCommit: apiPr.LastMergeTargetCommit.CommitId,
+				LastMergeCommit:       apiPr.LastMergeCommit.CommitId,
+				Url:                   apiPr.Url,
+			}
+			if apiPr.CreatedBy != nil {
+				pr.CreatedById = apiPr.CreatedBy.Id
+				pr.CreatedByName = apiPr.CreatedBy.DisplayName
+				if account := apiPr.CreatedBy.ToAccount(data.Options.ConnectionId); account != nil {
+					results = append(results, account)
+				}
+			}
+			results = append(results, pr)
+			for _, apiReviewer := range apiPr.Reviewers {
+				results = append(results, &models.AzurePrReviewer{
+					ConnectionId:  data.Options.ConnectionId,
+					PullRequestId: apiPr.PullRequestId,
+					ReviewerId:    apiReviewer.Id,
+					DisplayName:   apiReviewer.DisplayName,
+					UniqueName:    apiReviewer.UniqueName,
+					Vote:          apiReviewer.Vote,
+					IsRequired:    apiReviewer.IsRequired,
+					HasDeclined:   apiReviewer.HasDeclined,
+				})
+				if account := apiReviewer.ToAccount(data.Options.ConnectionId); account != nil {
+					results = append(results, account)
+				}
+			}
+			return results, nil
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	return extractor.Execute()
+}
@@ -0,0 +1,135 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/apache/incubator-devlake/errors"
+	"github.com/apache/incubator-devlake/plugins/azure/models"
+	"github.com/apache/incubator-devlake/plugins/core"
+	"github.com/apache/incubator-devlake/plugins/helper"
+)
+
+// AzureApiPropertyValue is a typed value in the `properties` of a thread
+type AzureApiPropertyValue struct {
+	Value string `json:"$value"`
+}
+
+type AzureApiPrThread struct {
+	Id            int `json:"id"`
+	ThreadContext *struct {
+		FilePath       string `json:"filePath"`
+		RightFileStart *struct {
+			Line int `json:"line"`
+		} `json:"rightFileStart"`
+	} `json:"threadContext"`
+	Properties map[string]AzureApiPropertyValue `json:"properties"`
+	Status     string                           `json:"status"`
+	IsDeleted  bool                             `json:"isDeleted"`
+	Comments   []struct {
+		Id              int               `json:"id"`
+		ParentCommentId int               `json:"parentCommentId"`
+		Author          *AzureApiIdentity `json:"author"`
+		Content         string            `json:"content"`
+		PublishedDate   time.Time         `json:"publishedDate"`
+		LastUpdatedDate *time.Time        `json:"lastUpdatedDate"`
+		CommentType     string            `json:"commentType"`
+		IsDeleted       bool              `json:"isDeleted"`
+	} `json:"comments"`
+}
+
+var ExtractApiPrThreadsMeta = core.SubTaskMeta{
+	Name:             "extractApiPullRequestThreads",
+	EntryPoint:       ExtractApiPullRequestThreads,
+	EnabledByDefault: true,
+	Description:      "Extract raw pull request threads data into tool layer table azure_pull_request_comments",
+	DomainTypes:      []string{core.DOMAIN_TYPE_CODE_REVIEW},
+}
+
+func ExtractApiPullRequestThreads(taskCtx core.SubTaskContext) errors.Error {
+	rawDataSubTaskArgs, data := CreateRawDataSubTaskArgs(taskCtx, RAW_PULL_REQUEST_THREAD_TABLE)
+	extractor, err := helper.NewApiExtractor(helper.ApiExtractorArgs{
+		RawDataSubTaskArgs: *rawDataSubTaskArgs,
+		Extract: func(row *helper.RawData) ([]interface{}, errors.Error) {
+			input := &AzureInput{}
+			err := errors.Convert(json.Unmarshal(row.Input, input))
+			if err != nil {
+				return nil, err
+			}
+			thread := &AzureApiPrThread{}
+			err = errors.Convert(json.Unmarshal(row.Data, thread))
+			if err != nil {
+				return nil, err
+			}
+			if thread.IsDeleted {
+				return nil, nil
+			}
+			threadType := thread.Properties["CodeReviewThreadType"].Value
+			vote := 0
+			if voteResult, ok := thread.Properties["CodeReviewVoteResult"]; ok {
+				vote, err = errors.Convert01(strconv.Atoi(voteResult.Value))
+				if err != nil {
+					return nil, err
+				}
+			}
+			results := make([]interface{}, 0, len(thread.Comments)*2)
+			for _, apiComment := range thread.Comments {
+				if apiComment.IsDeleted {
+					continue
+				}
+				comment := &models.AzurePrComment{
+					ConnectionId:    data.Options.ConnectionId,
+					PullRequestId:   input.AzureId,
+					ThreadId:        thread.Id,
+					CommentId:       apiComment.Id,
+					ParentCommentId: apiComment.ParentCommentId,
+					Content:         apiComment.Content,
+					CommentType:     apiComment.CommentType,
+					ThreadStatus:    thread.Status,
+					ThreadType:      threadType,
+					Vote:            vote,
+					PublishedDate:   apiComment.PublishedDate,
+					LastUpdatedDate: apiComment.LastUpdatedDate,
+				}
+				if thread.ThreadContext != nil {
+					comment.FilePath = thread.ThreadContext.FilePath
+					if thread.ThreadContext.RightFileStart != nil {
+						comment.Line = thread.ThreadContext.RightFileStart.Line
+					}
+				}
+				if apiComment.Author != nil {
+					comment.AuthorId = apiComment.Author.Id
+					comment.AuthorName = apiComment.Author.DisplayName
+					if account := apiComment.Author.ToAccount(data.Options.ConnectionId); account != nil {
+						results = append(results, account)
+					}
+				}
+				results = append(results, comment)
+			}
+			return results, nil
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	return extractor.Execute()
+}
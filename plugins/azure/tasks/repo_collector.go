@@ -19,7 +19,6 @@ package tasks
 
 import (
 	"encoding/json"
-	"fmt"
 	"github.com/apache/incubator-devlake/errors"
 	"net/http"
 	"net/url"
@@ -40,35 +39,25 @@ var CollectApiRepoMeta = core.SubTaskMeta{
 }
 
 func CollectApiRepositories(taskCtx core.SubTaskContext) errors.Error {
-	data := taskCtx.GetData().(*AzureTaskData)
+	rawDataSubTaskArgs, data := CreateRawDataSubTaskArgs(taskCtx, RAW_REPOSITORIES_TABLE)
 
 	collector, err := helper.NewApiCollector(helper.ApiCollectorArgs{
-		RawDataSubTaskArgs: helper.RawDataSubTaskArgs{
-			Ctx: taskCtx,
-			Params: AzureApiParams{
-				ConnectionId: data.Options.ConnectionId,
-				Project:      data.Options.Project,
-			},
-			Table: RAW_REPOSITORIES_TABLE,
-		},
-		ApiClient: data.ApiClient,
+		RawDataSubTaskArgs: *rawDataSubTaskArgs,
+		ApiClient:          data.ApiClient,
 
-		UrlTemplate: "{{ .Params.Project }}/_apis/git/repositories?api-version=7.1-preview.1",
+		UrlTemplate: "{{ .Params.Project }}/_apis/git/repositories/{{ .Params.RepositoryId }}",
 		Query: func(reqData *helper.RequestData) (url.Values, errors.Error) {
 			query := url.Values{}
-			query.Set("state", "all")
-			query.Set("page", fmt.Sprintf("%v", reqData.Pager.Page))
-			query.Set("direction", "asc")
-			query.Set("per_page", fmt.Sprintf("%v", reqData.Pager.Size))
-
+			query.Set("api-version", "7.1-preview.1")
 			return query, nil
 		},
 		ResponseParser: func(res *http.Response) ([]json.RawMessage, errors.Error) {
-			var data struct {
-				Repos []json.RawMessage `json:"value"`
+			var repo json.RawMessage
+			err := helper.UnmarshalResponse(res, &repo)
+			if err != nil {
+				return nil, err
 			}
-			err := helper.UnmarshalResponse(res, &data)
-			return data.Repos, err
+			return []json.RawMessage{repo}, nil
 		},
 	})
 
@@ -60,23 +60,9 @@ var ExtractApiRepoMeta = core.SubTaskMeta{
 type ApiRepoResponse AzureApiRepo
 
 func ExtractApiRepositories(taskCtx core.SubTaskContext) errors.Error {
-	data := taskCtx.GetData().(*AzureTaskData)
+	rawDataSubTaskArgs, data := CreateRawDataSubTaskArgs(taskCtx, RAW_REPOSITORIES_TABLE)
 	extractor, err := helper.NewApiExtractor(helper.ApiExtractorArgs{
-		RawDataSubTaskArgs: helper.RawDataSubTaskArgs{
-			Ctx: taskCtx,
-			/*
-				This struct will be JSONEncoded and stored into database along with raw data itself, to identity minimal
-				set of data to be process, for example, we process JiraIssues by Board
-			*/
-			Params: AzureApiParams{
-				ConnectionId: data.Options.ConnectionId,
-				Project:      data.Options.Project,
-			},
-			/*
-				Table store raw data
-			*/
-			Table: RAW_REPOSITORIES_TABLE,
-		},
+		RawDataSubTaskArgs: *rawDataSubTaskArgs,
 		Extract: func(row *helper.RawData) ([]interface{}, errors.Error) {
 			body := &ApiRepoResponse{}
 			err := errors.Convert(json.Unmarshal(row.Data, body))
@@ -84,7 +70,7 @@ func ExtractApiRepositories(taskCtx core.SubTaskContext) errors.Error {
 				return nil, err
 			}
 			if body.ID == "" {
-				return nil, errors.Default.New(fmt.Sprintf("repo %s not found", data.Options.Project))
+				return nil, errors.NotFound.New(fmt.Sprintf("repo %s not found in project %s", data.Options.RepositoryId, data.Options.Project))
 			}
 			results := make([]interface{}, 0, 1)
 			azureRepository := &models.AzureRepo{
@@ -93,12 +79,15 @@ func ExtractApiRepositories(taskCtx core.SubTaskContext) errors.Error {
 				Name:          body.Name,
 				Url:           body.URL,
 				ProjectId:     body.Project.ID,
+				ProjectName:   body.Project.Name,
 				DefaultBranch: body.DefaultBranch,
 				Size:          body.Size,
 				RemoteURL:     body.RemoteURL,
 				SshUrl:        body.SSHURL,
 				WebUrl:        body.WebURL,
 				IsDisabled:    body.IsDisabled,
+				// TransformationRuleId was loaded from the repo by EnrichOptions when it was not specified
+				TransformationRuleId: data.Options.TransformationRuleId,
 			}
 			data.Repo = azureRepository
 
@@ -0,0 +1,137 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/apache/incubator-devlake/errors"
+	"github.com/apache/incubator-devlake/plugins/azure/models"
+	"github.com/apache/incubator-devlake/plugins/core"
+	"github.com/apache/incubator-devlake/plugins/core/dal"
+	"github.com/apache/incubator-devlake/plugins/helper"
+)
+
+// AzureInput is the input of collectors depending on previously extracted records, like pull request threads
+type AzureInput struct {
+	AzureId int
+}
+
+// AzureApiIdentity is the IdentityRef returned by azure devops apis
+type AzureApiIdentity struct {
+	Id          string `json:"id"`
+	DisplayName string `json:"displayName"`
+	UniqueName  string `json:"uniqueName"`
+	ImageUrl    string `json:"imageUrl"`
+}
+
+// ToAccount converts the identity to an AzureAccount, returns nil if the identity is empty
+func (identity *AzureApiIdentity) ToAccount(connectionId uint64) *models.AzureAccount {
+	if identity == nil || identity.Id == "" {
+		return nil
+	}
+	return &models.AzureAccount{
+		ConnectionId: connectionId,
+		AccountId:    identity.Id,
+		DisplayName:  identity.DisplayName,
+		UniqueName:   identity.UniqueName,
+		ImageUrl:     identity.ImageUrl,
+	}
+}
+
+// AzureApiGitUser is the GitUserDate returned by azure devops git apis
+type AzureApiGitUser struct {
+	Name  string    `json:"name"`
+	Email string    `json:"email"`
+	Date  time.Time `json:"date"`
+}
+
+func CreateRawDataSubTaskArgs(taskCtx core.SubTaskContext, table string) (*helper.RawDataSubTaskArgs, *AzureTaskData) {
+	data := taskCtx.GetData().(*AzureTaskData)
+	rawDataSubTaskArgs := &helper.RawDataSubTaskArgs{
+		Ctx: taskCtx,
+		Params: AzureApiParams{
+			ConnectionId: data.Options.ConnectionId,
+			Project:      data.Options.Project,
+			RepositoryId: data.Options.RepositoryId,
+		},
+		Table: table,
+	}
+	return rawDataSubTaskArgs, data
+}
+
+// CreateProjectRawDataSubTaskArgs creates the args of subtasks whose raw data belongs to the project rather than
+// the repo, like work items, so they are collected only once no matter how many repos of the project are synced
+func CreateProjectRawDataSubTaskArgs(taskCtx core.SubTaskContext, table string) (*helper.RawDataSubTaskArgs, *AzureTaskData) {
+	rawDataSubTaskArgs, data := CreateRawDataSubTaskArgs(taskCtx, table)
+	rawDataSubTaskArgs.Params = AzureApiParams{
+		ConnectionId: data.Options.ConnectionId,
+		Project:      data.Options.Project,
+	}
+	return rawDataSubTaskArgs, data
+}
+
+// ParseRawMessageFromValue extracts items from the `value` field which wraps all list responses of azure devops
+func ParseRawMessageFromValue(res *http.Response) ([]json.RawMessage, errors.Error) {
+	var data struct {
+		Value []json.RawMessage `json:"value"`
+	}
+	err := helper.UnmarshalResponse(res, &data)
+	return data.Value, err
+}
+
+// GetNextPageContinuationToken returns the continuation token of the next page, which was sent by
+// azure devops in the `x-ms-continuationtoken` header, for apis paginated by token
+func GetNextPageContinuationToken(_ *helper.RequestData, prevPageResponse *http.Response) (interface{}, errors.Error) {
+	token := prevPageResponse.Header.Get("x-ms-continuationtoken")
+	if token == "" {
+		return nil, helper.ErrFinishCollect
+	}
+	return token, nil
+}
+
+// GetPullRequestsIterator iterates over the pull requests of the repo
+func GetPullRequestsIterator(taskCtx core.SubTaskContext) (*helper.DalCursorIterator, errors.Error) {
+	db := taskCtx.GetDal()
+	data := taskCtx.GetData().(*AzureTaskData)
+	cursor, err := db.Cursor(
+		dal.Select("azure_id"),
+		dal.From(&models.AzurePullRequest{}),
+		dal.Where("repository_id = ? AND connection_id = ?", data.Options.RepositoryId, data.Options.ConnectionId),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return helper.NewDalCursorIterator(db, cursor, reflect.TypeOf(AzureInput{}))
+}
+
+// splitByComma splits a comma separated list of the transformation rule, empty items are ignored
+func splitByComma(s string) []string {
+	result := make([]string, 0)
+	for _, item := range strings.Split(s, ",") {
+		item = strings.TrimSpace(item)
+		if item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
@@ -18,6 +18,8 @@ limitations under the License.
 package tasks
 
 import (
+	"time"
+
 	"github.com/apache/incubator-devlake/errors"
 	"github.com/apache/incubator-devlake/plugins/azure/models"
 	"github.com/apache/incubator-devlake/plugins/helper"
@@ -26,31 +28,69 @@ import (
 type AzureApiParams struct {
 	ConnectionId uint64
 	Project      string
+	RepositoryId string `json:",omitempty"`
 }
 
 type AzureOptions struct {
-	ConnectionId uint64 `json:"connectionId"`
-	Project      string
-	Since        string
-	Tasks        []string `json:"tasks,omitempty"`
+	ConnectionId uint64   `json:"connectionId" mapstructure:"connectionId,omitempty"`
+	Project      string   `json:"project" mapstructure:"project,omitempty"`
+	RepositoryId string   `json:"repositoryId" mapstructure:"repositoryId,omitempty"`
+	Since        string   `json:"since" mapstructure:"since,omitempty"`
+	Tasks        []string `json:"tasks,omitempty" mapstructure:",omitempty"`
+	// CreatedDateAfter limits collection of pull requests, commits, builds and work items to those created/changed after it
+	CreatedDateAfter                string `json:"createdDateAfter" mapstructure:"createdDateAfter,omitempty"`
+	TransformationRuleId            uint64 `json:"transformationRuleId" mapstructure:"transformationRuleId,omitempty"`
+	*models.AzureTransformationRule `mapstructure:"transformationRules,omitempty" json:"transformationRules"`
 }
 
 type AzureTaskData struct {
-	Options    *AzureOptions
-	ApiClient  *helper.ApiAsyncClient
-	Connection *models.AzureConnection
-	Repo       *models.AzureRepo
+	Options          *AzureOptions
+	ApiClient        *helper.ApiAsyncClient
+	Connection       *models.AzureConnection
+	Repo             *models.AzureRepo
+	CreatedDateAfter *time.Time
 }
 
 func DecodeAndValidateTaskOptions(options map[string]interface{}) (*AzureOptions, errors.Error) {
+	op, err := DecodeTaskOptions(options)
+	if err != nil {
+		return nil, err
+	}
+	err = ValidateTaskOptions(op)
+	if err != nil {
+		return nil, err
+	}
+	return op, nil
+}
+
+func DecodeTaskOptions(options map[string]interface{}) (*AzureOptions, errors.Error) {
 	var op AzureOptions
 	err := helper.Decode(options, &op, nil)
 	if err != nil {
 		return nil, errors.Default.Wrap(err, "unable to decode Azure options")
 	}
+	return &op, nil
+}
+
+func EncodeTaskOptions(op *AzureOptions) (map[string]interface{}, errors.Error) {
+	var result map[string]interface{}
+	err := helper.Decode(op, &result, nil)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func ValidateTaskOptions(op *AzureOptions) errors.Error {
+	if op.Project == "" {
+		return errors.BadInput.New("Azure project is required")
+	}
+	if op.RepositoryId == "" {
+		return errors.BadInput.New("Azure repositoryId is required")
+	}
 	// find the needed Azure now
 	if op.ConnectionId == 0 {
-		return nil, errors.BadInput.New("Azure connectionId is invalid")
+		return errors.BadInput.New("Azure connectionId is invalid")
 	}
-	return &op, nil
+	return nil
 }
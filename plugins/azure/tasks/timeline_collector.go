@@ -0,0 +1,98 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"reflect"
+
+	"github.com/apache/incubator-devlake/errors"
+	"github.com/apache/incubator-devlake/plugins/azure/models"
+	"github.com/apache/incubator-devlake/plugins/core"
+	"github.com/apache/incubator-devlake/plugins/core/dal"
+	"github.com/apache/incubator-devlake/plugins/helper"
+)
+
+const RAW_TIMELINE_TABLE = "azure_api_timelines"
+
+var CollectApiTimelinesMeta = core.SubTaskMeta{
+	Name:             "collectApiTimelines",
+	EntryPoint:       CollectApiTimelines,
+	EnabledByDefault: true,
+	Description:      "Collect timelines of builds from Azure api, which contain the stages and jobs",
+	DomainTypes:      []string{core.DOMAIN_TYPE_CICD},
+}
+
+func CollectApiTimelines(taskCtx core.SubTaskContext) errors.Error {
+	rawDataSubTaskArgs, data := CreateRawDataSubTaskArgs(taskCtx, RAW_TIMELINE_TABLE)
+	collectorWithState, err := helper.NewApiCollectorWithState(*rawDataSubTaskArgs, data.CreatedDateAfter)
+	if err != nil {
+		return err
+	}
+
+	db := taskCtx.GetDal()
+	clauses := []dal.Clause{
+		dal.Select("azure_id"),
+		dal.From(&models.AzureBuild{}),
+		dal.Where("repository_id = ? AND connection_id = ?", data.Options.RepositoryId, data.Options.ConnectionId),
+	}
+	incremental := collectorWithState.IsIncremental()
+	if incremental {
+		// timelines of builds finished before last collection wouldn't change anymore
+		clauses = append(clauses, dal.Where("finish_time IS NULL OR finish_time > ?", collectorWithState.LatestState.LatestSuccessStart))
+	}
+	cursor, err := db.Cursor(clauses...)
+	if err != nil {
+		return err
+	}
+	iterator, err := helper.NewDalCursorIterator(db, cursor, reflect.TypeOf(AzureInput{}))
+	if err != nil {
+		return err
+	}
+
+	err = collectorWithState.InitCollector(helper.ApiCollectorArgs{
+		ApiClient:   data.ApiClient,
+		Incremental: incremental,
+		Input:       iterator,
+		UrlTemplate: "{{ .Params.Project }}/_apis/build/builds/{{ .Input.AzureId }}/timeline",
+		Query: func(reqData *helper.RequestData) (url.Values, errors.Error) {
+			query := url.Values{}
+			query.Set("api-version", "7.1-preview.2")
+			return query, nil
+		},
+		// the whole timeline is saved as one row, so that stages of jobs could be resolved by the extractor
+		ResponseParser: func(res *http.Response) ([]json.RawMessage, errors.Error) {
+			if res.StatusCode == http.StatusNoContent {
+				return nil, nil
+			}
+			var timeline json.RawMessage
+			err := helper.UnmarshalResponse(res, &timeline)
+			if err != nil {
+				return nil, err
+			}
+			return []json.RawMessage{timeline}, nil
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	return collectorWithState.Execute()
+}
@@ -0,0 +1,121 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/apache/incubator-devlake/errors"
+	"github.com/apache/incubator-devlake/models/domainlayer"
+	"github.com/apache/incubator-devlake/models/domainlayer/devops"
+	"github.com/apache/incubator-devlake/models/domainlayer/didgen"
+	"github.com/apache/incubator-devlake/plugins/azure/models"
+	"github.com/apache/incubator-devlake/plugins/core"
+	"github.com/apache/incubator-devlake/plugins/core/dal"
+	"github.com/apache/incubator-devlake/plugins/helper"
+)
+
+var ConvertTimelinesMeta = core.SubTaskMeta{
+	Name:             "convertTimelines",
+	EntryPoint:       ConvertTimelines,
+	EnabledByDefault: true,
+	Description:      "Convert jobs of tool layer table azure_timeline_records into domain layer table cicd_tasks",
+	DomainTypes:      []string{core.DOMAIN_TYPE_CICD},
+}
+
+func ConvertTimelines(taskCtx core.SubTaskContext) errors.Error {
+	rawDataSubTaskArgs, data := CreateRawDataSubTaskArgs(taskCtx, RAW_TIMELINE_TABLE)
+	db := taskCtx.GetDal()
+	deploymentPattern := data.Options.DeploymentPattern
+	productionPattern := data.Options.ProductionPattern
+	regexEnricher := helper.NewRegexEnricher()
+	err := regexEnricher.AddRegexp(deploymentPattern, productionPattern)
+	if err != nil {
+		return err
+	}
+
+	cursor, err := db.Cursor(
+		dal.Select("tr.*"),
+		dal.From("_tool_azure_timeline_records tr"),
+		dal.Join(`left join _tool_azure_builds b on (
+			b.azure_id = tr.build_id AND b.connection_id = tr.connection_id
+		)`),
+		dal.Where("tr.type = ? AND b.repository_id = ? AND b.connection_id = ?",
+			RECORD_TYPE_JOB, data.Options.RepositoryId, data.Options.ConnectionId),
+	)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close()
+
+	recordIdGen := didgen.NewDomainIdGenerator(&models.AzureTimelineRecord{})
+	buildIdGen := didgen.NewDomainIdGenerator(&models.AzureBuild{})
+	domainRepoId := didgen.NewDomainIdGenerator(&models.AzureRepo{}).Generate(data.Options.ConnectionId, data.Options.RepositoryId)
+
+	converter, err := helper.NewDataConverter(helper.DataConverterArgs{
+		InputRowType:       reflect.TypeOf(models.AzureTimelineRecord{}),
+		Input:              cursor,
+		RawDataSubTaskArgs: *rawDataSubTaskArgs,
+		Convert: func(inputRow interface{}) ([]interface{}, errors.Error) {
+			record := inputRow.(*models.AzureTimelineRecord)
+			// jobs are named after their stages so that the patterns could match either of them
+			name := record.Name
+			if record.StageName != "" {
+				name = fmt.Sprintf("%s/%s", record.StageName, record.Name)
+			}
+			domainTask := &devops.CICDTask{
+				DomainEntity: domainlayer.DomainEntity{
+					Id: recordIdGen.Generate(data.Options.ConnectionId, record.RecordId),
+				},
+				Name:       name,
+				PipelineId: buildIdGen.Generate(data.Options.ConnectionId, record.BuildId),
+				Result: devops.GetResult(&devops.ResultRule{
+					Success: []string{"succeeded", "succeededWithIssues"},
+					Failed:  []string{"failed"},
+					Abort:   []string{"canceled", "abandoned", "skipped"},
+					Default: "",
+				}, record.Result),
+				Status: devops.GetStatus(&devops.StatusRule{
+					InProgress: []string{"pending", "inProgress"},
+					Done:       []string{"completed"},
+					Default:    "",
+				}, record.State),
+				FinishedDate: record.FinishTime,
+				CicdScopeId:  domainRepoId,
+			}
+			if record.StartTime != nil {
+				domainTask.StartedDate = *record.StartTime
+				if record.FinishTime != nil {
+					domainTask.DurationSec = uint64(record.FinishTime.Sub(*record.StartTime) / time.Second)
+				}
+			}
+			domainTask.Type = regexEnricher.GetEnrichResult(deploymentPattern, name, devops.DEPLOYMENT)
+			domainTask.Environment = regexEnricher.GetEnrichResult(productionPattern, name, devops.PRODUCTION)
+			return []interface{}{
+				domainTask,
+			}, nil
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	return converter.Execute()
+}
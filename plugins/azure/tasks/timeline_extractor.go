@@ -0,0 +1,120 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/apache/incubator-devlake/errors"
+	"github.com/apache/incubator-devlake/plugins/azure/models"
+	"github.com/apache/incubator-devlake/plugins/core"
+	"github.com/apache/incubator-devlake/plugins/helper"
+)
+
+// types of timeline records
+const (
+	RECORD_TYPE_STAGE = "Stage"
+	RECORD_TYPE_PHASE = "Phase"
+	RECORD_TYPE_JOB   = "Job"
+)
+
+type AzureApiTimelineRecord struct {
+	Id         string     `json:"id"`
+	ParentId   string     `json:"parentId"`
+	Type       string     `json:"type"`
+	Name       string     `json:"name"`
+	State      string     `json:"state"`
+	Result     string     `json:"result"`
+	Attempt    int        `json:"attempt"`
+	StartTime  *time.Time `json:"startTime"`
+	FinishTime *time.Time `json:"finishTime"`
+}
+
+type AzureApiTimeline struct {
+	Records []AzureApiTimelineRecord `json:"records"`
+}
+
+var ExtractApiTimelinesMeta = core.SubTaskMeta{
+	Name:             "extractApiTimelines",
+	EntryPoint:       ExtractApiTimelines,
+	EnabledByDefault: true,
+	Description:      "Extract stages and jobs of raw timelines data into tool layer table azure_timeline_records",
+	DomainTypes:      []string{core.DOMAIN_TYPE_CICD},
+}
+
+func ExtractApiTimelines(taskCtx core.SubTaskContext) errors.Error {
+	rawDataSubTaskArgs, data := CreateRawDataSubTaskArgs(taskCtx, RAW_TIMELINE_TABLE)
+	extractor, err := helper.NewApiExtractor(helper.ApiExtractorArgs{
+		RawDataSubTaskArgs: *rawDataSubTaskArgs,
+		Extract: func(row *helper.RawData) ([]interface{}, errors.Error) {
+			input := &AzureInput{}
+			err := errors.Convert(json.Unmarshal(row.Input, input))
+			if err != nil {
+				return nil, err
+			}
+			timeline := &AzureApiTimeline{}
+			err = errors.Convert(json.Unmarshal(row.Data, timeline))
+			if err != nil {
+				return nil, err
+			}
+			recordsById := make(map[string]*AzureApiTimelineRecord, len(timeline.Records))
+			for i := range timeline.Records {
+				recordsById[timeline.Records[i].Id] = &timeline.Records[i]
+			}
+			results := make([]interface{}, 0, len(timeline.Records))
+			for _, apiRecord := range timeline.Records {
+				if apiRecord.Type != RECORD_TYPE_STAGE && apiRecord.Type != RECORD_TYPE_JOB {
+					continue
+				}
+				results = append(results, &models.AzureTimelineRecord{
+					ConnectionId: data.Options.ConnectionId,
+					RecordId:     apiRecord.Id,
+					BuildId:      input.AzureId,
+					ParentId:     apiRecord.ParentId,
+					Type:         apiRecord.Type,
+					Name:         apiRecord.Name,
+					StageName:    findStageName(recordsById, &apiRecord),
+					State:        apiRecord.State,
+					Result:       apiRecord.Result,
+					Attempt:      apiRecord.Attempt,
+					StartTime:    apiRecord.StartTime,
+					FinishTime:   apiRecord.FinishTime,
+				})
+			}
+			return results, nil
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	return extractor.Execute()
+}
+
+// findStageName walks up the Job -> Phase -> Stage chain, classic pipelines have no stages and an empty name is returned
+func findStageName(recordsById map[string]*AzureApiTimelineRecord, record *AzureApiTimelineRecord) string {
+	// the depth is limited in case of any malformed parent ids
+	for depth := 0; record != nil && depth <= len(recordsById); depth++ {
+		if record.Type == RECORD_TYPE_STAGE {
+			return record.Name
+		}
+		record = recordsById[record.ParentId]
+	}
+	return ""
+}
@@ -0,0 +1,154 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/apache/incubator-devlake/errors"
+	"github.com/apache/incubator-devlake/plugins/core"
+	"github.com/apache/incubator-devlake/plugins/helper"
+)
+
+const RAW_WORK_ITEM_TABLE = "azure_api_work_items"
+
+// the max number of work items could be fetched by one request
+const workItemsBatchSize = 200
+
+// the max number of work items could be returned by one wiql query
+const wiqlLimit = 20000
+
+var CollectApiWorkItemsMeta = core.SubTaskMeta{
+	Name:             "collectApiWorkItems",
+	EntryPoint:       CollectApiWorkItems,
+	EnabledByDefault: true,
+	Description:      "Collect work items of the project from Azure api",
+	DomainTypes:      []string{core.DOMAIN_TYPE_TICKET},
+}
+
+func CollectApiWorkItems(taskCtx core.SubTaskContext) errors.Error {
+	rawDataSubTaskArgs, data := CreateProjectRawDataSubTaskArgs(taskCtx, RAW_WORK_ITEM_TABLE)
+	collectorWithState, err := helper.NewApiCollectorWithState(*rawDataSubTaskArgs, data.CreatedDateAfter)
+	if err != nil {
+		return err
+	}
+
+	// work items can only be listed by wiql, which returns ids only, details are fetched by batches of ids afterwards
+	incremental := collectorWithState.IsIncremental()
+	condition := "[System.TeamProject] = @project"
+	if incremental {
+		condition += fmt.Sprintf(" And [System.ChangedDate] >= '%s'", collectorWithState.LatestState.LatestSuccessStart.Format(time.RFC3339))
+	} else if data.CreatedDateAfter != nil {
+		condition += fmt.Sprintf(" And [System.CreatedDate] >= '%s'", data.CreatedDateAfter.Format(time.RFC3339))
+	}
+	ids, err := queryAllWorkItemIds(taskCtx.GetLogger(), data.Options.Project, condition, func(wiql string) ([]int, errors.Error) {
+		return queryWorkItemIds(data, wiql)
+	})
+	if err != nil {
+		return err
+	}
+	iterator := helper.NewQueueIterator()
+	for i := 0; i < len(ids); i += workItemsBatchSize {
+		end := i + workItemsBatchSize
+		if end > len(ids) {
+			end = len(ids)
+		}
+		iterator.Push(helper.NewQueueIteratorNode(strings.Join(ids[i:end], ",")))
+	}
+
+	err = collectorWithState.InitCollector(helper.ApiCollectorArgs{
+		ApiClient:   data.ApiClient,
+		Incremental: incremental,
+		Input:       iterator,
+		UrlTemplate: "{{ .Params.Project }}/_apis/wit/workitems",
+		Query: func(reqData *helper.RequestData) (url.Values, errors.Error) {
+			query := url.Values{}
+			query.Set("api-version", "7.1-preview.3")
+			query.Set("ids", reqData.Input.(*helper.QueueIteratorNode).Data().(string))
+			query.Set("errorPolicy", "omit")
+			return query, nil
+		},
+		ResponseParser: ParseRawMessageFromValue,
+	})
+	if err != nil {
+		return err
+	}
+
+	return collectorWithState.Execute()
+}
+
+// queryAllWorkItemIds pages through the work items matching the condition by their ids, since a wiql query
+// returns at most wiqlLimit work items and the rest are silently dropped
+func queryAllWorkItemIds(
+	logger core.Logger,
+	project string,
+	condition string,
+	query func(wiql string) ([]int, errors.Error),
+) ([]string, errors.Error) {
+	var ids []string
+	lastId := 0
+	for {
+		wiql := fmt.Sprintf("Select [System.Id] From WorkItems Where %s And [System.Id] > %d Order By [System.Id] Asc", condition, lastId)
+		pageIds, err := query(wiql)
+		if err != nil {
+			return nil, err
+		}
+		for _, id := range pageIds {
+			ids = append(ids, fmt.Sprintf("%d", id))
+		}
+		if len(pageIds) < wiqlLimit {
+			return ids, nil
+		}
+		lastId = pageIds[len(pageIds)-1]
+		logger.Info("wiql query of %s hit the limit of %d work items, querying the work items after id %d", project, wiqlLimit, lastId)
+	}
+}
+
+func queryWorkItemIds(data *AzureTaskData, wiql string) ([]int, errors.Error) {
+	query := url.Values{}
+	query.Set("api-version", "7.1-preview.2")
+	query.Set("timePrecision", "true")
+	query.Set("$top", fmt.Sprintf("%d", wiqlLimit))
+	res, err := data.ApiClient.Post(fmt.Sprintf("%s/_apis/wit/wiql", data.Options.Project), query, map[string]string{
+		"query": wiql,
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode != http.StatusOK {
+		return nil, errors.HttpStatus(res.StatusCode).New(fmt.Sprintf("unexpected status code when querying work items of %s", data.Options.Project))
+	}
+	var result struct {
+		WorkItems []struct {
+			Id int `json:"id"`
+		} `json:"workItems"`
+	}
+	err = helper.UnmarshalResponse(res, &result)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]int, 0, len(result.WorkItems))
+	for _, workItem := range result.WorkItems {
+		ids = append(ids, workItem.Id)
+	}
+	return ids, nil
+}
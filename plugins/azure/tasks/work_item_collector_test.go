@@ -0,0 +1,74 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/apache/incubator-devlake/errors"
+	"github.com/apache/incubator-devlake/plugins/core"
+	"github.com/stretchr/testify/assert"
+)
+
+type infoRecorder struct {
+	core.Logger
+	infos []string
+}
+
+func (l *infoRecorder) Info(format string, a ...interface{}) {
+	l.infos = append(l.infos, fmt.Sprintf(format, a...))
+}
+
+func TestQueryAllWorkItemIds(t *testing.T) {
+	// the project has wiqlLimit + 2 work items, the first query hits the limit
+	total := wiqlLimit + 2
+	var wiqls []string
+	query := func(wiql string) ([]int, errors.Error) {
+		wiqls = append(wiqls, wiql)
+		afterId := 0
+		_, err := fmt.Sscanf(wiql[len("Select [System.Id] From WorkItems Where [System.TeamProject] = @project And [System.Id] > "):], "%d", &afterId)
+		assert.Nil(t, err)
+		var ids []int
+		for id := afterId + 1; id <= total && len(ids) < wiqlLimit; id++ {
+			ids = append(ids, id)
+		}
+		return ids, nil
+	}
+	logger := &infoRecorder{}
+	ids, err := queryAllWorkItemIds(logger, "test", "[System.TeamProject] = @project", query)
+	assert.Nil(t, err)
+	assert.Len(t, ids, total)
+	assert.Equal(t, "1", ids[0])
+	assert.Equal(t, fmt.Sprintf("%d", total), ids[total-1])
+	assert.Equal(t, []string{
+		"Select [System.Id] From WorkItems Where [System.TeamProject] = @project And [System.Id] > 0 Order By [System.Id] Asc",
+		fmt.Sprintf("Select [System.Id] From WorkItems Where [System.TeamProject] = @project And [System.Id] > %d Order By [System.Id] Asc", wiqlLimit),
+	}, wiqls)
+	assert.Len(t, logger.infos, 1)
+
+	// a single page when the limit is not hit
+	total = 3
+	wiqls = nil
+	logger = &infoRecorder{}
+	ids, err = queryAllWorkItemIds(logger, "test", "[System.TeamProject] = @project", query)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"1", "2", "3"}, ids)
+	assert.Len(t, wiqls, 1)
+	assert.Empty(t, logger.infos)
+}
@@ -0,0 +1,200 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/apache/incubator-devlake/errors"
+	"github.com/apache/incubator-devlake/models/domainlayer"
+	"github.com/apache/incubator-devlake/models/domainlayer/didgen"
+	"github.com/apache/incubator-devlake/models/domainlayer/ticket"
+	"github.com/apache/incubator-devlake/plugins/azure/models"
+	"github.com/apache/incubator-devlake/plugins/core"
+	"github.com/apache/incubator-devlake/plugins/core/dal"
+	"github.com/apache/incubator-devlake/plugins/helper"
+)
+
+// built-in work item types and states of the agile, scrum, basic and cmmi processes
+const (
+	DEFAULT_ISSUE_TYPE_REQUIREMENT = "Epic,Feature,User Story,Product Backlog Item,Requirement,Issue"
+	DEFAULT_ISSUE_TYPE_BUG         = "Bug"
+	DEFAULT_ISSUE_TYPE_INCIDENT    = ""
+
+	DEFAULT_ISSUE_STATUS_TODO        = "New,To Do,Proposed,Approved"
+	DEFAULT_ISSUE_STATUS_IN_PROGRESS = "Active,Committed,In Progress,Doing,Resolved"
+	DEFAULT_ISSUE_STATUS_DONE        = "Closed,Done,Completed,Removed"
+)
+
+var ConvertWorkItemsMeta = core.SubTaskMeta{
+	Name:             "convertWorkItems",
+	EntryPoint:       ConvertWorkItems,
+	EnabledByDefault: true,
+	Description:      "Convert tool layer table azure_work_items into domain layer table issues and board_issues",
+	DomainTypes:      []string{core.DOMAIN_TYPE_TICKET},
+}
+
+func ConvertWorkItems(taskCtx core.SubTaskContext) errors.Error {
+	rawDataSubTaskArgs, data := CreateRawDataSubTaskArgs(taskCtx, RAW_WORK_ITEM_TABLE)
+	db := taskCtx.GetDal()
+	typeMap := newWorkItemTypeMap(data.Options.AzureTransformationRule)
+	statusMap := newWorkItemStatusMap(data.Options.AzureTransformationRule)
+
+	// work items were collected once for the whole project, they are gathered into the board of the repo
+	projectRawDataSubTaskArgs, _ := CreateProjectRawDataSubTaskArgs(taskCtx, RAW_WORK_ITEM_TABLE)
+	params, err := errors.Convert01(json.Marshal(projectRawDataSubTaskArgs.Params))
+	if err != nil {
+		return err
+	}
+	cursor, err := db.Cursor(
+		dal.From(&models.AzureWorkItem{}),
+		dal.Where("_raw_data_table = ? AND _raw_data_params = ?", "_raw_"+RAW_WORK_ITEM_TABLE, string(params)),
+	)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close()
+
+	issueIdGen := didgen.NewDomainIdGenerator(&models.AzureWorkItem{})
+	accountIdGen := didgen.NewDomainIdGenerator(&models.AzureAccount{})
+	boardId := didgen.NewDomainIdGenerator(&models.AzureRepo{}).Generate(data.Options.ConnectionId, data.Options.RepositoryId)
+
+	converter, err := helper.NewDataConverter(helper.DataConverterArgs{
+		InputRowType:       reflect.TypeOf(models.AzureWorkItem{}),
+		Input:              cursor,
+		RawDataSubTaskArgs: *rawDataSubTaskArgs,
+		Convert: func(inputRow interface{}) ([]interface{}, errors.Error) {
+			workItem := inputRow.(*models.AzureWorkItem)
+			issue := &ticket.Issue{
+				DomainEntity: domainlayer.DomainEntity{
+					Id: issueIdGen.Generate(data.Options.ConnectionId, workItem.AzureId),
+				},
+				Url:             workItem.Url,
+				IssueKey:        fmt.Sprintf("%d", workItem.AzureId),
+				Title:           workItem.Title,
+				Description:     workItem.Description,
+				OriginalType:    workItem.Type,
+				OriginalStatus:  workItem.State,
+				StoryPoint:      int64(workItem.StoryPoints),
+				CreatedDate:     workItem.CreatedDate,
+				UpdatedDate:     workItem.ChangedDate,
+				Priority:        workItem.Priority,
+				Severity:        workItem.Severity,
+				Component:       workItem.AreaPath,
+				CreatorName:     workItem.CreatedByName,
+				AssigneeName:    workItem.AssignedToName,
+				OriginalProject: workItem.ProjectName,
+			}
+			if issueType, ok := typeMap[workItem.Type]; ok {
+				issue.Type = issueType
+			} else {
+				issue.Type = workItem.Type
+			}
+			if status, ok := statusMap[workItem.State]; ok {
+				issue.Status = status
+			} else {
+				issue.Status = ticket.OTHER
+			}
+			if issue.Status == ticket.DONE {
+				issue.ResolutionDate = workItem.ClosedDate
+				if issue.ResolutionDate == nil {
+					issue.ResolutionDate = workItem.ResolvedDate
+				}
+				if issue.ResolutionDate != nil && issue.CreatedDate != nil {
+					issue.LeadTimeMinutes = int64(issue.ResolutionDate.Sub(*issue.CreatedDate).Minutes())
+				}
+			}
+			if workItem.ParentId != 0 {
+				issue.ParentIssueId = issueIdGen.Generate(data.Options.ConnectionId, workItem.ParentId)
+			}
+			if workItem.CreatedById != "" {
+				issue.CreatorId = accountIdGen.Generate(data.Options.ConnectionId, workItem.CreatedById)
+			}
+			if workItem.AssignedToId != "" {
+				issue.AssigneeId = accountIdGen.Generate(data.Options.ConnectionId, workItem.AssignedToId)
+			}
+			boardIssue := &ticket.BoardIssue{
+				BoardId: boardId,
+				IssueId: issue.Id,
+			}
+			return []interface{}{
+				issue,
+				boardIssue,
+			}, nil
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	return converter.Execute()
+}
+
+func newWorkItemTypeMap(rule *models.AzureTransformationRule) map[string]string {
+	requirement, bug, incident := DEFAULT_ISSUE_TYPE_REQUIREMENT, DEFAULT_ISSUE_TYPE_BUG, DEFAULT_ISSUE_TYPE_INCIDENT
+	if rule != nil {
+		if rule.IssueTypeRequirement != "" {
+			requirement = rule.IssueTypeRequirement
+		}
+		if rule.IssueTypeBug != "" {
+			bug = rule.IssueTypeBug
+		}
+		if rule.IssueTypeIncident != "" {
+			incident = rule.IssueTypeIncident
+		}
+	}
+	typeMap := map[string]string{"Task": ticket.TASK}
+	for _, t := range splitByComma(requirement) {
+		typeMap[t] = ticket.REQUIREMENT
+	}
+	for _, t := range splitByComma(bug) {
+		typeMap[t] = ticket.BUG
+	}
+	for _, t := range splitByComma(incident) {
+		typeMap[t] = ticket.INCIDENT
+	}
+	return typeMap
+}
+
+func newWorkItemStatusMap(rule *models.AzureTransformationRule) map[string]string {
+	todo, inProgress, done := DEFAULT_ISSUE_STATUS_TODO, DEFAULT_ISSUE_STATUS_IN_PROGRESS, DEFAULT_ISSUE_STATUS_DONE
+	if rule != nil {
+		if rule.IssueStatusTodo != "" {
+			todo = rule.IssueStatusTodo
+		}
+		if rule.IssueStatusInProgress != "" {
+			inProgress = rule.IssueStatusInProgress
+		}
+		if rule.IssueStatusDone != "" {
+			done = rule.IssueStatusDone
+		}
+	}
+	statusMap := make(map[string]string)
+	for _, s := range splitByComma(todo) {
+		statusMap[s] = ticket.TODO
+	}
+	for _, s := range splitByComma(inProgress) {
+		statusMap[s] = ticket.IN_PROGRESS
+	}
+	for _, s := range splitByComma(done) {
+		statusMap[s] = ticket.DONE
+	}
+	return statusMap
+}
@@ -0,0 +1,57 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"testing"
+
+	"github.com/apache/incubator-devlake/models/domainlayer/ticket"
+	"github.com/apache/incubator-devlake/plugins/azure/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewWorkItemTypeMap(t *testing.T) {
+	typeMap := newWorkItemTypeMap(nil)
+	assert.Equal(t, ticket.REQUIREMENT, typeMap["User Story"])
+	assert.Equal(t, ticket.REQUIREMENT, typeMap["Product Backlog Item"])
+	assert.Equal(t, ticket.BUG, typeMap["Bug"])
+	assert.Equal(t, ticket.TASK, typeMap["Task"])
+
+	typeMap = newWorkItemTypeMap(&models.AzureTransformationRule{
+		IssueTypeBug:      "Bug, Defect",
+		IssueTypeIncident: "Incident",
+	})
+	assert.Equal(t, ticket.BUG, typeMap["Defect"])
+	assert.Equal(t, ticket.INCIDENT, typeMap["Incident"])
+	assert.Equal(t, ticket.REQUIREMENT, typeMap["Epic"])
+}
+
+func TestNewWorkItemStatusMap(t *testing.T) {
+	statusMap := newWorkItemStatusMap(nil)
+	assert.Equal(t, ticket.TODO, statusMap["New"])
+	assert.Equal(t, ticket.IN_PROGRESS, statusMap["Active"])
+	assert.Equal(t, ticket.DONE, statusMap["Closed"])
+
+	statusMap = newWorkItemStatusMap(&models.AzureTransformationRule{
+		IssueStatusDone: "Closed,Released",
+	})
+	assert.Equal(t, ticket.DONE, statusMap["Released"])
+	_, ok := statusMap["Removed"]
+	assert.False(t, ok)
+	assert.Equal(t, ticket.TODO, statusMap["To Do"])
+}
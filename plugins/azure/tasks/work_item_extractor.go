@@ -0,0 +1,137 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/apache/incubator-devlake/errors"
+	"github.com/apache/incubator-devlake/plugins/azure/models"
+	"github.com/apache/incubator-devlake/plugins/core"
+	"github.com/apache/incubator-devlake/plugins/helper"
+)
+
+type AzureApiWorkItem struct {
+	Id     int `json:"id"`
+	Rev    int `json:"rev"`
+	Fields struct {
+		TeamProject   string            `json:"System.TeamProject"`
+		WorkItemType  string            `json:"System.WorkItemType"`
+		State         string            `json:"System.State"`
+		Reason        string            `json:"System.Reason"`
+		Title         string            `json:"System.Title"`
+		Description   string            `json:"System.Description"`
+		AreaPath      string            `json:"System.AreaPath"`
+		IterationPath string            `json:"System.IterationPath"`
+		Parent        int               `json:"System.Parent"`
+		AssignedTo    *AzureApiIdentity `json:"System.AssignedTo"`
+		CreatedBy     *AzureApiIdentity `json:"System.CreatedBy"`
+		CreatedDate   *time.Time        `json:"System.CreatedDate"`
+		ChangedDate   *time.Time        `json:"System.ChangedDate"`
+		ResolvedDate  *time.Time        `json:"Microsoft.VSTS.Common.ResolvedDate"`
+		ClosedDate    *time.Time        `json:"Microsoft.VSTS.Common.ClosedDate"`
+		Priority      int               `json:"Microsoft.VSTS.Common.Priority"`
+		Severity      string            `json:"Microsoft.VSTS.Common.Severity"`
+		// StoryPoints is used by the agile process while Effort is used by the scrum process
+		StoryPoints float64 `json:"Microsoft.VSTS.Scheduling.StoryPoints"`
+		Effort      float64 `json:"Microsoft.VSTS.Scheduling.Effort"`
+	} `json:"fields"`
+	Links struct {
+		Html struct {
+			Href string `json:"href"`
+		} `json:"html"`
+	} `json:"_links"`
+	Url string `json:"url"`
+}
+
+var ExtractApiWorkItemsMeta = core.SubTaskMeta{
+	Name:             "extractApiWorkItems",
+	EntryPoint:       ExtractApiWorkItems,
+	EnabledByDefault: true,
+	Description:      "Extract raw work items data into tool layer table azure_work_items",
+	DomainTypes:      []string{core.DOMAIN_TYPE_TICKET},
+}
+
+func ExtractApiWorkItems(taskCtx core.SubTaskContext) errors.Error {
+	rawDataSubTaskArgs, data := CreateProjectRawDataSubTaskArgs(taskCtx, RAW_WORK_ITEM_TABLE)
+	extractor, err := helper.NewApiExtractor(helper.ApiExtractorArgs{
+		RawDataSubTaskArgs: *rawDataSubTaskArgs,
+		Extract: func(row *helper.RawData) ([]interface{}, errors.Error) {
+			apiWorkItem := &AzureApiWorkItem{}
+			err := errors.Convert(json.Unmarshal(row.Data, apiWorkItem))
+			if err != nil {
+				return nil, err
+			}
+			fields := &apiWorkItem.Fields
+			workItem := &models.AzureWorkItem{
+				ConnectionId:  data.Options.ConnectionId,
+				AzureId:       apiWorkItem.Id,
+				ProjectName:   fields.TeamProject,
+				Rev:           apiWorkItem.Rev,
+				Title:         fields.Title,
+				Description:   fields.Description,
+				Type:          fields.WorkItemType,
+				State:         fields.State,
+				Reason:        fields.Reason,
+				AreaPath:      fields.AreaPath,
+				IterationPath: fields.IterationPath,
+				Severity:      fields.Severity,
+				StoryPoints:   fields.StoryPoints,
+				ParentId:      fields.Parent,
+				CreatedDate:   fields.CreatedDate,
+				ChangedDate:   fields.ChangedDate,
+				ResolvedDate:  fields.ResolvedDate,
+				ClosedDate:    fields.ClosedDate,
+				Url:           apiWorkItem.Links.Html.Href,
+			}
+			if workItem.StoryPoints == 0 {
+				workItem.StoryPoints = fields.Effort
+			}
+			if fields.Priority != 0 {
+				workItem.Priority = fmt.Sprintf("%d", fields.Priority)
+			}
+			if workItem.Url == "" {
+				workItem.Url = apiWorkItem.Url
+			}
+			results := make([]interface{}, 0, 3)
+			if fields.AssignedTo != nil {
+				workItem.AssignedToId = fields.AssignedTo.Id
+				workItem.AssignedToName = fields.AssignedTo.DisplayName
+				if account := fields.AssignedTo.ToAccount(data.Options.ConnectionId); account != nil {
+					results = append(results, account)
+				}
+			}
+			if fields.CreatedBy != nil {
+				workItem.CreatedById = fields.CreatedBy.Id
+				workItem.CreatedByName = fields.CreatedBy.DisplayName
+				if account := fields.CreatedBy.ToAccount(data.Options.ConnectionId); account != nil {
+					results = append(results, account)
+				}
+			}
+			results = append(results, workItem)
+			return results, nil
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	return extractor.Execute()
+}
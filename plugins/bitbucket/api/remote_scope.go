@@ -0,0 +1,172 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/apache/incubator-devlake/errors"
+	"github.com/apache/incubator-devlake/plugins/bitbucket/models"
+	"github.com/apache/incubator-devlake/plugins/bitbucket/tasks"
+	"github.com/apache/incubator-devlake/plugins/core"
+	"github.com/apache/incubator-devlake/plugins/helper"
+)
+
+const (
+	TypeGroup = "group"
+	TypeScope = "scope"
+)
+
+type RemoteScopesChild struct {
+	Type     string      `json:"type"`
+	ParentId *string     `json:"parentId"`
+	Id       string      `json:"id"`
+	Name     string      `json:"name"`
+	Data     interface{} `json:"data"`
+}
+
+type RemoteScopesOutput struct {
+	Children      []RemoteScopesChild `json:"children"`
+	NextPageToken string              `json:"nextPageToken"`
+}
+
+type workspacesResponse struct {
+	Next   string `json:"next"`
+	Values []struct {
+		Workspace struct {
+			Slug string `json:"slug"`
+			Name string `json:"name"`
+		} `json:"workspace"`
+	} `json:"values"`
+}
+
+type reposResponse struct {
+	Next   string                   `json:"next"`
+	Values []tasks.BitbucketApiRepo `json:"values"`
+}
+
+// RemoteScopes list the workspaces of the connection, or the repos of the workspace specified by groupId
+// @Summary list workspaces or repos from Bitbucket
+// @Description list the workspaces the user has access to, or the repos under the workspace when groupId is given
+// @Tags plugins/bitbucket
+// @Param connectionId path int true "connection ID"
+// @Param groupId query string false "workspace slug, list workspaces if empty"
+// @Param pageToken query string false "page token returned by the previous request"
+// @Param pageSize query int false "page size, default 50"
+// @Success 200  {object} RemoteScopesOutput
+// @Failure 400  {object} shared.ApiBody "Bad Request"
+// @Failure 500  {object} shared.ApiBody "Internal Error"
+// @Router /plugins/bitbucket/connections/{connectionId}/remote-scopes [GET]
+func RemoteScopes(input *core.ApiResourceInput) (*core.ApiResourceOutput, errors.Error) {
+	connectionId, _ := extractParam(input.Params)
+	if connectionId == 0 {
+		return nil, errors.BadInput.New("invalid connectionId")
+	}
+	connection := &models.BitbucketConnection{}
+	err := connectionHelper.FirstById(connection, connectionId)
+	if err != nil {
+		return nil, err
+	}
+	apiClient, err := newApiClient(connection)
+	if err != nil {
+		return nil, err
+	}
+	groupId := input.Query.Get("groupId")
+	page := 1
+	if pageToken := input.Query.Get("pageToken"); pageToken != "" {
+		page, err = errors.Convert01(strconv.Atoi(pageToken))
+		if err != nil {
+			return nil, errors.BadInput.Wrap(err, "invalid pageToken")
+		}
+	}
+	pageSize, _ := strconv.Atoi(input.Query.Get("pageSize"))
+	if pageSize <= 0 {
+		pageSize = 50
+	}
+	query := url.Values{}
+	query.Set("page", strconv.Itoa(page))
+	query.Set("pagelen", strconv.Itoa(pageSize))
+
+	output := RemoteScopesOutput{Children: []RemoteScopesChild{}}
+	var next string
+	if groupId == "" {
+		var workspaces workspacesResponse
+		err = getRemote(apiClient, "user/permissions/workspaces", query, &workspaces)
+		if err != nil {
+			return nil, err
+		}
+		for _, value := range workspaces.Values {
+			output.Children = append(output.Children, RemoteScopesChild{
+				Type: TypeGroup,
+				Id:   value.Workspace.Slug,
+				Name: value.Workspace.Name,
+			})
+		}
+		next = workspaces.Next
+	} else {
+		var repos reposResponse
+		err = getRemote(apiClient, fmt.Sprintf("repositories/%s", url.PathEscape(groupId)), query, &repos)
+		if err != nil {
+			return nil, err
+		}
+		for i := range repos.Values {
+			repo := convertApiRepoToScope(&repos.Values[i], connectionId)
+			output.Children = append(output.Children, RemoteScopesChild{
+				Type:     TypeScope,
+				ParentId: &groupId,
+				Id:       repo.BitbucketId,
+				Name:     repo.Name,
+				Data:     repo,
+			})
+		}
+		next = repos.Next
+	}
+	if next != "" {
+		output.NextPageToken = strconv.Itoa(page + 1)
+	}
+	return &core.ApiResourceOutput{Body: output, Status: http.StatusOK}, nil
+}
+
+func getRemote(apiClient helper.ApiClientGetter, path string, query url.Values, result interface{}) errors.Error {
+	res, err := apiClient.Get(path, query, nil)
+	if err != nil {
+		return err
+	}
+	if res.StatusCode != http.StatusOK {
+		return errors.HttpStatus(res.StatusCode).New(fmt.Sprintf("unexpected status code when requesting %s", path))
+	}
+	return helper.UnmarshalResponse(res, result)
+}
+
+func convertApiRepoToScope(repo *tasks.BitbucketApiRepo, connectionId uint64) *models.BitbucketRepo {
+	return &models.BitbucketRepo{
+		ConnectionId: connectionId,
+		BitbucketId:  repo.FullName,
+		Name:         repo.FullName,
+		HTMLUrl:      repo.Links.Html.Href,
+		Description:  repo.Description,
+		OwnerId:      repo.Owner.AccountId,
+		Language:     repo.Language,
+		CloneUrl:     repo.GetHttpsCloneUrl(),
+		CreatedDate:  repo.CreatedAt,
+		UpdatedDate:  repo.UpdatedAt,
+	}
+}
@@ -41,10 +41,10 @@ func TestCommentDataFlow(t *testing.T) {
 			ConnectionId: 1,
 			Owner:        "panjf2000",
 			Repo:         "ants",
-			TransformationRules: models.TransformationRules{
-				IssueStatusTODO:       []string{"new", "open"},
-				IssueStatusINPROGRESS: []string{"on hold"},
-				IssueStatusDONE:       []string{"closed"},
+			BitbucketTransformationRule: &models.BitbucketTransformationRule{
+				IssueStatusTodo:       "new,open",
+				IssueStatusInProgress: "on hold",
+				IssueStatusDone:       "closed",
 			},
 		},
 		Repo: bitbucketRepository,
@@ -40,10 +40,10 @@ func TestIssueDataFlow(t *testing.T) {
 			ConnectionId: 1,
 			Owner:        "panjf2000",
 			Repo:         "ants",
-			TransformationRules: models.TransformationRules{
-				IssueStatusTODO:       []string{"new", "open", "wantfix"},
-				IssueStatusINPROGRESS: []string{"on hold", "resolved"},
-				IssueStatusDONE:       []string{"closed", "invalid"},
+			BitbucketTransformationRule: &models.BitbucketTransformationRule{
+				IssueStatusTodo:       "new,open,wantfix",
+				IssueStatusInProgress: "on hold,resolved",
+				IssueStatusDone:       "closed,invalid",
 			},
 		},
 		Repo: bitbucketRepository,
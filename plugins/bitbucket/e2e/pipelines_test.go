@@ -37,9 +37,10 @@ func TestBitbucketPipelineDataFlow(t *testing.T) {
 	}
 	taskData := &tasks.BitbucketTaskData{
 		Options: &tasks.BitbucketOptions{
-			ConnectionId: 1,
-			Owner:        "thenicetgp",
-			Repo:         "ptest",
+			ConnectionId:                1,
+			Owner:                       "thenicetgp",
+			Repo:                        "ptest",
+			BitbucketTransformationRule: &models.BitbucketTransformationRule{},
 		},
 		Repo: bitbucketRepository,
 	}
@@ -84,4 +85,26 @@ func TestBitbucketPipelineDataFlow(t *testing.T) {
 			"environment",
 		},
 	)
+
+	// verify conversion with transformation rule, pipelines not matching the pattern keep their type
+	taskData.Options.BitbucketTransformationRule = &models.BitbucketTransformationRule{
+		DeploymentPattern: "^(main|pipeline)$",
+		ProductionPattern: "main",
+	}
+	dataflowTester.FlushTabler(&devops.CiCDPipelineCommit{})
+	dataflowTester.FlushTabler(&devops.CICDPipeline{})
+	dataflowTester.Subtask(tasks.ConvertPipelineMeta, taskData)
+	dataflowTester.VerifyTable(
+		devops.CICDPipeline{},
+		"./snapshot_tables/cicd_pipelines_with_rule.csv",
+		[]string{
+			"id",
+			"name",
+			"result",
+			"status",
+			"type",
+			"duration_sec",
+			"environment",
+		},
+	)
 }
@@ -38,10 +38,10 @@ func TestRepoDataFlow(t *testing.T) {
 	}
 	taskData := &tasks.BitbucketTaskData{
 		Options: &tasks.BitbucketOptions{
-			ConnectionId:        1,
-			Owner:               "panjf2000",
-			Repo:                "ants",
-			TransformationRules: models.TransformationRules{},
+			ConnectionId:                1,
+			Owner:                       "panjf2000",
+			Repo:                        "ants",
+			BitbucketTransformationRule: new(models.BitbucketTransformationRule),
 		},
 		Repo: bitbucketRepository,
 	}
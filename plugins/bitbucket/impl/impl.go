@@ -37,10 +37,24 @@ var _ core.PluginApi = (*Bitbucket)(nil)
 var _ core.PluginModel = (*Bitbucket)(nil)
 var _ core.PluginMigration = (*Bitbucket)(nil)
 var _ core.PluginBlueprintV100 = (*Bitbucket)(nil)
+var _ core.DataSourcePluginBlueprintV200 = (*Bitbucket)(nil)
 var _ core.CloseablePluginTask = (*Bitbucket)(nil)
+var _ core.PluginSource = (*Bitbucket)(nil)
 
 type Bitbucket string
 
+func (plugin Bitbucket) Connection() interface{} {
+	return &models.BitbucketConnection{}
+}
+
+func (plugin Bitbucket) Scope() interface{} {
+	return &models.BitbucketRepo{}
+}
+
+func (plugin Bitbucket) TransformationRule() interface{} {
+	return &models.BitbucketTransformationRule{}
+}
+
 func (plugin Bitbucket) Init(basicRes core.BasicRes) errors.Error {
 	api.Init(basicRes)
 	return nil
@@ -58,6 +72,8 @@ func (plugin Bitbucket) GetTablesInfo() []dal.Tabler {
 		&models.BitbucketPipeline{},
 		&models.BitbucketRepo{},
 		&models.BitbucketRepoCommit{},
+		&models.BitbucketDeployment{},
+		&models.BitbucketTransformationRule{},
 	}
 }
 
@@ -108,6 +124,10 @@ func (plugin Bitbucket) PrepareTaskData(taskCtx core.TaskContext, options map[st
 	if err != nil {
 		return nil, err
 	}
+	err = EnrichOptions(taskCtx, op)
+	if err != nil {
+		return nil, err
+	}
 	connectionHelper := helper.NewConnectionHelper(
 		taskCtx,
 		nil,
@@ -141,6 +161,10 @@ func (plugin Bitbucket) MakePipelinePlan(connectionId uint64, scope []*core.Blue
 	return api.MakePipelinePlan(plugin.SubTaskMetas(), connectionId, scope)
 }
 
+func (plugin Bitbucket) MakeDataSourcePipelinePlanV200(connectionId uint64, scopes []*core.BlueprintScopeV200, syncPolicy core.BlueprintSyncPolicy) (pp core.PipelinePlan, sc []core.Scope, err errors.Error) {
+	return api.MakeDataSourcePipelinePlanV200(plugin.SubTaskMetas(), connectionId, scopes, &syncPolicy)
+}
+
 func (plugin Bitbucket) ApiResources() map[string]map[string]core.ApiResourceHandler {
 	return map[string]map[string]core.ApiResourceHandler{
 		"test": {
@@ -155,6 +179,28 @@ func (plugin Bitbucket) ApiResources() map[string]map[string]core.ApiResourceHan
 			"DELETE": api.DeleteConnection,
 			"GET":    api.GetConnection,
 		},
+		"connections/:connectionId/scopes/*repoId": {
+			"GET":   api.GetScope,
+			"PATCH": api.UpdateScope,
+		},
+		"connections/:connectionId/scopes": {
+			"GET": api.GetScopeList,
+			"PUT": api.PutScope,
+		},
+		"connections/:connectionId/remote-scopes": {
+			"GET": api.RemoteScopes,
+		},
+		"transformation_rules": {
+			"POST": api.CreateTransformationRule,
+			"GET":  api.GetTransformationRuleList,
+		},
+		"transformation_rules/:id": {
+			"PATCH": api.UpdateTransformationRule,
+			"GET":   api.GetTransformationRule,
+		},
+		"connections/:connectionId/proxy/rest/*path": {
+			"GET": api.Proxy,
+		},
 	}
 }
 
@@ -166,3 +212,28 @@ func (plugin Bitbucket) Close(taskCtx core.TaskContext) errors.Error {
 	data.ApiClient.Release()
 	return nil
 }
+
+// EnrichOptions loads the transformation rule of the repo when it is not given in the options
+func EnrichOptions(taskCtx core.TaskContext, op *tasks.BitbucketOptions) errors.Error {
+	db := taskCtx.GetDal()
+	var repo models.BitbucketRepo
+	err := db.First(&repo, dal.Where("connection_id = ? AND bitbucket_id = ?", op.ConnectionId, fmt.Sprintf("%s/%s", op.Owner, op.Repo)))
+	if err != nil && !db.IsErrorNotFound(err) {
+		return errors.Default.Wrap(err, fmt.Sprintf("fail to find repo %s/%s", op.Owner, op.Repo))
+	}
+	if op.TransformationRuleId == 0 {
+		op.TransformationRuleId = repo.TransformationRuleId
+	}
+	if op.BitbucketTransformationRule == nil && op.TransformationRuleId != 0 {
+		var transformationRule models.BitbucketTransformationRule
+		err = db.First(&transformationRule, dal.Where("id = ?", op.TransformationRuleId))
+		if err != nil && !db.IsErrorNotFound(err) {
+			return errors.BadInput.Wrap(err, "fail to get transformationRule")
+		}
+		op.BitbucketTransformationRule = &transformationRule
+	}
+	if op.BitbucketTransformationRule == nil {
+		op.BitbucketTransformationRule = new(models.BitbucketTransformationRule)
+	}
+	return nil
+}
@@ -38,13 +38,6 @@ type BoardResponse struct {
 	Title string
 	Value string
 }
-type TransformationRules struct {
-	IssueStatusTODO       []string `mapstructure:"issueStatusTodo" json:"issueStatusTodo"`
-	IssueStatusINPROGRESS []string `mapstructure:"issueStatusInProgress" json:"issueStatusInProgress"`
-	IssueStatusDONE       []string `mapstructure:"issueStatusDone" json:"issueStatusDone"`
-	IssueStatusOTHER      []string `mapstructure:"issueStatusOther" json:"issueStatusOther"`
-}
-
 type BitbucketConnection struct {
 	helper.RestConnection `mapstructure:",squash"`
 	helper.BasicAuth      `mapstructure:",squash"`
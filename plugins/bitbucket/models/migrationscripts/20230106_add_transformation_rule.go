@@ -0,0 +1,48 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migrationscripts
+
+import (
+	"github.com/apache/incubator-devlake/errors"
+	"github.com/apache/incubator-devlake/helpers/migrationhelper"
+	"github.com/apache/incubator-devlake/plugins/bitbucket/models/migrationscripts/archived"
+	"github.com/apache/incubator-devlake/plugins/core"
+)
+
+type bitbucketRepo20230106 struct {
+	TransformationRuleId uint64
+	CloneUrl             string `gorm:"type:varchar(255)"`
+}
+
+func (bitbucketRepo20230106) TableName() string {
+	return "_tool_bitbucket_repos"
+}
+
+type addTransformationRule20230106 struct{}
+
+func (*addTransformationRule20230106) Up(basicRes core.BasicRes) errors.Error {
+	return migrationhelper.AutoMigrateTables(basicRes, &bitbucketRepo20230106{}, &archived.BitbucketTransformationRule{})
+}
+
+func (*addTransformationRule20230106) Version() uint64 {
+	return 20230106120301
+}
+
+func (*addTransformationRule20230106) Name() string {
+	return "add table _tool_bitbucket_transformation_rules, add transformation_rule_id&clone_url to _tool_bitbucket_repos"
+}
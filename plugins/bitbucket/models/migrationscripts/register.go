@@ -29,5 +29,6 @@ func All() []core.MigrationScript {
 		new(addPrCommits20221008),
 		new(addDeployment20221013),
 		new(addRepoIdAndCommitShaField20221014),
+		new(addTransformationRule20230106),
 	}
 }
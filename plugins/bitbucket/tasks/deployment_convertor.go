@@ -42,6 +42,13 @@ var ConvertDeploymentMeta = core.SubTaskMeta{
 func ConvertDeployments(taskCtx core.SubTaskContext) errors.Error {
 	db := taskCtx.GetDal()
 	data := taskCtx.GetData().(*BitbucketTaskData)
+	deploymentPattern := data.Options.DeploymentPattern
+	productionPattern := data.Options.ProductionPattern
+	regexEnricher := helper.NewRegexEnricher()
+	err := regexEnricher.AddRegexp(deploymentPattern, productionPattern)
+	if err != nil {
+		return err
+	}
 
 	cursor, err := db.Cursor(dal.From(bitbucketModels.BitbucketDeployment{}))
 	if err != nil {
@@ -88,10 +95,12 @@ func ConvertDeployments(taskCtx core.SubTaskContext) errors.Error {
 					InProgress: []string{bitbucketModels.IN_PROGRESS, bitbucketModels.PENDING, bitbucketModels.BUILDING},
 					Default:    devops.DONE,
 				}, bitbucketDeployment.Status),
-				Type:         bitbucketDeployment.Type,
 				StartedDate:  *startedAt,
 				FinishedDate: bitbucketDeployment.CompletedOn,
 			}
+			domainDeployment.Type, domainDeployment.Environment = enrichCicdType(
+				regexEnricher, deploymentPattern, productionPattern, bitbucketDeployment.Name, bitbucketDeployment.Type,
+			)
 			// rebuild the FinishedDate and DurationSec by Status
 			finishedAt := time.Now()
 			if domainDeployment.Status != devops.DONE {
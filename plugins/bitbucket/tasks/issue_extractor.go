@@ -20,6 +20,7 @@ package tasks
 import (
 	"encoding/json"
 	"github.com/apache/incubator-devlake/models/domainlayer/ticket"
+	"strings"
 	"time"
 
 	"github.com/apache/incubator-devlake/errors"
@@ -70,7 +71,7 @@ var ExtractApiIssuesMeta = core.SubTaskMeta{
 
 func ExtractApiIssues(taskCtx core.SubTaskContext) errors.Error {
 	data := taskCtx.GetData().(*BitbucketTaskData)
-	config := data.Options.TransformationRules
+	config := data.Options.BitbucketTransformationRule
 	issueStatusMap, err := newIssueStatusMap(config)
 	if err != nil {
 		return nil
@@ -177,19 +178,33 @@ func convertBitbucketIssue(issue *IssuesResponse, connectionId uint64, repositor
 	return bitbucketIssue, nil
 }
 
-func newIssueStatusMap(config models.TransformationRules) (map[string]string, errors.Error) {
+func newIssueStatusMap(config *models.BitbucketTransformationRule) (map[string]string, errors.Error) {
 	issueStatusMap := make(map[string]string, 3)
-	for _, state := range config.IssueStatusTODO {
+	if config == nil {
+		return issueStatusMap, nil
+	}
+	for _, state := range splitIssueStatus(config.IssueStatusTodo) {
 		issueStatusMap[state] = ticket.TODO
 	}
-	for _, state := range config.IssueStatusINPROGRESS {
+	for _, state := range splitIssueStatus(config.IssueStatusInProgress) {
 		issueStatusMap[state] = ticket.IN_PROGRESS
 	}
-	for _, state := range config.IssueStatusDONE {
+	for _, state := range splitIssueStatus(config.IssueStatusDone) {
 		issueStatusMap[state] = ticket.DONE
 	}
-	for _, state := range config.IssueStatusOTHER {
+	for _, state := range splitIssueStatus(config.IssueStatusOther) {
 		issueStatusMap[state] = ticket.OTHER
 	}
 	return issueStatusMap, nil
 }
+
+func splitIssueStatus(states string) []string {
+	var result []string
+	for _, state := range strings.Split(states, ",") {
+		state = strings.TrimSpace(state)
+		if state != "" {
+			result = append(result, state)
+		}
+	}
+	return result
+}
@@ -42,6 +42,13 @@ var ConvertPipelineMeta = core.SubTaskMeta{
 func ConvertPipelines(taskCtx core.SubTaskContext) errors.Error {
 	db := taskCtx.GetDal()
 	data := taskCtx.GetData().(*BitbucketTaskData)
+	deploymentPattern := data.Options.DeploymentPattern
+	productionPattern := data.Options.ProductionPattern
+	regexEnricher := helper.NewRegexEnricher()
+	err := regexEnricher.AddRegexp(deploymentPattern, productionPattern)
+	if err != nil {
+		return err
+	}
 
 	cursor, err := db.Cursor(dal.From(bitbucketModels.BitbucketPipeline{}))
 	if err != nil {
@@ -95,11 +102,13 @@ func ConvertPipelines(taskCtx core.SubTaskContext) errors.Error {
 					InProgress: []string{bitbucketModels.IN_PROGRESS, bitbucketModels.PENDING, bitbucketModels.BUILDING},
 					Default:    devops.DONE,
 				}, bitbucketPipeline.Status),
-				Type:         "CI/CD",
 				CreatedDate:  createdAt,
 				DurationSec:  bitbucketPipeline.DurationInSeconds,
 				FinishedDate: bitbucketPipeline.BitbucketCompleteOn,
 			}
+			domainPipeline.Type, domainPipeline.Environment = enrichCicdType(
+				regexEnricher, deploymentPattern, productionPattern, bitbucketPipeline.RefName, "CI/CD",
+			)
 			results = append(results, domainPipelineCommit, domainPipeline)
 			return results, nil
 		},
@@ -111,3 +120,12 @@ func ConvertPipelines(taskCtx core.SubTaskContext) errors.Error {
 
 	return converter.Execute()
 }
+
+// enrichCicdType returns DEPLOYMENT and the matched environment when name matches the deploymentPattern,
+// otherwise the originalType is kept and the environment is left empty
+func enrichCicdType(regexEnricher *helper.RegexEnricher, deploymentPattern, productionPattern, name, originalType string) (string, string) {
+	if deploymentPattern == "" || regexEnricher.GetEnrichResult(deploymentPattern, name, devops.DEPLOYMENT) == "" {
+		return originalType, ""
+	}
+	return devops.DEPLOYMENT, regexEnricher.GetEnrichResult(productionPattern, name, devops.PRODUCTION)
+}
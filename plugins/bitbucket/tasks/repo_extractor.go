@@ -66,6 +66,16 @@ type BitbucketApiRepo struct {
 	} `json:"links"`
 }
 
+// GetHttpsCloneUrl returns the https clone url of the repo, or empty string if there is none
+func (repo *BitbucketApiRepo) GetHttpsCloneUrl() string {
+	for _, u := range repo.Links.Clone {
+		if u.Name == "https" {
+			return u.Href
+		}
+	}
+	return ""
+}
+
 func ExtractApiRepositories(taskCtx core.SubTaskContext) errors.Error {
 	data := taskCtx.GetData().(*BitbucketTaskData)
 	extractor, err := helper.NewApiExtractor(helper.ApiExtractorArgs{
@@ -96,15 +106,17 @@ func ExtractApiRepositories(taskCtx core.SubTaskContext) errors.Error {
 			}
 			results := make([]interface{}, 0, 1)
 			bitbucketRepository := &models.BitbucketRepo{
-				ConnectionId: data.Options.ConnectionId,
-				BitbucketId:  body.FullName,
-				Name:         body.FullName,
-				HTMLUrl:      body.Links.Html.Href,
-				Description:  body.Description,
-				OwnerId:      body.Owner.AccountId,
-				Language:     body.Language,
-				CreatedDate:  body.CreatedAt,
-				UpdatedDate:  body.UpdatedAt,
+				ConnectionId:         data.Options.ConnectionId,
+				BitbucketId:          body.FullName,
+				Name:                 body.FullName,
+				HTMLUrl:              body.Links.Html.Href,
+				Description:          body.Description,
+				TransformationRuleId: data.Options.TransformationRuleId,
+				OwnerId:              body.Owner.AccountId,
+				Language:             body.Language,
+				CloneUrl:             (*BitbucketApiRepo)(body).GetHttpsCloneUrl(),
+				CreatedDate:          body.CreatedAt,
+				UpdatedDate:          body.UpdatedAt,
 			}
 			data.Repo = bitbucketRepository
 
@@ -18,20 +18,24 @@ limitations under the License.
 package tasks
 
 import (
-	"github.com/apache/incubator-devlake/errors"
+	"fmt"
+	"strings"
 	"time"
 
+	"github.com/apache/incubator-devlake/errors"
+
 	"github.com/apache/incubator-devlake/plugins/bitbucket/models"
 	"github.com/apache/incubator-devlake/plugins/helper"
 )
 
 type BitbucketOptions struct {
-	ConnectionId               uint64   `json:"connectionId"`
-	Tasks                      []string `json:"tasks,omitempty"`
-	Since                      string
-	Owner                      string
-	Repo                       string
-	models.TransformationRules `mapstructure:"transformationRules" json:"transformationRules"`
+	ConnectionId                        uint64   `json:"connectionId" mapstructure:"connectionId,omitempty"`
+	Tasks                               []string `json:"tasks,omitempty" mapstructure:",omitempty"`
+	Since                               string   `json:"since" mapstructure:"since,omitempty"`
+	Owner                               string   `json:"owner" mapstructure:"owner,omitempty"`
+	Repo                                string   `json:"repo" mapstructure:"repo,omitempty"`
+	TransformationRuleId                uint64   `json:"transformationRuleId" mapstructure:"transformationRuleId,omitempty"`
+	*models.BitbucketTransformationRule `mapstructure:"transformationRules,omitempty" json:"transformationRules"`
 }
 
 type BitbucketTaskData struct {
@@ -42,21 +46,77 @@ type BitbucketTaskData struct {
 }
 
 func DecodeAndValidateTaskOptions(options map[string]interface{}) (*BitbucketOptions, errors.Error) {
+	op, err := DecodeTaskOptions(options)
+	if err != nil {
+		return nil, err
+	}
+	err = ValidateTaskOptions(op)
+	if err != nil {
+		return nil, err
+	}
+	return op, nil
+}
+
+func DecodeTaskOptions(options map[string]interface{}) (*BitbucketOptions, errors.Error) {
 	var op BitbucketOptions
-	err := helper.Decode(options, &op, nil)
+	err := helper.Decode(normalizeTransformationRules(options), &op, nil)
 	if err != nil {
-		return nil, errors.Default.New("could not decode options for Bitbucket execution")
+		return nil, errors.Default.Wrap(err, "could not decode options for Bitbucket execution")
 	}
+	return &op, nil
+}
+
+// normalizeTransformationRules joins the issue states which were configured as arrays before v2.0.0,
+// so both forms can be decoded into BitbucketTransformationRule
+func normalizeTransformationRules(options map[string]interface{}) map[string]interface{} {
+	rules, ok := options["transformationRules"].(map[string]interface{})
+	if !ok {
+		return options
+	}
+	normalizedRules := make(map[string]interface{}, len(rules))
+	for key, value := range rules {
+		normalizedRules[key] = value
+	}
+	for _, key := range []string{"issueStatusTodo", "issueStatusInProgress", "issueStatusDone", "issueStatusOther"} {
+		switch states := rules[key].(type) {
+		case []string:
+			normalizedRules[key] = strings.Join(states, ",")
+		case []interface{}:
+			joined := make([]string, 0, len(states))
+			for _, state := range states {
+				joined = append(joined, fmt.Sprint(state))
+			}
+			normalizedRules[key] = strings.Join(joined, ",")
+		}
+	}
+	normalizedOptions := make(map[string]interface{}, len(options))
+	for key, value := range options {
+		normalizedOptions[key] = value
+	}
+	normalizedOptions["transformationRules"] = normalizedRules
+	return normalizedOptions
+}
+
+func EncodeTaskOptions(op *BitbucketOptions) (map[string]interface{}, errors.Error) {
+	var result map[string]interface{}
+	err := helper.Decode(op, &result, nil)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func ValidateTaskOptions(op *BitbucketOptions) errors.Error {
 	if op.Owner == "" {
-		return nil, errors.Default.New("owner is required for Bitbucket execution")
+		return errors.Default.New("owner is required for Bitbucket execution")
 	}
 	if op.Repo == "" {
-		return nil, errors.Default.New("repo is required for Bitbucket execution")
+		return errors.Default.New("repo is required for Bitbucket execution")
 	}
 
 	// find the needed Bitbucket now
 	if op.ConnectionId == 0 {
-		return nil, errors.Default.New("Bitbucket connectionId is invalid")
+		return errors.Default.New("Bitbucket connectionId is invalid")
 	}
-	return &op, nil
+	return nil
 }
@@ -0,0 +1,55 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodeTaskOptionsIssueStatus(t *testing.T) {
+	// issue states in the array form of v1.0.0 blueprints
+	op, err := DecodeTaskOptions(map[string]interface{}{
+		"connectionId": 1,
+		"owner":        "panjf2000",
+		"repo":         "ants",
+		"transformationRules": map[string]interface{}{
+			"deploymentPattern": "main",
+			"issueStatusTodo":   []interface{}{"new", "open"},
+			"issueStatusDone":   []string{"closed", "invalid"},
+		},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, "main", op.DeploymentPattern)
+	assert.Equal(t, "new,open", op.IssueStatusTodo)
+	assert.Equal(t, "closed,invalid", op.IssueStatusDone)
+
+	// issue states in the string form of transformation rules
+	op, err = DecodeTaskOptions(map[string]interface{}{
+		"connectionId": 1,
+		"owner":        "panjf2000",
+		"repo":         "ants",
+		"transformationRules": map[string]interface{}{
+			"issueStatusInProgress": "on hold,resolved",
+		},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, "on hold,resolved", op.IssueStatusInProgress)
+	assert.Equal(t, []string{"on hold", "resolved"}, splitIssueStatus(op.IssueStatusInProgress))
+}
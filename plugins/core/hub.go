@@ -69,3 +69,22 @@ func FindPluginNameBySubPkgPath(subPkgPath string) (string, errors.Error) {
 	}
 	return "", errors.Default.New(fmt.Sprintf("Unable to find plugin for subPkgPath %s", subPkgPath))
 }
+
+// PipelineSubmitter queues a pipeline with the plan and returns its id
+type PipelineSubmitter func(name string, plan PipelinePlan, labels []string) (uint64, errors.Error)
+
+var pipelineSubmitter PipelineSubmitter
+
+// RegisterPipelineSubmitter is called by the framework once the pipelines could be queued
+func RegisterPipelineSubmitter(submitter PipelineSubmitter) {
+	pipelineSubmitter = submitter
+}
+
+// SubmitPipeline allows the apis of the plugins to run subtasks in the background, the pipeline keeps the status
+// of them and could be rerun or cancelled like any other pipeline
+func SubmitPipeline(name string, plan PipelinePlan, labels ...string) (uint64, errors.Error) {
+	if pipelineSubmitter == nil {
+		return 0, errors.Default.New("RegisterPipelineSubmitter have never been called.")
+	}
+	return pipelineSubmitter(name, plan, labels)
+}
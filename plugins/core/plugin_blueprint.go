@@ -152,6 +152,14 @@ type MetricPluginBlueprintV200 interface {
 	MakeMetricPluginPipelinePlanV200(projectName string, options json.RawMessage) (PipelinePlan, errors.Error)
 }
 
+// ScopedMetricPluginBlueprintV200 is implemented by the metric plugins which make tasks for the scopes
+// of the project, take refdiff as an example, it compares the refs of every repo. The scopes produced by the
+// data-source plugins are passed in since the project_mapping is only refreshed after the plan is made.
+type ScopedMetricPluginBlueprintV200 interface {
+	MetricPluginBlueprintV200
+	MakeScopedMetricPluginPipelinePlanV200(projectName string, options json.RawMessage, scopes []Scope) (PipelinePlan, errors.Error)
+}
+
 // CompositeDataSourcePluginBlueprintV200 is for unit test
 type CompositeDataSourcePluginBlueprintV200 interface {
 	PluginMeta
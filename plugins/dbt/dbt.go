@@ -45,6 +45,7 @@ func main() {
 	deferFlag := dbtCmd.Flags().BoolP("defer", "", false, "dbt defer")
 	noDefer := dbtCmd.Flags().BoolP("noDefer", "", false, "dbt no defer")
 	fullRefresh := dbtCmd.Flags().BoolP("fullRefresh", "", false, "dbt full refresh")
+	targetPath := dbtCmd.Flags().StringP("targetPath", "", "", "dbt target path where the artifacts are written")
 	dbtArgs := dbtCmd.Flags().StringSliceP("args", "a", []string{}, "dbt run args")
 	projectVars := make(map[string]string)
 	projectVars["event_min_id"] = "7581"
@@ -74,6 +75,7 @@ func main() {
 			"defer":          *deferFlag,
 			"noDefer":        *noDefer,
 			"fullRefresh":    *fullRefresh,
+			"targetPath":     *targetPath,
 		})
 	}
 	runner.RunCmd(dbtCmd)
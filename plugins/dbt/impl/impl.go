@@ -23,13 +23,16 @@ import (
 	"github.com/apache/incubator-devlake/plugins/helper"
 
 	"github.com/apache/incubator-devlake/plugins/core"
+	"github.com/apache/incubator-devlake/plugins/dbt/models"
+	"github.com/apache/incubator-devlake/plugins/dbt/models/migrationscripts"
 	"github.com/apache/incubator-devlake/plugins/dbt/tasks"
 )
 
 var (
-	_ core.PluginMeta  = (*Dbt)(nil)
-	_ core.PluginTask  = (*Dbt)(nil)
-	_ core.PluginModel = (*Dbt)(nil)
+	_ core.PluginMeta      = (*Dbt)(nil)
+	_ core.PluginTask      = (*Dbt)(nil)
+	_ core.PluginModel     = (*Dbt)(nil)
+	_ core.PluginMigration = (*Dbt)(nil)
 )
 
 type Dbt struct{}
@@ -41,12 +44,17 @@ func (plugin Dbt) Description() string {
 func (plugin Dbt) SubTaskMetas() []core.SubTaskMeta {
 	return []core.SubTaskMeta{
 		tasks.GitMeta,
+		tasks.DbtSeedMeta,
 		tasks.DbtConverterMeta,
+		tasks.DbtTestMeta,
 	}
 }
 
 func (plugin Dbt) GetTablesInfo() []dal.Tabler {
-	return []dal.Tabler{}
+	return []dal.Tabler{
+		&models.DbtInvocation{},
+		&models.DbtNodeResult{},
+	}
 }
 
 func (plugin Dbt) PrepareTaskData(taskCtx core.TaskContext, options map[string]interface{}) (interface{}, errors.Error) {
@@ -68,6 +76,10 @@ func (plugin Dbt) PrepareTaskData(taskCtx core.TaskContext, options map[string]i
 	}, nil
 }
 
+func (plugin Dbt) MigrationScripts() []core.MigrationScript {
+	return migrationscripts.All()
+}
+
 func (plugin Dbt) RootPkgPath() string {
 	return "github.com/apache/incubator-devlake/plugins/dbt"
 }
@@ -0,0 +1,42 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import (
+	"time"
+
+	"github.com/apache/incubator-devlake/models/common"
+)
+
+// DbtInvocation is a `dbt run/test/seed` command executed by the plugin, read from the `run_results.json`
+type DbtInvocation struct {
+	common.NoPKModel
+	InvocationId string `gorm:"primaryKey;type:varchar(100)"`
+	ProjectName  string `gorm:"type:varchar(255)"`
+	Command      string `gorm:"type:varchar(20);index"`
+	DbtVersion   string `gorm:"type:varchar(50)"`
+	GeneratedAt  *time.Time
+	ElapsedTime  float64
+	Success      bool
+	ResultCount  int
+	FailureCount int
+}
+
+func (DbtInvocation) TableName() string {
+	return "_tool_dbt_invocations"
+}
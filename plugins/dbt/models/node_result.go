@@ -0,0 +1,53 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import (
+	"time"
+
+	"github.com/apache/incubator-devlake/models/common"
+)
+
+// DbtNodeResult is the outcome of a model, test, seed or snapshot within an invocation, the node
+// attributes are taken from the `manifest.json` of the same invocation
+type DbtNodeResult struct {
+	common.NoPKModel
+	InvocationId     string `gorm:"primaryKey;type:varchar(100)"`
+	UniqueId         string `gorm:"primaryKey;type:varchar(255)"`
+	Command          string `gorm:"type:varchar(20)"`
+	ResourceType     string `gorm:"type:varchar(20);index"`
+	Name             string `gorm:"type:varchar(255)"`
+	PackageName      string `gorm:"type:varchar(255)"`
+	DatabaseName     string `gorm:"type:varchar(255)"`
+	SchemaName       string `gorm:"type:varchar(255)"`
+	RelationName     string `gorm:"type:varchar(255)"`
+	Materialized     string `gorm:"type:varchar(50)"`
+	OriginalFilePath string
+	Status           string `gorm:"type:varchar(20);index"`
+	Message          string
+	Failures         *int
+	RowsAffected     *int64
+	ExecutionTime    float64
+	ThreadId         string `gorm:"type:varchar(50)"`
+	StartedAt        *time.Time
+	CompletedAt      *time.Time
+}
+
+func (DbtNodeResult) TableName() string {
+	return "_tool_dbt_node_results"
+}
@@ -0,0 +1,199 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/apache/incubator-devlake/errors"
+	"github.com/apache/incubator-devlake/plugins/dbt/models"
+)
+
+// RunResults is the subset of dbt's `run_results.json` artifact used by the plugin
+type RunResults struct {
+	Metadata struct {
+		DbtVersion   string     `json:"dbt_version"`
+		GeneratedAt  *time.Time `json:"generated_at"`
+		InvocationId string     `json:"invocation_id"`
+	} `json:"metadata"`
+	Results     []RunResult `json:"results"`
+	ElapsedTime float64     `json:"elapsed_time"`
+	Args        struct {
+		Which string `json:"which"`
+	} `json:"args"`
+}
+
+// RunResult is the outcome of a single node
+type RunResult struct {
+	UniqueId        string  `json:"unique_id"`
+	Status          string  `json:"status"`
+	Message         *string `json:"message"`
+	Failures        *int    `json:"failures"`
+	ExecutionTime   float64 `json:"execution_time"`
+	ThreadId        string  `json:"thread_id"`
+	AdapterResponse struct {
+		RowsAffected *int64 `json:"rows_affected"`
+	} `json:"adapter_response"`
+	Timing []struct {
+		Name        string     `json:"name"`
+		StartedAt   *time.Time `json:"started_at"`
+		CompletedAt *time.Time `json:"completed_at"`
+	} `json:"timing"`
+}
+
+// Manifest is the subset of dbt's `manifest.json` artifact used by the plugin
+type Manifest struct {
+	Nodes map[string]ManifestNode `json:"nodes"`
+}
+
+// ManifestNode describes a model, test, seed or snapshot
+type ManifestNode struct {
+	Name             string `json:"name"`
+	ResourceType     string `json:"resource_type"`
+	PackageName      string `json:"package_name"`
+	Database         string `json:"database"`
+	Schema           string `json:"schema"`
+	RelationName     string `json:"relation_name"`
+	OriginalFilePath string `json:"original_file_path"`
+	Config           struct {
+		Materialized string `json:"materialized"`
+	} `json:"config"`
+}
+
+// failedStatuses are the node statuses which fail the subtask, `warn` and `skipped` are not among them
+var failedStatuses = map[string]bool{
+	"error":         true,
+	"fail":          true,
+	"runtime error": true,
+}
+
+// ReadRunResults parses the `run_results.json` at path
+func ReadRunResults(path string) (*RunResults, errors.Error) {
+	runResults := &RunResults{}
+	err := readJsonFile(path, runResults)
+	if err != nil {
+		return nil, err
+	}
+	return runResults, nil
+}
+
+// ReadManifest parses the `manifest.json` at path
+func ReadManifest(path string) (*Manifest, errors.Error) {
+	manifest := &Manifest{}
+	err := readJsonFile(path, manifest)
+	if err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+func readJsonFile(path string, v interface{}) errors.Error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return errors.Convert(err)
+	}
+	err = json.Unmarshal(content, v)
+	if err != nil {
+		return errors.Default.Wrap(err, fmt.Sprintf("failed to parse %s", path))
+	}
+	return nil
+}
+
+// ConvertRunResults turns the artifacts of an invocation into tool layer records, manifest is optional
+func ConvertRunResults(projectName string, command string, runResults *RunResults, manifest *Manifest) (*models.DbtInvocation, []*models.DbtNodeResult) {
+	if runResults.Args.Which != "" {
+		command = runResults.Args.Which
+	}
+	invocation := &models.DbtInvocation{
+		InvocationId: runResults.Metadata.InvocationId,
+		ProjectName:  projectName,
+		Command:      command,
+		DbtVersion:   runResults.Metadata.DbtVersion,
+		GeneratedAt:  runResults.Metadata.GeneratedAt,
+		ElapsedTime:  runResults.ElapsedTime,
+		ResultCount:  len(runResults.Results),
+	}
+	nodeResults := make([]*models.DbtNodeResult, 0, len(runResults.Results))
+	for _, result := range runResults.Results {
+		nodeResult := &models.DbtNodeResult{
+			InvocationId:  invocation.InvocationId,
+			UniqueId:      result.UniqueId,
+			Command:       command,
+			ResourceType:  strings.SplitN(result.UniqueId, ".", 2)[0],
+			Status:        result.Status,
+			Failures:      result.Failures,
+			RowsAffected:  result.AdapterResponse.RowsAffected,
+			ExecutionTime: result.ExecutionTime,
+			ThreadId:      result.ThreadId,
+		}
+		if result.Message != nil {
+			nodeResult.Message = *result.Message
+		}
+		// the node ran from the start of its first phase (compile) to the end of its last (execute)
+		for _, timing := range result.Timing {
+			if nodeResult.StartedAt == nil || (timing.StartedAt != nil && timing.StartedAt.Before(*nodeResult.StartedAt)) {
+				nodeResult.StartedAt = timing.StartedAt
+			}
+			if nodeResult.CompletedAt == nil || (timing.CompletedAt != nil && timing.CompletedAt.After(*nodeResult.CompletedAt)) {
+				nodeResult.CompletedAt = timing.CompletedAt
+			}
+		}
+		if manifest != nil {
+			if node, ok := manifest.Nodes[result.UniqueId]; ok {
+				nodeResult.Name = node.Name
+				nodeResult.ResourceType = node.ResourceType
+				nodeResult.PackageName = node.PackageName
+				nodeResult.DatabaseName = node.Database
+				nodeResult.SchemaName = node.Schema
+				nodeResult.RelationName = node.RelationName
+				nodeResult.Materialized = node.Config.Materialized
+				nodeResult.OriginalFilePath = node.OriginalFilePath
+			}
+		}
+		if nodeResult.Name == "" {
+			parts := strings.Split(result.UniqueId, ".")
+			nodeResult.Name = parts[len(parts)-1]
+		}
+		if failedStatuses[nodeResult.Status] {
+			invocation.FailureCount++
+		}
+		nodeResults = append(nodeResults, nodeResult)
+	}
+	invocation.Success = invocation.FailureCount == 0
+	return invocation, nodeResults
+}
+
+// FailedNodesError summarizes the failed nodes into an error, nil if all nodes succeeded
+func FailedNodesError(command string, nodeResults []*models.DbtNodeResult) errors.Error {
+	var failures []string
+	for _, nodeResult := range nodeResults {
+		if failedStatuses[nodeResult.Status] {
+			failures = append(failures, fmt.Sprintf("%s %s: %s", nodeResult.UniqueId, nodeResult.Status, nodeResult.Message))
+		}
+	}
+	if len(failures) == 0 {
+		return nil
+	}
+	sort.Strings(failures)
+	return errors.Default.New(fmt.Sprintf("dbt %s failed on %d node(s):\n%s", command, len(failures), strings.Join(failures, "\n")))
+}
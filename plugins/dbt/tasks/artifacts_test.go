@@ -0,0 +1,122 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const runResultsJson = `{
+  "metadata": {"dbt_version": "1.3.1", "generated_at": "2023-01-20T08:00:05.000000Z", "invocation_id": "inv-1"},
+  "results": [
+    {
+      "status": "success", "thread_id": "Thread-1", "execution_time": 1.5, "message": "SELECT 12", "failures": null,
+      "adapter_response": {"_message": "SELECT 12", "rows_affected": 12},
+      "timing": [
+        {"name": "compile", "started_at": "2023-01-20T08:00:01Z", "completed_at": "2023-01-20T08:00:02Z"},
+        {"name": "execute", "started_at": "2023-01-20T08:00:02Z", "completed_at": "2023-01-20T08:00:03Z"}
+      ],
+      "unique_id": "model.demo.my_first_dbt_model"
+    },
+    {
+      "status": "error", "thread_id": "Thread-1", "execution_time": 0.2, "message": "relation does not exist",
+      "adapter_response": {}, "timing": [], "unique_id": "model.demo.my_second_dbt_model"
+    },
+    {
+      "status": "warn", "thread_id": "Thread-1", "execution_time": 0.1, "message": "Got 1 result", "failures": 1,
+      "adapter_response": {}, "timing": [], "unique_id": "test.demo.not_null_my_first_dbt_model_id.5fb22c2710"
+    }
+  ],
+  "elapsed_time": 3.2,
+  "args": {"which": "run"}
+}`
+
+const manifestJson = `{
+  "nodes": {
+    "model.demo.my_first_dbt_model": {
+      "name": "my_first_dbt_model", "resource_type": "model", "package_name": "demo",
+      "database": "lake", "schema": "public", "relation_name": "\"lake\".\"public\".\"my_first_dbt_model\"",
+      "original_file_path": "models/example/my_first_dbt_model.sql", "config": {"materialized": "table"}
+    }
+  }
+}`
+
+func TestConvertRunResults(t *testing.T) {
+	runResults := &RunResults{}
+	assert.Nil(t, json.Unmarshal([]byte(runResultsJson), runResults))
+	manifest := &Manifest{}
+	assert.Nil(t, json.Unmarshal([]byte(manifestJson), manifest))
+
+	invocation, nodeResults := ConvertRunResults("demo", DBT_RUN, runResults, manifest)
+	assert.Equal(t, "inv-1", invocation.InvocationId)
+	assert.Equal(t, "run", invocation.Command)
+	assert.Equal(t, "1.3.1", invocation.DbtVersion)
+	assert.Equal(t, 3, invocation.ResultCount)
+	assert.Equal(t, 1, invocation.FailureCount)
+	assert.False(t, invocation.Success)
+	assert.Len(t, nodeResults, 3)
+
+	first := nodeResults[0]
+	assert.Equal(t, "my_first_dbt_model", first.Name)
+	assert.Equal(t, "table", first.Materialized)
+	assert.Equal(t, "public", first.SchemaName)
+	assert.Equal(t, int64(12), *first.RowsAffected)
+	assert.Equal(t, "2023-01-20T08:00:01Z", first.StartedAt.UTC().Format("2006-01-02T15:04:05Z"))
+	assert.Equal(t, "2023-01-20T08:00:03Z", first.CompletedAt.UTC().Format("2006-01-02T15:04:05Z"))
+
+	// nodes missing from the manifest are named after their unique id
+	second := nodeResults[1]
+	assert.Equal(t, "my_second_dbt_model", second.Name)
+	assert.Equal(t, "model", second.ResourceType)
+	assert.Nil(t, second.RowsAffected)
+	assert.Equal(t, "test", nodeResults[2].ResourceType)
+	assert.Equal(t, 1, *nodeResults[2].Failures)
+
+	err := FailedNodesError(DBT_RUN, nodeResults)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "model.demo.my_second_dbt_model error: relation does not exist")
+	assert.NotContains(t, err.Error(), "not_null_my_first_dbt_model_id")
+	assert.Nil(t, FailedNodesError(DBT_RUN, nodeResults[:1]))
+}
+
+func TestBuildDbtArgs(t *testing.T) {
+	options := &DbtOptions{
+		ProjectPath:    "/demo",
+		SelectedModels: []string{"a", "b"},
+		FullRefresh:    true,
+		Args:           []string{"--debug"},
+	}
+	args, err := buildDbtArgs(DBT_RUN, options)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"run", "--project-dir", "/demo", "--threads", "1", "--select", "a", "b", "--debug", "--full-refresh", "--profiles-dir", "/demo"}, args)
+
+	args, err = buildDbtArgs(DBT_SEED, options)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"seed", "--project-dir", "/demo", "--threads", "1", "--full-refresh", "--profiles-dir", "/demo"}, args)
+
+	args, err = buildDbtArgs(DBT_TEST, options)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"test", "--project-dir", "/demo", "--threads", "1", "--select", "a", "b", "--profiles-dir", "/demo"}, args)
+
+	assert.Equal(t, "/demo/target", targetPath(options))
+	options.TargetPath = "out"
+	assert.Equal(t, "/demo/out", targetPath(options))
+}
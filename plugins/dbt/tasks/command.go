@@ -0,0 +1,301 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"bufio"
+	"encoding/json"
+	"net"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/apache/incubator-devlake/errors"
+	"github.com/apache/incubator-devlake/plugins/core"
+	"github.com/apache/incubator-devlake/plugins/dbt/models"
+	"github.com/apache/incubator-devlake/plugins/helper"
+	"github.com/spf13/viper"
+)
+
+const (
+	DBT_SEED = "seed"
+	DBT_RUN  = "run"
+	DBT_TEST = "test"
+)
+
+// progressPattern matches the lines like `12:00:01  2 of 5 OK created sql table model ...`
+var progressPattern = regexp.MustCompile(`\b(\d+) of (\d+) (OK|PASS|WARN|FAIL|ERROR|SKIP)\b`)
+
+// prepareProject creates the `profiles.yml` and installs the dependencies once for all dbt subtasks
+func prepareProject(taskCtx core.SubTaskContext) errors.Error {
+	data := taskCtx.GetData().(*DbtTaskData)
+	if data.prepared {
+		return nil
+	}
+	log := taskCtx.GetLogger()
+	projectPath := data.Options.ProjectPath
+	projectName := data.Options.ProjectName
+	projectTarget := data.Options.ProjectTarget
+
+	defaultProfilesPath := filepath.Join(projectPath, "profiles.yml")
+	_, err := errors.Convert01(os.Stat(defaultProfilesPath))
+	// if profiles.yml not exist, create it manually
+	if err != nil {
+		dbUrl := taskCtx.GetConfig("DB_URL")
+		u, err := errors.Convert01(url.Parse(dbUrl))
+		if err != nil {
+			return err
+		}
+		dbType := u.Scheme
+		dbUsername := u.User.Username()
+		dbPassword, _ := u.User.Password()
+		dbServer, dbPort, _ := net.SplitHostPort(u.Host)
+		dbDataBase := u.Path[1:]
+		var dbSchema string
+		flag := strings.Compare(dbType, "mysql")
+		if flag == 0 {
+			// mysql database
+			dbSchema = dbDataBase
+		} else {
+			// other database
+			mapQuery, err := errors.Convert01(url.ParseQuery(u.RawQuery))
+			if err != nil {
+				return err
+			}
+			if value, ok := mapQuery["search_path"]; ok {
+				if len(value) < 1 {
+					return errors.Default.New("DB_URL search_path parses error")
+				}
+				dbSchema = value[0]
+			} else {
+				dbSchema = "public"
+			}
+		}
+		config := viper.New()
+		config.Set(projectName+".target", projectTarget)
+		config.Set(projectName+".outputs."+projectTarget+".type", dbType)
+		dbPortInt, _ := strconv.Atoi(dbPort)
+		config.Set(projectName+".outputs."+projectTarget+".port", dbPortInt)
+		config.Set(projectName+".outputs."+projectTarget+".password", dbPassword)
+		config.Set(projectName+".outputs."+projectTarget+".schema", dbSchema)
+		if flag == 0 {
+			config.Set(projectName+".outputs."+projectTarget+".server", dbServer)
+			config.Set(projectName+".outputs."+projectTarget+".username", dbUsername)
+			config.Set(projectName+".outputs."+projectTarget+".database", dbDataBase)
+		} else {
+			config.Set(projectName+".outputs."+projectTarget+".host", dbServer)
+			config.Set(projectName+".outputs."+projectTarget+".user", dbUsername)
+			config.Set(projectName+".outputs."+projectTarget+".dbname", dbDataBase)
+		}
+		err = errors.Convert(config.WriteConfigAs(defaultProfilesPath))
+		if err != nil {
+			return err
+		}
+	}
+	// if package.yml exist, install dbt dependencies
+	defaultPackagesPath := filepath.Join(projectPath, "packages.yml")
+	_, err = errors.Convert01(os.Stat(defaultPackagesPath))
+	if err == nil {
+		cmdDeps := exec.Command("dbt", "deps", "--project-dir", projectPath)
+		log.Info("dbt deps run script: %v", cmdDeps)
+		out, err := cmdDeps.CombinedOutput()
+		if err != nil {
+			return errors.Default.Wrap(err, "dbt deps failed: "+string(out))
+		}
+	}
+	data.prepared = true
+	return nil
+}
+
+// buildDbtArgs translates the options into the arguments of the dbt command, the model selection
+// only applies to `run` and `test`
+func buildDbtArgs(command string, options *DbtOptions) ([]string, errors.Error) {
+	//set default threads = 1, prevent dbt threads can not release, so occur zombie process
+	dbtExecParams := []string{command, "--project-dir", options.ProjectPath, "--threads", "1"}
+	if options.ProjectVars != nil {
+		jsonProjectVars, err := json.Marshal(options.ProjectVars)
+		if err != nil {
+			return nil, errors.Default.New("parameters vars json marshal error")
+		}
+		dbtExecParams = append(dbtExecParams, "--vars", string(jsonProjectVars))
+	}
+	if command != DBT_SEED {
+		if len(options.SelectedModels) > 0 {
+			dbtExecParams = append(dbtExecParams, "--select")
+			dbtExecParams = append(dbtExecParams, options.SelectedModels...)
+		}
+		if len(options.ExcludeModels) > 0 {
+			dbtExecParams = append(dbtExecParams, "--exclude")
+			dbtExecParams = append(dbtExecParams, options.ExcludeModels...)
+		}
+		if options.Selector != "" {
+			dbtExecParams = append(dbtExecParams, "--selector", options.Selector)
+		}
+		if options.Defer {
+			dbtExecParams = append(dbtExecParams, "--defer")
+		}
+		if options.NoDefer {
+			dbtExecParams = append(dbtExecParams, "--no-defer")
+		}
+	}
+	if command == DBT_RUN && options.Args != nil {
+		dbtExecParams = append(dbtExecParams, options.Args...)
+	}
+	if options.State != "" {
+		dbtExecParams = append(dbtExecParams, "--state", options.State)
+	}
+	if options.FailFast {
+		dbtExecParams = append(dbtExecParams, "--fail-fast")
+	}
+	if options.NoVersionCheck {
+		dbtExecParams = append(dbtExecParams, "--no-version-check")
+	}
+	if options.FullRefresh && command != DBT_TEST {
+		dbtExecParams = append(dbtExecParams, "--full-refresh")
+	}
+	if options.TargetPath != "" {
+		dbtExecParams = append(dbtExecParams, "--target-path", options.TargetPath)
+	}
+	if options.ProfilesPath != "" {
+		dbtExecParams = append(dbtExecParams, "--profiles-dir", options.ProfilesPath)
+	} else {
+		// default projectPath
+		dbtExecParams = append(dbtExecParams, "--profiles-dir", options.ProjectPath)
+	}
+	if options.Profile != "" {
+		dbtExecParams = append(dbtExecParams, "--profile", options.Profile)
+	}
+	return dbtExecParams, nil
+}
+
+// targetPath returns the directory where dbt writes its artifacts
+func targetPath(options *DbtOptions) string {
+	if options.TargetPath == "" {
+		return filepath.Join(options.ProjectPath, "target")
+	}
+	if filepath.IsAbs(options.TargetPath) {
+		return options.TargetPath
+	}
+	return filepath.Join(options.ProjectPath, options.TargetPath)
+}
+
+// runDbtCommand executes the dbt command, saves the results read from the artifacts and fails if any node failed
+func runDbtCommand(taskCtx core.SubTaskContext, command string) errors.Error {
+	log := taskCtx.GetLogger()
+	taskCtx.SetProgress(0, -1)
+	err := prepareProject(taskCtx)
+	if err != nil {
+		return err
+	}
+	data := taskCtx.GetData().(*DbtTaskData)
+	dbtExecParams, err := buildDbtArgs(command, data.Options)
+	if err != nil {
+		return err
+	}
+	// remove the artifacts of the previous invocation, so they won't be mistaken for the results of this one
+	runResultsPath := filepath.Join(targetPath(data.Options), "run_results.json")
+	if removeErr := os.Remove(runResultsPath); removeErr != nil && !os.IsNotExist(removeErr) {
+		return errors.Convert(removeErr)
+	}
+
+	cmd := exec.Command("dbt", dbtExecParams...)
+	log.Info("dbt %s script: %v", command, cmd)
+	stdout, stdoutErr := cmd.StdoutPipe()
+	if stdoutErr != nil {
+		return errors.Convert(stdoutErr)
+	}
+	if err = errors.Convert(cmd.Start()); err != nil {
+		return err
+	}
+	scanner := bufio.NewScanner(stdout)
+	var output []string
+	for scanner.Scan() {
+		line := scanner.Text()
+		log.Info(line)
+		output = append(output, line)
+		if matches := progressPattern.FindStringSubmatch(line); matches != nil {
+			current, _ := strconv.Atoi(matches[1])
+			total, _ := strconv.Atoi(matches[2])
+			taskCtx.SetProgress(current, total)
+		}
+	}
+	scanErr := scanner.Err()
+	// prevent zombie process
+	waitErr := cmd.Wait()
+	if scanErr != nil {
+		return errors.Default.Wrap(scanErr, "dbt read stdout failed")
+	}
+
+	runResults, err := ReadRunResults(runResultsPath)
+	if err != nil {
+		if waitErr != nil {
+			// dbt failed before running any node, e.g. compilation or connection errors
+			return errors.Default.Wrap(waitErr, "dbt "+command+" failed:\n"+strings.Join(lastLines(output, 20), "\n"))
+		}
+		return err
+	}
+	manifest, err := ReadManifest(filepath.Join(targetPath(data.Options), "manifest.json"))
+	if err != nil {
+		log.Warn(err, "failed to read the dbt manifest, node attributes would be missing")
+		manifest = nil
+	}
+	invocation, nodeResults := ConvertRunResults(data.Options.ProjectName, command, runResults, manifest)
+	err = saveResults(taskCtx, invocation, nodeResults)
+	if err != nil {
+		return err
+	}
+	log.Info("dbt %s finished: %d nodes, %d failed", command, invocation.ResultCount, invocation.FailureCount)
+	err = FailedNodesError(command, nodeResults)
+	if err != nil {
+		return err
+	}
+	if waitErr != nil {
+		return errors.Default.Wrap(waitErr, "dbt "+command+" exited abnormally:\n"+strings.Join(lastLines(output, 20), "\n"))
+	}
+	return nil
+}
+
+func saveResults(taskCtx core.SubTaskContext, invocation *models.DbtInvocation, nodeResults []*models.DbtNodeResult) errors.Error {
+	err := taskCtx.GetDal().CreateOrUpdate(invocation)
+	if err != nil {
+		return err
+	}
+	batch, err := helper.NewBatchSave(taskCtx, reflect.TypeOf(&models.DbtNodeResult{}), 500)
+	if err != nil {
+		return err
+	}
+	for _, nodeResult := range nodeResults {
+		err = batch.Add(nodeResult)
+		if err != nil {
+			return err
+		}
+	}
+	return batch.Close()
+}
+
+func lastLines(lines []string, n int) []string {
+	if len(lines) > n {
+		return lines[len(lines)-n:]
+	}
+	return lines
+}
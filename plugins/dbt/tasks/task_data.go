@@ -36,6 +36,8 @@ type DbtOptions struct {
 	Defer          bool                   `json:"defer"`
 	NoDefer        bool                   `json:"noDefer"`
 	FullRefresh    bool                   `json:"fullRefresh"`
+	// where dbt writes the artifacts, relative to projectPath unless absolute, `target` by default
+	TargetPath string `json:"targetPath"`
 	// deprecated, dbt run args
 	Args  []string `json:"args"`
 	Tasks []string `json:"tasks,omitempty"`
@@ -43,4 +45,6 @@ type DbtOptions struct {
 
 type DbtTaskData struct {
 	Options *DbtOptions
+	// profiles.yml and dependencies are prepared by the first dbt subtask
+	prepared bool
 }
@@ -0,0 +1,83 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/apache/incubator-devlake/errors"
+	"github.com/apache/incubator-devlake/plugins/core"
+	"github.com/apache/incubator-devlake/plugins/core/dal"
+	"github.com/apache/incubator-devlake/plugins/dora/models"
+	"github.com/apache/incubator-devlake/plugins/helper"
+)
+
+// GetDoraMetrics return the DORA metrics of a project
+// @Summary return the DORA metrics of a project
+// @Description return the deployment frequency, change lead time, time to restore and change failure rate of a project
+// @Description by period along with the benchmark levels, ordered by the start of the period
+// @Tags plugins/dora
+// @Param projectName query string true "project name"
+// @Param period query string false "DAY, WEEK or MONTH, default MONTH"
+// @Param since query string false "periods starting at or after the time, i.e. 2023-01-01"
+// @Param until query string false "periods starting before the time"
+// @Param pageSize query int false "page size, default 50"
+// @Param page query int false "page, default 1"
+// @Success 200  {object} []models.DoraMetric
+// @Failure 400  {object} shared.ApiBody "Bad Request"
+// @Failure 500  {object} shared.ApiBody "Internal Error"
+// @Router /plugins/dora/metrics [GET]
+func GetDoraMetrics(input *core.ApiResourceInput) (*core.ApiResourceOutput, errors.Error) {
+	projectName := input.Query.Get("projectName")
+	if projectName == "" {
+		return nil, errors.BadInput.New("projectName is required")
+	}
+	period := input.Query.Get("period")
+	if period == "" {
+		period = models.PERIOD_MONTH
+	}
+	if period != models.PERIOD_DAY && period != models.PERIOD_WEEK && period != models.PERIOD_MONTH {
+		return nil, errors.BadInput.New(fmt.Sprintf("invalid period %s, it should be DAY, WEEK or MONTH", period))
+	}
+	clauses := []dal.Clause{
+		dal.Where("project_name = ? AND period = ?", projectName, period),
+	}
+	if since := input.Query.Get("since"); since != "" {
+		t, err := helper.ConvertStringToTime(since)
+		if err != nil {
+			return nil, errors.BadInput.Wrap(err, "invalid since")
+		}
+		clauses = append(clauses, dal.Where("period_start >= ?", t))
+	}
+	if until := input.Query.Get("until"); until != "" {
+		t, err := helper.ConvertStringToTime(until)
+		if err != nil {
+			return nil, errors.BadInput.Wrap(err, "invalid until")
+		}
+		clauses = append(clauses, dal.Where("period_start < ?", t))
+	}
+	limit, offset := helper.GetLimitOffset(input.Query, "pageSize", "page")
+	clauses = append(clauses, dal.Orderby("period_start"), dal.Limit(limit), dal.Offset(offset))
+	var metrics []models.DoraMetric
+	err := basicRes.GetDal().All(&metrics, clauses...)
+	if err != nil {
+		return nil, errors.Default.Wrap(err, "error on get DORA metrics")
+	}
+	return &core.ApiResourceOutput{Body: metrics, Status: http.StatusOK}, nil
+}
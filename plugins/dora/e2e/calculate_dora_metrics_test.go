@@ -0,0 +1,88 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package e2e
+
+import (
+	"testing"
+
+	"github.com/apache/incubator-devlake/helpers/e2ehelper"
+	"github.com/apache/incubator-devlake/models/common"
+	"github.com/apache/incubator-devlake/models/domainlayer/code"
+	"github.com/apache/incubator-devlake/models/domainlayer/crossdomain"
+	"github.com/apache/incubator-devlake/models/domainlayer/devops"
+	"github.com/apache/incubator-devlake/models/domainlayer/ticket"
+	"github.com/apache/incubator-devlake/plugins/dora/impl"
+	"github.com/apache/incubator-devlake/plugins/dora/models"
+	"github.com/apache/incubator-devlake/plugins/dora/tasks"
+)
+
+func TestCalculateDoraMetricsDataFlow(t *testing.T) {
+	var plugin impl.Dora
+	dataflowTester := e2ehelper.NewDataFlowTester(t, "dora", plugin)
+
+	taskData := &tasks.DoraTaskData{
+		Options: &tasks.DoraOptions{
+			ProjectName: "project1",
+		},
+	}
+	// import raw data table
+	dataflowTester.ImportCsvIntoTabler("./raw_tables/dora_metrics_cicd_tasks.csv", &devops.CICDTask{})
+	dataflowTester.ImportCsvIntoTabler("./raw_tables/project_mapping.csv", &crossdomain.ProjectMapping{})
+	dataflowTester.ImportCsvIntoTabler("./raw_tables/dora_metrics_pull_requests.csv", &code.PullRequest{})
+	dataflowTester.ImportCsvIntoTabler("./raw_tables/dora_metrics_project_pr_metrics.csv", &crossdomain.ProjectPrMetric{})
+	dataflowTester.ImportCsvIntoTabler("./raw_tables/dora_metrics_board_issues.csv", &ticket.BoardIssue{})
+	dataflowTester.ImportCsvIntoTabler("./raw_tables/dora_metrics_issues.csv", &ticket.Issue{})
+	dataflowTester.FlushTabler(&models.DoraChangeFailure{})
+
+	// verify calculation
+	dataflowTester.FlushTabler(&models.DoraMetric{})
+	dataflowTester.Subtask(tasks.CalculateDoraMetricsMeta, taskData)
+	dataflowTester.VerifyTableWithOptions(&models.DoraMetric{}, e2ehelper.TableOptions{
+		CSVRelPath:  "./snapshot_tables/dora_metrics.csv",
+		IgnoreTypes: []interface{}{common.NoPKModel{}},
+	})
+}
+
+func TestCalculateDoraMetricsWithPointInTimeProjectMappingDataFlow(t *testing.T) {
+	var plugin impl.Dora
+	dataflowTester := e2ehelper.NewDataFlowTester(t, "dora", plugin)
+
+	taskData := &tasks.DoraTaskData{
+		Options: &tasks.DoraOptions{
+			ProjectName:               "project1",
+			PointInTimeProjectMapping: true,
+		},
+	}
+	// cicd1 moved from project1 to project2 on 2023-01-03, so task2 belongs to project2
+	dataflowTester.ImportCsvIntoTabler("./raw_tables/point_in_time_cicd_tasks.csv", &devops.CICDTask{})
+	dataflowTester.ImportCsvIntoTabler("./raw_tables/point_in_time_project_mapping_history.csv", &crossdomain.ProjectMappingHistory{})
+	dataflowTester.FlushTabler(&crossdomain.ProjectMapping{})
+	dataflowTester.FlushTabler(&code.PullRequest{})
+	dataflowTester.FlushTabler(&crossdomain.ProjectPrMetric{})
+	dataflowTester.FlushTabler(&ticket.BoardIssue{})
+	dataflowTester.FlushTabler(&ticket.Issue{})
+	dataflowTester.FlushTabler(&models.DoraChangeFailure{})
+
+	// verify calculation
+	dataflowTester.FlushTabler(&models.DoraMetric{})
+	dataflowTester.Subtask(tasks.CalculateDoraMetricsMeta, taskData)
+	dataflowTester.VerifyTableWithOptions(&models.DoraMetric{}, e2ehelper.TableOptions{
+		CSVRelPath:  "./snapshot_tables/point_in_time_dora_metrics.csv",
+		IgnoreTypes: []interface{}{common.NoPKModel{}},
+	})
+}
@@ -23,6 +23,7 @@ import (
 
 	"github.com/apache/incubator-devlake/helpers/e2ehelper"
 	"github.com/apache/incubator-devlake/models/common"
+	"github.com/apache/incubator-devlake/models/domainlayer/code"
 	"github.com/apache/incubator-devlake/models/domainlayer/devops"
 	"github.com/apache/incubator-devlake/models/domainlayer/ticket"
 	"github.com/apache/incubator-devlake/plugins/dora/impl"
@@ -55,3 +56,59 @@ func TestConnectIncidentToDeploymentDataFlow(t *testing.T) {
 		IgnoreTypes: []interface{}{common.NoPKModel{}},
 	})
 }
+
+func TestIncidentAttributionStrategiesDataFlow(t *testing.T) {
+	var plugin impl.Dora
+	dataflowTester := e2ehelper.NewDataFlowTester(t, "dora", plugin)
+
+	taskData := &tasks.DoraTaskData{
+		Options: &tasks.DoraOptions{
+			ProjectName: "project1",
+			TransformationRules: tasks.TransformationRules{
+				IncidentAttributionStrategies: []string{
+					tasks.ATTRIBUTION_ISSUE_FIELD,
+					tasks.ATTRIBUTION_COMMIT_REFERENCE,
+					tasks.ATTRIBUTION_SERVICE_MAPPING,
+					tasks.ATTRIBUTION_SAME_REPO,
+					tasks.ATTRIBUTION_SAME_COMPONENT,
+					tasks.ATTRIBUTION_LATEST,
+				},
+				IncidentDeploymentField: "x_deployment_id",
+				IncidentServiceMappings: map[string][]string{
+					"checkout": {"cicd2"},
+				},
+				IncidentAttributionWindowHours: 72,
+			},
+		},
+	}
+	// import raw data table
+	dataflowTester.ImportCsvIntoTabler("./raw_tables/incident_attribution_cicd_tasks.csv", &devops.CICDTask{})
+	dataflowTester.ImportCsvIntoTabler("./raw_tables/incident_attribution_cicd_scopes.csv", &devops.CicdScope{})
+	dataflowTester.ImportCsvIntoTabler("./raw_tables/incident_attribution_cicd_pipeline_commits.csv", &devops.CiCDPipelineCommit{})
+	dataflowTester.ImportCsvIntoTabler("./raw_tables/incident_attribution_repos.csv", &code.Repo{})
+	dataflowTester.ImportCsvIntoTabler("./raw_tables/incident_attribution_commits.csv", &code.Commit{})
+	dataflowTester.ImportCsvIntoTabler("./raw_tables/incident_attribution_repo_commits.csv", &code.RepoCommit{})
+	dataflowTester.ImportCsvIntoTabler("./raw_tables/incident_attribution_commits_diffs.csv", &code.CommitsDiff{})
+	dataflowTester.ImportCsvIntoTabler("./raw_tables/incident_attribution_pull_requests.csv", &code.PullRequest{})
+	dataflowTester.ImportCsvIntoTabler("./raw_tables/incident_attribution_project_mapping.csv", &crossdomain.ProjectMapping{})
+	dataflowTester.ImportCsvIntoTabler("./raw_tables/incident_attribution_board_issues.csv", &ticket.BoardIssue{})
+	dataflowTester.ImportCsvIntoTabler("./raw_tables/incident_attribution_board_repos.csv", &crossdomain.BoardRepo{})
+	dataflowTester.ImportCsvIntoTabler("./raw_tables/incident_attribution_issues.csv", &ticket.Issue{})
+	// the custom column is usually created by the customize plugin
+	err := dataflowTester.Dal.AddColumn("issues", "x_deployment_id", "varchar(255)")
+	if err != nil {
+		panic(err)
+	}
+	err = dataflowTester.Dal.Exec("UPDATE issues SET x_deployment_id = ? WHERE id = ?", "pipeline2", "incident1")
+	if err != nil {
+		panic(err)
+	}
+
+	// verify converter
+	dataflowTester.FlushTabler(&crossdomain.ProjectIssueMetric{})
+	dataflowTester.Subtask(tasks.ConnectIncidentToDeploymentMeta, taskData)
+	dataflowTester.VerifyTableWithOptions(&crossdomain.ProjectIssueMetric{}, e2ehelper.TableOptions{
+		CSVRelPath:  "./snapshot_tables/incident_attribution_project_issue_metrics.csv",
+		IgnoreTypes: []interface{}{common.NoPKModel{}},
+	})
+}
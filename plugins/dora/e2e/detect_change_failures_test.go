@@ -0,0 +1,70 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package e2e
+
+import (
+	"testing"
+
+	"github.com/apache/incubator-devlake/helpers/e2ehelper"
+	"github.com/apache/incubator-devlake/models/common"
+	"github.com/apache/incubator-devlake/models/domainlayer/code"
+	"github.com/apache/incubator-devlake/models/domainlayer/crossdomain"
+	"github.com/apache/incubator-devlake/models/domainlayer/devops"
+	"github.com/apache/incubator-devlake/models/domainlayer/ticket"
+	"github.com/apache/incubator-devlake/plugins/dora/impl"
+	"github.com/apache/incubator-devlake/plugins/dora/models"
+	"github.com/apache/incubator-devlake/plugins/dora/tasks"
+)
+
+func TestDetectChangeFailuresDataFlow(t *testing.T) {
+	var plugin impl.Dora
+	dataflowTester := e2ehelper.NewDataFlowTester(t, "dora", plugin)
+
+	taskData := &tasks.DoraTaskData{
+		Options: &tasks.DoraOptions{
+			ProjectName: "project1",
+		},
+	}
+	// import raw data table
+	dataflowTester.ImportCsvIntoTabler("./raw_tables/change_failures_cicd_tasks.csv", &devops.CICDTask{})
+	dataflowTester.ImportCsvIntoTabler("./raw_tables/change_failures_project_mapping.csv", &crossdomain.ProjectMapping{})
+	dataflowTester.ImportCsvIntoTabler("./raw_tables/change_failures_cicd_pipeline_commits.csv", &devops.CiCDPipelineCommit{})
+	dataflowTester.ImportCsvIntoTabler("./raw_tables/change_failures_commits.csv", &code.Commit{})
+	dataflowTester.ImportCsvIntoTabler("./raw_tables/change_failures_repo_commits.csv", &code.RepoCommit{})
+	dataflowTester.ImportCsvIntoTabler("./raw_tables/change_failures_commits_diffs.csv", &code.CommitsDiff{})
+
+	// verify detection
+	dataflowTester.FlushTabler(&models.DoraChangeFailure{})
+	dataflowTester.Subtask(tasks.DetectChangeFailuresMeta, taskData)
+	dataflowTester.VerifyTableWithOptions(&models.DoraChangeFailure{}, e2ehelper.TableOptions{
+		CSVRelPath:  "./snapshot_tables/dora_change_failures.csv",
+		IgnoreTypes: []interface{}{common.NoPKModel{}},
+	})
+
+	// the project has no incident, so the change failure rate comes from the detected failures
+	dataflowTester.FlushTabler(&code.PullRequest{})
+	dataflowTester.FlushTabler(&crossdomain.ProjectPrMetric{})
+	dataflowTester.FlushTabler(&ticket.BoardIssue{})
+	dataflowTester.FlushTabler(&ticket.Issue{})
+	dataflowTester.FlushTabler(&models.DoraMetric{})
+	dataflowTester.Subtask(tasks.CalculateDoraMetricsMeta, taskData)
+	dataflowTester.VerifyTableWithOptions(&models.DoraMetric{}, e2ehelper.TableOptions{
+		CSVRelPath:  "./snapshot_tables/change_failures_dora_metrics.csv",
+		IgnoreTypes: []interface{}{common.NoPKModel{}},
+	})
+}
@@ -19,10 +19,13 @@ package impl
 
 import (
 	"encoding/json"
+	"reflect"
 
 	"github.com/apache/incubator-devlake/errors"
 	"github.com/apache/incubator-devlake/plugins/core"
 	"github.com/apache/incubator-devlake/plugins/core/dal"
+	"github.com/apache/incubator-devlake/plugins/dora/api"
+	"github.com/apache/incubator-devlake/plugins/dora/models"
 	"github.com/apache/incubator-devlake/plugins/dora/models/migrationscripts"
 	"github.com/apache/incubator-devlake/plugins/dora/tasks"
 )
@@ -34,6 +37,8 @@ var _ core.PluginModel = (*Dora)(nil)
 var _ core.PluginMetric = (*Dora)(nil)
 var _ core.PluginMigration = (*Dora)(nil)
 var _ core.MetricPluginBlueprintV200 = (*Dora)(nil)
+var _ core.PluginApi = (*Dora)(nil)
+var _ core.PluginInit = (*Dora)(nil)
 
 type Dora struct{}
 
@@ -41,6 +46,11 @@ func (plugin Dora) Description() string {
 	return "collect some Dora data"
 }
 
+func (plugin Dora) Init(basicRes core.BasicRes) errors.Error {
+	api.Init(basicRes)
+	return nil
+}
+
 func (plugin Dora) Dashboards() []core.GrafanaDashboard {
 	return nil
 }
@@ -65,7 +75,10 @@ func (plugin Dora) RequiredDataEntities() (data []map[string]interface{}, err er
 }
 
 func (plugin Dora) GetTablesInfo() []dal.Tabler {
-	return []dal.Tabler{}
+	return []dal.Tabler{
+		&models.DoraMetric{},
+		&models.DoraChangeFailure{},
+	}
 }
 
 func (plugin Dora) IsProjectMetric() bool {
@@ -86,6 +99,8 @@ func (plugin Dora) SubTaskMetas() []core.SubTaskMeta {
 		tasks.EnrichTaskEnvMeta,
 		tasks.CalculateChangeLeadTimeMeta,
 		tasks.ConnectIncidentToDeploymentMeta,
+		tasks.DetectChangeFailuresMeta,
+		tasks.CalculateDoraMetricsMeta,
 		tasks.CalculateChangeLeadTimeOldMeta,
 		tasks.ConnectIncidentToDeploymentOldMeta,
 	}
@@ -106,6 +121,14 @@ func (plugin Dora) RootPkgPath() string {
 	return "github.com/apache/incubator-devlake/plugins/dora"
 }
 
+func (plugin Dora) ApiResources() map[string]map[string]core.ApiResourceHandler {
+	return map[string]map[string]core.ApiResourceHandler{
+		"metrics": {
+			"GET": api.GetDoraMetrics,
+		},
+	}
+}
+
 func (plugin Dora) MigrationScripts() []core.MigrationScript {
 	return migrationscripts.All()
 }
@@ -134,6 +157,14 @@ func (plugin Dora) MakeMetricPluginPipelinePlanV200(projectName string, options
 			},
 		},
 	}
+	if op.PointInTimeProjectMapping {
+		stageDeploymentCommitdiff[0].Options["pointInTimeProjectMapping"] = true
+		stageDora[0].Options["pointInTimeProjectMapping"] = true
+	}
+	// the rules drive the incident attribution and the hotfix detection of the dora subtasks
+	if !reflect.DeepEqual(op.TransformationRules, tasks.TransformationRules{}) {
+		stageDora[0].Options["transformationRules"] = op.TransformationRules
+	}
 	plan = append(plan, stageDeploymentCommitdiff, stageDora)
 
 	return plan, nil
@@ -20,6 +20,7 @@ package impl
 import (
 	"encoding/json"
 	"github.com/apache/incubator-devlake/plugins/core"
+	"github.com/apache/incubator-devlake/plugins/dora/tasks"
 	"github.com/stretchr/testify/assert"
 	"testing"
 )
@@ -52,3 +53,62 @@ func TestMakeMetricPluginPipelinePlanV200(t *testing.T) {
 	}
 	assert.Equal(t, doraOutputPlan, plan)
 }
+
+func TestMakeMetricPluginPipelinePlanV200WithPointInTimeProjectMapping(t *testing.T) {
+	var dora Dora
+	const projectName = "TestMakePlanV200-project"
+	optionJson, err := json.Marshal(map[string]interface{}{
+		"pointInTimeProjectMapping": true,
+	})
+	assert.Nil(t, err)
+	plan, err := dora.MakeMetricPluginPipelinePlanV200(projectName, optionJson)
+	assert.Nil(t, err)
+	doraOutputPlan := core.PipelinePlan{
+		core.PipelineStage{
+			{
+				Plugin:   "refdiff",
+				Subtasks: []string{"calculateProjectDeploymentCommitsDiff"},
+				Options:  map[string]interface{}{"projectName": projectName, "pointInTimeProjectMapping": true},
+			},
+		},
+		core.PipelineStage{
+			{
+				Plugin:  "dora",
+				Options: map[string]interface{}{"projectName": projectName, "pointInTimeProjectMapping": true},
+			},
+		},
+	}
+	assert.Equal(t, doraOutputPlan, plan)
+}
+
+func TestMakeMetricPluginPipelinePlanV200WithTransformationRules(t *testing.T) {
+	var dora Dora
+	const projectName = "TestMakePlanV200-project"
+	optionJson := []byte(`{
+		"transformationRules": {
+			"incidentAttributionStrategies": ["ISSUE_FIELD", "SERVICE_MAPPING", "LATEST"],
+			"incidentDeploymentField": "deployment_id",
+			"incidentServiceMappings": {"pagerduty:Service:1:P1": ["github:GithubRepo:1:1"]},
+			"incidentAttributionWindowHours": 48,
+			"hotfixBranchPattern": "^hotfix/"
+		}
+	}`)
+	plan, err := dora.MakeMetricPluginPipelinePlanV200(projectName, optionJson)
+	assert.Nil(t, err)
+	assert.Len(t, plan, 2)
+	assert.NotContains(t, plan[0][0].Options, "transformationRules")
+
+	// the options are stored as json in the pipeline and decoded by PrepareTaskData
+	taskOptionsJson, e := json.Marshal(plan[1][0].Options)
+	assert.Nil(t, e)
+	var taskOptions map[string]interface{}
+	assert.Nil(t, json.Unmarshal(taskOptionsJson, &taskOptions))
+	op, err := tasks.DecodeAndValidateTaskOptions(taskOptions)
+	assert.Nil(t, err)
+	assert.Equal(t, projectName, op.ProjectName)
+	assert.Equal(t, []string{"ISSUE_FIELD", "SERVICE_MAPPING", "LATEST"}, op.IncidentAttributionStrategies)
+	assert.Equal(t, "deployment_id", op.IncidentDeploymentField)
+	assert.Equal(t, map[string][]string{"pagerduty:Service:1:P1": {"github:GithubRepo:1:1"}}, op.IncidentServiceMappings)
+	assert.Equal(t, 48, op.IncidentAttributionWindowHours)
+	assert.Equal(t, "^hotfix/", op.HotfixBranchPattern)
+}
@@ -0,0 +1,53 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import (
+	"time"
+
+	"github.com/apache/incubator-devlake/models/common"
+)
+
+// the signals of a failed change detected from the delivery data
+const (
+	// a commit deployed by the deployment was reverted by a later commit
+	SIGNAL_REVERT = "REVERT"
+	// a later deployment redeployed an older commit, rolling back the deployment
+	SIGNAL_ROLLBACK = "ROLLBACK"
+	// the production deployment itself failed
+	SIGNAL_FAILED_DEPLOYMENT = "FAILED_DEPLOYMENT"
+	// a later deployment was made from a hotfix branch
+	SIGNAL_HOTFIX = "HOTFIX"
+)
+
+// DoraChangeFailure is a production deployment detected as a failed change, a deployment may be detected by more
+// than one signal, EvidenceId is the commit or the deployment which gave the signal
+type DoraChangeFailure struct {
+	ProjectName  string     `gorm:"primaryKey;type:varchar(100)" json:"projectName"`
+	DeploymentId string     `gorm:"primaryKey;type:varchar(255)" json:"deploymentId"`
+	Signal       string     `gorm:"primaryKey;type:varchar(50)" json:"signal"`
+	EvidenceId   string     `gorm:"primaryKey;type:varchar(255)" json:"evidenceId"`
+	Evidence     string     `json:"evidence"`
+	DetectedDate *time.Time `json:"detectedDate"`
+
+	common.NoPKModel `json:"-"`
+}
+
+func (DoraChangeFailure) TableName() string {
+	return "dora_change_failures"
+}
@@ -0,0 +1,72 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import (
+	"time"
+
+	"github.com/apache/incubator-devlake/models/common"
+)
+
+// the periods the DORA metrics are calculated on, a week starts on Monday
+const (
+	PERIOD_DAY   = "DAY"
+	PERIOD_WEEK  = "WEEK"
+	PERIOD_MONTH = "MONTH"
+)
+
+// the benchmark levels defined by the State of DevOps report, same as the ones in `dora_benchmarks`
+const (
+	LEVEL_ELITE  = "ELITE"
+	LEVEL_HIGH   = "HIGH"
+	LEVEL_MEDIUM = "MEDIUM"
+	LEVEL_LOW    = "LOW"
+)
+
+// DoraMetric is the snapshot of the four key metrics of a project in a period, the level is empty when there is
+// no data to measure the metric
+type DoraMetric struct {
+	ProjectName string    `gorm:"primaryKey;type:varchar(100)" json:"projectName"`
+	Period      string    `gorm:"primaryKey;type:varchar(20)" json:"period"`
+	PeriodStart time.Time `gorm:"primaryKey" json:"periodStart"`
+	PeriodEnd   time.Time `json:"periodEnd"`
+
+	DeploymentCount          int    `json:"deploymentCount"`
+	DeploymentDays           int    `json:"deploymentDays"`
+	DeploymentFrequencyLevel string `gorm:"type:varchar(20)" json:"deploymentFrequencyLevel"`
+
+	MergedPrCount               int    `json:"mergedPrCount"`
+	MedianChangeLeadTimeMinutes *int64 `json:"medianChangeLeadTimeMinutes"`
+	ChangeLeadTimeLevel         string `gorm:"type:varchar(20)" json:"changeLeadTimeLevel"`
+
+	IncidentCount              int    `json:"incidentCount"`
+	RestoredIncidentCount      int    `json:"restoredIncidentCount"`
+	MedianTimeToRestoreMinutes *int64 `json:"medianTimeToRestoreMinutes"`
+	TimeToRestoreLevel         string `gorm:"type:varchar(20)" json:"timeToRestoreLevel"`
+
+	// ChangeFailureCount is the number of deployments detected as failed changes in `dora_change_failures`
+	ChangeFailureCount     int      `json:"changeFailureCount"`
+	ChangeFailureRate      *float64 `json:"changeFailureRate"`
+	ChangeFailureRateLevel string   `gorm:"type:varchar(20)" json:"changeFailureRateLevel"`
+
+	common.NoPKModel `json:"-"`
+}
+
+func (DoraMetric) TableName() string {
+	return "dora_metrics"
+}
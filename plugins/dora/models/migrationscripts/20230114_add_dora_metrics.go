@@ -0,0 +1,70 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migrationscripts
+
+import (
+	"time"
+
+	"github.com/apache/incubator-devlake/errors"
+	"github.com/apache/incubator-devlake/helpers/migrationhelper"
+	"github.com/apache/incubator-devlake/models/migrationscripts/archived"
+	"github.com/apache/incubator-devlake/plugins/core"
+)
+
+type addDoraMetrics struct{}
+
+type doraMetric20230114 struct {
+	ProjectName string    `gorm:"primaryKey;type:varchar(100)"`
+	Period      string    `gorm:"primaryKey;type:varchar(20)"`
+	PeriodStart time.Time `gorm:"primaryKey"`
+	PeriodEnd   time.Time
+
+	DeploymentCount          int
+	DeploymentDays           int
+	DeploymentFrequencyLevel string `gorm:"type:varchar(20)"`
+
+	MergedPrCount               int
+	MedianChangeLeadTimeMinutes *int64
+	ChangeLeadTimeLevel         string `gorm:"type:varchar(20)"`
+
+	IncidentCount              int
+	RestoredIncidentCount      int
+	MedianTimeToRestoreMinutes *int64
+	TimeToRestoreLevel         string `gorm:"type:varchar(20)"`
+
+	ChangeFailureRate      *float64
+	ChangeFailureRateLevel string `gorm:"type:varchar(20)"`
+
+	archived.NoPKModel
+}
+
+func (doraMetric20230114) TableName() string {
+	return "dora_metrics"
+}
+
+func (*addDoraMetrics) Up(basicRes core.BasicRes) errors.Error {
+	return migrationhelper.AutoMigrateTables(basicRes, &doraMetric20230114{})
+}
+
+func (*addDoraMetrics) Version() uint64 {
+	return 20230114093518
+}
+
+func (*addDoraMetrics) Name() string {
+	return "add dora_metrics"
+}
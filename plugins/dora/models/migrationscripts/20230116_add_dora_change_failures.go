@@ -0,0 +1,64 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migrationscripts
+
+import (
+	"time"
+
+	"github.com/apache/incubator-devlake/errors"
+	"github.com/apache/incubator-devlake/helpers/migrationhelper"
+	"github.com/apache/incubator-devlake/models/migrationscripts/archived"
+	"github.com/apache/incubator-devlake/plugins/core"
+)
+
+type addDoraChangeFailures struct{}
+
+type doraChangeFailure20230116 struct {
+	ProjectName  string `gorm:"primaryKey;type:varchar(100)"`
+	DeploymentId string `gorm:"primaryKey;type:varchar(255)"`
+	Signal       string `gorm:"primaryKey;type:varchar(50)"`
+	EvidenceId   string `gorm:"primaryKey;type:varchar(255)"`
+	Evidence     string
+	DetectedDate *time.Time
+
+	archived.NoPKModel
+}
+
+func (doraChangeFailure20230116) TableName() string {
+	return "dora_change_failures"
+}
+
+type doraMetric20230116 struct {
+	ChangeFailureCount int
+}
+
+func (doraMetric20230116) TableName() string {
+	return "dora_metrics"
+}
+
+func (*addDoraChangeFailures) Up(basicRes core.BasicRes) errors.Error {
+	return migrationhelper.AutoMigrateTables(basicRes, &doraChangeFailure20230116{}, &doraMetric20230116{})
+}
+
+func (*addDoraChangeFailures) Version() uint64 {
+	return 20230116081245
+}
+
+func (*addDoraChangeFailures) Name() string {
+	return "add dora_change_failures and change_failure_count to dora_metrics"
+}
@@ -23,5 +23,7 @@ import "github.com/apache/incubator-devlake/plugins/core"
 func All() []core.MigrationScript {
 	return []core.MigrationScript{
 		new(addDoraBenchmark),
+		new(addDoraMetrics),
+		new(addDoraChangeFailures),
 	}
 }
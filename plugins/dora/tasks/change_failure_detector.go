@@ -0,0 +1,256 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"time"
+
+	"github.com/apache/incubator-devlake/errors"
+	"github.com/apache/incubator-devlake/models/domainlayer/devops"
+	"github.com/apache/incubator-devlake/plugins/core"
+	"github.com/apache/incubator-devlake/plugins/core/dal"
+	"github.com/apache/incubator-devlake/plugins/dora/models"
+	"github.com/apache/incubator-devlake/plugins/helper"
+)
+
+var DetectChangeFailuresMeta = core.SubTaskMeta{
+	Name:             "detectChangeFailures",
+	EntryPoint:       DetectChangeFailures,
+	EnabledByDefault: true,
+	Description:      "Detect failed changes from reverts, rollbacks, hotfixes and failed production deployments",
+	DomainTypes:      []string{core.DOMAIN_TYPE_CICD, core.DOMAIN_TYPE_CODE},
+}
+
+// DEFAULT_HOTFIX_BRANCH_PATTERN is used when HotfixBranchPattern is not set in the transformation rules
+const DEFAULT_HOTFIX_BRANCH_PATTERN = `(?i)^hotfix`
+
+var revertPattern = regexp.MustCompile(`This reverts commit ([0-9a-f]{7,40})`)
+
+// deployedCommit is a commit deployed to production by a deployment, or the deployment itself when Sha is empty
+type deployedCommit struct {
+	DeploymentId string
+	Result       string
+	FinishedDate *time.Time
+	CommitSha    string
+	RepoId       string
+	Branch       string
+}
+
+type revertCommit struct {
+	Sha           string
+	Message       string
+	CommittedDate *time.Time
+}
+
+// DetectChangeFailures replaces the `dora_change_failures` of the project with the production deployments detected
+// as failed changes, so the change failure rate could be measured without an incident tracker
+func DetectChangeFailures(taskCtx core.SubTaskContext) errors.Error {
+	db := taskCtx.GetDal()
+	data := taskCtx.GetData().(*DoraTaskData)
+	projectName := data.Options.ProjectName
+	hotfixPattern := data.Options.HotfixBranchPattern
+	if hotfixPattern == "" {
+		hotfixPattern = DEFAULT_HOTFIX_BRANCH_PATTERN
+	}
+	hotfixRegex, e := regexp.Compile(hotfixPattern)
+	if e != nil {
+		return errors.BadInput.Wrap(e, "invalid hotfixBranchPattern")
+	}
+
+	deploymentClauses := []dal.Clause{
+		dal.From("cicd_tasks ct"),
+		helper.ProjectMappingJoin("pm", "ct.cicd_scope_id", "ct.finished_date", data.Options.PointInTimeProjectMapping),
+		dal.Where(`ct.environment = ? and ct.type = ? and ct.finished_date is not null
+			and pm.project_name = ? and pm.table = ?`,
+			devops.PRODUCTION, devops.DEPLOYMENT, projectName, "cicd_scopes"),
+	}
+	var failedDeployments []deployedCommit
+	err := db.All(&failedDeployments, append(deploymentClauses,
+		dal.Select("ct.id AS deployment_id, ct.result, ct.finished_date"),
+		dal.Where("ct.result = ?", devops.FAILURE),
+		dal.Orderby("ct.finished_date, ct.id"),
+	)...)
+	if err != nil {
+		return err
+	}
+	var deployedCommits []deployedCommit
+	err = db.All(&deployedCommits, append(deploymentClauses,
+		dal.Select("ct.id AS deployment_id, ct.result, ct.finished_date, cpc.commit_sha, cpc.repo_id, cpc.branch"),
+		dal.Join("join cicd_pipeline_commits cpc on cpc.pipeline_id = ct.pipeline_id"),
+		dal.Where("ct.result = ?", devops.SUCCESS),
+		dal.Orderby("ct.finished_date, ct.id"),
+	)...)
+	if err != nil {
+		return err
+	}
+	var reverts []revertCommit
+	err = db.All(&reverts,
+		dal.Select("c.sha, c.message, c.committed_date"),
+		dal.From("commits c"),
+		dal.Join("join repo_commits rc on rc.commit_sha = c.sha"),
+		helper.ProjectMappingJoin("pm", "rc.repo_id", "c.committed_date", data.Options.PointInTimeProjectMapping),
+		dal.Where("pm.project_name = ? and pm.table = ? and c.message like ?", projectName, "repos", "%This reverts commit%"),
+		dal.Orderby("c.committed_date, c.sha"),
+	)
+	if err != nil {
+		return err
+	}
+
+	detector := newChangeFailureDetector(projectName, data.Options.PointInTimeProjectMapping)
+	detector.detectFailedDeployments(failedDeployments)
+	detector.detectRollbacksAndHotfixes(deployedCommits, hotfixRegex)
+	for _, revert := range reverts {
+		err = detector.detectRevert(db, revert)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = db.Delete(&models.DoraChangeFailure{}, dal.Where("project_name = ?", projectName))
+	if err != nil {
+		return err
+	}
+	batchSaver, err := helper.NewBatchSave(taskCtx, reflect.TypeOf(&models.DoraChangeFailure{}), 500)
+	if err != nil {
+		return err
+	}
+	for _, failure := range detector.failures {
+		err = batchSaver.Add(failure)
+		if err != nil {
+			return err
+		}
+	}
+	return batchSaver.Close()
+}
+
+type changeFailureDetector struct {
+	projectName string
+	pointInTime bool
+	failures    []*models.DoraChangeFailure
+	detected    map[models.DoraChangeFailure]bool
+}
+
+func newChangeFailureDetector(projectName string, pointInTime bool) *changeFailureDetector {
+	return &changeFailureDetector{
+		projectName: projectName,
+		pointInTime: pointInTime,
+		detected:    make(map[models.DoraChangeFailure]bool),
+	}
+}
+
+func (d *changeFailureDetector) add(deploymentId, signal, evidenceId, evidence string, detectedDate *time.Time) {
+	key := models.DoraChangeFailure{DeploymentId: deploymentId, Signal: signal, EvidenceId: evidenceId}
+	if d.detected[key] {
+		return
+	}
+	d.detected[key] = true
+	d.failures = append(d.failures, &models.DoraChangeFailure{
+		ProjectName:  d.projectName,
+		DeploymentId: deploymentId,
+		Signal:       signal,
+		EvidenceId:   evidenceId,
+		Evidence:     evidence,
+		DetectedDate: detectedDate,
+	})
+}
+
+func (d *changeFailureDetector) detectFailedDeployments(failedDeployments []deployedCommit) {
+	for _, deployment := range failedDeployments {
+		d.add(deployment.DeploymentId, models.SIGNAL_FAILED_DEPLOYMENT, deployment.DeploymentId,
+			fmt.Sprintf("deployment %s finished with %s", deployment.DeploymentId, deployment.Result),
+			deployment.FinishedDate)
+	}
+}
+
+// detectRollbacksAndHotfixes walks through the successful deployments of every repo in order, the previous deployment
+// of the repo failed when the current one redeploys a commit first deployed before the previous one, or deploys a
+// hotfix branch
+func (d *changeFailureDetector) detectRollbacksAndHotfixes(deployedCommits []deployedCommit, hotfixRegex *regexp.Regexp) {
+	type firstDeployment struct {
+		deploymentId string
+		index        int
+	}
+	lastDeployed := make(map[string]deployedCommit)
+	firstDeployments := make(map[string]map[string]firstDeployment)
+	for i, current := range deployedCommits {
+		firstOfRepo := firstDeployments[current.RepoId]
+		if firstOfRepo == nil {
+			firstOfRepo = make(map[string]firstDeployment)
+			firstDeployments[current.RepoId] = firstOfRepo
+		}
+		if last, ok := lastDeployed[current.RepoId]; ok && last.DeploymentId != current.DeploymentId {
+			first, redeployed := firstOfRepo[current.CommitSha]
+			if redeployed && first.index < firstOfRepo[last.CommitSha].index {
+				d.add(last.DeploymentId, models.SIGNAL_ROLLBACK, current.DeploymentId,
+					fmt.Sprintf("deployment %s redeployed commit %s of deployment %s", current.DeploymentId, current.CommitSha, first.deploymentId),
+					current.FinishedDate)
+			}
+			if current.Branch != "" && hotfixRegex.MatchString(current.Branch) {
+				d.add(last.DeploymentId, models.SIGNAL_HOTFIX, current.DeploymentId,
+					fmt.Sprintf("deployment %s deployed hotfix branch %s", current.DeploymentId, current.Branch),
+					current.FinishedDate)
+			}
+		}
+		if _, ok := firstOfRepo[current.CommitSha]; !ok {
+			firstOfRepo[current.CommitSha] = firstDeployment{deploymentId: current.DeploymentId, index: i}
+		}
+		lastDeployed[current.RepoId] = current
+	}
+}
+
+// detectRevert marks the first successful production deployment which deployed the reverted commit as failed
+func (d *changeFailureDetector) detectRevert(db dal.Dal, revert revertCommit) errors.Error {
+	for _, match := range revertPattern.FindAllStringSubmatch(revert.Message, -1) {
+		shas, err := expandShas(db, d.projectName, d.pointInTime, []string{match[1]})
+		if err != nil {
+			return err
+		}
+		if len(shas) == 0 {
+			continue
+		}
+		cicdTask := &devops.CICDTask{}
+		err = db.First(cicdTask,
+			dal.From(cicdTask),
+			helper.ProjectMappingJoin("pm", "cicd_tasks.cicd_scope_id", "cicd_tasks.finished_date", d.pointInTime),
+			dal.Where(
+				`cicd_tasks.result = ? 
+					and cicd_tasks.environment = ?
+					and cicd_tasks.type = ?
+					and cicd_tasks.finished_date is not null
+					and pm.table = ?
+					and pm.project_name = ?`,
+				devops.SUCCESS, devops.PRODUCTION, devops.DEPLOYMENT, "cicd_scopes", d.projectName,
+			),
+			deploymentContainsCommits(shas),
+			dal.Orderby("cicd_tasks.finished_date ASC"),
+		)
+		if err != nil {
+			if db.IsErrorNotFound(err) {
+				continue
+			}
+			return err
+		}
+		d.add(cicdTask.Id, models.SIGNAL_REVERT, revert.Sha,
+			fmt.Sprintf("commit %s reverts commit %s", revert.Sha, match[1]),
+			revert.CommittedDate)
+	}
+	return nil
+}
@@ -41,7 +41,7 @@ func CalculateChangeLeadTime(taskCtx core.SubTaskContext) errors.Error {
 			ct.finished_date as task_finished_date, cpc.repo_id as repo_id`),
 		dal.From(`cicd_tasks ct`),
 		dal.Join(`left join cicd_pipeline_commits cpc on ct.pipeline_id = cpc.pipeline_id`),
-		dal.Join(`left join project_mapping pm on pm.row_id = ct.cicd_scope_id`),
+		helper.ProjectMappingJoin("pm", "ct.cicd_scope_id", "ct.finished_date", data.Options.PointInTimeProjectMapping),
 		dal.Where(`ct.environment = ? and ct.type = ? and ct.result = ? and pm.project_name = ? and pm.table = ?`,
 			devops.PRODUCTION, devops.DEPLOYMENT, devops.SUCCESS, data.Options.ProjectName, "cicd_scopes"),
 		dal.Orderby(`cpc.repo_id, ct.started_date `),
@@ -67,7 +67,7 @@ func CalculateChangeLeadTime(taskCtx core.SubTaskContext) errors.Error {
 	// get prs by repo project_name
 	clauses := []dal.Clause{
 		dal.From(&code.PullRequest{}),
-		dal.Join(`left join project_mapping pm on pm.row_id = pull_requests.base_repo_id`),
+		helper.ProjectMappingJoin("pm", "pull_requests.base_repo_id", "pull_requests.merged_date", data.Options.PointInTimeProjectMapping),
 		dal.Where("pull_requests.merged_date IS NOT NULL and pm.project_name = ? and pm.table = ?", data.Options.ProjectName, "repos"),
 	}
 	cursor, err := db.Cursor(clauses...)
@@ -0,0 +1,315 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/apache/incubator-devlake/errors"
+	"github.com/apache/incubator-devlake/models/domainlayer/devops"
+	"github.com/apache/incubator-devlake/models/domainlayer/ticket"
+	"github.com/apache/incubator-devlake/plugins/core"
+	"github.com/apache/incubator-devlake/plugins/core/dal"
+	"github.com/apache/incubator-devlake/plugins/dora/models"
+	"github.com/apache/incubator-devlake/plugins/helper"
+)
+
+var CalculateDoraMetricsMeta = core.SubTaskMeta{
+	Name:             "calculateDoraMetrics",
+	EntryPoint:       CalculateDoraMetrics,
+	EnabledByDefault: true,
+	Description:      "Calculate the four key DORA metrics of the project by day, week and month",
+	DomainTypes:      []string{core.DOMAIN_TYPE_CICD, core.DOMAIN_TYPE_CODE, core.DOMAIN_TYPE_TICKET},
+}
+
+// doraEvent is a deployment, a merged pull request or an incident, Minutes is the change lead time of the pull
+// request or the time to restore of the incident
+type doraEvent struct {
+	Time    *time.Time
+	Minutes *int64
+}
+
+// CalculateDoraMetrics replaces the `dora_metrics` of the project with the ones calculated from the successful
+// production deployments, the change lead time in `project_pr_metrics` and the incidents on the boards of the project.
+// The change failure rate falls back to the failed changes in `dora_change_failures` when the project has no incident
+func CalculateDoraMetrics(taskCtx core.SubTaskContext) errors.Error {
+	db := taskCtx.GetDal()
+	data := taskCtx.GetData().(*DoraTaskData)
+	projectName := data.Options.ProjectName
+
+	var deployments, failedDeployments, changeFailures, prs, incidents []doraEvent
+	deploymentClauses := []dal.Clause{
+		dal.Select("ct.finished_date AS time"),
+		dal.From("cicd_tasks ct"),
+		helper.ProjectMappingJoin("pm", "ct.cicd_scope_id", "ct.finished_date", data.Options.PointInTimeProjectMapping),
+		dal.Where(`ct.environment = ? and ct.type = ? and ct.finished_date is not null
+			and pm.project_name = ? and pm.table = ?`,
+			devops.PRODUCTION, devops.DEPLOYMENT, projectName, "cicd_scopes"),
+	}
+	err := db.All(&deployments, append(deploymentClauses, dal.Where("ct.result = ?", devops.SUCCESS))...)
+	if err != nil {
+		return err
+	}
+	err = db.All(&failedDeployments, append(deploymentClauses, dal.Where("ct.result = ?", devops.FAILURE))...)
+	if err != nil {
+		return err
+	}
+	// a deployment counts once no matter how many signals detected it
+	err = db.All(&changeFailures, append(deploymentClauses, dal.Where(`ct.id in (
+			select dcf.deployment_id from dora_change_failures dcf where dcf.project_name = ?
+		)`, projectName))...)
+	if err != nil {
+		return err
+	}
+	err = db.All(&prs,
+		dal.Select("pr.merged_date AS time, prm.pr_cycle_time AS minutes"),
+		dal.From("project_pr_metrics prm"),
+		dal.Join("left join pull_requests pr on pr.id = prm.id"),
+		dal.Where("prm.project_name = ? and pr.merged_date is not null and prm.pr_cycle_time is not null", projectName),
+	)
+	if err != nil {
+		return err
+	}
+	// only the resolved incidents have the time to restore
+	err = db.All(&incidents,
+		dal.Select("i.created_date AS time, CASE WHEN i.resolution_date IS NOT NULL THEN i.lead_time_minutes END AS minutes"),
+		dal.From("issues i"),
+		dal.Join("left join board_issues bi on bi.issue_id = i.id"),
+		helper.ProjectMappingJoin("pm", "bi.board_id", "i.created_date", data.Options.PointInTimeProjectMapping),
+		dal.Where("i.type = ? and i.created_date is not null and pm.project_name = ? and pm.table = ?",
+			ticket.INCIDENT, projectName, "boards"),
+	)
+	if err != nil {
+		return err
+	}
+
+	err = db.Delete(&models.DoraMetric{}, dal.Where("project_name = ?", projectName))
+	if err != nil {
+		return err
+	}
+	batchSaver, err := helper.NewBatchSave(taskCtx, reflect.TypeOf(&models.DoraMetric{}), 500)
+	if err != nil {
+		return err
+	}
+	for _, metric := range calculateDoraMetrics(projectName, deployments, failedDeployments, changeFailures, prs, incidents) {
+		err = batchSaver.Add(metric)
+		if err != nil {
+			return err
+		}
+	}
+	return batchSaver.Close()
+}
+
+// calculateDoraMetrics returns the metrics of every day, week and month from the first event to the last one,
+// the periods without any event are included as well. Without any incident, the change failure rate is the ratio of
+// the failed changes to all production deployments including the failed ones
+func calculateDoraMetrics(projectName string, deployments, failedDeployments, changeFailures, prs, incidents []doraEvent) []*models.DoraMetric {
+	useIncidents := len(incidents) > 0
+	var first, last *time.Time
+	for _, events := range [][]doraEvent{deployments, failedDeployments, changeFailures, prs, incidents} {
+		for _, event := range events {
+			if event.Time == nil {
+				continue
+			}
+			if first == nil || event.Time.Before(*first) {
+				first = event.Time
+			}
+			if last == nil || event.Time.After(*last) {
+				last = event.Time
+			}
+		}
+	}
+	if first == nil {
+		return nil
+	}
+	var metrics []*models.DoraMetric
+	for _, period := range []string{models.PERIOD_DAY, models.PERIOD_WEEK, models.PERIOD_MONTH} {
+		buckets := make(map[time.Time]*doraBucket)
+		var starts []time.Time
+		for start := periodStart(period, *first); !start.After(*last); start = periodEnd(period, start) {
+			buckets[start] = &doraBucket{deploymentDays: make(map[time.Time]bool), useIncidents: useIncidents}
+			starts = append(starts, start)
+		}
+		for _, deployment := range deployments {
+			if deployment.Time != nil {
+				bucket := buckets[periodStart(period, *deployment.Time)]
+				bucket.deploymentCount++
+				bucket.deploymentDays[periodStart(models.PERIOD_DAY, *deployment.Time)] = true
+			}
+		}
+		for _, deployment := range failedDeployments {
+			if deployment.Time != nil {
+				buckets[periodStart(period, *deployment.Time)].failedDeploymentCount++
+			}
+		}
+		for _, failure := range changeFailures {
+			if failure.Time != nil {
+				buckets[periodStart(period, *failure.Time)].changeFailureCount++
+			}
+		}
+		for _, pr := range prs {
+			if pr.Time != nil && pr.Minutes != nil {
+				bucket := buckets[periodStart(period, *pr.Time)]
+				bucket.leadTimes = append(bucket.leadTimes, *pr.Minutes)
+			}
+		}
+		for _, incident := range incidents {
+			if incident.Time != nil {
+				bucket := buckets[periodStart(period, *incident.Time)]
+				bucket.incidentCount++
+				if incident.Minutes != nil {
+					bucket.restoreTimes = append(bucket.restoreTimes, *incident.Minutes)
+				}
+			}
+		}
+		for _, start := range starts {
+			metrics = append(metrics, buckets[start].toMetric(projectName, period, start))
+		}
+	}
+	return metrics
+}
+
+type doraBucket struct {
+	deploymentCount       int
+	deploymentDays        map[time.Time]bool
+	failedDeploymentCount int
+	changeFailureCount    int
+	leadTimes             []int64
+	incidentCount         int
+	restoreTimes          []int64
+	useIncidents          bool
+}
+
+func (b *doraBucket) toMetric(projectName, period string, start time.Time) *models.DoraMetric {
+	end := periodEnd(period, start)
+	metric := &models.DoraMetric{
+		ProjectName:           projectName,
+		Period:                period,
+		PeriodStart:           start,
+		PeriodEnd:             end,
+		DeploymentCount:       b.deploymentCount,
+		DeploymentDays:        len(b.deploymentDays),
+		MergedPrCount:         len(b.leadTimes),
+		IncidentCount:         b.incidentCount,
+		RestoredIncidentCount: len(b.restoreTimes),
+		ChangeFailureCount:    b.changeFailureCount,
+	}
+	metric.DeploymentFrequencyLevel = deploymentFrequencyLevel(metric.DeploymentDays, end.Sub(start))
+	if len(b.leadTimes) > 0 {
+		metric.MedianChangeLeadTimeMinutes = median(b.leadTimes)
+		metric.ChangeLeadTimeLevel = changeLeadTimeLevel(*metric.MedianChangeLeadTimeMinutes)
+	}
+	if len(b.restoreTimes) > 0 {
+		metric.MedianTimeToRestoreMinutes = median(b.restoreTimes)
+		metric.TimeToRestoreLevel = timeToRestoreLevel(*metric.MedianTimeToRestoreMinutes)
+	}
+	if b.useIncidents && b.deploymentCount > 0 {
+		rate := float64(b.incidentCount) / float64(b.deploymentCount)
+		metric.ChangeFailureRate = &rate
+		metric.ChangeFailureRateLevel = changeFailureRateLevel(rate)
+	} else if total := b.deploymentCount + b.failedDeploymentCount; !b.useIncidents && total > 0 {
+		rate := float64(b.changeFailureCount) / float64(total)
+		metric.ChangeFailureRate = &rate
+		metric.ChangeFailureRateLevel = changeFailureRateLevel(rate)
+	}
+	return metric
+}
+
+// periodStart returns the start of the period containing t in UTC
+func periodStart(period string, t time.Time) time.Time {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	switch period {
+	case models.PERIOD_WEEK:
+		return day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+	case models.PERIOD_MONTH:
+		return day.AddDate(0, 0, 1-day.Day())
+	}
+	return day
+}
+
+// periodEnd returns the start of the next period
+func periodEnd(period string, start time.Time) time.Time {
+	switch period {
+	case models.PERIOD_WEEK:
+		return start.AddDate(0, 0, 7)
+	case models.PERIOD_MONTH:
+		return start.AddDate(0, 1, 0)
+	}
+	return start.AddDate(0, 0, 1)
+}
+
+// median returns the smallest value greater than half of the values, the same way the DORA dashboard does
+func median(values []int64) *int64 {
+	sorted := append([]int64(nil), values...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	return &sorted[len(sorted)/2]
+}
+
+// deploymentFrequencyLevel classifies the number of days with deployments per week in the period,
+// the thresholds are the ones of the DORA dashboard
+func deploymentFrequencyLevel(deploymentDays int, duration time.Duration) string {
+	daysPerWeek := float64(deploymentDays) * 7 * 24 / duration.Hours()
+	switch {
+	case daysPerWeek >= 3:
+		return models.LEVEL_ELITE
+	case daysPerWeek >= 1:
+		return models.LEVEL_HIGH
+	case deploymentDays > 0:
+		return models.LEVEL_MEDIUM
+	}
+	return models.LEVEL_LOW
+}
+
+func changeLeadTimeLevel(minutes int64) string {
+	switch {
+	case minutes < 60:
+		return models.LEVEL_ELITE
+	case minutes < 7*24*60:
+		return models.LEVEL_HIGH
+	case minutes < 180*24*60:
+		return models.LEVEL_MEDIUM
+	}
+	return models.LEVEL_LOW
+}
+
+func timeToRestoreLevel(minutes int64) string {
+	switch {
+	case minutes < 60:
+		return models.LEVEL_ELITE
+	case minutes < 24*60:
+		return models.LEVEL_HIGH
+	case minutes < 7*24*60:
+		return models.LEVEL_MEDIUM
+	}
+	return models.LEVEL_LOW
+}
+
+func changeFailureRateLevel(rate float64) string {
+	switch {
+	case rate <= .15:
+		return models.LEVEL_ELITE
+	case rate <= .20:
+		return models.LEVEL_HIGH
+	case rate <= .30:
+		return models.LEVEL_MEDIUM
+	}
+	return models.LEVEL_LOW
+}
@@ -0,0 +1,60 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"testing"
+	"time"
+
+	"github.com/apache/incubator-devlake/plugins/dora/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCalculateDoraMetricsFailureBeforeFirstDeployment(t *testing.T) {
+	at := func(value string) doraEvent {
+		eventTime, err := time.Parse(time.RFC3339, value)
+		assert.Nil(t, err)
+		return doraEvent{Time: &eventTime}
+	}
+	deployments := []doraEvent{at("2023-02-01T10:00:00Z"), at("2023-02-02T10:00:00Z")}
+	// the failed change was deployed before the first successful deployment, i.e. with a result other than SUCCESS
+	changeFailures := []doraEvent{at("2023-01-30T10:00:00Z"), {}}
+
+	metrics := calculateDoraMetrics("project1", deployments, nil, changeFailures, nil, nil)
+	byPeriod := make(map[string][]*models.DoraMetric)
+	for _, metric := range metrics {
+		byPeriod[metric.Period] = append(byPeriod[metric.Period], metric)
+	}
+	assert.Len(t, byPeriod[models.PERIOD_DAY], 4)
+	assert.Len(t, byPeriod[models.PERIOD_WEEK], 1)
+	assert.Len(t, byPeriod[models.PERIOD_MONTH], 2)
+
+	firstDay := byPeriod[models.PERIOD_DAY][0]
+	assert.Equal(t, time.Date(2023, 1, 30, 0, 0, 0, 0, time.UTC), firstDay.PeriodStart)
+	assert.Equal(t, 1, firstDay.ChangeFailureCount)
+	assert.Nil(t, firstDay.ChangeFailureRate)
+
+	week := byPeriod[models.PERIOD_WEEK][0]
+	assert.Equal(t, 2, week.DeploymentCount)
+	assert.Equal(t, 1, week.ChangeFailureCount)
+	assert.Equal(t, 0.5, *week.ChangeFailureRate)
+
+	january := byPeriod[models.PERIOD_MONTH][0]
+	assert.Equal(t, 1, january.ChangeFailureCount)
+	assert.Equal(t, 0, january.DeploymentCount)
+}
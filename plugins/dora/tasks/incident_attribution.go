@@ -0,0 +1,335 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/apache/incubator-devlake/errors"
+	"github.com/apache/incubator-devlake/models/domainlayer/crossdomain"
+	"github.com/apache/incubator-devlake/models/domainlayer/devops"
+	"github.com/apache/incubator-devlake/models/domainlayer/ticket"
+	"github.com/apache/incubator-devlake/plugins/core/dal"
+	"github.com/apache/incubator-devlake/plugins/helper"
+)
+
+// strategies to attribute an incident to a deployment
+const (
+	// the deployment task or pipeline id stored in a column of the incident
+	ATTRIBUTION_ISSUE_FIELD = "ISSUE_FIELD"
+	// commit shas or pull request links mentioned in the title or description of the incident
+	ATTRIBUTION_COMMIT_REFERENCE = "COMMIT_REFERENCE"
+	// the component of the incident, i.e. the PagerDuty service, mapped to cicd scopes by the transformation rules
+	ATTRIBUTION_SERVICE_MAPPING = "SERVICE_MAPPING"
+	// deployments of the repo which the incident was reported to
+	ATTRIBUTION_SAME_REPO = "SAME_REPO"
+	// deployments of the cicd scope or repo named after the component of the incident
+	ATTRIBUTION_SAME_COMPONENT = "SAME_COMPONENT"
+	// the latest deployment of the project
+	ATTRIBUTION_LATEST = "LATEST"
+)
+
+type attributionStrategy struct {
+	Confidence string
+	// Explicit strategies don't require the deployment to be finished before the incident was created
+	Explicit bool
+	// Windowed strategies respect IncidentAttributionWindowHours
+	Windowed bool
+	// Earliest picks the earliest deployment instead of the latest one
+	Earliest bool
+	// Clauses narrows down the candidate deployments, the strategy is skipped when ok is false
+	Clauses func(a *incidentAttributor, issue *ticket.Issue) (clauses []dal.Clause, ok bool, err errors.Error)
+}
+
+var attributionStrategies = map[string]*attributionStrategy{
+	ATTRIBUTION_ISSUE_FIELD: {
+		Confidence: crossdomain.ATTRIBUTION_CONFIDENCE_HIGH,
+		Explicit:   true,
+		Clauses:    issueFieldClauses,
+	},
+	ATTRIBUTION_COMMIT_REFERENCE: {
+		Confidence: crossdomain.ATTRIBUTION_CONFIDENCE_HIGH,
+		Earliest:   true,
+		Clauses:    commitReferenceClauses,
+	},
+	ATTRIBUTION_SERVICE_MAPPING: {
+		Confidence: crossdomain.ATTRIBUTION_CONFIDENCE_MEDIUM,
+		Windowed:   true,
+		Clauses:    serviceMappingClauses,
+	},
+	ATTRIBUTION_SAME_REPO: {
+		Confidence: crossdomain.ATTRIBUTION_CONFIDENCE_MEDIUM,
+		Windowed:   true,
+		Clauses:    sameRepoClauses,
+	},
+	ATTRIBUTION_SAME_COMPONENT: {
+		Confidence: crossdomain.ATTRIBUTION_CONFIDENCE_MEDIUM,
+		Windowed:   true,
+		Clauses:    sameComponentClauses,
+	},
+	ATTRIBUTION_LATEST: {
+		Confidence: crossdomain.ATTRIBUTION_CONFIDENCE_LOW,
+		Windowed:   true,
+		Clauses: func(*incidentAttributor, *ticket.Issue) ([]dal.Clause, bool, errors.Error) {
+			return nil, true, nil
+		},
+	},
+}
+
+type incidentAttributor struct {
+	db         dal.Dal
+	options    *DoraOptions
+	strategies []string
+}
+
+func newIncidentAttributor(db dal.Dal, options *DoraOptions) *incidentAttributor {
+	strategies := options.IncidentAttributionStrategies
+	if len(strategies) == 0 {
+		strategies = []string{ATTRIBUTION_LATEST}
+	}
+	return &incidentAttributor{db: db, options: options, strategies: strategies}
+}
+
+// attribute tries the configured strategies in order and fills the deployment of the first match into the metric
+func (a *incidentAttributor) attribute(issue *ticket.Issue, metric *crossdomain.ProjectIssueMetric) (bool, errors.Error) {
+	for _, name := range a.strategies {
+		strategy := attributionStrategies[name]
+		clauses, ok, err := strategy.Clauses(a, issue)
+		if err != nil {
+			return false, errors.Default.Wrap(err, "error attributing incident "+issue.Id+" by "+name)
+		}
+		if !ok {
+			continue
+		}
+		cicdTask := &devops.CICDTask{}
+		err = a.db.First(cicdTask, append(a.deploymentClauses(issue, strategy), clauses...)...)
+		if err != nil {
+			if a.db.IsErrorNotFound(err) {
+				continue
+			}
+			return false, err
+		}
+		metric.DeploymentId = cicdTask.Id
+		metric.AttributionStrategy = name
+		metric.AttributionConfidence = strategy.Confidence
+		return true, nil
+	}
+	return false, nil
+}
+
+// deploymentClauses selects the successful production deployments of the project
+func (a *incidentAttributor) deploymentClauses(issue *ticket.Issue, strategy *attributionStrategy) []dal.Clause {
+	clauses := []dal.Clause{
+		dal.From(&devops.CICDTask{}),
+		helper.ProjectMappingJoin("pm", "cicd_tasks.cicd_scope_id", "cicd_tasks.finished_date", a.options.PointInTimeProjectMapping),
+		dal.Where(
+			`cicd_tasks.result = ? 
+				and cicd_tasks.environment = ?
+				and cicd_tasks.type = ?
+				and pm.table = ?
+				and pm.project_name = ?`,
+			devops.SUCCESS, devops.PRODUCTION, devops.DEPLOYMENT, "cicd_scopes", a.options.ProjectName,
+		),
+	}
+	if !strategy.Explicit {
+		clauses = append(clauses, dal.Where("cicd_tasks.finished_date < ?", issue.CreatedDate))
+	}
+	if strategy.Windowed && a.options.IncidentAttributionWindowHours > 0 && issue.CreatedDate != nil {
+		window := time.Duration(a.options.IncidentAttributionWindowHours) * time.Hour
+		clauses = append(clauses, dal.Where("cicd_tasks.finished_date >= ?", issue.CreatedDate.Add(-window)))
+	}
+	if strategy.Earliest {
+		clauses = append(clauses, dal.Orderby("cicd_tasks.finished_date ASC"))
+	} else {
+		clauses = append(clauses, dal.Orderby("cicd_tasks.finished_date DESC"))
+	}
+	return clauses
+}
+
+func issueFieldClauses(a *incidentAttributor, issue *ticket.Issue) ([]dal.Clause, bool, errors.Error) {
+	// the column name was validated by DecodeAndValidateTaskOptions
+	column := a.options.IncidentDeploymentField
+	var values []string
+	err := a.db.Pluck(column, &values,
+		dal.From(&ticket.Issue{}),
+		dal.Where("id = ? and "+column+" is not null and "+column+" != ''", issue.Id),
+	)
+	if err != nil || len(values) == 0 {
+		return nil, false, err
+	}
+	return []dal.Clause{
+		dal.Where("(cicd_tasks.id = ? or cicd_tasks.pipeline_id = ?)", values[0], values[0]),
+	}, true, nil
+}
+
+var (
+	shaPattern = regexp.MustCompile(`\b[0-9a-f]{7,40}\b`)
+	// a pull request is referenced by its url or qualified by the repo, i.e. o/r#42, the last two segments of the
+	// path are taken as the repo. A bare #42 is ambiguous since it is used for list items and issue keys as well
+	pullRequestPattern = regexp.MustCompile(`([\w.-]+/[\w.-]+)(?:/pull/|(?:/-)?/merge_requests/|#)(\d+)\b`)
+)
+
+// commitReferences returns the shas and the keys of the pull requests by repo mentioned in the text
+func commitReferences(text string) ([]string, map[string][]int) {
+	var shas []string
+	for _, sha := range shaPattern.FindAllString(text, -1) {
+		// numbers like dates and ids are not shas
+		if strings.ContainsAny(sha, "abcdef") {
+			shas = append(shas, sha)
+		}
+	}
+	pullRequests := make(map[string][]int)
+	for _, match := range pullRequestPattern.FindAllStringSubmatch(text, -1) {
+		key, err := strconv.Atoi(match[2])
+		if err == nil {
+			repo := strings.ToLower(match[1])
+			pullRequests[repo] = append(pullRequests[repo], key)
+		}
+	}
+	return shas, pullRequests
+}
+
+func commitReferenceClauses(a *incidentAttributor, issue *ticket.Issue) ([]dal.Clause, bool, errors.Error) {
+	refs, pullRequests := commitReferences(issue.Title + "\n" + issue.Description)
+	shas, err := expandShas(a.db, a.options.ProjectName, a.options.PointInTimeProjectMapping, refs)
+	if err != nil {
+		return nil, false, err
+	}
+	repos := make([]string, 0, len(pullRequests))
+	for repo := range pullRequests {
+		repos = append(repos, repo)
+	}
+	sort.Strings(repos)
+	for _, repo := range repos {
+		var mergeShas []string
+		err = a.db.Pluck("pr.merge_commit_sha", &mergeShas,
+			dal.From("pull_requests pr"),
+			dal.Join("left join repos r on r.id = pr.base_repo_id"),
+			helper.ProjectMappingJoin("pm", "pr.base_repo_id", "pr.merged_date", a.options.PointInTimeProjectMapping),
+			dal.Where(
+				`pm.project_name = ? and pm.table = ? and (lower(r.name) = ? or lower(r.name) like ?)
+					and pr.pull_request_key in ? and pr.merge_commit_sha != ''`,
+				a.options.ProjectName, "repos", repo, "%/"+repo, pullRequests[repo],
+			),
+		)
+		if err != nil {
+			return nil, false, err
+		}
+		shas = append(shas, mergeShas...)
+	}
+	if len(shas) == 0 {
+		return nil, false, nil
+	}
+	return []dal.Clause{deploymentContainsCommits(shas)}, true, nil
+}
+
+// expandShas expands the abbreviated shas with the commits of the repos in the project, the full ones are kept as is
+// since the deployments are narrowed down to the project anyway
+func expandShas(db dal.Dal, projectName string, pointInTime bool, refs []string) ([]string, errors.Error) {
+	var shas []string
+	for _, ref := range refs {
+		if len(ref) == 40 {
+			shas = append(shas, ref)
+			continue
+		}
+		var expanded []string
+		err := db.Pluck("c.sha", &expanded,
+			dal.From("commits c"),
+			dal.Join("left join repo_commits rc on rc.commit_sha = c.sha"),
+			helper.ProjectMappingJoin("pm", "rc.repo_id", "c.committed_date", pointInTime),
+			dal.Where("c.sha like ? and pm.project_name = ? and pm.table = ?", ref+"%", projectName, "repos"),
+		)
+		if err != nil {
+			return nil, err
+		}
+		shas = append(shas, expanded...)
+	}
+	return shas, nil
+}
+
+// deploymentContainsCommits filters the `cicd_tasks` which deployed any of the commits, either directly or by
+// deploying a descendant of it according to refdiff
+func deploymentContainsCommits(shas []string) dal.Clause {
+	return dal.Where(
+		`exists (
+			select 1 from cicd_pipeline_commits cpc
+			where cpc.pipeline_id = cicd_tasks.pipeline_id and (
+				cpc.commit_sha in ?
+				or exists (select 1 from commits_diffs cd where cd.new_commit_sha = cpc.commit_sha and cd.commit_sha in ?)
+			)
+		)`,
+		shas, shas,
+	)
+}
+
+func serviceMappingClauses(a *incidentAttributor, issue *ticket.Issue) ([]dal.Clause, bool, errors.Error) {
+	scopeIds := a.options.IncidentServiceMappings[issue.Component]
+	if issue.Component != "" && len(scopeIds) > 0 {
+		return []dal.Clause{dal.Where("cicd_tasks.cicd_scope_id in ?", scopeIds)}, true, nil
+	}
+	// otherwise take the repos or cicd scopes mapped to the boards of the incident, e.g. by the serviceMappings of pagerduty
+	return []dal.Clause{
+		dal.Where(
+			`exists (
+				select 1 from board_repos br
+				join board_issues bi on bi.board_id = br.board_id
+				where bi.issue_id = ? and (
+					br.repo_id = cicd_tasks.cicd_scope_id
+					or exists (select 1 from cicd_pipeline_commits cpc where cpc.pipeline_id = cicd_tasks.pipeline_id and cpc.repo_id = br.repo_id)
+				)
+			)`,
+			issue.Id,
+		),
+	}, true, nil
+}
+
+func sameRepoClauses(_ *incidentAttributor, issue *ticket.Issue) ([]dal.Clause, bool, errors.Error) {
+	// boards of issue trackers which come with the repo share the id of the repo, i.e. GitHub and GitLab
+	return []dal.Clause{
+		dal.Where(
+			`exists (
+				select 1 from cicd_pipeline_commits cpc
+				join board_issues bi on bi.board_id = cpc.repo_id
+				where cpc.pipeline_id = cicd_tasks.pipeline_id and bi.issue_id = ?
+			)`,
+			issue.Id,
+		),
+	}, true, nil
+}
+
+func sameComponentClauses(_ *incidentAttributor, issue *ticket.Issue) ([]dal.Clause, bool, errors.Error) {
+	if issue.Component == "" {
+		return nil, false, nil
+	}
+	component := strings.ToLower(issue.Component)
+	return []dal.Clause{
+		dal.Join("left join cicd_scopes cs on cs.id = cicd_tasks.cicd_scope_id"),
+		dal.Where(
+			`(lower(cs.name) = ? or exists (
+				select 1 from cicd_pipeline_commits cpc
+				join repos r on r.id = cpc.repo_id
+				where cpc.pipeline_id = cicd_tasks.pipeline_id and lower(r.name) = ?
+			))`,
+			component, component,
+		),
+	}, true, nil
+}
@@ -0,0 +1,39 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCommitReferences(t *testing.T) {
+	shas, pullRequests := commitReferences("[#4] Crash reported on 20221113, see issue #12 and build 1234567")
+	assert.Empty(t, shas)
+	assert.Empty(t, pullRequests)
+
+	shas, pullRequests = commitReferences(`caused by 3f2a9c1 and abcdef0123456789abcdef0123456789abcdef01
+		regression from https://github.com/O/R/pull/42, https://gitlab.com/g/sub/p/-/merge_requests/7 and o/web#43`)
+	assert.Equal(t, []string{"3f2a9c1", "abcdef0123456789abcdef0123456789abcdef01"}, shas)
+	assert.Equal(t, map[string][]int{
+		"o/r":   {42},
+		"sub/p": {7},
+		"o/web": {43},
+	}, pullRequests)
+}
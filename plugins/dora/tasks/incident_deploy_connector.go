@@ -24,7 +24,6 @@ import (
 	"github.com/apache/incubator-devlake/models/domainlayer/crossdomain"
 
 	"github.com/apache/incubator-devlake/errors"
-	"github.com/apache/incubator-devlake/models/domainlayer/devops"
 	"github.com/apache/incubator-devlake/models/domainlayer/ticket"
 	"github.com/apache/incubator-devlake/plugins/core"
 	"github.com/apache/incubator-devlake/plugins/core/dal"
@@ -46,7 +45,7 @@ func ConnectIncidentToDeployment(taskCtx core.SubTaskContext) errors.Error {
 	clauses := []dal.Clause{
 		dal.From(`issues i`),
 		dal.Join(`left join board_issues bi on bi.issue_id = i.id`),
-		dal.Join(`left join project_mapping pm on pm.row_id = bi.board_id`),
+		helper.ProjectMappingJoin("pm", "bi.board_id", "i.created_date", data.Options.PointInTimeProjectMapping),
 		dal.Where(
 			"i.type = ? and pm.project_name = ? and pm.table = ?",
 			"INCIDENT", data.Options.ProjectName, "boards",
@@ -58,6 +57,7 @@ func ConnectIncidentToDeployment(taskCtx core.SubTaskContext) errors.Error {
 	}
 	defer cursor.Close()
 
+	attributor := newIncidentAttributor(db, data.Options)
 	enricher, err := helper.NewDataConverter(helper.DataConverterArgs{
 		RawDataSubTaskArgs: helper.RawDataSubTaskArgs{
 			Ctx: taskCtx,
@@ -76,30 +76,10 @@ func ConnectIncidentToDeployment(taskCtx core.SubTaskContext) errors.Error {
 				},
 				ProjectName: data.Options.ProjectName,
 			}
-			cicdTask := &devops.CICDTask{}
-			cicdTakClauses := []dal.Clause{
-				dal.From(cicdTask),
-				dal.Join("left join project_mapping pm on cicd_tasks.cicd_scope_id = pm.row_id"),
-				dal.Where(
-					`cicd_tasks.finished_date < ? 
-								and cicd_tasks.result = ? 
-								and cicd_tasks.environment = ?
-								and cicd_tasks.type = ?
-								and pm.table = ?
-								and pm.project_name = ?`,
-					issue.CreatedDate, devops.SUCCESS, devops.PRODUCTION, devops.DEPLOYMENT, "cicd_scopes", data.Options.ProjectName,
-				),
-				dal.Orderby("cicd_tasks.finished_date DESC"),
+			found, err := attributor.attribute(issue, projectIssueMetric)
+			if err != nil || !found {
+				return nil, err
 			}
-			err = db.First(cicdTask, cicdTakClauses...)
-			if err != nil {
-				if db.IsErrorNotFound(err) {
-					return nil, nil
-				} else {
-					return nil, err
-				}
-			}
-			projectIssueMetric.DeploymentId = cicdTask.Id
 
 			return []interface{}{projectIssueMetric}, nil
 		},
@@ -18,6 +18,9 @@ limitations under the License.
 package tasks
 
 import (
+	"fmt"
+	"regexp"
+
 	"github.com/apache/incubator-devlake/errors"
 	"github.com/apache/incubator-devlake/plugins/helper"
 )
@@ -30,17 +33,30 @@ type TransformationRules struct {
 	ProductionPattern string `mapstructure:"productionPattern" json:"productionPattern"`
 	StagingPattern    string `mapstructure:"stagingPattern" json:"stagingPattern"`
 	TestingPattern    string `mapstructure:"testingPattern" json:"testingPattern"`
+	// IncidentAttributionStrategies are tried in order to attribute an incident to a deployment, defaults to LATEST
+	IncidentAttributionStrategies []string `mapstructure:"incidentAttributionStrategies" json:"incidentAttributionStrategies"`
+	// IncidentDeploymentField is the column of `issues` holding the id of the deployment task or pipeline, required by ISSUE_FIELD
+	IncidentDeploymentField string `mapstructure:"incidentDeploymentField" json:"incidentDeploymentField"`
+	// IncidentServiceMappings maps the component of an incident, i.e. a PagerDuty service, to cicd scope ids for SERVICE_MAPPING,
+	// which falls back to the board_repos of the boards of the incident, i.e. the serviceMappings of pagerduty
+	IncidentServiceMappings map[string][]string `mapstructure:"incidentServiceMappings" json:"incidentServiceMappings"`
+	// IncidentAttributionWindowHours ignores deployments finished more than the given hours before the incident, 0 for no limit
+	IncidentAttributionWindowHours int `mapstructure:"incidentAttributionWindowHours" json:"incidentAttributionWindowHours"`
+	// HotfixBranchPattern matches the branches of the hotfix deployments, defaults to DEFAULT_HOTFIX_BRANCH_PATTERN
+	HotfixBranchPattern string `mapstructure:"hotfixBranchPattern" json:"hotfixBranchPattern"`
 }
 
 type DoraOptions struct {
-	Tasks               []string `json:"tasks,omitempty"`
-	Since               string
-	RepoId              string `json:"repoId"`
-	CicdScopeId         string `json:"cicdScopeId"`
-	BoardId             string `json:"boardId"`
-	Prefix              string `json:"prefix"`
-	ProjectName         string `json:"projectName"`
-	TransformationRules `mapstructure:"transformationRules" json:"transformationRules"`
+	Tasks       []string `json:"tasks,omitempty"`
+	Since       string
+	RepoId      string `json:"repoId"`
+	CicdScopeId string `json:"cicdScopeId"`
+	BoardId     string `json:"boardId"`
+	Prefix      string `json:"prefix"`
+	ProjectName string `json:"projectName"`
+	// PointInTimeProjectMapping uses the project mapping valid at the date of every event instead of the current one
+	PointInTimeProjectMapping bool `json:"pointInTimeProjectMapping"`
+	TransformationRules       `mapstructure:"transformationRules" json:"transformationRules"`
 }
 
 type DoraTaskData struct {
@@ -53,6 +69,36 @@ func DecodeAndValidateTaskOptions(options map[string]interface{}) (*DoraOptions,
 	if err != nil {
 		return nil, errors.Default.Wrap(err, "error decoding DORA task options")
 	}
+	err = validateIncidentAttribution(&op.TransformationRules)
+	if err != nil {
+		return nil, err
+	}
+	if op.HotfixBranchPattern != "" {
+		_, e := regexp.Compile(op.HotfixBranchPattern)
+		if e != nil {
+			return nil, errors.BadInput.Wrap(e, "invalid hotfixBranchPattern")
+		}
+	}
 
 	return &op, nil
 }
+
+var columnNamePattern = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+func validateIncidentAttribution(rules *TransformationRules) errors.Error {
+	for _, strategy := range rules.IncidentAttributionStrategies {
+		switch strategy {
+		case ATTRIBUTION_ISSUE_FIELD:
+			if !columnNamePattern.MatchString(rules.IncidentDeploymentField) {
+				return errors.BadInput.New(fmt.Sprintf("invalid incidentDeploymentField %q for strategy %s", rules.IncidentDeploymentField, strategy))
+			}
+		case ATTRIBUTION_SERVICE_MAPPING, ATTRIBUTION_COMMIT_REFERENCE, ATTRIBUTION_SAME_REPO, ATTRIBUTION_SAME_COMPONENT, ATTRIBUTION_LATEST:
+		default:
+			return errors.BadInput.New(fmt.Sprintf("unknown incident attribution strategy %s", strategy))
+		}
+	}
+	if rules.IncidentAttributionWindowHours < 0 {
+		return errors.BadInput.New("incidentAttributionWindowHours must not be negative")
+	}
+	return nil
+}
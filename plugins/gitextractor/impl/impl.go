@@ -19,12 +19,14 @@ package impl
 
 import (
 	"fmt"
+	"regexp"
 	"strings"
 
 	"github.com/apache/incubator-devlake/errors"
 	"github.com/apache/incubator-devlake/plugins/core"
 	"github.com/apache/incubator-devlake/plugins/core/dal"
 	"github.com/apache/incubator-devlake/plugins/gitextractor/models"
+	"github.com/apache/incubator-devlake/plugins/gitextractor/models/migrationscripts"
 	"github.com/apache/incubator-devlake/plugins/gitextractor/parser"
 	"github.com/apache/incubator-devlake/plugins/gitextractor/store"
 	"github.com/apache/incubator-devlake/plugins/gitextractor/tasks"
@@ -34,11 +36,14 @@ import (
 var _ core.PluginMeta = (*GitExtractor)(nil)
 var _ core.PluginTask = (*GitExtractor)(nil)
 var _ core.PluginModel = (*GitExtractor)(nil)
+var _ core.PluginMigration = (*GitExtractor)(nil)
 
 type GitExtractor struct{}
 
 func (plugin GitExtractor) GetTablesInfo() []dal.Tabler {
-	return []dal.Tabler{}
+	return []dal.Tabler{
+		&models.GitRepoTip{},
+	}
 }
 
 func (plugin GitExtractor) Description() string {
@@ -65,6 +70,8 @@ func (plugin GitExtractor) PrepareTaskData(taskCtx core.TaskContext, options map
 		return nil, err
 	}
 	storage := store.NewDatabase(taskCtx, op.RepoId)
+	// keep the commits stored by the previous runs unless a full rescan is requested
+	storage.SetIncrementalMode(!op.FullRescan)
 	repo, err := NewGitRepo(taskCtx.GetLogger(), storage, op)
 	if err != nil {
 		return nil, err
@@ -73,7 +80,7 @@ func (plugin GitExtractor) PrepareTaskData(taskCtx core.TaskContext, options map
 }
 
 func (plugin GitExtractor) Close(taskCtx core.TaskContext) errors.Error {
-	if repo, ok := taskCtx.GetData().(*parser.GitRepo); ok {
+	if repo, ok := taskCtx.GetData().(parser.RepoCollector); ok {
 		if err := repo.Close(); err != nil {
 			return errors.Convert(err)
 		}
@@ -81,23 +88,53 @@ func (plugin GitExtractor) Close(taskCtx core.TaskContext) errors.Error {
 	return nil
 }
 
+func (plugin GitExtractor) MigrationScripts() []core.MigrationScript {
+	return migrationscripts.All()
+}
+
 func (plugin GitExtractor) RootPkgPath() string {
 	return "github.com/apache/incubator-devlake/plugins/gitextractor"
 }
 
-// NewGitRepo create and return a new parser git repo
-func NewGitRepo(logger core.Logger, storage models.Store, op tasks.GitExtractorOptions) (*parser.GitRepo, errors.Error) {
+// NewGitRepo create and return a new parser git repo, go-git is used instead of libgit2 when UseGoGit is set
+func NewGitRepo(logger core.Logger, storage models.Store, op tasks.GitExtractorOptions) (parser.RepoCollector, errors.Error) {
 	var err errors.Error
-	var repo *parser.GitRepo
+	var repo parser.RepoCollector
 	p := parser.NewGitRepoCreator(storage, logger)
 	if strings.HasPrefix(op.Url, "http") {
-		repo, err = p.CloneOverHTTP(op.RepoId, op.Url, op.User, op.Password, op.Proxy)
+		if op.UseGoGit {
+			repo, err = p.CloneGoGitOverHTTP(op.RepoId, op.Url, op.User, op.Password, op.Proxy)
+		} else {
+			repo, err = p.CloneOverHTTP(op.RepoId, op.Url, op.User, op.Password, op.Proxy)
+		}
 	} else if url := strings.TrimPrefix(op.Url, "ssh://"); strings.HasPrefix(url, "git@") {
-		repo, err = p.CloneOverSSH(op.RepoId, url, op.PrivateKey, op.Passphrase)
+		if op.UseGoGit {
+			repo, err = p.CloneGoGitOverSSH(op.RepoId, url, op.PrivateKey, op.Passphrase)
+		} else {
+			repo, err = p.CloneOverSSH(op.RepoId, url, op.PrivateKey, op.Passphrase)
+		}
 	} else if strings.HasPrefix(op.Url, "/") {
-		repo, err = p.LocalRepo(op.Url, op.RepoId)
+		if op.UseGoGit {
+			repo, err = p.LocalGoGitRepo(op.Url, op.RepoId)
+		} else {
+			repo, err = p.LocalRepo(op.Url, op.RepoId)
+		}
 	} else {
 		return nil, errors.BadInput.New(fmt.Sprintf("unsupported url [%s]", op.Url))
 	}
-	return repo, err
+	if err != nil {
+		return nil, err
+	}
+	repo.SetFullRescan(op.FullRescan)
+	repo.SetMailmap(op.Mailmap)
+	var pattern *regexp.Regexp
+	if op.DiffLineBranchPattern != "" {
+		var e error
+		pattern, e = regexp.Compile(op.DiffLineBranchPattern)
+		if e != nil {
+			return nil, errors.BadInput.Wrap(e, "invalid diffLineBranchPattern")
+		}
+	}
+	repo.SetDiffLineBranches(op.DiffLineBranches, pattern)
+	return repo, nil
 }
@@ -20,6 +20,7 @@ package main
 import (
 	"context"
 	"flag"
+	"strings"
 
 	"github.com/apache/incubator-devlake/config"
 	rootImpl "github.com/apache/incubator-devlake/impl"
@@ -44,6 +45,10 @@ func main() {
 	password := flag.String("password", "", "-password")
 	output := flag.String("output", "", "-output")
 	dbUrl := flag.String("db", "", "-db")
+	fullRescan := flag.Bool("fullRescan", false, "-fullRescan")
+	useGoGit := flag.Bool("useGoGit", false, "-useGoGit")
+	diffLineBranches := flag.String("diffLineBranches", "", "-diffLineBranches main,release/1.0")
+	diffLineBranchPattern := flag.String("diffLineBranchPattern", "", "-diffLineBranchPattern ^release/")
 	flag.Parse()
 
 	cfg := config.GetConfig()
@@ -72,7 +77,9 @@ func main() {
 	}
 	// If we didn't specify output or dburl, we will use db by default
 	if storage == nil {
-		storage = store.NewDatabase(basicRes, *id)
+		database := store.NewDatabase(basicRes, *id)
+		database.SetIncrementalMode(!*fullRescan)
+		storage = database
 	}
 	defer storage.Close()
 	ctx := context.Background()
@@ -82,13 +89,20 @@ func main() {
 		"git extractor",
 		nil,
 	)
-	repo, err := impl.NewGitRepo(log, storage, tasks.GitExtractorOptions{
-		RepoId:   *id,
-		Url:      *url,
-		User:     *user,
-		Password: *password,
-		Proxy:    *proxy,
-	})
+	options := tasks.GitExtractorOptions{
+		RepoId:                *id,
+		Url:                   *url,
+		User:                  *user,
+		Password:              *password,
+		Proxy:                 *proxy,
+		FullRescan:            *fullRescan,
+		UseGoGit:              *useGoGit,
+		DiffLineBranchPattern: *diffLineBranchPattern,
+	}
+	if *diffLineBranches != "" {
+		options.DiffLineBranches = strings.Split(*diffLineBranches, ",")
+	}
+	repo, err := impl.NewGitRepo(log, storage, options)
 	if err != nil {
 		panic(err)
 	}
@@ -102,3 +102,16 @@ func NewFileBlame() (*FileBlame, error) {
 	fb.Idx = 0
 	return &fb, nil
 }
+
+// Clone returns a copy of the FileBlame, so the blame of a branch could be continued on another
+func (fb *FileBlame) Clone() *FileBlame {
+	clone := &FileBlame{Lines: list.New()}
+	for e := fb.Lines.Front(); e != nil; e = e.Next() {
+		clone.Lines.PushBack(e.Value)
+	}
+	clone.It = clone.Lines.Front()
+	if clone.It != nil {
+		clone.Idx = 1
+	}
+	return clone
+}
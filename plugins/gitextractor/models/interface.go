@@ -28,8 +28,16 @@ type Store interface {
 	Refs(ref *code.Ref) errors.Error
 	CommitFiles(file *code.CommitFile) errors.Error
 	CommitParents(pp []*code.CommitParent) errors.Error
+	CommitContributors(cc []*code.CommitContributor) errors.Error
 	CommitFileComponents(commitFileComponent *code.CommitFileComponent) errors.Error
 	CommitLineChange(commitLineChange *code.CommitLineChange) errors.Error
 	RepoSnapshot(snapshot *code.RepoSnapshot) errors.Error
+	CommitBranches(commitBranch *code.CommitBranch) errors.Error
+	// ResetBranchSnapshots removes the snapshots and commit branches of the repo, which are rebuilt from the branch
+	// tips since the branches may be rewritten or removed
+	ResetBranchSnapshots(repoId string) errors.Error
+	// RepoTips replaces the tips of the repo once all its commits are collected, the records collected before are
+	// saved first so the tips never run ahead of the commits
+	RepoTips(repoId string, tips []*GitRepoTip) errors.Error
 	Close() errors.Error
 }
@@ -0,0 +1,32 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import "github.com/apache/incubator-devlake/models/common"
+
+// GitRepoTip is a commit whose history has been stored completely by the last successful run, the next incremental
+// run only walks the commits not reachable from the tips
+type GitRepoTip struct {
+	RepoId    string `gorm:"primaryKey;type:varchar(255)"`
+	CommitSha string `gorm:"primaryKey;type:varchar(40)"`
+	common.NoPKModel
+}
+
+func (GitRepoTip) TableName() string {
+	return "_tool_gitextractor_repo_tips"
+}
@@ -19,19 +19,19 @@ package parser
 
 import (
 	"encoding/base64"
-	"fmt"
 	"net"
 	"os"
 
 	"github.com/apache/incubator-devlake/errors"
 	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/transport/http"
 	"github.com/go-git/go-git/v5/plumbing/transport/ssh"
-	git "github.com/libgit2/git2go/v33"
 	ssh2 "golang.org/x/crypto/ssh"
 )
 
 // We have done comparison experiments for git2go and go-git, and the results show that git2go has better performance.
-// We kept go-git because it supports cloning via key-based SSH.
+// We kept go-git because it supports cloning via key-based SSH, and it is also available as the pure-Go backend
+// for the environments without libgit2.
 
 const DefaultUser = "git"
 
@@ -55,27 +55,27 @@ func cloneOverSSH(url, dir, passphrase string, pk []byte) errors.Error {
 	return nil
 }
 
-func (l *GitRepoCreator) CloneOverHTTP(repoId, url, user, password, proxy string) (*GitRepo, errors.Error) {
-	return withTempDirectory(func(dir string) (*GitRepo, error) {
-		cloneOptions := &git.CloneOptions{Bare: true}
-		if proxy != "" {
-			cloneOptions.FetchOptions.ProxyOptions.Type = git.ProxyTypeAuto
-			cloneOptions.FetchOptions.ProxyOptions.Url = proxy
-		}
+// CloneGoGitOverHTTP clones the repo with go-git, the proxy is not supported by go-git yet
+func (l *GitRepoCreator) CloneGoGitOverHTTP(repoId, url, user, password, proxy string) (RepoCollector, errors.Error) {
+	if proxy != "" {
+		return nil, errors.BadInput.New("proxy is not supported by the go-git backend")
+	}
+	return withTempDirectory(func(dir string) (RepoCollector, error) {
+		cloneOptions := &gogit.CloneOptions{URL: url}
 		if user != "" {
-			auth := fmt.Sprintf("Authorization: Basic %s", base64.StdEncoding.EncodeToString([]byte(user+":"+password)))
-			cloneOptions.FetchOptions.Headers = []string{auth}
+			cloneOptions.Auth = &http.BasicAuth{Username: user, Password: password}
 		}
-		clonedRepo, err := git.Clone(url, dir, cloneOptions)
+		_, err := gogit.PlainClone(dir, true, cloneOptions)
 		if err != nil {
 			return nil, err
 		}
-		return l.newGitRepo(repoId, clonedRepo), nil
+		return l.LocalGoGitRepo(dir, repoId)
 	})
 }
 
-func (l *GitRepoCreator) CloneOverSSH(repoId, url, privateKey, passphrase string) (*GitRepo, errors.Error) {
-	return withTempDirectory(func(dir string) (*GitRepo, error) {
+// CloneGoGitOverSSH clones the repo with go-git and opens it with go-git as well
+func (l *GitRepoCreator) CloneGoGitOverSSH(repoId, url, privateKey, passphrase string) (RepoCollector, errors.Error) {
+	return withTempDirectory(func(dir string) (RepoCollector, error) {
 		pk, err := base64.StdEncoding.DecodeString(privateKey)
 		if err != nil {
 			return nil, err
@@ -84,11 +84,11 @@ func (l *GitRepoCreator) CloneOverSSH(repoId, url, privateKey, passphrase string
 		if err != nil {
 			return nil, err
 		}
-		return l.LocalRepo(dir, repoId)
+		return l.LocalGoGitRepo(dir, repoId)
 	})
 }
 
-func withTempDirectory(f func(tempDir string) (*GitRepo, error)) (*GitRepo, errors.Error) {
+func withTempDirectory(f func(tempDir string) (RepoCollector, error)) (RepoCollector, errors.Error) {
 	dir, err := os.MkdirTemp("", "gitextractor")
 	if err != nil {
 		return nil, errors.Convert(err)
@@ -105,6 +105,6 @@ func withTempDirectory(f func(tempDir string) (*GitRepo, error)) (*GitRepo, erro
 	if err != nil {
 		return nil, errors.Convert(err)
 	}
-	repo.cleanup = cleanup
-	return repo, errors.Convert(err)
+	repo.setCleanup(cleanup)
+	return repo, nil
 }
@@ -0,0 +1,102 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package parser
+
+import (
+	"context"
+
+	"github.com/apache/incubator-devlake/errors"
+	"github.com/apache/incubator-devlake/models/domainlayer/code"
+	"github.com/apache/incubator-devlake/plugins/core/dal"
+	"github.com/apache/incubator-devlake/plugins/gitextractor/models"
+)
+
+// loadCommitState returns the commits stored for the repo, and the tips saved by the last successful run, the
+// history of the tips has been stored completely while the rest commits might come from an interrupted run
+func loadCommitState(db dal.Dal, repoId string) (knownShas []string, lastTips []string, err errors.Error) {
+	err = db.Pluck("commit_sha", &knownShas, dal.From(&code.RepoCommit{}), dal.Where("repo_id = ?", repoId))
+	if err != nil {
+		return nil, nil, err
+	}
+	err = db.Pluck("commit_sha", &lastTips, dal.From(&models.GitRepoTip{}), dal.Where("repo_id = ?", repoId))
+	if err != nil {
+		return nil, nil, err
+	}
+	return knownShas, lastTips, nil
+}
+
+func newRepoTips(repoId string, shas []string) []*models.GitRepoTip {
+	tips := make([]*models.GitRepoTip, 0, len(shas))
+	for _, sha := range shas {
+		tips = append(tips, &models.GitRepoTip{RepoId: repoId, CommitSha: sha})
+	}
+	return tips
+}
+
+// newCommitShas returns the commits reachable from the tips but not from the lastTips, the walk goes on through the
+// known commits outside the history of the lastTips since their parents might be missing, but they are not returned
+func newCommitShas(
+	ctx context.Context,
+	tips []string,
+	lastTips []string,
+	known map[string]bool,
+	commitParents func(sha string) ([]string, errors.Error),
+) ([]string, errors.Error) {
+	complete := make(map[string]bool)
+	queue := append([]string(nil), lastTips...)
+	for len(queue) > 0 {
+		if err := ctx.Err(); err != nil {
+			return nil, errors.Convert(err)
+		}
+		sha := queue[0]
+		queue = queue[1:]
+		if complete[sha] {
+			continue
+		}
+		parents, err := commitParents(sha)
+		if err != nil {
+			// the last tip might be gone after a force push, the commits would be walked again then
+			continue
+		}
+		complete[sha] = true
+		queue = append(queue, parents...)
+	}
+	var shas []string
+	visited := make(map[string]bool)
+	queue = append(queue, tips...)
+	for len(queue) > 0 {
+		if err := ctx.Err(); err != nil {
+			return nil, errors.Convert(err)
+		}
+		sha := queue[0]
+		queue = queue[1:]
+		if complete[sha] || visited[sha] {
+			continue
+		}
+		visited[sha] = true
+		parents, err := commitParents(sha)
+		if err != nil {
+			return nil, err
+		}
+		if !known[sha] {
+			shas = append(shas, sha)
+		}
+		queue = append(queue, parents...)
+	}
+	return shas, nil
+}
@@ -0,0 +1,71 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package parser
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"testing"
+
+	"github.com/apache/incubator-devlake/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewCommitShas(t *testing.T) {
+	// a <- b <- c (main), b <- d <- e (feature)
+	graph := map[string][]string{
+		"a": nil,
+		"b": {"a"},
+		"c": {"b"},
+		"d": {"b"},
+		"e": {"d"},
+	}
+	commitParents := func(sha string) ([]string, errors.Error) {
+		parents, ok := graph[sha]
+		if !ok {
+			return nil, errors.NotFound.New(fmt.Sprintf("commit %s not found", sha))
+		}
+		return parents, nil
+	}
+	newShas := func(tips, lastTips, knownShas []string) []string {
+		known := make(map[string]bool)
+		for _, sha := range knownShas {
+			known[sha] = true
+		}
+		shas, err := newCommitShas(context.Background(), tips, lastTips, known, commitParents)
+		assert.Nil(t, err)
+		sort.Strings(shas)
+		return shas
+	}
+
+	// the first run was interrupted after storing the tips, the walk must not stop at them
+	assert.Equal(t, []string{"a", "b", "d"}, newShas([]string{"c", "e"}, nil, []string{"c", "e"}))
+	// the last run succeeded on main, only the commits of feature are new
+	assert.Equal(t, []string{"d", "e"}, newShas([]string{"c", "e"}, []string{"c"}, []string{"a", "b", "c"}))
+	// the last tip is gone after a force push
+	assert.Equal(t, []string{"d", "e"}, newShas([]string{"e"}, []string{"x"}, []string{"a", "b", "c"}))
+	// nothing changed since the last run
+	assert.Empty(t, newShas([]string{"c", "e"}, []string{"c", "e"}, []string{"a", "b", "c", "d", "e"}))
+
+	// the walk stops once the context is cancelled
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := newCommitShas(ctx, []string{"c"}, nil, nil, commitParents)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,568 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package parser
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+
+	"github.com/apache/incubator-devlake/errors"
+	"github.com/apache/incubator-devlake/models/domainlayer/code"
+	"github.com/apache/incubator-devlake/plugins/core"
+	"github.com/apache/incubator-devlake/plugins/gitextractor/models"
+)
+
+// the line origins follow the names used by libgit2
+const (
+	lineContext  = "Context"
+	lineAddition = "Addition"
+	lineDeletion = "Deletion"
+)
+
+type diffLine struct {
+	origin    string
+	oldLineno int
+	newLineno int
+}
+
+// fileDiff is the diff of a file between a commit and one of its parents, the line numbers are -1 when not applicable
+type fileDiff struct {
+	oldPath string
+	newPath string
+	hunks   [][]diffLine
+}
+
+// newCommitFile returns the commit file with the id made of the commitSha and the sha256 of the path,
+// since some long paths would not fit varchar(255) along with the commitSha
+func newCommitFile(commitSha, filePath string) *code.CommitFile {
+	shaFilePath := sha256.New()
+	shaFilePath.Write([]byte(filePath))
+	commitFile := &code.CommitFile{
+		CommitSha: commitSha,
+		FilePath:  filePath,
+	}
+	commitFile.Id = commitSha + ":" + hex.EncodeToString(shaFilePath.Sum(nil))
+	return commitFile
+}
+
+func newCommitFileComponent(commitFile *code.CommitFile, componentMap map[string]*regexp.Regexp) *code.CommitFileComponent {
+	commitFileComponent := &code.CommitFileComponent{
+		CommitFileId:  commitFile.Id,
+		ComponentName: "Default",
+	}
+	for component, reg := range componentMap {
+		if reg.MatchString(commitFile.FilePath) {
+			commitFileComponent.ComponentName = component
+			break
+		}
+	}
+	return commitFileComponent
+}
+
+// branchTip is a branch to collect the diff lines on, named the same way as CollectBranches does
+type branchTip struct {
+	name string
+	sha  string
+}
+
+// diffLineSource is implemented by the git backends to feed the diffLineCollector
+type diffLineSource interface {
+	// headBranch returns the branch HEAD points to, or nil if the repo is empty
+	headBranch() (*branchTip, errors.Error)
+	// branchTips returns the local and remote branches
+	branchTips() ([]*branchTip, errors.Error)
+	// commitParents returns the parents of the commit in order
+	commitParents(sha string) ([]string, errors.Error)
+	// commitDiff returns the diff of the commit against the given parent, or against the empty tree if parentSha is empty,
+	// the files are sorted by path
+	commitDiff(subtaskCtx core.SubTaskContext, sha, parentSha string) ([]fileDiff, errors.Error)
+}
+
+// selectBranches picks the listed branches first and then the ones matching the pattern ordered by name
+func selectBranches(all []*branchTip, names []string, pattern *regexp.Regexp, logger core.Logger) []*branchTip {
+	byName := make(map[string]*branchTip, len(all))
+	for _, tip := range all {
+		byName[tip.name] = tip
+	}
+	selected := make([]*branchTip, 0)
+	picked := make(map[string]bool)
+	for _, name := range names {
+		tip, ok := byName[name]
+		if !ok {
+			logger.Warn(nil, "branch %s not found in the repo", name)
+			continue
+		}
+		if !picked[name] {
+			picked[name] = true
+			selected = append(selected, tip)
+		}
+	}
+	if pattern != nil {
+		var matched []*branchTip
+		for _, tip := range all {
+			if !picked[tip.name] && pattern.MatchString(tip.name) {
+				picked[tip.name] = true
+				matched = append(matched, tip)
+			}
+		}
+		sort.Slice(matched, func(i, j int) bool {
+			return matched[i].name < matched[j].name
+		})
+		selected = append(selected, matched...)
+	}
+	return selected
+}
+
+// parentLineno returns the line number in the parent of line newLineno in the commit according to the diff of
+// the file between them, ok is false when the line was added by the commit
+func parentLineno(file *fileDiff, newLineno int) (oldLineno int, ok bool) {
+	// the lines between the changes are the same on both sides, oldNext and newNext are the next line numbers of them
+	oldNext, newNext := 1, 1
+	for _, hunk := range file.hunks {
+		for _, line := range hunk {
+			// skip the unchanged lines before the line
+			gap := line.newLineno - newNext
+			if line.oldLineno > 0 {
+				gap = line.oldLineno - oldNext
+			}
+			if newLineno < newNext+gap {
+				return oldNext + newLineno - newNext, true
+			}
+			oldNext += gap
+			newNext += gap
+			if line.newLineno == newLineno {
+				if line.origin == lineAddition {
+					return 0, false
+				}
+				return line.oldLineno, true
+			}
+			if line.oldLineno > 0 {
+				oldNext++
+			}
+			if line.newLineno > 0 {
+				newNext++
+			}
+		}
+	}
+	return oldNext + newLineno - newNext, true
+}
+
+// fileSnapshot is the commit which changed each line last for all files at a commit
+type fileSnapshot map[string] /*file path*/ *models.FileBlame
+
+func (s fileSnapshot) clone() fileSnapshot {
+	clone := make(fileSnapshot, len(s))
+	for path, fileBlame := range s {
+		clone[path] = fileBlame.Clone()
+	}
+	return clone
+}
+
+// blame returns the commit which changed the line last, or an empty string if it is unknown
+func (s fileSnapshot) blame(path string, lineNo int) string {
+	fileBlame, ok := s[path]
+	if !ok {
+		return ""
+	}
+	l := fileBlame.Find(lineNo)
+	if l == nil || l.Value == nil {
+		return ""
+	}
+	return l.Value.(string)
+}
+
+// diffLineCollector stores the line changes of the commits on the selected branches, and maintains a snapshot of
+// the files at each commit to find out which commit each deleted line belongs to
+type diffLineCollector struct {
+	store    models.Store
+	logger   core.Logger
+	source   diffLineSource
+	repoId   string
+	parents  map[string][]string
+	children map[string][]string
+	// remaining is the number of children not processed yet, the snapshot of a commit is released when it reaches 0
+	remaining map[string]int
+	snapshots map[string]fileSnapshot
+}
+
+func newDiffLineCollector(store models.Store, logger core.Logger, source diffLineSource, repoId string) *diffLineCollector {
+	return &diffLineCollector{
+		store:     store,
+		logger:    logger,
+		source:    source,
+		repoId:    repoId,
+		parents:   make(map[string][]string),
+		children:  make(map[string][]string),
+		remaining: make(map[string]int),
+		snapshots: make(map[string]fileSnapshot),
+	}
+}
+
+// collectDiffLines collects the line changes of the commits on the branches given by names and pattern along with
+// the branch of HEAD, and stores the branches of each commit and the snapshot of each branch
+func collectDiffLines(
+	subtaskCtx core.SubTaskContext,
+	store models.Store,
+	source diffLineSource,
+	repoId string,
+	names []string,
+	pattern *regexp.Regexp,
+) errors.Error {
+	logger := subtaskCtx.GetLogger()
+	var tips []*branchTip
+	if len(names) > 0 || pattern != nil {
+		all, err := source.branchTips()
+		if err != nil {
+			return err
+		}
+		tips = selectBranches(all, names, pattern, logger)
+	}
+	// the branch of HEAD is the default one, it is always collected since refdiff calculates the code ownership
+	// on its snapshot
+	head, err := source.headBranch()
+	if err != nil {
+		return err
+	}
+	if head != nil {
+		selected := false
+		for _, tip := range tips {
+			selected = selected || tip.name == head.name
+		}
+		if !selected {
+			tips = append(tips, head)
+		}
+	}
+	if len(tips) == 0 {
+		logger.Info("no branch to collect diff lines on")
+		return nil
+	}
+	collector := newDiffLineCollector(store, logger, source, repoId)
+	err = collector.collect(subtaskCtx, tips)
+	if err != nil {
+		return err
+	}
+	logger.Info("collect diff lines finished")
+	return nil
+}
+
+// collect walks the commits of the branches from the oldest to the newest, so the snapshot of the parents is always
+// ready when a commit is processed
+func (c *diffLineCollector) collect(subtaskCtx core.SubTaskContext, tips []*branchTip) errors.Error {
+	// the branches may be rewritten or removed since the last run, so their snapshots and commits are rebuilt
+	err := c.store.ResetBranchSnapshots(c.repoId)
+	if err != nil {
+		return err
+	}
+	for _, tip := range tips {
+		err = c.walk(tip)
+		if err != nil {
+			return err
+		}
+	}
+	tipsOf := make(map[string][]string)
+	for _, tip := range tips {
+		tipsOf[tip.sha] = append(tipsOf[tip.sha], tip.name)
+	}
+	subtaskCtx.SetProgress(0, len(c.parents))
+	// Kahn's algorithm, pending holds the number of parents not processed yet
+	pending := make(map[string]int, len(c.parents))
+	var stack []string
+	for sha, parents := range c.parents {
+		pending[sha] = len(parents)
+		if len(parents) == 0 {
+			stack = append(stack, sha)
+		}
+	}
+	sort.Strings(stack)
+	for len(stack) > 0 {
+		select {
+		case <-subtaskCtx.GetContext().Done():
+			return errors.Convert(subtaskCtx.GetContext().Err())
+		default:
+		}
+		sha := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		snapshot, err := c.collectCommit(subtaskCtx, sha)
+		if err != nil {
+			return err
+		}
+		for _, branch := range tipsOf[sha] {
+			err = c.storeSnapshot(branch, snapshot)
+			if err != nil {
+				return err
+			}
+		}
+		if c.remaining[sha] > 0 {
+			c.snapshots[sha] = snapshot
+		}
+		for _, child := range c.children[sha] {
+			pending[child]--
+			if pending[child] == 0 {
+				stack = append(stack, child)
+			}
+		}
+		subtaskCtx.IncProgress(1)
+	}
+	return nil
+}
+
+// walk stores the branch of the commits reachable from the tip, and loads the parents of the ones not found on the
+// previous branches
+func (c *diffLineCollector) walk(tip *branchTip) errors.Error {
+	visited := make(map[string]bool)
+	stack := []string{tip.sha}
+	for len(stack) > 0 {
+		sha := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if visited[sha] {
+			continue
+		}
+		visited[sha] = true
+		err := c.store.CommitBranches(&code.CommitBranch{
+			RepoId:    c.repoId,
+			CommitSha: sha,
+			Branch:    tip.name,
+		})
+		if err != nil {
+			return err
+		}
+		if parents, ok := c.parents[sha]; ok {
+			stack = append(stack, parents...)
+			continue
+		}
+		parents, err := c.source.commitParents(sha)
+		if err != nil {
+			return err
+		}
+		// the same parent may be listed twice, i.e. a merge of a branch into itself
+		var uniqueParents []string
+		for _, parent := range parents {
+			duplicated := false
+			for _, p := range uniqueParents {
+				duplicated = duplicated || p == parent
+			}
+			if !duplicated {
+				uniqueParents = append(uniqueParents, parent)
+			}
+		}
+		c.parents[sha] = uniqueParents
+		for _, parent := range uniqueParents {
+			c.children[parent] = append(c.children[parent], sha)
+			c.remaining[parent]++
+			stack = append(stack, parent)
+		}
+	}
+	return nil
+}
+
+// release drops the snapshot of the parent once all of its children are processed
+func (c *diffLineCollector) release(parent string) {
+	c.remaining[parent]--
+	if c.remaining[parent] == 0 {
+		delete(c.snapshots, parent)
+	}
+}
+
+// collectCommit stores the line changes of the commit and returns the snapshot at the commit.
+// The changes are taken against the first parent. For a merge commit, a line is blamed on the commit which
+// changed it last on the parent it is unchanged from, and only the changes to the files different from all
+// parents are recorded without the lines coming from the other parents, since they were recorded on the
+// merged commits already.
+func (c *diffLineCollector) collectCommit(subtaskCtx core.SubTaskContext, sha string) (fileSnapshot, errors.Error) {
+	parents := c.parents[sha]
+	var snapshot fileSnapshot
+	if len(parents) == 0 {
+		snapshot = make(fileSnapshot)
+	} else if c.remaining[parents[0]] == 1 {
+		// the last child takes over the snapshot of the parent instead of copying it
+		snapshot = c.snapshots[parents[0]]
+	} else {
+		snapshot = c.snapshots[parents[0]].clone()
+	}
+	firstParent := ""
+	if len(parents) > 0 {
+		firstParent = parents[0]
+	}
+	files, err := c.source.commitDiff(subtaskCtx, sha, firstParent)
+	if err != nil {
+		return nil, err
+	}
+	// the diffs against the other parents by the file path
+	otherDiffs := make([]map[string]*fileDiff, len(parents))
+	for k := 1; k < len(parents); k++ {
+		diffs, err := c.source.commitDiff(subtaskCtx, sha, parents[k])
+		if err != nil {
+			return nil, err
+		}
+		otherDiffs[k] = make(map[string]*fileDiff, len(diffs))
+		for i := range diffs {
+			otherDiffs[k][diffs[i].newPath] = &diffs[i]
+		}
+	}
+	// a file may have more than one delta, i.e. when it is changed into a symlink, they are applied together
+	for i, j := 0, 0; i < len(files); i = j {
+		for j = i + 1; j < len(files) && files[j].newPath == files[i].newPath; j++ {
+		}
+		err = c.collectFile(sha, files[i:j], parents, otherDiffs, snapshot)
+		if err != nil {
+			return nil, err
+		}
+	}
+	for _, parent := range parents {
+		c.release(parent)
+	}
+	return snapshot, nil
+}
+
+// inheritedBlame returns the commit which changed the line last on one of the other parents of a merge commit,
+// or an empty string if the line is new to all of them
+func (c *diffLineCollector) inheritedBlame(file *fileDiff, lineNo int, parents []string, otherDiffs []map[string]*fileDiff) string {
+	for k := 1; k < len(parents); k++ {
+		parentFile, changed := otherDiffs[k][file.newPath]
+		parentPath, parentLine := file.newPath, lineNo
+		if changed {
+			var ok bool
+			parentLine, ok = parentLineno(parentFile, lineNo)
+			if !ok {
+				continue
+			}
+			parentPath = parentFile.oldPath
+		}
+		if blame := c.snapshots[parents[k]].blame(parentPath, parentLine); blame != "" {
+			return blame
+		}
+	}
+	return ""
+}
+
+// collectFile stores the line changes of the deltas of a file and applies them to the snapshot
+func (c *diffLineCollector) collectFile(
+	sha string,
+	deltas []fileDiff,
+	parents []string,
+	otherDiffs []map[string]*fileDiff,
+	snapshot fileSnapshot,
+) errors.Error {
+	oldPath, newPath := deltas[0].oldPath, deltas[0].newPath
+	isMerge := len(parents) > 1
+	// for a merge commit, the file is recorded only when it is different from all parents
+	record := true
+	for k := 1; k < len(parents); k++ {
+		if _, changed := otherDiffs[k][newPath]; !changed {
+			record = false
+		}
+	}
+	if _, ok := snapshot[oldPath]; !ok {
+		fileBlame, err := models.NewFileBlame()
+		if err != nil {
+			return errors.Convert(err)
+		}
+		snapshot[oldPath] = fileBlame
+	}
+	type addedLine struct {
+		lineNo int
+		blame  string
+	}
+	var deleted []int
+	var added []addedLine
+	for d := range deltas {
+		file := &deltas[d]
+		for i, hunk := range file.hunks {
+			hunkNum := i + 1
+			for _, line := range hunk {
+				commitLineChange := &code.CommitLineChange{}
+				commitLineChange.CommitSha = sha
+				commitLineChange.ChangedType = line.origin
+				commitLineChange.LineNoNew = line.newLineno
+				commitLineChange.LineNoOld = line.oldLineno
+				commitLineChange.OldFilePath = file.oldPath
+				commitLineChange.NewFilePath = file.newPath
+				commitLineChange.HunkNum = hunkNum
+				commitLineChange.Id = sha + ":" + file.newPath + ":" + strconv.Itoa(line.oldLineno) + ":" + strconv.Itoa(line.newLineno)
+				if line.origin == lineAddition {
+					blame := ""
+					if isMerge {
+						blame = c.inheritedBlame(file, line.newLineno, parents, otherDiffs)
+					}
+					if blame == "" {
+						blame = sha
+					}
+					added = append(added, addedLine{lineNo: line.newLineno, blame: blame})
+					if blame != sha {
+						continue
+					}
+				} else if line.origin == lineDeletion {
+					if prevCommit := snapshot.blame(file.oldPath, line.oldLineno); prevCommit != "" {
+						commitLineChange.PrevCommit = prevCommit
+					} else {
+						c.logger.Warn(nil, "line %d of %s not found in blame of %s", line.oldLineno, file.oldPath, sha)
+					}
+					deleted = append(deleted, line.oldLineno)
+				}
+				if isMerge && (!record || line.origin == lineContext) {
+					continue
+				}
+				err := c.store.CommitLineChange(commitLineChange)
+				if err != nil {
+					return err
+				}
+			}
+		}
+	}
+	// remove the lines from the bottom, so the line numbers of the rest would not change
+	fileBlame := snapshot[oldPath]
+	sort.Sort(sort.Reverse(sort.IntSlice(deleted)))
+	for _, lineNo := range deleted {
+		fileBlame.RemoveLine(lineNo)
+	}
+	for _, line := range added {
+		fileBlame.AddLine(line.lineNo, line.blame)
+	}
+	delete(snapshot, oldPath)
+	if fileBlame.Lines.Len() > 0 {
+		snapshot[newPath] = fileBlame
+	}
+	return nil
+}
+
+// storeSnapshot stores the commit which changed each line last at the head of the branch
+func (c *diffLineCollector) storeSnapshot(branch string, snapshot fileSnapshot) errors.Error {
+	for filePath, fileBlame := range snapshot {
+		lineNo := 0
+		for e := fileBlame.Lines.Front(); e != nil; e = e.Next() {
+			lineNo++
+			commitSha, _ := e.Value.(string)
+			snapshotLine := &code.RepoSnapshot{
+				RepoId:    c.repoId,
+				Branch:    branch,
+				FilePath:  filePath,
+				LineNo:    lineNo,
+				CommitSha: commitSha,
+			}
+			err := c.store.RepoSnapshot(snapshotLine)
+			if err != nil {
+				return errors.Default.Wrap(err, fmt.Sprintf("failed to store the snapshot of %s", filePath))
+			}
+		}
+	}
+	return nil
+}
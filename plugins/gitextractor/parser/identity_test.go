@@ -0,0 +1,76 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package parser
+
+import (
+	"testing"
+
+	"github.com/apache/incubator-devlake/models/domainlayer/code"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMailmapResolve(t *testing.T) {
+	mailmap := NewMailmap()
+	mailmap.Parse(`
+# comments are ignored
+Joe Developer <joe@example.com>
+<jane@example.com> <jane@laptop.(none)>
+Jane Doe <jane@example.com> jane <Jane@Desktop.(none)>
+Jane Doe <jane@example.com>
+`)
+	tests := []struct {
+		name, email, wantName, wantEmail string
+	}{
+		{"joe", "joe@example.com", "Joe Developer", "joe@example.com"},
+		{"Jane", "jane@laptop.(none)", "Jane", "jane@example.com"},
+		{"jane", "jane@desktop.(none)", "Jane Doe", "jane@example.com"},
+		{"somebody", "jane@desktop.(none)", "somebody", "jane@desktop.(none)"},
+		{"jane", "jane@example.com", "Jane Doe", "jane@example.com"},
+		{"unknown", "unknown@example.com", "unknown", "unknown@example.com"},
+	}
+	for _, tt := range tests {
+		name, email := mailmap.Resolve(tt.name, tt.email)
+		assert.Equal(t, tt.wantName, name)
+		assert.Equal(t, tt.wantEmail, email)
+	}
+
+	// the extra mailmap takes precedence
+	mailmap.Parse("Joseph <joseph@example.com> <joe@example.com>")
+	name, email := mailmap.Resolve("joe", "joe@example.com")
+	assert.Equal(t, "Joseph", name)
+	assert.Equal(t, "joseph@example.com", email)
+}
+
+func TestParseContributorTrailers(t *testing.T) {
+	message := `Fix the login page (#123)
+
+* fix the style
+* Reviewed-by: not a trailer <nobody@example.com> in the body
+
+Co-authored-by: Jane Doe <jane@example.com>
+Reviewed-by: Joe <joe@example.com>
+Signed-off-by: Jane Doe <jane@example.com>
+Change-Id: I8f2e1
+`
+	assert.Equal(t, []CommitTrailer{
+		{Role: code.COMMIT_CO_AUTHOR, Name: "Jane Doe", Email: "jane@example.com"},
+		{Role: code.COMMIT_REVIEWER, Name: "Joe", Email: "joe@example.com"},
+		{Role: code.COMMIT_SIGNER, Name: "Jane Doe", Email: "jane@example.com"},
+	}, ParseContributorTrailers(message))
+	assert.Empty(t, ParseContributorTrailers("Fix typo"))
+}
@@ -0,0 +1,102 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package parser
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Mailmap maps the names and emails recorded in commits to the canonical ones, see `git help gitmailmap`
+type Mailmap struct {
+	// entries matching both name and email, keyed by lower(email) + "\x00" + lower(name)
+	byNameEmail map[string]mailmapEntry
+	// entries matching email only, keyed by lower(email)
+	byEmail map[string]mailmapEntry
+}
+
+type mailmapEntry struct {
+	name  string
+	email string
+}
+
+var mailmapIdentityPattern = regexp.MustCompile(`\s*([^<#]*?)\s*<([^>]*)>`)
+
+// NewMailmap returns an empty Mailmap which resolves identities to themselves
+func NewMailmap() *Mailmap {
+	return &Mailmap{
+		byNameEmail: make(map[string]mailmapEntry),
+		byEmail:     make(map[string]mailmapEntry),
+	}
+}
+
+// Parse adds the entries in the content of a .mailmap file, later entries take precedence over earlier ones
+func (m *Mailmap) Parse(content string) {
+	for _, line := range strings.Split(content, "\n") {
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = line[:i]
+		}
+		identities := mailmapIdentityPattern.FindAllStringSubmatch(line, 2)
+		switch len(identities) {
+		case 1:
+			// Proper Name <commit@email.xx>
+			addMailmapEntry(m.byEmail, strings.ToLower(identities[0][2]), mailmapEntry{name: identities[0][1]})
+		case 2:
+			// [Proper Name] <proper@email.xx> [Commit Name] <commit@email.xx>
+			entry := mailmapEntry{name: identities[0][1], email: identities[0][2]}
+			commitName, commitEmail := identities[1][1], strings.ToLower(identities[1][2])
+			if commitName == "" {
+				addMailmapEntry(m.byEmail, commitEmail, entry)
+			} else {
+				addMailmapEntry(m.byNameEmail, commitEmail+"\x00"+strings.ToLower(commitName), entry)
+			}
+		}
+	}
+}
+
+// addMailmapEntry merges the entry into the existing one, so `Proper Name <commit@email.xx>` and
+// `<proper@email.xx> <commit@email.xx>` could be given in separate lines
+func addMailmapEntry(entries map[string]mailmapEntry, key string, entry mailmapEntry) {
+	existing := entries[key]
+	if entry.name != "" {
+		existing.name = entry.name
+	}
+	if entry.email != "" {
+		existing.email = entry.email
+	}
+	entries[key] = existing
+}
+
+// Resolve returns the canonical name and email, emails are matched case-insensitively
+func (m *Mailmap) Resolve(name, email string) (string, string) {
+	lowerEmail := strings.ToLower(email)
+	entry, ok := m.byNameEmail[lowerEmail+"\x00"+strings.ToLower(name)]
+	if !ok {
+		entry, ok = m.byEmail[lowerEmail]
+	}
+	if !ok {
+		return name, email
+	}
+	if entry.name != "" {
+		name = entry.name
+	}
+	if entry.email != "" {
+		email = entry.email
+	}
+	return name, email
+}
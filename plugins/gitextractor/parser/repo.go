@@ -1,3 +1,5 @@
+//go:build libgit2
+
 /*
 Licensed to the Apache Software Foundation (ASF) under one or more
 contributor license agreements.  See the NOTICE file distributed with
@@ -19,12 +21,9 @@ package parser
 
 import (
 	"context"
-	"crypto/sha256"
-	"encoding/hex"
 	"fmt"
 	"regexp"
-	"sort"
-	"strconv"
+	"strings"
 
 	"github.com/apache/incubator-devlake/errors"
 	"github.com/apache/incubator-devlake/models/domainlayer"
@@ -38,11 +37,38 @@ import (
 var TypeNotMatchError = "the requested type does not match the type in the ODB"
 
 type GitRepo struct {
-	store   models.Store
-	logger  core.Logger
-	id      string
-	repo    *git.Repository
-	cleanup func()
+	store      models.Store
+	logger     core.Logger
+	id         string
+	repo       *git.Repository
+	cleanup    func()
+	fullRescan bool
+	// extraMailmap is applied on top of the .mailmap in the repo
+	extraMailmap string
+	mailmap      *Mailmap
+	// diffLineBranches and diffLineBranchPattern select the branches CollectDiffLine works on
+	diffLineBranches      []string
+	diffLineBranchPattern *regexp.Regexp
+}
+
+func (r *GitRepo) setCleanup(cleanup func()) {
+	r.cleanup = cleanup
+}
+
+// SetFullRescan makes CollectCommits process all commits in the repo instead of the new ones only
+func (r *GitRepo) SetFullRescan(fullRescan bool) {
+	r.fullRescan = fullRescan
+}
+
+// SetMailmap sets the mailmap content to be applied on top of the .mailmap in the repo
+func (r *GitRepo) SetMailmap(mailmap string) {
+	r.extraMailmap = mailmap
+}
+
+// SetDiffLineBranches sets the branches to collect the diff lines on besides the branch of HEAD
+func (r *GitRepo) SetDiffLineBranches(branches []string, pattern *regexp.Regexp) {
+	r.diffLineBranches = branches
+	r.diffLineBranchPattern = pattern
 }
 
 // CollectAll The main parser subtask
@@ -228,11 +254,39 @@ func (r *GitRepo) CollectCommits(subtaskCtx core.SubTaskContext) errors.Error {
 	for _, component := range components {
 		componentMap[component.Name] = regexp.MustCompile(component.PathRegex)
 	}
+	r.mailmap, err = r.loadMailmap()
+	if err != nil {
+		return err
+	}
+	tips, err := r.commitTips()
+	if err != nil {
+		return err
+	}
+	if !r.fullRescan {
+		knownShas, lastTips, err := loadCommitState(db, r.id)
+		if err != nil {
+			return err
+		}
+		// fall back to the full scan on the first run
+		if len(knownShas) > 0 {
+			err = r.collectNewCommits(subtaskCtx, knownShas, tips, lastTips, opts, componentMap)
+			if err != nil {
+				return err
+			}
+			return r.store.RepoTips(r.id, newRepoTips(r.id, tips))
+		}
+	}
+	if count, err := r.CountCommits(subtaskCtx.GetContext()); err != nil {
+		subtaskCtx.GetLogger().Error(err, "unable to get commit count")
+		subtaskCtx.SetProgress(0, -1)
+	} else {
+		subtaskCtx.SetProgress(0, count)
+	}
 	odb, err := errors.Convert01(r.repo.Odb())
 	if err != nil {
 		return err
 	}
-	return errors.Convert(odb.ForEach(func(id *git.Oid) error {
+	err = errors.Convert(odb.ForEach(func(id *git.Oid) error {
 		select {
 		case <-subtaskCtx.GetContext().Done():
 			return subtaskCtx.GetContext().Err()
@@ -245,55 +299,202 @@ func (r *GitRepo) CollectCommits(subtaskCtx core.SubTaskContext) errors.Error {
 		if commit == nil {
 			return nil
 		}
-		commitSha := commit.Id().String()
-		r.logger.Debug("process commit: %s", commitSha)
-		c := &code.Commit{
-			Sha:     commitSha,
-			Message: commit.Message(),
-		}
-		author := commit.Author()
-		if author != nil {
-			c.AuthorName = author.Name
-			c.AuthorEmail = author.Email
-			c.AuthorId = author.Email
-			c.AuthoredDate = author.When
-		}
-		committer := commit.Committer()
-		if committer != nil {
-			c.CommitterName = committer.Name
-			c.CommitterEmail = committer.Email
-			c.CommitterId = committer.Email
-			c.CommittedDate = committer.When
-		}
-		err = r.storeParentCommits(commitSha, commit)
+		err = r.collectCommit(commit, opts, componentMap)
 		if err != nil {
 			return err
 		}
-		var parent *git.Commit
-		if commit.ParentCount() > 0 {
-			parent = commit.Parent(0)
+		subtaskCtx.IncProgress(1)
+		return nil
+	}))
+	if err != nil {
+		return err
+	}
+	// the tips are saved only after all commits are collected, so an interrupted run would be walked again
+	return r.store.RepoTips(r.id, newRepoTips(r.id, tips))
+}
+
+// collectNewCommits walks the commits reachable from the current branches and tags but not from the tips saved by
+// the last successful run, commits stored already are skipped
+func (r *GitRepo) collectNewCommits(
+	subtaskCtx core.SubTaskContext,
+	knownShas []string,
+	tips []string,
+	lastTips []string,
+	opts *git.DiffOptions,
+	componentMap map[string]*regexp.Regexp,
+) errors.Error {
+	known := make(map[string]struct{}, len(knownShas))
+	for _, sha := range knownShas {
+		known[sha] = struct{}{}
+	}
+	walk, err := r.repo.Walk()
+	if err != nil {
+		return errors.Convert(err)
+	}
+	defer walk.Free()
+	for _, sha := range tips {
+		oid, e := git.NewOid(sha)
+		if e != nil {
+			return errors.Convert(e)
+		}
+		if e = walk.Push(oid); e != nil {
+			return errors.Convert(e)
 		}
-		var stats *git.DiffStats
-		if stats, err = r.getDiffComparedToParent(c.Sha, commit, parent, opts, componentMap); err != nil {
-			return err
+	}
+	for _, sha := range lastTips {
+		oid, e := git.NewOid(sha)
+		if e != nil {
+			continue
 		}
-		c.Additions += stats.Insertions()
-		c.Deletions += stats.Deletions()
-		err = r.store.Commits(c)
-		if err != nil {
-			return err
+		// the tip might be gone after a force push, the known shas would take care of it
+		if e = walk.Hide(oid); e != nil {
+			r.logger.Debug("unable to hide the last tip %s: %s", sha, e)
+		}
+	}
+	newCommits := make([]*git.Oid, 0)
+	err = walk.Iterate(func(commit *git.Commit) bool {
+		if _, ok := known[commit.Id().String()]; !ok {
+			newCommits = append(newCommits, commit.Id())
+		}
+		return subtaskCtx.GetContext().Err() == nil
+	})
+	if err != nil {
+		return errors.Convert(err)
+	}
+	if err = subtaskCtx.GetContext().Err(); err != nil {
+		return errors.Convert(err)
+	}
+	r.logger.Info("found %d new commits in repo %s", len(newCommits), r.id)
+	subtaskCtx.SetProgress(0, len(newCommits))
+	for _, id := range newCommits {
+		select {
+		case <-subtaskCtx.GetContext().Done():
+			return errors.Convert(subtaskCtx.GetContext().Err())
+		default:
 		}
-		repoCommit := &code.RepoCommit{
-			RepoId:    r.id,
-			CommitSha: c.Sha,
+		commit, err := r.repo.LookupCommit(id)
+		if err != nil {
+			return errors.Convert(err)
 		}
-		err = r.store.RepoCommits(repoCommit)
+		err = r.collectCommit(commit, opts, componentMap)
 		if err != nil {
-			return err
+			return errors.Convert(err)
 		}
 		subtaskCtx.IncProgress(1)
-		return nil
-	}))
+	}
+	return nil
+}
+
+// commitTips returns the commits the branches, remote branches and tags point to
+func (r *GitRepo) commitTips() ([]string, errors.Error) {
+	iter, err := r.repo.NewReferenceIterator()
+	if err != nil {
+		return nil, errors.Convert(err)
+	}
+	defer iter.Free()
+	seen := make(map[string]bool)
+	var tips []string
+	for {
+		ref, err := iter.Next()
+		if git.IsErrorCode(err, git.ErrorCodeIterOver) {
+			return tips, nil
+		}
+		if err != nil {
+			return nil, errors.Convert(err)
+		}
+		if !(ref.IsBranch() || ref.IsRemote() || ref.IsTag()) {
+			continue
+		}
+		// tags might point to trees or blobs
+		obj, err := ref.Peel(git.ObjectCommit)
+		if err != nil {
+			continue
+		}
+		sha := obj.Id().String()
+		if !seen[sha] {
+			seen[sha] = true
+			tips = append(tips, sha)
+		}
+	}
+}
+
+func (r *GitRepo) collectCommit(commit *git.Commit, opts *git.DiffOptions, componentMap map[string]*regexp.Regexp) errors.Error {
+	commitSha := commit.Id().String()
+	r.logger.Debug("process commit: %s", commitSha)
+	c := &code.Commit{
+		Sha:     commitSha,
+		Message: commit.Message(),
+	}
+	author := commit.Author()
+	if author != nil {
+		c.AuthorName, c.AuthorEmail = r.mailmap.Resolve(author.Name, author.Email)
+		c.AuthorId = c.AuthorEmail
+		c.AuthoredDate = author.When
+	}
+	committer := commit.Committer()
+	if committer != nil {
+		c.CommitterName, c.CommitterEmail = r.mailmap.Resolve(committer.Name, committer.Email)
+		c.CommitterId = c.CommitterEmail
+		c.CommittedDate = committer.When
+	}
+	err := r.store.CommitContributors(commitContributors(c, r.mailmap))
+	if err != nil {
+		return err
+	}
+	err = r.storeParentCommits(commitSha, commit)
+	if err != nil {
+		return err
+	}
+	var parent *git.Commit
+	if commit.ParentCount() > 0 {
+		parent = commit.Parent(0)
+	}
+	var stats *git.DiffStats
+	if stats, err = r.getDiffComparedToParent(c.Sha, commit, parent, opts, componentMap); err != nil {
+		return err
+	}
+	c.Additions += stats.Insertions()
+	c.Deletions += stats.Deletions()
+	err = r.store.Commits(c)
+	if err != nil {
+		return err
+	}
+	repoCommit := &code.RepoCommit{
+		RepoId:    r.id,
+		CommitSha: c.Sha,
+	}
+	return r.store.RepoCommits(repoCommit)
+}
+
+// loadMailmap reads the .mailmap of the HEAD commit, the extra mailmap given in options is applied on top of it
+func (r *GitRepo) loadMailmap() (*Mailmap, errors.Error) {
+	mailmap := NewMailmap()
+	head, err := r.repo.Head()
+	// an empty repo has no HEAD
+	if err == nil {
+		var commit *git.Commit
+		var tree *git.Tree
+		var entry *git.TreeEntry
+		commit, err = r.repo.LookupCommit(head.Target())
+		if err != nil {
+			return nil, errors.Convert(err)
+		}
+		tree, err = commit.Tree()
+		if err != nil {
+			return nil, errors.Convert(err)
+		}
+		entry, err = tree.EntryByPath(".mailmap")
+		if err == nil {
+			var blob *git.Blob
+			blob, err = r.repo.LookupBlob(entry.Id)
+			if err != nil {
+				return nil, errors.Convert(err)
+			}
+			mailmap.Parse(string(blob.Contents()))
+		}
+	}
+	mailmap.Parse(r.extraMailmap)
+	return mailmap, nil
 }
 
 func (r *GitRepo) storeParentCommits(commitSha string, commit *git.Commit) errors.Error {
@@ -344,40 +545,27 @@ func (r *GitRepo) getDiffComparedToParent(commitSha string, commit *git.Commit,
 
 func (r *GitRepo) storeCommitFilesFromDiff(commitSha string, diff *git.Diff, componentMap map[string]*regexp.Regexp) errors.Error {
 	var commitFile *code.CommitFile
-	var commitFileComponent *code.CommitFileComponent
 	var err error
+	storeCommitFile := func() error {
+		err = r.store.CommitFiles(commitFile)
+		if err != nil {
+			r.logger.Error(err, "CommitFiles error")
+			return err
+		}
+		err = r.store.CommitFileComponents(newCommitFileComponent(commitFile, componentMap))
+		if err != nil {
+			r.logger.Error(err, "CommitFileComponents error")
+		}
+		return err
+	}
 	err = diff.ForEach(func(file git.DiffDelta, progress float64) (
 		git.DiffForEachHunkCallback, error) {
 		if commitFile != nil {
-			err = r.store.CommitFiles(commitFile)
-			if err != nil {
-				r.logger.Error(err, "CommitFiles error")
+			if err = storeCommitFile(); err != nil {
 				return nil, err
 			}
 		}
-
-		commitFile = new(code.CommitFile)
-		commitFile.CommitSha = commitSha
-		commitFile.FilePath = file.NewFile.Path
-
-		// With some long path,the varchar(255) was not enough both ID and file_path
-		// So we use the hash to compress the path in ID and add length of file_path.
-		// Use commitSha and the sha256 of FilePath to create id
-		shaFilePath := sha256.New()
-		shaFilePath.Write([]byte(file.NewFile.Path))
-		commitFile.Id = commitSha + ":" + hex.EncodeToString(shaFilePath.Sum(nil))
-
-		commitFileComponent = new(code.CommitFileComponent)
-		for component, reg := range componentMap {
-			if reg.MatchString(commitFile.FilePath) {
-				commitFileComponent.ComponentName = component
-				break
-			}
-		}
-		commitFileComponent.CommitFileId = commitFile.Id
-		if commitFileComponent.ComponentName == "" {
-			commitFileComponent.ComponentName = "Default"
-		}
+		commitFile = newCommitFile(commitSha, file.NewFile.Path)
 		return func(hunk git.DiffHunk) (git.DiffForEachLineCallback, error) {
 			return func(line git.DiffLine) error {
 				if line.Origin == git.DiffLineAddition {
@@ -390,185 +578,127 @@ func (r *GitRepo) storeCommitFilesFromDiff(commitSha string, diff *git.Diff, com
 			}, nil
 		}, nil
 	}, git.DiffDetailLines)
-	if commitFileComponent != nil {
-		err = r.store.CommitFileComponents(commitFileComponent)
-		if err != nil {
-			r.logger.Error(err, "CommitFileComponents error")
-		}
-	}
-	if commitFile != nil {
-		err = r.store.CommitFiles(commitFile)
-		if err != nil {
-			r.logger.Error(err, "CommitFiles error")
-		}
+	if err == nil && commitFile != nil {
+		err = storeCommitFile()
 	}
 	return errors.Convert(err)
 }
 
-// CollectDiffLine get line diff data from a specific branch
+// CollectDiffLine get line diff data from the selected branches
 func (r *GitRepo) CollectDiffLine(subtaskCtx core.SubTaskContext) errors.Error {
-	//Using this subtask,we can get every line change in every commit.
-	//We maintain a snapshot structure to get which commit each deleted line belongs to
-	snapshot := make(map[string] /*file path*/ *models.FileBlame)
-	repo := r.repo
-	//step 1. get the reverse commit list
-	commitList := make([]git.Commit, 0)
-	//get currently head commitsha, dafault is master branch
-	// check branch, if not master, checkout to branch's head
-	commitOid, err1 := repo.Head()
-	if err1 != nil && err1.Error() != TypeNotMatchError {
-		return errors.Convert(err1)
-	}
-	//get head commit object and add into commitList
-	commit, err1 := repo.LookupCommit(commitOid.Target())
-	if err1 != nil && err1.Error() != TypeNotMatchError {
-		return errors.Convert(err1)
-	}
-	commitList = append(commitList, *commit)
-	// if current head has parents, get parent commitsha
-	for commit != nil && commit.ParentCount() > 0 {
-		pid := commit.ParentId(0)
-		commit, err1 = repo.LookupCommit(pid)
-		if err1 != nil && err1.Error() != TypeNotMatchError {
-			return errors.Convert(err1)
-		}
-		commitList = append(commitList, *commit)
+	return collectDiffLines(subtaskCtx, r.store, r, r.id, r.diffLineBranches, r.diffLineBranchPattern)
+}
+
+func (r *GitRepo) headBranch() (*branchTip, errors.Error) {
+	head, err := r.repo.Head()
+	if git.IsErrorCode(err, git.ErrorCodeUnbornBranch) || git.IsErrorCode(err, git.ErrorCodeNotFound) {
+		return nil, nil
 	}
-	// reverse commitList
-	for i, j := 0, len(commitList)-1; i < j; i, j = i+1, j-1 {
-		commitList[i], commitList[j] = commitList[j], commitList[i]
+	if err != nil {
+		return nil, errors.Convert(err)
 	}
-	//step 2. get the diff of each commit
-	// for each commit, get the diff
-	for _, commitsha := range commitList {
-		curcommit, err := repo.LookupCommit(commitsha.Id())
-		if err != nil {
-			return errors.Convert(err)
-		}
-		if curcommit.ParentCount() == 0 || curcommit.ParentCount() > 0 {
-			var parentTree, tree *git.Tree
-			tree, err = curcommit.Tree()
-			if err != nil {
-				return errors.Convert(err)
-			}
-			var diff *git.Diff
-			//FIXME error type convert
-			opts, err := git.DefaultDiffOptions()
-			opts.NotifyCallback = func(diffSoFar *git.Diff, delta git.DiffDelta, matchedPathSpec string) error {
-				return nil
-			}
-			if err != nil {
-				return errors.Convert(err)
-			}
-			if curcommit.ParentCount() > 0 {
-				parent := curcommit.Parent(0)
-				parentTree, err = parent.Tree()
-			}
-			diff, err = repo.DiffTreeToTree(parentTree, tree, &opts)
-			if err != nil {
-				return errors.Convert(err)
-			}
-			deleted := make(models.DiffLines, 0)
-			added := make(models.DiffLines, 0)
-			var lastFile string
-			lastFile = ""
-			err = diff.ForEach(func(file git.DiffDelta, progress float64) (git.DiffForEachHunkCallback, error) {
-				//if doesn't exist in snapshot, create a new one
-				if _, ok := snapshot[file.OldFile.Path]; !ok {
-					fileBlame, err := models.NewFileBlame()
-					if err != nil {
-						r.logger.Info("Create FileBlame Error")
-						return nil, err
-					}
-					snapshot[file.OldFile.Path] = (*models.FileBlame)(fileBlame)
-				}
-				if lastFile == "" {
-					lastFile = file.NewFile.Path
-				} else if lastFile != file.NewFile.Path {
-					updateSnapshotFileBlame(curcommit, deleted, added, lastFile, snapshot)
-					//reset the deleted and added,last_file now is current file
-					deleted = make([]git.DiffLine, 0)
-					added = make([]git.DiffLine, 0)
-					lastFile = file.NewFile.Path
-				}
-				hunkNum := 0
-				return func(hunk git.DiffHunk) (git.DiffForEachLineCallback, error) {
-					hunkNum++
-					return func(line git.DiffLine) error {
-						commitLineChange := &code.CommitLineChange{}
-						commitLineChange.CommitSha = curcommit.Id().String()
-						commitLineChange.ChangedType = line.Origin.String()
-						commitLineChange.LineNoNew = line.NewLineno
-						commitLineChange.LineNoOld = line.OldLineno
-						commitLineChange.OldFilePath = file.OldFile.Path
-						commitLineChange.NewFilePath = file.NewFile.Path
-						commitLineChange.HunkNum = hunkNum
-						commitLineChange.Id = curcommit.Id().String() + ":" + file.NewFile.Path + ":" + strconv.Itoa(line.OldLineno) + ":" + strconv.Itoa(line.NewLineno)
-						if line.Origin == git.DiffLineAddition {
-							added = append(added, line)
-						} else if line.Origin == git.DiffLineDeletion {
-							fb := snapshot[file.OldFile.Path]
-							l := fb.Find(line.OldLineno)
-							if l != nil && l.Value != nil {
-								temp := snapshot[file.OldFile.Path].Find(line.OldLineno)
-								commitLineChange.PrevCommit = temp.Value.(string)
-							} else {
-								r.logger.Info("err", file.OldFile.Path, line.OldLineno, curcommit.Id().String())
-							}
-							deleted = append(deleted, line)
-						}
-						err = r.store.CommitLineChange(commitLineChange)
-						if err != nil {
-							return errors.Convert(err)
-						}
-						return nil
-					}, nil
-				}, nil
-			}, git.DiffDetailLines)
-			if err != nil {
-				return errors.Convert(err)
-			}
-			//finally,process the last file in diff
-			updateSnapshotFileBlame(curcommit, deleted, added, lastFile, snapshot)
-		}
+	name := "HEAD"
+	if head.IsBranch() {
+		name = strings.TrimPrefix(head.Name(), "refs/heads/")
 	}
-	r.logger.Info("line change collect success")
-	db := subtaskCtx.GetDal()
-	err := db.Delete(&code.RepoSnapshot{}, dal.Where("repo_id= ?", r.id))
+	return &branchTip{name: name, sha: head.Target().String()}, nil
+}
+
+func (r *GitRepo) branchTips() ([]*branchTip, errors.Error) {
+	branchIter, err := r.repo.NewBranchIterator(git.BranchAll)
 	if err != nil {
-		return errors.Convert(err)
+		return nil, errors.Convert(err)
 	}
-	for fp := range snapshot {
-		temp := snapshot[fp]
-		count := 0
-		for e := temp.Lines.Front(); e != nil; e = e.Next() {
-			count++
-			snapshotLine := &code.RepoSnapshot{}
-			snapshotLine.RepoId = r.id
-			snapshotLine.LineNo = count
-			snapshotLine.CommitSha = e.Value.(string)
-			snapshotLine.FilePath = fp
-			err := r.store.RepoSnapshot(snapshotLine)
-			if err != nil {
-				r.logger.Info("error")
-				return err
-			}
+	var tips []*branchTip
+	err = branchIter.ForEach(func(branch *git.Branch, branchType git.BranchType) error {
+		// skip the symbolic ones like origin/HEAD
+		if !(branch.IsBranch() || branch.IsRemote()) || branch.Target() == nil {
+			return nil
 		}
+		name, err1 := branch.Name()
+		if err1 != nil && err1.Error() != TypeNotMatchError {
+			return err1
+		}
+		tips = append(tips, &branchTip{name: name, sha: branch.Target().String()})
+		return nil
+	})
+	return tips, errors.Convert(err)
+}
 
+func (r *GitRepo) lookupCommit(sha string) (*git.Commit, errors.Error) {
+	oid, err := git.NewOid(sha)
+	if err != nil {
+		return nil, errors.Convert(err)
 	}
+	commit, err := r.repo.LookupCommit(oid)
+	if err != nil {
+		return nil, errors.Convert(err)
+	}
+	return commit, nil
+}
 
-	r.logger.Info("collect snapshot finished")
-	return nil
+func (r *GitRepo) commitParents(sha string) ([]string, errors.Error) {
+	commit, err := r.lookupCommit(sha)
+	if err != nil {
+		return nil, err
+	}
+	parents := make([]string, 0, commit.ParentCount())
+	for i := uint(0); i < commit.ParentCount(); i++ {
+		parents = append(parents, commit.ParentId(i).String())
+	}
+	return parents, nil
 }
 
-func updateSnapshotFileBlame(currentCommit *git.Commit, deleted models.DiffLines, added models.DiffLines, lastFile string, snapshot map[string]*models.FileBlame) {
-	sort.Sort(deleted)
-	for _, line := range deleted {
-		snapshot[lastFile].RemoveLine(line.OldLineno)
+func (r *GitRepo) commitDiff(subtaskCtx core.SubTaskContext, sha, parentSha string) ([]fileDiff, errors.Error) {
+	commit, err := r.lookupCommit(sha)
+	if err != nil {
+		return nil, err
+	}
+	tree, err1 := commit.Tree()
+	if err1 != nil {
+		return nil, errors.Convert(err1)
+	}
+	var parentTree *git.Tree
+	if parentSha != "" {
+		parent, err := r.lookupCommit(parentSha)
+		if err != nil {
+			return nil, err
+		}
+		parentTree, err1 = parent.Tree()
+		if err1 != nil {
+			return nil, errors.Convert(err1)
+		}
+	}
+	opts, err := getDiffOpts()
+	if err != nil {
+		return nil, err
+	}
+	diff, err1 := r.repo.DiffTreeToTree(parentTree, tree, opts)
+	if err1 != nil {
+		return nil, errors.Convert(err1)
 	}
-	for _, line := range added {
-		snapshot[lastFile].AddLine(line.NewLineno, currentCommit.Id().String())
+	defer diff.Free()
+	var files []fileDiff
+	err1 = diff.ForEach(func(file git.DiffDelta, progress float64) (git.DiffForEachHunkCallback, error) {
+		files = append(files, fileDiff{oldPath: file.OldFile.Path, newPath: file.NewFile.Path})
+		fileIdx := len(files) - 1
+		return func(hunk git.DiffHunk) (git.DiffForEachLineCallback, error) {
+			files[fileIdx].hunks = append(files[fileIdx].hunks, nil)
+			hunkIdx := len(files[fileIdx].hunks) - 1
+			return func(line git.DiffLine) error {
+				files[fileIdx].hunks[hunkIdx] = append(files[fileIdx].hunks[hunkIdx], diffLine{
+					origin:    line.Origin.String(),
+					oldLineno: line.OldLineno,
+					newLineno: line.NewLineno,
+				})
+				return nil
+			}, nil
+		}, nil
+	}, git.DiffDetailLines)
+	if err1 != nil {
+		return nil, errors.Convert(err1)
 	}
+	return files, nil
 }
 
 func getDiffOpts() (*git.DiffOptions, errors.Error) {
@@ -0,0 +1,46 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package parser
+
+import (
+	"context"
+	"regexp"
+
+	"github.com/apache/incubator-devlake/errors"
+	"github.com/apache/incubator-devlake/plugins/core"
+)
+
+// RepoCollector is implemented by the git backends, GitRepo on libgit2 and GoGitRepo on go-git
+type RepoCollector interface {
+	// SetFullRescan makes CollectCommits process all commits in the repo instead of the new ones only
+	SetFullRescan(fullRescan bool)
+	// SetMailmap sets the mailmap content to be applied on top of the .mailmap in the repo
+	SetMailmap(mailmap string)
+	// SetDiffLineBranches sets the branches to collect the diff lines on besides the branch of HEAD
+	SetDiffLineBranches(branches []string, pattern *regexp.Regexp)
+	CountTags() (int, errors.Error)
+	CountBranches(ctx context.Context) (int, errors.Error)
+	CountCommits(ctx context.Context) (int, errors.Error)
+	CollectAll(subtaskCtx core.SubTaskContext) errors.Error
+	CollectTags(subtaskCtx core.SubTaskContext) errors.Error
+	CollectBranches(subtaskCtx core.SubTaskContext) errors.Error
+	CollectCommits(subtaskCtx core.SubTaskContext) errors.Error
+	CollectDiffLine(subtaskCtx core.SubTaskContext) errors.Error
+	Close() errors.Error
+	setCleanup(cleanup func())
+}
@@ -21,7 +21,7 @@ import (
 	"github.com/apache/incubator-devlake/errors"
 	"github.com/apache/incubator-devlake/plugins/core"
 	"github.com/apache/incubator-devlake/plugins/gitextractor/models"
-	git "github.com/libgit2/git2go/v33"
+	gogit "github.com/go-git/go-git/v5"
 )
 
 const (
@@ -41,20 +41,16 @@ func NewGitRepoCreator(store models.Store, logger core.Logger) *GitRepoCreator {
 	}
 }
 
-// LocalRepo open a local repository
-func (l *GitRepoCreator) LocalRepo(repoPath, repoId string) (*GitRepo, errors.Error) {
-	repo, err := git.OpenRepository(repoPath)
+// LocalGoGitRepo open a local repository with go-git
+func (l *GitRepoCreator) LocalGoGitRepo(repoPath, repoId string) (*GoGitRepo, errors.Error) {
+	repo, err := gogit.PlainOpen(repoPath)
 	if err != nil {
 		return nil, errors.Convert(err)
 	}
-	return l.newGitRepo(repoId, repo), nil
-}
-
-func (l *GitRepoCreator) newGitRepo(repoId string, repo *git.Repository) *GitRepo {
-	return &GitRepo{
+	return &GoGitRepo{
 		store:  l.store,
 		logger: l.logger,
 		id:     repoId,
 		repo:   repo,
-	}
+	}, nil
 }
@@ -0,0 +1,44 @@
+//go:build !libgit2
+
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package parser
+
+import (
+	"github.com/apache/incubator-devlake/errors"
+)
+
+// the binary is built without the libgit2 tag, so the go-git backend is used in place of libgit2
+
+// LocalRepo open a local repository with go-git since libgit2 is not available
+func (l *GitRepoCreator) LocalRepo(repoPath, repoId string) (RepoCollector, errors.Error) {
+	l.logger.Info("libgit2 is not available, go-git is used instead")
+	return l.LocalGoGitRepo(repoPath, repoId)
+}
+
+// CloneOverHTTP clones the repo with go-git since libgit2 is not available
+func (l *GitRepoCreator) CloneOverHTTP(repoId, url, user, password, proxy string) (RepoCollector, errors.Error) {
+	l.logger.Info("libgit2 is not available, go-git is used instead")
+	return l.CloneGoGitOverHTTP(repoId, url, user, password, proxy)
+}
+
+// CloneOverSSH clones the repo with go-git since libgit2 is not available
+func (l *GitRepoCreator) CloneOverSSH(repoId, url, privateKey, passphrase string) (RepoCollector, errors.Error) {
+	l.logger.Info("libgit2 is not available, go-git is used instead")
+	return l.CloneGoGitOverSSH(repoId, url, privateKey, passphrase)
+}
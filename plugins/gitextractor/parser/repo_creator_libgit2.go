@@ -0,0 +1,79 @@
+//go:build libgit2
+
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package parser
+
+import (
+	"encoding/base64"
+	"fmt"
+
+	"github.com/apache/incubator-devlake/errors"
+	git "github.com/libgit2/git2go/v33"
+)
+
+// LocalRepo open a local repository
+func (l *GitRepoCreator) LocalRepo(repoPath, repoId string) (*GitRepo, errors.Error) {
+	repo, err := git.OpenRepository(repoPath)
+	if err != nil {
+		return nil, errors.Convert(err)
+	}
+	return l.newGitRepo(repoId, repo), nil
+}
+
+func (l *GitRepoCreator) newGitRepo(repoId string, repo *git.Repository) *GitRepo {
+	return &GitRepo{
+		store:  l.store,
+		logger: l.logger,
+		id:     repoId,
+		repo:   repo,
+	}
+}
+
+func (l *GitRepoCreator) CloneOverHTTP(repoId, url, user, password, proxy string) (RepoCollector, errors.Error) {
+	return withTempDirectory(func(dir string) (RepoCollector, error) {
+		cloneOptions := &git.CloneOptions{Bare: true}
+		if proxy != "" {
+			cloneOptions.FetchOptions.ProxyOptions.Type = git.ProxyTypeAuto
+			cloneOptions.FetchOptions.ProxyOptions.Url = proxy
+		}
+		if user != "" {
+			auth := fmt.Sprintf("Authorization: Basic %s", base64.StdEncoding.EncodeToString([]byte(user+":"+password)))
+			cloneOptions.FetchOptions.Headers = []string{auth}
+		}
+		clonedRepo, err := git.Clone(url, dir, cloneOptions)
+		if err != nil {
+			return nil, err
+		}
+		return l.newGitRepo(repoId, clonedRepo), nil
+	})
+}
+
+func (l *GitRepoCreator) CloneOverSSH(repoId, url, privateKey, passphrase string) (RepoCollector, errors.Error) {
+	return withTempDirectory(func(dir string) (RepoCollector, error) {
+		pk, err := base64.StdEncoding.DecodeString(privateKey)
+		if err != nil {
+			return nil, err
+		}
+		err = cloneOverSSH(url, dir, passphrase, pk)
+		if err != nil {
+			return nil, err
+		}
+		return l.LocalRepo(dir, repoId)
+	})
+}
@@ -0,0 +1,689 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package parser
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/apache/incubator-devlake/errors"
+	"github.com/apache/incubator-devlake/models/domainlayer"
+	"github.com/apache/incubator-devlake/models/domainlayer/code"
+	"github.com/apache/incubator-devlake/plugins/core"
+	"github.com/apache/incubator-devlake/plugins/core/dal"
+	"github.com/apache/incubator-devlake/plugins/gitextractor/models"
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	fdiff "github.com/go-git/go-git/v5/plumbing/format/diff"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// diffContextLines is the number of context lines around the changes in a hunk, same as the default of libgit2
+const diffContextLines = 3
+
+// GoGitRepo is the pure-Go counterpart of GitRepo, it produces the same records without the need of libgit2
+type GoGitRepo struct {
+	store      models.Store
+	logger     core.Logger
+	id         string
+	repo       *gogit.Repository
+	cleanup    func()
+	fullRescan bool
+	// extraMailmap is applied on top of the .mailmap in the repo
+	extraMailmap string
+	mailmap      *Mailmap
+	// diffLineBranches and diffLineBranchPattern select the branches CollectDiffLine works on
+	diffLineBranches      []string
+	diffLineBranchPattern *regexp.Regexp
+}
+
+func (r *GoGitRepo) setCleanup(cleanup func()) {
+	r.cleanup = cleanup
+}
+
+// SetFullRescan makes CollectCommits process all commits in the repo instead of the new ones only
+func (r *GoGitRepo) SetFullRescan(fullRescan bool) {
+	r.fullRescan = fullRescan
+}
+
+// SetMailmap sets the mailmap content to be applied on top of the .mailmap in the repo
+func (r *GoGitRepo) SetMailmap(mailmap string) {
+	r.extraMailmap = mailmap
+}
+
+// SetDiffLineBranches sets the branches to collect the diff lines on besides the branch of HEAD
+func (r *GoGitRepo) SetDiffLineBranches(branches []string, pattern *regexp.Regexp) {
+	r.diffLineBranches = branches
+	r.diffLineBranchPattern = pattern
+}
+
+// CollectAll The main parser subtask
+func (r *GoGitRepo) CollectAll(subtaskCtx core.SubTaskContext) errors.Error {
+	subtaskCtx.SetProgress(0, -1)
+	err := r.CollectTags(subtaskCtx)
+	if err != nil {
+		return err
+	}
+	err = r.CollectBranches(subtaskCtx)
+	if err != nil {
+		return err
+	}
+	err = r.CollectCommits(subtaskCtx)
+	if err != nil {
+		return err
+	}
+	return r.CollectDiffLine(subtaskCtx)
+}
+
+// Close resources
+func (r *GoGitRepo) Close() errors.Error {
+	defer func() {
+		if r.cleanup != nil {
+			r.cleanup()
+		}
+	}()
+	return r.store.Close()
+}
+
+// CountTags Count git tags subtask
+func (r *GoGitRepo) CountTags() (int, errors.Error) {
+	tags, err := r.repo.Tags()
+	if err != nil {
+		return 0, errors.Convert(err)
+	}
+	count := 0
+	err = tags.ForEach(func(*plumbing.Reference) error {
+		count++
+		return nil
+	})
+	return count, errors.Convert(err)
+}
+
+// CountBranches count the number of branches in a git repo
+func (r *GoGitRepo) CountBranches(ctx context.Context) (int, errors.Error) {
+	count := 0
+	err := r.forEachBranch(ctx, func(*plumbing.Reference, string) error {
+		count++
+		return nil
+	})
+	return count, err
+}
+
+// CountCommits count the number of commits in a git repo
+func (r *GoGitRepo) CountCommits(ctx context.Context) (int, errors.Error) {
+	commits, err := r.repo.CommitObjects()
+	if err != nil {
+		return 0, errors.Convert(err)
+	}
+	count := 0
+	err = commits.ForEach(func(*object.Commit) error {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+		count++
+		return nil
+	})
+	return count, errors.Convert(err)
+}
+
+// CollectTags Collect Tags data
+func (r *GoGitRepo) CollectTags(subtaskCtx core.SubTaskContext) errors.Error {
+	tags, err := r.repo.Tags()
+	if err != nil {
+		return errors.Convert(err)
+	}
+	return errors.Convert(tags.ForEach(func(tagRef *plumbing.Reference) error {
+		select {
+		case <-subtaskCtx.GetContext().Done():
+			return subtaskCtx.GetContext().Err()
+		default:
+		}
+		// annotated tags point to tag objects, lightweight tags point to the commits directly
+		tagCommit := tagRef.Hash().String()
+		tag, err1 := r.repo.TagObject(tagRef.Hash())
+		if err1 != nil && err1 != plumbing.ErrObjectNotFound {
+			return err1
+		}
+		if tag != nil {
+			tagCommit = tag.Target.String()
+		}
+		name := tagRef.Name().String()
+		ref := &code.Ref{
+			DomainEntity: domainlayer.DomainEntity{Id: fmt.Sprintf("%s:%s", r.id, name)},
+			RepoId:       r.id,
+			Name:         name,
+			CommitSha:    tagCommit,
+			RefType:      TAG,
+		}
+		err1 = r.store.Refs(ref)
+		if err1 != nil {
+			return err1
+		}
+		subtaskCtx.IncProgress(1)
+		return nil
+	}))
+}
+
+// CollectBranches Collect branch data
+func (r *GoGitRepo) CollectBranches(subtaskCtx core.SubTaskContext) errors.Error {
+	// an empty repo has no HEAD
+	head, err := r.repo.Reference(plumbing.HEAD, false)
+	if err != nil && err != plumbing.ErrReferenceNotFound {
+		return errors.Convert(err)
+	}
+	return r.forEachBranch(subtaskCtx.GetContext(), func(branch *plumbing.Reference, name string) error {
+		var sha string
+		if branch.Type() == plumbing.HashReference {
+			sha = branch.Hash().String()
+		}
+		ref := &code.Ref{
+			DomainEntity: domainlayer.DomainEntity{Id: fmt.Sprintf("%s:%s", r.id, name)},
+			RepoId:       r.id,
+			Name:         name,
+			CommitSha:    sha,
+			RefType:      BRANCH,
+			IsDefault:    head != nil && head.Type() == plumbing.SymbolicReference && head.Target() == branch.Name(),
+		}
+		err1 := r.store.Refs(ref)
+		if err1 != nil {
+			return err1
+		}
+		subtaskCtx.IncProgress(1)
+		return nil
+	})
+}
+
+// forEachBranch calls f with the local and remote branches along with their names as libgit2 gives,
+// i.e. `main` for refs/heads/main and `origin/main` for refs/remotes/origin/main
+func (r *GoGitRepo) forEachBranch(ctx context.Context, f func(branch *plumbing.Reference, name string) error) errors.Error {
+	refs, err := r.repo.References()
+	if err != nil {
+		return errors.Convert(err)
+	}
+	return errors.Convert(refs.ForEach(func(ref *plumbing.Reference) error {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+		refName := ref.Name().String()
+		if ref.Name().IsBranch() {
+			return f(ref, strings.TrimPrefix(refName, "refs/heads/"))
+		}
+		if ref.Name().IsRemote() {
+			return f(ref, strings.TrimPrefix(refName, "refs/remotes/"))
+		}
+		return nil
+	}))
+}
+
+// CollectCommits Collect data from each commit, we can also get the diff line
+func (r *GoGitRepo) CollectCommits(subtaskCtx core.SubTaskContext) errors.Error {
+	db := subtaskCtx.GetDal()
+	components := make([]code.Component, 0)
+	err := db.All(&components, dal.From(components), dal.Where("repo_id= ?", r.id))
+	if err != nil {
+		return err
+	}
+	componentMap := make(map[string]*regexp.Regexp)
+	for _, component := range components {
+		componentMap[component.Name] = regexp.MustCompile(component.PathRegex)
+	}
+	r.mailmap, err = r.loadMailmap()
+	if err != nil {
+		return err
+	}
+	tips, err := r.commitTips()
+	if err != nil {
+		return err
+	}
+	if !r.fullRescan {
+		knownShas, lastTips, err := loadCommitState(db, r.id)
+		if err != nil {
+			return err
+		}
+		// fall back to the full scan on the first run
+		if len(knownShas) > 0 {
+			err = r.collectNewCommits(subtaskCtx, knownShas, tips, lastTips, componentMap)
+			if err != nil {
+				return err
+			}
+			return r.store.RepoTips(r.id, newRepoTips(r.id, tips))
+		}
+	}
+	if count, err := r.CountCommits(subtaskCtx.GetContext()); err != nil {
+		subtaskCtx.GetLogger().Error(err, "unable to get commit count")
+		subtaskCtx.SetProgress(0, -1)
+	} else {
+		subtaskCtx.SetProgress(0, count)
+	}
+	commits, err := errors.Convert01(r.repo.CommitObjects())
+	if err != nil {
+		return err
+	}
+	err = errors.Convert(commits.ForEach(func(commit *object.Commit) error {
+		select {
+		case <-subtaskCtx.GetContext().Done():
+			return subtaskCtx.GetContext().Err()
+		default:
+		}
+		err1 := r.collectCommit(subtaskCtx.GetContext(), commit, componentMap)
+		if err1 != nil {
+			return err1
+		}
+		subtaskCtx.IncProgress(1)
+		return nil
+	}))
+	if err != nil {
+		return err
+	}
+	// the tips are saved only after all commits are collected, so an interrupted run would be walked again
+	return r.store.RepoTips(r.id, newRepoTips(r.id, tips))
+}
+
+// collectNewCommits collects the commits reachable from the current branches and tags but not from the tips saved by
+// the last successful run, commits stored already are skipped
+func (r *GoGitRepo) collectNewCommits(
+	subtaskCtx core.SubTaskContext,
+	knownShas []string,
+	tips []string,
+	lastTips []string,
+	componentMap map[string]*regexp.Regexp,
+) errors.Error {
+	known := make(map[string]bool, len(knownShas))
+	for _, sha := range knownShas {
+		known[sha] = true
+	}
+	newShas, err := newCommitShas(subtaskCtx.GetContext(), tips, lastTips, known, r.commitParents)
+	if err != nil {
+		return err
+	}
+	r.logger.Info("found %d new commits in repo %s", len(newShas), r.id)
+	subtaskCtx.SetProgress(0, len(newShas))
+	for _, sha := range newShas {
+		select {
+		case <-subtaskCtx.GetContext().Done():
+			return errors.Convert(subtaskCtx.GetContext().Err())
+		default:
+		}
+		commit, err := errors.Convert01(r.repo.CommitObject(plumbing.NewHash(sha)))
+		if err != nil {
+			return err
+		}
+		err = r.collectCommit(subtaskCtx.GetContext(), commit, componentMap)
+		if err != nil {
+			return err
+		}
+		subtaskCtx.IncProgress(1)
+	}
+	return nil
+}
+
+// commitTips returns the commits the branches, remote branches and tags point to
+func (r *GoGitRepo) commitTips() ([]string, errors.Error) {
+	refs, err := r.repo.References()
+	if err != nil {
+		return nil, errors.Convert(err)
+	}
+	seen := make(map[plumbing.Hash]bool)
+	var tips []string
+	err = refs.ForEach(func(ref *plumbing.Reference) error {
+		if ref.Type() != plumbing.HashReference || !(ref.Name().IsBranch() || ref.Name().IsRemote() || ref.Name().IsTag()) {
+			return nil
+		}
+		hash := ref.Hash()
+		for {
+			obj, err1 := r.repo.Object(plumbing.AnyObject, hash)
+			if err1 != nil {
+				return err1
+			}
+			tag, ok := obj.(*object.Tag)
+			if !ok {
+				if _, ok = obj.(*object.Commit); ok && !seen[hash] {
+					seen[hash] = true
+					tips = append(tips, hash.String())
+				}
+				return nil
+			}
+			hash = tag.Target
+		}
+	})
+	if err != nil {
+		return nil, errors.Convert(err)
+	}
+	return tips, nil
+}
+
+func (r *GoGitRepo) collectCommit(ctx context.Context, commit *object.Commit, componentMap map[string]*regexp.Regexp) errors.Error {
+	r.logger.Debug("process commit: %s", commit.Hash.String())
+	c := &code.Commit{
+		Sha:           commit.Hash.String(),
+		Message:       commit.Message,
+		AuthoredDate:  commit.Author.When,
+		CommittedDate: commit.Committer.When,
+	}
+	c.AuthorName, c.AuthorEmail = r.mailmap.Resolve(commit.Author.Name, commit.Author.Email)
+	c.AuthorId = c.AuthorEmail
+	c.CommitterName, c.CommitterEmail = r.mailmap.Resolve(commit.Committer.Name, commit.Committer.Email)
+	c.CommitterId = c.CommitterEmail
+	err := r.store.CommitContributors(commitContributors(c, r.mailmap))
+	if err != nil {
+		return err
+	}
+	var commitParents []*code.CommitParent
+	for _, parentHash := range commit.ParentHashes {
+		commitParents = append(commitParents, &code.CommitParent{
+			CommitSha:       c.Sha,
+			ParentCommitSha: parentHash.String(),
+		})
+	}
+	err = r.store.CommitParents(commitParents)
+	if err != nil {
+		return err
+	}
+	changes, err := r.diffToFirstParent(ctx, commit)
+	if err != nil {
+		return err
+	}
+	for _, change := range changes {
+		commitFile := newCommitFile(c.Sha, changePath(change))
+		chunks, err := changeChunks(ctx, change)
+		if err != nil {
+			return err
+		}
+		for _, chunk := range chunks {
+			switch chunk.Type() {
+			case fdiff.Add:
+				commitFile.Additions += countLines(chunk.Content())
+			case fdiff.Delete:
+				commitFile.Deletions += countLines(chunk.Content())
+			}
+		}
+		c.Additions += commitFile.Additions
+		c.Deletions += commitFile.Deletions
+		err = r.store.CommitFiles(commitFile)
+		if err != nil {
+			r.logger.Error(err, "CommitFiles error")
+			return err
+		}
+		err = r.store.CommitFileComponents(newCommitFileComponent(commitFile, componentMap))
+		if err != nil {
+			r.logger.Error(err, "CommitFileComponents error")
+			return err
+		}
+	}
+	err = r.store.Commits(c)
+	if err != nil {
+		return err
+	}
+	repoCommit := &code.RepoCommit{
+		RepoId:    r.id,
+		CommitSha: c.Sha,
+	}
+	return r.store.RepoCommits(repoCommit)
+}
+
+// loadMailmap reads the .mailmap of the HEAD commit, the extra mailmap given in options is applied on top of it
+func (r *GoGitRepo) loadMailmap() (*Mailmap, errors.Error) {
+	mailmap := NewMailmap()
+	head, err := r.repo.Head()
+	// an empty repo has no HEAD
+	if err == nil {
+		var commit *object.Commit
+		var file *object.File
+		commit, err = r.repo.CommitObject(head.Hash())
+		if err != nil {
+			return nil, errors.Convert(err)
+		}
+		file, err = commit.File(".mailmap")
+		if err == nil {
+			var content string
+			content, err = file.Contents()
+			if err != nil {
+				return nil, errors.Convert(err)
+			}
+			mailmap.Parse(content)
+		}
+	}
+	mailmap.Parse(r.extraMailmap)
+	return mailmap, nil
+}
+
+// diffToFirstParent returns the changes of the commit compared to its first parent sorted by path as libgit2 does,
+// renames are not detected
+func (r *GoGitRepo) diffToFirstParent(ctx context.Context, commit *object.Commit) (object.Changes, errors.Error) {
+	var parent *object.Commit
+	if commit.NumParents() > 0 {
+		var err error
+		parent, err = commit.Parent(0)
+		if err != nil {
+			return nil, errors.Convert(err)
+		}
+	}
+	return r.diffToParent(ctx, commit, parent)
+}
+
+// diffToParent returns the changes of the commit compared to the parent, or to the empty tree if parent is nil
+func (r *GoGitRepo) diffToParent(ctx context.Context, commit, parent *object.Commit) (object.Changes, errors.Error) {
+	tree, err := commit.Tree()
+	if err != nil {
+		return nil, errors.Convert(err)
+	}
+	var parentTree *object.Tree
+	if parent != nil {
+		parentTree, err = parent.Tree()
+		if err != nil {
+			return nil, errors.Convert(err)
+		}
+	}
+	changes, err := object.DiffTreeContext(ctx, parentTree, tree)
+	if err != nil {
+		return nil, errors.Convert(err)
+	}
+	sort.SliceStable(changes, func(i, j int) bool {
+		return changePath(changes[i]) < changePath(changes[j])
+	})
+	return changes, nil
+}
+
+// changePath returns the path of the file after the change, or before the change if it was deleted
+func changePath(change *object.Change) string {
+	if change.To.Name != "" {
+		return change.To.Name
+	}
+	return change.From.Name
+}
+
+// changeChunks returns the diff chunks of the change, submodules and binary files have no chunks
+func changeChunks(ctx context.Context, change *object.Change) ([]fdiff.Chunk, errors.Error) {
+	if change.From.TreeEntry.Mode == filemode.Submodule || change.To.TreeEntry.Mode == filemode.Submodule {
+		return nil, nil
+	}
+	patch, err := change.PatchContext(ctx)
+	if err != nil {
+		return nil, errors.Convert(err)
+	}
+	var chunks []fdiff.Chunk
+	for _, filePatch := range patch.FilePatches() {
+		chunks = append(chunks, filePatch.Chunks()...)
+	}
+	return chunks, nil
+}
+
+// countLines counts the lines in the chunk content, the last line may not end with a newline
+func countLines(content string) int {
+	if content == "" {
+		return 0
+	}
+	count := strings.Count(content, "\n")
+	if !strings.HasSuffix(content, "\n") {
+		count++
+	}
+	return count
+}
+
+// splitLines splits the chunk content into lines, the newlines are kept
+func splitLines(content string) []string {
+	lines := strings.SplitAfter(content, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// CollectDiffLine get line diff data from the selected branches
+func (r *GoGitRepo) CollectDiffLine(subtaskCtx core.SubTaskContext) errors.Error {
+	return collectDiffLines(subtaskCtx, r.store, r, r.id, r.diffLineBranches, r.diffLineBranchPattern)
+}
+
+func (r *GoGitRepo) headBranch() (*branchTip, errors.Error) {
+	head, err := r.repo.Head()
+	if err == plumbing.ErrReferenceNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Convert(err)
+	}
+	name := "HEAD"
+	if head.Name().IsBranch() {
+		name = strings.TrimPrefix(head.Name().String(), "refs/heads/")
+	}
+	return &branchTip{name: name, sha: head.Hash().String()}, nil
+}
+
+func (r *GoGitRepo) branchTips() ([]*branchTip, errors.Error) {
+	var tips []*branchTip
+	err := r.forEachBranch(context.Background(), func(branch *plumbing.Reference, name string) error {
+		// skip the symbolic ones like origin/HEAD
+		if branch.Type() == plumbing.HashReference {
+			tips = append(tips, &branchTip{name: name, sha: branch.Hash().String()})
+		}
+		return nil
+	})
+	return tips, err
+}
+
+func (r *GoGitRepo) commitParents(sha string) ([]string, errors.Error) {
+	commit, err := r.repo.CommitObject(plumbing.NewHash(sha))
+	if err != nil {
+		return nil, errors.Convert(err)
+	}
+	parents := make([]string, 0, len(commit.ParentHashes))
+	for _, hash := range commit.ParentHashes {
+		parents = append(parents, hash.String())
+	}
+	return parents, nil
+}
+
+func (r *GoGitRepo) commitDiff(subtaskCtx core.SubTaskContext, sha, parentSha string) ([]fileDiff, errors.Error) {
+	commit, err := r.repo.CommitObject(plumbing.NewHash(sha))
+	if err != nil {
+		return nil, errors.Convert(err)
+	}
+	var parent *object.Commit
+	if parentSha != "" {
+		parent, err = r.repo.CommitObject(plumbing.NewHash(parentSha))
+		if err != nil {
+			return nil, errors.Convert(err)
+		}
+	}
+	changes, err1 := r.diffToParent(subtaskCtx.GetContext(), commit, parent)
+	if err1 != nil {
+		return nil, err1
+	}
+	files := make([]fileDiff, 0, len(changes))
+	for _, change := range changes {
+		file := fileDiff{oldPath: change.From.Name, newPath: change.To.Name}
+		if file.oldPath == "" {
+			file.oldPath = file.newPath
+		}
+		if file.newPath == "" {
+			file.newPath = file.oldPath
+		}
+		chunks, err := changeChunks(subtaskCtx.GetContext(), change)
+		if err != nil {
+			return nil, err
+		}
+		file.hunks = groupHunks(chunkLines(chunks), diffContextLines)
+		files = append(files, file)
+	}
+	return files, nil
+}
+
+// chunkLines numbers all lines of the chunks, the line number is -1 for the side where the line does not exist
+func chunkLines(chunks []fdiff.Chunk) []diffLine {
+	var lines []diffLine
+	oldLineno, newLineno := 1, 1
+	for _, chunk := range chunks {
+		for range splitLines(chunk.Content()) {
+			switch chunk.Type() {
+			case fdiff.Equal:
+				lines = append(lines, diffLine{origin: lineContext, oldLineno: oldLineno, newLineno: newLineno})
+				oldLineno++
+				newLineno++
+			case fdiff.Delete:
+				lines = append(lines, diffLine{origin: lineDeletion, oldLineno: oldLineno, newLineno: -1})
+				oldLineno++
+			case fdiff.Add:
+				lines = append(lines, diffLine{origin: lineAddition, oldLineno: -1, newLineno: newLineno})
+				newLineno++
+			}
+		}
+	}
+	return lines
+}
+
+// groupHunks cuts the lines into hunks with the context lines around the changes, two groups of changes are put into
+// the same hunk when there are no more than 2*contextLines lines between them, the way xdiff of libgit2 does
+func groupHunks(lines []diffLine, contextLines int) [][]diffLine {
+	var hunks [][]diffLine
+	first, last := -1, -1
+	emit := func() {
+		start := first - contextLines
+		if start < 0 {
+			start = 0
+		}
+		end := last + contextLines + 1
+		if end > len(lines) {
+			end = len(lines)
+		}
+		hunks = append(hunks, lines[start:end])
+	}
+	for i, line := range lines {
+		if line.origin == lineContext {
+			continue
+		}
+		if first < 0 {
+			first = i
+		} else if i-last-1 > 2*contextLines {
+			emit()
+			first = i
+		}
+		last = i
+	}
+	if first >= 0 {
+		emit()
+	}
+	return hunks
+}
@@ -0,0 +1,421 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package parser
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/apache/incubator-devlake/errors"
+	"github.com/apache/incubator-devlake/helpers/unithelper"
+	"github.com/apache/incubator-devlake/mocks"
+	"github.com/apache/incubator-devlake/models/domainlayer/code"
+	"github.com/apache/incubator-devlake/plugins/core"
+	"github.com/apache/incubator-devlake/plugins/core/dal"
+	"github.com/apache/incubator-devlake/plugins/gitextractor/models"
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// memoryStore keeps the records in memory so the outputs of the backends could be compared
+type memoryStore struct {
+	repoCommits          []*code.RepoCommit
+	commits              []*code.Commit
+	refs                 []*code.Ref
+	commitFiles          []*code.CommitFile
+	commitParents        []*code.CommitParent
+	commitContributors   []*code.CommitContributor
+	commitFileComponents []*code.CommitFileComponent
+	commitLineChanges    []*code.CommitLineChange
+	repoSnapshots        []*code.RepoSnapshot
+	commitBranches       []*code.CommitBranch
+	repoTips             []*models.GitRepoTip
+}
+
+func (s *memoryStore) RepoCommits(repoCommit *code.RepoCommit) errors.Error {
+	s.repoCommits = append(s.repoCommits, repoCommit)
+	return nil
+}
+
+func (s *memoryStore) Commits(commit *code.Commit) errors.Error {
+	commit.AuthoredDate = commit.AuthoredDate.UTC()
+	commit.CommittedDate = commit.CommittedDate.UTC()
+	s.commits = append(s.commits, commit)
+	return nil
+}
+
+func (s *memoryStore) Refs(ref *code.Ref) errors.Error {
+	s.refs = append(s.refs, ref)
+	return nil
+}
+
+func (s *memoryStore) CommitFiles(file *code.CommitFile) errors.Error {
+	s.commitFiles = append(s.commitFiles, file)
+	return nil
+}
+
+func (s *memoryStore) CommitParents(pp []*code.CommitParent) errors.Error {
+	s.commitParents = append(s.commitParents, pp...)
+	return nil
+}
+
+func (s *memoryStore) CommitContributors(cc []*code.CommitContributor) errors.Error {
+	s.commitContributors = append(s.commitContributors, cc...)
+	return nil
+}
+
+func (s *memoryStore) CommitFileComponents(commitFileComponent *code.CommitFileComponent) errors.Error {
+	s.commitFileComponents = append(s.commitFileComponents, commitFileComponent)
+	return nil
+}
+
+func (s *memoryStore) CommitLineChange(commitLineChange *code.CommitLineChange) errors.Error {
+	s.commitLineChanges = append(s.commitLineChanges, commitLineChange)
+	return nil
+}
+
+func (s *memoryStore) RepoSnapshot(snapshot *code.RepoSnapshot) errors.Error {
+	s.repoSnapshots = append(s.repoSnapshots, snapshot)
+	return nil
+}
+
+func (s *memoryStore) CommitBranches(commitBranch *code.CommitBranch) errors.Error {
+	s.commitBranches = append(s.commitBranches, commitBranch)
+	return nil
+}
+
+func (s *memoryStore) ResetBranchSnapshots(_ string) errors.Error {
+	s.repoSnapshots = nil
+	s.commitBranches = nil
+	return nil
+}
+
+func (s *memoryStore) RepoTips(_ string, tips []*models.GitRepoTip) errors.Error {
+	s.repoTips = tips
+	return nil
+}
+
+func (s *memoryStore) Close() errors.Error {
+	return nil
+}
+
+// sort puts the records in a stable order since the backends walk the objects in different orders
+func (s *memoryStore) sort() {
+	sort.Slice(s.repoCommits, func(i, j int) bool { return s.repoCommits[i].CommitSha < s.repoCommits[j].CommitSha })
+	sort.Slice(s.commits, func(i, j int) bool { return s.commits[i].Sha < s.commits[j].Sha })
+	sort.Slice(s.refs, func(i, j int) bool { return s.refs[i].Id < s.refs[j].Id })
+	sort.Slice(s.commitFiles, func(i, j int) bool { return s.commitFiles[i].Id < s.commitFiles[j].Id })
+	sort.Slice(s.commitParents, func(i, j int) bool {
+		return s.commitParents[i].CommitSha+s.commitParents[i].ParentCommitSha < s.commitParents[j].CommitSha+s.commitParents[j].ParentCommitSha
+	})
+	sort.Slice(s.commitContributors, func(i, j int) bool {
+		a, b := s.commitContributors[i], s.commitContributors[j]
+		return a.CommitSha+a.Role+a.Email < b.CommitSha+b.Role+b.Email
+	})
+	sort.Slice(s.commitFileComponents, func(i, j int) bool {
+		return s.commitFileComponents[i].CommitFileId < s.commitFileComponents[j].CommitFileId
+	})
+	sort.Slice(s.commitLineChanges, func(i, j int) bool { return s.commitLineChanges[i].Id < s.commitLineChanges[j].Id })
+	sort.Slice(s.commitBranches, func(i, j int) bool {
+		a, b := s.commitBranches[i], s.commitBranches[j]
+		return a.Branch+":"+a.CommitSha < b.Branch+":"+b.CommitSha
+	})
+	sort.Slice(s.repoTips, func(i, j int) bool { return s.repoTips[i].CommitSha < s.repoTips[j].CommitSha })
+	sort.Slice(s.repoSnapshots, func(i, j int) bool {
+		a, b := s.repoSnapshots[i], s.repoSnapshots[j]
+		return fmt.Sprintf("%s:%s:%08d", a.Branch, a.FilePath, a.LineNo) < fmt.Sprintf("%s:%s:%08d", b.Branch, b.FilePath, b.LineNo)
+	})
+}
+
+func numberedLines(prefix string, n int, replaced map[int]string) string {
+	var sb strings.Builder
+	for i := 1; i <= n; i++ {
+		if line, ok := replaced[i]; ok {
+			if line != "" {
+				sb.WriteString(line + "\n")
+			}
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("%s line %d\n", prefix, i))
+	}
+	return sb.String()
+}
+
+// createFixtureRepo creates a repo with additions, modifications, deletions, a merge, a branch and both kinds of tags,
+// and returns the commits in the order they were made
+func createFixtureRepo(t *testing.T, dir string) []plumbing.Hash {
+	repo, err := gogit.PlainInit(dir, false)
+	assert.Nil(t, err)
+	worktree, err := repo.Worktree()
+	assert.Nil(t, err)
+	when := time.Date(2023, 1, 1, 8, 0, 0, 0, time.FixedZone("", 8*3600))
+	var hashes []plumbing.Hash
+	commit := func(message string, files map[string]string, parents ...plumbing.Hash) plumbing.Hash {
+		for name, content := range files {
+			path := filepath.Join(dir, name)
+			if content == "" {
+				_, err = worktree.Remove(name)
+				assert.Nil(t, err)
+				continue
+			}
+			assert.Nil(t, os.MkdirAll(filepath.Dir(path), 0755))
+			assert.Nil(t, os.WriteFile(path, []byte(content), 0644))
+			_, err = worktree.Add(name)
+			assert.Nil(t, err)
+		}
+		when = when.Add(time.Hour)
+		hash, err := worktree.Commit(message, &gogit.CommitOptions{
+			Author:    &object.Signature{Name: "Alice", Email: "alice@old.example.com", When: when},
+			Committer: &object.Signature{Name: "Bob", Email: "bob@example.com", When: when},
+			Parents:   parents,
+		})
+		assert.Nil(t, err)
+		hashes = append(hashes, hash)
+		return hash
+	}
+	commit("init", map[string]string{
+		"a.txt":     numberedLines("a", 30, nil),
+		"dir/b.txt": numberedLines("b", 5, nil),
+		".mailmap":  "Alice <alice@example.com> <alice@old.example.com>\n",
+	})
+	c2 := commit("modify a, delete b and add c", map[string]string{
+		"a.txt":     numberedLines("a", 30, map[int]string{2: "a changed 2", 20: "a changed 20", 21: ""}),
+		"dir/b.txt": "",
+		"c.txt":     numberedLines("c", 8, nil),
+	})
+	_, err = repo.CreateTag("v1", c2, nil)
+	assert.Nil(t, err)
+	assert.Nil(t, worktree.Checkout(&gogit.CheckoutOptions{Branch: plumbing.NewBranchReferenceName("feature"), Create: true}))
+	c3 := commit("modify c on feature", map[string]string{
+		"c.txt": numberedLines("c", 8, map[int]string{4: "c changed 4"}),
+	})
+	assert.Nil(t, worktree.Checkout(&gogit.CheckoutOptions{Branch: plumbing.NewBranchReferenceName("master")}))
+	c4 := commit("modify a on master\n\nCo-authored-by: Carol <carol@example.com>\nSigned-off-by: Alice <alice@old.example.com>", map[string]string{
+		"a.txt": numberedLines("a", 30, map[int]string{2: "a changed 2", 5: "a changed 5", 20: "a changed 20", 21: ""}),
+	})
+	merge := commit("merge feature", map[string]string{
+		"c.txt": numberedLines("c", 8, map[int]string{4: "c changed 4"}),
+	}, c4, c3)
+	_, err = repo.CreateTag("v2", merge, &gogit.CreateTagOptions{
+		Tagger:  &object.Signature{Name: "Bob", Email: "bob@example.com", When: when},
+		Message: "release v2",
+	})
+	assert.Nil(t, err)
+	return hashes
+}
+
+func newTestSubTaskContext() core.SubTaskContext {
+	mockDal := new(mocks.Dal)
+	mockDal.On("All", mock.Anything, mock.Anything).Return(nil)
+	mockCtx := unithelper.DummySubTaskContext(mockDal)
+	mockCtx.On("GetContext").Return(context.Background())
+	return mockCtx
+}
+
+// newIncrementalSubTaskContext returns the context of a run after the previous one stored the knownShas and saved
+// the lastTips
+func newIncrementalSubTaskContext(knownShas, lastTips []string) core.SubTaskContext {
+	mockDal := new(mocks.Dal)
+	mockDal.On("All", mock.Anything, mock.Anything).Return(nil)
+	mockDal.On("Pluck", "commit_sha", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		// the first clause is the From of the table
+		switch args.Get(2).([]dal.Clause)[0].Data.(type) {
+		case *code.RepoCommit:
+			*args.Get(1).(*[]string) = knownShas
+		case *models.GitRepoTip:
+			*args.Get(1).(*[]string) = lastTips
+		}
+	}).Return(nil)
+	mockCtx := unithelper.DummySubTaskContext(mockDal)
+	mockCtx.On("GetContext").Return(context.Background())
+	return mockCtx
+}
+
+func TestGoGitRepoCollectNewCommits(t *testing.T) {
+	dir := t.TempDir()
+	hashes := createFixtureRepo(t, dir)
+	c1, c2, c3, c4, merge := hashes[0].String(), hashes[1].String(), hashes[2].String(), hashes[3].String(), hashes[4].String()
+	repoId := "github:GithubRepo:1:1"
+	collectNewCommits := func(knownShas, lastTips []string) *memoryStore {
+		store := &memoryStore{}
+		logger := unithelper.DummyLogger()
+		logger.On("Info", "found %d new commits in repo %s", mock.Anything, repoId).Once()
+		repo, err := NewGitRepoCreator(store, logger).LocalGoGitRepo(dir, repoId)
+		assert.Nil(t, err)
+		assert.Nil(t, repo.CollectCommits(newIncrementalSubTaskContext(knownShas, lastTips)))
+		logger.AssertExpectations(t)
+		store.sort()
+		return store
+	}
+	commitShas := func(store *memoryStore) []string {
+		var shas []string
+		for _, commit := range store.commits {
+			shas = append(shas, commit.Sha)
+		}
+		sort.Strings(shas)
+		return shas
+	}
+	tips := []string{c2, c3, merge}
+	sort.Strings(tips)
+
+	// the last run was interrupted after storing the merge commit, no tips were saved, the parents of the merge
+	// commit must be collected even though it is stored already
+	store := collectNewCommits([]string{merge}, nil)
+	expected := []string{c1, c2, c3, c4}
+	sort.Strings(expected)
+	assert.Equal(t, expected, commitShas(store))
+	assert.Equal(t, newRepoTips(repoId, tips), store.repoTips)
+
+	// the last run succeeded and nothing changed since then
+	store = collectNewCommits([]string{c1, c2, c3, c4, merge}, tips)
+	assert.Empty(t, store.commits)
+	assert.Equal(t, newRepoTips(repoId, tips), store.repoTips)
+
+	// the tips saved by the last run are behind the stored commits, the commits between them are walked again and
+	// only the missing ones are collected
+	store = collectNewCommits([]string{c1, c2, merge}, []string{c2})
+	expected = []string{c3, c4}
+	sort.Strings(expected)
+	assert.Equal(t, expected, commitShas(store))
+}
+
+func TestGroupHunks(t *testing.T) {
+	lines := chunkLines(nil)
+	assert.Empty(t, groupHunks(lines, diffContextLines))
+	var all []diffLine
+	for i := 1; i <= 20; i++ {
+		all = append(all, diffLine{origin: lineContext, oldLineno: i, newLineno: i})
+	}
+	// the changes at the 2nd and the 9th lines are 6 lines apart, so they are in the same hunk
+	all[1].origin = lineDeletion
+	all[8].origin = lineDeletion
+	all[18].origin = lineDeletion
+	hunks := groupHunks(all, diffContextLines)
+	assert.Len(t, hunks, 2)
+	assert.Equal(t, 1, hunks[0][0].oldLineno)
+	assert.Equal(t, 12, hunks[0][len(hunks[0])-1].oldLineno)
+	assert.Equal(t, 16, hunks[1][0].oldLineno)
+	assert.Equal(t, 20, hunks[1][len(hunks[1])-1].oldLineno)
+}
+
+func TestCollectDiffLinesOnBranches(t *testing.T) {
+	dir := t.TempDir()
+	hashes := createFixtureRepo(t, dir)
+	c1, c2, c3, c4, merge := hashes[0].String(), hashes[1].String(), hashes[2].String(), hashes[3].String(), hashes[4].String()
+	store := &memoryStore{}
+	repo, err := NewGitRepoCreator(store, unithelper.DummyLogger()).LocalGoGitRepo(dir, "github:GithubRepo:1:1")
+	assert.Nil(t, err)
+	repo.SetDiffLineBranches([]string{"master", "missing"}, regexp.MustCompile("^feat"))
+	subtaskCtx := newTestSubTaskContext()
+	// the branch not found is reported
+	logger := subtaskCtx.GetLogger().(*mocks.Logger)
+	logger.On("Warn", nil, "branch %s not found in the repo", "missing").Once()
+	assert.Nil(t, repo.CollectDiffLine(subtaskCtx))
+	logger.AssertExpectations(t)
+	store.sort()
+
+	// the commits of feature are merged into master, so they are on both branches
+	branches := make(map[string][]string)
+	for _, commitBranch := range store.commitBranches {
+		assert.Equal(t, "github:GithubRepo:1:1", commitBranch.RepoId)
+		branches[commitBranch.CommitSha] = append(branches[commitBranch.CommitSha], commitBranch.Branch)
+	}
+	assert.Equal(t, map[string][]string{
+		c1:    {"feature", "master"},
+		c2:    {"feature", "master"},
+		c3:    {"feature", "master"},
+		c4:    {"master"},
+		merge: {"master"},
+	}, branches)
+	for _, change := range store.commitLineChanges {
+		// the line changed on feature is not recorded again on the merge commit
+		assert.NotEqual(t, merge, change.CommitSha)
+	}
+	blame := make(map[string]string)
+	for _, line := range store.repoSnapshots {
+		blame[fmt.Sprintf("%s:%s:%d", line.Branch, line.FilePath, line.LineNo)] = line.CommitSha
+	}
+	assert.Equal(t, c1, blame["feature:a.txt:5"])
+	assert.Equal(t, c4, blame["master:a.txt:5"])
+	assert.Equal(t, c3, blame["feature:c.txt:4"])
+	// the merge commit inherits the blame from the second parent
+	assert.Equal(t, c3, blame["master:c.txt:4"])
+	assert.Equal(t, c1, blame["master:a.txt:1"])
+	assert.NotContains(t, blame, "master:dir/b.txt:1")
+}
+
+func TestCollectDiffLinesOnHeadBranch(t *testing.T) {
+	dir := t.TempDir()
+	hashes := createFixtureRepo(t, dir)
+	merge := hashes[4].String()
+	store := &memoryStore{}
+	repo, err := NewGitRepoCreator(store, unithelper.DummyLogger()).LocalGoGitRepo(dir, "github:GithubRepo:1:1")
+	assert.Nil(t, err)
+	repo.SetDiffLineBranches([]string{"feature"}, nil)
+	assert.Nil(t, repo.CollectDiffLine(newTestSubTaskContext()))
+	store.sort()
+
+	// the branch of HEAD is collected along with the selected one
+	snapshotBranches := make(map[string]bool)
+	for _, line := range store.repoSnapshots {
+		snapshotBranches[line.Branch] = true
+	}
+	assert.Equal(t, map[string]bool{"feature": true, "master": true}, snapshotBranches)
+	var mergeBranches []string
+	for _, commitBranch := range store.commitBranches {
+		if commitBranch.CommitSha == merge {
+			mergeBranches = append(mergeBranches, commitBranch.Branch)
+		}
+	}
+	assert.Equal(t, []string{"master"}, mergeBranches)
+}
+
+func TestParentLineno(t *testing.T) {
+	// line 3 is replaced and 2 lines are deleted after line 10 of a 20-line file
+	file := &fileDiff{hunks: [][]diffLine{
+		{
+			{origin: lineContext, oldLineno: 1, newLineno: 1},
+			{origin: lineContext, oldLineno: 2, newLineno: 2},
+			{origin: lineDeletion, oldLineno: 3, newLineno: -1},
+			{origin: lineAddition, oldLineno: -1, newLineno: 3},
+			{origin: lineAddition, oldLineno: -1, newLineno: 4},
+			{origin: lineContext, oldLineno: 4, newLineno: 5},
+		},
+		{
+			{origin: lineDeletion, oldLineno: 11, newLineno: -1},
+			{origin: lineDeletion, oldLineno: 12, newLineno: -1},
+		},
+	}}
+	for newLineno, expected := range map[int]int{1: 1, 3: 0, 4: 0, 5: 4, 6: 5, 11: 10, 12: 13, 18: 19} {
+		oldLineno, ok := parentLineno(file, newLineno)
+		assert.Equal(t, expected != 0, ok)
+		if ok {
+			assert.Equal(t, expected, oldLineno, newLineno)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+//go:build libgit2
+
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package parser
+
+import (
+	"testing"
+
+	"github.com/apache/incubator-devlake/errors"
+	"github.com/apache/incubator-devlake/helpers/unithelper"
+	"github.com/stretchr/testify/assert"
+)
+
+func collectWith(t *testing.T, newRepo func(creator *GitRepoCreator) (RepoCollector, errors.Error)) *memoryStore {
+	store := &memoryStore{}
+	repo, err := newRepo(NewGitRepoCreator(store, unithelper.DummyLogger()))
+	assert.Nil(t, err)
+	repo.SetFullRescan(true)
+	assert.Nil(t, repo.CollectAll(newTestSubTaskContext()))
+	store.sort()
+	return store
+}
+
+func TestGoGitRepoMatchesLibgit2(t *testing.T) {
+	dir := t.TempDir()
+	createFixtureRepo(t, dir)
+	repoId := "github:GithubRepo:1:1"
+	expected := collectWith(t, func(creator *GitRepoCreator) (RepoCollector, errors.Error) {
+		return creator.LocalRepo(dir, repoId)
+	})
+	actual := collectWith(t, func(creator *GitRepoCreator) (RepoCollector, errors.Error) {
+		return creator.LocalGoGitRepo(dir, repoId)
+	})
+	assert.Len(t, actual.commits, 5)
+	assert.NotEmpty(t, actual.commitLineChanges)
+	assert.Equal(t, expected.refs, actual.refs)
+	assert.Equal(t, expected.commits, actual.commits)
+	assert.Equal(t, expected.repoCommits, actual.repoCommits)
+	assert.Equal(t, expected.commitParents, actual.commitParents)
+	assert.Equal(t, expected.commitContributors, actual.commitContributors)
+	assert.Equal(t, expected.commitFiles, actual.commitFiles)
+	assert.Equal(t, expected.commitFileComponents, actual.commitFileComponents)
+	assert.Equal(t, expected.commitLineChanges, actual.commitLineChanges)
+	assert.Equal(t, expected.repoSnapshots, actual.repoSnapshots)
+	assert.Equal(t, expected.repoTips, actual.repoTips)
+}
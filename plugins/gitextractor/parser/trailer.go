@@ -0,0 +1,88 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package parser
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/apache/incubator-devlake/models/domainlayer/code"
+)
+
+// the trailers which indicate the people contributed to the commit along with the author
+var contributorTrailers = map[string]string{
+	"co-authored-by": code.COMMIT_CO_AUTHOR,
+	"reviewed-by":    code.COMMIT_REVIEWER,
+	"signed-off-by":  code.COMMIT_SIGNER,
+}
+
+var trailerPattern = regexp.MustCompile(`^([A-Za-z0-9-]+):\s*(.*?)\s*<([^>]+)>\s*$`)
+
+// CommitTrailer is a Co-authored-by, Reviewed-by or Signed-off-by trailer of a commit message
+type CommitTrailer struct {
+	Role  string
+	Name  string
+	Email string
+}
+
+// ParseContributorTrailers returns the contributor trailers in the last paragraph of the commit message, which is
+// where `git interpret-trailers` and the squash merges of GitHub/GitLab put them
+func ParseContributorTrailers(message string) []CommitTrailer {
+	paragraphs := strings.Split(strings.TrimSpace(strings.ReplaceAll(message, "\r\n", "\n")), "\n\n")
+	var trailers []CommitTrailer
+	for _, line := range strings.Split(paragraphs[len(paragraphs)-1], "\n") {
+		matches := trailerPattern.FindStringSubmatch(strings.TrimSpace(line))
+		if matches == nil {
+			continue
+		}
+		if role, ok := contributorTrailers[strings.ToLower(matches[1])]; ok {
+			trailers = append(trailers, CommitTrailer{
+				Role:  role,
+				Name:  matches[2],
+				Email: matches[3],
+			})
+		}
+	}
+	return trailers
+}
+
+// commitContributors returns the author and the people in the trailers, so the pair-programmed and squash-merged
+// commits could be attributed to all of them
+func commitContributors(c *code.Commit, mailmap *Mailmap) []*code.CommitContributor {
+	var contributors []*code.CommitContributor
+	keeper := make(map[string]bool)
+	addContributor := func(role, name, email string) {
+		if email == "" || keeper[role+":"+email] {
+			return
+		}
+		keeper[role+":"+email] = true
+		contributors = append(contributors, &code.CommitContributor{
+			CommitSha: c.Sha,
+			Email:     email,
+			Role:      role,
+			Name:      name,
+			AccountId: email,
+		})
+	}
+	addContributor(code.COMMIT_AUTHOR, c.AuthorName, c.AuthorEmail)
+	for _, trailer := range ParseContributorTrailers(c.Message) {
+		name, email := mailmap.Resolve(trailer.Name, trailer.Email)
+		addContributor(trailer.Role, name, email)
+	}
+	return contributors
+}
@@ -26,6 +26,7 @@ import (
 	"reflect"
 
 	"github.com/apache/incubator-devlake/models/domainlayer/code"
+	"github.com/apache/incubator-devlake/plugins/gitextractor/models"
 )
 
 type csvWriter struct {
@@ -84,9 +85,11 @@ type CsvStore struct {
 	refWriter                 *csvWriter
 	commitFileWriter          *csvWriter
 	commitParentWriter        *csvWriter
+	commitContributorWriter   *csvWriter
 	commitFileComponentWriter *csvWriter
 	commitLineChangeWriter    *csvWriter
 	snapshotWriter            *csvWriter
+	commitBranchWriter        *csvWriter
 }
 
 func NewCsvStore(dir string) (*CsvStore, errors.Error) {
@@ -118,6 +121,10 @@ func NewCsvStore(dir string) (*CsvStore, errors.Error) {
 	if err != nil {
 		return nil, errors.Convert(err)
 	}
+	s.commitContributorWriter, err = newCsvWriter(filepath.Join(dir, "commit_contributors.csv"), code.CommitContributor{})
+	if err != nil {
+		return nil, errors.Convert(err)
+	}
 	s.commitFileComponentWriter, err = newCsvWriter(filepath.Join(dir, "commit_file_components.csv"), code.CommitFileComponent{})
 	if err != nil {
 		return nil, errors.Convert(err)
@@ -130,6 +137,10 @@ func NewCsvStore(dir string) (*CsvStore, errors.Error) {
 	if err != nil {
 		return nil, errors.Convert(err)
 	}
+	s.commitBranchWriter, err = newCsvWriter(filepath.Join(dir, "commit_branches.csv"), code.CommitBranch{})
+	if err != nil {
+		return nil, errors.Convert(err)
+	}
 	return s, nil
 }
 
@@ -161,6 +172,10 @@ func (c *CsvStore) RepoSnapshot(ss *code.RepoSnapshot) errors.Error {
 	return c.snapshotWriter.Write(ss)
 }
 
+func (c *CsvStore) CommitBranches(commitBranch *code.CommitBranch) errors.Error {
+	return c.commitBranchWriter.Write(commitBranch)
+}
+
 func (c *CsvStore) CommitParents(pp []*code.CommitParent) errors.Error {
 	var err error
 	for _, p := range pp {
@@ -172,6 +187,26 @@ func (c *CsvStore) CommitParents(pp []*code.CommitParent) errors.Error {
 	return nil
 }
 
+func (c *CsvStore) CommitContributors(cc []*code.CommitContributor) errors.Error {
+	for _, contributor := range cc {
+		err := c.commitContributorWriter.Write(contributor)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ResetBranchSnapshots does nothing since the csv outputs are always collected from scratch
+func (c *CsvStore) ResetBranchSnapshots(_ string) errors.Error {
+	return nil
+}
+
+// RepoTips is not written since the csv outputs are always collected from scratch
+func (c *CsvStore) RepoTips(_ string, _ []*models.GitRepoTip) errors.Error {
+	return nil
+}
+
 func (c *CsvStore) Close() errors.Error {
 	if c.repoCommitWriter != nil {
 		c.repoCommitWriter.Close()
@@ -188,8 +223,14 @@ func (c *CsvStore) Close() errors.Error {
 	if c.commitParentWriter != nil {
 		c.commitParentWriter.Close()
 	}
+	if c.commitContributorWriter != nil {
+		c.commitContributorWriter.Close()
+	}
 	if c.snapshotWriter != nil {
 		c.snapshotWriter.Close()
 	}
+	if c.commitBranchWriter != nil {
+		c.commitBranchWriter.Close()
+	}
 	return nil
 }
@@ -27,21 +27,27 @@ import (
 	"github.com/apache/incubator-devlake/models/domainlayer/code"
 	"github.com/apache/incubator-devlake/models/domainlayer/crossdomain"
 	"github.com/apache/incubator-devlake/plugins/core"
+	"github.com/apache/incubator-devlake/plugins/core/dal"
+	"github.com/apache/incubator-devlake/plugins/gitextractor/models"
 	"github.com/apache/incubator-devlake/plugins/helper"
 )
 
 const BathSize = 100
 
 type Database struct {
-	driver *helper.BatchSaveDivider
-	table  string
-	params string
+	basicRes core.BasicRes
+	driver   *helper.BatchSaveDivider
+	// refs are always rewritten since branches and tags might be deleted
+	refDriver *helper.BatchSaveDivider
+	table     string
+	params    string
 }
 
 func NewDatabase(basicRes core.BasicRes, repoId string) *Database {
 	database := &Database{
-		table:  "gitextractor",
-		params: repoId,
+		basicRes: basicRes,
+		table:    "gitextractor",
+		params:   repoId,
 	}
 	database.driver = helper.NewBatchSaveDivider(
 		basicRes,
@@ -49,9 +55,20 @@ func NewDatabase(basicRes core.BasicRes, repoId string) *Database {
 		database.table,
 		database.params,
 	)
+	database.refDriver = helper.NewBatchSaveDivider(
+		basicRes,
+		BathSize,
+		database.table,
+		database.params,
+	)
 	return database
 }
 
+// SetIncrementalMode keeps the commits stored by the previous runs and appends the new ones
+func (d *Database) SetIncrementalMode(incrementalMode bool) {
+	d.driver.SetIncrementalMode(incrementalMode)
+}
+
 func (d *Database) updateRawDataFields(rawData *common.RawDataOrigin) {
 	rawData.RawDataTable = d.table
 	rawData.RawDataParams = d.params
@@ -91,7 +108,7 @@ func (d *Database) Commits(commit *code.Commit) errors.Error {
 }
 
 func (d *Database) Refs(ref *code.Ref) errors.Error {
-	batch, err := d.driver.ForType(reflect.TypeOf(ref))
+	batch, err := d.refDriver.ForType(reflect.TypeOf(ref))
 	if err != nil {
 		return err
 	}
@@ -126,6 +143,15 @@ func (d *Database) RepoSnapshot(snapshotElement *code.RepoSnapshot) errors.Error
 	return batch.Add(snapshotElement)
 }
 
+func (d *Database) CommitBranches(commitBranch *code.CommitBranch) errors.Error {
+	batch, err := d.driver.ForType(reflect.TypeOf(commitBranch))
+	if err != nil {
+		return err
+	}
+	d.updateRawDataFields(&commitBranch.RawDataOrigin)
+	return batch.Add(commitBranch)
+}
+
 func (d *Database) CommitLineChange(commitLineChange *code.CommitLineChange) errors.Error {
 	batch, err := d.driver.ForType(reflect.TypeOf(commitLineChange))
 	if err != nil {
@@ -153,6 +179,58 @@ func (d *Database) CommitParents(pp []*code.CommitParent) errors.Error {
 	return nil
 }
 
+func (d *Database) CommitContributors(cc []*code.CommitContributor) errors.Error {
+	if len(cc) == 0 {
+		return nil
+	}
+	batch, err := d.driver.ForType(reflect.TypeOf(cc[0]))
+	if err != nil {
+		return err
+	}
+	for _, contributor := range cc {
+		d.updateRawDataFields(&contributor.RawDataOrigin)
+		err = batch.Add(contributor)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *Database) ResetBranchSnapshots(repoId string) errors.Error {
+	db := d.basicRes.GetDal()
+	err := db.Delete(&code.RepoSnapshot{}, dal.Where("repo_id = ?", repoId))
+	if err != nil {
+		return err
+	}
+	return db.Delete(&code.CommitBranch{}, dal.Where("repo_id = ?", repoId))
+}
+
+func (d *Database) RepoTips(repoId string, tips []*models.GitRepoTip) errors.Error {
+	err := d.driver.Flush()
+	if err != nil {
+		return err
+	}
+	db := d.basicRes.GetDal()
+	err = db.Delete(&models.GitRepoTip{}, dal.Where("repo_id = ?", repoId))
+	if err != nil {
+		return err
+	}
+	if len(tips) == 0 {
+		return nil
+	}
+	return db.CreateOrUpdate(tips)
+}
+
 func (d *Database) Close() errors.Error {
-	return d.driver.Close()
+	err := d.driver.Close()
+	if err != nil {
+		return err
+	}
+	err = d.refDriver.Close()
+	if err != nil {
+		return err
+	}
+	// keep the commit generations in step with the commit_parents just stored, so refdiff walks stay bounded
+	return helper.UpdateCommitGenerations(d.basicRes.GetDal(), d.basicRes.GetLogger(), d.params)
 }
@@ -18,6 +18,7 @@ limitations under the License.
 package tasks
 
 import (
+	"regexp"
 	"strings"
 
 	"github.com/apache/incubator-devlake/errors"
@@ -33,6 +34,16 @@ type GitExtractorOptions struct {
 	PrivateKey string `json:"privateKey"`
 	Passphrase string `json:"passphrase"`
 	Proxy      string `json:"proxy"`
+	// FullRescan processes all commits in the repo again instead of the ones not stored yet
+	FullRescan bool `json:"fullRescan"`
+	// Mailmap is in the format of .mailmap, and applied on top of the .mailmap in the repo
+	Mailmap string `json:"mailmap"`
+	// UseGoGit switches to the pure-Go backend based on go-git, which does not require libgit2
+	UseGoGit bool `json:"useGoGit"`
+	// DiffLineBranches and DiffLineBranchPattern select the branches to collect the diff lines on,
+	// the branch of HEAD is always collected along with them
+	DiffLineBranches      []string `json:"diffLineBranches"`
+	DiffLineBranchPattern string   `json:"diffLineBranchPattern"`
 }
 
 func (o GitExtractorOptions) Valid() errors.Error {
@@ -46,18 +57,15 @@ func (o GitExtractorOptions) Valid() errors.Error {
 	if !(strings.HasPrefix(o.Url, "http") || strings.HasPrefix(url, "git@") || strings.HasPrefix(o.Url, "/")) {
 		return errors.BadInput.New("wrong url")
 	}
+	if _, err := regexp.Compile(o.DiffLineBranchPattern); err != nil {
+		return errors.BadInput.Wrap(err, "invalid diffLineBranchPattern")
+	}
 	return nil
 }
 
 func CollectGitCommits(subTaskCtx core.SubTaskContext) errors.Error {
 	repo := getGitRepo(subTaskCtx)
-	if count, err := repo.CountCommits(subTaskCtx.GetContext()); err != nil {
-		subTaskCtx.GetLogger().Error(err, "unable to get commit count")
-		subTaskCtx.SetProgress(0, -1)
-		return err
-	} else {
-		subTaskCtx.SetProgress(0, count)
-	}
+	// the progress is set by CollectCommits since only the new commits are processed in incremental mode
 	return repo.CollectCommits(subTaskCtx)
 }
 
@@ -87,18 +95,12 @@ func CollectGitTags(subTaskCtx core.SubTaskContext) errors.Error {
 
 func CollectGitDiffLines(subTaskCtx core.SubTaskContext) errors.Error {
 	repo := getGitRepo(subTaskCtx)
-	if count, err := repo.CountTags(); err != nil {
-		subTaskCtx.GetLogger().Error(err, "unable to get line content")
-		subTaskCtx.SetProgress(0, -1)
-		return err
-	} else {
-		subTaskCtx.SetProgress(0, count)
-	}
+	// the progress is set by CollectDiffLine once the commits on the branches are found
 	return repo.CollectDiffLine(subTaskCtx)
 }
 
-func getGitRepo(subTaskCtx core.SubTaskContext) *parser.GitRepo {
-	repo, ok := subTaskCtx.GetData().(*parser.GitRepo)
+func getGitRepo(subTaskCtx core.SubTaskContext) parser.RepoCollector {
+	repo, ok := subTaskCtx.GetData().(parser.RepoCollector)
 	if !ok {
 		panic("git repo reference not found on context")
 	}
@@ -133,6 +135,6 @@ var CollectGitDiffLineMeta = core.SubTaskMeta{
 	Name:             "collectDiffLine",
 	EntryPoint:       CollectGitDiffLines,
 	EnabledByDefault: false,
-	Description:      "collect git commit diff line into Domain Layer Tables",
+	Description:      "collect git commit diff line of the selected branches into Domain Layer Tables",
 	DomainTypes:      []string{core.DOMAIN_TYPE_CODE},
 }
@@ -0,0 +1,306 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/apache/incubator-devlake/errors"
+	"github.com/apache/incubator-devlake/plugins/core"
+	"github.com/apache/incubator-devlake/plugins/core/dal"
+	"github.com/apache/incubator-devlake/plugins/github/models"
+	"github.com/apache/incubator-devlake/plugins/github/tasks"
+	"github.com/apache/incubator-devlake/plugins/helper"
+)
+
+const (
+	webhookSignatureHeader = "X-Hub-Signature-256"
+	webhookEventHeader     = "X-GitHub-Event"
+	webhookDeliveryHeader  = "X-GitHub-Delivery"
+)
+
+// WebhookRepository is the `repository` object shared by all repository events
+type WebhookRepository struct {
+	Id       int    `json:"id"`
+	FullName string `json:"full_name"`
+}
+
+// WebhookCommitUser is the author or committer of a commit in push events
+type WebhookCommitUser struct {
+	Name     string `json:"name"`
+	Email    string `json:"email"`
+	Username string `json:"username"`
+}
+
+// WebhookCommit is a commit in push events, which is shaped differently from the one returned by the REST api
+type WebhookCommit struct {
+	Id        string            `json:"id"`
+	Message   string            `json:"message"`
+	Timestamp string            `json:"timestamp"`
+	Url       string            `json:"url"`
+	Author    WebhookCommitUser `json:"author"`
+	Committer WebhookCommitUser `json:"committer"`
+}
+
+// WebhookPayload contains the fields of the supported events we are interested in
+type WebhookPayload struct {
+	Action      string            `json:"action"`
+	Repository  WebhookRepository `json:"repository"`
+	PullRequest json.RawMessage   `json:"pull_request"`
+	WorkflowRun json.RawMessage   `json:"workflow_run"`
+	Commits     []WebhookCommit   `json:"commits"`
+}
+
+// webhookRecords are the raw rows of an event and the subtasks to process them
+type webhookRecords struct {
+	table    string
+	rows     []json.RawMessage
+	subtasks []string
+}
+
+// the response of the `/commits` REST api, which is what `extractApiCommits` expects
+type webhookApiCommit struct {
+	Sha    string `json:"sha"`
+	Url    string `json:"url"`
+	Commit struct {
+		Author    webhookApiCommitUser `json:"author"`
+		Committer webhookApiCommitUser `json:"committer"`
+		Message   string               `json:"message"`
+	} `json:"commit"`
+}
+
+type webhookApiCommitUser struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
+	Date  string `json:"date"`
+}
+
+// PostWebhook receives native webhook deliveries of github
+// @Summary receive github webhook deliveries
+// @Description Receive native github webhook deliveries (pull_request, push and workflow_run),
+// @Description store the payloads into the raw tables and submit a pipeline to extract/convert the affected entities.
+// @Description The `X-Hub-Signature-256` header is verified with the webhook secret of the connection.
+// @Description The pipelines are labelled `parallel/github/<connectionId>`, add the label to the blueprint collecting
+// @Description the connection to keep it from running alongside them.
+// @Tags plugins/github
+// @Param connectionId path int true "connection ID"
+// @Success 200  {object} shared.ApiBody
+// @Success 202  {object} shared.ApiBody
+// @Failure 400  {string} errcode.Error "Bad Request"
+// @Failure 401  {string} errcode.Error "Unauthorized"
+// @Failure 500  {string} errcode.Error "Internal Error"
+// @Router /plugins/github/connections/{connectionId}/webhooks [POST]
+func PostWebhook(input *core.ApiResourceInput) (*core.ApiResourceOutput, errors.Error) {
+	connection := &models.GithubConnection{}
+	err := connectionHelper.First(connection, input.Params)
+	if err != nil {
+		return nil, err
+	}
+	if input.Request == nil {
+		return nil, errors.BadInput.New("missing request")
+	}
+	body, err := readWebhookBody(input.Request)
+	if err != nil {
+		return nil, err
+	}
+	err = VerifyWebhookSignature(connection.WebhookSecret, input.Request.Header.Get(webhookSignatureHeader), body)
+	if err != nil {
+		return nil, err
+	}
+	event := input.Request.Header.Get(webhookEventHeader)
+	delivery := input.Request.Header.Get(webhookDeliveryHeader)
+	if event == "ping" {
+		return &core.ApiResourceOutput{Body: map[string]interface{}{"success": true, "message": "pong"}, Status: http.StatusOK}, nil
+	}
+
+	payload := &WebhookPayload{}
+	if e := json.Unmarshal(body, payload); e != nil {
+		return nil, errors.BadInput.Wrap(errors.Convert(e), "invalid webhook payload")
+	}
+	records := makeWebhookRecords(event, payload)
+	if records == nil {
+		return ignoredWebhook(fmt.Sprintf("event %s is not supported", event)), nil
+	}
+	db := basicRes.GetDal()
+	repo := &models.GithubRepo{}
+	err = db.First(repo, dal.Where("connection_id = ? AND github_id = ?", connection.ID, payload.Repository.Id))
+	if db.IsErrorNotFound(err) {
+		return ignoredWebhook(fmt.Sprintf("repo %s is not a scope of the connection", payload.Repository.FullName)), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	params, e := json.Marshal(tasks.GithubApiParams{ConnectionId: connection.ID, Name: repo.Name})
+	if e != nil {
+		return nil, errors.Convert(e)
+	}
+	rawInput, e := json.Marshal(map[string]string{"event": event, "delivery": delivery})
+	if e != nil {
+		return nil, errors.Convert(e)
+	}
+	table := "_raw_" + records.table
+	err = db.AutoMigrate(&helper.RawData{}, dal.From(table))
+	if err != nil {
+		return nil, err
+	}
+	rawDataIds := make([]uint64, 0, len(records.rows))
+	for _, row := range records.rows {
+		rawData := &helper.RawData{
+			Params: string(params),
+			Data:   row,
+			Url:    input.Request.URL.String(),
+			Input:  rawInput,
+		}
+		err = db.Create(rawData, dal.From(table))
+		if err != nil {
+			return nil, err
+		}
+		rawDataIds = append(rawDataIds, rawData.ID)
+	}
+
+	// deliveries are processed in pipelines to have their status recorded and be able to rerun or cancel them,
+	// the `parallel/` label keeps the pipelines of the connection from running alongside each other
+	pipelineId, err := core.SubmitPipeline(
+		fmt.Sprintf("github webhook %s %s", event, delivery),
+		makeWebhookPlan(repo, records, rawDataIds),
+		"webhook",
+		fmt.Sprintf("parallel/github/%d", connection.ID),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &core.ApiResourceOutput{
+		Body: map[string]interface{}{
+			"success":    true,
+			"event":      event,
+			"delivery":   delivery,
+			"rawDataIds": rawDataIds,
+			"pipelineId": pipelineId,
+		},
+		Status: http.StatusAccepted,
+	}, nil
+}
+
+// VerifyWebhookSignature checks the `sha256=<hex hmac of body>` signature sent by github
+func VerifyWebhookSignature(secret string, signature string, body []byte) errors.Error {
+	if secret == "" {
+		return errors.Forbidden.New("webhook secret is not configured for the connection")
+	}
+	if !strings.HasPrefix(signature, "sha256=") {
+		return errors.Unauthorized.New("missing or malformed X-Hub-Signature-256 header")
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	expected := hex.EncodeToString(mac.Sum(nil))
+	if !hmac.Equal([]byte(expected), []byte(strings.ToLower(strings.TrimPrefix(signature, "sha256=")))) {
+		return errors.Unauthorized.New("webhook signature mismatched")
+	}
+	return nil
+}
+
+func makeWebhookRecords(event string, payload *WebhookPayload) *webhookRecords {
+	switch event {
+	case "pull_request":
+		if len(payload.PullRequest) == 0 {
+			return nil
+		}
+		return &webhookRecords{
+			table:    tasks.RAW_PULL_REQUEST_TABLE,
+			rows:     []json.RawMessage{payload.PullRequest},
+			subtasks: []string{"extractApiPullRequests", "convertPullRequests", "convertPullRequestLabels"},
+		}
+	case "workflow_run":
+		if len(payload.WorkflowRun) == 0 || string(payload.WorkflowRun) == "null" {
+			return nil
+		}
+		return &webhookRecords{
+			table:    tasks.RAW_RUN_TABLE,
+			rows:     []json.RawMessage{payload.WorkflowRun},
+			subtasks: []string{"extractRuns", "convertRuns"},
+		}
+	case "push":
+		if len(payload.Commits) == 0 {
+			return nil
+		}
+		rows := make([]json.RawMessage, 0, len(payload.Commits))
+		for _, commit := range payload.Commits {
+			apiCommit := &webhookApiCommit{Sha: commit.Id, Url: commit.Url}
+			apiCommit.Commit.Message = commit.Message
+			apiCommit.Commit.Author = webhookApiCommitUser{Name: commit.Author.Name, Email: commit.Author.Email, Date: commit.Timestamp}
+			apiCommit.Commit.Committer = webhookApiCommitUser{Name: commit.Committer.Name, Email: commit.Committer.Email, Date: commit.Timestamp}
+			row, err := json.Marshal(apiCommit)
+			if err != nil {
+				return nil
+			}
+			rows = append(rows, row)
+		}
+		return &webhookRecords{
+			table:    tasks.RAW_COMMIT_TABLE,
+			rows:     rows,
+			subtasks: []string{"extractApiCommits", "convertCommits"},
+		}
+	}
+	return nil
+}
+
+// makeWebhookPlan runs the subtasks over the raw data rows stored for the delivery
+func makeWebhookPlan(repo *models.GithubRepo, records *webhookRecords, rawDataIds []uint64) core.PipelinePlan {
+	return core.PipelinePlan{
+		{
+			{
+				Plugin:   "github",
+				Subtasks: records.subtasks,
+				Options: map[string]interface{}{
+					"connectionId": repo.ConnectionId,
+					"name":         repo.Name,
+					"githubId":     repo.GithubId,
+					helper.RAW_DATA_IDS_OPTION: map[string][]uint64{
+						"_raw_" + records.table: rawDataIds,
+					},
+				},
+			},
+		},
+	}
+}
+
+func readWebhookBody(req *http.Request) ([]byte, errors.Error) {
+	if req.Body == nil {
+		return nil, errors.BadInput.New("empty webhook payload")
+	}
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		return nil, errors.Convert(err)
+	}
+	return body, nil
+}
+
+func ignoredWebhook(reason string) *core.ApiResourceOutput {
+	return &core.ApiResourceOutput{
+		Body:   map[string]interface{}{"success": true, "ignored": true, "message": reason},
+		Status: http.StatusAccepted,
+	}
+}
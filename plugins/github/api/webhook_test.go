@@ -0,0 +1,103 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+
+	"github.com/apache/incubator-devlake/plugins/github/models"
+	"github.com/apache/incubator-devlake/plugins/github/tasks"
+	"github.com/apache/incubator-devlake/plugins/helper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerifyWebhookSignature(t *testing.T) {
+	body := []byte(`{"action":"opened"}`)
+	mac := hmac.New(sha256.New, []byte("secret"))
+	mac.Write(body)
+	signature := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+
+	assert.Nil(t, VerifyWebhookSignature("secret", signature, body))
+	assert.NotNil(t, VerifyWebhookSignature("another", signature, body))
+	assert.NotNil(t, VerifyWebhookSignature("secret", signature, []byte(`{"action":"closed"}`)))
+	assert.NotNil(t, VerifyWebhookSignature("secret", "", body))
+	assert.NotNil(t, VerifyWebhookSignature("", signature, body))
+}
+
+func TestMakeWebhookRecords(t *testing.T) {
+	payload := &WebhookPayload{}
+	err := json.Unmarshal([]byte(`{
+		"ref": "refs/heads/main",
+		"repository": {"id": 1, "full_name": "apache/incubator-devlake"},
+		"commits": [{
+			"id": "c1a9b4e8",
+			"message": "fix: something",
+			"timestamp": "2023-01-25T08:00:00+08:00",
+			"url": "https://github.com/apache/incubator-devlake/commit/c1a9b4e8",
+			"author": {"name": "a", "email": "a@example.com", "username": "a"},
+			"committer": {"name": "b", "email": "b@example.com", "username": "b"}
+		}]
+	}`), payload)
+	assert.Nil(t, err)
+
+	records := makeWebhookRecords("push", payload)
+	assert.Equal(t, tasks.RAW_COMMIT_TABLE, records.table)
+	assert.Equal(t, []string{"extractApiCommits", "convertCommits"}, records.subtasks)
+	assert.Len(t, records.rows, 1)
+	commit := &tasks.CommitsResponse{}
+	assert.Nil(t, json.Unmarshal(records.rows[0], commit))
+	assert.Equal(t, "c1a9b4e8", commit.Sha)
+	assert.Equal(t, "fix: something", commit.Commit.Message)
+	assert.Equal(t, "a@example.com", commit.Commit.Author.Email)
+	assert.Equal(t, "b", commit.Commit.Committer.Name)
+	assert.Equal(t, "2023-01-25T00:00:00Z", commit.Commit.Committer.Date.ToTime().UTC().Format("2006-01-02T15:04:05Z"))
+
+	payload = &WebhookPayload{}
+	assert.Nil(t, json.Unmarshal([]byte(`{"action":"completed","workflow_run":{"id":2}}`), payload))
+	records = makeWebhookRecords("workflow_run", payload)
+	assert.Equal(t, tasks.RAW_RUN_TABLE, records.table)
+	assert.JSONEq(t, `{"id":2}`, string(records.rows[0]))
+
+	// deployments are not collected by the plugin
+	payload = &WebhookPayload{}
+	assert.Nil(t, json.Unmarshal([]byte(`{"action":"created","deployment_status":{"state":"success"},"workflow_run":{"id":2}}`), payload))
+	assert.Nil(t, makeWebhookRecords("deployment_status", payload))
+
+	payload = &WebhookPayload{}
+	assert.Nil(t, json.Unmarshal([]byte(`{"action":"opened","pull_request":{"id":3}}`), payload))
+	records = makeWebhookRecords("pull_request", payload)
+	assert.Equal(t, tasks.RAW_PULL_REQUEST_TABLE, records.table)
+	assert.Nil(t, makeWebhookRecords("issues", payload))
+}
+
+func TestMakeWebhookPlan(t *testing.T) {
+	repo := &models.GithubRepo{ConnectionId: 1, GithubId: 134018330, Name: "apache/incubator-devlake"}
+	records := &webhookRecords{table: tasks.RAW_COMMIT_TABLE, subtasks: []string{"extractApiCommits", "convertCommits"}}
+	plan := makeWebhookPlan(repo, records, []uint64{3, 4})
+	assert.Len(t, plan, 1)
+	assert.Len(t, plan[0], 1)
+	task := plan[0][0]
+	assert.Equal(t, "github", task.Plugin)
+	assert.Equal(t, records.subtasks, task.Subtasks)
+	assert.Equal(t, "apache/incubator-devlake", task.Options["name"])
+	assert.Equal(t, map[string][]uint64{"_raw_" + tasks.RAW_COMMIT_TABLE: {3, 4}}, task.Options[helper.RAW_DATA_IDS_OPTION])
+}
@@ -30,7 +30,8 @@ type TestConnectionRequest struct {
 type GithubConnection struct {
 	helper.RestConnection `mapstructure:",squash"`
 	helper.AccessToken    `mapstructure:",squash"`
-	EnableGraphql         bool `mapstructure:"enableGraphql" json:"enableGraphql"`
+	EnableGraphql         bool   `mapstructure:"enableGraphql" json:"enableGraphql"`
+	WebhookSecret         string `mapstructure:"webhookSecret" json:"webhookSecret" encrypt:"yes"`
 }
 
 func (GithubConnection) TableName() string {
@@ -45,11 +45,13 @@ func ConvertRuns(taskCtx core.SubTaskContext) errors.Error {
 	repoId := data.Options.GithubId
 
 	pipeline := &models.GithubRun{}
-	cursor, err := db.Cursor(
+	clauses := []dal.Clause{
 		dal.Select("id, repo_id, connection_id, name, head_sha, head_branch, status, conclusion, github_created_at, github_updated_at,_raw_data_remark, _raw_data_id, _raw_data_table, _raw_data_params"),
 		dal.From(pipeline),
 		dal.Where("repo_id = ? and connection_id=?", repoId, data.Options.ConnectionId),
-	)
+	}
+	clauses = append(clauses, helper.RawDataIdsClauses(taskCtx.GetContext(), RAW_RUN_TABLE, "")...)
+	cursor, err := db.Cursor(clauses...)
 	if err != nil {
 		return err
 	}
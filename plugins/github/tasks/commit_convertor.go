@@ -43,14 +43,16 @@ func ConvertCommits(taskCtx core.SubTaskContext) errors.Error {
 	data := taskCtx.GetData().(*GithubTaskData)
 	repoId := data.Options.GithubId
 
-	cursor, err := db.Cursor(
+	clauses := []dal.Clause{
 		dal.From("_tool_github_commits gc"),
 		dal.Join(`left join _tool_github_repo_commits grc on (
 			grc.commit_sha = gc.sha
 		)`),
 		dal.Select("gc.*"),
 		dal.Where("grc.repo_id = ? AND grc.connection_id = ?", repoId, data.Options.ConnectionId),
-	)
+	}
+	clauses = append(clauses, helper.RawDataIdsClauses(taskCtx.GetContext(), RAW_COMMIT_TABLE, "gc")...)
+	cursor, err := db.Cursor(clauses...)
 	if err != nil {
 		return err
 	}
@@ -66,7 +68,7 @@ func ConvertCommits(taskCtx core.SubTaskContext) errors.Error {
 				ConnectionId: data.Options.ConnectionId,
 				Name:         data.Options.Name,
 			},
-			Table: RAW_COMMENTS_TABLE,
+			Table: RAW_COMMIT_TABLE,
 		},
 		InputRowType: reflect.TypeOf(githubModels.GithubCommit{}),
 		Input:        cursor,
@@ -44,10 +44,12 @@ func ConvertPullRequests(taskCtx core.SubTaskContext) errors.Error {
 	data := taskCtx.GetData().(*GithubTaskData)
 	repoId := data.Options.GithubId
 
-	cursor, err := db.Cursor(
+	clauses := []dal.Clause{
 		dal.From(&models.GithubPullRequest{}),
 		dal.Where("repo_id = ? and connection_id = ?", repoId, data.Options.ConnectionId),
-	)
+	}
+	clauses = append(clauses, helper.RawDataIdsClauses(taskCtx.GetContext(), RAW_PULL_REQUEST_TABLE, "")...)
+	cursor, err := db.Cursor(clauses...)
 	if err != nil {
 		return err
 	}
@@ -43,12 +43,14 @@ func ConvertPullRequestLabels(taskCtx core.SubTaskContext) errors.Error {
 	data := taskCtx.GetData().(*GithubTaskData)
 	repoId := data.Options.GithubId
 
-	cursor, err := db.Cursor(
+	clauses := []dal.Clause{
 		dal.From(&githubModels.GithubPrLabel{}),
 		dal.Join(`left join _tool_github_pull_requests on _tool_github_pull_requests.github_id = _tool_github_pull_request_labels.pull_id`),
 		dal.Where("_tool_github_pull_requests.repo_id = ? and _tool_github_pull_requests.connection_id = ?", repoId, data.Options.ConnectionId),
 		dal.Orderby("pull_id ASC"),
-	)
+	}
+	clauses = append(clauses, helper.RawDataIdsClauses(taskCtx.GetContext(), RAW_PULL_REQUEST_TABLE, "_tool_github_pull_request_labels")...)
+	cursor, err := db.Cursor(clauses...)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,358 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/apache/incubator-devlake/errors"
+	"github.com/apache/incubator-devlake/plugins/core"
+	"github.com/apache/incubator-devlake/plugins/core/dal"
+	"github.com/apache/incubator-devlake/plugins/gitlab/models"
+	"github.com/apache/incubator-devlake/plugins/gitlab/tasks"
+	"github.com/apache/incubator-devlake/plugins/helper"
+)
+
+const (
+	webhookTokenHeader = "X-Gitlab-Token"
+	webhookEventHeader = "X-Gitlab-Event"
+)
+
+// WebhookUser is the user object in the webhook payloads
+type WebhookUser struct {
+	Id        int    `json:"id"`
+	Name      string `json:"name"`
+	Username  string `json:"username"`
+	AvatarUrl string `json:"avatar_url"`
+}
+
+// WebhookCommit is a commit in push events
+type WebhookCommit struct {
+	Id        string `json:"id"`
+	Title     string `json:"title"`
+	Message   string `json:"message"`
+	Timestamp string `json:"timestamp"`
+	Url       string `json:"url"`
+	Author    struct {
+		Name  string `json:"name"`
+		Email string `json:"email"`
+	} `json:"author"`
+}
+
+// WebhookObjectAttributes contains the fields of merge requests and pipelines we are interested in
+type WebhookObjectAttributes struct {
+	Id              int     `json:"id"`
+	Iid             int     `json:"iid"`
+	Title           string  `json:"title"`
+	Description     string  `json:"description"`
+	State           string  `json:"state"`
+	Url             string  `json:"url"`
+	SourceBranch    string  `json:"source_branch"`
+	TargetBranch    string  `json:"target_branch"`
+	SourceProjectId int     `json:"source_project_id"`
+	TargetProjectId int     `json:"target_project_id"`
+	AuthorId        int     `json:"author_id"`
+	MergeCommitSha  string  `json:"merge_commit_sha"`
+	WorkInProgress  bool    `json:"work_in_progress"`
+	Ref             string  `json:"ref"`
+	Sha             string  `json:"sha"`
+	Tag             bool    `json:"tag"`
+	Status          string  `json:"status"`
+	Duration        float64 `json:"duration"`
+	CreatedAt       string  `json:"created_at"`
+	UpdatedAt       string  `json:"updated_at"`
+	FinishedAt      string  `json:"finished_at"`
+}
+
+// WebhookPayload contains the fields of the supported events we are interested in
+type WebhookPayload struct {
+	ObjectKind string       `json:"object_kind"`
+	User       *WebhookUser `json:"user"`
+	Project    struct {
+		Id                int    `json:"id"`
+		PathWithNamespace string `json:"path_with_namespace"`
+	} `json:"project"`
+	ObjectAttributes WebhookObjectAttributes `json:"object_attributes"`
+	Labels           []struct {
+		Title string `json:"title"`
+	} `json:"labels"`
+	Reviewers []WebhookUser   `json:"reviewers"`
+	Commits   []WebhookCommit `json:"commits"`
+}
+
+// webhookRecords are the raw rows of an event and the subtasks to process them
+type webhookRecords struct {
+	table    string
+	rows     []json.RawMessage
+	subtasks []string
+}
+
+// PostWebhook receives native webhook deliveries of gitlab
+// @Summary receive gitlab webhook deliveries
+// @Description Receive native gitlab webhook deliveries (merge request, pipeline and push hooks),
+// @Description store the payloads into the raw tables and submit a pipeline to extract/convert the affected entities.
+// @Description The `X-Gitlab-Token` header must match the webhook secret of the connection.
+// @Description The pipelines are labelled `parallel/gitlab/<connectionId>`, add the label to the blueprint collecting
+// @Description the connection to keep it from running alongside them.
+// @Tags plugins/gitlab
+// @Param connectionId path int true "connection ID"
+// @Success 202  {object} shared.ApiBody
+// @Failure 400  {string} errcode.Error "Bad Request"
+// @Failure 401  {string} errcode.Error "Unauthorized"
+// @Failure 500  {string} errcode.Error "Internal Error"
+// @Router /plugins/gitlab/connections/{connectionId}/webhooks [POST]
+func PostWebhook(input *core.ApiResourceInput) (*core.ApiResourceOutput, errors.Error) {
+	connection := &models.GitlabConnection{}
+	err := connectionHelper.First(connection, input.Params)
+	if err != nil {
+		return nil, err
+	}
+	if input.Request == nil || input.Request.Body == nil {
+		return nil, errors.BadInput.New("empty webhook payload")
+	}
+	err = VerifyWebhookToken(connection.WebhookSecret, input.Request.Header.Get(webhookTokenHeader))
+	if err != nil {
+		return nil, err
+	}
+	body, e := io.ReadAll(input.Request.Body)
+	if e != nil {
+		return nil, errors.Convert(e)
+	}
+	payload := &WebhookPayload{}
+	if e := json.Unmarshal(body, payload); e != nil {
+		return nil, errors.BadInput.Wrap(errors.Convert(e), "invalid webhook payload")
+	}
+	records, err := makeWebhookRecords(payload)
+	if err != nil {
+		return nil, err
+	}
+	if records == nil {
+		return ignoredWebhook(fmt.Sprintf("event %s is not supported", input.Request.Header.Get(webhookEventHeader))), nil
+	}
+	db := basicRes.GetDal()
+	project := &models.GitlabProject{}
+	err = db.First(project, dal.Where("connection_id = ? AND gitlab_id = ?", connection.ID, payload.Project.Id))
+	if db.IsErrorNotFound(err) {
+		return ignoredWebhook(fmt.Sprintf("project %s is not a scope of the connection", payload.Project.PathWithNamespace)), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	params, e := json.Marshal(tasks.GitlabApiParams{ConnectionId: connection.ID, ProjectId: project.GitlabId})
+	if e != nil {
+		return nil, errors.Convert(e)
+	}
+	rawInput, e := json.Marshal(map[string]string{"event": payload.ObjectKind})
+	if e != nil {
+		return nil, errors.Convert(e)
+	}
+	table := "_raw_" + records.table
+	err = db.AutoMigrate(&helper.RawData{}, dal.From(table))
+	if err != nil {
+		return nil, err
+	}
+	rawDataIds := make([]uint64, 0, len(records.rows))
+	for _, row := range records.rows {
+		rawData := &helper.RawData{
+			Params: string(params),
+			Data:   row,
+			Url:    input.Request.URL.String(),
+			Input:  rawInput,
+		}
+		err = db.Create(rawData, dal.From(table))
+		if err != nil {
+			return nil, err
+		}
+		rawDataIds = append(rawDataIds, rawData.ID)
+	}
+
+	// deliveries are processed in pipelines to have their status recorded and be able to rerun or cancel them,
+	// the `parallel/` label keeps the pipelines of the connection from running alongside each other
+	pipelineId, err := core.SubmitPipeline(
+		fmt.Sprintf("gitlab webhook %s %d", payload.ObjectKind, project.GitlabId),
+		makeWebhookPlan(project, records, rawDataIds),
+		"webhook",
+		fmt.Sprintf("parallel/gitlab/%d", connection.ID),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &core.ApiResourceOutput{
+		Body: map[string]interface{}{
+			"success":    true,
+			"event":      payload.ObjectKind,
+			"rawDataIds": rawDataIds,
+			"pipelineId": pipelineId,
+		},
+		Status: http.StatusAccepted,
+	}, nil
+}
+
+// VerifyWebhookToken checks the secret token sent by gitlab
+func VerifyWebhookToken(secret string, token string) errors.Error {
+	if secret == "" {
+		return errors.Forbidden.New("webhook secret is not configured for the connection")
+	}
+	if subtle.ConstantTimeCompare([]byte(secret), []byte(token)) != 1 {
+		return errors.Unauthorized.New("webhook token mismatched")
+	}
+	return nil
+}
+
+func makeWebhookRecords(payload *WebhookPayload) (*webhookRecords, errors.Error) {
+	var rows []interface{}
+	records := &webhookRecords{}
+	attributes := payload.ObjectAttributes
+	switch payload.ObjectKind {
+	case "merge_request":
+		mr := map[string]interface{}{
+			"id":                attributes.Id,
+			"iid":               attributes.Iid,
+			"project_id":        payload.Project.Id,
+			"source_project_id": attributes.SourceProjectId,
+			"target_project_id": attributes.TargetProjectId,
+			"state":             attributes.State,
+			"title":             attributes.Title,
+			"description":       attributes.Description,
+			"web_url":           attributes.Url,
+			"work_in_progress":  attributes.WorkInProgress,
+			"source_branch":     attributes.SourceBranch,
+			"target_branch":     attributes.TargetBranch,
+			"created_at":        normalizeWebhookTime(attributes.CreatedAt),
+			"merge_commit_sha":  attributes.MergeCommitSha,
+		}
+		// the hook doesn't carry merged_at/closed_at, the last update is when the state changed
+		switch attributes.State {
+		case "merged":
+			mr["merged_at"] = normalizeWebhookTime(attributes.UpdatedAt)
+		case "closed":
+			mr["closed_at"] = normalizeWebhookTime(attributes.UpdatedAt)
+		}
+		author := map[string]interface{}{"id": attributes.AuthorId}
+		if payload.User != nil && payload.User.Id == attributes.AuthorId {
+			author["username"] = payload.User.Username
+		}
+		mr["author"] = author
+		labels := make([]string, 0, len(payload.Labels))
+		for _, label := range payload.Labels {
+			labels = append(labels, label.Title)
+		}
+		mr["labels"] = labels
+		mr["reviewers"] = payload.Reviewers
+		rows = append(rows, mr)
+		records.table = tasks.RAW_MERGE_REQUEST_TABLE
+		records.subtasks = []string{"extractApiMergeRequests", "convertApiMergeRequests", "convertMrLabels"}
+	case "pipeline":
+		pipeline := map[string]interface{}{
+			"id":         attributes.Id,
+			"ref":        attributes.Ref,
+			"sha":        attributes.Sha,
+			"status":     attributes.Status,
+			"tag":        attributes.Tag,
+			"duration":   int(attributes.Duration),
+			"web_url":    attributes.Url,
+			"created_at": normalizeWebhookTime(attributes.CreatedAt),
+		}
+		if attributes.FinishedAt != "" {
+			pipeline["finished_at"] = normalizeWebhookTime(attributes.FinishedAt)
+			pipeline["updated_at"] = normalizeWebhookTime(attributes.FinishedAt)
+		}
+		rows = append(rows, pipeline)
+		records.table = tasks.RAW_PIPELINE_TABLE
+		records.subtasks = []string{"extractApiPipelines", "convertPipelines", "convertPipelineCommits"}
+	case "push":
+		for _, commit := range payload.Commits {
+			shortId := commit.Id
+			if len(shortId) > 8 {
+				shortId = shortId[:8]
+			}
+			// push hooks only carry the author, which is what gitlab shows for the commit anyway
+			rows = append(rows, map[string]interface{}{
+				"id":              commit.Id,
+				"short_id":        shortId,
+				"title":           commit.Title,
+				"message":         commit.Message,
+				"author_name":     commit.Author.Name,
+				"author_email":    commit.Author.Email,
+				"authored_date":   normalizeWebhookTime(commit.Timestamp),
+				"committer_name":  commit.Author.Name,
+				"committer_email": commit.Author.Email,
+				"committed_date":  normalizeWebhookTime(commit.Timestamp),
+				"web_url":         commit.Url,
+			})
+		}
+		records.table = tasks.RAW_COMMIT_TABLE
+		records.subtasks = []string{"extractApiCommits", "convertApiCommits"}
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+	for _, row := range rows {
+		data, err := json.Marshal(row)
+		if err != nil {
+			return nil, errors.Convert(err)
+		}
+		records.rows = append(records.rows, data)
+	}
+	return records, nil
+}
+
+// normalizeWebhookTime converts `2016-08-12 15:23:28 UTC` used by some hooks into the format of the REST api
+func normalizeWebhookTime(value string) interface{} {
+	if value == "" {
+		return nil
+	}
+	t, err := time.Parse("2006-01-02 15:04:05 MST", value)
+	if err != nil {
+		return value
+	}
+	return t.Format(time.RFC3339)
+}
+
+// makeWebhookPlan runs the subtasks over the raw data rows stored for the delivery
+func makeWebhookPlan(project *models.GitlabProject, records *webhookRecords, rawDataIds []uint64) core.PipelinePlan {
+	return core.PipelinePlan{
+		{
+			{
+				Plugin:   "gitlab",
+				Subtasks: records.subtasks,
+				Options: map[string]interface{}{
+					"connectionId": project.ConnectionId,
+					"projectId":    project.GitlabId,
+					helper.RAW_DATA_IDS_OPTION: map[string][]uint64{
+						"_raw_" + records.table: rawDataIds,
+					},
+				},
+			},
+		},
+	}
+}
+
+func ignoredWebhook(reason string) *core.ApiResourceOutput {
+	return &core.ApiResourceOutput{
+		Body:   map[string]interface{}{"success": true, "ignored": true, "message": reason},
+		Status: http.StatusAccepted,
+	}
+}
@@ -0,0 +1,120 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/apache/incubator-devlake/plugins/gitlab/models"
+	"github.com/apache/incubator-devlake/plugins/gitlab/tasks"
+	"github.com/apache/incubator-devlake/plugins/helper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerifyWebhookToken(t *testing.T) {
+	assert.Nil(t, VerifyWebhookToken("secret", "secret"))
+	assert.NotNil(t, VerifyWebhookToken("secret", "another"))
+	assert.NotNil(t, VerifyWebhookToken("secret", ""))
+	assert.NotNil(t, VerifyWebhookToken("", ""))
+}
+
+func TestMakeWebhookRecords(t *testing.T) {
+	payload := &WebhookPayload{}
+	err := json.Unmarshal([]byte(`{
+		"object_kind": "merge_request",
+		"user": {"id": 1, "username": "root"},
+		"project": {"id": 5, "path_with_namespace": "devlake/devlake"},
+		"object_attributes": {
+			"id": 99, "iid": 1, "title": "feat: webhook", "state": "merged",
+			"source_branch": "feat", "target_branch": "main", "author_id": 1,
+			"created_at": "2023-01-25 08:00:00 UTC", "updated_at": "2023-01-25 09:00:00 UTC",
+			"url": "https://gitlab.com/devlake/devlake/-/merge_requests/1"
+		},
+		"labels": [{"title": "bug"}],
+		"reviewers": [{"id": 2, "name": "reviewer", "username": "reviewer"}]
+	}`), payload)
+	assert.Nil(t, err)
+	records, err := makeWebhookRecords(payload)
+	assert.Nil(t, err)
+	assert.Equal(t, tasks.RAW_MERGE_REQUEST_TABLE, records.table)
+	mr := &tasks.MergeRequestRes{}
+	assert.Nil(t, json.Unmarshal(records.rows[0], mr))
+	assert.Equal(t, 99, mr.GitlabId)
+	assert.Equal(t, 5, mr.ProjectId)
+	assert.Equal(t, "root", mr.Author.Username)
+	assert.Equal(t, []string{"bug"}, mr.Labels)
+	assert.Equal(t, "reviewer", mr.Reviewers[0].Username)
+	assert.Equal(t, "https://gitlab.com/devlake/devlake/-/merge_requests/1", mr.WebUrl)
+	assert.Equal(t, "2023-01-25T09:00:00Z", mr.MergedAt.ToTime().UTC().Format("2006-01-02T15:04:05Z"))
+
+	payload = &WebhookPayload{}
+	assert.Nil(t, json.Unmarshal([]byte(`{
+		"object_kind": "pipeline",
+		"project": {"id": 5},
+		"object_attributes": {
+			"id": 31, "ref": "main", "sha": "bcbb5ec3", "status": "success", "duration": 63,
+			"created_at": "2023-01-25 08:00:00 UTC", "finished_at": "2023-01-25 08:01:03 UTC"
+		}
+	}`), payload))
+	records, err = makeWebhookRecords(payload)
+	assert.Nil(t, err)
+	assert.Equal(t, tasks.RAW_PIPELINE_TABLE, records.table)
+	pipeline := &tasks.ApiPipeline{}
+	assert.Nil(t, json.Unmarshal(records.rows[0], pipeline))
+	assert.Equal(t, 31, pipeline.Id)
+	assert.Equal(t, "success", pipeline.Status)
+	assert.Equal(t, float64(63), pipeline.UpdatedAt.ToTime().Sub(pipeline.CreatedAt.ToTime()).Seconds())
+
+	payload = &WebhookPayload{}
+	assert.Nil(t, json.Unmarshal([]byte(`{
+		"object_kind": "push",
+		"project": {"id": 5},
+		"commits": [{
+			"id": "b6568db1bc1dcd7f8b4d5a946b0b91f9dacd7327", "title": "fix", "message": "fix\n",
+			"timestamp": "2023-01-25T08:00:00+08:00", "url": "https://gitlab.com/devlake/devlake/-/commit/b6568db1",
+			"author": {"name": "a", "email": "a@example.com"}
+		}]
+	}`), payload))
+	records, err = makeWebhookRecords(payload)
+	assert.Nil(t, err)
+	assert.Equal(t, tasks.RAW_COMMIT_TABLE, records.table)
+	commit := &tasks.GitlabApiCommit{}
+	assert.Nil(t, json.Unmarshal(records.rows[0], commit))
+	assert.Equal(t, "b6568db1", commit.ShortId)
+	assert.Equal(t, "a@example.com", commit.CommitterEmail)
+
+	payload = &WebhookPayload{}
+	assert.Nil(t, json.Unmarshal([]byte(`{"object_kind": "issue"}`), payload))
+	records, err = makeWebhookRecords(payload)
+	assert.Nil(t, err)
+	assert.Nil(t, records)
+}
+
+func TestMakeWebhookPlan(t *testing.T) {
+	project := &models.GitlabProject{ConnectionId: 1, GitlabId: 12345678}
+	records := &webhookRecords{table: tasks.RAW_PIPELINE_TABLE, subtasks: []string{"extractApiPipelines", "convertPipelines"}}
+	plan := makeWebhookPlan(project, records, []uint64{7})
+	assert.Len(t, plan, 1)
+	assert.Len(t, plan[0], 1)
+	task := plan[0][0]
+	assert.Equal(t, "gitlab", task.Plugin)
+	assert.Equal(t, records.subtasks, task.Subtasks)
+	assert.Equal(t, 12345678, task.Options["projectId"])
+	assert.Equal(t, map[string][]uint64{"_raw_" + tasks.RAW_PIPELINE_TABLE: {7}}, task.Options[helper.RAW_DATA_IDS_OPTION])
+}
@@ -239,6 +239,9 @@ func (plugin Gitlab) ApiResources() map[string]map[string]core.ApiResourceHandle
 			"GET":   api.GetScope,
 			"PATCH": api.UpdateScope,
 		},
+		"connections/:connectionId/webhooks": {
+			"POST": api.PostWebhook,
+		},
 		"connections/:connectionId/scopes": {
 			"GET": api.GetScopeList,
 			"PUT": api.PutScope,
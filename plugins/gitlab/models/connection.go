@@ -25,6 +25,7 @@ import (
 type GitlabConnection struct {
 	helper.RestConnection `mapstructure:",squash"`
 	helper.AccessToken    `mapstructure:",squash"`
+	WebhookSecret         string `mapstructure:"webhookSecret" json:"webhookSecret" encrypt:"yes"`
 }
 
 type TestConnectionRequest struct {
@@ -31,5 +31,6 @@ func All() []core.MigrationScript {
 		new(fixDurationToFloat8),
 		new(addTransformationRule20221125),
 		new(addStdTypeToIssue221230),
+		new(addWebhookSecretForConnection),
 	}
 }
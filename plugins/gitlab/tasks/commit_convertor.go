@@ -52,6 +52,7 @@ func ConvertApiCommits(taskCtx core.SubTaskContext) errors.Error {
 		dal.Where("gpc.gitlab_project_id = ? and gpc.connection_id = ? ",
 			data.Options.ProjectId, data.Options.ConnectionId),
 	}
+	clauses = append(clauses, helper.RawDataIdsClauses(taskCtx.GetContext(), RAW_COMMIT_TABLE, "gc")...)
 	cursor, err := db.Cursor(clauses...)
 	if err != nil {
 		return err
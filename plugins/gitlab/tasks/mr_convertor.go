@@ -45,6 +45,7 @@ func ConvertApiMergeRequests(taskCtx core.SubTaskContext) errors.Error {
 		dal.From(&models.GitlabMergeRequest{}),
 		dal.Where("project_id=? and connection_id = ?", data.Options.ProjectId, data.Options.ConnectionId),
 	}
+	clauses = append(clauses, helper.RawDataIdsClauses(taskCtx.GetContext(), RAW_MERGE_REQUEST_TABLE, "")...)
 
 	cursor, err := db.Cursor(clauses...)
 	if err != nil {
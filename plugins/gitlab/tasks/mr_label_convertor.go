@@ -51,6 +51,7 @@ func ConvertMrLabels(taskCtx core.SubTaskContext) errors.Error {
 			projectId, data.Options.ConnectionId),
 		dal.Orderby("mr_id ASC"),
 	}
+	clauses = append(clauses, helper.RawDataIdsClauses(taskCtx.GetContext(), RAW_MERGE_REQUEST_TABLE, "_tool_gitlab_mr_labels")...)
 
 	cursor, err := db.Cursor(clauses...)
 	if err != nil {
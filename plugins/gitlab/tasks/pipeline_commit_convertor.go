@@ -41,8 +41,12 @@ func ConvertPipelineCommits(taskCtx core.SubTaskContext) errors.Error {
 	db := taskCtx.GetDal()
 	data := taskCtx.GetData().(*GitlabTaskData)
 
-	cursor, err := db.Cursor(dal.From(gitlabModels.GitlabPipelineProject{}),
-		dal.Where("project_id = ? and connection_id = ?", data.Options.ProjectId, data.Options.ConnectionId))
+	clauses := []dal.Clause{
+		dal.From(gitlabModels.GitlabPipelineProject{}),
+		dal.Where("project_id = ? and connection_id = ?", data.Options.ProjectId, data.Options.ConnectionId),
+	}
+	clauses = append(clauses, helper.RawDataIdsClauses(taskCtx.GetContext(), RAW_PIPELINE_TABLE, "")...)
+	cursor, err := db.Cursor(clauses...)
 	if err != nil {
 		return err
 	}
@@ -44,8 +44,12 @@ func ConvertPipelines(taskCtx core.SubTaskContext) errors.Error {
 	db := taskCtx.GetDal()
 	data := taskCtx.GetData().(*GitlabTaskData)
 
-	cursor, err := db.Cursor(dal.From(gitlabModels.GitlabPipeline{}),
-		dal.Where("project_id = ? and connection_id = ?", data.Options.ProjectId, data.Options.ConnectionId))
+	clauses := []dal.Clause{
+		dal.From(gitlabModels.GitlabPipeline{}),
+		dal.Where("project_id = ? and connection_id = ?", data.Options.ProjectId, data.Options.ConnectionId),
+	}
+	clauses = append(clauses, helper.RawDataIdsClauses(taskCtx.GetContext(), RAW_PIPELINE_TABLE, "")...)
+	cursor, err := db.Cursor(clauses...)
 	if err != nil {
 		return err
 	}
@@ -45,6 +45,8 @@ type RequestData struct {
 	Params    interface{}
 	Input     interface{}
 	InputJSON []byte
+	// CustomData carries whatever GetNextPageCustomData extracted from the previous page, i.e. a continuation token
+	CustomData interface{}
 }
 
 // AsyncResponseHandler FIXME ...
@@ -73,6 +75,10 @@ type ApiCollectorArgs struct {
 	// GetTotalPages is to tell `ApiCollector` total number of pages based on response of the first page.
 	// so `ApiCollector` could collect those pages in parallel for us
 	GetTotalPages func(res *http.Response, args *ApiCollectorArgs) (int, errors.Error)
+	// GetNextPageCustomData is for APIs paginated by cursor/continuation token, pages would be fetched one
+	// after another, and the returned value would be available as `reqData.CustomData` for the next page.
+	// Return ErrFinishCollect to indicate there are no more pages
+	GetNextPageCustomData func(prevReqData *RequestData, prevPageResponse *http.Response) (interface{}, errors.Error)
 	// Concurrency specify qps for api that doesn't return total number of pages/records
 	// NORMALLY, DO NOT SPECIFY THIS PARAMETER, unless you know what it means
 	Concurrency    int
@@ -211,6 +217,8 @@ func (collector *ApiCollector) exec(input interface{}) {
 		collector.fetchAsync(reqData, nil)
 	} else if collector.args.GetTotalPages != nil {
 		collector.fetchPagesDetermined(reqData)
+	} else if collector.args.GetNextPageCustomData != nil {
+		collector.fetchPagesSequentially(reqData)
 	} else {
 		collector.fetchPagesUndetermined(reqData)
 	}
@@ -244,6 +252,32 @@ func (collector *ApiCollector) fetchPagesDetermined(reqData *RequestData) {
 	})
 }
 
+// fetchPagesSequentially fetches data of all pages one by one for APIs that paginate by cursor/continuation token
+func (collector *ApiCollector) fetchPagesSequentially(reqData *RequestData) {
+	apiClient := collector.args.ApiClient
+	var collect func() errors.Error
+	collect = func() errors.Error {
+		collector.fetchAsync(reqData, func(count int, body []byte, res *http.Response) errors.Error {
+			customData, err := collector.args.GetNextPageCustomData(reqData, res)
+			if err != nil {
+				if errors.Is(err, ErrFinishCollect) {
+					return nil
+				}
+				return errors.Default.Wrap(err, "fetchPagesSequentially get next page custom data failed")
+			}
+			apiClient.NextTick(func() errors.Error {
+				reqData.CustomData = customData
+				reqData.Pager.Skip += collector.args.PageSize
+				reqData.Pager.Page += 1
+				return collect()
+			})
+			return nil
+		})
+		return nil
+	}
+	apiClient.NextTick(collect)
+}
+
 // fetchPagesUndetermined fetches data of all pages for APIs that do NOT return paging information
 func (collector *ApiCollector) fetchPagesUndetermined(reqData *RequestData) {
 	//logger := collector.args.Ctx.GetLogger()
@@ -67,11 +67,16 @@ func (extractor *ApiExtractor) Execute() errors.Error {
 	db := extractor.args.Ctx.GetDal()
 	log := extractor.args.Ctx.GetLogger()
 
+	ctx := extractor.args.Ctx.GetContext()
+	rawDataIds := rawDataIdsFromContext(ctx, extractor.table)
 	clauses := []dal.Clause{
 		dal.From(extractor.table),
 		dal.Where("params = ?", extractor.params),
 		dal.Orderby("id ASC"),
 	}
+	if rawDataIds != nil {
+		clauses = append(clauses, dal.Where("id IN ?", rawDataIdList(rawDataIds)))
+	}
 
 	count, err := db.Count(clauses...)
 	if err != nil {
@@ -88,10 +93,13 @@ func (extractor *ApiExtractor) Execute() errors.Error {
 	// batch save divider
 	RAW_DATA_ORIGIN := "RawDataOrigin"
 	divider := NewBatchSaveDivider(extractor.args.Ctx, extractor.args.BatchSize, extractor.table, extractor.params)
+	// records extracted from the other rows must be kept when only some rows are processed
+	if rawDataIds != nil {
+		divider.SetIncrementalMode(true)
+	}
 
 	// prgress
 	extractor.args.Ctx.SetProgress(0, -1)
-	ctx := extractor.args.Ctx.GetContext()
 	// iterate all rows
 	for cursor.Next() {
 		select {
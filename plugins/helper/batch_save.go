@@ -42,6 +42,7 @@ type BatchSave struct {
 	valueIndex map[string]int
 	primaryKey []reflect.StructField
 	tableName  string
+	cdc        *cdcRecorder
 }
 
 // NewBatchSave creates a new BatchSave instance
@@ -71,6 +72,7 @@ func NewBatchSave(basicRes core.BasicRes, slotType reflect.Type, size int, table
 		valueIndex: make(map[string]int),
 		primaryKey: primaryKey,
 		tableName:  tn,
+		cdc:        newCdcRecorder(basicRes, slotType, primaryKey, tn),
 	}, nil
 }
 
@@ -111,7 +113,10 @@ func (c *BatchSave) Flush() errors.Error {
 	if c.tableName != "" {
 		clauses = append(clauses, dal.From(c.tableName))
 	}
-	err := c.db.CreateOrUpdate(c.slots.Slice(0, c.current).Interface(), clauses...)
+	slots := c.slots.Slice(0, c.current)
+	err := c.cdc.save(c.db, slots, func(db dal.Dal) errors.Error {
+		return db.CreateOrUpdate(slots.Interface(), clauses...)
+	})
 	if err != nil {
 		return err
 	}
@@ -124,9 +129,12 @@ func (c *BatchSave) Flush() errors.Error {
 // Close would flash the cache and release resources
 func (c *BatchSave) Close() errors.Error {
 	if c.current > 0 {
-		return c.Flush()
+		err := c.Flush()
+		if err != nil {
+			return err
+		}
 	}
-	return nil
+	return c.cdc.flushDeletes(c.db)
 }
 
 func getKeyValue(iface interface{}, primaryKey []reflect.StructField) string {
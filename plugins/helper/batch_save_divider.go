@@ -37,6 +37,8 @@ type BatchSaveDivider struct {
 	batchSize int
 	table     string
 	params    string
+	// incrementalMode keeps the records saved by the previous runs
+	incrementalMode bool
 }
 
 // NewBatchSaveDivider create a new BatchInsertDivider instance
@@ -53,6 +55,11 @@ func NewBatchSaveDivider(basicRes core.BasicRes, batchSize int, table string, pa
 	}
 }
 
+// SetIncrementalMode stops the divider from deleting the outdated records, so the new records would be appended
+func (d *BatchSaveDivider) SetIncrementalMode(incrementalMode bool) {
+	d.incrementalMode = incrementalMode
+}
+
 // ForType returns a `BatchSave` instance for specific type
 func (d *BatchSaveDivider) ForType(rowType reflect.Type) (*BatchSave, errors.Error) {
 	// get the cache for the specific type
@@ -75,18 +82,32 @@ func (d *BatchSaveDivider) ForType(rowType reflect.Type) (*BatchSave, errors.Err
 			return nil, errors.Default.New(fmt.Sprintf("type %s must have RawDataOrigin embeded", rowElemType.Name()))
 		}
 		// all good, delete outdated records before we insertion
-		d.log.Debug("deleting outdate records for %s", rowElemType.Name())
-		err = d.db.Delete(
-			row,
-			dal.Where("_raw_data_table = ? AND _raw_data_params = ?", d.table, d.params),
-		)
-		if err != nil {
-			return nil, err
+		if !d.incrementalMode {
+			d.log.Debug("deleting outdate records for %s", rowElemType.Name())
+			where := dal.Where("_raw_data_table = ? AND _raw_data_params = ?", d.table, d.params)
+			err = batch.cdc.delete(d.db, d.table, d.params, row, where)
+			if err != nil {
+				return nil, err
+			}
 		}
 	}
 	return batch, nil
 }
 
+// Flush saves the cached records of all types into database, the divider could still be used afterwards
+func (d *BatchSaveDivider) Flush() errors.Error {
+	for _, batch := range d.batches {
+		if batch.current == 0 {
+			continue
+		}
+		err := batch.Flush()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // Close all batches so the rest records get saved into db
 func (d *BatchSaveDivider) Close() errors.Error {
 	for _, batch := range d.batches {
@@ -57,6 +57,7 @@ func TestBatchSaveDivider(t *testing.T) {
 
 	mockRes.On("GetDal").Return(mockDal)
 	mockRes.On("GetLogger").Return(mockLog)
+	mockRes.On("GetConfig", "CDC_ENABLED").Return("")
 
 	// we expect total 2 deletion calls after all code got carried out
 	mockDal.On("Delete", mock.Anything, mock.Anything).Return(nil).Twice()
@@ -87,3 +88,29 @@ func TestBatchSaveDivider(t *testing.T) {
 	// assertion
 	mockDal.AssertExpectations(t)
 }
+
+func TestBatchSaveDividerIncrementalMode(t *testing.T) {
+	mockDal := new(mocks.Dal)
+
+	mockLog := unithelper.DummyLogger()
+	mockRes := new(mocks.BasicRes)
+
+	mockRes.On("GetDal").Return(mockDal)
+	mockRes.On("GetLogger").Return(mockLog)
+	mockRes.On("GetConfig", "CDC_ENABLED").Return("")
+
+	mockDal.On("GetPrimaryKeyFields", mock.Anything).Return(
+		[]reflect.StructField{
+			{Name: "ID", Type: reflect.TypeOf("")},
+		},
+	)
+
+	divider := NewBatchSaveDivider(mockRes, 10, "", "")
+	divider.SetIncrementalMode(true)
+
+	_, err := divider.ForType(reflect.TypeOf(&MockJirIssueBsd{}))
+	assert.Nil(t, err)
+
+	// the outdated records should be kept in incremental mode
+	mockDal.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,373 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helper
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/apache/incubator-devlake/errors"
+	"github.com/apache/incubator-devlake/models"
+	"github.com/apache/incubator-devlake/models/common"
+	"github.com/apache/incubator-devlake/models/domainlayer/domaininfo"
+	"github.com/apache/incubator-devlake/plugins/core"
+	"github.com/apache/incubator-devlake/plugins/core/dal"
+	"gorm.io/gorm/schema"
+)
+
+type pipelineIdContextKey struct{}
+
+// ContextWithPipelineId attaches the id of the running pipeline to the context, so the records written by
+// the subtasks could be traced back to it
+func ContextWithPipelineId(ctx context.Context, pipelineId uint64) context.Context {
+	return context.WithValue(ctx, pipelineIdContextKey{}, pipelineId)
+}
+
+// PipelineIdFromContext returns the pipeline id attached by ContextWithPipelineId, 0 if there was none
+func PipelineIdFromContext(ctx context.Context) uint64 {
+	if ctx == nil {
+		return 0
+	}
+	pipelineId, _ := ctx.Value(pipelineIdContextKey{}).(uint64)
+	return pipelineId
+}
+
+var domainTables map[string]bool
+var domainTablesOnce sync.Once
+
+func isDomainTable(table string) bool {
+	domainTablesOnce.Do(func() {
+		domainTables = make(map[string]bool)
+		for _, tabler := range domaininfo.GetDomainTablesInfo() {
+			domainTables[tabler.TableName()] = true
+		}
+	})
+	return domainTables[table]
+}
+
+// cdcRecorder writes the changes made by BatchSave to the `_devlake_cdc_events` outbox, it is enabled by
+// the `CDC_ENABLED` config and only tracks the domain layer tables. The events are written in the transaction
+// of the records, so they are committed or rolled back together
+type cdcRecorder struct {
+	slotType   reflect.Type
+	table      string
+	primaryKey []reflect.StructField
+	columns    []string
+	pipelineId uint64
+	// scope identifies the snapshots of the records deleted by the BatchSaveDivider, empty when nothing was deleted
+	scope string
+}
+
+// cdcPageSize is the number of records read at once while capturing the deleted ones
+const cdcPageSize = 500
+
+var noPKModelType = reflect.TypeOf(common.NoPKModel{})
+var modelType = reflect.TypeOf(common.Model{})
+var rawDataOriginType = reflect.TypeOf(common.RawDataOrigin{})
+
+func newCdcRecorder(basicRes core.BasicRes, slotType reflect.Type, primaryKey []reflect.StructField, tableName string) *cdcRecorder {
+	enabled, _ := strconv.ParseBool(basicRes.GetConfig("CDC_ENABLED"))
+	if !enabled {
+		return nil
+	}
+	if tableName == "" {
+		tabler, ok := reflect.New(slotType.Elem()).Interface().(dal.Tabler)
+		if !ok {
+			return nil
+		}
+		tableName = tabler.TableName()
+	}
+	if !isDomainTable(tableName) {
+		return nil
+	}
+	var pipelineId uint64
+	if execCtx, ok := basicRes.(core.ExecContext); ok {
+		pipelineId = PipelineIdFromContext(execCtx.GetContext())
+	}
+	columns := make([]string, len(primaryKey))
+	for i, field := range primaryKey {
+		columns[i] = cdcColumnName(field)
+	}
+	return &cdcRecorder{
+		slotType:   slotType,
+		table:      tableName,
+		primaryKey: primaryKey,
+		columns:    columns,
+		pipelineId: pipelineId,
+	}
+}
+
+// cdcTransaction runs fn in a new transaction, or in the one of the caller if db is already a transaction
+func cdcTransaction(db dal.Dal, fn func(tx dal.Dal) errors.Error) (err errors.Error) {
+	if _, ok := db.(dal.Transaction); ok {
+		return fn(db)
+	}
+	tx := db.Begin()
+	defer func() {
+		if r := recover(); r != nil || err != nil {
+			if rollbackErr := tx.Rollback(); rollbackErr != nil {
+				err = errors.Default.Wrap(rollbackErr, "failed to rollback the cdc transaction")
+			}
+			if r != nil {
+				panic(r)
+			}
+		}
+	}()
+	err = fn(tx)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// delete removes the outdated records of the raw data scope, the recorder keeps a snapshot of each of them
+// so saving one again is recorded as an update, or as nothing when it did not change
+func (r *cdcRecorder) delete(db dal.Dal, rawTable string, rawParams string, entity interface{}, where dal.Clause) errors.Error {
+	if r == nil {
+		return db.Delete(entity, where)
+	}
+	r.scope = fmt.Sprintf("%x", sha256.Sum256([]byte(strings.Join([]string{r.table, rawTable, rawParams}, "\x00"))))
+	return cdcTransaction(db, func(tx dal.Dal) errors.Error {
+		err := r.captureSnapshots(tx, where)
+		if err != nil {
+			return err
+		}
+		return tx.Delete(entity, where)
+	})
+}
+
+// captureSnapshots pages through the records matching the clause, so they are never all in memory. The
+// snapshots left by an interrupted run of the same scope are overwritten and recorded as deletions by this one
+func (r *cdcRecorder) captureSnapshots(db dal.Dal, where dal.Clause) errors.Error {
+	now := time.Now()
+	for offset := 0; ; offset += cdcPageSize {
+		records := reflect.New(reflect.SliceOf(r.slotType))
+		err := db.All(
+			records.Interface(),
+			dal.From(r.table),
+			where,
+			dal.Orderby(strings.Join(r.columns, ",")),
+			dal.Limit(cdcPageSize),
+			dal.Offset(offset),
+		)
+		if err != nil {
+			return err
+		}
+		count := records.Elem().Len()
+		if count == 0 {
+			return nil
+		}
+		snapshots := make([]*models.CdcSnapshot, count)
+		for i := 0; i < count; i++ {
+			record := records.Elem().Index(i).Interface()
+			snapshots[i] = &models.CdcSnapshot{
+				Scope:       r.scope,
+				EntityId:    getKeyValue(record, r.primaryKey),
+				DomainTable: r.table,
+				Fingerprint: cdcFingerprint(record),
+				CreatedAt:   now,
+			}
+		}
+		err = db.CreateOrUpdate(snapshots)
+		if err != nil {
+			return err
+		}
+		if count < cdcPageSize {
+			return nil
+		}
+	}
+}
+
+// save runs write along with the events of the records, the records whose persisted fields did not change
+// produce no event
+func (r *cdcRecorder) save(db dal.Dal, slots reflect.Value, write func(db dal.Dal) errors.Error) errors.Error {
+	if r == nil || slots.Len() == 0 {
+		return write(db)
+	}
+	return cdcTransaction(db, func(tx dal.Dal) errors.Error {
+		events, resaved, err := r.diff(tx, slots)
+		if err != nil {
+			return err
+		}
+		err = write(tx)
+		if err != nil {
+			return err
+		}
+		if len(events) > 0 {
+			err = tx.Create(events)
+			if err != nil {
+				return err
+			}
+		}
+		if len(resaved) == 0 {
+			return nil
+		}
+		return tx.Delete(&models.CdcSnapshot{}, dal.Where("scope = ? AND entity_id IN ?", r.scope, resaved))
+	})
+}
+
+// diff compares the records to be saved with the stored ones or with the snapshots of the deleted ones,
+// it returns the events along with the keys of the snapshots which are saved again
+func (r *cdcRecorder) diff(db dal.Dal, slots reflect.Value) ([]*models.CdcEvent, []string, errors.Error) {
+	keys := make([]string, slots.Len())
+	values := make([]interface{}, slots.Len())
+	for i := 0; i < slots.Len(); i++ {
+		slot := slots.Index(i).Interface()
+		keys[i] = getKeyValue(slot, r.primaryKey)
+		values[i] = r.keyColumnValues(slot)
+	}
+	where := dal.Where(fmt.Sprintf("(%s) IN ?", strings.Join(r.columns, ",")), values)
+	if len(r.columns) == 1 {
+		where = dal.Where(fmt.Sprintf("%s IN ?", r.columns[0]), values)
+	}
+	records := reflect.New(reflect.SliceOf(r.slotType))
+	err := db.All(records.Interface(), dal.From(r.table), where)
+	if err != nil {
+		return nil, nil, err
+	}
+	fingerprints := make(map[string]string, records.Elem().Len())
+	for i := 0; i < records.Elem().Len(); i++ {
+		record := records.Elem().Index(i).Interface()
+		fingerprints[getKeyValue(record, r.primaryKey)] = cdcFingerprint(record)
+	}
+	var resaved []string
+	if r.scope != "" {
+		var snapshots []*models.CdcSnapshot
+		err = db.All(&snapshots, dal.Where("scope = ? AND entity_id IN ?", r.scope, keys))
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, snapshot := range snapshots {
+			resaved = append(resaved, snapshot.EntityId)
+			if _, ok := fingerprints[snapshot.EntityId]; !ok {
+				fingerprints[snapshot.EntityId] = snapshot.Fingerprint
+			}
+		}
+	}
+	var events []*models.CdcEvent
+	for i, key := range keys {
+		fingerprint, ok := fingerprints[key]
+		if !ok {
+			events = append(events, r.newEvent(key, models.CDC_INSERT))
+		} else if fingerprint != cdcFingerprint(slots.Index(i).Interface()) {
+			events = append(events, r.newEvent(key, models.CDC_UPDATE))
+		}
+	}
+	return events, resaved, nil
+}
+
+// flushDeletes records the deletion of the snapshots which were never saved again
+func (r *cdcRecorder) flushDeletes(db dal.Dal) errors.Error {
+	if r == nil || r.scope == "" {
+		return nil
+	}
+	return cdcTransaction(db, func(tx dal.Dal) errors.Error {
+		err := tx.Exec(
+			fmt.Sprintf(
+				"INSERT INTO %s (pipeline_id, domain_table, entity_id, operation, created_at) "+
+					"SELECT ?, domain_table, entity_id, ?, ? FROM %s WHERE scope = ?",
+				models.CdcEvent{}.TableName(), models.CdcSnapshot{}.TableName(),
+			),
+			r.pipelineId, models.CDC_DELETE, time.Now(), r.scope,
+		)
+		if err != nil {
+			return err
+		}
+		return tx.Delete(&models.CdcSnapshot{}, dal.Where("scope = ?", r.scope))
+	})
+}
+
+func (r *cdcRecorder) newEvent(key string, operation string) *models.CdcEvent {
+	return &models.CdcEvent{
+		PipelineId:  r.pipelineId,
+		DomainTable: r.table,
+		EntityId:    key,
+		Operation:   operation,
+		CreatedAt:   time.Now(),
+	}
+}
+
+func (r *cdcRecorder) keyColumnValues(slot interface{}) interface{} {
+	v := reflect.ValueOf(slot).Elem()
+	if len(r.primaryKey) == 1 {
+		return v.FieldByName(r.primaryKey[0].Name).Interface()
+	}
+	values := make([]interface{}, len(r.primaryKey))
+	for i, field := range r.primaryKey {
+		values[i] = v.FieldByName(field.Name).Interface()
+	}
+	return values
+}
+
+func cdcColumnName(field reflect.StructField) string {
+	for _, setting := range strings.Split(field.Tag.Get("gorm"), ";") {
+		kv := strings.SplitN(setting, ":", 2)
+		if len(kv) == 2 && strings.EqualFold(strings.TrimSpace(kv[0]), "column") {
+			return strings.TrimSpace(kv[1])
+		}
+	}
+	return schema.NamingStrategy{}.ColumnName("", field.Name)
+}
+
+// cdcFingerprint hashes the persisted fields of a record, the bookkeeping fields of the framework are left out and
+// the times are compared to the second, since the database keeps less precision than the collected values
+func cdcFingerprint(record interface{}) string {
+	hash := sha256.New()
+	writeCdcFields(hash, reflect.Indirect(reflect.ValueOf(record)))
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+func writeCdcFields(w io.Writer, v reflect.Value) {
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		if !field.IsExported() || strings.HasPrefix(field.Tag.Get("gorm"), "-") {
+			continue
+		}
+		switch {
+		case field.Type == noPKModelType, field.Type == rawDataOriginType:
+			continue
+		case field.Type == modelType:
+			_, _ = fmt.Fprintf(w, "ID=%d;", v.Field(i).Interface().(common.Model).ID)
+		case field.Anonymous && field.Type.Kind() == reflect.Struct:
+			writeCdcFields(w, v.Field(i))
+		default:
+			_, _ = fmt.Fprintf(w, "%s=%s;", field.Name, cdcValue(v.Field(i)))
+		}
+	}
+}
+
+func cdcValue(v reflect.Value) string {
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return "<nil>"
+		}
+		v = v.Elem()
+	}
+	if t, ok := v.Interface().(time.Time); ok {
+		return strconv.FormatInt(t.Unix(), 10)
+	}
+	return fmt.Sprintf("%v", v.Interface())
+}
@@ -0,0 +1,222 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helper
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/apache/incubator-devlake/errors"
+	"github.com/apache/incubator-devlake/helpers/unithelper"
+	"github.com/apache/incubator-devlake/mocks"
+	"github.com/apache/incubator-devlake/models"
+	"github.com/apache/incubator-devlake/models/common"
+	"github.com/apache/incubator-devlake/plugins/core/dal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestPipelineIdFromContext(t *testing.T) {
+	assert.Equal(t, uint64(0), PipelineIdFromContext(context.Background()))
+	ctx := ContextWithPipelineId(context.Background(), 42)
+	assert.Equal(t, uint64(42), PipelineIdFromContext(ctx))
+}
+
+func TestCdcColumnName(t *testing.T) {
+	type row struct {
+		Id        string `gorm:"primaryKey;type:varchar(255)"`
+		CommitSha string `gorm:"primaryKey"`
+		Custom    string `gorm:"primaryKey;column:custom_key"`
+	}
+	rowType := reflect.TypeOf(row{})
+	assert.Equal(t, "id", cdcColumnName(rowType.Field(0)))
+	assert.Equal(t, "commit_sha", cdcColumnName(rowType.Field(1)))
+	assert.Equal(t, "custom_key", cdcColumnName(rowType.Field(2)))
+}
+
+func TestIsDomainTable(t *testing.T) {
+	assert.True(t, isDomainTable("pull_requests"))
+	assert.True(t, isDomainTable("cicd_tasks"))
+	assert.False(t, isDomainTable("_tool_github_pull_requests"))
+}
+
+type cdcTestPullRequest struct {
+	common.NoPKModel
+	Id         string `gorm:"primaryKey"`
+	Title      string
+	MergedDate *time.Time
+	Comments   int `gorm:"-"`
+}
+
+func (cdcTestPullRequest) TableName() string {
+	return "pull_requests"
+}
+
+func TestCdcFingerprint(t *testing.T) {
+	mergedDate := time.Date(2023, 1, 2, 3, 4, 5, 678901234, time.UTC)
+	pr := &cdcTestPullRequest{Id: "1", Title: "fix", MergedDate: &mergedDate}
+	stored := &cdcTestPullRequest{Id: "1", Title: "fix", Comments: 3}
+	stored.CreatedAt = time.Now()
+	stored.RawDataId = 42
+	storedMergedDate := mergedDate.Truncate(time.Millisecond).In(time.FixedZone("UTC+8", 8*3600))
+	stored.MergedDate = &storedMergedDate
+	assert.Equal(t, cdcFingerprint(pr), cdcFingerprint(stored))
+
+	stored.Title = "fix the build"
+	assert.NotEqual(t, cdcFingerprint(pr), cdcFingerprint(stored))
+	stored.Title = "fix"
+	stored.MergedDate = nil
+	assert.NotEqual(t, cdcFingerprint(pr), cdcFingerprint(stored))
+}
+
+func newCdcTestRes(mockDal *mocks.Dal) *mocks.BasicRes {
+	mockRes := new(mocks.BasicRes)
+	mockRes.On("GetDal").Return(mockDal)
+	mockRes.On("GetLogger").Return(unithelper.DummyLogger())
+	mockRes.On("GetConfig", "CDC_ENABLED").Return("true")
+	mockDal.On("GetPrimaryKeyFields", mock.Anything).Return(
+		[]reflect.StructField{reflect.TypeOf(cdcTestPullRequest{}).Field(1)},
+	)
+	return mockRes
+}
+
+func cdcOperations(events []*models.CdcEvent) map[string]string {
+	operations := make(map[string]string)
+	for _, event := range events {
+		operations[event.EntityId] = event.Operation
+	}
+	return operations
+}
+
+func TestBatchSaveRecordsChangedRecords(t *testing.T) {
+	mockDal := new(mocks.Dal)
+	mockTx := new(mocks.Transaction)
+	mockRes := newCdcTestRes(mockDal)
+	mockDal.On("Begin").Return(mockTx)
+	mockTx.On("All", mock.AnythingOfType("*[]*helper.cdcTestPullRequest"), mock.Anything).Run(func(args mock.Arguments) {
+		dst := args.Get(0).(*[]*cdcTestPullRequest)
+		*dst = []*cdcTestPullRequest{{Id: "1", Title: "unchanged"}, {Id: "2", Title: "before"}}
+	}).Return(nil).Once()
+	mockTx.On("CreateOrUpdate", mock.Anything, mock.Anything).Return(nil).Once()
+	var events []*models.CdcEvent
+	mockTx.On("Create", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		events = args.Get(0).([]*models.CdcEvent)
+	}).Return(nil).Once()
+	mockTx.On("Commit").Return(nil).Once()
+
+	batch, err := NewBatchSave(mockRes, reflect.TypeOf(&cdcTestPullRequest{}), 10)
+	assert.Nil(t, err)
+	assert.Nil(t, batch.Add(&cdcTestPullRequest{Id: "1", Title: "unchanged"}))
+	assert.Nil(t, batch.Add(&cdcTestPullRequest{Id: "2", Title: "after"}))
+	assert.Nil(t, batch.Add(&cdcTestPullRequest{Id: "3", Title: "new"}))
+	assert.Nil(t, batch.Close())
+
+	assert.Equal(t, map[string]string{"2": models.CDC_UPDATE, "3": models.CDC_INSERT}, cdcOperations(events))
+	assert.Equal(t, "pull_requests", events[0].DomainTable)
+	mockTx.AssertExpectations(t)
+	mockTx.AssertNotCalled(t, "Rollback")
+}
+
+func TestBatchSaveRollsBackEventsWithRecords(t *testing.T) {
+	mockDal := new(mocks.Dal)
+	mockTx := new(mocks.Transaction)
+	mockRes := newCdcTestRes(mockDal)
+	mockDal.On("Begin").Return(mockTx)
+	mockTx.On("All", mock.Anything, mock.Anything).Return(nil)
+	mockTx.On("CreateOrUpdate", mock.Anything, mock.Anything).Return(errors.Default.New("deadlock"))
+	mockTx.On("Rollback").Return(nil).Once()
+
+	batch, err := NewBatchSave(mockRes, reflect.TypeOf(&cdcTestPullRequest{}), 10)
+	assert.Nil(t, err)
+	assert.Nil(t, batch.Add(&cdcTestPullRequest{Id: "1"}))
+	assert.NotNil(t, batch.Flush())
+
+	mockTx.AssertExpectations(t)
+	mockTx.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	mockTx.AssertNotCalled(t, "Commit")
+}
+
+func TestBatchSaveDividerRecordsDeletes(t *testing.T) {
+	mockDal := new(mocks.Dal)
+	mockRes := newCdcTestRes(mockDal)
+
+	// the outdated records are captured as snapshots in the transaction deleting them
+	captureTx := new(mocks.Transaction)
+	mockDal.On("Begin").Return(captureTx).Once()
+	captureTx.On("All", mock.AnythingOfType("*[]*helper.cdcTestPullRequest"), mock.Anything).Run(func(args mock.Arguments) {
+		dst := args.Get(0).(*[]*cdcTestPullRequest)
+		*dst = []*cdcTestPullRequest{{Id: "1", Title: "unchanged"}, {Id: "2", Title: "removed"}}
+	}).Return(nil).Once()
+	var snapshots []*models.CdcSnapshot
+	captureTx.On("CreateOrUpdate", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		snapshots = args.Get(0).([]*models.CdcSnapshot)
+	}).Return(nil).Once()
+	captureTx.On("Delete", mock.Anything, mock.Anything).Return(nil).Once()
+	captureTx.On("Commit").Return(nil).Once()
+
+	divider := NewBatchSaveDivider(mockRes, 10, "_raw_github_api_pull_requests", `{"Name":"apache/incubator-devlake"}`)
+	batch, err := divider.ForType(reflect.TypeOf(&cdcTestPullRequest{}))
+	assert.Nil(t, err)
+	captureTx.AssertExpectations(t)
+	assert.Len(t, snapshots, 2)
+	assert.Equal(t, "1", snapshots[0].EntityId)
+	assert.Equal(t, cdcFingerprint(&cdcTestPullRequest{Id: "1", Title: "unchanged"}), snapshots[0].Fingerprint)
+	scope := snapshots[0].Scope
+
+	// saving a captured record again compares it with its snapshot
+	saveTx := new(mocks.Transaction)
+	mockDal.On("Begin").Return(saveTx).Once()
+	saveTx.On("All", mock.AnythingOfType("*[]*helper.cdcTestPullRequest"), mock.Anything).Return(nil).Once()
+	saveTx.On("All", mock.AnythingOfType("*[]*models.CdcSnapshot"), mock.Anything).Run(func(args mock.Arguments) {
+		dst := args.Get(0).(*[]*models.CdcSnapshot)
+		*dst = snapshots[:1]
+	}).Return(nil).Once()
+	saveTx.On("CreateOrUpdate", mock.Anything, mock.Anything).Return(nil).Once()
+	var events []*models.CdcEvent
+	saveTx.On("Create", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		events = args.Get(0).([]*models.CdcEvent)
+	}).Return(nil).Once()
+	var resaved []dal.Clause
+	saveTx.On("Delete", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		resaved = args.Get(1).([]dal.Clause)
+	}).Return(nil).Once()
+	saveTx.On("Commit").Return(nil).Once()
+
+	// the snapshots which were not saved again are recorded as deletions
+	deleteTx := new(mocks.Transaction)
+	mockDal.On("Begin").Return(deleteTx).Once()
+	var deleteParams []interface{}
+	deleteTx.On("Exec", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		deleteParams = args.Get(1).([]interface{})
+	}).Return(nil).Once()
+	deleteTx.On("Delete", mock.Anything, mock.Anything).Return(nil).Once()
+	deleteTx.On("Commit").Return(nil).Once()
+
+	assert.Nil(t, batch.Add(&cdcTestPullRequest{Id: "1", Title: "unchanged"}))
+	assert.Nil(t, batch.Add(&cdcTestPullRequest{Id: "3", Title: "new"}))
+	assert.Nil(t, divider.Close())
+
+	saveTx.AssertExpectations(t)
+	assert.Equal(t, map[string]string{"3": models.CDC_INSERT}, cdcOperations(events))
+	assert.Equal(t, []interface{}{scope, []string{"1"}}, resaved[0].Data.(dal.DalClause).Params)
+	deleteTx.AssertExpectations(t)
+	assert.Equal(t, models.CDC_DELETE, deleteParams[1])
+	assert.Equal(t, scope, deleteParams[3])
+}
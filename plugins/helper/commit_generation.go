@@ -0,0 +1,186 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helper
+
+import (
+	"github.com/apache/incubator-devlake/errors"
+	"github.com/apache/incubator-devlake/models/domainlayer/code"
+	"github.com/apache/incubator-devlake/plugins/core"
+	"github.com/apache/incubator-devlake/plugins/core/dal"
+)
+
+// commitGenerationQueryLimit keeps the IN clauses of the generation queries within the placeholder limits
+const commitGenerationQueryLimit = 1000
+
+// UpdateCommitGenerations calculates the generation numbers of the commits of the repo which do not have one yet,
+// only the new commits and the generations of their direct parents are loaded, so it is cheap for incremental runs
+func UpdateCommitGenerations(db dal.Dal, logger core.Logger, repoId string) errors.Error {
+	var pending []string
+	err := db.Pluck(
+		"rc.commit_sha",
+		&pending,
+		dal.From("repo_commits rc"),
+		dal.Join("LEFT JOIN commit_generations cg ON (cg.commit_sha = rc.commit_sha)"),
+		dal.Where("rc.repo_id = ? AND cg.commit_sha IS NULL", repoId),
+	)
+	if err != nil {
+		return err
+	}
+	if len(pending) == 0 {
+		return nil
+	}
+
+	parents, err := LoadCommitParents(db, pending)
+	if err != nil {
+		return err
+	}
+	generations, err := loadKnownParentGenerations(db, pending, parents)
+	if err != nil {
+		return err
+	}
+	calculated := CalculateCommitGenerations(pending, parents, generations)
+
+	commitGenerations := make([]*code.CommitGeneration, 0, commitGenerationQueryLimit)
+	for i, sha := range pending {
+		commitGenerations = append(commitGenerations, &code.CommitGeneration{
+			CommitSha:  sha,
+			Generation: calculated[sha],
+		})
+		if len(commitGenerations) == commitGenerationQueryLimit || i == len(pending)-1 {
+			err = db.CreateOrUpdate(commitGenerations)
+			if err != nil {
+				return err
+			}
+			commitGenerations = commitGenerations[:0]
+		}
+	}
+	logger.Info("generation numbers of %d commits of repo %s updated", len(pending), repoId)
+	return nil
+}
+
+// CalculateCommitGenerations calculates the generation numbers of the pending commits in topological order,
+// parents missing from both the pending commits and the known generations are treated as generation 0
+func CalculateCommitGenerations(pending []string, parents map[string][]string, known map[string]int) map[string]int {
+	isPending := make(map[string]bool, len(pending))
+	for _, sha := range pending {
+		isPending[sha] = true
+	}
+	// count the pending parents of each commit, a commit is ready once all of them are calculated
+	waiting := make(map[string]int, len(pending))
+	children := make(map[string][]string)
+	for _, sha := range pending {
+		for _, parent := range parents[sha] {
+			if isPending[parent] {
+				waiting[sha]++
+				children[parent] = append(children[parent], sha)
+			}
+		}
+	}
+	var ready []string
+	for _, sha := range pending {
+		if waiting[sha] == 0 {
+			ready = append(ready, sha)
+		}
+	}
+
+	generations := make(map[string]int, len(pending))
+	for len(ready) > 0 {
+		sha := ready[len(ready)-1]
+		ready = ready[:len(ready)-1]
+		generation := 0
+		for _, parent := range parents[sha] {
+			parentGeneration, ok := generations[parent]
+			if !ok {
+				parentGeneration = known[parent]
+			}
+			if parentGeneration > generation {
+				generation = parentGeneration
+			}
+		}
+		generations[sha] = generation + 1
+		for _, child := range children[sha] {
+			waiting[child]--
+			if waiting[child] == 0 {
+				ready = append(ready, child)
+			}
+		}
+	}
+	return generations
+}
+
+// LoadCommitParents returns the parent shas of the given commits
+func LoadCommitParents(db dal.Dal, shas []string) (map[string][]string, errors.Error) {
+	parents := make(map[string][]string)
+	for start := 0; start < len(shas); start += commitGenerationQueryLimit {
+		end := start + commitGenerationQueryLimit
+		if end > len(shas) {
+			end = len(shas)
+		}
+		var commitParents []code.CommitParent
+		err := db.All(
+			&commitParents,
+			dal.Select("commit_sha, parent_commit_sha"),
+			dal.Where("commit_sha IN ?", shas[start:end]),
+		)
+		if err != nil {
+			return nil, err
+		}
+		for _, cp := range commitParents {
+			parents[cp.CommitSha] = append(parents[cp.CommitSha], cp.ParentCommitSha)
+		}
+	}
+	return parents, nil
+}
+
+func loadKnownParentGenerations(db dal.Dal, pending []string, parents map[string][]string) (map[string]int, errors.Error) {
+	isPending := make(map[string]bool, len(pending))
+	for _, sha := range pending {
+		isPending[sha] = true
+	}
+	var shas []string
+	seen := make(map[string]bool)
+	for _, pp := range parents {
+		for _, parent := range pp {
+			if !isPending[parent] && !seen[parent] {
+				seen[parent] = true
+				shas = append(shas, parent)
+			}
+		}
+	}
+	return LoadCommitGenerations(db, shas)
+}
+
+// LoadCommitGenerations returns the stored generation numbers of the given commits, unknown ones are left out
+func LoadCommitGenerations(db dal.Dal, shas []string) (map[string]int, errors.Error) {
+	generations := make(map[string]int, len(shas))
+	for start := 0; start < len(shas); start += commitGenerationQueryLimit {
+		end := start + commitGenerationQueryLimit
+		if end > len(shas) {
+			end = len(shas)
+		}
+		var commitGenerations []code.CommitGeneration
+		err := db.All(&commitGenerations, dal.Where("commit_sha IN ?", shas[start:end]))
+		if err != nil {
+			return nil, err
+		}
+		for _, cg := range commitGenerations {
+			generations[cg.CommitSha] = cg.Generation
+		}
+	}
+	return generations, nil
+}
@@ -0,0 +1,46 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helper
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCalculateCommitGenerations(t *testing.T) {
+	// c and d are stored already, e merges d with the new branch f - g
+	parents := map[string][]string{
+		"e": {"d", "g"},
+		"g": {"f"},
+		"f": {"b"},
+		"h": {"missing"},
+	}
+	known := map[string]int{
+		"b": 2,
+		"d": 4,
+	}
+	generations := CalculateCommitGenerations([]string{"e", "g", "f", "h", "root"}, parents, known)
+	assert.Equal(t, map[string]int{
+		"f":    3,
+		"g":    4,
+		"e":    5,
+		"h":    1,
+		"root": 1,
+	}, generations)
+}
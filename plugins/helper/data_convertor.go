@@ -84,13 +84,19 @@ func (converter *DataConverter) Execute() errors.Error {
 	// batch save divider
 	RAW_DATA_ORIGIN := "RawDataOrigin"
 	divider := NewBatchSaveDivider(converter.args.Ctx, converter.args.BatchSize, converter.table, converter.params)
+	ctx := converter.args.Ctx.GetContext()
+	// only the rows extracted from the specified raw data get converted, and the others are kept. The converters are
+	// expected to read them only with RawDataIdsClauses, the rows are checked again for the ones which don't
+	rawDataIds := rawDataIdsFromContext(ctx, converter.table)
+	if rawDataIds != nil {
+		divider.SetIncrementalMode(true)
+	}
 
 	// set progress
 	converter.args.Ctx.SetProgress(0, -1)
 
 	cursor := converter.args.Input
 	defer cursor.Close()
-	ctx := converter.args.Ctx.GetContext()
 	// iterate all rows
 	for cursor.Next() {
 		select {
@@ -103,6 +109,12 @@ func (converter *DataConverter) Execute() errors.Error {
 		if err != nil {
 			return errors.Default.Wrap(err, "error fetching rows")
 		}
+		if rawDataIds != nil {
+			inputOrigin := reflect.ValueOf(inputRow).Elem().FieldByName(RAW_DATA_ORIGIN)
+			if inputOrigin.IsValid() && !rawDataIds[inputOrigin.FieldByName("RawDataId").Uint()] {
+				continue
+			}
+		}
 
 		results, err := converter.args.Convert(inputRow)
 		if err != nil {
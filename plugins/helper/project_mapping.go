@@ -0,0 +1,38 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helper
+
+import (
+	"fmt"
+
+	"github.com/apache/incubator-devlake/plugins/core/dal"
+)
+
+// ProjectMappingJoin left joins the project mapping of `rowId` as `alias`. With pointInTime, the mapping comes from
+// `project_mapping_history` and must be valid at `date`, so the rows moved between projects count for the project
+// they belonged to at that time, otherwise the current `project_mapping` is used.
+// Both tables share the `project_name`, `table` and `row_id` columns, so the conditions on `alias` work either way
+func ProjectMappingJoin(alias, rowId, date string, pointInTime bool) dal.Clause {
+	if !pointInTime {
+		return dal.Join(fmt.Sprintf("left join project_mapping %s on %s.row_id = %s", alias, alias, rowId))
+	}
+	return dal.Join(fmt.Sprintf(
+		"left join project_mapping_history %s on %s.row_id = %s and %s.valid_from <= %s and (%s.valid_to is null or %s.valid_to > %s)",
+		alias, alias, rowId, alias, date, alias, alias, date,
+	))
+}
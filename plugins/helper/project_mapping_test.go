@@ -0,0 +1,38 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helper
+
+import (
+	"testing"
+
+	"github.com/apache/incubator-devlake/plugins/core/dal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProjectMappingJoin(t *testing.T) {
+	clause := ProjectMappingJoin("pm", "ct.cicd_scope_id", "ct.finished_date", false)
+	assert.Equal(t, dal.JoinClause, clause.Type)
+	assert.Equal(t, "left join project_mapping pm on pm.row_id = ct.cicd_scope_id", clause.Data.(dal.DalClause).Expr)
+
+	clause = ProjectMappingJoin("pm", "ct.cicd_scope_id", "ct.finished_date", true)
+	assert.Equal(t,
+		"left join project_mapping_history pm on pm.row_id = ct.cicd_scope_id and pm.valid_from <= ct.finished_date "+
+			"and (pm.valid_to is null or pm.valid_to > ct.finished_date)",
+		clause.Data.(dal.DalClause).Expr,
+	)
+}
@@ -0,0 +1,95 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helper
+
+import (
+	"context"
+
+	"github.com/apache/incubator-devlake/errors"
+	"github.com/apache/incubator-devlake/plugins/core/dal"
+)
+
+// RAW_DATA_IDS_OPTION is the task option carrying the raw data rows to be processed, like ContextWithRawDataIds
+const RAW_DATA_IDS_OPTION = "rawDataIds"
+
+type rawDataIdsContextKey struct{}
+
+// ContextWithRawDataIds restricts ApiExtractor and DataConverter to the given raw data rows, keyed by the
+// raw table name like `_raw_github_api_pull_requests`. The records of other rows are kept as they are, which
+// allows processing data pushed by webhooks without going over the whole scope again
+func ContextWithRawDataIds(ctx context.Context, rawDataIds map[string][]uint64) context.Context {
+	filters := make(map[string]map[uint64]bool, len(rawDataIds))
+	for table, ids := range rawDataIds {
+		filter := make(map[uint64]bool, len(ids))
+		for _, id := range ids {
+			filter[id] = true
+		}
+		filters[table] = filter
+	}
+	return context.WithValue(ctx, rawDataIdsContextKey{}, filters)
+}
+
+// ContextWithRawDataIdsOption restricts the context to the raw data rows of the RAW_DATA_IDS_OPTION task option,
+// the context is returned as it is if the option is absent
+func ContextWithRawDataIdsOption(ctx context.Context, options map[string]interface{}) (context.Context, errors.Error) {
+	option, ok := options[RAW_DATA_IDS_OPTION]
+	if !ok || option == nil {
+		return ctx, nil
+	}
+	var rawDataIds map[string][]uint64
+	err := Decode(option, &rawDataIds, nil)
+	if err != nil {
+		return nil, errors.BadInput.Wrap(err, "rawDataIds could not be decoded")
+	}
+	return ContextWithRawDataIds(ctx, rawDataIds), nil
+}
+
+// RawDataIdsClauses restricts the input of a converter to the records extracted from the raw data rows of
+// ContextWithRawDataIds, so only they are read instead of the whole scope. The rawTable is the Table of
+// RawDataSubTaskArgs, and the records are qualified by the tool table or alias if the input is a join
+func RawDataIdsClauses(ctx context.Context, rawTable string, toolTable string) []dal.Clause {
+	table := "_raw_" + rawTable
+	filter := rawDataIdsFromContext(ctx, table)
+	if filter == nil {
+		return nil
+	}
+	prefix := ""
+	if toolTable != "" {
+		prefix = toolTable + "."
+	}
+	return []dal.Clause{
+		dal.Where(prefix+"_raw_data_table = ? AND "+prefix+"_raw_data_id IN ?", table, rawDataIdList(filter)),
+	}
+}
+
+// rawDataIdsFromContext returns the raw data rows of the table to be processed, nil if there is no restriction
+func rawDataIdsFromContext(ctx context.Context, table string) map[uint64]bool {
+	if ctx == nil {
+		return nil
+	}
+	filters, _ := ctx.Value(rawDataIdsContextKey{}).(map[string]map[uint64]bool)
+	return filters[table]
+}
+
+func rawDataIdList(filter map[uint64]bool) []uint64 {
+	ids := make([]uint64, 0, len(filter))
+	for id := range filter {
+		ids = append(ids, id)
+	}
+	return ids
+}
@@ -0,0 +1,80 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helper
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"testing"
+
+	"github.com/apache/incubator-devlake/plugins/core/dal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContextWithRawDataIds(t *testing.T) {
+	assert.Nil(t, rawDataIdsFromContext(context.Background(), "_raw_github_api_pull_requests"))
+
+	ctx := ContextWithRawDataIds(context.Background(), map[string][]uint64{
+		"_raw_github_api_pull_requests": {3, 1},
+	})
+	filter := rawDataIdsFromContext(ctx, "_raw_github_api_pull_requests")
+	assert.True(t, filter[1])
+	assert.True(t, filter[3])
+	assert.False(t, filter[2])
+	ids := rawDataIdList(filter)
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	assert.Equal(t, []uint64{1, 3}, ids)
+	assert.Nil(t, rawDataIdsFromContext(ctx, "_raw_github_api_runs"))
+}
+
+func TestContextWithRawDataIdsOption(t *testing.T) {
+	ctx, err := ContextWithRawDataIdsOption(context.Background(), map[string]interface{}{"connectionId": 1})
+	assert.Nil(t, err)
+	assert.Nil(t, rawDataIdsFromContext(ctx, "_raw_github_api_pull_requests"))
+
+	// the options of pipeline tasks are decoded from json
+	var options map[string]interface{}
+	assert.Nil(t, json.Unmarshal([]byte(`{"rawDataIds":{"_raw_github_api_pull_requests":[2,5]}}`), &options))
+	ctx, err = ContextWithRawDataIdsOption(context.Background(), options)
+	assert.Nil(t, err)
+	filter := rawDataIdsFromContext(ctx, "_raw_github_api_pull_requests")
+	assert.True(t, filter[2])
+	assert.True(t, filter[5])
+	assert.False(t, filter[1])
+
+	_, err = ContextWithRawDataIdsOption(context.Background(), map[string]interface{}{"rawDataIds": "1,2"})
+	assert.NotNil(t, err)
+}
+
+func TestRawDataIdsClauses(t *testing.T) {
+	assert.Nil(t, RawDataIdsClauses(context.Background(), "github_api_pull_requests", ""))
+
+	ctx := ContextWithRawDataIds(context.Background(), map[string][]uint64{
+		"_raw_github_api_pull_requests": {3},
+	})
+	assert.Nil(t, RawDataIdsClauses(ctx, "github_api_runs", ""))
+	clauses := RawDataIdsClauses(ctx, "github_api_pull_requests", "")
+	assert.Len(t, clauses, 1)
+	assert.Equal(t, dal.WhereClause, clauses[0].Type)
+	assert.Equal(t, "_raw_data_table = ? AND _raw_data_id IN ?", clauses[0].Data.(dal.DalClause).Expr)
+	assert.Equal(t, []interface{}{"_raw_github_api_pull_requests", []uint64{3}}, clauses[0].Data.(dal.DalClause).Params)
+
+	clauses = RawDataIdsClauses(ctx, "github_api_pull_requests", "pr")
+	assert.Equal(t, "pr._raw_data_table = ? AND pr._raw_data_id IN ?", clauses[0].Data.(dal.DalClause).Expr)
+}
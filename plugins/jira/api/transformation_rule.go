@@ -18,15 +18,21 @@ limitations under the License.
 package api
 
 import (
+	"context"
+	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/apache/incubator-devlake/errors"
+	"github.com/apache/incubator-devlake/models/domainlayer/ticket"
 	"github.com/apache/incubator-devlake/plugins/core"
 	"github.com/apache/incubator-devlake/plugins/core/dal"
 	"github.com/apache/incubator-devlake/plugins/helper"
 	"github.com/apache/incubator-devlake/plugins/jira/models"
 	"github.com/apache/incubator-devlake/plugins/jira/tasks"
+	"github.com/apache/incubator-devlake/plugins/jira/tasks/apiv2models"
 )
 
 // CreateTransformationRule create transformation rule for Jira
@@ -35,6 +41,7 @@ import (
 // @Tags plugins/jira
 // @Accept application/json
 // @Param transformationRule body tasks.JiraTransformationRule true "transformation rule"
+// @Param connectionId query int false "validate customFieldMappings against the fields of this connection"
 // @Success 200  {object} tasks.JiraTransformationRule
 // @Failure 400  {object} shared.ApiBody "Bad Request"
 // @Failure 500  {object} shared.ApiBody "Internal Error"
@@ -61,6 +68,7 @@ func CreateTransformationRule(input *core.ApiResourceInput) (*core.ApiResourceOu
 // @Accept application/json
 // @Param id path int true "id"
 // @Param transformationRule body tasks.JiraTransformationRule true "transformation rule"
+// @Param connectionId query int false "validate customFieldMappings against the fields of this connection"
 // @Success 200  {object} tasks.JiraTransformationRule
 // @Failure 400  {object} shared.ApiBody "Bad Request"
 // @Failure 500  {object} shared.ApiBody "Internal Error"
@@ -79,6 +87,14 @@ func UpdateTransformationRule(input *core.ApiResourceInput) (*core.ApiResourceOu
 	if err != nil {
 		return nil, errors.Default.Wrap(err, "error decoding map into transformationRule")
 	}
+	rule, err := tasks.MakeTransformationRules(old)
+	if err != nil {
+		return nil, errors.BadInput.Wrap(err, "error decoding transformationRule")
+	}
+	err = validateCustomFieldMappings(input, rule.CustomFieldMappings)
+	if err != nil {
+		return nil, err
+	}
 	old.ID = transformationRuleId
 	err = basicRes.GetDal().Update(&old, dal.Where("id = ?", transformationRuleId))
 	if err != nil {
@@ -96,9 +112,66 @@ func makeDbTransformationRuleFromInput(input *core.ApiResourceInput) (*models.Ji
 	if err != nil {
 		return nil, err
 	}
+	err = validateCustomFieldMappings(input, req.CustomFieldMappings)
+	if err != nil {
+		return nil, err
+	}
 	return req.ToDb()
 }
 
+// validateCustomFieldMappings makes sure the target columns exist in table `issues`, and the mapped fields match
+// the metadata returned by `api/2/field` when `connectionId` is specified
+func validateCustomFieldMappings(input *core.ApiResourceInput, mappings []tasks.CustomFieldMapping) errors.Error {
+	if len(mappings) == 0 {
+		return nil
+	}
+	var fields []apiv2models.Field
+	if connectionId := input.Query.Get("connectionId"); connectionId != "" {
+		connection := &models.JiraConnection{}
+		err := connectionHelper.First(connection, map[string]string{"connectionId": connectionId})
+		if err != nil {
+			return err
+		}
+		apiClient, err := helper.NewApiClient(
+			context.TODO(),
+			connection.Endpoint,
+			map[string]string{
+				"Authorization": fmt.Sprintf("Basic %v", connection.GetEncodedToken()),
+			},
+			30*time.Second,
+			connection.Proxy,
+			basicRes,
+		)
+		if err != nil {
+			return err
+		}
+		fields, err = tasks.GetFields(apiClient)
+		if err != nil {
+			return errors.Default.Wrap(err, "failed to get fields from Jira")
+		}
+	}
+	err := tasks.ValidateCustomFieldMappings(mappings, fields)
+	if err != nil {
+		return err
+	}
+	columns, err := basicRes.GetDal().GetColumns(&ticket.Issue{}, func(columnMeta dal.ColumnMeta) bool {
+		return strings.HasPrefix(columnMeta.Name(), "x_")
+	})
+	if err != nil {
+		return err
+	}
+	existingColumns := make(map[string]bool, len(columns))
+	for _, column := range columns {
+		existingColumns[column.Name()] = true
+	}
+	for _, mapping := range mappings {
+		if mapping.TargetColumn != "" && !existingColumns[mapping.TargetColumn] {
+			return errors.BadInput.New(fmt.Sprintf("column %s does not exist in table issues, please create it with the customize plugin first", mapping.TargetColumn))
+		}
+	}
+	return nil
+}
+
 // GetTransformationRule return one transformation rule
 // @Summary return one transformation rule
 // @Description return one transformation rule
@@ -0,0 +1,110 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package e2e
+
+import (
+	"testing"
+
+	"github.com/apache/incubator-devlake/helpers/e2ehelper"
+	"github.com/apache/incubator-devlake/models/domainlayer/ticket"
+	"github.com/apache/incubator-devlake/plugins/jira/impl"
+	"github.com/apache/incubator-devlake/plugins/jira/models"
+	"github.com/apache/incubator-devlake/plugins/jira/tasks"
+)
+
+func TestVersionDataFlow(t *testing.T) {
+	var plugin impl.Jira
+	dataflowTester := e2ehelper.NewDataFlowTester(t, "jira", plugin)
+
+	taskData := &tasks.JiraTaskData{
+		Options: &tasks.JiraOptions{
+			ConnectionId: 2,
+			BoardId:      8,
+		},
+	}
+
+	// import raw data table
+	dataflowTester.ImportCsvIntoRawTable("./raw_tables/_raw_jira_api_versions.csv", "_raw_jira_api_versions")
+	dataflowTester.ImportCsvIntoRawTable("./raw_tables/_raw_jira_api_issues.csv", "_raw_jira_api_issues")
+
+	// verify version extraction
+	dataflowTester.FlushTabler(&models.JiraVersion{})
+	dataflowTester.Subtask(tasks.ExtractVersionsMeta, taskData)
+	dataflowTester.VerifyTable(
+		models.JiraVersion{},
+		"./snapshot_tables/_tool_jira_versions.csv",
+		e2ehelper.ColumnWithRawData(
+			"connection_id",
+			"version_id",
+			"project_id",
+			"self",
+			"name",
+			"description",
+			"archived",
+			"released",
+			"start_date",
+			"release_date",
+		),
+	)
+
+	// verify fixVersions extraction from issues
+	dataflowTester.FlushTabler(&models.JiraIssue{})
+	dataflowTester.FlushTabler(&models.JiraBoardIssue{})
+	dataflowTester.FlushTabler(&models.JiraIssueFixVersion{})
+	dataflowTester.Subtask(tasks.ExtractIssuesMeta, taskData)
+	dataflowTester.VerifyTable(
+		models.JiraIssueFixVersion{},
+		"./snapshot_tables/_tool_jira_issue_fix_versions.csv",
+		e2ehelper.ColumnWithRawData(
+			"connection_id",
+			"issue_id",
+			"version_id",
+		),
+	)
+
+	// verify version conversion
+	dataflowTester.ImportCsvIntoTabler("./snapshot_tables/_tool_jira_projects.csv", &models.JiraProject{})
+	dataflowTester.FlushTabler(&ticket.Version{})
+	dataflowTester.Subtask(tasks.ConvertVersionsMeta, taskData)
+	dataflowTester.VerifyTable(
+		ticket.Version{},
+		"./snapshot_tables/versions.csv",
+		e2ehelper.ColumnWithRawData(
+			"id",
+			"name",
+			"description",
+			"url",
+			"status",
+			"started_date",
+			"released_date",
+			"original_project",
+		),
+	)
+
+	// verify issue fixVersions conversion
+	dataflowTester.FlushTabler(&ticket.IssueFixVersion{})
+	dataflowTester.Subtask(tasks.ConvertIssueFixVersionsMeta, taskData)
+	dataflowTester.VerifyTable(
+		ticket.IssueFixVersion{},
+		"./snapshot_tables/issue_fix_versions.csv",
+		e2ehelper.ColumnWithRawData(
+			"issue_id",
+			"version_id",
+		),
+	)
+}
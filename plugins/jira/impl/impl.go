@@ -75,6 +75,8 @@ func (plugin Jira) GetTablesInfo() []dal.Tabler {
 		&models.JiraIssueChangelogItems{},
 		&models.JiraIssueChangelogs{},
 		&models.JiraIssueCommit{},
+		&models.JiraIssueCustomField{},
+		&models.JiraIssueFixVersion{},
 		&models.JiraIssueLabel{},
 		&models.JiraIssueType{},
 		&models.JiraProject{},
@@ -83,6 +85,7 @@ func (plugin Jira) GetTablesInfo() []dal.Tabler {
 		&models.JiraSprint{},
 		&models.JiraSprintIssue{},
 		&models.JiraStatus{},
+		&models.JiraVersion{},
 		&models.JiraWorklog{},
 	}
 }
@@ -122,6 +125,7 @@ func (plugin Jira) SubTaskMetas() []core.SubTaskMeta {
 		tasks.ConvertBoardMeta,
 
 		tasks.ConvertIssuesMeta,
+		tasks.ConvertIssueCustomFieldsMeta,
 
 		tasks.ConvertWorklogsMeta,
 
@@ -138,6 +142,11 @@ func (plugin Jira) SubTaskMetas() []core.SubTaskMeta {
 
 		tasks.CollectEpicsMeta,
 		tasks.ExtractEpicsMeta,
+
+		tasks.CollectVersionsMeta,
+		tasks.ExtractVersionsMeta,
+		tasks.ConvertVersionsMeta,
+		tasks.ConvertIssueFixVersionsMeta,
 	}
 }
 
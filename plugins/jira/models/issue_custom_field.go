@@ -0,0 +1,40 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import (
+	"time"
+
+	"github.com/apache/incubator-devlake/models/common"
+)
+
+// JiraIssueCustomField stores the coerced value of a custom field mapped by the transformation rule
+type JiraIssueCustomField struct {
+	common.NoPKModel
+	ConnectionId uint64 `gorm:"primaryKey"`
+	IssueId      uint64 `gorm:"primaryKey"`
+	FieldId      string `gorm:"primaryKey;type:varchar(255)"`
+	FieldType    string `gorm:"type:varchar(50)"`
+	StringValue  string
+	NumberValue  *float64
+	DateValue    *time.Time
+}
+
+func (JiraIssueCustomField) TableName() string {
+	return "_tool_jira_issue_custom_fields"
+}
@@ -0,0 +1,50 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migrationscripts
+
+import (
+	"github.com/apache/incubator-devlake/errors"
+	"github.com/apache/incubator-devlake/models/migrationscripts/archived"
+	"github.com/apache/incubator-devlake/plugins/core"
+)
+
+type jiraIssueCustomFieldColumn20230126 struct {
+	archived.NoPKModel
+	ConnectionId uint64 `gorm:"primaryKey"`
+	BoardId      uint64 `gorm:"primaryKey"`
+	TargetColumn string `gorm:"primaryKey;type:varchar(255)"`
+	FieldId      string `gorm:"type:varchar(255)"`
+}
+
+func (jiraIssueCustomFieldColumn20230126) TableName() string {
+	return "_tool_jira_issue_custom_field_columns"
+}
+
+type addCustomFieldColumns20230126 struct{}
+
+func (script *addCustomFieldColumns20230126) Up(basicRes core.BasicRes) errors.Error {
+	return basicRes.GetDal().AutoMigrate(&jiraIssueCustomFieldColumn20230126{})
+}
+
+func (*addCustomFieldColumns20230126) Version() uint64 {
+	return 20230126101200
+}
+
+func (*addCustomFieldColumns20230126) Name() string {
+	return "add table _tool_jira_issue_custom_field_columns"
+}
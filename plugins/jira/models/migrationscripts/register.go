@@ -29,5 +29,8 @@ func All() []core.MigrationScript {
 		new(addInitTables20220716),
 		new(addTransformationRule20221116),
 		new(addProjectName20221215),
+		new(addVersions20230103),
+		new(addCustomFields20230105),
+		new(addCustomFieldColumns20230126),
 	}
 }
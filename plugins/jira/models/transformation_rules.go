@@ -30,6 +30,7 @@ type JiraTransformationRule struct {
 	StoryPointField            string          `mapstructure:"storyPointField,omitempty" json:"storyPointField" gorm:"type:varchar(255)"`
 	RemotelinkCommitShaPattern string          `mapstructure:"remotelinkCommitShaPattern,omitempty" json:"remotelinkCommitShaPattern" gorm:"type:varchar(255)"`
 	TypeMappings               json.RawMessage `mapstructure:"typeMappings,omitempty" json:"typeMappings"`
+	CustomFieldMappings        json.RawMessage `mapstructure:"customFieldMappings,omitempty" json:"customFieldMappings"`
 }
 
 func (JiraTransformationRule) TableName() string {
@@ -0,0 +1,36 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apiv2models
+
+// Field is an item of the response of `api/2/field`
+type Field struct {
+	ID     string       `json:"id"`
+	Key    string       `json:"key"`
+	Name   string       `json:"name"`
+	Custom bool         `json:"custom"`
+	Schema *FieldSchema `json:"schema"`
+}
+
+// FieldSchema describes the type of values of a Field
+type FieldSchema struct {
+	Type     string `json:"type"`
+	Items    string `json:"items"`
+	System   string `json:"system"`
+	Custom   string `json:"custom"`
+	CustomId uint64 `json:"customId"`
+}
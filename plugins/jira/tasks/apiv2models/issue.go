@@ -64,7 +64,7 @@ type Issue struct {
 				Three2X32 string `json:"32x32"`
 			} `json:"avatarUrls"`
 		} `json:"project"`
-		FixVersions        []interface{}       `json:"fixVersions"`
+		FixVersions        []Version           `json:"fixVersions"`
 		Aggregatetimespent interface{}         `json:"aggregatetimespent"`
 		Resolution         interface{}         `json:"resolution"`
 		Resolutiondate     *helper.Iso8601Time `json:"resolutiondate"`
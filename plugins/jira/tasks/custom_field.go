@@ -0,0 +1,225 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/apache/incubator-devlake/errors"
+	"github.com/apache/incubator-devlake/plugins/helper"
+	"github.com/apache/incubator-devlake/plugins/jira/models"
+	"github.com/apache/incubator-devlake/plugins/jira/tasks/apiv2models"
+)
+
+var targetColumnPattern = regexp.MustCompile(`^x_[a-zA-Z0-9_]+$`)
+
+// schema types of `api/2/field` accepted by each CustomFieldMapping.FieldType
+var customFieldSchemaTypes = map[string][]string{
+	CustomFieldTypeString:      {"string", "any"},
+	CustomFieldTypeNumber:      {"number"},
+	CustomFieldTypeDate:        {"date", "datetime"},
+	CustomFieldTypeOption:      {"option", "option-with-child", "string"},
+	CustomFieldTypeMultiSelect: {"array"},
+	CustomFieldTypeUser:        {"user"},
+}
+
+// GetFields fetches the metadata of all fields, including custom ones, from `api/2/field`
+func GetFields(apiClient helper.ApiClientGetter) ([]apiv2models.Field, errors.Error) {
+	res, err := apiClient.Get("api/2/field", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode != http.StatusOK {
+		return nil, errors.HttpStatus(res.StatusCode).New("unexpected status code when requesting api/2/field")
+	}
+	var fields []apiv2models.Field
+	err = helper.UnmarshalResponse(res, &fields)
+	if err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+// ValidateCustomFieldMappings checks mappings are unique, and if `fields` is not empty, that every field exists
+// and its schema is compatible with the declared FieldType
+func ValidateCustomFieldMappings(mappings []CustomFieldMapping, fields []apiv2models.Field) errors.Error {
+	fieldsById := make(map[string]*apiv2models.Field, len(fields))
+	for i := range fields {
+		fieldsById[fields[i].ID] = &fields[i]
+	}
+	seenFields := make(map[string]bool)
+	seenColumns := make(map[string]bool)
+	for _, mapping := range mappings {
+		if seenFields[mapping.FieldId] {
+			return errors.BadInput.New(fmt.Sprintf("field %s is mapped more than once", mapping.FieldId))
+		}
+		seenFields[mapping.FieldId] = true
+		acceptedSchemaTypes, ok := customFieldSchemaTypes[mapping.FieldType]
+		if !ok {
+			return errors.BadInput.New(fmt.Sprintf("unsupported fieldType %s of field %s", mapping.FieldType, mapping.FieldId))
+		}
+		if mapping.TargetColumn != "" {
+			if !targetColumnPattern.MatchString(mapping.TargetColumn) {
+				return errors.BadInput.New(fmt.Sprintf("targetColumn %s should start with `x_` and contain only letters, digits or `_`", mapping.TargetColumn))
+			}
+			if seenColumns[mapping.TargetColumn] {
+				return errors.BadInput.New(fmt.Sprintf("targetColumn %s is used more than once", mapping.TargetColumn))
+			}
+			seenColumns[mapping.TargetColumn] = true
+		}
+		if len(fields) == 0 {
+			continue
+		}
+		field, ok := fieldsById[mapping.FieldId]
+		if !ok {
+			return errors.BadInput.New(fmt.Sprintf("field %s does not exist", mapping.FieldId))
+		}
+		if field.Schema == nil {
+			continue
+		}
+		compatible := false
+		for _, schemaType := range acceptedSchemaTypes {
+			if field.Schema.Type == schemaType {
+				compatible = true
+				break
+			}
+		}
+		if !compatible {
+			return errors.BadInput.New(fmt.Sprintf(
+				"field %s (%s) is of type %s, which cannot be extracted as %s",
+				mapping.FieldId, field.Name, field.Schema.Type, mapping.FieldType,
+			))
+		}
+	}
+	return nil
+}
+
+// CoerceCustomField converts the raw value of a custom field into a JiraIssueCustomField according to fieldType,
+// nil is returned when the field is empty
+func CoerceCustomField(connectionId uint64, issueId uint64, mapping CustomFieldMapping, value interface{}) (*models.JiraIssueCustomField, errors.Error) {
+	if value == nil {
+		return nil, nil
+	}
+	result := &models.JiraIssueCustomField{
+		ConnectionId: connectionId,
+		IssueId:      issueId,
+		FieldId:      mapping.FieldId,
+		FieldType:    mapping.FieldType,
+	}
+	switch mapping.FieldType {
+	case CustomFieldTypeNumber:
+		var number float64
+		switch v := value.(type) {
+		case float64:
+			number = v
+		case string:
+			if v == "" {
+				return nil, nil
+			}
+			var err error
+			number, err = strconv.ParseFloat(v, 64)
+			if err != nil {
+				return nil, errors.BadInput.Wrap(err, fmt.Sprintf("value of field %s is not a number", mapping.FieldId))
+			}
+		default:
+			return nil, errors.BadInput.New(fmt.Sprintf("value of field %s is not a number", mapping.FieldId))
+		}
+		result.NumberValue = &number
+		result.StringValue = strconv.FormatFloat(number, 'f', -1, 64)
+	case CustomFieldTypeDate:
+		s, ok := value.(string)
+		if !ok {
+			return nil, errors.BadInput.New(fmt.Sprintf("value of field %s is not a date", mapping.FieldId))
+		}
+		if s == "" {
+			return nil, nil
+		}
+		date, err := helper.ConvertStringToTime(s)
+		if err != nil {
+			return nil, errors.BadInput.Wrap(err, fmt.Sprintf("value of field %s is not a date", mapping.FieldId))
+		}
+		result.DateValue = &date
+		result.StringValue = date.Format(time.RFC3339)
+	case CustomFieldTypeOption:
+		result.StringValue = optionValue(value)
+	case CustomFieldTypeMultiSelect:
+		items, ok := value.([]interface{})
+		if !ok {
+			return nil, errors.BadInput.New(fmt.Sprintf("value of field %s is not an array", mapping.FieldId))
+		}
+		if len(items) == 0 {
+			return nil, nil
+		}
+		values := make([]string, 0, len(items))
+		for _, item := range items {
+			values = append(values, optionValue(item))
+		}
+		result.StringValue = strings.Join(values, ",")
+	case CustomFieldTypeUser:
+		blob, err := json.Marshal(value)
+		if err != nil {
+			return nil, errors.Convert(err)
+		}
+		var account apiv2models.Account
+		err = json.Unmarshal(blob, &account)
+		if err != nil {
+			return nil, errors.BadInput.Wrap(err, fmt.Sprintf("value of field %s is not a user", mapping.FieldId))
+		}
+		result.StringValue = account.ToToolLayer(connectionId).AccountId
+	default:
+		switch v := value.(type) {
+		case string:
+			result.StringValue = v
+		case float64:
+			result.StringValue = strconv.FormatFloat(v, 'f', -1, 64)
+		case bool:
+			result.StringValue = strconv.FormatBool(v)
+		default:
+			blob, err := json.Marshal(v)
+			if err != nil {
+				return nil, errors.Convert(err)
+			}
+			result.StringValue = string(blob)
+		}
+	}
+	return result, nil
+}
+
+// optionValue returns the display value of a select option, or the name of other objects like components
+func optionValue(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case map[string]interface{}:
+		for _, key := range []string{"value", "name", "key", "id"} {
+			if s, ok := v[key].(string); ok {
+				if child, ok := v["child"].(map[string]interface{}); ok {
+					return s + "/" + optionValue(child)
+				}
+				return s
+			}
+		}
+	}
+	return fmt.Sprint(value)
+}
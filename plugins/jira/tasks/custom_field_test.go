@@ -0,0 +1,95 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"testing"
+
+	"github.com/apache/incubator-devlake/plugins/jira/models"
+	"github.com/apache/incubator-devlake/plugins/jira/tasks/apiv2models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateCustomFieldMappings(t *testing.T) {
+	fields := []apiv2models.Field{
+		{ID: "customfield_10024", Name: "Story point estimate", Schema: &apiv2models.FieldSchema{Type: "number"}},
+		{ID: "customfield_10020", Name: "Team"},
+	}
+	tests := []struct {
+		name     string
+		mappings []CustomFieldMapping
+		fields   []apiv2models.Field
+		wantErr  bool
+	}{
+		{"static", []CustomFieldMapping{{FieldId: "customfield_10024", FieldType: CustomFieldTypeNumber, TargetColumn: "x_story_point"}}, nil, false},
+		{"duplicated field", []CustomFieldMapping{{FieldId: "customfield_10024", FieldType: CustomFieldTypeNumber}, {FieldId: "customfield_10024", FieldType: CustomFieldTypeString}}, nil, true},
+		{"duplicated column", []CustomFieldMapping{{FieldId: "customfield_10024", FieldType: CustomFieldTypeNumber, TargetColumn: "x_a"}, {FieldId: "customfield_10020", FieldType: CustomFieldTypeString, TargetColumn: "x_a"}}, nil, true},
+		{"invalid column", []CustomFieldMapping{{FieldId: "customfield_10024", FieldType: CustomFieldTypeNumber, TargetColumn: "story_point; drop table issues"}}, nil, true},
+		{"unknown type", []CustomFieldMapping{{FieldId: "customfield_10024", FieldType: "currency"}}, nil, true},
+		{"remote ok", []CustomFieldMapping{{FieldId: "customfield_10024", FieldType: CustomFieldTypeNumber}}, fields, false},
+		{"remote missing field", []CustomFieldMapping{{FieldId: "customfield_99999", FieldType: CustomFieldTypeString}}, fields, true},
+		{"remote incompatible type", []CustomFieldMapping{{FieldId: "customfield_10024", FieldType: CustomFieldTypeDate}}, fields, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateCustomFieldMappings(tt.mappings, tt.fields)
+			assert.Equal(t, tt.wantErr, err != nil, err)
+		})
+	}
+}
+
+func TestCoerceCustomField(t *testing.T) {
+	number, err := CoerceCustomField(1, 10001, CustomFieldMapping{FieldId: "f", FieldType: CustomFieldTypeNumber}, 5.0)
+	assert.Nil(t, err)
+	assert.Equal(t, 5.0, *number.NumberValue)
+	assert.Equal(t, "5", number.StringValue)
+
+	date, err := CoerceCustomField(1, 10001, CustomFieldMapping{FieldId: "f", FieldType: CustomFieldTypeDate}, "2022-07-05")
+	assert.Nil(t, err)
+	assert.Equal(t, "2022-07-05T00:00:00Z", date.DateValue.UTC().Format("2006-01-02T15:04:05Z07:00"))
+
+	option, err := CoerceCustomField(1, 10001, CustomFieldMapping{FieldId: "f", FieldType: CustomFieldTypeOption},
+		map[string]interface{}{"value": "Backend", "child": map[string]interface{}{"value": "API"}})
+	assert.Nil(t, err)
+	assert.Equal(t, "Backend/API", option.StringValue)
+
+	multi, err := CoerceCustomField(1, 10001, CustomFieldMapping{FieldId: "f", FieldType: CustomFieldTypeMultiSelect},
+		[]interface{}{map[string]interface{}{"value": "a"}, map[string]interface{}{"value": "b"}})
+	assert.Nil(t, err)
+	assert.Equal(t, "a,b", multi.StringValue)
+
+	empty, err := CoerceCustomField(1, 10001, CustomFieldMapping{FieldId: "f", FieldType: CustomFieldTypeString}, nil)
+	assert.Nil(t, err)
+	assert.Nil(t, empty)
+
+	_, err = CoerceCustomField(1, 10001, CustomFieldMapping{FieldId: "f", FieldType: CustomFieldTypeNumber}, "abc")
+	assert.NotNil(t, err)
+}
+
+func TestCustomFieldColumnsToClear(t *testing.T) {
+	previous := []models.JiraIssueCustomFieldColumn{
+		{TargetColumn: "x_team"},
+		{TargetColumn: "x_removed"},
+		{TargetColumn: "x_dropped"},
+		{TargetColumn: "x_bad; DROP TABLE issues"},
+	}
+	targetColumns := map[string]string{"customfield_10024": "x_story_point", "customfield_10020": "x_team"}
+	existingColumns := map[string]bool{"x_team": true, "x_removed": true, "x_story_point": true, "x_bad; DROP TABLE issues": true}
+	assert.Equal(t, []string{"x_removed", "x_story_point", "x_team"}, customFieldColumnsToClear(previous, targetColumns, existingColumns))
+	assert.Empty(t, customFieldColumnsToClear(nil, nil, existingColumns))
+}
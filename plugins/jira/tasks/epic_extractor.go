@@ -54,7 +54,7 @@ func ExtractEpics(taskCtx core.SubTaskContext) errors.Error {
 			Table: RAW_EPIC_TABLE,
 		},
 		Extract: func(row *helper.RawData) ([]interface{}, errors.Error) {
-			return extractIssues(data, mappings, true, row)
+			return extractIssues(data, mappings, true, row, logger)
 		},
 	})
 	if err != nil {
@@ -0,0 +1,182 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/apache/incubator-devlake/errors"
+	"github.com/apache/incubator-devlake/models/domainlayer/didgen"
+	"github.com/apache/incubator-devlake/models/domainlayer/ticket"
+	"github.com/apache/incubator-devlake/plugins/core"
+	"github.com/apache/incubator-devlake/plugins/core/dal"
+	"github.com/apache/incubator-devlake/plugins/jira/models"
+)
+
+var ConvertIssueCustomFieldsMeta = core.SubTaskMeta{
+	Name:             "convertIssueCustomFields",
+	EntryPoint:       ConvertIssueCustomFields,
+	EnabledByDefault: true,
+	Description:      "copy Jira custom fields into the target columns of domain layer table issues",
+	DomainTypes:      []string{core.DOMAIN_TYPE_TICKET},
+}
+
+func ConvertIssueCustomFields(taskCtx core.SubTaskContext) errors.Error {
+	data := taskCtx.GetData().(*JiraTaskData)
+	var mappings []CustomFieldMapping
+	if data.Options.TransformationRules != nil {
+		mappings = data.Options.TransformationRules.CustomFieldMappings
+	}
+	err := ValidateCustomFieldMappings(mappings, nil)
+	if err != nil {
+		return err
+	}
+	targetColumns := make(map[string]string)
+	for _, mapping := range mappings {
+		if mapping.TargetColumn != "" {
+			targetColumns[mapping.FieldId] = mapping.TargetColumn
+		}
+	}
+	err = clearCustomFieldColumns(taskCtx, targetColumns)
+	if err != nil {
+		return err
+	}
+	if len(targetColumns) == 0 {
+		return nil
+	}
+	fieldIds := make([]string, 0, len(targetColumns))
+	for fieldId := range targetColumns {
+		fieldIds = append(fieldIds, fieldId)
+	}
+
+	db := taskCtx.GetDal()
+	ctx := taskCtx.GetContext()
+	logger := taskCtx.GetLogger()
+	logger.Info("convert custom fields %v", fieldIds)
+	cursor, err := db.Cursor(
+		dal.Select("cf.*"),
+		dal.From("_tool_jira_issue_custom_fields cf"),
+		dal.Join(`LEFT JOIN _tool_jira_board_issues bi
+              ON cf.connection_id = bi.connection_id AND cf.issue_id = bi.issue_id`),
+		dal.Where("cf.connection_id = ? AND bi.board_id = ? AND cf.field_id IN ?", data.Options.ConnectionId, data.Options.BoardId, fieldIds),
+	)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close()
+
+	issueIdGen := didgen.NewDomainIdGenerator(&models.JiraIssue{})
+	taskCtx.SetProgress(0, -1)
+	for cursor.Next() {
+		select {
+		case <-ctx.Done():
+			return errors.Convert(ctx.Err())
+		default:
+		}
+		customField := &models.JiraIssueCustomField{}
+		err = db.Fetch(cursor, customField)
+		if err != nil {
+			return err
+		}
+		// targetColumn was validated against `^x_[a-zA-Z0-9_]+$` so it is safe to be formatted into the statement
+		err = db.Exec(
+			fmt.Sprintf("UPDATE issues SET %s = ? WHERE id = ?", targetColumns[customField.FieldId]),
+			customField.StringValue,
+			issueIdGen.Generate(customField.ConnectionId, customField.IssueId),
+		)
+		if err != nil {
+			return errors.Default.Wrap(err, fmt.Sprintf("failed to update column %s of issues", targetColumns[customField.FieldId]))
+		}
+		taskCtx.IncProgress(1)
+	}
+	return nil
+}
+
+// clearCustomFieldColumns empties the `x_` columns of the board's issues written by the last run and the current
+// mappings, so neither the values of removed mappings nor the ones of emptied fields are left behind
+func clearCustomFieldColumns(taskCtx core.SubTaskContext, targetColumns map[string]string) errors.Error {
+	data := taskCtx.GetData().(*JiraTaskData)
+	db := taskCtx.GetDal()
+	var previous []models.JiraIssueCustomFieldColumn
+	err := db.All(&previous, dal.Where("connection_id = ? AND board_id = ?", data.Options.ConnectionId, data.Options.BoardId))
+	if err != nil {
+		return err
+	}
+	columns, err := db.GetColumns(&ticket.Issue{}, func(columnMeta dal.ColumnMeta) bool {
+		return strings.HasPrefix(columnMeta.Name(), "x_")
+	})
+	if err != nil {
+		return err
+	}
+	existingColumns := make(map[string]bool, len(columns))
+	for _, column := range columns {
+		existingColumns[column.Name()] = true
+	}
+	boardId := didgen.NewDomainIdGenerator(&models.JiraBoard{}).Generate(data.Options.ConnectionId, data.Options.BoardId)
+	for _, column := range customFieldColumnsToClear(previous, targetColumns, existingColumns) {
+		err = db.Exec(
+			fmt.Sprintf("UPDATE issues SET %s = NULL WHERE id IN (SELECT issue_id FROM board_issues WHERE board_id = ?)", column),
+			boardId,
+		)
+		if err != nil {
+			return errors.Default.Wrap(err, fmt.Sprintf("failed to clear column %s of issues", column))
+		}
+	}
+
+	err = db.Delete(&models.JiraIssueCustomFieldColumn{}, dal.Where("connection_id = ? AND board_id = ?", data.Options.ConnectionId, data.Options.BoardId))
+	if err != nil {
+		return err
+	}
+	current := make([]*models.JiraIssueCustomFieldColumn, 0, len(targetColumns))
+	for fieldId, column := range targetColumns {
+		current = append(current, &models.JiraIssueCustomFieldColumn{
+			ConnectionId: data.Options.ConnectionId,
+			BoardId:      data.Options.BoardId,
+			TargetColumn: column,
+			FieldId:      fieldId,
+		})
+	}
+	if len(current) == 0 {
+		return nil
+	}
+	return db.CreateOrUpdate(current)
+}
+
+// customFieldColumnsToClear returns the previous and current target columns which still exist in `issues`
+func customFieldColumnsToClear(previous []models.JiraIssueCustomFieldColumn, targetColumns map[string]string, existingColumns map[string]bool) []string {
+	seen := make(map[string]bool)
+	var columns []string
+	add := func(column string) {
+		// columns read back from the database are checked again before being formatted into the statement
+		if seen[column] || !existingColumns[column] || !targetColumnPattern.MatchString(column) {
+			return
+		}
+		seen[column] = true
+		columns = append(columns, column)
+	}
+	for _, column := range previous {
+		add(column.TargetColumn)
+	}
+	for _, column := range targetColumns {
+		add(column)
+	}
+	sort.Strings(columns)
+	return columns
+}
@@ -77,7 +77,7 @@ func ExtractIssues(taskCtx core.SubTaskContext) errors.Error {
 			Table: RAW_ISSUE_TABLE,
 		},
 		Extract: func(row *helper.RawData) ([]interface{}, errors.Error) {
-			return extractIssues(data, mappings, false, row)
+			return extractIssues(data, mappings, false, row, logger)
 		},
 	})
 	if err != nil {
@@ -86,7 +86,7 @@ func ExtractIssues(taskCtx core.SubTaskContext) errors.Error {
 	return extractor.Execute()
 }
 
-func extractIssues(data *JiraTaskData, mappings *typeMappings, ignoreBoard bool, row *helper.RawData) ([]interface{}, errors.Error) {
+func extractIssues(data *JiraTaskData, mappings *typeMappings, ignoreBoard bool, row *helper.RawData, logger core.Logger) ([]interface{}, errors.Error) {
 	var apiIssue apiv2models.Issue
 	err := errors.Convert(json.Unmarshal(row.Data, &apiIssue))
 	if err != nil {
@@ -174,6 +174,26 @@ func extractIssues(data *JiraTaskData, mappings *typeMappings, ignoreBoard bool,
 		}
 		results = append(results, issueLabel)
 	}
+	if data.Options.TransformationRules != nil {
+		for _, mapping := range data.Options.TransformationRules.CustomFieldMappings {
+			customField, err := CoerceCustomField(data.Options.ConnectionId, issue.IssueId, mapping, apiIssue.Fields.AllFields[mapping.FieldId])
+			if err != nil {
+				// a malformed value shouldn't stop the other fields and issues from being extracted
+				logger.Warn(err, "skip custom field %s of issue %s", mapping.FieldId, issue.IssueKey)
+				continue
+			}
+			if customField != nil {
+				results = append(results, customField)
+			}
+		}
+	}
+	for _, fixVersion := range apiIssue.Fields.FixVersions {
+		results = append(results, &models.JiraIssueFixVersion{
+			ConnectionId: data.Options.ConnectionId,
+			IssueId:      issue.IssueId,
+			VersionId:    fixVersion.ID,
+		})
+	}
 	return results, nil
 }
 
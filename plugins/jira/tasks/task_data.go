@@ -40,12 +40,30 @@ type TypeMapping struct {
 
 type TypeMappings map[string]TypeMapping
 
+const (
+	CustomFieldTypeString      = "string"
+	CustomFieldTypeNumber      = "number"
+	CustomFieldTypeDate        = "date"
+	CustomFieldTypeOption      = "option"
+	CustomFieldTypeMultiSelect = "multiSelect"
+	CustomFieldTypeUser        = "user"
+)
+
+// CustomFieldMapping extracts a Jira custom field into the `x_` column TargetColumn of the domain `issues` table,
+// or only into the key/value table `_tool_jira_issue_custom_fields` when TargetColumn is empty
+type CustomFieldMapping struct {
+	FieldId      string `json:"fieldId" validate:"required"`
+	FieldType    string `json:"fieldType" validate:"required,oneof=string number date option multiSelect user"`
+	TargetColumn string `json:"targetColumn"`
+}
+
 type JiraTransformationRule struct {
-	Name                       string       `gorm:"type:varchar(255)" validate:"required"`
-	EpicKeyField               string       `json:"epicKeyField"`
-	StoryPointField            string       `json:"storyPointField"`
-	RemotelinkCommitShaPattern string       `json:"remotelinkCommitShaPattern"`
-	TypeMappings               TypeMappings `json:"typeMappings"`
+	Name                       string               `gorm:"type:varchar(255)" validate:"required"`
+	EpicKeyField               string               `json:"epicKeyField"`
+	StoryPointField            string               `json:"storyPointField"`
+	RemotelinkCommitShaPattern string               `json:"remotelinkCommitShaPattern"`
+	TypeMappings               TypeMappings         `json:"typeMappings"`
+	CustomFieldMappings        []CustomFieldMapping `json:"customFieldMappings" validate:"dive"`
 }
 
 func (r *JiraTransformationRule) ToDb() (rule *models.JiraTransformationRule, error2 errors.Error) {
@@ -53,12 +71,17 @@ func (r *JiraTransformationRule) ToDb() (rule *models.JiraTransformationRule, er
 	if err != nil {
 		return nil, errors.Default.Wrap(err, "error marshaling TypeMappings")
 	}
+	customFieldMappings, err := json.Marshal(r.CustomFieldMappings)
+	if err != nil {
+		return nil, errors.Default.Wrap(err, "error marshaling CustomFieldMappings")
+	}
 	return &models.JiraTransformationRule{
 		Name:                       r.Name,
 		EpicKeyField:               r.EpicKeyField,
 		StoryPointField:            r.StoryPointField,
 		RemotelinkCommitShaPattern: r.RemotelinkCommitShaPattern,
 		TypeMappings:               blob,
+		CustomFieldMappings:        customFieldMappings,
 	}, nil
 }
 func (r *JiraTransformationRule) FromDb(rule *models.JiraTransformationRule) (*JiraTransformationRule, errors.Error) {
@@ -67,11 +90,19 @@ func (r *JiraTransformationRule) FromDb(rule *models.JiraTransformationRule) (*J
 	if err != nil {
 		return nil, errors.Default.Wrap(err, "error marshaling TypeMappings")
 	}
+	var customFieldMappings []CustomFieldMapping
+	if len(rule.CustomFieldMappings) > 0 {
+		err = json.Unmarshal(rule.CustomFieldMappings, &customFieldMappings)
+		if err != nil {
+			return nil, errors.Default.Wrap(err, "error unmarshaling CustomFieldMappings")
+		}
+	}
 	r.Name = rule.Name
 	r.EpicKeyField = rule.EpicKeyField
 	r.StoryPointField = rule.StoryPointField
 	r.RemotelinkCommitShaPattern = rule.RemotelinkCommitShaPattern
 	r.TypeMappings = mappings
+	r.CustomFieldMappings = customFieldMappings
 	return r, nil
 }
 
@@ -81,12 +112,20 @@ func MakeTransformationRules(rule models.JiraTransformationRule) (*JiraTransform
 	if err != nil {
 		return nil, errors.Default.Wrap(err, "unable to unmarshal the typeMapping")
 	}
+	var customFieldMappings []CustomFieldMapping
+	if len(rule.CustomFieldMappings) > 0 {
+		err = json.Unmarshal(rule.CustomFieldMappings, &customFieldMappings)
+		if err != nil {
+			return nil, errors.Default.Wrap(err, "unable to unmarshal the customFieldMappings")
+		}
+	}
 	result := &JiraTransformationRule{
 		Name:                       rule.Name,
 		EpicKeyField:               rule.EpicKeyField,
 		StoryPointField:            rule.StoryPointField,
 		RemotelinkCommitShaPattern: rule.RemotelinkCommitShaPattern,
 		TypeMappings:               typeMapping,
+		CustomFieldMappings:        customFieldMappings,
 	}
 	return result, nil
 }
@@ -0,0 +1,92 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+
+	"github.com/apache/incubator-devlake/errors"
+	"github.com/apache/incubator-devlake/plugins/core"
+	"github.com/apache/incubator-devlake/plugins/core/dal"
+	"github.com/apache/incubator-devlake/plugins/helper"
+)
+
+const RAW_VERSION_TABLE = "jira_api_versions"
+
+var _ core.SubTaskEntryPoint = CollectVersions
+
+var CollectVersionsMeta = core.SubTaskMeta{
+	Name:             "collectVersions",
+	EntryPoint:       CollectVersions,
+	EnabledByDefault: true,
+	Description:      "collect Jira project versions",
+	DomainTypes:      []string{core.DOMAIN_TYPE_TICKET},
+}
+
+type projectInput struct {
+	ProjectId uint64
+}
+
+func CollectVersions(taskCtx core.SubTaskContext) errors.Error {
+	data := taskCtx.GetData().(*JiraTaskData)
+	db := taskCtx.GetDal()
+	logger := taskCtx.GetLogger()
+	logger.Info("collect versions")
+
+	// versions belong to projects, so we collect them for every project the board has issues in
+	cursor, err := db.Cursor(
+		dal.Select("DISTINCT i.project_id"),
+		dal.From("_tool_jira_board_issues bi"),
+		dal.Join("LEFT JOIN _tool_jira_issues i ON (bi.connection_id = i.connection_id AND bi.issue_id = i.issue_id)"),
+		dal.Where("bi.connection_id = ? AND bi.board_id = ? AND i.project_id > 0", data.Options.ConnectionId, data.Options.BoardId),
+	)
+	if err != nil {
+		return err
+	}
+	iterator, err := helper.NewDalCursorIterator(db, cursor, reflect.TypeOf(projectInput{}))
+	if err != nil {
+		return err
+	}
+
+	collector, err := helper.NewApiCollector(helper.ApiCollectorArgs{
+		RawDataSubTaskArgs: helper.RawDataSubTaskArgs{
+			Ctx: taskCtx,
+			Params: JiraApiParams{
+				ConnectionId: data.Options.ConnectionId,
+				BoardId:      data.Options.BoardId,
+			},
+			Table: RAW_VERSION_TABLE,
+		},
+		ApiClient:   data.ApiClient,
+		Input:       iterator,
+		UrlTemplate: "api/2/project/{{ .Input.ProjectId }}/versions",
+		ResponseParser: func(res *http.Response) ([]json.RawMessage, errors.Error) {
+			var result []json.RawMessage
+			err := helper.UnmarshalResponse(res, &result)
+			return result, err
+		},
+		AfterResponse: ignoreHTTPStatus404,
+	})
+	if err != nil {
+		logger.Error(err, "collect version error")
+		return err
+	}
+	return collector.Execute()
+}
@@ -0,0 +1,168 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"reflect"
+	"strconv"
+
+	"github.com/apache/incubator-devlake/errors"
+	"github.com/apache/incubator-devlake/models/domainlayer"
+	"github.com/apache/incubator-devlake/models/domainlayer/didgen"
+	"github.com/apache/incubator-devlake/models/domainlayer/ticket"
+	"github.com/apache/incubator-devlake/plugins/core"
+	"github.com/apache/incubator-devlake/plugins/core/dal"
+	"github.com/apache/incubator-devlake/plugins/helper"
+	"github.com/apache/incubator-devlake/plugins/jira/models"
+)
+
+var ConvertVersionsMeta = core.SubTaskMeta{
+	Name:             "convertVersions",
+	EntryPoint:       ConvertVersions,
+	EnabledByDefault: true,
+	Description:      "convert Jira versions",
+	DomainTypes:      []string{core.DOMAIN_TYPE_TICKET},
+}
+
+var ConvertIssueFixVersionsMeta = core.SubTaskMeta{
+	Name:             "convertIssueFixVersions",
+	EntryPoint:       ConvertIssueFixVersions,
+	EnabledByDefault: true,
+	Description:      "convert Jira issue fixVersions",
+	DomainTypes:      []string{core.DOMAIN_TYPE_TICKET},
+}
+
+func ConvertVersions(taskCtx core.SubTaskContext) errors.Error {
+	data := taskCtx.GetData().(*JiraTaskData)
+	connectionId := data.Options.ConnectionId
+	boardId := data.Options.BoardId
+	db := taskCtx.GetDal()
+	logger := taskCtx.GetLogger()
+	logger.Info("convert versions")
+	var projects []models.JiraProject
+	err := db.All(&projects, dal.Where("connection_id = ?", connectionId))
+	if err != nil {
+		return err
+	}
+	projectNames := make(map[string]string, len(projects))
+	for _, project := range projects {
+		projectNames[project.Id] = project.Name
+	}
+	cursor, err := db.Cursor(
+		dal.Select("v.*"),
+		dal.From("_tool_jira_versions v"),
+		dal.Where(`v.connection_id = ? AND v.project_id IN (
+				SELECT i.project_id FROM _tool_jira_board_issues bi
+				LEFT JOIN _tool_jira_issues i ON (bi.connection_id = i.connection_id AND bi.issue_id = i.issue_id)
+				WHERE bi.connection_id = ? AND bi.board_id = ?
+			)`, connectionId, connectionId, boardId),
+	)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close()
+
+	versionIdGen := didgen.NewDomainIdGenerator(&models.JiraVersion{})
+	converter, err := helper.NewDataConverter(helper.DataConverterArgs{
+		RawDataSubTaskArgs: helper.RawDataSubTaskArgs{
+			Ctx: taskCtx,
+			Params: JiraApiParams{
+				ConnectionId: connectionId,
+				BoardId:      boardId,
+			},
+			Table: RAW_VERSION_TABLE,
+		},
+		InputRowType: reflect.TypeOf(models.JiraVersion{}),
+		Input:        cursor,
+		Convert: func(inputRow interface{}) ([]interface{}, errors.Error) {
+			jiraVersion := inputRow.(*models.JiraVersion)
+			version := &ticket.Version{
+				DomainEntity:    domainlayer.DomainEntity{Id: versionIdGen.Generate(connectionId, jiraVersion.VersionId)},
+				Name:            jiraVersion.Name,
+				Description:     jiraVersion.Description,
+				Url:             jiraVersion.Self,
+				Status:          getVersionStatus(jiraVersion),
+				StartedDate:     jiraVersion.StartDate,
+				ReleasedDate:    jiraVersion.ReleaseDate,
+				OriginalProject: projectNames[strconv.FormatUint(jiraVersion.ProjectId, 10)],
+			}
+			return []interface{}{version}, nil
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	return converter.Execute()
+}
+
+func ConvertIssueFixVersions(taskCtx core.SubTaskContext) errors.Error {
+	data := taskCtx.GetData().(*JiraTaskData)
+	connectionId := data.Options.ConnectionId
+	db := taskCtx.GetDal()
+	cursor, err := db.Cursor(
+		dal.Select("fv.*"),
+		dal.From("_tool_jira_issue_fix_versions fv"),
+		dal.Join(`LEFT JOIN _tool_jira_board_issues bi
+              ON fv.connection_id = bi.connection_id AND fv.issue_id = bi.issue_id`),
+		dal.Where("fv.connection_id = ? AND bi.board_id = ?", connectionId, data.Options.BoardId),
+	)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close()
+
+	issueIdGen := didgen.NewDomainIdGenerator(&models.JiraIssue{})
+	versionIdGen := didgen.NewDomainIdGenerator(&models.JiraVersion{})
+	converter, err := helper.NewDataConverter(helper.DataConverterArgs{
+		RawDataSubTaskArgs: helper.RawDataSubTaskArgs{
+			Ctx: taskCtx,
+			Params: JiraApiParams{
+				ConnectionId: connectionId,
+				BoardId:      data.Options.BoardId,
+			},
+			Table: RAW_ISSUE_TABLE,
+		},
+		InputRowType: reflect.TypeOf(models.JiraIssueFixVersion{}),
+		Input:        cursor,
+		Convert: func(inputRow interface{}) ([]interface{}, errors.Error) {
+			fixVersion := inputRow.(*models.JiraIssueFixVersion)
+			return []interface{}{
+				&ticket.IssueFixVersion{
+					IssueId:   issueIdGen.Generate(connectionId, fixVersion.IssueId),
+					VersionId: versionIdGen.Generate(connectionId, fixVersion.VersionId),
+				},
+			}, nil
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	return converter.Execute()
+}
+
+func getVersionStatus(version *models.JiraVersion) string {
+	if version.Archived {
+		return ticket.VersionArchived
+	}
+	if version.Released {
+		return ticket.VersionReleased
+	}
+	return ticket.VersionUnreleased
+}
@@ -0,0 +1,99 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"fmt"
+
+	"github.com/apache/incubator-devlake/errors"
+	"github.com/apache/incubator-devlake/models/domainlayer"
+	"github.com/apache/incubator-devlake/models/domainlayer/didgen"
+	"github.com/apache/incubator-devlake/models/domainlayer/ticket"
+	"github.com/apache/incubator-devlake/plugins/core"
+	"github.com/apache/incubator-devlake/plugins/core/dal"
+	"github.com/apache/incubator-devlake/plugins/helper"
+	"github.com/apache/incubator-devlake/plugins/pagerduty/models"
+	"github.com/apache/incubator-devlake/plugins/pagerduty/tasks"
+	"github.com/apache/incubator-devlake/utils"
+)
+
+// MakeDataSourcePipelinePlanV200 generates a single pagerduty task for all the selected services of the connection,
+// since the tap collects the incidents of the whole account at once
+func MakeDataSourcePipelinePlanV200(subtaskMetas []core.SubTaskMeta, connectionId uint64, bpScopes []*core.BlueprintScopeV200, syncPolicy *core.BlueprintSyncPolicy) (core.PipelinePlan, []core.Scope, errors.Error) {
+	connection := &models.PagerDutyConnection{}
+	err := connectionHelper.FirstById(connection, connectionId)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(bpScopes) == 0 {
+		return core.PipelinePlan{}, []core.Scope{}, nil
+	}
+	// the tap requires a date to start the collection from
+	if syncPolicy == nil || syncPolicy.CreatedDateAfter == nil {
+		return nil, nil, errors.BadInput.New("createdDateAfter of the sync policy is required to collect PagerDuty incidents")
+	}
+	db := basicRes.GetDal()
+	idGen := didgen.NewDomainIdGenerator(&models.Service{})
+	op := &tasks.PagerDutyOptions{
+		ConnectionId: connection.ID,
+	}
+	var entities []string
+	scopes := make([]core.Scope, 0, len(bpScopes))
+	for i, bpScope := range bpScopes {
+		service := &models.Service{}
+		err = db.First(service, dal.Where(`connection_id = ? AND id = ?`, connection.ID, bpScope.Id))
+		if err != nil {
+			return nil, nil, errors.Default.Wrap(err, fmt.Sprintf("fail to find service %s", bpScope.Id))
+		}
+		// all the services share one task, so they have to share the transformation rule as well
+		if i > 0 && service.TransformationRuleId != op.TransformationRuleId {
+			return nil, nil, errors.BadInput.New(fmt.Sprintf("service %s uses a different transformation rule from other services of the connection", service.Id))
+		}
+		op.TransformationRuleId = service.TransformationRuleId
+		op.ServiceIds = append(op.ServiceIds, service.Id)
+		entities = utils.StringsUniq(append(entities, bpScope.Entities...))
+		scopes = append(scopes, &ticket.Board{
+			DomainEntity: domainlayer.DomainEntity{
+				Id: idGen.Generate(connection.ID, service.Id),
+			},
+			Name:        service.Name,
+			Description: service.Description,
+			Url:         service.Url,
+			CreatedDate: service.CreatedDate,
+		})
+	}
+	options, err := tasks.EncodeTaskOptions(op)
+	if err != nil {
+		return nil, nil, err
+	}
+	options["start_date"] = syncPolicy.CreatedDateAfter.UTC().Format("2006-01-02T15:04:05Z")
+	subtasks, err := helper.MakePipelinePlanSubtasks(subtaskMetas, entities)
+	if err != nil {
+		return nil, nil, err
+	}
+	plan := core.PipelinePlan{
+		{
+			{
+				Plugin:   "pagerduty",
+				Subtasks: subtasks,
+				Options:  options,
+			},
+		},
+	}
+	return plan, scopes, nil
+}
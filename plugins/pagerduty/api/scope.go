@@ -0,0 +1,221 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/apache/incubator-devlake/errors"
+	"github.com/apache/incubator-devlake/plugins/core"
+	"github.com/apache/incubator-devlake/plugins/core/dal"
+	"github.com/apache/incubator-devlake/plugins/helper"
+	"github.com/apache/incubator-devlake/plugins/pagerduty/models"
+	"github.com/mitchellh/mapstructure"
+)
+
+type apiService struct {
+	models.Service
+	TransformationRuleName string `json:"transformationRuleName,omitempty"`
+}
+
+type req struct {
+	Data []*models.Service `json:"data"`
+}
+
+// PutScope create or update pagerduty services
+// @Summary create or update pagerduty services
+// @Description Create or update pagerduty services
+// @Tags plugins/pagerduty
+// @Accept application/json
+// @Param connectionId path int true "connection ID"
+// @Param scope body req true "json"
+// @Success 200  {object} []models.Service
+// @Failure 400  {object} shared.ApiBody "Bad Request"
+// @Failure 500  {object} shared.ApiBody "Internal Error"
+// @Router /plugins/pagerduty/connections/{connectionId}/scopes [PUT]
+func PutScope(input *core.ApiResourceInput) (*core.ApiResourceOutput, errors.Error) {
+	connectionId, _ := extractParam(input.Params)
+	if connectionId == 0 {
+		return nil, errors.BadInput.New("invalid connectionId")
+	}
+	var services req
+	err := errors.Convert(mapstructure.Decode(input.Body, &services))
+	if err != nil {
+		return nil, errors.BadInput.Wrap(err, "decoding PagerDuty service error")
+	}
+	keeper := make(map[string]struct{})
+	now := time.Now()
+	for _, service := range services.Data {
+		if _, ok := keeper[service.Id]; ok {
+			return nil, errors.BadInput.New("duplicated item")
+		} else {
+			keeper[service.Id] = struct{}{}
+		}
+		service.ConnectionId = connectionId
+		if service.CreatedDate == nil {
+			service.CreatedDate = &now
+		}
+		err = verifyService(service)
+		if err != nil {
+			return nil, err
+		}
+	}
+	err = basicRes.GetDal().CreateOrUpdate(services.Data)
+	if err != nil {
+		return nil, errors.Default.Wrap(err, "error on saving PagerDuty service")
+	}
+	return &core.ApiResourceOutput{Body: services.Data, Status: http.StatusOK}, nil
+}
+
+// UpdateScope patch to pagerduty service
+// @Summary patch to pagerduty service
+// @Description patch to pagerduty service
+// @Tags plugins/pagerduty
+// @Accept application/json
+// @Param connectionId path int true "connection ID"
+// @Param serviceId path string true "service ID"
+// @Param scope body models.Service true "json"
+// @Success 200  {object} models.Service
+// @Failure 400  {object} shared.ApiBody "Bad Request"
+// @Failure 500  {object} shared.ApiBody "Internal Error"
+// @Router /plugins/pagerduty/connections/{connectionId}/scopes/{serviceId} [PATCH]
+func UpdateScope(input *core.ApiResourceInput) (*core.ApiResourceOutput, errors.Error) {
+	connectionId, serviceId := extractParam(input.Params)
+	if connectionId == 0 || serviceId == "" {
+		return nil, errors.BadInput.New("invalid connectionId or serviceId")
+	}
+	var service models.Service
+	err := basicRes.GetDal().First(&service, dal.Where("connection_id = ? AND id = ?", connectionId, serviceId))
+	if err != nil {
+		return nil, errors.Default.Wrap(err, "getting PagerDuty service error")
+	}
+	err = helper.DecodeMapStruct(input.Body, &service)
+	if err != nil {
+		return nil, errors.Default.Wrap(err, "patch pagerduty service error")
+	}
+	// the primary key should not be changed
+	service.ConnectionId = connectionId
+	service.Id = serviceId
+	err = verifyService(&service)
+	if err != nil {
+		return nil, err
+	}
+	err = basicRes.GetDal().Update(service)
+	if err != nil {
+		return nil, errors.Default.Wrap(err, "error on saving PagerDuty service")
+	}
+	return &core.ApiResourceOutput{Body: service, Status: http.StatusOK}, nil
+}
+
+// GetScopeList get PagerDuty services
+// @Summary get PagerDuty services
+// @Description get PagerDuty services
+// @Tags plugins/pagerduty
+// @Param connectionId path int true "connection ID"
+// @Param pageSize query int false "page size, default 50"
+// @Param page query int false "page size, default 1"
+// @Success 200  {object} []apiService
+// @Failure 400  {object} shared.ApiBody "Bad Request"
+// @Failure 500  {object} shared.ApiBody "Internal Error"
+// @Router /plugins/pagerduty/connections/{connectionId}/scopes/ [GET]
+func GetScopeList(input *core.ApiResourceInput) (*core.ApiResourceOutput, errors.Error) {
+	var services []models.Service
+	connectionId, _ := extractParam(input.Params)
+	if connectionId == 0 {
+		return nil, errors.BadInput.New("invalid path params")
+	}
+	limit, offset := helper.GetLimitOffset(input.Query, "pageSize", "page")
+	err := basicRes.GetDal().All(&services, dal.Where("connection_id = ?", connectionId), dal.Limit(limit), dal.Offset(offset))
+	if err != nil {
+		return nil, err
+	}
+	var ruleIds []uint64
+	for _, service := range services {
+		if service.TransformationRuleId > 0 {
+			ruleIds = append(ruleIds, service.TransformationRuleId)
+		}
+	}
+	var rules []models.PagerDutyTransformationRule
+	if len(ruleIds) > 0 {
+		err = basicRes.GetDal().All(&rules, dal.Where("id IN (?)", ruleIds))
+		if err != nil {
+			return nil, err
+		}
+	}
+	names := make(map[uint64]string)
+	for _, rule := range rules {
+		names[rule.ID] = rule.Name
+	}
+	var apiServices []apiService
+	for _, service := range services {
+		apiServices = append(apiServices, apiService{service, names[service.TransformationRuleId]})
+	}
+	return &core.ApiResourceOutput{Body: apiServices, Status: http.StatusOK}, nil
+}
+
+// GetScope get one PagerDuty service
+// @Summary get one PagerDuty service
+// @Description get one PagerDuty service
+// @Tags plugins/pagerduty
+// @Param connectionId path int true "connection ID"
+// @Param serviceId path string true "service ID"
+// @Success 200  {object} apiService
+// @Failure 400  {object} shared.ApiBody "Bad Request"
+// @Failure 500  {object} shared.ApiBody "Internal Error"
+// @Router /plugins/pagerduty/connections/{connectionId}/scopes/{serviceId} [GET]
+func GetScope(input *core.ApiResourceInput) (*core.ApiResourceOutput, errors.Error) {
+	var service models.Service
+	connectionId, serviceId := extractParam(input.Params)
+	if connectionId == 0 || serviceId == "" {
+		return nil, errors.BadInput.New("invalid path params")
+	}
+	db := basicRes.GetDal()
+	err := db.First(&service, dal.Where("connection_id = ? AND id = ?", connectionId, serviceId))
+	if db.IsErrorNotFound(err) {
+		return nil, errors.NotFound.New("record not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+	var rule models.PagerDutyTransformationRule
+	if service.TransformationRuleId > 0 {
+		err = basicRes.GetDal().First(&rule, dal.Where("id = ?", service.TransformationRuleId))
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &core.ApiResourceOutput{Body: apiService{service, rule.Name}, Status: http.StatusOK}, nil
+}
+
+func extractParam(params map[string]string) (uint64, string) {
+	connectionId, _ := strconv.ParseUint(params["connectionId"], 10, 64)
+	serviceId := params["serviceId"]
+	return connectionId, serviceId
+}
+
+func verifyService(service *models.Service) errors.Error {
+	if service.ConnectionId == 0 {
+		return errors.BadInput.New("invalid connectionId")
+	}
+	if service.Id == "" {
+		return errors.BadInput.New("invalid service id")
+	}
+	return nil
+}
@@ -45,6 +45,8 @@ func TestIncidentDataFlow(t *testing.T) {
 	dataflowTester.FlushTabler(&models.User{})
 	dataflowTester.FlushTabler(&models.Service{})
 	dataflowTester.FlushTabler(&models.Assignment{})
+	dataflowTester.FlushTabler(&models.EscalationPolicy{})
+	dataflowTester.FlushTabler(&models.LogEntry{})
 	dataflowTester.Subtask(tasks.ExtractIncidentsMeta, taskData)
 	dataflowTester.VerifyTableWithOptions(
 		models.Incident{},
@@ -74,7 +76,22 @@ func TestIncidentDataFlow(t *testing.T) {
 			IgnoreTypes: []any{common.Model{}},
 		},
 	)
+	dataflowTester.VerifyTableWithOptions(
+		models.EscalationPolicy{},
+		e2ehelper.TableOptions{
+			CSVRelPath:  "./snapshot_tables/_tool_pagerduty_escalation_policies.csv",
+			IgnoreTypes: []any{common.Model{}},
+		},
+	)
+	dataflowTester.VerifyTableWithOptions(
+		models.LogEntry{},
+		e2ehelper.TableOptions{
+			CSVRelPath:  "./snapshot_tables/_tool_pagerduty_log_entries.csv",
+			IgnoreTypes: []any{common.Model{}},
+		},
+	)
 	dataflowTester.FlushTabler(&ticket.Issue{})
+	dataflowTester.FlushTabler(&ticket.BoardIssue{})
 	dataflowTester.Subtask(tasks.ConvertIncidentsMeta, taskData)
 	dataflowTester.VerifyTableWithOptions(
 		ticket.Issue{},
@@ -84,4 +101,11 @@ func TestIncidentDataFlow(t *testing.T) {
 			IgnoreFields: []string{"original_project"},
 		},
 	)
+	dataflowTester.VerifyTableWithOptions(
+		ticket.BoardIssue{},
+		e2ehelper.TableOptions{
+			CSVRelPath:  "./snapshot_tables/board_issues.csv",
+			IgnoreTypes: []any{common.NoPKModel{}},
+		},
+	)
 }
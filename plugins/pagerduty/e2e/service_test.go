@@ -0,0 +1,114 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package e2e
+
+import (
+	"testing"
+
+	"github.com/apache/incubator-devlake/helpers/e2ehelper"
+	"github.com/apache/incubator-devlake/models/common"
+	"github.com/apache/incubator-devlake/models/domainlayer/crossdomain"
+	"github.com/apache/incubator-devlake/models/domainlayer/ticket"
+	"github.com/apache/incubator-devlake/plugins/pagerduty/impl"
+	"github.com/apache/incubator-devlake/plugins/pagerduty/models"
+	"github.com/apache/incubator-devlake/plugins/pagerduty/tasks"
+)
+
+func TestServiceDataFlow(t *testing.T) {
+	var plugin impl.PagerDuty
+	dataflowTester := e2ehelper.NewDataFlowTester(t, "pagerduty", plugin)
+
+	taskData := &tasks.PagerDutyTaskData{
+		Options: &tasks.PagerDutyOptions{
+			ConnectionId: 1,
+			Transformations: tasks.TransformationRules{
+				PriorityMappings: map[string]string{"P1": "HIGHEST"},
+				UrgencyMappings:  map[string]string{"high": "SEV1", "low": "SEV3"},
+				ServiceMappings: map[string][]string{
+					"PIKL83L":        {"github:GithubRepo:1:134018330"},
+					"PaymentService": {"jenkins:JenkinsJob:1:payment"},
+				},
+			},
+		},
+	}
+
+	// import raw data table
+	dataflowTester.ImportCsvIntoRawTable("./raw_tables/_raw_pagerduty_incidents.csv", "_raw_pagerduty_incidents")
+	dataflowTester.ImportCsvIntoRawTable("./raw_tables/_raw_pagerduty_services.csv", "_raw_pagerduty_services")
+
+	// services are extracted after incidents, the details from the services stream should win
+	dataflowTester.FlushTabler(&models.Incident{})
+	dataflowTester.FlushTabler(&models.User{})
+	dataflowTester.FlushTabler(&models.Service{})
+	dataflowTester.FlushTabler(&models.Assignment{})
+	dataflowTester.FlushTabler(&models.EscalationPolicy{})
+	dataflowTester.FlushTabler(&models.LogEntry{})
+	dataflowTester.Subtask(tasks.ExtractIncidentsMeta, taskData)
+	dataflowTester.Subtask(tasks.ExtractServicesMeta, taskData)
+	dataflowTester.VerifyTableWithOptions(
+		models.Service{},
+		e2ehelper.TableOptions{
+			CSVRelPath:  "./snapshot_tables/_tool_pagerduty_services_collected.csv",
+			IgnoreTypes: []any{common.Model{}},
+		},
+	)
+	dataflowTester.VerifyTableWithOptions(
+		models.EscalationPolicy{},
+		e2ehelper.TableOptions{
+			CSVRelPath:  "./snapshot_tables/_tool_pagerduty_escalation_policies_collected.csv",
+			IgnoreTypes: []any{common.Model{}},
+		},
+	)
+
+	// verify conversion
+	dataflowTester.FlushTabler(&ticket.Board{})
+	dataflowTester.FlushTabler(&crossdomain.BoardRepo{})
+	dataflowTester.Subtask(tasks.ConvertServicesMeta, taskData)
+	dataflowTester.VerifyTableWithOptions(
+		ticket.Board{},
+		e2ehelper.TableOptions{
+			CSVRelPath:  "./snapshot_tables/boards.csv",
+			IgnoreTypes: []any{common.NoPKModel{}},
+		},
+	)
+	dataflowTester.VerifyTableWithOptions(
+		crossdomain.BoardRepo{},
+		e2ehelper.TableOptions{
+			CSVRelPath:  "./snapshot_tables/board_repos.csv",
+			IgnoreTypes: []any{common.NoPKModel{}},
+		},
+	)
+	dataflowTester.FlushTabler(&ticket.Issue{})
+	dataflowTester.FlushTabler(&ticket.BoardIssue{})
+	dataflowTester.Subtask(tasks.ConvertIncidentsMeta, taskData)
+	dataflowTester.VerifyTableWithOptions(
+		ticket.Issue{},
+		e2ehelper.TableOptions{
+			CSVRelPath:   "./snapshot_tables/issues_with_transformation_rules.csv",
+			IgnoreTypes:  []any{common.NoPKModel{}},
+			IgnoreFields: []string{"original_project"},
+		},
+	)
+	dataflowTester.VerifyTableWithOptions(
+		ticket.BoardIssue{},
+		e2ehelper.TableOptions{
+			CSVRelPath:  "./snapshot_tables/board_issues.csv",
+			IgnoreTypes: []any{common.NoPKModel{}},
+		},
+	)
+}
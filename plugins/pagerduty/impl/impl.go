@@ -24,6 +24,7 @@ import (
 	"github.com/apache/incubator-devlake/errors"
 	"github.com/apache/incubator-devlake/helpers/pluginhelper/tap"
 	"github.com/apache/incubator-devlake/plugins/core"
+	"github.com/apache/incubator-devlake/plugins/core/dal"
 	"github.com/apache/incubator-devlake/plugins/helper"
 	"github.com/apache/incubator-devlake/plugins/pagerduty/api"
 	"github.com/apache/incubator-devlake/plugins/pagerduty/models"
@@ -37,6 +38,8 @@ var _ core.PluginInit = (*PagerDuty)(nil)
 var _ core.PluginTask = (*PagerDuty)(nil)
 var _ core.PluginApi = (*PagerDuty)(nil)
 var _ core.PluginBlueprintV100 = (*PagerDuty)(nil)
+var _ core.DataSourcePluginBlueprintV200 = (*PagerDuty)(nil)
+var _ core.PluginSource = (*PagerDuty)(nil)
 var _ core.CloseablePluginTask = (*PagerDuty)(nil)
 
 type PagerDuty struct{}
@@ -45,6 +48,18 @@ func (plugin PagerDuty) Description() string {
 	return "collect some PagerDuty data"
 }
 
+func (plugin PagerDuty) Connection() interface{} {
+	return &models.PagerDutyConnection{}
+}
+
+func (plugin PagerDuty) Scope() interface{} {
+	return &models.Service{}
+}
+
+func (plugin PagerDuty) TransformationRule() interface{} {
+	return &models.PagerDutyTransformationRule{}
+}
+
 func (plugin PagerDuty) Init(basicRes core.BasicRes) errors.Error {
 	api.Init(basicRes)
 	return nil
@@ -54,6 +69,10 @@ func (plugin PagerDuty) SubTaskMetas() []core.SubTaskMeta {
 	return []core.SubTaskMeta{
 		tasks.CollectIncidentsMeta,
 		tasks.ExtractIncidentsMeta,
+		// services are extracted after incidents so that their details are not overwritten by the references embedded in incidents
+		tasks.CollectServicesMeta,
+		tasks.ExtractServicesMeta,
+		tasks.ConvertServicesMeta,
 		tasks.ConvertIncidentsMeta,
 	}
 }
@@ -63,6 +82,18 @@ func (plugin PagerDuty) PrepareTaskData(taskCtx core.TaskContext, options map[st
 	if err != nil {
 		return nil, err
 	}
+	if op.TransformationRuleId != 0 {
+		var transformationRule models.PagerDutyTransformationRule
+		err = taskCtx.GetDal().First(&transformationRule, dal.Where("id = ?", op.TransformationRuleId))
+		if err != nil {
+			return nil, errors.BadInput.Wrap(err, "fail to get transformationRule")
+		}
+		rules, err := tasks.MakeTransformationRules(&transformationRule)
+		if err != nil {
+			return nil, err
+		}
+		op.Transformations = *rules
+	}
 	connectionHelper := helper.NewConnectionHelper(
 		taskCtx,
 		nil,
@@ -118,6 +149,22 @@ func (plugin PagerDuty) ApiResources() map[string]map[string]core.ApiResourceHan
 			"PATCH":  api.PatchConnection,
 			"DELETE": api.DeleteConnection,
 		},
+		"connections/:connectionId/scopes/:serviceId": {
+			"GET":   api.GetScope,
+			"PATCH": api.UpdateScope,
+		},
+		"connections/:connectionId/scopes": {
+			"GET": api.GetScopeList,
+			"PUT": api.PutScope,
+		},
+		"transformation_rules": {
+			"POST": api.CreateTransformationRule,
+			"GET":  api.GetTransformationRuleList,
+		},
+		"transformation_rules/:id": {
+			"PATCH": api.UpdateTransformationRule,
+			"GET":   api.GetTransformationRule,
+		},
 	}
 }
 
@@ -125,6 +172,10 @@ func (plugin PagerDuty) MakePipelinePlan(connectionId uint64, scope []*core.Blue
 	return api.MakePipelinePlan(plugin.SubTaskMetas(), connectionId, scope)
 }
 
+func (plugin PagerDuty) MakeDataSourcePipelinePlanV200(connectionId uint64, scopes []*core.BlueprintScopeV200, syncPolicy core.BlueprintSyncPolicy) (pp core.PipelinePlan, sc []core.Scope, err errors.Error) {
+	return api.MakeDataSourcePipelinePlanV200(plugin.SubTaskMetas(), connectionId, scopes, &syncPolicy)
+}
+
 func (plugin PagerDuty) Close(taskCtx core.TaskContext) errors.Error {
 	_, ok := taskCtx.GetData().(*tasks.PagerDutyTaskData)
 	if !ok {
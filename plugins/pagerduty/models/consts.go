@@ -22,4 +22,5 @@ const (
 	TapExecutable        = "tap-pagerduty"
 	StreamPropertiesFile = "pagerduty.json"
 	IncidentStream       = "incidents"
+	ServiceStream        = "services"
 )
@@ -34,15 +34,19 @@ type (
 
 	Incident struct {
 		common.NoPKModel
-		ConnectionId uint64 `gorm:"primaryKey"`
-		Number       int    `gorm:"primaryKey"`
-		Url          string
-		ServiceId    string
-		Summary      string
-		Status       IncidentStatus  //acknowledged, triggered, resolved
-		Urgency      IncidentUrgency //high or low
-		CreatedDate  time.Time
-		UpdatedDate  time.Time
+		ConnectionId       uint64 `gorm:"primaryKey"`
+		Number             int    `gorm:"primaryKey"`
+		Url                string
+		ServiceId          string
+		EscalationPolicyId string
+		Summary            string
+		Status             IncidentStatus  //acknowledged, triggered, resolved
+		Urgency            IncidentUrgency //high or low
+		Priority           string          //e.g. P1, empty if priorities are not enabled for the account
+		CreatedDate        time.Time
+		UpdatedDate        time.Time
+		AcknowledgedDate   *time.Time
+		ResolvedDate       *time.Time
 	}
 )
 
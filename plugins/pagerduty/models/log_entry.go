@@ -0,0 +1,49 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import (
+	"time"
+
+	"github.com/apache/incubator-devlake/models/common"
+)
+
+const (
+	LogEntryTypeTrigger     = "trigger_log_entry"
+	LogEntryTypeAcknowledge = "acknowledge_log_entry"
+	LogEntryTypeResolve     = "resolve_log_entry"
+)
+
+// LogEntry is one event on the timeline of an incident, like being triggered, acknowledged, escalated or resolved
+type LogEntry struct {
+	common.NoPKModel
+	ConnectionId   uint64 `gorm:"primaryKey"`
+	Id             string `gorm:"primaryKey"`
+	IncidentNumber int    `gorm:"index"`
+	Type           string `gorm:"type:varchar(100)"`
+	Summary        string
+	AgentId        string `gorm:"type:varchar(100)"`
+	AgentType      string `gorm:"type:varchar(100)"`
+	AgentName      string
+	ChannelType    string `gorm:"type:varchar(100)"`
+	CreatedDate    time.Time
+}
+
+func (LogEntry) TableName() string {
+	return "_tool_pagerduty_log_entries"
+}
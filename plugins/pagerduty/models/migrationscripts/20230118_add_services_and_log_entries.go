@@ -0,0 +1,113 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migrationscripts
+
+import (
+	"time"
+
+	"github.com/apache/incubator-devlake/errors"
+	"github.com/apache/incubator-devlake/helpers/migrationhelper"
+	"github.com/apache/incubator-devlake/models/migrationscripts/archived"
+	"github.com/apache/incubator-devlake/plugins/core"
+	"gorm.io/datatypes"
+)
+
+type addServicesAndLogEntries struct{}
+
+type service20230118 struct {
+	Description          string
+	Status               string `gorm:"type:varchar(100)"`
+	EscalationPolicyId   string `gorm:"type:varchar(100)"`
+	CreatedDate          *time.Time
+	TransformationRuleId uint64
+}
+
+func (service20230118) TableName() string {
+	return "_tool_pagerduty_services"
+}
+
+type incident20230118 struct {
+	EscalationPolicyId string
+	Priority           string
+	AcknowledgedDate   *time.Time
+	ResolvedDate       *time.Time
+}
+
+func (incident20230118) TableName() string {
+	return "_tool_pagerduty_incidents"
+}
+
+type escalationPolicy20230118 struct {
+	archived.NoPKModel
+	ConnectionId uint64 `gorm:"primaryKey"`
+	Id           string `gorm:"primaryKey"`
+	Url          string
+	Name         string
+}
+
+func (escalationPolicy20230118) TableName() string {
+	return "_tool_pagerduty_escalation_policies"
+}
+
+type logEntry20230118 struct {
+	archived.NoPKModel
+	ConnectionId   uint64 `gorm:"primaryKey"`
+	Id             string `gorm:"primaryKey"`
+	IncidentNumber int    `gorm:"index"`
+	Type           string `gorm:"type:varchar(100)"`
+	Summary        string
+	AgentId        string `gorm:"type:varchar(100)"`
+	AgentType      string `gorm:"type:varchar(100)"`
+	AgentName      string
+	ChannelType    string `gorm:"type:varchar(100)"`
+	CreatedDate    time.Time
+}
+
+func (logEntry20230118) TableName() string {
+	return "_tool_pagerduty_log_entries"
+}
+
+type transformationRule20230118 struct {
+	archived.Model
+	Name             string `gorm:"type:varchar(255);index:idx_name_pagerduty,unique"`
+	PriorityMappings datatypes.JSONMap
+	UrgencyMappings  datatypes.JSONMap
+	ServiceMappings  datatypes.JSONMap
+}
+
+func (transformationRule20230118) TableName() string {
+	return "_tool_pagerduty_transformation_rules"
+}
+
+func (*addServicesAndLogEntries) Up(basicRes core.BasicRes) errors.Error {
+	return migrationhelper.AutoMigrateTables(basicRes,
+		&service20230118{},
+		&incident20230118{},
+		&escalationPolicy20230118{},
+		&logEntry20230118{},
+		&transformationRule20230118{},
+	)
+}
+
+func (*addServicesAndLogEntries) Version() uint64 {
+	return 20230118102405
+}
+
+func (*addServicesAndLogEntries) Name() string {
+	return "add services details, escalation policies, log entries and transformation rules for pagerduty"
+}
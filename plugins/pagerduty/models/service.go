@@ -17,14 +17,23 @@ limitations under the License.
 
 package models
 
-import "github.com/apache/incubator-devlake/models/common"
+import (
+	"time"
+
+	"github.com/apache/incubator-devlake/models/common"
+)
 
 type Service struct {
-	common.NoPKModel
-	ConnectionId uint64 `gorm:"primaryKey"`
-	Url          string
-	Id           string `gorm:"primaryKey"`
-	Name         string
+	common.NoPKModel     `json:"-" mapstructure:"-"`
+	ConnectionId         uint64     `json:"connectionId" gorm:"primaryKey" mapstructure:"connectionId,omitempty"`
+	Url                  string     `json:"url" mapstructure:"url,omitempty"`
+	Id                   string     `json:"id" gorm:"primaryKey" mapstructure:"id"`
+	Name                 string     `json:"name" mapstructure:"name,omitempty"`
+	Description          string     `json:"description" mapstructure:"description,omitempty"`
+	Status               string     `json:"status" gorm:"type:varchar(100)" mapstructure:"status,omitempty"`
+	EscalationPolicyId   string     `json:"escalationPolicyId" gorm:"type:varchar(100)" mapstructure:"escalationPolicyId,omitempty"`
+	CreatedDate          *time.Time `json:"createdDate" mapstructure:"-"`
+	TransformationRuleId uint64     `json:"transformationRuleId,omitempty" mapstructure:"transformationRuleId,omitempty"`
 }
 
 func (Service) TableName() string {
@@ -0,0 +1,38 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import (
+	"github.com/apache/incubator-devlake/models/common"
+	"gorm.io/datatypes"
+)
+
+type PagerDutyTransformationRule struct {
+	common.Model `mapstructure:"-"`
+	Name         string `mapstructure:"name" json:"name" gorm:"type:varchar(255);index:idx_name_pagerduty,unique" validate:"required"`
+	// PriorityMappings maps the priority of incidents, like `P1`, to the priority of issues
+	PriorityMappings datatypes.JSONMap `mapstructure:"priorityMappings,omitempty" json:"priorityMappings" swaggertype:"object" format:"json"`
+	// UrgencyMappings maps the urgency of incidents, `high` or `low`, to the severity of issues
+	UrgencyMappings datatypes.JSONMap `mapstructure:"urgencyMappings,omitempty" json:"urgencyMappings" swaggertype:"object" format:"json"`
+	// ServiceMappings maps the id or name of services to the ids of the repos or cicd_scopes they are deployed from
+	ServiceMappings datatypes.JSONMap `mapstructure:"serviceMappings,omitempty" json:"serviceMappings" swaggertype:"object" format:"json"`
+}
+
+func (PagerDutyTransformationRule) TableName() string {
+	return "_tool_pagerduty_transformation_rules"
+}
@@ -46,26 +46,34 @@ type (
 		common.NoPKModel
 		*models.Incident
 		*models.User
-		AssignedAt time.Time
+		AssignedAt  time.Time
+		ServiceName string
 	}
 )
 
 func ConvertIncidents(taskCtx core.SubTaskContext) errors.Error {
 	db := taskCtx.GetDal()
 	data := taskCtx.GetData().(*PagerDutyTaskData)
-	cursor, err := db.Cursor(
-		dal.Select("pi.*, pu.*, pa.assigned_at"),
+	clauses := []dal.Clause{
+		dal.Select("pi.*, pu.*, pa.assigned_at, ps.name AS service_name"),
 		dal.From("_tool_pagerduty_incidents AS pi"),
 		dal.Join(`LEFT JOIN _tool_pagerduty_assignments AS pa ON pa.incident_number = pi.number`),
 		dal.Join(`LEFT JOIN _tool_pagerduty_users AS pu ON pa.user_id = pu.id`),
+		dal.Join(`LEFT JOIN _tool_pagerduty_services AS ps ON ps.id = pi.service_id AND ps.connection_id = pi.connection_id`),
 		dal.Where("pi.connection_id = ?", data.Options.ConnectionId),
-	)
+	}
+	if len(data.Options.ServiceIds) > 0 {
+		clauses = append(clauses, dal.Where("pi.service_id IN (?)", data.Options.ServiceIds))
+	}
+	cursor, err := db.Cursor(clauses...)
 	if err != nil {
 		return err
 	}
 	defer cursor.Close()
 	seenIncidents := map[int]*IncidentWithUser{}
 	idGen := didgen.NewDomainIdGenerator(&models.Incident{})
+	serviceIdGen := didgen.NewDomainIdGenerator(&models.Service{})
+	rules := data.Options.Transformations
 	converter, err := helper.NewDataConverter(helper.DataConverterArgs{
 		RawDataSubTaskArgs: helper.RawDataSubTaskArgs{
 			Ctx: taskCtx,
@@ -103,14 +111,24 @@ func ConvertIncidents(taskCtx core.SubTaskContext) errors.Error {
 				CreatedDate:     &incident.CreatedDate,
 				UpdatedDate:     &incident.UpdatedDate,
 				LeadTimeMinutes: leadTime,
-				Priority:        string(incident.Urgency),
+				Priority:        getPriority(incident, rules.PriorityMappings),
+				Severity:        rules.UrgencyMappings[string(incident.Urgency)],
 				AssigneeId:      user.Id,
 				AssigneeName:    user.Name,
+				// the service is taken as the component, so the incident could be attributed to the deployments of it
+				Component: combined.ServiceName,
 			}
 			seenIncidents[incident.Number] = combined
-			return []interface{}{
+			results := []interface{}{
 				domainIssue,
-			}, nil
+			}
+			if incident.ServiceId != "" {
+				results = append(results, &ticket.BoardIssue{
+					BoardId: serviceIdGen.Generate(data.Options.ConnectionId, incident.ServiceId),
+					IssueId: domainIssue.Id,
+				})
+			}
+			return results, nil
 		},
 	})
 	if err != nil {
@@ -136,8 +154,19 @@ func getTimes(incident *models.Incident) (int64, *time.Time) {
 	var leadTime int64
 	var resolutionDate *time.Time
 	if incident.Status == models.IncidentStatusResolved {
-		resolutionDate = &incident.UpdatedDate
+		// the resolve log entry is preferred, the last status change is taken if there were no log entries
+		resolutionDate = incident.ResolvedDate
+		if resolutionDate == nil {
+			resolutionDate = &incident.UpdatedDate
+		}
 		leadTime = int64(resolutionDate.Sub(incident.CreatedDate).Minutes())
 	}
 	return leadTime, resolutionDate
 }
+
+func getPriority(incident *models.Incident, priorityMappings map[string]string) string {
+	if priority, ok := priorityMappings[incident.Priority]; ok && incident.Priority != "" {
+		return priority
+	}
+	return string(incident.Urgency)
+}
@@ -30,6 +30,10 @@ var _ core.SubTaskEntryPoint = ExtractIncidents
 
 func ExtractIncidents(taskCtx core.SubTaskContext) errors.Error {
 	data := taskCtx.GetData().(*PagerDutyTaskData)
+	existingServices, err := loadServices(taskCtx.GetDal(), data.Options.ConnectionId)
+	if err != nil {
+		return err
+	}
 	extractor, err := helper.NewApiExtractor(helper.ApiExtractorArgs{
 		RawDataSubTaskArgs: helper.RawDataSubTaskArgs{
 			Ctx: taskCtx,
@@ -58,15 +62,60 @@ func ExtractIncidents(taskCtx core.SubTaskContext) errors.Error {
 			}
 			results = append(results, &incident)
 			if incidentRaw.Service != nil {
-				service := models.Service{
-					ConnectionId: data.Options.ConnectionId,
-					Url:          resolve(incidentRaw.Service.HtmlUrl),
-					Id:           *incidentRaw.Service.Id,
-					Name:         *incidentRaw.Service.Summary,
+				service := models.Service{}
+				// keep the details collected from the services stream, only the reference is embedded in incidents
+				if existing, ok := existingServices[*incidentRaw.Service.Id]; ok {
+					service = *existing
 				}
+				service.ConnectionId = data.Options.ConnectionId
+				service.Url = resolve(incidentRaw.Service.HtmlUrl)
+				service.Id = *incidentRaw.Service.Id
+				service.Name = *incidentRaw.Service.Summary
 				incident.ServiceId = service.Id
 				results = append(results, &service)
 			}
+			if incidentRaw.EscalationPolicy != nil {
+				incident.EscalationPolicyId = *incidentRaw.EscalationPolicy.Id
+				results = append(results, &models.EscalationPolicy{
+					ConnectionId: data.Options.ConnectionId,
+					Id:           *incidentRaw.EscalationPolicy.Id,
+					Url:          resolve(incidentRaw.EscalationPolicy.HtmlUrl),
+					Name:         resolve(incidentRaw.EscalationPolicy.Summary),
+				})
+			}
+			if incidentRaw.Priority != nil {
+				incident.Priority = resolve(incidentRaw.Priority.Summary)
+			}
+			for _, logEntryRaw := range incidentRaw.LogEntries {
+				logEntry := &models.LogEntry{
+					ConnectionId:   data.Options.ConnectionId,
+					Id:             *logEntryRaw.Id,
+					IncidentNumber: *incidentRaw.IncidentNumber,
+					Type:           resolve(logEntryRaw.Type),
+					Summary:        resolve(logEntryRaw.Summary),
+					CreatedDate:    *logEntryRaw.CreatedAt,
+				}
+				if logEntryRaw.Agent != nil {
+					logEntry.AgentId = resolve(logEntryRaw.Agent.Id)
+					logEntry.AgentType = resolve(logEntryRaw.Agent.Type)
+					logEntry.AgentName = resolve(logEntryRaw.Agent.Summary)
+				}
+				if logEntryRaw.Channel != nil {
+					logEntry.ChannelType = resolve(logEntryRaw.Channel.Type)
+				}
+				// the first acknowledgement and the last resolution make up the timeline of the incident
+				switch logEntry.Type {
+				case models.LogEntryTypeAcknowledge:
+					if incident.AcknowledgedDate == nil || logEntry.CreatedDate.Before(*incident.AcknowledgedDate) {
+						incident.AcknowledgedDate = &logEntry.CreatedDate
+					}
+				case models.LogEntryTypeResolve:
+					if incident.ResolvedDate == nil || logEntry.CreatedDate.After(*incident.ResolvedDate) {
+						incident.ResolvedDate = &logEntry.CreatedDate
+					}
+				}
+				results = append(results, logEntry)
+			}
 			for _, assignmentRaw := range incidentRaw.Assignments {
 				userRaw := assignmentRaw.Assignee
 				results = append(results, &models.Assignment{
@@ -102,6 +151,6 @@ var ExtractIncidentsMeta = core.SubTaskMeta{
 	Name:             "extractIncidents",
 	EntryPoint:       ExtractIncidents,
 	EnabledByDefault: true,
-	Description:      "Extract PagerDuty incidents",
+	Description:      "Extract PagerDuty incidents with their log entries",
 	DomainTypes:      []string{core.DOMAIN_TYPE_TICKET},
 }
@@ -0,0 +1,62 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"github.com/apache/incubator-devlake/errors"
+	"github.com/apache/incubator-devlake/helpers/pluginhelper/tap"
+	"github.com/apache/incubator-devlake/plugins/core"
+	"github.com/apache/incubator-devlake/plugins/helper"
+	"github.com/apache/incubator-devlake/plugins/pagerduty/models"
+)
+
+const RAW_SERVICES_TABLE = "pagerduty_services"
+
+var _ core.SubTaskEntryPoint = CollectServices
+
+func CollectServices(taskCtx core.SubTaskContext) errors.Error {
+	data := taskCtx.GetData().(*PagerDutyTaskData)
+	collector, err := tap.NewTapCollector(
+		&tap.CollectorArgs[tap.SingerTapStream]{
+			RawDataSubTaskArgs: helper.RawDataSubTaskArgs{
+				Ctx:   taskCtx,
+				Table: RAW_SERVICES_TABLE,
+				Params: models.PagerDutyParams{
+					Stream:       models.ServiceStream,
+					ConnectionId: data.Options.ConnectionId,
+				},
+			},
+			TapClient:    data.Client,
+			TapConfig:    data.Config,
+			ConnectionId: data.Options.ConnectionId,
+			StreamName:   models.ServiceStream,
+		},
+	)
+	if err != nil {
+		return err
+	}
+	return collector.Execute()
+}
+
+var CollectServicesMeta = core.SubTaskMeta{
+	Name:             "collectServices",
+	EntryPoint:       CollectServices,
+	EnabledByDefault: true,
+	Description:      "Collect PagerDuty services",
+	DomainTypes:      []string{core.DOMAIN_TYPE_TICKET},
+}
@@ -0,0 +1,102 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"reflect"
+
+	"github.com/apache/incubator-devlake/errors"
+	"github.com/apache/incubator-devlake/models/domainlayer"
+	"github.com/apache/incubator-devlake/models/domainlayer/crossdomain"
+	"github.com/apache/incubator-devlake/models/domainlayer/didgen"
+	"github.com/apache/incubator-devlake/models/domainlayer/ticket"
+	"github.com/apache/incubator-devlake/plugins/core"
+	"github.com/apache/incubator-devlake/plugins/core/dal"
+	"github.com/apache/incubator-devlake/plugins/helper"
+	"github.com/apache/incubator-devlake/plugins/pagerduty/models"
+)
+
+var ConvertServicesMeta = core.SubTaskMeta{
+	Name:             "convertServices",
+	EntryPoint:       ConvertServices,
+	EnabledByDefault: true,
+	Description:      "Convert services into domain layer table boards, and map them to repos by the serviceMappings",
+	DomainTypes:      []string{core.DOMAIN_TYPE_TICKET, core.DOMAIN_TYPE_CROSS},
+}
+
+func ConvertServices(taskCtx core.SubTaskContext) errors.Error {
+	db := taskCtx.GetDal()
+	data := taskCtx.GetData().(*PagerDutyTaskData)
+	clauses := []dal.Clause{
+		dal.From(&models.Service{}),
+		dal.Where("connection_id = ?", data.Options.ConnectionId),
+	}
+	if len(data.Options.ServiceIds) > 0 {
+		clauses = append(clauses, dal.Where("id IN (?)", data.Options.ServiceIds))
+	}
+	cursor, err := db.Cursor(clauses...)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close()
+	idGen := didgen.NewDomainIdGenerator(&models.Service{})
+	serviceMappings := data.Options.Transformations.ServiceMappings
+	converter, err := helper.NewDataConverter(helper.DataConverterArgs{
+		RawDataSubTaskArgs: helper.RawDataSubTaskArgs{
+			Ctx: taskCtx,
+			Params: models.PagerDutyParams{
+				ConnectionId: data.Options.ConnectionId,
+				Stream:       models.ServiceStream,
+			},
+			Table: RAW_SERVICES_TABLE,
+		},
+		InputRowType: reflect.TypeOf(models.Service{}),
+		Input:        cursor,
+		Convert: func(inputRow interface{}) ([]interface{}, errors.Error) {
+			service := inputRow.(*models.Service)
+			boardId := idGen.Generate(data.Options.ConnectionId, service.Id)
+			results := []interface{}{
+				&ticket.Board{
+					DomainEntity: domainlayer.DomainEntity{
+						Id: boardId,
+					},
+					Name:        service.Name,
+					Description: service.Description,
+					Url:         service.Url,
+					CreatedDate: service.CreatedDate,
+				},
+			}
+			// services could be mapped either by id or by name
+			scopeIds := serviceMappings[service.Id]
+			if len(scopeIds) == 0 {
+				scopeIds = serviceMappings[service.Name]
+			}
+			for _, scopeId := range scopeIds {
+				results = append(results, &crossdomain.BoardRepo{
+					BoardId: boardId,
+					RepoId:  scopeId,
+				})
+			}
+			return results, nil
+		},
+	})
+	if err != nil {
+		return err
+	}
+	return converter.Execute()
+}
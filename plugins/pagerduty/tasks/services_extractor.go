@@ -0,0 +1,107 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"encoding/json"
+
+	"github.com/apache/incubator-devlake/errors"
+	"github.com/apache/incubator-devlake/plugins/core"
+	"github.com/apache/incubator-devlake/plugins/core/dal"
+	"github.com/apache/incubator-devlake/plugins/helper"
+	"github.com/apache/incubator-devlake/plugins/pagerduty/models"
+	"github.com/apache/incubator-devlake/plugins/pagerduty/models/generated"
+)
+
+var _ core.SubTaskEntryPoint = ExtractServices
+
+func ExtractServices(taskCtx core.SubTaskContext) errors.Error {
+	data := taskCtx.GetData().(*PagerDutyTaskData)
+	existing, err := loadServices(taskCtx.GetDal(), data.Options.ConnectionId)
+	if err != nil {
+		return err
+	}
+	extractor, err := helper.NewApiExtractor(helper.ApiExtractorArgs{
+		RawDataSubTaskArgs: helper.RawDataSubTaskArgs{
+			Ctx: taskCtx,
+			Params: models.PagerDutyParams{
+				ConnectionId: data.Options.ConnectionId,
+				Stream:       models.ServiceStream,
+			},
+			Table: RAW_SERVICES_TABLE,
+		},
+		Extract: func(row *helper.RawData) ([]interface{}, errors.Error) {
+			serviceRaw := &generated.Services{}
+			err := errors.Convert(json.Unmarshal(row.Data, serviceRaw))
+			if err != nil {
+				return nil, err
+			}
+			results := make([]interface{}, 0, 2)
+			service := &models.Service{
+				ConnectionId: data.Options.ConnectionId,
+				Url:          resolve(serviceRaw.HtmlUrl),
+				Id:           *serviceRaw.Id,
+				Name:         resolve(serviceRaw.Name),
+				Description:  resolve(serviceRaw.Description),
+				Status:       resolve(serviceRaw.Status),
+				CreatedDate:  serviceRaw.CreatedAt,
+			}
+			// the transformation rule is assigned through the scope api, it must survive the re-collection
+			if old, ok := existing[service.Id]; ok {
+				service.TransformationRuleId = old.TransformationRuleId
+			}
+			if serviceRaw.EscalationPolicy != nil {
+				service.EscalationPolicyId = *serviceRaw.EscalationPolicy.Id
+				results = append(results, &models.EscalationPolicy{
+					ConnectionId: data.Options.ConnectionId,
+					Id:           *serviceRaw.EscalationPolicy.Id,
+					Url:          resolve(serviceRaw.EscalationPolicy.HtmlUrl),
+					Name:         resolve(serviceRaw.EscalationPolicy.Summary),
+				})
+			}
+			results = append(results, service)
+			return results, nil
+		},
+	})
+	if err != nil {
+		return err
+	}
+	return extractor.Execute()
+}
+
+// loadServices returns the services of the connection which were saved before, keyed by their ids
+func loadServices(db dal.Dal, connectionId uint64) (map[string]*models.Service, errors.Error) {
+	var services []*models.Service
+	err := db.All(&services, dal.Where("connection_id = ?", connectionId))
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[string]*models.Service, len(services))
+	for _, service := range services {
+		result[service.Id] = service
+	}
+	return result, nil
+}
+
+var ExtractServicesMeta = core.SubTaskMeta{
+	Name:             "extractServices",
+	EntryPoint:       ExtractServices,
+	EnabledByDefault: true,
+	Description:      "Extract PagerDuty services and their escalation policies",
+	DomainTypes:      []string{core.DOMAIN_TYPE_TICKET},
+}
@@ -25,9 +25,12 @@ import (
 )
 
 type PagerDutyOptions struct {
-	ConnectionId    uint64   `json:"connectionId"`
-	Tasks           []string `json:"tasks,omitempty"`
-	Transformations TransformationRules
+	ConnectionId uint64   `json:"connectionId"`
+	Tasks        []string `json:"tasks,omitempty"`
+	// ServiceIds limits the conversion to the incidents of the given services, all services are converted if empty
+	ServiceIds           []string            `json:"serviceIds,omitempty" mapstructure:"serviceIds,omitempty"`
+	TransformationRuleId uint64              `json:"transformationRuleId,omitempty" mapstructure:"transformationRuleId,omitempty"`
+	Transformations      TransformationRules `json:"transformationRules" mapstructure:"transformationRules"`
 }
 
 type PagerDutyTaskData struct {
@@ -37,7 +40,26 @@ type PagerDutyTaskData struct {
 }
 
 type TransformationRules struct {
-	//Placeholder struct for later if needed
+	// PriorityMappings maps the priority of incidents to the priority of issues, the urgency is used when it is not mapped
+	PriorityMappings map[string]string `json:"priorityMappings" mapstructure:"priorityMappings"`
+	// UrgencyMappings maps the urgency of incidents to the severity of issues
+	UrgencyMappings map[string]string `json:"urgencyMappings" mapstructure:"urgencyMappings"`
+	// ServiceMappings maps the id or name of services to the ids of the repos or cicd_scopes they are deployed from
+	ServiceMappings map[string][]string `json:"serviceMappings" mapstructure:"serviceMappings"`
+}
+
+// MakeTransformationRules turns the transformation rule stored in the database into the one used by the subtasks
+func MakeTransformationRules(rule *models.PagerDutyTransformationRule) (*TransformationRules, errors.Error) {
+	var transformationRules TransformationRules
+	err := helper.DecodeMapStruct(map[string]interface{}{
+		"priorityMappings": map[string]interface{}(rule.PriorityMappings),
+		"urgencyMappings":  map[string]interface{}(rule.UrgencyMappings),
+		"serviceMappings":  map[string]interface{}(rule.ServiceMappings),
+	}, &transformationRules)
+	if err != nil {
+		return nil, errors.BadInput.Wrap(err, "invalid transformation rule")
+	}
+	return &transformationRules, nil
 }
 
 func DecodeAndValidateTaskOptions(options map[string]interface{}) (*PagerDutyOptions, errors.Error) {
@@ -50,3 +72,12 @@ func DecodeAndValidateTaskOptions(options map[string]interface{}) (*PagerDutyOpt
 	}
 	return &op, nil
 }
+
+func EncodeTaskOptions(op *PagerDutyOptions) (map[string]interface{}, errors.Error) {
+	var result map[string]interface{}
+	err := helper.Decode(op, &result, nil)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -0,0 +1,121 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"encoding/json"
+
+	"github.com/apache/incubator-devlake/errors"
+	"github.com/apache/incubator-devlake/models/domainlayer/code"
+	"github.com/apache/incubator-devlake/plugins/core"
+	"github.com/apache/incubator-devlake/plugins/core/dal"
+	"github.com/apache/incubator-devlake/plugins/refdiff/tasks"
+)
+
+// MakeMetricPluginPipelinePlanV200 generates the plan for the repos currently mapped to the project
+func MakeMetricPluginPipelinePlanV200(projectName string, options json.RawMessage) (core.PipelinePlan, errors.Error) {
+	var repos []*code.Repo
+	err := basicRes.GetDal().All(
+		&repos,
+		dal.Select("r.*"),
+		dal.From("repos r"),
+		dal.Join("LEFT JOIN project_mapping pm ON (pm.row_id = r.id)"),
+		dal.Where("pm.project_name = ? AND pm.table = ?", projectName, "repos"),
+	)
+	if err != nil {
+		return nil, err
+	}
+	scopes := make([]core.Scope, len(repos))
+	for i, repo := range repos {
+		scopes[i] = repo
+	}
+	return MakeScopedMetricPluginPipelinePlanV200(projectName, options, scopes)
+}
+
+// MakeScopedMetricPluginPipelinePlanV200 generates a refdiff task for every repo of the project matched by a policy
+func MakeScopedMetricPluginPipelinePlanV200(projectName string, options json.RawMessage, scopes []core.Scope) (core.PipelinePlan, errors.Error) {
+	op := &tasks.RefdiffProjectOptions{}
+	err := errors.Convert(json.Unmarshal(options, op))
+	if err != nil {
+		return nil, errors.BadInput.Wrap(err, "failed to decode refdiff options")
+	}
+	for i := range op.Policies {
+		err = op.Policies[i].Valid()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return makePipelinePlanV200(projectName, op, scopes), nil
+}
+
+func makePipelinePlanV200(projectName string, op *tasks.RefdiffProjectOptions, scopes []core.Scope) core.PipelinePlan {
+	stage := core.PipelineStage{}
+	for _, scope := range scopes {
+		if scope.TableName() != (&code.Repo{}).TableName() {
+			continue
+		}
+		for _, policy := range op.Policies {
+			if !policy.Match(scope.ScopeId(), scope.ScopeName()) {
+				continue
+			}
+			if len(policy.Pairs) == 0 && policy.TagsPattern == "" {
+				break
+			}
+			taskOptions := map[string]interface{}{
+				"repoId": scope.ScopeId(),
+			}
+			if len(policy.Pairs) > 0 {
+				pairs := make([]map[string]interface{}, len(policy.Pairs))
+				for i, pair := range policy.Pairs {
+					pairs[i] = map[string]interface{}{
+						"newRef": pair.NewRef,
+						"oldRef": pair.OldRef,
+					}
+				}
+				taskOptions["pairs"] = pairs
+			}
+			if policy.TagsPattern != "" {
+				taskOptions["tagsPattern"] = policy.TagsPattern
+				taskOptions["tagsLimit"] = policy.TagsLimit
+				taskOptions["tagsOrder"] = policy.TagsOrder
+			}
+			stage = append(stage, &core.PipelineTask{
+				Plugin:  "refdiff",
+				Options: taskOptions,
+			})
+			break
+		}
+	}
+	if op.DeploymentPairs {
+		deploymentTask := &core.PipelineTask{
+			Plugin:   "refdiff",
+			Subtasks: []string{tasks.CalculateProjectDeploymentCommitsDiffMeta.Name},
+			Options: map[string]interface{}{
+				"projectName": projectName,
+			},
+		}
+		if op.PointInTimeProjectMapping {
+			deploymentTask.Options["pointInTimeProjectMapping"] = true
+		}
+		stage = append(stage, deploymentTask)
+	}
+	if len(stage) == 0 {
+		return core.PipelinePlan{}
+	}
+	return core.PipelinePlan{stage}
+}
@@ -0,0 +1,85 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/apache/incubator-devlake/models/domainlayer"
+	"github.com/apache/incubator-devlake/models/domainlayer/code"
+	"github.com/apache/incubator-devlake/models/domainlayer/ticket"
+	"github.com/apache/incubator-devlake/plugins/core"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMakeScopedMetricPluginPipelinePlanV200(t *testing.T) {
+	scopes := []core.Scope{
+		&code.Repo{DomainEntity: domainlayer.DomainEntity{Id: "github:GithubRepo:1:1"}, Name: "apache/devlake"},
+		&ticket.Board{DomainEntity: domainlayer.DomainEntity{Id: "github:GithubRepo:1:1"}, Name: "apache/devlake"},
+		&code.Repo{DomainEntity: domainlayer.DomainEntity{Id: "gitlab:GitlabProject:1:2"}, Name: "apache/website"},
+		&code.Repo{DomainEntity: domainlayer.DomainEntity{Id: "gitlab:GitlabProject:1:3"}, Name: "apache/sandbox"},
+	}
+	options := json.RawMessage(`{
+		"policies": [
+			{"repoPattern": "sandbox$"},
+			{"repoPattern": "^github:", "tagsPattern": "^refs/tags/v", "tagsLimit": 10, "tagsOrder": "reverse semver"},
+			{"pairs": [{"newRef": "refs/heads/main", "oldRef": "refs/heads/release"}]}
+		],
+		"deploymentPairs": true
+	}`)
+	plan, err := MakeScopedMetricPluginPipelinePlanV200("project1", options, scopes)
+	assert.Nil(t, err)
+	// the sandbox is matched by a policy without any pairs, so it is skipped
+	assert.Equal(t, core.PipelinePlan{
+		{
+			{
+				Plugin: "refdiff",
+				Options: map[string]interface{}{
+					"repoId":      "github:GithubRepo:1:1",
+					"tagsPattern": "^refs/tags/v",
+					"tagsLimit":   10,
+					"tagsOrder":   "reverse semver",
+				},
+			},
+			{
+				Plugin: "refdiff",
+				Options: map[string]interface{}{
+					"repoId": "gitlab:GitlabProject:1:2",
+					"pairs": []map[string]interface{}{
+						{"newRef": "refs/heads/main", "oldRef": "refs/heads/release"},
+					},
+				},
+			},
+			{
+				Plugin:   "refdiff",
+				Subtasks: []string{"calculateProjectDeploymentCommitsDiff"},
+				Options: map[string]interface{}{
+					"projectName": "project1",
+				},
+			},
+		},
+	}, plan)
+
+	plan, err = MakeScopedMetricPluginPipelinePlanV200("project1", json.RawMessage(`{}`), scopes)
+	assert.Nil(t, err)
+	assert.Empty(t, plan)
+
+	_, err = MakeScopedMetricPluginPipelinePlanV200("project1", json.RawMessage(`{"policies": [{"tagsOrder": "newest"}]}`), scopes)
+	assert.NotNil(t, err)
+}
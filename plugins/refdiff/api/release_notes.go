@@ -0,0 +1,154 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/apache/incubator-devlake/errors"
+	"github.com/apache/incubator-devlake/plugins/core"
+	"github.com/apache/incubator-devlake/plugins/helper"
+	"github.com/apache/incubator-devlake/plugins/refdiff/tasks"
+)
+
+// GetReleaseNotes return the release notes between two refs of a repo
+// @Summary return the release notes between two refs of a repo
+// @Description return the pull requests merged between the refs grouped by type or label, the linked issues grouped
+// @Description by type, the cherry-picks and the contributors, in JSON or Markdown
+// @Tags plugins/refdiff
+// @Param repoId query string true "repo ID"
+// @Param newRef query string true "the new ref, i.e. refs/tags/v1.1.0, tags could be given without refs/tags/"
+// @Param oldRef query string true "the old ref"
+// @Param format query string false "json or markdown, default json"
+// @Success 200  {object} tasks.ReleaseNotes
+// @Failure 400  {object} shared.ApiBody "Bad Request"
+// @Failure 404  {object} shared.ApiBody "Not Found"
+// @Failure 500  {object} shared.ApiBody "Internal Error"
+// @Router /plugins/refdiff/release-notes [GET]
+func GetReleaseNotes(input *core.ApiResourceInput) (*core.ApiResourceOutput, errors.Error) {
+	repoId := input.Query.Get("repoId")
+	if repoId == "" {
+		return nil, errors.BadInput.New("repoId is required")
+	}
+	format := input.Query.Get("format")
+	if format != "" && format != "json" && format != "markdown" {
+		return nil, errors.BadInput.New(fmt.Sprintf("invalid format %s, it should be json or markdown", format))
+	}
+	db := basicRes.GetDal()
+	pair, err := tasks.ResolveRefPair(db, repoId, input.Query.Get("newRef"), input.Query.Get("oldRef"))
+	if err != nil {
+		return nil, err
+	}
+	calculated, err := tasks.IsCommitsDiffCalculated(db, pair)
+	if err != nil {
+		return nil, err
+	}
+	if !calculated {
+		return nil, errors.NotFound.New(fmt.Sprintf("the diff between %s and %s has not been calculated, run refdiff or POST the refs to release-notes", pair[2], pair[3]))
+	}
+	notes, err := tasks.GenerateReleaseNotes(db, repoId, pair)
+	if err != nil {
+		return nil, err
+	}
+	if format == "markdown" {
+		return &core.ApiResourceOutput{
+			Status: http.StatusOK,
+			File: &core.OutputFile{
+				ContentType: "text/markdown; charset=utf-8",
+				Data:        []byte(notes.Markdown()),
+			},
+		}, nil
+	}
+	return &core.ApiResourceOutput{Body: notes, Status: http.StatusOK}, nil
+}
+
+type ReleaseNotesRequest struct {
+	RepoId string `json:"repoId" mapstructure:"repoId" validate:"required"`
+	NewRef string `json:"newRef" mapstructure:"newRef" validate:"required"`
+	OldRef string `json:"oldRef" mapstructure:"oldRef" validate:"required"`
+}
+
+type ReleaseNotesCalculation struct {
+	// PipelineId is the pipeline calculating the commits diff, it is 0 when the diff has been calculated already
+	PipelineId uint64 `json:"pipelineId"`
+	NewRef     string `json:"newRef"`
+	OldRef     string `json:"oldRef"`
+}
+
+// PostReleaseNotes calculates the commits diff between two refs of a repo for the release notes
+// @Summary calculate the commits diff between two refs of a repo for the release notes
+// @Description the diff is calculated by a pipeline in the background, the release notes could be retrieved by GET
+// @Description once the pipeline finishes
+// @Tags plugins/refdiff
+// @Param body body ReleaseNotesRequest true "json body"
+// @Success 200  {object} ReleaseNotesCalculation "the diff has been calculated already"
+// @Success 202  {object} ReleaseNotesCalculation "the pipeline calculating the diff"
+// @Failure 400  {object} shared.ApiBody "Bad Request"
+// @Failure 404  {object} shared.ApiBody "Not Found"
+// @Failure 500  {object} shared.ApiBody "Internal Error"
+// @Router /plugins/refdiff/release-notes [POST]
+func PostReleaseNotes(input *core.ApiResourceInput) (*core.ApiResourceOutput, errors.Error) {
+	request := &ReleaseNotesRequest{}
+	err := helper.DecodeMapStruct(input.Body, request)
+	if err != nil {
+		return nil, errors.BadInput.Wrap(err, "invalid request body")
+	}
+	if validationErr := vld.Struct(request); validationErr != nil {
+		return nil, errors.BadInput.Wrap(validationErr, "invalid request body")
+	}
+	db := basicRes.GetDal()
+	pair, err := tasks.ResolveRefPair(db, request.RepoId, request.NewRef, request.OldRef)
+	if err != nil {
+		return nil, err
+	}
+	calculation := &ReleaseNotesCalculation{NewRef: pair[2], OldRef: pair[3]}
+	calculated, err := tasks.IsCommitsDiffCalculated(db, pair)
+	if err != nil {
+		return nil, err
+	}
+	if calculated {
+		return &core.ApiResourceOutput{Body: calculation, Status: http.StatusOK}, nil
+	}
+	calculation.PipelineId, err = core.SubmitPipeline(
+		fmt.Sprintf("release notes of %s between %s and %s", request.RepoId, pair[2], pair[3]),
+		releaseNotesPlan(request.RepoId, pair),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &core.ApiResourceOutput{Body: calculation, Status: http.StatusAccepted}, nil
+}
+
+// releaseNotesPlan calculates the commits diff of the resolved refs only
+func releaseNotesPlan(repoId string, pair tasks.RefCommitPair) core.PipelinePlan {
+	return core.PipelinePlan{
+		{
+			{
+				Plugin:   "refdiff",
+				Subtasks: []string{tasks.CalculateCommitsDiffMeta.Name},
+				Options: map[string]interface{}{
+					"repoId": repoId,
+					"pairs": []map[string]interface{}{
+						{"newRef": pair[2], "oldRef": pair[3]},
+					},
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,41 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"testing"
+
+	"github.com/apache/incubator-devlake/plugins/helper"
+	"github.com/apache/incubator-devlake/plugins/refdiff/tasks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReleaseNotesPlan(t *testing.T) {
+	pair := tasks.RefCommitPair{"sha2", "sha1", "refs/tags/v1.1", "refs/tags/v1.0"}
+	plan := releaseNotesPlan("github:GithubRepo:1:1", pair)
+	if assert.Len(t, plan, 1) && assert.Len(t, plan[0], 1) {
+		task := plan[0][0]
+		assert.Equal(t, "refdiff", task.Plugin)
+		assert.Equal(t, []string{tasks.CalculateCommitsDiffMeta.Name}, task.Subtasks)
+		// the options are decoded the same way as PrepareTaskData does
+		var op tasks.RefdiffOptions
+		assert.Nil(t, helper.Decode(task.Options, &op, nil))
+		assert.Equal(t, "github:GithubRepo:1:1", op.RepoId)
+		assert.Equal(t, []tasks.RefPair{{NewRef: "refs/tags/v1.1", OldRef: "refs/tags/v1.0"}}, op.Pairs)
+	}
+}
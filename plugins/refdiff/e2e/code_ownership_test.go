@@ -0,0 +1,64 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package e2e
+
+import (
+	"testing"
+
+	"github.com/apache/incubator-devlake/helpers/e2ehelper"
+	"github.com/apache/incubator-devlake/models/common"
+	"github.com/apache/incubator-devlake/models/domainlayer/code"
+	"github.com/apache/incubator-devlake/plugins/refdiff/impl"
+	"github.com/apache/incubator-devlake/plugins/refdiff/tasks"
+)
+
+func TestCodeOwnershipDataFlow(t *testing.T) {
+
+	var plugin impl.RefDiff
+	dataflowTester := e2ehelper.NewDataFlowTester(t, "refdiff", plugin)
+
+	taskData := &tasks.RefdiffTaskData{
+		Options: &tasks.RefdiffOptions{
+			RepoId:       "github:GithubRepo:1:2",
+			ChurnWindows: []int{30, 90},
+		},
+	}
+
+	// import raw data table
+	dataflowTester.ImportCsvIntoTabler("./raw_tables/refs.csv", &code.Ref{})
+	dataflowTester.ImportCsvIntoTabler("./raw_tables/commits.csv", &code.Commit{})
+	dataflowTester.ImportCsvIntoTabler("./raw_tables/repo_commits.csv", &code.RepoCommit{})
+	dataflowTester.ImportCsvIntoTabler("./raw_tables/commit_parents.csv", &code.CommitParent{})
+	dataflowTester.ImportCsvIntoTabler("./raw_tables/commit_branches.csv", &code.CommitBranch{})
+	dataflowTester.ImportCsvIntoTabler("./raw_tables/commit_files.csv", &code.CommitFile{})
+	dataflowTester.ImportCsvIntoTabler("./raw_tables/repo_snapshot.csv", &code.RepoSnapshot{})
+
+	// verify calculation
+	dataflowTester.FlushTabler(&code.CodeOwnership{})
+	dataflowTester.FlushTabler(&code.CodeHotspot{})
+
+	dataflowTester.Subtask(tasks.CalculateCodeOwnershipMeta, taskData)
+	dataflowTester.VerifyTableWithOptions(&code.CodeOwnership{}, e2ehelper.TableOptions{
+		CSVRelPath:  "./snapshot_tables/code_ownerships.csv",
+		IgnoreTypes: []interface{}{common.NoPKModel{}},
+	})
+	dataflowTester.VerifyTableWithOptions(&code.CodeHotspot{}, e2ehelper.TableOptions{
+		CSVRelPath:  "./snapshot_tables/code_hotspots.csv",
+		IgnoreTypes: []interface{}{common.NoPKModel{}},
+	})
+}
@@ -51,6 +51,7 @@ func TestRepoDataFlow(t *testing.T) {
 	// verify extraction
 	dataflowTester.FlushTabler(&code.CommitsDiff{})
 	dataflowTester.FlushTabler(&code.FinishedCommitsDiff{})
+	dataflowTester.FlushTabler(&code.CommitGeneration{})
 
 	dataflowTester.Subtask(tasks.CalculateProjectDeploymentCommitsDiffMeta, taskData)
 	dataflowTester.VerifyTableWithOptions(&code.CommitsDiff{}, e2ehelper.TableOptions{
@@ -0,0 +1,110 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package e2e
+
+import (
+	"testing"
+
+	"github.com/apache/incubator-devlake/helpers/e2ehelper"
+	"github.com/apache/incubator-devlake/models/domainlayer/code"
+	"github.com/apache/incubator-devlake/models/domainlayer/crossdomain"
+	"github.com/apache/incubator-devlake/models/domainlayer/ticket"
+	"github.com/apache/incubator-devlake/plugins/core/dal"
+	"github.com/apache/incubator-devlake/plugins/refdiff/impl"
+	"github.com/apache/incubator-devlake/plugins/refdiff/tasks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReleaseNotes(t *testing.T) {
+	var plugin impl.RefDiff
+	dataflowTester := e2ehelper.NewDataFlowTester(t, "refdiff", plugin)
+	repoId := "github:GithubRepo:1:3"
+
+	dataflowTester.ImportCsvIntoTabler("./raw_tables/release_notes_refs.csv", &code.Ref{})
+	dataflowTester.ImportCsvIntoTabler("./raw_tables/release_notes_repo_commits.csv", &code.RepoCommit{})
+	dataflowTester.ImportCsvIntoTabler("./raw_tables/release_notes_commit_parents.csv", &code.CommitParent{})
+	dataflowTester.ImportCsvIntoTabler("./raw_tables/release_notes_commits.csv", &code.Commit{})
+	dataflowTester.ImportCsvIntoTabler("./raw_tables/release_notes_pull_requests.csv", &code.PullRequest{})
+	dataflowTester.ImportCsvIntoTabler("./raw_tables/release_notes_pull_request_commits.csv", &code.PullRequestCommit{})
+	dataflowTester.ImportCsvIntoTabler("./raw_tables/release_notes_pull_request_labels.csv", &code.PullRequestLabel{})
+	dataflowTester.ImportCsvIntoTabler("./raw_tables/release_notes_pull_request_issues.csv", &crossdomain.PullRequestIssue{})
+	dataflowTester.ImportCsvIntoTabler("./raw_tables/release_notes_issues.csv", &ticket.Issue{})
+	dataflowTester.ImportCsvIntoTabler("./raw_tables/release_notes_refs_pr_cherrypicks.csv", &code.RefsPrCherrypick{})
+	dataflowTester.ImportCsvIntoTabler("./raw_tables/release_notes_accounts.csv", &crossdomain.Account{})
+	dataflowTester.ImportCsvIntoTabler("./raw_tables/release_notes_user_accounts.csv", &crossdomain.UserAccount{})
+	dataflowTester.ImportCsvIntoTabler("./raw_tables/release_notes_users.csv", &crossdomain.User{})
+	dataflowTester.FlushTabler(&code.CommitsDiff{})
+	dataflowTester.FlushTabler(&code.FinishedCommitsDiff{})
+	dataflowTester.FlushTabler(&code.RefCommit{})
+	dataflowTester.FlushTabler(&code.CommitGeneration{})
+
+	// tags could be given without the prefix
+	pair, err := tasks.ResolveRefPair(dataflowTester.Dal, repoId, "v1.1", "refs/tags/v1.0")
+	assert.Nil(t, err)
+	assert.Equal(t, tasks.RefCommitPair{"notes_sha4", "notes_sha1", "refs/tags/v1.1", "refs/tags/v1.0"}, pair)
+	_, err = tasks.ResolveRefPair(dataflowTester.Dal, repoId, "v2.0", "v1.0")
+	assert.NotNil(t, err)
+
+	// the diff is calculated by the pipeline submitted by the api
+	calculated, err := tasks.IsCommitsDiffCalculated(dataflowTester.Dal, pair)
+	assert.Nil(t, err)
+	assert.False(t, calculated)
+	dataflowTester.Subtask(tasks.CalculateCommitsDiffMeta, &tasks.RefdiffTaskData{
+		Options: &tasks.RefdiffOptions{RepoId: repoId, AllPairs: tasks.RefCommitPairs{pair}},
+	})
+	calculated, err = tasks.IsCommitsDiffCalculated(dataflowTester.Dal, pair)
+	assert.Nil(t, err)
+	assert.True(t, calculated)
+	// the generation numbers are filled for the walk
+	generations, err := dataflowTester.Dal.Count(dal.From(&code.CommitGeneration{}))
+	assert.Nil(t, err)
+	assert.Equal(t, int64(4), generations)
+
+	notes, err := tasks.GenerateReleaseNotes(dataflowTester.Dal, repoId, pair)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(3), notes.CommitCount)
+
+	// pr3 was merged before v1.0, pr2 was rebased so only its commits are in the range
+	if assert.Len(t, notes.PullRequestGroups, 3) {
+		assert.Equal(t, "bug", notes.PullRequestGroups[0].Name)
+		assert.Equal(t, 2, notes.PullRequestGroups[0].PullRequests[0].PullRequestKey)
+		assert.Equal(t, []string{"bug", "crash"}, notes.PullRequestGroups[0].PullRequests[0].Labels)
+		assert.Equal(t, "feature", notes.PullRequestGroups[1].Name)
+		assert.Equal(t, 1, notes.PullRequestGroups[1].PullRequests[0].PullRequestKey)
+		assert.Equal(t, tasks.RELEASE_NOTES_OTHERS, notes.PullRequestGroups[2].Name)
+		assert.Equal(t, 4, notes.PullRequestGroups[2].PullRequests[0].PullRequestKey)
+	}
+	if assert.Len(t, notes.IssueGroups, 2) {
+		assert.Equal(t, "BUG", notes.IssueGroups[0].Type)
+		assert.Equal(t, "12", notes.IssueGroups[0].Issues[0].IssueKey)
+		assert.Equal(t, "REQUIREMENT", notes.IssueGroups[1].Type)
+		assert.Len(t, notes.IssueGroups[1].Issues, 1)
+		assert.Equal(t, "11", notes.IssueGroups[1].Issues[0].IssueKey)
+	}
+	if assert.Len(t, notes.CherryPicks, 1) {
+		assert.Equal(t, 1, notes.CherryPicks[0].ParentPrKey)
+		assert.Equal(t, []string{"release-1.0"}, notes.CherryPicks[0].CherrypickBaseBranches)
+		assert.Equal(t, []string{"5"}, notes.CherryPicks[0].CherrypickPrKeys)
+	}
+	// authors of pull requests and commits are merged into users
+	assert.Equal(t, []*tasks.ReleaseNotesContributor{
+		{UserId: "user1", Name: "Alice", Email: "alice@example.com", PullRequestCount: 1, CommitCount: 2},
+		{UserId: "user2", Name: "Bob", Email: "bob@example.com", PullRequestCount: 1, CommitCount: 1},
+		{Name: "Carol", PullRequestCount: 1},
+	}, notes.Contributors)
+}
@@ -18,19 +18,24 @@ limitations under the License.
 package impl
 
 import (
+	"encoding/json"
+
 	"github.com/apache/incubator-devlake/errors"
 	"github.com/apache/incubator-devlake/plugins/core"
 	"github.com/apache/incubator-devlake/plugins/core/dal"
 	"github.com/apache/incubator-devlake/plugins/helper"
+	"github.com/apache/incubator-devlake/plugins/refdiff/api"
 	"github.com/apache/incubator-devlake/plugins/refdiff/tasks"
 )
 
 // make sure interface is implemented
 var _ core.PluginMeta = (*RefDiff)(nil)
+var _ core.PluginInit = (*RefDiff)(nil)
 var _ core.PluginTask = (*RefDiff)(nil)
 var _ core.PluginApi = (*RefDiff)(nil)
 var _ core.PluginModel = (*RefDiff)(nil)
 var _ core.PluginMetric = (*RefDiff)(nil)
+var _ core.ScopedMetricPluginBlueprintV200 = (*RefDiff)(nil)
 
 type RefDiff struct{}
 
@@ -41,6 +46,11 @@ func (plugin RefDiff) Description() string {
 	return "Calculate commits diff for specified ref pairs based on `commits` and `commit_parents` tables"
 }
 
+func (plugin RefDiff) Init(basicRes core.BasicRes) errors.Error {
+	api.Init(basicRes)
+	return nil
+}
+
 func (plugin RefDiff) RequiredDataEntities() (data []map[string]interface{}, err errors.Error) {
 	return []map[string]interface{}{}, nil
 }
@@ -65,8 +75,10 @@ func (plugin RefDiff) SubTaskMetas() []core.SubTaskMeta {
 	return []core.SubTaskMeta{
 		tasks.CalculateCommitsDiffMeta,
 		tasks.CalculateIssuesDiffMeta,
+		tasks.CalculateVersionsIssuesDiffMeta,
 		tasks.CalculatePrCherryPickMeta,
 		tasks.CalculateProjectDeploymentCommitsDiffMeta,
+		tasks.CalculateCodeOwnershipMeta,
 	}
 }
 
@@ -82,7 +94,7 @@ func (plugin RefDiff) PrepareTaskData(taskCtx core.TaskContext, options map[stri
 	tagsLimit := op.TagsLimit
 	tagsOrder := op.TagsOrder
 
-	rs, err := tasks.CalculateTagPattern(db, tagsPattern, tagsLimit, tagsOrder)
+	rs, err := tasks.CalculateTagPattern(db, op.RepoId, tagsPattern, tagsLimit, tagsOrder)
 	if err != nil {
 		return nil, err
 	}
@@ -96,11 +108,24 @@ func (plugin RefDiff) PrepareTaskData(taskCtx core.TaskContext, options map[stri
 	}, nil
 }
 
+func (plugin RefDiff) MakeMetricPluginPipelinePlanV200(projectName string, options json.RawMessage) (core.PipelinePlan, errors.Error) {
+	return api.MakeMetricPluginPipelinePlanV200(projectName, options)
+}
+
+func (plugin RefDiff) MakeScopedMetricPluginPipelinePlanV200(projectName string, options json.RawMessage, scopes []core.Scope) (core.PipelinePlan, errors.Error) {
+	return api.MakeScopedMetricPluginPipelinePlanV200(projectName, options, scopes)
+}
+
 // PkgPath information lost when compiled as plugin(.so)
 func (plugin RefDiff) RootPkgPath() string {
 	return "github.com/apache/incubator-devlake/plugins/refdiff"
 }
 
 func (plugin RefDiff) ApiResources() map[string]map[string]core.ApiResourceHandler {
-	return nil
+	return map[string]map[string]core.ApiResourceHandler{
+		"release-notes": {
+			"GET":  api.GetReleaseNotes,
+			"POST": api.PostReleaseNotes,
+		},
+	}
 }
@@ -0,0 +1,317 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"path"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/apache/incubator-devlake/errors"
+	"github.com/apache/incubator-devlake/models/domainlayer/code"
+	"github.com/apache/incubator-devlake/plugins/core"
+	"github.com/apache/incubator-devlake/plugins/core/dal"
+	"github.com/apache/incubator-devlake/plugins/helper"
+)
+
+// DefaultChurnWindows are the windows in days used when ChurnWindows is not specified in options
+var DefaultChurnWindows = []int{30, 90, 180}
+
+type snapshotFileAuthor struct {
+	FilePath   string
+	AuthorId   string
+	AuthorName string
+	LineCount  int
+}
+
+type fileChange struct {
+	FilePath      string
+	CommitSha     string
+	Additions     int
+	Deletions     int
+	CommittedDate time.Time
+}
+
+type codePathChurn struct {
+	commitCount   int
+	additions     int
+	deletions     int
+	lastCommitSha string
+}
+
+type codePathStats struct {
+	pathType    string
+	lineCount   int
+	fileCount   int
+	authorLines map[string]int
+	// churns has one element for each churn window
+	churns []codePathChurn
+}
+
+// codePaths returns the file path followed by the directories containing it up to the root directory
+func codePaths(filePath string) []string {
+	paths := []string{filePath}
+	for dir := path.Dir(filePath); dir != "."; dir = path.Dir(dir) {
+		paths = append(paths, dir)
+	}
+	return append(paths, code.CODE_PATH_ROOT)
+}
+
+// calculateBusFactor returns the least number of authors owning more than half of the lines
+func calculateBusFactor(authorLines map[string]int, lineCount int) int {
+	lines := make([]int, 0, len(authorLines))
+	for _, authorLineCount := range authorLines {
+		lines = append(lines, authorLineCount)
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(lines)))
+	owned := 0
+	for i, authorLineCount := range lines {
+		owned += authorLineCount
+		if owned*2 > lineCount {
+			return i + 1
+		}
+	}
+	return len(lines)
+}
+
+// topAuthor returns the author owning the most lines, the smaller id wins the tie
+func topAuthor(authorLines map[string]int) (string, int) {
+	var topAuthorId string
+	topLineCount := -1
+	for authorId, lineCount := range authorLines {
+		if lineCount > topLineCount || (lineCount == topLineCount && authorId < topAuthorId) {
+			topAuthorId, topLineCount = authorId, lineCount
+		}
+	}
+	return topAuthorId, topLineCount
+}
+
+// CalculateCodeOwnership calculates the line ownership by author, the churn, the bus factor and the hotspot score
+// of each file and directory at the head of the default branch, based on `repo_snapshot`, `commit_branches` and
+// `commit_files`
+func CalculateCodeOwnership(taskCtx core.SubTaskContext) errors.Error {
+	data := taskCtx.GetData().(*RefdiffTaskData)
+	repoId := data.Options.RepoId
+	db := taskCtx.GetDal()
+	logger := taskCtx.GetLogger()
+	if repoId == "" {
+		return nil
+	}
+	windows := data.Options.ChurnWindows
+	if len(windows) == 0 {
+		windows = DefaultChurnWindows
+	}
+
+	// the snapshot and the commits of the default branch are stored by gitextractor, which always collects the branch of HEAD
+	defaultBranch := &code.Ref{}
+	err := db.First(defaultBranch, dal.Where("repo_id = ? AND ref_type = ? AND is_default = ?", repoId, "BRANCH", true))
+	if err != nil {
+		if db.IsErrorNotFound(err) {
+			logger.Info("no default branch found for repo %s, skip calculating code ownership", repoId)
+			return nil
+		}
+		return err
+	}
+	snapshotCommit := &code.Commit{}
+	err = db.First(snapshotCommit, dal.Where("sha = ?", defaultBranch.CommitSha))
+	if err != nil {
+		if db.IsErrorNotFound(err) {
+			logger.Info("commit %s not found, skip calculating code ownership", defaultBranch.CommitSha)
+			return nil
+		}
+		return err
+	}
+
+	// step 1. ownership by author from the snapshot
+	var fileAuthors []snapshotFileAuthor
+	err = db.All(
+		&fileAuthors,
+		dal.Select("s.file_path, COALESCE(c.author_id, '') AS author_id, MAX(c.author_name) AS author_name, COUNT(*) AS line_count"),
+		dal.From("repo_snapshot s"),
+		dal.Join("LEFT JOIN commits c ON c.sha = s.commit_sha"),
+		dal.Where("s.repo_id = ? AND s.branch = ?", repoId, defaultBranch.Name),
+		dal.Groupby("s.file_path, COALESCE(c.author_id, '')"),
+		dal.Orderby("s.file_path"),
+	)
+	if err != nil {
+		return err
+	}
+	stats := make(map[string]*codePathStats)
+	authorNames := make(map[string]string)
+	lastFilePath := ""
+	for _, fileAuthor := range fileAuthors {
+		authorNames[fileAuthor.AuthorId] = fileAuthor.AuthorName
+		for i, p := range codePaths(fileAuthor.FilePath) {
+			pathStats, ok := stats[p]
+			if !ok {
+				pathStats = &codePathStats{
+					pathType:    code.CODE_PATH_DIRECTORY,
+					authorLines: make(map[string]int),
+					churns:      make([]codePathChurn, len(windows)),
+				}
+				if i == 0 {
+					pathStats.pathType = code.CODE_PATH_FILE
+				}
+				stats[p] = pathStats
+			}
+			if fileAuthor.FilePath != lastFilePath {
+				pathStats.fileCount++
+			}
+			pathStats.lineCount += fileAuthor.LineCount
+			pathStats.authorLines[fileAuthor.AuthorId] += fileAuthor.LineCount
+		}
+		lastFilePath = fileAuthor.FilePath
+	}
+	logger.Info("found %d files and directories in the snapshot of repo %s", len(stats), repoId)
+
+	// step 2. churn of the commits on the default branch in the windows before the snapshot commit, merge commits
+	// are excluded since their changes were counted in the commits merged
+	maxWindow := 0
+	for _, window := range windows {
+		if window > maxWindow {
+			maxWindow = window
+		}
+	}
+	snapshotDate := snapshotCommit.CommittedDate
+	cursor, err := db.Cursor(
+		dal.Select("cf.file_path, cf.commit_sha, cf.additions, cf.deletions, c.committed_date"),
+		dal.From("commit_files cf"),
+		dal.Join("LEFT JOIN commits c ON c.sha = cf.commit_sha"),
+		dal.Join("LEFT JOIN commit_branches cb ON cb.commit_sha = cf.commit_sha"),
+		dal.Where(
+			`cb.repo_id = ? AND cb.branch = ? AND c.committed_date > ? AND c.committed_date <= ?
+			AND cf.commit_sha NOT IN (SELECT commit_sha FROM commit_parents GROUP BY commit_sha HAVING COUNT(*) > 1)`,
+			repoId, defaultBranch.Name, snapshotDate.AddDate(0, 0, -maxWindow), snapshotDate,
+		),
+		dal.Orderby("cf.commit_sha"),
+	)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close()
+	for cursor.Next() {
+		change := &fileChange{}
+		err = db.Fetch(cursor, change)
+		if err != nil {
+			return err
+		}
+		for _, p := range codePaths(change.FilePath) {
+			// only the paths existing in the snapshot are of interest
+			pathStats, ok := stats[p]
+			if !ok {
+				continue
+			}
+			for i, window := range windows {
+				if !change.CommittedDate.After(snapshotDate.AddDate(0, 0, -window)) {
+					continue
+				}
+				churn := &pathStats.churns[i]
+				churn.additions += change.Additions
+				churn.deletions += change.Deletions
+				if churn.lastCommitSha != change.CommitSha {
+					churn.commitCount++
+					churn.lastCommitSha = change.CommitSha
+				}
+			}
+		}
+	}
+
+	// step 3. replace the results of the same snapshot
+	err = db.Delete(&code.CodeOwnership{}, dal.Where("repo_id = ? AND snapshot_commit_sha = ?", repoId, snapshotCommit.Sha))
+	if err != nil {
+		return err
+	}
+	err = db.Delete(&code.CodeHotspot{}, dal.Where("repo_id = ? AND snapshot_commit_sha = ?", repoId, snapshotCommit.Sha))
+	if err != nil {
+		return err
+	}
+	ownershipBatch, err := helper.NewBatchSave(taskCtx, reflect.TypeOf(&code.CodeOwnership{}), 500)
+	if err != nil {
+		return err
+	}
+	hotspotBatch, err := helper.NewBatchSave(taskCtx, reflect.TypeOf(&code.CodeHotspot{}), 500)
+	if err != nil {
+		return err
+	}
+	paths := make([]string, 0, len(stats))
+	for p := range stats {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	taskCtx.SetProgress(0, len(paths))
+	for _, p := range paths {
+		pathStats := stats[p]
+		for authorId, lineCount := range pathStats.authorLines {
+			err = ownershipBatch.Add(&code.CodeOwnership{
+				RepoId:            repoId,
+				SnapshotCommitSha: snapshotCommit.Sha,
+				Path:              p,
+				AuthorId:          authorId,
+				PathType:          pathStats.pathType,
+				AuthorName:        authorNames[authorId],
+				LineCount:         lineCount,
+				Ownership:         float64(lineCount) / float64(pathStats.lineCount),
+			})
+			if err != nil {
+				return err
+			}
+		}
+		topAuthorId, topLineCount := topAuthor(pathStats.authorLines)
+		busFactor := calculateBusFactor(pathStats.authorLines, pathStats.lineCount)
+		for i, window := range windows {
+			churn := pathStats.churns[i]
+			hotspot := &code.CodeHotspot{
+				RepoId:             repoId,
+				SnapshotCommitSha:  snapshotCommit.Sha,
+				Path:               p,
+				WindowDays:         window,
+				PathType:           pathStats.pathType,
+				LineCount:          pathStats.lineCount,
+				FileCount:          pathStats.fileCount,
+				AuthorCount:        len(pathStats.authorLines),
+				BusFactor:          busFactor,
+				TopAuthorId:        topAuthorId,
+				TopAuthorOwnership: float64(topLineCount) / float64(pathStats.lineCount),
+				CommitCount:        churn.commitCount,
+				Additions:          churn.additions,
+				Deletions:          churn.deletions,
+				Churn:              churn.additions + churn.deletions,
+			}
+			hotspot.HotspotScore = float64(hotspot.Churn) * float64(hotspot.LineCount)
+			err = hotspotBatch.Add(hotspot)
+			if err != nil {
+				return err
+			}
+		}
+		taskCtx.IncProgress(1)
+	}
+	err = ownershipBatch.Close()
+	if err != nil {
+		return err
+	}
+	return hotspotBatch.Close()
+}
+
+var CalculateCodeOwnershipMeta = core.SubTaskMeta{
+	Name:             "calculateCodeOwnership",
+	EntryPoint:       CalculateCodeOwnership,
+	EnabledByDefault: true,
+	Description:      "Calculate the code ownership, churn, bus factor and hotspot score of files and directories",
+	DomainTypes:      []string{core.DOMAIN_TYPE_CODE},
+}
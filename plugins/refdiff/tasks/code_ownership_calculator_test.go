@@ -0,0 +1,44 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"testing"
+
+	"github.com/apache/incubator-devlake/models/domainlayer/code"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCodePaths(t *testing.T) {
+	assert.Equal(t, []string{"README.md", code.CODE_PATH_ROOT}, codePaths("README.md"))
+	assert.Equal(t, []string{"a/b/c.go", "a/b", "a", code.CODE_PATH_ROOT}, codePaths("a/b/c.go"))
+}
+
+func TestCalculateBusFactor(t *testing.T) {
+	assert.Equal(t, 0, calculateBusFactor(map[string]int{}, 0))
+	assert.Equal(t, 1, calculateBusFactor(map[string]int{"alice": 3, "bob": 1}, 4))
+	// exactly a half is not enough
+	assert.Equal(t, 2, calculateBusFactor(map[string]int{"alice": 2, "bob": 1, "carol": 1}, 4))
+	assert.Equal(t, 3, calculateBusFactor(map[string]int{"alice": 1, "bob": 1, "carol": 1, "dave": 1, "eve": 1}, 5))
+}
+
+func TestTopAuthor(t *testing.T) {
+	authorId, lineCount := topAuthor(map[string]int{"carol": 2, "bob": 2, "alice": 1})
+	assert.Equal(t, "bob", authorId)
+	assert.Equal(t, 2, lineCount)
+}
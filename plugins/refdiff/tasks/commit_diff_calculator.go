@@ -26,6 +26,7 @@ import (
 	"github.com/apache/incubator-devlake/models/domainlayer/code"
 	"github.com/apache/incubator-devlake/plugins/core"
 	"github.com/apache/incubator-devlake/plugins/core/dal"
+	"github.com/apache/incubator-devlake/plugins/helper"
 	"github.com/apache/incubator-devlake/plugins/refdiff/utils"
 )
 
@@ -71,123 +72,135 @@ func CalculateCommitsDiff(taskCtx core.SubTaskContext) errors.Error {
 		return nil
 	}
 
-	commitNodeGraph := utils.NewCommitNodeGraph()
-	// mysql limit
-	insertCountLimitOfCommitsDiff := int(65535 / reflect.ValueOf(code.CommitsDiff{}).NumField())
-
-	// load commits from db
-	commitParent := &code.CommitParent{}
-	cursor, err := db.Cursor(
-		dal.Select("cp.*"),
-		dal.Join("LEFT JOIN repo_commits rc ON (rc.commit_sha = cp.commit_sha)"),
-		dal.From("commit_parents cp"),
-		dal.Where("rc.repo_id = ?", repoId),
-	)
+	commitGraphWalker, err := newCommitGraphWalker(db, logger, repoId)
 	if err != nil {
 		return err
 	}
-	defer cursor.Close()
 
-	for cursor.Next() {
+	// calculate diffs for commits pairs and store them into database
+	lenCommitPairs := len(commitPairs)
+	taskCtx.SetProgress(0, lenCommitPairs)
+
+	for _, pair := range commitPairs {
 		select {
 		case <-ctx.Done():
 			return errors.Convert(ctx.Err())
 		default:
 		}
-		err = db.Fetch(cursor, commitParent)
+		err = saveCommitsDiff(db, logger, commitGraphWalker, pair, refCommit)
 		if err != nil {
-			return errors.Default.Wrap(err, "failed to read commit from database")
+			return err
 		}
-		commitNodeGraph.AddParent(commitParent.CommitSha, commitParent.ParentCommitSha)
+		taskCtx.IncProgress(1)
 	}
+	return nil
+}
 
-	logger.Info("Create a commit node graph with node count[%d]", commitNodeGraph.Size())
+// newCommitGraphWalker brings the generation numbers of the repo up to date, gitextractor maintains them as well,
+// and returns a walker which only loads the commits between the compared ones and their merge bases
+func newCommitGraphWalker(db dal.Dal, logger core.Logger, repoId string) (*utils.CommitGraphWalker, errors.Error) {
+	err := helper.UpdateCommitGenerations(db, logger, repoId)
+	if err != nil {
+		return nil, err
+	}
+	return utils.NewCommitGraphWalker(&commitGraphLoader{db: db}, commitGraphLoadBatchSize), nil
+}
 
-	// calculate diffs for commits pairs and store them into database
+const commitGraphLoadBatchSize = 500
+
+// commitGraphLoader reads commit_parents and commit_generations for the commit graph walks
+type commitGraphLoader struct {
+	db dal.Dal
+}
+
+func (l *commitGraphLoader) LoadGenerations(shas []string) (map[string]int, errors.Error) {
+	return helper.LoadCommitGenerations(l.db, shas)
+}
+
+func (l *commitGraphLoader) LoadParents(shas []string) (map[string][]string, errors.Error) {
+	return helper.LoadCommitParents(l.db, shas)
+}
+
+// saveCommitsDiff calculates the commits reachable from the new commit but not from the old one and stores them
+func saveCommitsDiff(db dal.Dal, logger core.Logger, commitGraphWalker *utils.CommitGraphWalker, pair RefCommitPair, refCommit *code.RefCommit) errors.Error {
+	// mysql limit
+	insertCountLimitOfCommitsDiff := int(65535 / reflect.ValueOf(code.CommitsDiff{}).NumField())
 	commitsDiff := &code.CommitsDiff{}
 	finishedCommitDiff := &code.FinishedCommitsDiff{}
-	lenCommitPairs := len(commitPairs)
-	taskCtx.SetProgress(0, lenCommitPairs)
+	// ref might advance, keep commit sha for debugging
+	commitsDiff.NewCommitSha = pair[0]
+	commitsDiff.OldCommitSha = pair[1]
 
-	for _, pair := range commitPairs {
-		select {
-		case <-ctx.Done():
-			return errors.Convert(ctx.Err())
-		default:
-		}
-		// ref might advance, keep commit sha for debugging
-		commitsDiff.NewCommitSha = pair[0]
-		commitsDiff.OldCommitSha = pair[1]
-
-		finishedCommitDiff.NewCommitSha = pair[0]
-		finishedCommitDiff.OldCommitSha = pair[1]
-
-		if commitsDiff.NewCommitSha == commitsDiff.OldCommitSha {
-			// different refs might point to a same commit, it is ok
-			logger.Info(
-				"skipping ref pair due to they are the same %s",
-				commitsDiff.NewCommitSha,
-			)
-			continue
-		}
+	finishedCommitDiff.NewCommitSha = pair[0]
+	finishedCommitDiff.OldCommitSha = pair[1]
 
-		lostSha, oldCount, newCount := commitNodeGraph.CalculateLostSha(pair[1], pair[0])
-
-		commitsDiffs := []code.CommitsDiff{}
-		refCommits := []code.RefCommit{}
-		finishedCommitDiffs := []code.FinishedCommitsDiff{}
-
-		commitsDiff.SortingIndex = 1
-		for _, sha := range lostSha {
-			commitsDiff.CommitSha = sha
-			commitsDiffs = append(commitsDiffs, *commitsDiff)
-
-			// sql limit placeholders count only 65535
-			if commitsDiff.SortingIndex%insertCountLimitOfCommitsDiff == 0 {
-				logger.Info("commitsDiffs count in limited[%d] index[%d]--exec and clean", len(commitsDiffs), commitsDiff.SortingIndex)
-				err = db.CreateIfNotExist(commitsDiffs)
-				if err != nil {
-					return err
-				}
-				commitsDiffs = []code.CommitsDiff{}
-			}
+	if commitsDiff.NewCommitSha == commitsDiff.OldCommitSha {
+		// different refs might point to a same commit, it is ok
+		logger.Info(
+			"skipping ref pair due to they are the same %s",
+			commitsDiff.NewCommitSha,
+		)
+		return nil
+	}
 
-			commitsDiff.SortingIndex++
-		}
+	lostSha, oldCount, newCount, err := commitGraphWalker.CalculateLostSha(pair[1], pair[0])
+	if err != nil {
+		return err
+	}
+
+	commitsDiffs := []code.CommitsDiff{}
+	refCommits := []code.RefCommit{}
+	finishedCommitDiffs := []code.FinishedCommitsDiff{}
+
+	commitsDiff.SortingIndex = 1
+	for _, sha := range lostSha {
+		commitsDiff.CommitSha = sha
+		commitsDiffs = append(commitsDiffs, *commitsDiff)
 
-		if len(commitsDiffs) > 0 {
-			logger.Info("insert data count [%d]", len(commitsDiffs))
-			err = db.CreateIfNotExist(commitsDiffs)
+		// sql limit placeholders count only 65535
+		if commitsDiff.SortingIndex%insertCountLimitOfCommitsDiff == 0 {
+			logger.Info("commitsDiffs count in limited[%d] index[%d]--exec and clean", len(commitsDiffs), commitsDiff.SortingIndex)
+			err := db.CreateIfNotExist(commitsDiffs)
 			if err != nil {
 				return err
 			}
+			commitsDiffs = []code.CommitsDiff{}
 		}
 
-		refCommits = append(refCommits, *refCommit)
-		if len(refCommits) > 0 {
-			err = db.CreateIfNotExist(refCommits)
-			if err != nil {
-				return err
-			}
+		commitsDiff.SortingIndex++
+	}
+
+	if len(commitsDiffs) > 0 {
+		logger.Info("insert data count [%d]", len(commitsDiffs))
+		err := db.CreateIfNotExist(commitsDiffs)
+		if err != nil {
+			return err
 		}
+	}
 
-		finishedCommitDiffs = append(finishedCommitDiffs, *finishedCommitDiff)
-		if len(finishedCommitDiffs) > 0 {
-			err = db.CreateIfNotExist(finishedCommitDiffs)
-			if err != nil {
-				return err
-			}
+	refCommits = append(refCommits, *refCommit)
+	if len(refCommits) > 0 {
+		err := db.CreateIfNotExist(refCommits)
+		if err != nil {
+			return err
 		}
+	}
 
-		logger.Info(
-			"total %d commits of difference found between [new][%s] and [old][%s(total:%d)]",
-			newCount,
-			commitsDiff.NewCommitSha,
-			commitsDiff.OldCommitSha,
-			oldCount,
-		)
-		taskCtx.IncProgress(1)
+	finishedCommitDiffs = append(finishedCommitDiffs, *finishedCommitDiff)
+	if len(finishedCommitDiffs) > 0 {
+		err := db.CreateIfNotExist(finishedCommitDiffs)
+		if err != nil {
+			return err
+		}
 	}
+
+	logger.Info(
+		"total %d commits of difference found between [new][%s] and [old][%s(visited:%d)]",
+		newCount,
+		commitsDiff.NewCommitSha,
+		commitsDiff.OldCommitSha,
+		oldCount,
+	)
 	return nil
 }
 
@@ -24,7 +24,6 @@ import (
 	"github.com/apache/incubator-devlake/models/domainlayer/code"
 	"github.com/apache/incubator-devlake/plugins/core"
 	"github.com/apache/incubator-devlake/plugins/core/dal"
-	"github.com/apache/incubator-devlake/plugins/refdiff/utils"
 )
 
 func CommitDiffConvertor(pipelineCommitShaList []string, existFinishedCommitDiff []code.FinishedCommitsDiff) (commitPairs []code.CommitsDiff, finishedCommitDiffs []code.FinishedCommitsDiff) {
@@ -52,9 +51,14 @@ func CalculateProjectDeploymentCommitsDiff(taskCtx core.SubTaskContext) errors.E
 		return nil
 	}
 
+	mappingTable := "project_mapping"
+	if data.Options.PointInTimeProjectMapping {
+		// the deployments made before a repo moved to another project are still part of the history of the project
+		mappingTable = "project_mapping_history"
+	}
 	cursorScope, err := db.Cursor(
-		dal.Select("row_id"),
-		dal.From("project_mapping"),
+		dal.Select("distinct row_id"),
+		dal.From(mappingTable),
 		dal.Where("project_name = ?", projectName),
 	)
 	if err != nil {
@@ -94,24 +98,11 @@ func CalculateProjectDeploymentCommitsDiff(taskCtx core.SubTaskContext) errors.E
 		commitPairs, finishedCommitDiffs := CommitDiffConvertor(pipelineCommitShaList, existFinishedCommitDiff)
 
 		insertCountLimitOfDeployCommitsDiff := int(65535 / reflect.ValueOf(code.CommitsDiff{}).NumField())
-		commitNodeGraph := utils.NewCommitNodeGraph()
-
-		var CommitParentList []code.CommitParent
-		err = db.All(&CommitParentList,
-			dal.Select("cp.*"),
-			dal.Join("LEFT JOIN repo_commits rc ON (rc.commit_sha = cp.commit_sha)"),
-			dal.From("commit_parents cp"),
-			dal.Where("rc.repo_id = ?", scopeId),
-		)
+		commitGraphWalker, err := newCommitGraphWalker(db, logger, scopeId)
 		if err != nil {
 			return err
 		}
 
-		for i := 0; i < len(CommitParentList); i++ {
-			commitNodeGraph.AddParent(CommitParentList[i].CommitSha, CommitParentList[i].ParentCommitSha)
-		}
-		logger.Info("Create a commit node graph with node count[%d]", commitNodeGraph.Size())
-
 		// calculate diffs for commits pairs and store them into database
 		commitsDiff := &code.CommitsDiff{}
 		lenCommitPairs := len(commitPairs)
@@ -136,7 +127,10 @@ func CalculateProjectDeploymentCommitsDiff(taskCtx core.SubTaskContext) errors.E
 				continue
 			}
 
-			lostSha, oldCount, newCount := commitNodeGraph.CalculateLostSha(commitsDiff.OldCommitSha, commitsDiff.NewCommitSha)
+			lostSha, oldCount, newCount, err := commitGraphWalker.CalculateLostSha(commitsDiff.OldCommitSha, commitsDiff.NewCommitSha)
+			if err != nil {
+				return err
+			}
 
 			commitsDiffs := []code.CommitsDiff{}
 			commitsDiff.SortingIndex = 1
@@ -173,7 +167,7 @@ func CalculateProjectDeploymentCommitsDiff(taskCtx core.SubTaskContext) errors.E
 			}
 
 			logger.Info(
-				"total %d commits of difference found between [new][%s] and [old][%s(total:%d)]",
+				"total %d commits of difference found between [new][%s] and [old][%s(visited:%d)]",
 				newCount,
 				commitsDiff.NewCommitSha,
 				commitsDiff.OldCommitSha,
@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"regexp"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -41,6 +42,77 @@ type RefdiffOptions struct {
 
 	AllPairs    RefCommitPairs // Pairs and TagsPattern Pairs
 	ProjectName string
+	// BoardIds are the boards whose versions are linked to the ref pairs,
+	// the boards of the projects the repo belongs to are used when it is empty
+	BoardIds []string `json:"boardIds"`
+	// PointInTimeProjectMapping includes the repos which belonged to the project in the past
+	PointInTimeProjectMapping bool `json:"pointInTimeProjectMapping"`
+
+	// ChurnWindows are the numbers of days before the snapshot commit to calculate the churn of code hotspots in,
+	// DefaultChurnWindows is used when it is empty
+	ChurnWindows []int `json:"churnWindows"`
+}
+
+const (
+	TAGS_ORDER_ALPHABETICALLY         = "alphabetically"
+	TAGS_ORDER_REVERSE_ALPHABETICALLY = "reverse alphabetically"
+	TAGS_ORDER_SEMVER                 = "semver"
+	TAGS_ORDER_REVERSE_SEMVER         = "reverse semver"
+)
+
+// RefdiffProjectOptions are the options of refdiff as a metric plugin of a project in blueprint v2.0.0
+type RefdiffProjectOptions struct {
+	// Policies are matched against every repo of the project in order, the first matching one is applied
+	Policies []RefdiffPolicy `json:"policies"`
+	// DeploymentPairs calculates the commits diffs between the consecutive deployments of the project as well
+	DeploymentPairs           bool `json:"deploymentPairs"`
+	PointInTimeProjectMapping bool `json:"pointInTimeProjectMapping"`
+}
+
+// RefdiffPolicy picks the ref pairs to be compared for the repos it matches
+type RefdiffPolicy struct {
+	// RepoPattern matches the ids or names of the repos, all repos are matched when it is empty
+	RepoPattern string    `json:"repoPattern"`
+	Pairs       []RefPair `json:"pairs"`
+	// TagsPattern matches the names of tags or branches, i.e. ^refs/tags/v\d+ or ^release/, the matched refs
+	// are sorted by TagsOrder and each one is compared with the next one
+	TagsPattern string `json:"tagsPattern"`
+	TagsLimit   int    `json:"tagsLimit"`
+	TagsOrder   string `json:"tagsOrder"`
+}
+
+// Valid checks the patterns and the order of the policy
+func (p *RefdiffPolicy) Valid() errors.Error {
+	if _, err := regexp.Compile(p.RepoPattern); err != nil {
+		return errors.BadInput.Wrap(err, fmt.Sprintf("invalid repoPattern %s", p.RepoPattern))
+	}
+	if _, err := regexp.Compile(p.TagsPattern); err != nil {
+		return errors.BadInput.Wrap(err, fmt.Sprintf("invalid tagsPattern %s", p.TagsPattern))
+	}
+	// a pair needs two tags at least, otherwise the pattern would be ignored silently by CalculateTagPattern
+	if p.TagsPattern != "" && p.TagsLimit <= 1 {
+		return errors.BadInput.New(fmt.Sprintf("tagsLimit should be greater than 1 for tagsPattern %s", p.TagsPattern))
+	}
+	switch p.TagsOrder {
+	case "", TAGS_ORDER_ALPHABETICALLY, TAGS_ORDER_REVERSE_ALPHABETICALLY, TAGS_ORDER_SEMVER, TAGS_ORDER_REVERSE_SEMVER:
+	default:
+		return errors.BadInput.New(fmt.Sprintf("unknown tagsOrder %s", p.TagsOrder))
+	}
+	for i, pair := range p.Pairs {
+		if pair.NewRef == "" || pair.OldRef == "" {
+			return errors.BadInput.New(fmt.Sprintf("pair #%d needs both newRef and oldRef", i))
+		}
+	}
+	return nil
+}
+
+// Match tells whether the policy applies to the repo
+func (p *RefdiffPolicy) Match(repoId, repoName string) bool {
+	if p.RepoPattern == "" {
+		return true
+	}
+	r := regexp.MustCompile(p.RepoPattern)
+	return r.MatchString(repoId) || r.MatchString(repoName)
 }
 
 type RefdiffTaskData struct {
@@ -95,9 +167,22 @@ func (rs RefsSemver) Len() int {
 	return len(rs)
 }
 
+// Less compares the versions by semver precedence when both names end with one, i.e. refs/tags/v1.10.0-rc.1,
+// and falls back to comparing the dot separated parts otherwise
 func (rs RefsSemver) Less(i, j int) bool {
-	parti := strings.Split(rs[i].Name, ".")
-	partj := strings.Split(rs[j].Name, ".")
+	vi, oki := parseSemver(rs[i].Name)
+	vj, okj := parseSemver(rs[j].Name)
+	if oki && okj {
+		if c := compareSemver(vi, vj); c != 0 {
+			return c < 0
+		}
+	}
+	return lessByDottedParts(rs[i].Name, rs[j].Name)
+}
+
+func lessByDottedParts(namei, namej string) bool {
+	parti := strings.Split(namei, ".")
+	partj := strings.Split(namej, ".")
 
 	for k := 0; k < len(partj); k++ {
 		if k >= len(parti) {
@@ -121,6 +206,74 @@ func (rs RefsSemver) Less(i, j int) bool {
 	return false
 }
 
+var semverPattern = regexp.MustCompile(`(\d+)(?:\.(\d+))?(?:\.(\d+))?(?:-([0-9A-Za-z.-]+))?(?:\+[0-9A-Za-z.-]+)?$`)
+
+type semver struct {
+	core       [3]int
+	prerelease []string
+}
+
+// parseSemver extracts the version at the end of a ref name, missing minor and patch numbers are taken as 0
+func parseSemver(name string) (*semver, bool) {
+	match := semverPattern.FindStringSubmatch(name)
+	if match == nil {
+		return nil, false
+	}
+	v := &semver{}
+	for k := 0; k < 3; k++ {
+		if match[k+1] == "" {
+			continue
+		}
+		n, err := strconv.Atoi(match[k+1])
+		if err != nil {
+			return nil, false
+		}
+		v.core[k] = n
+	}
+	if match[4] != "" {
+		v.prerelease = strings.Split(match[4], ".")
+	}
+	return v, true
+}
+
+// compareSemver follows the precedence rules of https://semver.org, a pre-release is lower than its release
+func compareSemver(a, b *semver) int {
+	for k := 0; k < 3; k++ {
+		if a.core[k] != b.core[k] {
+			if a.core[k] < b.core[k] {
+				return -1
+			}
+			return 1
+		}
+	}
+	if len(a.prerelease) == 0 || len(b.prerelease) == 0 {
+		return len(b.prerelease) - len(a.prerelease)
+	}
+	for k := 0; k < len(a.prerelease) && k < len(b.prerelease); k++ {
+		ai, aErr := strconv.Atoi(a.prerelease[k])
+		bi, bErr := strconv.Atoi(b.prerelease[k])
+		switch {
+		case aErr == nil && bErr == nil:
+			if ai != bi {
+				if ai < bi {
+					return -1
+				}
+				return 1
+			}
+		case aErr == nil:
+			// numeric identifiers have lower precedence than alphanumeric ones
+			return -1
+		case bErr == nil:
+			return 1
+		default:
+			if c := strings.Compare(a.prerelease[k], b.prerelease[k]); c != 0 {
+				return c
+			}
+		}
+	}
+	return len(a.prerelease) - len(b.prerelease)
+}
+
 func (rs RefsSemver) Swap(i, j int) {
 	rs[i], rs[j] = rs[j], rs[i]
 }
@@ -137,19 +290,24 @@ func (rs RefsReverseSemver) Swap(i, j int) {
 	rs[i], rs[j] = rs[j], rs[i]
 }
 
-// CalculateTagPattern Calculate the TagPattern order by tagsOrder and return the Refs
-func CalculateTagPattern(db dal.Dal, tagsPattern string, tagsLimit int, tagsOrder string) (Refs, errors.Error) {
+// CalculateTagPattern Calculate the TagPattern order by tagsOrder and return the Refs, only the refs of the repo are
+// matched when repoId is given
+func CalculateTagPattern(db dal.Dal, repoId string, tagsPattern string, tagsLimit int, tagsOrder string) (Refs, errors.Error) {
 	rs := Refs{}
 
 	// caculate Pattern part
 	if tagsPattern == "" || tagsLimit <= 1 {
 		return rs, nil
 	}
-	rows, err := db.Cursor(
+	clauses := []dal.Clause{
 		dal.From("refs"),
 		dal.Where(""),
 		dal.Orderby("created_date desc"),
-	)
+	}
+	if repoId != "" {
+		clauses[1] = dal.Where("repo_id = ?", repoId)
+	}
+	rows, err := db.Cursor(clauses...)
 
 	if err != nil {
 		return rs, err
@@ -171,13 +329,13 @@ func CalculateTagPattern(db dal.Dal, tagsPattern string, tagsLimit int, tagsOrde
 		}
 	}
 	switch tagsOrder {
-	case "alphabetically":
+	case TAGS_ORDER_ALPHABETICALLY:
 		sort.Sort(RefsAlphabetically(rs))
-	case "reverse alphabetically":
+	case TAGS_ORDER_REVERSE_ALPHABETICALLY:
 		sort.Sort(RefsReverseAlphabetically(rs))
-	case "semver":
+	case TAGS_ORDER_SEMVER:
 		sort.Sort(RefsSemver(rs))
-	case "reverse semver":
+	case TAGS_ORDER_REVERSE_SEMVER:
 		sort.Sort(RefsReverseSemver(rs))
 	default:
 	}
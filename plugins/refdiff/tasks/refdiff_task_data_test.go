@@ -0,0 +1,87 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/apache/incubator-devlake/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func refNames(rs Refs) []string {
+	names := make([]string, len(rs))
+	for i, r := range rs {
+		names[i] = r.Name
+	}
+	return names
+}
+
+func TestRefsSemver(t *testing.T) {
+	rs := Refs{
+		{Name: "refs/tags/v1.10.0"},
+		{Name: "refs/tags/v1.2.0"},
+		{Name: "refs/tags/v1.10.0-rc.2"},
+		{Name: "refs/tags/v1.10.0-rc.10"},
+		{Name: "refs/tags/v1.10.0-beta"},
+		{Name: "refs/tags/v1.9"},
+		{Name: "refs/tags/v2.0.0+build.1"},
+	}
+	sort.Sort(RefsSemver(rs))
+	assert.Equal(t, []string{
+		"refs/tags/v1.2.0",
+		"refs/tags/v1.9",
+		"refs/tags/v1.10.0-beta",
+		"refs/tags/v1.10.0-rc.2",
+		"refs/tags/v1.10.0-rc.10",
+		"refs/tags/v1.10.0",
+		"refs/tags/v2.0.0+build.1",
+	}, refNames(rs))
+
+	sort.Sort(RefsReverseSemver(rs))
+	assert.Equal(t, "refs/tags/v2.0.0+build.1", rs[0].Name)
+	assert.Equal(t, "refs/tags/v1.10.0", rs[1].Name)
+	assert.Equal(t, "refs/tags/v1.2.0", rs[6].Name)
+
+	// names without a version are compared by their dot separated parts
+	rs = Refs{{Name: "release.b"}, {Name: "release.a"}}
+	sort.Sort(RefsSemver(rs))
+	assert.Equal(t, []string{"release.a", "release.b"}, refNames(rs))
+}
+
+func TestRefdiffPolicy(t *testing.T) {
+	policy := &RefdiffPolicy{RepoPattern: "^github:", TagsPattern: `^refs/tags/v\d+`, TagsLimit: 10, TagsOrder: TAGS_ORDER_REVERSE_SEMVER}
+	assert.Nil(t, policy.Valid())
+	assert.True(t, policy.Match("github:GithubRepo:1:1", "apache/devlake"))
+	assert.False(t, policy.Match("gitlab:GitlabProject:1:1", "apache/devlake"))
+	assert.True(t, (&RefdiffPolicy{}).Match("gitlab:GitlabProject:1:1", "apache/devlake"))
+	assert.True(t, (&RefdiffPolicy{RepoPattern: "devlake$"}).Match("gitlab:GitlabProject:1:1", "apache/devlake"))
+
+	assert.NotNil(t, (&RefdiffPolicy{RepoPattern: "("}).Valid())
+	assert.NotNil(t, (&RefdiffPolicy{TagsOrder: "newest"}).Valid())
+	assert.NotNil(t, (&RefdiffPolicy{Pairs: []RefPair{{NewRef: "refs/tags/v1.1"}}}).Valid())
+	// the tags pattern needs two tags at least to make a pair
+	for _, limit := range []int{0, 1} {
+		err := (&RefdiffPolicy{TagsPattern: `^refs/tags/v\d+`, TagsLimit: limit}).Valid()
+		if assert.NotNil(t, err) {
+			assert.Equal(t, errors.BadInput, err.GetType())
+		}
+	}
+	assert.Nil(t, (&RefdiffPolicy{TagsPattern: `^refs/tags/v\d+`, TagsLimit: 2}).Valid())
+}
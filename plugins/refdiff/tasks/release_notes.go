@@ -0,0 +1,542 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/apache/incubator-devlake/errors"
+	"github.com/apache/incubator-devlake/models/domainlayer/code"
+	"github.com/apache/incubator-devlake/models/domainlayer/crossdomain"
+	"github.com/apache/incubator-devlake/plugins/core/dal"
+)
+
+// RELEASE_NOTES_OTHERS is the group of pull requests without type or label, and of issues without type
+const RELEASE_NOTES_OTHERS = "Others"
+
+type ReleaseNotes struct {
+	RepoId            string                     `json:"repoId"`
+	NewRef            string                     `json:"newRef"`
+	OldRef            string                     `json:"oldRef"`
+	NewCommitSha      string                     `json:"newCommitSha"`
+	OldCommitSha      string                     `json:"oldCommitSha"`
+	CommitCount       int64                      `json:"commitCount"`
+	PullRequestGroups []ReleaseNotesPrGroup      `json:"pullRequestGroups"`
+	IssueGroups       []ReleaseNotesIssueGroup   `json:"issueGroups"`
+	CherryPicks       []ReleaseNotesCherryPick   `json:"cherryPicks"`
+	Contributors      []*ReleaseNotesContributor `json:"contributors"`
+}
+
+type ReleaseNotesPrGroup struct {
+	Name         string                    `json:"name"`
+	PullRequests []ReleaseNotesPullRequest `json:"pullRequests"`
+}
+
+type ReleaseNotesPullRequest struct {
+	Id             string     `json:"id"`
+	PullRequestKey int        `json:"pullRequestKey"`
+	Title          string     `json:"title"`
+	Url            string     `json:"url"`
+	Type           string     `json:"type"`
+	Labels         []string   `json:"labels"`
+	AuthorName     string     `json:"authorName"`
+	MergedDate     *time.Time `json:"mergedDate"`
+}
+
+type ReleaseNotesIssueGroup struct {
+	Type   string              `json:"type"`
+	Issues []ReleaseNotesIssue `json:"issues"`
+}
+
+type ReleaseNotesIssue struct {
+	Id       string `json:"id"`
+	IssueKey string `json:"issueKey"`
+	Title    string `json:"title"`
+	Url      string `json:"url"`
+	Type     string `json:"type"`
+	Status   string `json:"status"`
+}
+
+type ReleaseNotesCherryPick struct {
+	ParentPrId             string   `json:"parentPrId"`
+	ParentPrKey            int      `json:"parentPrKey"`
+	ParentPrUrl            string   `json:"parentPrUrl"`
+	CherrypickBaseBranches []string `json:"cherrypickBaseBranches"`
+	CherrypickPrKeys       []string `json:"cherrypickPrKeys"`
+}
+
+type ReleaseNotesContributor struct {
+	// UserId is empty when the author could not be resolved to a user through user_accounts
+	UserId           string `json:"userId"`
+	Name             string `json:"name"`
+	Email            string `json:"email"`
+	PullRequestCount int    `json:"pullRequestCount"`
+	CommitCount      int    `json:"commitCount"`
+}
+
+// ResolveRefPair converts the names of the new and old refs into a commit pair, tags could be given without the
+// `refs/tags/` prefix
+func ResolveRefPair(db dal.Dal, repoId string, newRef string, oldRef string) (RefCommitPair, errors.Error) {
+	newRefName, newSha, err := resolveRef(db, repoId, newRef)
+	if err != nil {
+		return RefCommitPair{}, err
+	}
+	oldRefName, oldSha, err := resolveRef(db, repoId, oldRef)
+	if err != nil {
+		return RefCommitPair{}, err
+	}
+	return RefCommitPair{newSha, oldSha, newRefName, oldRefName}, nil
+}
+
+func resolveRef(db dal.Dal, repoId string, refName string) (string, string, errors.Error) {
+	if refName == "" {
+		return "", "", errors.BadInput.New("ref name is empty")
+	}
+	for _, name := range []string{refName, "refs/tags/" + refName} {
+		ref := &code.Ref{}
+		err := db.First(ref, dal.Where("id = ?", fmt.Sprintf("%s:%s", repoId, name)))
+		if db.IsErrorNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return "", "", err
+		}
+		return ref.Name, ref.CommitSha, nil
+	}
+	return "", "", errors.NotFound.New(fmt.Sprintf("ref %s not found in repo %s", refName, repoId))
+}
+
+// IsCommitsDiffCalculated tells whether the commits diff of the pair has been calculated
+func IsCommitsDiffCalculated(db dal.Dal, pair RefCommitPair) (bool, errors.Error) {
+	if pair[0] == pair[1] {
+		return true, nil
+	}
+	count, err := db.Count(
+		dal.From(&code.FinishedCommitsDiff{}),
+		dal.Where("new_commit_sha = ? AND old_commit_sha = ?", pair[0], pair[1]),
+	)
+	return count > 0, err
+}
+
+// GenerateReleaseNotes collects the pull requests merged between the pair along with their issues, cherry-picks
+// and contributors, the commits diff of the pair must have been calculated
+func GenerateReleaseNotes(db dal.Dal, repoId string, pair RefCommitPair) (*ReleaseNotes, errors.Error) {
+	notes := &ReleaseNotes{
+		RepoId:            repoId,
+		NewRef:            pair[2],
+		OldRef:            pair[3],
+		NewCommitSha:      pair[0],
+		OldCommitSha:      pair[1],
+		PullRequestGroups: []ReleaseNotesPrGroup{},
+		IssueGroups:       []ReleaseNotesIssueGroup{},
+		CherryPicks:       []ReleaseNotesCherryPick{},
+		Contributors:      []*ReleaseNotesContributor{},
+	}
+	var err errors.Error
+	notes.CommitCount, err = db.Count(
+		dal.From(&code.CommitsDiff{}),
+		dal.Where("new_commit_sha = ? AND old_commit_sha = ?", pair[0], pair[1]),
+	)
+	if err != nil {
+		return nil, err
+	}
+	contributors := newContributorResolver()
+
+	// pull requests either merged by a commit in the range or having their commits in it, i.e. rebased
+	var prs []code.PullRequest
+	err = db.All(
+		&prs,
+		dal.From(&code.PullRequest{}),
+		dal.Where(
+			`base_repo_id = ? AND (
+				merge_commit_sha IN (SELECT commit_sha FROM commits_diffs WHERE new_commit_sha = ? AND old_commit_sha = ?)
+				OR EXISTS (
+					SELECT 1 FROM pull_request_commits prc
+					JOIN commits_diffs cd ON cd.commit_sha = prc.commit_sha
+					WHERE prc.pull_request_id = pull_requests.id AND cd.new_commit_sha = ? AND cd.old_commit_sha = ?
+				)
+			)`,
+			repoId, pair[0], pair[1], pair[0], pair[1],
+		),
+		dal.Orderby("pull_request_key"),
+	)
+	if err != nil {
+		return nil, err
+	}
+	prIds := make([]string, 0, len(prs))
+	authorIds := make([]string, 0, len(prs))
+	for _, pr := range prs {
+		prIds = append(prIds, pr.Id)
+		authorIds = append(authorIds, pr.AuthorId)
+	}
+	err = contributors.loadAccounts(db, authorIds)
+	if err != nil {
+		return nil, err
+	}
+	var labels []code.PullRequestLabel
+	if len(prIds) > 0 {
+		err = db.All(&labels, dal.Where("pull_request_id IN ?", prIds), dal.Orderby("label_name"))
+		if err != nil {
+			return nil, err
+		}
+	}
+	prLabels := make(map[string][]string)
+	for _, label := range labels {
+		prLabels[label.PullRequestId] = append(prLabels[label.PullRequestId], label.LabelName)
+	}
+	prGroups := make(map[string][]ReleaseNotesPullRequest)
+	for _, pr := range prs {
+		item := ReleaseNotesPullRequest{
+			Id:             pr.Id,
+			PullRequestKey: pr.PullRequestKey,
+			Title:          pr.Title,
+			Url:            pr.Url,
+			Type:           pr.Type,
+			Labels:         prLabels[pr.Id],
+			AuthorName:     pr.AuthorName,
+			MergedDate:     pr.MergedDate,
+		}
+		// the type is preferred, the first label is taken for the plugins which do not set the type
+		group := pr.Type
+		if group == "" && len(item.Labels) > 0 {
+			group = item.Labels[0]
+		}
+		if group == "" {
+			group = RELEASE_NOTES_OTHERS
+		}
+		prGroups[group] = append(prGroups[group], item)
+		contributor := contributors.byAccount(pr.AuthorId, pr.AuthorName)
+		if contributor != nil {
+			contributor.PullRequestCount++
+		}
+	}
+	for _, name := range sortedGroupNames(prGroups) {
+		notes.PullRequestGroups = append(notes.PullRequestGroups, ReleaseNotesPrGroup{Name: name, PullRequests: prGroups[name]})
+	}
+
+	// issues linked to the pull requests
+	if len(prIds) > 0 {
+		var issues []ReleaseNotesIssue
+		err = db.All(
+			&issues,
+			dal.Select("DISTINCT i.id, i.issue_key, i.title, i.url, i.type, i.status"),
+			dal.From("pull_request_issues pri"),
+			dal.Join("JOIN issues i ON i.id = pri.issue_id"),
+			dal.Where("pri.pull_request_id IN ?", prIds),
+			dal.Orderby("i.issue_key"),
+		)
+		if err != nil {
+			return nil, err
+		}
+		issueGroups := make(map[string][]ReleaseNotesIssue)
+		for _, issue := range issues {
+			group := issue.Type
+			if group == "" {
+				group = RELEASE_NOTES_OTHERS
+			}
+			issueGroups[group] = append(issueGroups[group], issue)
+		}
+		for _, name := range sortedGroupNames(issueGroups) {
+			notes.IssueGroups = append(notes.IssueGroups, ReleaseNotesIssueGroup{Type: name, Issues: issueGroups[name]})
+		}
+
+		var cherryPicks []code.RefsPrCherrypick
+		err = db.All(&cherryPicks, dal.Where("parent_pr_id IN ?", prIds), dal.Orderby("parent_pr_key"))
+		if err != nil {
+			return nil, err
+		}
+		for _, cherryPick := range cherryPicks {
+			notes.CherryPicks = append(notes.CherryPicks, ReleaseNotesCherryPick{
+				ParentPrId:             cherryPick.ParentPrId,
+				ParentPrKey:            cherryPick.ParentPrKey,
+				ParentPrUrl:            cherryPick.ParentPrUrl,
+				CherrypickBaseBranches: splitNonEmpty(cherryPick.CherrypickBaseBranches),
+				CherrypickPrKeys:       splitNonEmpty(cherryPick.CherrypickPrKeys),
+			})
+		}
+	}
+
+	// commit authors, matched to users by email
+	var commits []code.Commit
+	err = db.All(
+		&commits,
+		dal.Select("c.sha, c.author_name, c.author_email"),
+		dal.From("commits c"),
+		dal.Join("JOIN commits_diffs cd ON cd.commit_sha = c.sha"),
+		dal.Where("cd.new_commit_sha = ? AND cd.old_commit_sha = ?", pair[0], pair[1]),
+		dal.Orderby("cd.sorting_index"),
+	)
+	if err != nil {
+		return nil, err
+	}
+	emails := make([]string, 0, len(commits))
+	for _, commit := range commits {
+		emails = append(emails, commit.AuthorEmail)
+	}
+	err = contributors.loadEmails(db, emails)
+	if err != nil {
+		return nil, err
+	}
+	for _, commit := range commits {
+		contributor := contributors.byEmail(commit.AuthorEmail, commit.AuthorName)
+		if contributor != nil {
+			contributor.CommitCount++
+		}
+	}
+	notes.Contributors = contributors.list
+	return notes, nil
+}
+
+// contributorResolver merges the authors of pull requests and commits into users through user_accounts
+type contributorResolver struct {
+	accounts     map[string]crossdomain.Account
+	accountUsers map[string]string
+	emailUsers   map[string]string
+	users        map[string]crossdomain.User
+	contributors map[string]*ReleaseNotesContributor
+	list         []*ReleaseNotesContributor
+}
+
+func newContributorResolver() *contributorResolver {
+	return &contributorResolver{
+		accounts:     make(map[string]crossdomain.Account),
+		accountUsers: make(map[string]string),
+		emailUsers:   make(map[string]string),
+		users:        make(map[string]crossdomain.User),
+		contributors: make(map[string]*ReleaseNotesContributor),
+		list:         []*ReleaseNotesContributor{},
+	}
+}
+
+func (r *contributorResolver) loadAccounts(db dal.Dal, accountIds []string) errors.Error {
+	if len(accountIds) == 0 {
+		return nil
+	}
+	var accounts []crossdomain.Account
+	err := db.All(&accounts, dal.Where("id IN ?", accountIds))
+	if err != nil {
+		return err
+	}
+	for _, account := range accounts {
+		r.accounts[account.Id] = account
+	}
+	var userAccounts []crossdomain.UserAccount
+	err = db.All(&userAccounts, dal.Where("account_id IN ?", accountIds))
+	if err != nil {
+		return err
+	}
+	userIds := make([]string, 0, len(userAccounts))
+	for _, userAccount := range userAccounts {
+		r.accountUsers[userAccount.AccountId] = userAccount.UserId
+		userIds = append(userIds, userAccount.UserId)
+	}
+	return r.loadUsers(db, userIds)
+}
+
+func (r *contributorResolver) loadEmails(db dal.Dal, emails []string) errors.Error {
+	if len(emails) == 0 {
+		return nil
+	}
+	var users []crossdomain.User
+	err := db.All(&users, dal.Where("email IN ?", emails))
+	if err != nil {
+		return err
+	}
+	for _, user := range users {
+		r.users[user.Id] = user
+		r.emailUsers[user.Email] = user.Id
+	}
+	// the email might only be known by one of the accounts of the user
+	var accountEmails []struct {
+		UserId string
+		Email  string
+	}
+	err = db.All(
+		&accountEmails,
+		dal.Select("ua.user_id, a.email"),
+		dal.From("user_accounts ua"),
+		dal.Join("JOIN accounts a ON a.id = ua.account_id"),
+		dal.Where("a.email IN ?", emails),
+	)
+	if err != nil {
+		return err
+	}
+	userIds := make([]string, 0, len(accountEmails))
+	for _, accountEmail := range accountEmails {
+		if _, ok := r.emailUsers[accountEmail.Email]; !ok {
+			r.emailUsers[accountEmail.Email] = accountEmail.UserId
+			userIds = append(userIds, accountEmail.UserId)
+		}
+	}
+	return r.loadUsers(db, userIds)
+}
+
+func (r *contributorResolver) loadUsers(db dal.Dal, userIds []string) errors.Error {
+	if len(userIds) == 0 {
+		return nil
+	}
+	var users []crossdomain.User
+	err := db.All(&users, dal.Where("id IN ?", userIds))
+	if err != nil {
+		return err
+	}
+	for _, user := range users {
+		r.users[user.Id] = user
+	}
+	return nil
+}
+
+func (r *contributorResolver) byAccount(accountId string, authorName string) *ReleaseNotesContributor {
+	if userId, ok := r.accountUsers[accountId]; ok {
+		return r.byUser(userId)
+	}
+	if accountId == "" && authorName == "" {
+		return nil
+	}
+	account := r.accounts[accountId]
+	name := firstNonEmpty(account.FullName, account.UserName, authorName, accountId)
+	return r.get("account:"+accountId, "", name, account.Email)
+}
+
+func (r *contributorResolver) byEmail(email string, authorName string) *ReleaseNotesContributor {
+	if userId, ok := r.emailUsers[email]; ok {
+		return r.byUser(userId)
+	}
+	if email == "" && authorName == "" {
+		return nil
+	}
+	return r.get("email:"+email, "", firstNonEmpty(authorName, email), email)
+}
+
+func (r *contributorResolver) byUser(userId string) *ReleaseNotesContributor {
+	user := r.users[userId]
+	return r.get("user:"+userId, userId, firstNonEmpty(user.Name, user.Email, userId), user.Email)
+}
+
+func (r *contributorResolver) get(key, userId, name, email string) *ReleaseNotesContributor {
+	if contributor, ok := r.contributors[key]; ok {
+		return contributor
+	}
+	contributor := &ReleaseNotesContributor{UserId: userId, Name: name, Email: email}
+	r.contributors[key] = contributor
+	r.list = append(r.list, contributor)
+	return contributor
+}
+
+// Markdown renders the release notes for changelogs or release pages
+func (notes *ReleaseNotes) Markdown() string {
+	sb := &strings.Builder{}
+	fmt.Fprintf(sb, "# %s\n\n", shortRefName(notes.NewRef))
+	prCount := 0
+	for _, group := range notes.PullRequestGroups {
+		prCount += len(group.PullRequests)
+	}
+	fmt.Fprintf(sb, "Changes since %s: %d commits, %d pull requests.\n", shortRefName(notes.OldRef), notes.CommitCount, prCount)
+	if len(notes.PullRequestGroups) > 0 {
+		sb.WriteString("\n## Pull Requests\n")
+		for _, group := range notes.PullRequestGroups {
+			fmt.Fprintf(sb, "\n### %s\n\n", group.Name)
+			for _, pr := range group.PullRequests {
+				fmt.Fprintf(sb, "- %s %s", markdownLink(fmt.Sprintf("#%d", pr.PullRequestKey), pr.Url), pr.Title)
+				if pr.AuthorName != "" {
+					fmt.Fprintf(sb, " by @%s", pr.AuthorName)
+				}
+				sb.WriteString("\n")
+			}
+		}
+	}
+	if len(notes.IssueGroups) > 0 {
+		sb.WriteString("\n## Issues\n")
+		for _, group := range notes.IssueGroups {
+			fmt.Fprintf(sb, "\n### %s\n\n", group.Type)
+			for _, issue := range group.Issues {
+				fmt.Fprintf(sb, "- %s %s\n", markdownLink(issue.IssueKey, issue.Url), issue.Title)
+			}
+		}
+	}
+	if len(notes.CherryPicks) > 0 {
+		sb.WriteString("\n## Cherry-picks\n\n")
+		for _, cherryPick := range notes.CherryPicks {
+			prKeys := make([]string, 0, len(cherryPick.CherrypickPrKeys))
+			for _, key := range cherryPick.CherrypickPrKeys {
+				prKeys = append(prKeys, "#"+key)
+			}
+			fmt.Fprintf(
+				sb, "- %s cherry-picked into %s by %s\n",
+				markdownLink(fmt.Sprintf("#%d", cherryPick.ParentPrKey), cherryPick.ParentPrUrl),
+				strings.Join(cherryPick.CherrypickBaseBranches, ", "),
+				strings.Join(prKeys, ", "),
+			)
+		}
+	}
+	if len(notes.Contributors) > 0 {
+		sb.WriteString("\n## Contributors\n\n")
+		for _, contributor := range notes.Contributors {
+			fmt.Fprintf(sb, "- %s (%d pull requests, %d commits)\n", contributor.Name, contributor.PullRequestCount, contributor.CommitCount)
+		}
+	}
+	return sb.String()
+}
+
+// sortedGroupNames returns the names in alphabetical order with RELEASE_NOTES_OTHERS at last
+func sortedGroupNames[T any](groups map[string][]T) []string {
+	names := make([]string, 0, len(groups))
+	for name := range groups {
+		if name != RELEASE_NOTES_OTHERS {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	if _, ok := groups[RELEASE_NOTES_OTHERS]; ok {
+		names = append(names, RELEASE_NOTES_OTHERS)
+	}
+	return names
+}
+
+func markdownLink(text string, url string) string {
+	if url == "" {
+		return text
+	}
+	return fmt.Sprintf("[%s](%s)", text, url)
+}
+
+func shortRefName(refName string) string {
+	return strings.TrimPrefix(strings.TrimPrefix(refName, "refs/tags/"), "refs/heads/")
+}
+
+func splitNonEmpty(s string) []string {
+	result := []string{}
+	for _, part := range strings.Split(s, ",") {
+		if part != "" {
+			result = append(result, part)
+		}
+	}
+	return result
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
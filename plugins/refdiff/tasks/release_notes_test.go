@@ -0,0 +1,88 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReleaseNotesMarkdown(t *testing.T) {
+	notes := &ReleaseNotes{
+		NewRef:      "refs/tags/v1.1",
+		OldRef:      "refs/tags/v1.0",
+		CommitCount: 3,
+		PullRequestGroups: []ReleaseNotesPrGroup{
+			{Name: "feature", PullRequests: []ReleaseNotesPullRequest{
+				{PullRequestKey: 1, Title: "Add login", Url: "https://github.com/o/r/pull/1", AuthorName: "alice"},
+			}},
+			{Name: RELEASE_NOTES_OTHERS, PullRequests: []ReleaseNotesPullRequest{
+				{PullRequestKey: 4, Title: "Bump deps"},
+			}},
+		},
+		IssueGroups: []ReleaseNotesIssueGroup{
+			{Type: "REQUIREMENT", Issues: []ReleaseNotesIssue{
+				{IssueKey: "11", Title: "Support login", Url: "https://github.com/o/r/issues/11"},
+			}},
+		},
+		CherryPicks: []ReleaseNotesCherryPick{
+			{ParentPrKey: 1, ParentPrUrl: "https://github.com/o/r/pull/1", CherrypickBaseBranches: []string{"release-1.0"}, CherrypickPrKeys: []string{"5"}},
+		},
+		Contributors: []*ReleaseNotesContributor{
+			{Name: "Alice", PullRequestCount: 1, CommitCount: 2},
+		},
+	}
+	assert.Equal(t, `# v1.1
+
+Changes since v1.0: 3 commits, 2 pull requests.
+
+## Pull Requests
+
+### feature
+
+- [#1](https://github.com/o/r/pull/1) Add login by @alice
+
+### Others
+
+- #4 Bump deps
+
+## Issues
+
+### REQUIREMENT
+
+- [11](https://github.com/o/r/issues/11) Support login
+
+## Cherry-picks
+
+- [#1](https://github.com/o/r/pull/1) cherry-picked into release-1.0 by #5
+
+## Contributors
+
+- Alice (1 pull requests, 2 commits)
+`, notes.Markdown())
+}
+
+func TestSortedGroupNames(t *testing.T) {
+	groups := map[string][]int{
+		RELEASE_NOTES_OTHERS: {1},
+		"feature":            {2},
+		"bug":                {3},
+	}
+	assert.Equal(t, []string{"bug", "feature", RELEASE_NOTES_OTHERS}, sortedGroupNames(groups))
+}
@@ -0,0 +1,198 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/apache/incubator-devlake/errors"
+	"github.com/apache/incubator-devlake/models/domainlayer/crossdomain"
+	"github.com/apache/incubator-devlake/models/domainlayer/ticket"
+	"github.com/apache/incubator-devlake/plugins/core"
+	"github.com/apache/incubator-devlake/plugins/core/dal"
+)
+
+type versionIssue struct {
+	IssueId  string
+	IssueKey string
+}
+
+// normalizeVersionName reduces both ref names and version names to a comparable form,
+// e.g. `refs/tags/v1.2.0`, `v1.2.0` and `1.2.0` all become `1.2.0`
+func normalizeVersionName(name string) string {
+	name = strings.TrimSpace(name)
+	name = strings.TrimPrefix(name, "refs/tags/")
+	name = strings.TrimPrefix(name, "refs/heads/")
+	name = strings.ToLower(name)
+	if len(name) > 1 && name[0] == 'v' && name[1] >= '0' && name[1] <= '9' {
+		name = name[1:]
+	}
+	return name
+}
+
+// mergeVersionIssues reconciles the issues of a version with the issues found between a ref pair
+func mergeVersionIssues(
+	versionId, newRefId, oldRefId string,
+	inVersion []versionIssue,
+	inRefsDiff []crossdomain.RefsIssuesDiffs,
+) []*crossdomain.VersionsIssuesDiffs {
+	results := make([]*crossdomain.VersionsIssuesDiffs, 0, len(inVersion)+len(inRefsDiff))
+	byIssueId := make(map[string]*crossdomain.VersionsIssuesDiffs)
+	for _, issue := range inVersion {
+		if _, ok := byIssueId[issue.IssueId]; ok {
+			continue
+		}
+		diff := &crossdomain.VersionsIssuesDiffs{
+			VersionId:   versionId,
+			NewRefId:    newRefId,
+			OldRefId:    oldRefId,
+			IssueId:     issue.IssueId,
+			IssueNumber: issue.IssueKey,
+			InVersion:   true,
+		}
+		byIssueId[issue.IssueId] = diff
+		results = append(results, diff)
+	}
+	for _, issue := range inRefsDiff {
+		if diff, ok := byIssueId[issue.IssueId]; ok {
+			diff.InRefsDiff = true
+			continue
+		}
+		diff := &crossdomain.VersionsIssuesDiffs{
+			VersionId:   versionId,
+			NewRefId:    newRefId,
+			OldRefId:    oldRefId,
+			IssueId:     issue.IssueId,
+			IssueNumber: issue.IssueNumber,
+			InRefsDiff:  true,
+		}
+		byIssueId[issue.IssueId] = diff
+		results = append(results, diff)
+	}
+	return results
+}
+
+// CalculateVersionsIssuesDiff links ticket versions to the ref pairs whose new ref carries the same name,
+// and records which issues were shipped according to the ticket tool, to git, or to both
+func CalculateVersionsIssuesDiff(taskCtx core.SubTaskContext) errors.Error {
+	data := taskCtx.GetData().(*RefdiffTaskData)
+	repoId := data.Options.RepoId
+	db := taskCtx.GetDal()
+	logger := taskCtx.GetLogger()
+
+	if data.Options.ProjectName != "" {
+		return nil
+	}
+
+	boardIds := data.Options.BoardIds
+	if len(boardIds) == 0 {
+		// the boards of the projects the repo belongs to
+		err := db.Pluck(
+			"pm_board.row_id",
+			&boardIds,
+			dal.From("project_mapping pm_repo"),
+			dal.Join("JOIN project_mapping pm_board ON pm_board.project_name = pm_repo.project_name"),
+			dal.Where("pm_repo.`table` = 'repos' AND pm_repo.row_id = ? AND pm_board.`table` = 'boards'", repoId),
+		)
+		if err != nil {
+			return err
+		}
+	}
+	if len(boardIds) == 0 {
+		logger.Info("no boards found for repo %s, skip linking versions to refs", repoId)
+		return nil
+	}
+
+	var versions []ticket.Version
+	err := db.All(
+		&versions,
+		dal.Select("DISTINCT v.id, v.name"),
+		dal.From("versions v"),
+		dal.Join("JOIN issue_fix_versions ifv ON ifv.version_id = v.id"),
+		dal.Join("JOIN board_issues bi ON bi.issue_id = ifv.issue_id"),
+		dal.Where("bi.board_id IN ?", boardIds),
+	)
+	if err != nil {
+		return err
+	}
+	if len(versions) == 0 {
+		logger.Info("no versions found on boards %v, skip linking versions to refs", boardIds)
+		return nil
+	}
+	versionIdsByName := make(map[string][]string)
+	for _, version := range versions {
+		name := normalizeVersionName(version.Name)
+		versionIdsByName[name] = append(versionIdsByName[name], version.Id)
+	}
+
+	taskCtx.SetProgress(0, len(data.Options.AllPairs))
+	for _, pair := range data.Options.AllPairs {
+		newRefId := fmt.Sprintf("%s:%s", repoId, pair[2])
+		oldRefId := fmt.Sprintf("%s:%s", repoId, pair[3])
+		versionIds := versionIdsByName[normalizeVersionName(pair[2])]
+		if len(versionIds) == 0 {
+			taskCtx.IncProgress(1)
+			continue
+		}
+		var refsIssuesDiffs []crossdomain.RefsIssuesDiffs
+		err = db.All(&refsIssuesDiffs, dal.Where("new_ref_id = ? AND old_ref_id = ?", newRefId, oldRefId))
+		if err != nil {
+			return err
+		}
+		err = db.Delete(
+			&crossdomain.VersionsIssuesDiffs{},
+			dal.Where("new_ref_id = ? AND old_ref_id = ?", newRefId, oldRefId),
+		)
+		if err != nil {
+			return err
+		}
+		for _, versionId := range versionIds {
+			var issues []versionIssue
+			err = db.All(
+				&issues,
+				dal.Select("ifv.issue_id, i.issue_key"),
+				dal.From("issue_fix_versions ifv"),
+				dal.Join("LEFT JOIN issues i ON i.id = ifv.issue_id"),
+				dal.Where("ifv.version_id = ?", versionId),
+			)
+			if err != nil {
+				return err
+			}
+			diffs := mergeVersionIssues(versionId, newRefId, oldRefId, issues, refsIssuesDiffs)
+			if len(diffs) == 0 {
+				continue
+			}
+			err = db.CreateOrUpdate(diffs)
+			if err != nil {
+				return err
+			}
+			logger.Info("linked version %s to refs [new][%s] [old][%s] with %d issues", versionId, newRefId, oldRefId, len(diffs))
+		}
+		taskCtx.IncProgress(1)
+	}
+	return nil
+}
+
+var CalculateVersionsIssuesDiffMeta = core.SubTaskMeta{
+	Name:             "calculateVersionsIssuesDiff",
+	EntryPoint:       CalculateVersionsIssuesDiff,
+	EnabledByDefault: true,
+	Description:      "Link ticket versions to ref pairs and reconcile their issues with refs_issues_diffs",
+	DomainTypes:      []string{core.DOMAIN_TYPE_CODE, core.DOMAIN_TYPE_TICKET},
+}
@@ -0,0 +1,60 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"testing"
+
+	"github.com/apache/incubator-devlake/models/domainlayer/crossdomain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeVersionName(t *testing.T) {
+	assert.Equal(t, "1.2.0", normalizeVersionName("refs/tags/v1.2.0"))
+	assert.Equal(t, "1.2.0", normalizeVersionName("V1.2.0"))
+	assert.Equal(t, "1.2.0", normalizeVersionName(" 1.2.0 "))
+	assert.Equal(t, "release-1.2", normalizeVersionName("refs/heads/release-1.2"))
+	assert.Equal(t, "vnext", normalizeVersionName("vNext"))
+}
+
+func TestMergeVersionIssues(t *testing.T) {
+	inVersion := []versionIssue{
+		{IssueId: "jira:JiraIssue:1:10", IssueKey: "DL-10"},
+		{IssueId: "jira:JiraIssue:1:11", IssueKey: "DL-11"},
+	}
+	inRefsDiff := []crossdomain.RefsIssuesDiffs{
+		{IssueId: "jira:JiraIssue:1:11", IssueNumber: "DL-11"},
+		{IssueId: "jira:JiraIssue:1:12", IssueNumber: "DL-12"},
+	}
+	diffs := mergeVersionIssues("jira:JiraVersion:1:100", "repo:refs/tags/v1.1", "repo:refs/tags/v1.0", inVersion, inRefsDiff)
+	assert.Len(t, diffs, 3)
+
+	assert.Equal(t, "DL-10", diffs[0].IssueNumber)
+	assert.True(t, diffs[0].InVersion)
+	assert.False(t, diffs[0].InRefsDiff)
+
+	assert.Equal(t, "DL-11", diffs[1].IssueNumber)
+	assert.True(t, diffs[1].InVersion)
+	assert.True(t, diffs[1].InRefsDiff)
+
+	assert.Equal(t, "DL-12", diffs[2].IssueNumber)
+	assert.False(t, diffs[2].InVersion)
+	assert.True(t, diffs[2].InRefsDiff)
+	assert.Equal(t, "repo:refs/tags/v1.1", diffs[2].NewRefId)
+	assert.Equal(t, "jira:JiraVersion:1:100", diffs[2].VersionId)
+}
@@ -0,0 +1,205 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"container/heap"
+
+	"github.com/apache/incubator-devlake/errors"
+)
+
+const (
+	reachableFromOld uint8 = 1 << iota
+	reachableFromNew
+)
+
+// CommitGraphLoader loads the parts of the commit graph visited by a walk on demand
+type CommitGraphLoader interface {
+	// LoadGenerations returns the generation numbers of the given commits, unknown ones are left out
+	LoadGenerations(shas []string) (map[string]int, errors.Error)
+	// LoadParents returns the parent shas of the given commits
+	LoadParents(shas []string) (map[string][]string, errors.Error)
+}
+
+// CommitGraphWalker answers ref-pair diffs by walking the commit graph from both commits in the order of
+// descending generation numbers, so only the commits down to the merge bases are ever loaded
+type CommitGraphWalker struct {
+	loader    CommitGraphLoader
+	batchSize int
+}
+
+func NewCommitGraphWalker(loader CommitGraphLoader, batchSize int) *CommitGraphWalker {
+	if batchSize < 1 {
+		batchSize = 1
+	}
+	return &CommitGraphWalker{
+		loader:    loader,
+		batchSize: batchSize,
+	}
+}
+
+type walkNode struct {
+	sha        string
+	generation int
+	flags      uint8
+	parents    []string
+	loaded     bool
+	popped     bool
+}
+
+// walkQueue is a max-heap of generation numbers, ties are broken by sha to keep the output stable
+type walkQueue []*walkNode
+
+func (q walkQueue) Len() int { return len(q) }
+func (q walkQueue) Less(i, j int) bool {
+	if q[i].generation != q[j].generation {
+		return q[i].generation > q[j].generation
+	}
+	return q[i].sha < q[j].sha
+}
+func (q walkQueue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *walkQueue) Push(x interface{}) { *q = append(*q, x.(*walkNode)) }
+func (q *walkQueue) Pop() interface{} {
+	old := *q
+	node := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return node
+}
+
+type commitGraphWalk struct {
+	*CommitGraphWalker
+	nodes       map[string]*walkNode
+	generations map[string]int
+	queue       walkQueue
+	// unresolved counts the queued commits not known to be reachable from the old commit yet,
+	// the walk is over once it drops to zero since every remaining commit is then an ancestor of the old one
+	unresolved int
+}
+
+// CalculateLostSha calculates the commits which newSha has but oldSha does not have, newest first,
+// along with the number of visited commits reachable from oldSha and the number of lost commits
+func (w *CommitGraphWalker) CalculateLostSha(oldSha string, newSha string) ([]string, int, int, errors.Error) {
+	walk := &commitGraphWalk{
+		CommitGraphWalker: w,
+		nodes:             make(map[string]*walkNode),
+	}
+	generations, err := w.loader.LoadGenerations([]string{oldSha, newSha})
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	walk.generations = generations
+	walk.push(newSha, walk.generations[newSha], reachableFromNew)
+	walk.push(oldSha, walk.generations[oldSha], reachableFromOld)
+
+	var lostSha []string
+	oldCount := 0
+	for walk.queue.Len() > 0 && walk.unresolved > 0 {
+		node := heap.Pop(&walk.queue).(*walkNode)
+		node.popped = true
+		if node.flags&reachableFromOld == 0 {
+			walk.unresolved--
+			lostSha = append(lostSha, node.sha)
+		} else {
+			oldCount++
+		}
+		if !node.loaded {
+			err = walk.load(node)
+			if err != nil {
+				return nil, 0, 0, err
+			}
+		}
+		for _, parent := range node.parents {
+			generation, ok := walk.generations[parent]
+			if !ok {
+				// parents missing from the index, i.e. beyond a shallow clone, still have to come after their children
+				generation = node.generation - 1
+				if generation < 0 {
+					generation = 0
+				}
+			}
+			walk.push(parent, generation, node.flags)
+		}
+	}
+	return lostSha, oldCount, len(lostSha), nil
+}
+
+func (walk *commitGraphWalk) push(sha string, generation int, flags uint8) {
+	node, ok := walk.nodes[sha]
+	if !ok {
+		node = &walkNode{
+			sha:        sha,
+			generation: generation,
+			flags:      flags,
+		}
+		walk.nodes[sha] = node
+		heap.Push(&walk.queue, node)
+		if flags&reachableFromOld == 0 {
+			walk.unresolved++
+		}
+		return
+	}
+	if node.popped {
+		return
+	}
+	if node.flags&reachableFromOld == 0 && flags&reachableFromOld != 0 {
+		walk.unresolved--
+	}
+	node.flags |= flags
+}
+
+// load fetches the parents of the node along with the ones of other queued nodes to save round trips
+func (walk *commitGraphWalk) load(node *walkNode) errors.Error {
+	batch := []*walkNode{node}
+	for _, queued := range walk.queue {
+		if len(batch) >= walk.batchSize {
+			break
+		}
+		if !queued.loaded {
+			batch = append(batch, queued)
+		}
+	}
+	shas := make([]string, len(batch))
+	for i, n := range batch {
+		shas[i] = n.sha
+	}
+	parents, err := walk.loader.LoadParents(shas)
+	if err != nil {
+		return err
+	}
+	var unknown []string
+	for _, n := range batch {
+		n.parents = parents[n.sha]
+		n.loaded = true
+		for _, parent := range n.parents {
+			if _, ok := walk.generations[parent]; !ok {
+				unknown = append(unknown, parent)
+			}
+		}
+	}
+	if len(unknown) == 0 {
+		return nil
+	}
+	generations, err := walk.loader.LoadGenerations(unknown)
+	if err != nil {
+		return err
+	}
+	for sha, generation := range generations {
+		walk.generations[sha] = generation
+	}
+	return nil
+}
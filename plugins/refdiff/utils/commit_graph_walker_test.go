@@ -0,0 +1,113 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"testing"
+
+	"github.com/apache/incubator-devlake/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+type memoryCommitGraph struct {
+	parents     map[string][]string
+	generations map[string]int
+	loaded      map[string]bool
+}
+
+func (g *memoryCommitGraph) LoadGenerations(shas []string) (map[string]int, errors.Error) {
+	generations := make(map[string]int)
+	for _, sha := range shas {
+		if generation, ok := g.generations[sha]; ok {
+			generations[sha] = generation
+		}
+	}
+	return generations, nil
+}
+
+func (g *memoryCommitGraph) LoadParents(shas []string) (map[string][]string, errors.Error) {
+	parents := make(map[string][]string)
+	for _, sha := range shas {
+		g.loaded[sha] = true
+		parents[sha] = g.parents[sha]
+	}
+	return parents, nil
+}
+
+// newMemoryCommitGraph builds the graph
+//
+//	a - b - c - d - e - h   (main)
+//	     \     /
+//	      f - g             (feature)
+//
+// where x is an unrelated root and y is a commit on top of a missing parent
+func newMemoryCommitGraph() *memoryCommitGraph {
+	return &memoryCommitGraph{
+		parents: map[string][]string{
+			"b": {"a"},
+			"c": {"b"},
+			"f": {"b"},
+			"g": {"f"},
+			"d": {"c", "g"},
+			"e": {"d"},
+			"h": {"e"},
+			"y": {"z"},
+		},
+		generations: map[string]int{
+			"a": 1, "b": 2, "c": 3, "f": 3, "g": 4, "d": 5, "e": 6, "h": 7, "x": 1, "y": 1,
+		},
+		loaded: make(map[string]bool),
+	}
+}
+
+func TestCommitGraphWalkerCalculateLostSha(t *testing.T) {
+	graph := newMemoryCommitGraph()
+	walker := NewCommitGraphWalker(graph, 2)
+
+	lostSha, oldCount, newCount, err := walker.CalculateLostSha("c", "h")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"h", "e", "d", "g", "f"}, lostSha)
+	assert.Equal(t, 5, newCount)
+	assert.Equal(t, 1, oldCount)
+	// the walk stops at the merge base, the history below it is never loaded
+	assert.False(t, graph.loaded["a"])
+
+	lostSha, _, _, err = walker.CalculateLostSha("g", "e")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"e", "d", "c"}, lostSha)
+
+	// nothing is lost when the new commit is an ancestor of the old one
+	lostSha, _, _, err = walker.CalculateLostSha("h", "c")
+	assert.Nil(t, err)
+	assert.Empty(t, lostSha)
+
+	// unrelated histories lose the whole new side
+	lostSha, _, _, err = walker.CalculateLostSha("x", "c")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"c", "b", "a"}, lostSha)
+
+	// parents missing from the graph are still walked
+	lostSha, _, _, err = walker.CalculateLostSha("a", "y")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"y", "z"}, lostSha)
+
+	// unknown commits are treated as roots
+	lostSha, _, _, err = walker.CalculateLostSha("a", "unknown")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"unknown"}, lostSha)
+}
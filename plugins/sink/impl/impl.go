@@ -0,0 +1,77 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package impl
+
+import (
+	"fmt"
+
+	"github.com/apache/incubator-devlake/errors"
+	"github.com/apache/incubator-devlake/helpers/pluginhelper/sink"
+	"github.com/apache/incubator-devlake/plugins/core"
+	"github.com/apache/incubator-devlake/plugins/core/dal"
+	"github.com/apache/incubator-devlake/plugins/helper"
+	"github.com/apache/incubator-devlake/plugins/sink/tasks"
+)
+
+// make sure interface is implemented
+var _ core.PluginMeta = (*Sink)(nil)
+var _ core.PluginTask = (*Sink)(nil)
+var _ core.PluginModel = (*Sink)(nil)
+
+type Sink struct{}
+
+func (plugin Sink) Description() string {
+	return "Sync data from database to analytics targets like StarRocks, ClickHouse, MySQL or PostgreSQL"
+}
+
+func (plugin Sink) SubTaskMetas() []core.SubTaskMeta {
+	return []core.SubTaskMeta{
+		tasks.LoadDataMeta,
+	}
+}
+
+func (plugin Sink) PrepareTaskData(taskCtx core.TaskContext, options map[string]interface{}) (interface{}, errors.Error) {
+	op := &tasks.SinkOptions{}
+	err := helper.Decode(options, op, nil)
+	if err != nil {
+		return nil, err
+	}
+	if op.Target == "" {
+		return nil, errors.BadInput.New("target is required")
+	}
+	// fail early on unknown targets and invalid table patterns
+	_, err = sink.MatchTables(nil, op.Tables)
+	if err != nil {
+		return nil, err
+	}
+	if !sink.HasDriver(op.Target) {
+		return nil, errors.BadInput.New(fmt.Sprintf("unsupported target %s, available ones are %v", op.Target, sink.Drivers()))
+	}
+	return &tasks.SinkTaskData{
+		Options:       op,
+		TargetOptions: options,
+	}, nil
+}
+
+func (plugin Sink) GetTablesInfo() []dal.Tabler {
+	return []dal.Tabler{}
+}
+
+func (plugin Sink) RootPkgPath() string {
+	return "github.com/apache/incubator-devlake/plugins/sink"
+}
@@ -0,0 +1,66 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"github.com/apache/incubator-devlake/plugins/sink/impl"
+	"github.com/apache/incubator-devlake/runner"
+	"github.com/spf13/cobra"
+)
+
+// PluginEntry exports for Framework to search and load
+var PluginEntry impl.Sink //nolint
+
+// standalone mode for debugging
+func main() {
+	cmd := &cobra.Command{Use: "sink"}
+	_ = cmd.MarkFlagRequired("target")
+	target := cmd.Flags().StringP("target", "t", "", "target: starrocks, clickhouse, mysql or postgres")
+	tables := cmd.Flags().StringArrayP("table", "b", []string{}, "regular expressions of the tables to be loaded")
+	domainLayer := cmd.Flags().StringP("domain_layer", "l", "", "load the tables of the domain layer")
+	updateColumn := cmd.Flags().StringP("update_column", "u", "", "skip the tables whose latest value of the column is loaded")
+	batchSize := cmd.Flags().IntP("batch_size", "s", 1000, "rows per batch")
+	host := cmd.Flags().StringP("host", "H", "", "starrocks host")
+	port := cmd.Flags().IntP("port", "p", 9030, "starrocks port")
+	beHost := cmd.Flags().StringP("be_host", "", "", "starrocks be host")
+	bePort := cmd.Flags().IntP("be_port", "", 8040, "starrocks be port")
+	endpoint := cmd.Flags().StringP("endpoint", "e", "", "clickhouse http endpoint")
+	user := cmd.Flags().StringP("user", "U", "", "target user")
+	password := cmd.Flags().StringP("password", "P", "", "target password")
+	database := cmd.Flags().StringP("database", "d", "", "target database")
+	dsn := cmd.Flags().StringP("dsn", "D", "", "mysql or postgres target dsn")
+	cmd.Run = func(cmd *cobra.Command, args []string) {
+		runner.DirectRun(cmd, args, PluginEntry, map[string]interface{}{
+			"target":        *target,
+			"tables":        *tables,
+			"domain_layer":  *domainLayer,
+			"update_column": *updateColumn,
+			"batch_size":    *batchSize,
+			"host":          *host,
+			"port":          *port,
+			"be_host":       *beHost,
+			"be_port":       *bePort,
+			"endpoint":      *endpoint,
+			"user":          *user,
+			"password":      *password,
+			"database":      *database,
+			"dsn":           *dsn,
+		})
+	}
+	runner.RunCmd(cmd)
+}
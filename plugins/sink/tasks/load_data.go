@@ -0,0 +1,54 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"github.com/apache/incubator-devlake/errors"
+	"github.com/apache/incubator-devlake/helpers/pluginhelper/sink"
+	"github.com/apache/incubator-devlake/plugins/core"
+)
+
+// LoadData loads the selected tables into the target
+func LoadData(taskCtx core.SubTaskContext) errors.Error {
+	data := taskCtx.GetData().(*SinkTaskData)
+	options := &data.Options.Options
+	db, closeSource, err := sink.OpenSource(taskCtx.GetDal(), options)
+	if err != nil {
+		return err
+	}
+	if closeSource != nil {
+		defer closeSource()
+	}
+	tables, err := sink.SelectTables(db, options)
+	if err != nil {
+		return err
+	}
+	driver, err := sink.NewDriver(data.Options.Target, data.TargetOptions, taskCtx.GetLogger())
+	if err != nil {
+		return err
+	}
+	defer driver.Close()
+	return sink.NewLoader(db, driver, taskCtx.GetLogger(), options).Load(taskCtx.GetContext(), tables)
+}
+
+var LoadDataMeta = core.SubTaskMeta{
+	Name:             "loadData",
+	EntryPoint:       LoadData,
+	EnabledByDefault: true,
+	Description:      "Load the tables into the analytics target",
+}
@@ -0,0 +1,36 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"github.com/apache/incubator-devlake/helpers/pluginhelper/sink"
+)
+
+// SinkOptions selects the target and the tables, the options of the target are decoded by its driver,
+// i.e. host, port, user, password, database, be_host and be_port for starrocks, endpoint for clickhouse
+// dsn for mysql or postgres and directory for parquet
+type SinkOptions struct {
+	Target       string `mapstructure:"target"`
+	sink.Options `mapstructure:",squash"`
+}
+
+type SinkTaskData struct {
+	Options *SinkOptions
+	// TargetOptions are the raw options passed to the driver
+	TargetOptions map[string]interface{}
+}
@@ -0,0 +1,119 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/apache/incubator-devlake/errors"
+	"github.com/apache/incubator-devlake/models/domainlayer"
+	"github.com/apache/incubator-devlake/models/domainlayer/didgen"
+	"github.com/apache/incubator-devlake/models/domainlayer/ticket"
+	"github.com/apache/incubator-devlake/plugins/core"
+	"github.com/apache/incubator-devlake/plugins/core/dal"
+	"github.com/apache/incubator-devlake/plugins/helper"
+	"github.com/apache/incubator-devlake/plugins/tapd/models"
+	"github.com/apache/incubator-devlake/utils"
+)
+
+func MakeDataSourcePipelinePlanV200(subtaskMetas []core.SubTaskMeta, connectionId uint64, bpScopes []*core.BlueprintScopeV200, syncPolicy *core.BlueprintSyncPolicy) (core.PipelinePlan, []core.Scope, errors.Error) {
+	plan := make(core.PipelinePlan, len(bpScopes))
+	plan, err := makeDataSourcePipelinePlanV200(subtaskMetas, plan, bpScopes, connectionId, syncPolicy)
+	if err != nil {
+		return nil, nil, err
+	}
+	scopes, err := makeScopesV200(bpScopes, connectionId)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return plan, scopes, nil
+}
+
+func makeDataSourcePipelinePlanV200(
+	subtaskMetas []core.SubTaskMeta,
+	plan core.PipelinePlan,
+	bpScopes []*core.BlueprintScopeV200,
+	connectionId uint64,
+	syncPolicy *core.BlueprintSyncPolicy,
+) (core.PipelinePlan, errors.Error) {
+	for i, bpScope := range bpScopes {
+		stage := plan[i]
+		if stage == nil {
+			stage = core.PipelineStage{}
+		}
+		workspace, err := getWorkspaceByScope(bpScope, connectionId)
+		if err != nil {
+			return nil, err
+		}
+		// construct task options for tapd
+		options := make(map[string]interface{})
+		options["connectionId"] = connectionId
+		options["workspaceId"] = workspace.Id
+		options["transformationRuleId"] = workspace.TransformationRuleId
+		if syncPolicy.CreatedDateAfter != nil {
+			options["createdDateAfter"] = syncPolicy.CreatedDateAfter.Format(time.RFC3339)
+		}
+
+		subtasks, err := helper.MakePipelinePlanSubtasks(subtaskMetas, bpScope.Entities)
+		if err != nil {
+			return nil, err
+		}
+		stage = append(stage, &core.PipelineTask{
+			Plugin:   "tapd",
+			Subtasks: subtasks,
+			Options:  options,
+		})
+		plan[i] = stage
+	}
+
+	return plan, nil
+}
+
+func makeScopesV200(bpScopes []*core.BlueprintScopeV200, connectionId uint64) ([]core.Scope, errors.Error) {
+	scopes := make([]core.Scope, 0)
+	for _, bpScope := range bpScopes {
+		workspace, err := getWorkspaceByScope(bpScope, connectionId)
+		if err != nil {
+			return nil, err
+		}
+		// add board to scopes
+		if utils.StringsContains(bpScope.Entities, core.DOMAIN_TYPE_TICKET) {
+			domainBoard := &ticket.Board{
+				DomainEntity: domainlayer.DomainEntity{
+					Id: didgen.NewDomainIdGenerator(&models.TapdWorkspace{}).Generate(workspace.ConnectionId, workspace.Id),
+				},
+				Name: workspace.Name,
+				Url:  fmt.Sprintf("%s/%d", "https://tapd.cn", workspace.Id),
+			}
+			scopes = append(scopes, domainBoard)
+		}
+	}
+	return scopes, nil
+}
+
+func getWorkspaceByScope(bpScope *core.BlueprintScopeV200, connectionId uint64) (*models.TapdWorkspace, errors.Error) {
+	workspace := &models.TapdWorkspace{}
+	// get workspace from db
+	err := basicRes.GetDal().First(workspace, dal.Where(`connection_id = ? AND id = ?`, connectionId, bpScope.Id))
+	if err != nil {
+		return nil, errors.Default.Wrap(err, fmt.Sprintf("fail to find workspace %s", bpScope.Id))
+	}
+	return workspace, nil
+}
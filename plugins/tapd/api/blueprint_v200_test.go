@@ -0,0 +1,102 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"testing"
+
+	"github.com/apache/incubator-devlake/mocks"
+	"github.com/apache/incubator-devlake/models/domainlayer"
+	"github.com/apache/incubator-devlake/models/domainlayer/ticket"
+	"github.com/apache/incubator-devlake/plugins/core"
+	"github.com/apache/incubator-devlake/plugins/tapd/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestMakeDataSourcePipelinePlanV200(t *testing.T) {
+	mockMeta := mocks.NewPluginMeta(t)
+	mockMeta.On("RootPkgPath").Return("github.com/apache/incubator-devlake/plugins/tapd")
+	err := core.RegisterPlugin("tapd", mockMeta)
+	assert.Nil(t, err)
+	bs := &core.BlueprintScopeV200{
+		Entities: []string{core.DOMAIN_TYPE_TICKET},
+		Id:       "37027801",
+	}
+	syncPolicy := &core.BlueprintSyncPolicy{}
+	bpScopes := make([]*core.BlueprintScopeV200, 0)
+	bpScopes = append(bpScopes, bs)
+
+	basicRes = NewMockBasicRes()
+	plan := make(core.PipelinePlan, len(bpScopes))
+	plan, err = makeDataSourcePipelinePlanV200(nil, plan, bpScopes, uint64(1), syncPolicy)
+	assert.Nil(t, err)
+	basicRes = NewMockBasicRes()
+	scopes, err := makeScopesV200(bpScopes, uint64(1))
+	assert.Nil(t, err)
+
+	expectPlan := core.PipelinePlan{
+		core.PipelineStage{
+			{
+				Plugin:   "tapd",
+				Subtasks: []string{},
+				Options: map[string]interface{}{
+					"connectionId":         uint64(1),
+					"workspaceId":          uint64(37027801),
+					"transformationRuleId": uint64(2),
+				},
+			},
+		},
+	}
+	assert.Equal(t, expectPlan, plan)
+
+	expectScopes := make([]core.Scope, 0)
+	tapdBoard := &ticket.Board{
+		DomainEntity: domainlayer.DomainEntity{
+			Id: "tapd:TapdWorkspace:1:37027801",
+		},
+		Name: "test",
+		Url:  "https://tapd.cn/37027801",
+	}
+
+	expectScopes = append(expectScopes, tapdBoard)
+	assert.Equal(t, expectScopes, scopes)
+}
+
+// NewMockBasicRes FIXME ...
+func NewMockBasicRes() *mocks.BasicRes {
+	tapdWorkspace := &models.TapdWorkspace{
+		ConnectionId:         1,
+		Id:                   37027801,
+		Name:                 "test",
+		TransformationRuleId: 2,
+	}
+
+	mockRes := new(mocks.BasicRes)
+	mockDal := new(mocks.Dal)
+
+	mockDal.On("First", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		dst := args.Get(0).(*models.TapdWorkspace)
+		*dst = *tapdWorkspace
+	}).Return(nil).Once()
+
+	mockRes.On("GetDal").Return(mockDal)
+	mockRes.On("GetConfig", mock.Anything).Return("")
+
+	return mockRes
+}
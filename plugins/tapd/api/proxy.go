@@ -34,13 +34,8 @@ const (
 	TimeOut = 10 * time.Second
 )
 
-func Proxy(input *core.ApiResourceInput) (*core.ApiResourceOutput, errors.Error) {
-	connection := &models.TapdConnection{}
-	err := connectionHelper.First(connection, input.Params)
-	if err != nil {
-		return nil, err
-	}
-	apiClient, err := helper.NewApiClient(
+func newApiClient(connection *models.TapdConnection) (*helper.ApiClient, errors.Error) {
+	return helper.NewApiClient(
 		context.TODO(),
 		connection.Endpoint,
 		map[string]string{
@@ -50,6 +45,15 @@ func Proxy(input *core.ApiResourceInput) (*core.ApiResourceOutput, errors.Error)
 		connection.Proxy,
 		basicRes,
 	)
+}
+
+func Proxy(input *core.ApiResourceInput) (*core.ApiResourceOutput, errors.Error) {
+	connection := &models.TapdConnection{}
+	err := connectionHelper.First(connection, input.Params)
+	if err != nil {
+		return nil, err
+	}
+	apiClient, err := newApiClient(connection)
 	if err != nil {
 		return nil, err
 	}
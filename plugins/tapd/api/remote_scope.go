@@ -0,0 +1,142 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/apache/incubator-devlake/errors"
+	"github.com/apache/incubator-devlake/plugins/core"
+	"github.com/apache/incubator-devlake/plugins/helper"
+	"github.com/apache/incubator-devlake/plugins/tapd/models"
+)
+
+const (
+	TypeGroup = "group"
+	TypeScope = "scope"
+)
+
+type RemoteScopesChild struct {
+	Type     string      `json:"type"`
+	ParentId *string     `json:"parentId"`
+	Id       string      `json:"id"`
+	Name     string      `json:"name"`
+	Data     interface{} `json:"data"`
+}
+
+type RemoteScopesOutput struct {
+	Children      []RemoteScopesChild `json:"children"`
+	NextPageToken string              `json:"nextPageToken"`
+}
+
+type workspacesResponse struct {
+	Status int `json:"status"`
+	Data   []struct {
+		Workspace models.TapdWorkspace `json:"Workspace"`
+	} `json:"data"`
+	Info string `json:"info"`
+}
+
+// RemoteScopes list the workspaces of the company, or the sub workspaces of the workspace specified by groupId
+// @Summary list workspaces from Tapd
+// @Description list the workspaces of the company of the connection, or the sub workspaces when groupId is given
+// @Tags plugins/tapd
+// @Param connectionId path int true "connection ID"
+// @Param groupId query string false "workspace ID, list the workspaces of the company if empty"
+// @Success 200  {object} RemoteScopesOutput
+// @Failure 400  {object} shared.ApiBody "Bad Request"
+// @Failure 500  {object} shared.ApiBody "Internal Error"
+// @Router /plugins/tapd/connections/{connectionId}/remote-scopes [GET]
+func RemoteScopes(input *core.ApiResourceInput) (*core.ApiResourceOutput, errors.Error) {
+	connectionId, _ := extractParam(input.Params)
+	if connectionId == 0 {
+		return nil, errors.BadInput.New("invalid connectionId")
+	}
+	connection := &models.TapdConnection{}
+	err := connectionHelper.FirstById(connection, connectionId)
+	if err != nil {
+		return nil, err
+	}
+	apiClient, err := newApiClient(connection)
+	if err != nil {
+		return nil, err
+	}
+	groupId := input.Query.Get("groupId")
+	query := url.Values{}
+	output := RemoteScopesOutput{Children: []RemoteScopesChild{}}
+	var workspaces workspacesResponse
+	if groupId == "" {
+		// Tapd does not support listing the workspaces of the current user, the company is required
+		if connection.CompanyId == 0 {
+			return nil, errors.BadInput.New("companyId of the connection is required to list the workspaces")
+		}
+		query.Set("company_id", strconv.FormatUint(connection.CompanyId, 10))
+		err = getRemote(apiClient, "workspaces/projects", query, &workspaces)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range workspaces.Data {
+			output.Children = append(output.Children, RemoteScopesChild{
+				Type: TypeGroup,
+				Id:   strconv.FormatUint(item.Workspace.Id, 10),
+				Name: item.Workspace.Name,
+			})
+		}
+	} else {
+		// the workspace itself is returned along with its sub workspaces
+		query.Set("workspace_id", groupId)
+		err = getRemote(apiClient, "workspaces/sub_workspaces", query, &workspaces)
+		if err != nil {
+			return nil, err
+		}
+		for i := range workspaces.Data {
+			workspace := &workspaces.Data[i].Workspace
+			workspace.ConnectionId = connectionId
+			output.Children = append(output.Children, RemoteScopesChild{
+				Type:     TypeScope,
+				ParentId: &groupId,
+				Id:       strconv.FormatUint(workspace.Id, 10),
+				Name:     workspace.Name,
+				Data:     workspace,
+			})
+		}
+	}
+	return &core.ApiResourceOutput{Body: output, Status: http.StatusOK}, nil
+}
+
+func getRemote(apiClient helper.ApiClientGetter, path string, query url.Values, result *workspacesResponse) errors.Error {
+	res, err := apiClient.Get(path, query, nil)
+	if err != nil {
+		return err
+	}
+	if res.StatusCode != http.StatusOK {
+		return errors.HttpStatus(res.StatusCode).New(fmt.Sprintf("unexpected status code when requesting %s", path))
+	}
+	err = helper.UnmarshalResponse(res, result)
+	if err != nil {
+		return err
+	}
+	// Tapd responds 200 with status 0 and the reason in info on failures
+	if result.Status != 1 {
+		return errors.Default.New(fmt.Sprintf("failed to request %s: %s", path, result.Info))
+	}
+	return nil
+}
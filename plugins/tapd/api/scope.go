@@ -0,0 +1,228 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/apache/incubator-devlake/errors"
+	"github.com/apache/incubator-devlake/plugins/core"
+	"github.com/apache/incubator-devlake/plugins/core/dal"
+	"github.com/apache/incubator-devlake/plugins/helper"
+	"github.com/apache/incubator-devlake/plugins/tapd/models"
+	"github.com/mitchellh/mapstructure"
+)
+
+type apiWorkspace struct {
+	models.TapdWorkspace
+	TransformationRuleName string `json:"transformationRuleName,omitempty"`
+}
+
+type req struct {
+	Data []*models.TapdWorkspace `json:"data"`
+}
+
+// PutScope create or update tapd workspace
+// @Summary create or update tapd workspace
+// @Description Create or update tapd workspace
+// @Tags plugins/tapd
+// @Accept application/json
+// @Param connectionId path int true "connection ID"
+// @Param scope body req true "json"
+// @Success 200  {object} []models.TapdWorkspace
+// @Failure 400  {object} shared.ApiBody "Bad Request"
+// @Failure 500  {object} shared.ApiBody "Internal Error"
+// @Router /plugins/tapd/connections/{connectionId}/scopes [PUT]
+func PutScope(input *core.ApiResourceInput) (*core.ApiResourceOutput, errors.Error) {
+	connectionId, _ := extractParam(input.Params)
+	if connectionId == 0 {
+		return nil, errors.BadInput.New("invalid connectionId")
+	}
+	var workspaces req
+	err := decodeWeakly(input.Body, &workspaces)
+	if err != nil {
+		return nil, errors.BadInput.Wrap(err, "decoding Tapd workspace error")
+	}
+	keeper := make(map[uint64]struct{})
+	for _, workspace := range workspaces.Data {
+		if _, ok := keeper[workspace.Id]; ok {
+			return nil, errors.BadInput.New("duplicated item")
+		} else {
+			keeper[workspace.Id] = struct{}{}
+		}
+		workspace.ConnectionId = connectionId
+		err = verifyWorkspace(workspace)
+		if err != nil {
+			return nil, err
+		}
+	}
+	err = basicRes.GetDal().CreateOrUpdate(workspaces.Data)
+	if err != nil {
+		return nil, errors.Default.Wrap(err, "error on saving TapdWorkspace")
+	}
+	return &core.ApiResourceOutput{Body: workspaces.Data, Status: http.StatusOK}, nil
+}
+
+// UpdateScope patch to tapd workspace
+// @Summary patch to tapd workspace
+// @Description patch to tapd workspace
+// @Tags plugins/tapd
+// @Accept application/json
+// @Param connectionId path int true "connection ID"
+// @Param workspaceId path int true "workspace ID"
+// @Param scope body models.TapdWorkspace true "json"
+// @Success 200  {object} models.TapdWorkspace
+// @Failure 400  {object} shared.ApiBody "Bad Request"
+// @Failure 500  {object} shared.ApiBody "Internal Error"
+// @Router /plugins/tapd/connections/{connectionId}/scopes/{workspaceId} [PATCH]
+func UpdateScope(input *core.ApiResourceInput) (*core.ApiResourceOutput, errors.Error) {
+	connectionId, workspaceId := extractParam(input.Params)
+	if connectionId*workspaceId == 0 {
+		return nil, errors.BadInput.New("invalid connectionId or workspaceId")
+	}
+	var workspace models.TapdWorkspace
+	err := basicRes.GetDal().First(&workspace, dal.Where("connection_id = ? AND id = ?", connectionId, workspaceId))
+	if err != nil {
+		return nil, errors.Default.Wrap(err, "getting TapdWorkspace error")
+	}
+	err = decodeWeakly(input.Body, &workspace)
+	if err != nil {
+		return nil, errors.Default.Wrap(err, "patch tapd workspace error")
+	}
+	// the primary key should not be changed
+	workspace.ConnectionId = connectionId
+	workspace.Id = workspaceId
+	err = verifyWorkspace(&workspace)
+	if err != nil {
+		return nil, err
+	}
+	err = basicRes.GetDal().Update(workspace)
+	if err != nil {
+		return nil, errors.Default.Wrap(err, "error on saving TapdWorkspace")
+	}
+	return &core.ApiResourceOutput{Body: workspace, Status: http.StatusOK}, nil
+}
+
+// GetScopeList get Tapd workspaces
+// @Summary get Tapd workspaces
+// @Description get Tapd workspaces
+// @Tags plugins/tapd
+// @Param connectionId path int true "connection ID"
+// @Param pageSize query int false "page size, default 50"
+// @Param page query int false "page size, default 1"
+// @Success 200  {object} []apiWorkspace
+// @Failure 400  {object} shared.ApiBody "Bad Request"
+// @Failure 500  {object} shared.ApiBody "Internal Error"
+// @Router /plugins/tapd/connections/{connectionId}/scopes/ [GET]
+func GetScopeList(input *core.ApiResourceInput) (*core.ApiResourceOutput, errors.Error) {
+	var workspaces []models.TapdWorkspace
+	connectionId, _ := extractParam(input.Params)
+	if connectionId == 0 {
+		return nil, errors.BadInput.New("invalid path params")
+	}
+	limit, offset := helper.GetLimitOffset(input.Query, "pageSize", "page")
+	err := basicRes.GetDal().All(&workspaces, dal.Where("connection_id = ?", connectionId), dal.Limit(limit), dal.Offset(offset))
+	if err != nil {
+		return nil, err
+	}
+	var ruleIds []uint64
+	for _, workspace := range workspaces {
+		if workspace.TransformationRuleId > 0 {
+			ruleIds = append(ruleIds, workspace.TransformationRuleId)
+		}
+	}
+	var rules []models.TapdTransformationRule
+	if len(ruleIds) > 0 {
+		err = basicRes.GetDal().All(&rules, dal.Where("id IN (?)", ruleIds))
+		if err != nil {
+			return nil, err
+		}
+	}
+	names := make(map[uint64]string)
+	for _, rule := range rules {
+		names[rule.ID] = rule.Name
+	}
+	var apiWorkspaces []apiWorkspace
+	for _, workspace := range workspaces {
+		apiWorkspaces = append(apiWorkspaces, apiWorkspace{workspace, names[workspace.TransformationRuleId]})
+	}
+	return &core.ApiResourceOutput{Body: apiWorkspaces, Status: http.StatusOK}, nil
+}
+
+// GetScope get one Tapd workspace
+// @Summary get one Tapd workspace
+// @Description get one Tapd workspace
+// @Tags plugins/tapd
+// @Param connectionId path int true "connection ID"
+// @Param workspaceId path int true "workspace ID"
+// @Success 200  {object} apiWorkspace
+// @Failure 400  {object} shared.ApiBody "Bad Request"
+// @Failure 500  {object} shared.ApiBody "Internal Error"
+// @Router /plugins/tapd/connections/{connectionId}/scopes/{workspaceId} [GET]
+func GetScope(input *core.ApiResourceInput) (*core.ApiResourceOutput, errors.Error) {
+	var workspace models.TapdWorkspace
+	connectionId, workspaceId := extractParam(input.Params)
+	if connectionId*workspaceId == 0 {
+		return nil, errors.BadInput.New("invalid path params")
+	}
+	db := basicRes.GetDal()
+	err := db.First(&workspace, dal.Where("connection_id = ? AND id = ?", connectionId, workspaceId))
+	if db.IsErrorNotFound(err) {
+		return nil, errors.NotFound.New("record not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+	var rule models.TapdTransformationRule
+	if workspace.TransformationRuleId > 0 {
+		err = basicRes.GetDal().First(&rule, dal.Where("id = ?", workspace.TransformationRuleId))
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &core.ApiResourceOutput{Body: apiWorkspace{workspace, rule.Name}, Status: http.StatusOK}, nil
+}
+
+func extractParam(params map[string]string) (uint64, uint64) {
+	connectionId, _ := strconv.ParseUint(params["connectionId"], 10, 64)
+	workspaceId, _ := strconv.ParseUint(params["workspaceId"], 10, 64)
+	return connectionId, workspaceId
+}
+
+// decodeWeakly accepts the ids in both numbers and strings, the latter are returned by Tapd and the remote-scopes api
+func decodeWeakly(input map[string]interface{}, result interface{}) errors.Error {
+	decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		WeaklyTypedInput: true,
+		Result:           result,
+	})
+	if err != nil {
+		return errors.Convert(err)
+	}
+	return errors.Convert(decoder.Decode(input))
+}
+
+func verifyWorkspace(workspace *models.TapdWorkspace) errors.Error {
+	if workspace.ConnectionId == 0 {
+		return errors.BadInput.New("invalid connectionId")
+	}
+	if workspace.Id == 0 {
+		return errors.BadInput.New("invalid workspaceId")
+	}
+	return nil
+}
@@ -39,9 +39,23 @@ var _ core.PluginApi = (*Tapd)(nil)
 var _ core.PluginModel = (*Tapd)(nil)
 var _ core.PluginMigration = (*Tapd)(nil)
 var _ core.CloseablePluginTask = (*Tapd)(nil)
+var _ core.DataSourcePluginBlueprintV200 = (*Tapd)(nil)
+var _ core.PluginSource = (*Tapd)(nil)
 
 type Tapd struct{}
 
+func (plugin Tapd) Connection() interface{} {
+	return &models.TapdConnection{}
+}
+
+func (plugin Tapd) Scope() interface{} {
+	return &models.TapdWorkspace{}
+}
+
+func (plugin Tapd) TransformationRule() interface{} {
+	return &models.TapdTransformationRule{}
+}
+
 func (plugin Tapd) Init(basicRes core.BasicRes) errors.Error {
 	api.Init(basicRes)
 	return nil
@@ -80,6 +94,7 @@ func (plugin Tapd) GetTablesInfo() []dal.Tabler {
 		&models.TapdTaskCommit{},
 		&models.TapdTaskCustomFields{},
 		&models.TapdTaskLabel{},
+		&models.TapdTransformationRule{},
 		&models.TapdWorkSpaceBug{},
 		&models.TapdWorkSpaceStory{},
 		&models.TapdWorkSpaceTask{},
@@ -193,6 +208,10 @@ func (plugin Tapd) PrepareTaskData(taskCtx core.TaskContext, options map[string]
 		return nil, errors.Default.Wrap(err1, "fail to get CST Location")
 	}
 	op.CstZone = cstZone
+	err = EnrichOptions(taskCtx, &op)
+	if err != nil {
+		return nil, err
+	}
 	taskData := &tasks.TapdTaskData{
 		Options:    &op,
 		ApiClient:  tapdApiClient,
@@ -220,6 +239,10 @@ func (plugin Tapd) MigrationScripts() []core.MigrationScript {
 	return migrationscripts.All()
 }
 
+func (plugin Tapd) MakeDataSourcePipelinePlanV200(connectionId uint64, scopes []*core.BlueprintScopeV200, syncPolicy core.BlueprintSyncPolicy) (pp core.PipelinePlan, sc []core.Scope, err errors.Error) {
+	return api.MakeDataSourcePipelinePlanV200(plugin.SubTaskMetas(), connectionId, scopes, &syncPolicy)
+}
+
 func (plugin Tapd) ApiResources() map[string]map[string]core.ApiResourceHandler {
 	return map[string]map[string]core.ApiResourceHandler{
 		"test": {
@@ -237,6 +260,25 @@ func (plugin Tapd) ApiResources() map[string]map[string]core.ApiResourceHandler
 		"connections/:connectionId/proxy/rest/*path": {
 			"GET": api.Proxy,
 		},
+		"connections/:connectionId/scopes/:workspaceId": {
+			"GET":   api.GetScope,
+			"PATCH": api.UpdateScope,
+		},
+		"connections/:connectionId/scopes": {
+			"GET": api.GetScopeList,
+			"PUT": api.PutScope,
+		},
+		"connections/:connectionId/remote-scopes": {
+			"GET": api.RemoteScopes,
+		},
+		"transformation_rules": {
+			"POST": api.CreateTransformationRule,
+			"GET":  api.GetTransformationRuleList,
+		},
+		"transformation_rules/:id": {
+			"PATCH": api.UpdateTransformationRule,
+			"GET":   api.GetTransformationRule,
+		},
 	}
 }
 
@@ -248,3 +290,29 @@ func (plugin Tapd) Close(taskCtx core.TaskContext) errors.Error {
 	data.ApiClient.Release()
 	return nil
 }
+
+// EnrichOptions loads the transformation rule of the workspace when it is not given in the options
+func EnrichOptions(taskCtx core.TaskContext, op *tasks.TapdOptions) errors.Error {
+	db := taskCtx.GetDal()
+	if op.WorkspaceId != 0 && op.TransformationRuleId == 0 {
+		var workspace models.TapdWorkspace
+		err := db.First(&workspace, dal.Where("connection_id = ? AND id = ?", op.ConnectionId, op.WorkspaceId))
+		if err != nil && !db.IsErrorNotFound(err) {
+			return errors.Default.Wrap(err, fmt.Sprintf("fail to find workspace %d", op.WorkspaceId))
+		}
+		op.TransformationRuleId = workspace.TransformationRuleId
+	}
+	if op.TransformationRules.IsEmpty() && op.TransformationRuleId != 0 {
+		var transformationRule models.TapdTransformationRule
+		err := db.First(&transformationRule, dal.Where("id = ?", op.TransformationRuleId))
+		if err != nil {
+			return errors.BadInput.Wrap(err, "fail to get transformationRule")
+		}
+		rules, err := tasks.MakeTransformationRules(transformationRule)
+		if err != nil {
+			return errors.BadInput.Wrap(err, "fail to make transformationRule")
+		}
+		op.TransformationRules = *rules
+	}
+	return nil
+}
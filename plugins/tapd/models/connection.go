@@ -47,6 +47,8 @@ func (ba BasicAuth) GetEncodedToken() string {
 type TapdConnection struct {
 	helper.RestConnection `mapstructure:",squash"`
 	BasicAuth             `mapstructure:",squash"`
+	// CompanyId is used to list the workspaces of the company when adding scopes
+	CompanyId uint64 `mapstructure:"companyId" json:"companyId"`
 }
 
 type TapdConnectionDetail struct {
@@ -0,0 +1,59 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migrationscripts
+
+import (
+	"github.com/apache/incubator-devlake/errors"
+	"github.com/apache/incubator-devlake/helpers/migrationhelper"
+	"github.com/apache/incubator-devlake/plugins/core"
+	"github.com/apache/incubator-devlake/plugins/tapd/models/migrationscripts/archived"
+)
+
+type tapdConnection20230108 struct {
+	CompanyId uint64
+}
+
+func (tapdConnection20230108) TableName() string {
+	return "_tool_tapd_connections"
+}
+
+type tapdWorkspace20230108 struct {
+	TransformationRuleId uint64
+}
+
+func (tapdWorkspace20230108) TableName() string {
+	return "_tool_tapd_workspaces"
+}
+
+type addTransformationRule20230108 struct{}
+
+func (*addTransformationRule20230108) Up(basicRes core.BasicRes) errors.Error {
+	return migrationhelper.AutoMigrateTables(basicRes,
+		&tapdConnection20230108{},
+		&tapdWorkspace20230108{},
+		&archived.TapdTransformationRule{},
+	)
+}
+
+func (*addTransformationRule20230108) Version() uint64 {
+	return 20230108110024
+}
+
+func (*addTransformationRule20230108) Name() string {
+	return "add table _tool_tapd_transformation_rules, add company_id to _tool_tapd_connections and transformation_rule_id to _tool_tapd_workspaces"
+}
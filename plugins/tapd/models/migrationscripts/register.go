@@ -26,5 +26,6 @@ func All() []core.MigrationScript {
 	return []core.MigrationScript{
 		new(addInitTables),
 		new(increaseFieldLength),
+		new(addTransformationRule20230108),
 	}
 }
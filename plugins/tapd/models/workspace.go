@@ -36,7 +36,9 @@ type TapdWorkspace struct {
 	ParentId     uint64          `gorm:"type:BIGINT" json:"parent_id,string"`
 	Creator      string          `gorm:"type:varchar(255)" json:"creator"`
 	Created      *helper.CSTTime `json:"created"`
-	common.NoPKModel
+	// TransformationRuleId is only set when the workspace was added as a scope
+	TransformationRuleId uint64 `json:"transformationRuleId,omitempty" mapstructure:"transformationRuleId"`
+	common.NoPKModel     `json:"-" mapstructure:"-"`
 }
 
 func (TapdWorkspace) TableName() string {
@@ -18,20 +18,23 @@ limitations under the License.
 package tasks
 
 import (
+	"encoding/json"
 	"time"
 
+	"github.com/apache/incubator-devlake/errors"
 	"github.com/apache/incubator-devlake/plugins/helper"
 	"github.com/apache/incubator-devlake/plugins/tapd/models"
 )
 
 type TapdOptions struct {
-	ConnectionId        uint64   `mapstruct:"connectionId"`
-	WorkspaceId         uint64   `mapstruct:"workspaceId"`
-	CompanyId           uint64   `mapstruct:"companyId"`
-	Tasks               []string `mapstruct:"tasks,omitempty"`
-	CreatedDateAfter    string   `json:"createdDateAfter" mapstructure:"createdDateAfter,omitempty"`
-	CstZone             *time.Location
-	TransformationRules TransformationRules `json:"transformationRules"`
+	ConnectionId         uint64   `mapstruct:"connectionId"`
+	WorkspaceId          uint64   `mapstruct:"workspaceId"`
+	CompanyId            uint64   `mapstruct:"companyId"`
+	Tasks                []string `mapstruct:"tasks,omitempty"`
+	CreatedDateAfter     string   `json:"createdDateAfter" mapstructure:"createdDateAfter,omitempty"`
+	CstZone              *time.Location
+	TransformationRuleId uint64              `json:"transformationRuleId" mapstructure:"transformationRuleId,omitempty"`
+	TransformationRules  TransformationRules `json:"transformationRules"`
 }
 
 type TapdTaskData struct {
@@ -55,3 +58,26 @@ type TransformationRules struct {
 	TypeMappings   TypeMappings   `json:"typeMappings"`
 	StatusMappings StatusMappings `json:"statusMappings"`
 }
+
+// IsEmpty returns true when neither typeMappings nor statusMappings were given
+func (rules TransformationRules) IsEmpty() bool {
+	return len(rules.TypeMappings) == 0 && len(rules.StatusMappings) == 0
+}
+
+// MakeTransformationRules decodes the mappings stored in the transformation rule
+func MakeTransformationRules(rule models.TapdTransformationRule) (*TransformationRules, errors.Error) {
+	result := &TransformationRules{}
+	if len(rule.TypeMappings) > 0 {
+		err := json.Unmarshal(rule.TypeMappings, &result.TypeMappings)
+		if err != nil {
+			return nil, errors.Default.Wrap(err, "unable to unmarshal the typeMappings")
+		}
+	}
+	if len(rule.StatusMappings) > 0 {
+		err := json.Unmarshal(rule.StatusMappings, &result.StatusMappings)
+		if err != nil {
+			return nil, errors.Default.Wrap(err, "unable to unmarshal the statusMappings")
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,190 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/apache/incubator-devlake/errors"
+	"github.com/apache/incubator-devlake/plugins/core"
+	"github.com/apache/incubator-devlake/plugins/core/dal"
+	"github.com/apache/incubator-devlake/plugins/helper"
+	"github.com/apache/incubator-devlake/plugins/webhook/models"
+)
+
+const apiKeyPrefix = "dlwh_"
+
+type WebhookApiKeyReq struct {
+	Name      string     `mapstructure:"name" validate:"required"`
+	ExpiredAt *time.Time `mapstructure:"expiredAt"`
+}
+
+type WebhookApiKeyResponse struct {
+	models.WebhookApiKey
+	// ApiKey is returned only once on creation
+	ApiKey string `json:"apiKey"`
+}
+
+// PostApiKey
+// @Summary generate an api key for the webhook connection
+// @Description Generate an api key, the key is returned only once, example: {"name":"jenkins","expiredAt":"2024-01-01T00:00:00Z"}
+// @Description Once a connection has any api key, requests to it must carry one by `Authorization: Bearer <key>` or `X-Api-Key: <key>`
+// @Tags plugins/webhook
+// @Param body body WebhookApiKeyReq true "json body"
+// @Success 200  {object} WebhookApiKeyResponse
+// @Failure 400  {string} errcode.Error "Bad Request"
+// @Failure 500  {string} errcode.Error "Internal Error"
+// @Router /plugins/webhook/connections/{connectionId}/api-keys [POST]
+func PostApiKey(input *core.ApiResourceInput) (*core.ApiResourceOutput, errors.Error) {
+	connection := &models.WebhookConnection{}
+	err := connectionHelper.First(connection, input.Params)
+	if err != nil {
+		return nil, err
+	}
+	request := &WebhookApiKeyReq{}
+	err = helper.DecodeMapStruct(input.Body, request)
+	if err != nil {
+		return nil, errors.BadInput.Wrap(err, "input json error")
+	}
+	if validationErr := vld.Struct(request); validationErr != nil {
+		return nil, errors.BadInput.Wrap(validationErr, "input json error")
+	}
+	key, err := generateApiKey()
+	if err != nil {
+		return nil, err
+	}
+	apiKey := &models.WebhookApiKey{
+		ConnectionId: connection.ID,
+		Name:         request.Name,
+		KeyPrefix:    key[:len(apiKeyPrefix)+6],
+		KeyHash:      hashApiKey(key),
+		ExpiredAt:    request.ExpiredAt,
+	}
+	err = basicRes.GetDal().Create(apiKey)
+	if err != nil {
+		return nil, err
+	}
+	return &core.ApiResourceOutput{Body: &WebhookApiKeyResponse{WebhookApiKey: *apiKey, ApiKey: key}, Status: http.StatusOK}, nil
+}
+
+// ListApiKeys
+// @Summary list the api keys of the webhook connection
+// @Description List the api keys of the webhook connection, the keys themselves are not included
+// @Tags plugins/webhook
+// @Success 200  {object} []models.WebhookApiKey
+// @Failure 400  {string} errcode.Error "Bad Request"
+// @Failure 500  {string} errcode.Error "Internal Error"
+// @Router /plugins/webhook/connections/{connectionId}/api-keys [GET]
+func ListApiKeys(input *core.ApiResourceInput) (*core.ApiResourceOutput, errors.Error) {
+	connection := &models.WebhookConnection{}
+	err := connectionHelper.First(connection, input.Params)
+	if err != nil {
+		return nil, err
+	}
+	apiKeys := []models.WebhookApiKey{}
+	err = basicRes.GetDal().All(&apiKeys, dal.Where("connection_id = ?", connection.ID), dal.Orderby("id"))
+	if err != nil {
+		return nil, err
+	}
+	return &core.ApiResourceOutput{Body: apiKeys, Status: http.StatusOK}, nil
+}
+
+// DeleteApiKey
+// @Summary revoke an api key of the webhook connection
+// @Description Revoke an api key, the connection accepts requests without key again once all keys are revoked
+// @Tags plugins/webhook
+// @Success 200  {object} models.WebhookApiKey
+// @Failure 400  {string} errcode.Error "Bad Request"
+// @Failure 404  {string} errcode.Error "Not Found"
+// @Failure 500  {string} errcode.Error "Internal Error"
+// @Router /plugins/webhook/connections/{connectionId}/api-keys/{keyId} [DELETE]
+func DeleteApiKey(input *core.ApiResourceInput) (*core.ApiResourceOutput, errors.Error) {
+	connection := &models.WebhookConnection{}
+	err := connectionHelper.First(connection, input.Params)
+	if err != nil {
+		return nil, err
+	}
+	keyId, parseErr := strconv.ParseUint(input.Params["keyId"], 10, 64)
+	if parseErr != nil {
+		return nil, errors.BadInput.Wrap(parseErr, "invalid keyId")
+	}
+	db := basicRes.GetDal()
+	apiKey := &models.WebhookApiKey{}
+	err = db.First(apiKey, dal.Where("id = ? AND connection_id = ?", keyId, connection.ID))
+	if err != nil {
+		if db.IsErrorNotFound(err) {
+			return nil, errors.NotFound.Wrap(err, "api key not found")
+		}
+		return nil, err
+	}
+	err = db.Delete(apiKey)
+	if err != nil {
+		return nil, err
+	}
+	return &core.ApiResourceOutput{Body: apiKey, Status: http.StatusOK}, nil
+}
+
+// ListRequestLogs
+// @Summary list the requests received by the webhook connection
+// @Description List the requests received by the webhook connection with `logRequests` enabled, the latest first
+// @Tags plugins/webhook
+// @Param page query int false "page"
+// @Param pageSize query int false "page size"
+// @Success 200  {object} []models.WebhookRequestLog
+// @Failure 400  {string} errcode.Error "Bad Request"
+// @Failure 500  {string} errcode.Error "Internal Error"
+// @Router /plugins/webhook/connections/{connectionId}/logs [GET]
+func ListRequestLogs(input *core.ApiResourceInput) (*core.ApiResourceOutput, errors.Error) {
+	connection := &models.WebhookConnection{}
+	err := connectionHelper.First(connection, input.Params)
+	if err != nil {
+		return nil, err
+	}
+	limit, offset := helper.GetLimitOffset(input.Query, "pageSize", "page")
+	logs := []models.WebhookRequestLog{}
+	err = basicRes.GetDal().All(
+		&logs,
+		dal.Where("connection_id = ?", connection.ID),
+		dal.Orderby("id DESC"),
+		dal.Limit(limit),
+		dal.Offset(offset),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &core.ApiResourceOutput{Body: logs, Status: http.StatusOK}, nil
+}
+
+func generateApiKey() (string, errors.Error) {
+	buf := make([]byte, 24)
+	_, err := rand.Read(buf)
+	if err != nil {
+		return "", errors.Convert(err)
+	}
+	return apiKeyPrefix + hex.EncodeToString(buf), nil
+}
+
+func hashApiKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
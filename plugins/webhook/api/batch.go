@@ -0,0 +1,188 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+
+	"github.com/apache/incubator-devlake/errors"
+	"github.com/apache/incubator-devlake/plugins/core"
+	"github.com/apache/incubator-devlake/plugins/core/dal"
+	"github.com/apache/incubator-devlake/plugins/helper"
+)
+
+// the most items accepted by one batch request, larger backfills should be split into several requests
+const maxBatchSize = 1000
+
+// WebhookBatchItemResult is the outcome of one item of a batch request
+type WebhookBatchItemResult struct {
+	Index   int    `json:"index"`
+	Id      string `json:"id,omitempty"`
+	Success bool   `json:"success"`
+	Error   string `json:"error,omitempty"`
+}
+
+// WebhookBatchResponse lists the outcome of all items in their order in the request
+type WebhookBatchResponse struct {
+	Success bool                      `json:"success"`
+	Results []*WebhookBatchItemResult `json:"results"`
+}
+
+// batchItems returns the array under the key of the request body
+func batchItems(body map[string]interface{}, key string) ([]map[string]interface{}, errors.Error) {
+	raw, ok := body[key].([]interface{})
+	if !ok || len(raw) == 0 {
+		return nil, errors.BadInput.New(fmt.Sprintf("%s should be a non-empty array", key))
+	}
+	if len(raw) > maxBatchSize {
+		return nil, errors.BadInput.New(fmt.Sprintf("too many %s, at most %d are accepted in one request", key, maxBatchSize))
+	}
+	items := make([]map[string]interface{}, len(raw))
+	for i, item := range raw {
+		items[i], _ = item.(map[string]interface{})
+	}
+	return items, nil
+}
+
+// decodeBatchItem decodes and validates one item, the failure is recorded into the result
+func decodeBatchItem(item map[string]interface{}, request interface{}, result *WebhookBatchItemResult) bool {
+	if item == nil {
+		result.Error = "item should be an object"
+		return false
+	}
+	err := helper.DecodeMapStruct(item, request)
+	if err != nil {
+		result.Error = err.Error()
+		return false
+	}
+	if validationErr := vld.Struct(request); validationErr != nil {
+		result.Error = validationErr.Error()
+		return false
+	}
+	return true
+}
+
+// batchFailed reports whether any item failed, in which case nothing should be saved
+func batchFailed(results []*WebhookBatchItemResult) bool {
+	for _, result := range results {
+		if result.Error != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// batchOutput responds 400 with all results if any item failed, otherwise 200
+func batchOutput(results []*WebhookBatchItemResult) *core.ApiResourceOutput {
+	failed := batchFailed(results)
+	for _, result := range results {
+		result.Success = !failed
+	}
+	status := http.StatusOK
+	if failed {
+		status = http.StatusBadRequest
+	}
+	return &core.ApiResourceOutput{Body: &WebhookBatchResponse{Success: !failed, Results: results}, Status: status}
+}
+
+// txBasicRes makes BatchSave write into the transaction
+type txBasicRes struct {
+	core.BasicRes
+	tx dal.Transaction
+}
+
+func (r *txBasicRes) GetDal() dal.Dal {
+	return r.tx
+}
+
+// batchSaver upserts records of different types with one BatchSave for each type
+type batchSaver struct {
+	res    core.BasicRes
+	savers map[reflect.Type]*helper.BatchSave
+	order  []reflect.Type
+}
+
+func (s *batchSaver) add(records ...interface{}) errors.Error {
+	for _, record := range records {
+		recordType := reflect.TypeOf(record)
+		saver, ok := s.savers[recordType]
+		if !ok {
+			var err errors.Error
+			saver, err = helper.NewBatchSave(s.res, recordType, 500)
+			if err != nil {
+				return err
+			}
+			s.savers[recordType] = saver
+			s.order = append(s.order, recordType)
+		}
+		err := saver.Add(record)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addAll adds the elements of a slice of records
+func (s *batchSaver) addAll(records interface{}) errors.Error {
+	v := reflect.ValueOf(records)
+	for i := 0; i < v.Len(); i++ {
+		err := s.add(v.Index(i).Interface())
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *batchSaver) close() errors.Error {
+	for _, recordType := range s.order {
+		err := s.savers[recordType].Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// saveInTransaction runs save with a batchSaver bound to a new transaction, and commits only if all records are saved
+func saveInTransaction(save func(tx dal.Dal, saver *batchSaver) errors.Error) (err errors.Error) {
+	tx := basicRes.GetDal().Begin()
+	defer func() {
+		if r := recover(); r != nil || err != nil {
+			if rollbackErr := tx.Rollback(); rollbackErr != nil {
+				basicRes.GetLogger().Error(rollbackErr, "failed to rollback the batch")
+			}
+			if r != nil {
+				err = errors.Default.New(fmt.Sprintf("panic while saving the batch: %v", r))
+			}
+		}
+	}()
+	saver := &batchSaver{res: &txBasicRes{BasicRes: basicRes, tx: tx}, savers: make(map[reflect.Type]*helper.BatchSave)}
+	err = save(tx, saver)
+	if err != nil {
+		return err
+	}
+	err = saver.close()
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
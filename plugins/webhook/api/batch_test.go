@@ -0,0 +1,123 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBatchItems(t *testing.T) {
+	_, err := batchItems(map[string]interface{}{}, "issues")
+	assert.NotNil(t, err)
+	_, err = batchItems(map[string]interface{}{"issues": []interface{}{}}, "issues")
+	assert.NotNil(t, err)
+	_, err = batchItems(map[string]interface{}{"issues": make([]interface{}, maxBatchSize+1)}, "issues")
+	assert.NotNil(t, err)
+
+	items, err := batchItems(map[string]interface{}{"issues": []interface{}{map[string]interface{}{"issue_key": "DLK-1"}, "DLK-2"}}, "issues")
+	assert.Nil(t, err)
+	assert.Equal(t, "DLK-1", items[0]["issue_key"])
+	assert.Nil(t, items[1])
+}
+
+func TestMakeIssueBatchRecords(t *testing.T) {
+	vld = validator.New()
+	issue := func(issueKey string) map[string]interface{} {
+		return map[string]interface{}{
+			"board_key":       "DLK",
+			"issue_key":       issueKey,
+			"title":           "a feature from DLK",
+			"status":          "TODO",
+			"original_status": "created",
+			"created_date":    "2020-01-01T12:00:00+00:00",
+		}
+	}
+	labeled := issue("DLK-2")
+	labeled["labels"] = []interface{}{"backend", "bug"}
+	labeled["sprint_keys"] = []interface{}{"S1"}
+	sprints := []map[string]interface{}{{"board_key": "DLK", "sprint_key": "S1", "name": "Sprint 1", "status": "ACTIVE"}}
+
+	records := makeIssueBatchRecords(1, []map[string]interface{}{issue("DLK-1"), labeled}, sprints)
+	assert.False(t, batchFailed(records.issueResults))
+	assert.False(t, batchFailed(records.sprintResults))
+	assert.Equal(t, []string{"webhook:1:DLK"}, records.boardIds)
+	assert.Equal(t, "webhook:1:DLK:DLK-1", records.issueResults[0].Id)
+	assert.Len(t, records.issues, 2)
+	assert.Len(t, records.boardIssues, 2)
+	assert.Equal(t, []string{"webhook:1:DLK:DLK-2"}, records.labeledIds)
+	assert.Len(t, records.issueLabels, 2)
+	assert.Equal(t, "webhook:1:DLK:sprint:S1", records.sprints[0].Id)
+	assert.Equal(t, "webhook:1:DLK", records.sprints[0].OriginalBoardID)
+	assert.Equal(t, "webhook:1:DLK:sprint:S1", records.boardSprints[0].SprintId)
+	assert.Equal(t, "webhook:1:DLK:sprint:S1", records.sprintIssues[0].SprintId)
+	assert.Equal(t, "webhook:1:DLK:DLK-2", records.sprintIssues[0].IssueId)
+
+	invalid := issue("DLK-3")
+	invalid["status"] = "CLOSED"
+	records = makeIssueBatchRecords(1, []map[string]interface{}{issue("DLK-1"), invalid, nil}, nil)
+	assert.True(t, batchFailed(records.issueResults))
+	assert.Empty(t, records.issueResults[0].Error)
+	assert.NotEmpty(t, records.issueResults[1].Error)
+	assert.NotEmpty(t, records.issueResults[2].Error)
+
+	output := issueBatchOutput(records)
+	assert.Equal(t, http.StatusBadRequest, output.Status)
+	assert.False(t, records.issueResults[0].Success)
+}
+
+func TestMakeCicdTaskBatchRecords(t *testing.T) {
+	vld = validator.New()
+	task := func(pipelineName, name, startedDate string) map[string]interface{} {
+		return map[string]interface{}{
+			"pipeline_name": pipelineName,
+			"name":          name,
+			"result":        "SUCCESS",
+			"status":        "DONE",
+			"type":          "TEST",
+			"environment":   "PRODUCTION",
+			"created_date":  startedDate,
+			"finished_date": "2020-01-01T12:59:59+00:00",
+			"repo_id":       "devlake",
+			"commit_sha":    "015e3d3b480e417aede5a1293bd61de9b0fd051d",
+		}
+	}
+	records := makeCicdTaskBatchRecords(1, []map[string]interface{}{
+		task("A123", "unit-test", "2020-01-01T12:00:00+00:00"),
+		task("A123", "lint", "2020-01-01T12:30:00+00:00"),
+		task("B456", "unit-test", "2020-01-01T12:00:00+00:00"),
+	})
+	assert.False(t, batchFailed(records.results))
+	assert.Equal(t, "webhook:1:A123:lint", records.results[1].Id)
+	assert.Equal(t, []string{"webhook:1:A123", "webhook:1:B456"}, records.pipelineIds)
+	assert.Equal(t, "2020-01-01T12:00:00Z", records.pipelines["webhook:1:A123"].CreatedDate.UTC().Format("2006-01-02T15:04:05Z"))
+	assert.Equal(t, uint64(1799), records.tasks[1].DurationSec)
+	assert.Len(t, records.commits, 3)
+
+	output := batchOutput(records.results)
+	assert.Equal(t, http.StatusOK, output.Status)
+	assert.True(t, records.results[0].Success)
+
+	invalid := task("A123", "unit-test", "2020-01-01T12:00:00+00:00")
+	delete(invalid, "repo_id")
+	records = makeCicdTaskBatchRecords(1, []map[string]interface{}{invalid})
+	assert.True(t, batchFailed(records.results))
+}
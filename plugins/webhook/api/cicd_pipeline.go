@@ -87,23 +87,8 @@ func PostCicdTask(input *core.ApiResourceInput) (*core.ApiResourceOutput, errors
 	}
 
 	db := basicRes.GetDal()
-	pipelineId := fmt.Sprintf("%s:%d:%s", "webhook", connection.ID, request.PipelineName)
-	domainCicdTask := &devops.CICDTask{
-		DomainEntity: domainlayer.DomainEntity{
-			Id: fmt.Sprintf("%s:%d:%s:%s", "webhook", connection.ID, request.PipelineName, request.Name),
-		},
-		PipelineId:   pipelineId,
-		Name:         request.Name,
-		Result:       request.Result,
-		Status:       request.Status,
-		Type:         request.Type,
-		Environment:  request.Environment,
-		StartedDate:  request.StartedDate,
-		FinishedDate: request.FinishedDate,
-	}
-	if domainCicdTask.FinishedDate != nil {
-		domainCicdTask.DurationSec = uint64(domainCicdTask.FinishedDate.Sub(domainCicdTask.StartedDate).Seconds())
-	}
+	domainCicdTask := newDomainCicdTask(connection.ID, request)
+	pipelineId := domainCicdTask.PipelineId
 
 	domainPipeline := &devops.CICDPipeline{}
 	err = db.First(domainPipeline, dal.Where("id = ?", pipelineId))
@@ -123,12 +108,7 @@ func PostCicdTask(input *core.ApiResourceInput) (*core.ApiResourceOutput, errors
 		return nil, errors.Forbidden.New(`can not receive this task because pipeline has already been done.`)
 	}
 
-	domainPipelineCommit := &devops.CiCDPipelineCommit{
-		PipelineId: pipelineId,
-		CommitSha:  request.CommitSha,
-		Branch:     request.Branch,
-		RepoId:     request.RepoId,
-	}
+	domainPipelineCommit := newDomainPipelineCommit(pipelineId, request)
 
 	// save
 	err = db.CreateOrUpdate(domainCicdTask)
@@ -147,6 +127,140 @@ func PostCicdTask(input *core.ApiResourceInput) (*core.ApiResourceOutput, errors
 	return &core.ApiResourceOutput{Body: nil, Status: http.StatusOK}, nil
 }
 
+// newDomainCicdTask converts the request into the domain layer task
+func newDomainCicdTask(connectionId uint64, request *WebhookTaskRequest) *devops.CICDTask {
+	domainCicdTask := &devops.CICDTask{
+		DomainEntity: domainlayer.DomainEntity{
+			Id: fmt.Sprintf("%s:%d:%s:%s", "webhook", connectionId, request.PipelineName, request.Name),
+		},
+		PipelineId:   fmt.Sprintf("%s:%d:%s", "webhook", connectionId, request.PipelineName),
+		Name:         request.Name,
+		Result:       request.Result,
+		Status:       request.Status,
+		Type:         request.Type,
+		Environment:  request.Environment,
+		StartedDate:  request.StartedDate,
+		FinishedDate: request.FinishedDate,
+	}
+	if domainCicdTask.FinishedDate != nil {
+		domainCicdTask.DurationSec = uint64(domainCicdTask.FinishedDate.Sub(domainCicdTask.StartedDate).Seconds())
+	}
+	return domainCicdTask
+}
+
+func newDomainPipelineCommit(pipelineId string, request *WebhookTaskRequest) *devops.CiCDPipelineCommit {
+	return &devops.CiCDPipelineCommit{
+		PipelineId: pipelineId,
+		CommitSha:  request.CommitSha,
+		Branch:     request.Branch,
+		RepoId:     request.RepoId,
+	}
+}
+
+// webhookCicdTaskBatchRecords are the domain layer records of a cicd task batch
+type webhookCicdTaskBatchRecords struct {
+	results     []*WebhookBatchItemResult
+	tasks       []*devops.CICDTask
+	commits     []*devops.CiCDPipelineCommit
+	pipelineIds []string
+	// pipelines are created for the ids not existing yet, the same as PostCicdTask does for the first task
+	pipelines map[string]*devops.CICDPipeline
+}
+
+// PostCicdTasksBatch
+// @Summary create pipelines by webhook in batch
+// @Description Receive up to 1000 tasks, each is the same as the one of `/plugins/webhook/:connectionId/cicd_tasks`, and save them in one transaction.<br/>
+// @Description Nothing is saved if any task is invalid or belongs to a finished pipeline, the results tell the error of each task.<br/>
+// @Description example: {"tasks":[{"pipeline_name":"A123","name":"unit-test","result":"SUCCESS","status":"DONE","type":"TEST","environment":"PRODUCTION","created_date":"2020-01-01T12:00:00+00:00","finished_date":"2020-01-01T12:59:59+00:00","repo_id":"devlake","branch":"main","commit_sha":"015e3d3b480e417aede5a1293bd61de9b0fd051d"}]}
+// @Tags plugins/webhook
+// @Success 200  {object} WebhookBatchResponse
+// @Failure 400  {object} WebhookBatchResponse
+// @Failure 500  {string} errcode.Error "Internal Error"
+// @Router /plugins/webhook/:connectionId/cicd_tasks/batch [POST]
+func PostCicdTasksBatch(input *core.ApiResourceInput) (*core.ApiResourceOutput, errors.Error) {
+	connection := &models.WebhookConnection{}
+	err := connectionHelper.First(connection, input.Params)
+	if err != nil {
+		return nil, err
+	}
+	items, err := batchItems(input.Body, "tasks")
+	if err != nil {
+		return nil, err
+	}
+	records := makeCicdTaskBatchRecords(connection.ID, items)
+	if batchFailed(records.results) {
+		return batchOutput(records.results), nil
+	}
+
+	err = saveInTransaction(func(tx dal.Dal, saver *batchSaver) errors.Error {
+		var existingPipelines []*devops.CICDPipeline
+		err := tx.All(&existingPipelines, dal.Where("id IN ?", records.pipelineIds))
+		if err != nil {
+			return err
+		}
+		finished := make(map[string]bool)
+		for _, pipeline := range existingPipelines {
+			delete(records.pipelines, pipeline.Id)
+			finished[pipeline.Id] = pipeline.Status == `DONE`
+		}
+		for i, task := range records.tasks {
+			if finished[task.PipelineId] {
+				records.results[i].Error = `can not receive this task because pipeline has already been done.`
+			}
+		}
+		if batchFailed(records.results) {
+			return nil
+		}
+		for _, pipelineId := range records.pipelineIds {
+			if pipeline, ok := records.pipelines[pipelineId]; ok {
+				err = saver.add(pipeline)
+				if err != nil {
+					return err
+				}
+			}
+		}
+		err = saver.addAll(records.tasks)
+		if err != nil {
+			return err
+		}
+		return saver.addAll(records.commits)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return batchOutput(records.results), nil
+}
+
+// makeCicdTaskBatchRecords decodes and validates all items and converts them into domain layer records,
+// the records are only complete when all items are valid
+func makeCicdTaskBatchRecords(connectionId uint64, items []map[string]interface{}) *webhookCicdTaskBatchRecords {
+	records := &webhookCicdTaskBatchRecords{pipelines: make(map[string]*devops.CICDPipeline)}
+	for i, item := range items {
+		result := &WebhookBatchItemResult{Index: i}
+		records.results = append(records.results, result)
+		request := &WebhookTaskRequest{}
+		if !decodeBatchItem(item, request, result) {
+			continue
+		}
+		domainCicdTask := newDomainCicdTask(connectionId, request)
+		result.Id = domainCicdTask.Id
+		records.tasks = append(records.tasks, domainCicdTask)
+		records.commits = append(records.commits, newDomainPipelineCommit(domainCicdTask.PipelineId, request))
+		if _, ok := records.pipelines[domainCicdTask.PipelineId]; !ok {
+			records.pipelineIds = append(records.pipelineIds, domainCicdTask.PipelineId)
+			records.pipelines[domainCicdTask.PipelineId] = &devops.CICDPipeline{
+				DomainEntity: domainlayer.DomainEntity{
+					Id: domainCicdTask.PipelineId,
+				},
+				Name:        request.PipelineName,
+				Status:      `IN_PROGRESS`,
+				CreatedDate: request.StartedDate,
+			}
+		}
+	}
+	return records
+}
+
 // PostPipelineFinish
 // @Summary set pipeline's status to DONE
 // @Description set pipeline's status to DONE and cal duration
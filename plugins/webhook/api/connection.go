@@ -88,6 +88,7 @@ type WebhookConnectionResponse struct {
 	PostPipelineTaskEndpoint       string `json:"postPipelineTaskEndpoint"`
 	PostPipelineDeployTaskEndpoint string `json:"postPipelineDeployTaskEndpoint"`
 	ClosePipelineEndpoint          string `json:"closePipelineEndpoint"`
+	SigningEnabled                 bool   `json:"signingEnabled"`
 }
 
 // ListConnections
@@ -128,6 +129,9 @@ func GetConnection(input *core.ApiResourceInput) (*core.ApiResourceOutput, error
 
 func formatConnection(connection *models.WebhookConnection) *WebhookConnectionResponse {
 	response := &WebhookConnectionResponse{WebhookConnection: *connection}
+	// the signing secret is write-only
+	response.SigningEnabled = connection.SigningSecret != ""
+	response.SigningSecret = ""
 	response.PostIssuesEndpoint = fmt.Sprintf(`/plugins/webhook/%d/issues`, connection.ID)
 	response.CloseIssuesEndpoint = fmt.Sprintf(`/plugins/webhook/%d/issue/:boardKey/:issueKey/close`, connection.ID)
 	response.PostPipelineTaskEndpoint = fmt.Sprintf(`/plugins/webhook/%d/cicd_tasks`, connection.ID)
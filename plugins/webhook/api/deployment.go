@@ -0,0 +1,238 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"crypto/md5"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/apache/incubator-devlake/errors"
+	"github.com/apache/incubator-devlake/models/domainlayer"
+	"github.com/apache/incubator-devlake/models/domainlayer/devops"
+	"github.com/apache/incubator-devlake/plugins/core"
+	"github.com/apache/incubator-devlake/plugins/core/dal"
+	"github.com/apache/incubator-devlake/plugins/helper"
+	"github.com/apache/incubator-devlake/plugins/webhook/models"
+)
+
+type WebhookDeploymentReq struct {
+	// Id is supplied by the client and identifies the deployment, posting the same id again replaces it
+	Id           string     `mapstructure:"id" validate:"required"`
+	Name         string     `mapstructure:"name"`
+	Result       string     `mapstructure:"result" validate:"omitempty,oneof=SUCCESS FAILURE ABORT"`
+	Environment  string     `mapstructure:"environment" validate:"omitempty,oneof=PRODUCTION STAGING TESTING DEVELOPMENT"`
+	StartedDate  *time.Time `mapstructure:"start_time" validate:"required_with=FinishedDate"`
+	FinishedDate *time.Time `mapstructure:"end_time"`
+	// DeploymentCommits lists the repos deployed together, one commit for each of them
+	DeploymentCommits []WebhookDeploymentCommitReq `mapstructure:"deployment_commits" validate:"required,min=1,dive"`
+}
+
+type WebhookDeploymentCommitReq struct {
+	// RepoId is the id of the repo in the domain layer, e.g. github:GithubRepo:1:384111310, RepoUrl is used if omitted
+	RepoId        string `mapstructure:"repo_id"`
+	RepoUrl       string `mapstructure:"repo_url" validate:"required"`
+	Branch        string `mapstructure:"branch"`
+	CommitSha     string `mapstructure:"commit_sha" validate:"required"`
+	PrevCommitSha string `mapstructure:"prev_commit_sha"`
+}
+
+type webhookDeploymentRecords struct {
+	pipeline      *devops.CICDPipeline
+	tasks         []*devops.CICDTask
+	commits       []*devops.CiCDPipelineCommit
+	deployCommits []*models.WebhookDeploymentCommit
+}
+
+// PostDeployment
+// @Summary create a deployment of one or more repos by webhook
+// @Description Create a deployment with its cicd_pipeline, cicd_tasks and cicd_pipeline_commits in one transaction.<br/>
+// @Description The deployment is identified by the client supplied id, posting it again replaces the previous records, so the request is safe to retry.<br/>
+// @Description example: {"id":"release-2023.01.20","result":"SUCCESS","environment":"PRODUCTION","start_time":"2023-01-20T12:00:00+00:00","end_time":"2023-01-20T12:59:59+00:00","deployment_commits":[{"repo_url":"https://github.com/apache/incubator-devlake","branch":"main","commit_sha":"015e3d3b480e417aede5a1293bd61de9b0fd051d","prev_commit_sha":"9da6a3cd0bc7e1d1a5a0de0c6f5b3f9f6e05a9e3"}]}
+// @Tags plugins/webhook
+// @Param body body WebhookDeploymentReq true "json body"
+// @Success 200
+// @Failure 400  {string} errcode.Error "Bad Request"
+// @Failure 500  {string} errcode.Error "Internal Error"
+// @Router /plugins/webhook/connections/:connectionId/deployments [POST]
+func PostDeployment(input *core.ApiResourceInput) (*core.ApiResourceOutput, errors.Error) {
+	connection := &models.WebhookConnection{}
+	err := connectionHelper.First(connection, input.Params)
+	if err != nil {
+		return nil, err
+	}
+	request := &WebhookDeploymentReq{}
+	err = helper.DecodeMapStruct(input.Body, request)
+	if err != nil {
+		return &core.ApiResourceOutput{Body: err.Error(), Status: http.StatusBadRequest}, nil
+	}
+	if validationErr := vld.Struct(request); validationErr != nil {
+		return nil, errors.BadInput.Wrap(validationErr, `input json error`)
+	}
+	records, err := makeDeploymentRecords(connection.ID, request, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	err = saveDeploymentRecords(basicRes.GetDal(), connection.ID, request.Id, records)
+	if err != nil {
+		return nil, err
+	}
+	return &core.ApiResourceOutput{Body: nil, Status: http.StatusOK}, nil
+}
+
+// makeDeploymentRecords converts the request into domain layer records, the ids are derived from the
+// connection and the client supplied id so that they stay the same across retries
+func makeDeploymentRecords(connectionId uint64, request *WebhookDeploymentReq, now time.Time) (*webhookDeploymentRecords, errors.Error) {
+	scopeId := fmt.Sprintf("%s:%d", "webhook", connectionId)
+	pipelineId := fmt.Sprintf("%s:%d:%s:%s", "webhook", connectionId, "deployment", request.Id)
+	result := request.Result
+	if result == `` {
+		result = devops.SUCCESS
+	}
+	environment := request.Environment
+	if environment == `` {
+		environment = devops.PRODUCTION
+	}
+	startedDate := now
+	finishedDate := now
+	if request.StartedDate != nil {
+		startedDate = *request.StartedDate
+		if request.FinishedDate != nil {
+			finishedDate = *request.FinishedDate
+		}
+	}
+	if finishedDate.Before(startedDate) {
+		return nil, errors.BadInput.New(`end_time must not be earlier than start_time`)
+	}
+	durationSec := uint64(finishedDate.Sub(startedDate).Seconds())
+	name := request.Name
+	if name == `` {
+		name = fmt.Sprintf(`deployment %s`, request.Id)
+	}
+
+	records := &webhookDeploymentRecords{
+		pipeline: &devops.CICDPipeline{
+			DomainEntity: domainlayer.DomainEntity{
+				Id: pipelineId,
+			},
+			Name:         name,
+			Result:       result,
+			Status:       devops.DONE,
+			Type:         devops.DEPLOYMENT,
+			CreatedDate:  startedDate,
+			FinishedDate: &finishedDate,
+			DurationSec:  durationSec,
+			Environment:  environment,
+			CicdScopeId:  scopeId,
+		},
+	}
+	seen := make(map[string]bool)
+	for _, commit := range request.DeploymentCommits {
+		repoId := commit.RepoId
+		if repoId == `` {
+			repoId = commit.RepoUrl
+		}
+		if seen[repoId] {
+			return nil, errors.BadInput.New(fmt.Sprintf(`repo %s is deployed more than once`, repoId))
+		}
+		seen[repoId] = true
+		repoHash16 := fmt.Sprintf("%x", md5.Sum([]byte(repoId)))[:16]
+		records.tasks = append(records.tasks, &devops.CICDTask{
+			DomainEntity: domainlayer.DomainEntity{
+				Id: fmt.Sprintf("%s:%s", pipelineId, repoHash16),
+			},
+			PipelineId:   pipelineId,
+			Name:         fmt.Sprintf(`deployment %s of %s`, request.Id, commit.RepoUrl),
+			Result:       result,
+			Status:       devops.DONE,
+			Type:         devops.DEPLOYMENT,
+			Environment:  environment,
+			StartedDate:  startedDate,
+			FinishedDate: &finishedDate,
+			DurationSec:  durationSec,
+			CicdScopeId:  scopeId,
+		})
+		records.commits = append(records.commits, &devops.CiCDPipelineCommit{
+			PipelineId: pipelineId,
+			CommitSha:  commit.CommitSha,
+			Branch:     commit.Branch,
+			RepoId:     repoId,
+			Repo:       commit.RepoUrl,
+		})
+		records.deployCommits = append(records.deployCommits, &models.WebhookDeploymentCommit{
+			ConnectionId:  connectionId,
+			DeploymentId:  request.Id,
+			RepoId:        repoId,
+			RepoUrl:       commit.RepoUrl,
+			Branch:        commit.Branch,
+			CommitSha:     commit.CommitSha,
+			PrevCommitSha: commit.PrevCommitSha,
+			PipelineId:    pipelineId,
+		})
+	}
+	return records, nil
+}
+
+// saveDeploymentRecords replaces the records of the deployment in one transaction
+func saveDeploymentRecords(db dal.Dal, connectionId uint64, deploymentId string, records *webhookDeploymentRecords) (err errors.Error) {
+	tx := db.Begin()
+	defer func() {
+		if r := recover(); r != nil || err != nil {
+			if rollbackErr := tx.Rollback(); rollbackErr != nil {
+				basicRes.GetLogger().Error(rollbackErr, "failed to rollback the deployment %s", deploymentId)
+			}
+			if r != nil {
+				err = errors.Default.New(fmt.Sprintf("panic while saving the deployment %s: %v", deploymentId, r))
+			}
+		}
+	}()
+
+	// the repos of a retried deployment may differ from the last attempt, remove the stale ones first
+	pipelineId := records.pipeline.Id
+	err = tx.Delete(&devops.CICDTask{}, dal.Where("pipeline_id = ?", pipelineId))
+	if err != nil {
+		return err
+	}
+	err = tx.Delete(&devops.CiCDPipelineCommit{}, dal.Where("pipeline_id = ?", pipelineId))
+	if err != nil {
+		return err
+	}
+	err = tx.Delete(&models.WebhookDeploymentCommit{}, dal.Where("connection_id = ? AND deployment_id = ?", connectionId, deploymentId))
+	if err != nil {
+		return err
+	}
+
+	err = tx.CreateOrUpdate(records.pipeline)
+	if err != nil {
+		return err
+	}
+	err = tx.CreateOrUpdate(records.tasks)
+	if err != nil {
+		return err
+	}
+	err = tx.CreateOrUpdate(records.commits)
+	if err != nil {
+		return err
+	}
+	err = tx.CreateOrUpdate(records.deployCommits)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,75 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"testing"
+	"time"
+
+	"github.com/apache/incubator-devlake/models/domainlayer/devops"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMakeDeploymentRecords(t *testing.T) {
+	startedDate := time.Date(2023, 1, 20, 12, 0, 0, 0, time.UTC)
+	finishedDate := startedDate.Add(10 * time.Minute)
+	request := &WebhookDeploymentReq{
+		Id:           "release-1",
+		Environment:  devops.STAGING,
+		StartedDate:  &startedDate,
+		FinishedDate: &finishedDate,
+		DeploymentCommits: []WebhookDeploymentCommitReq{
+			{RepoUrl: "https://github.com/apache/incubator-devlake", Branch: "main", CommitSha: "sha1", PrevCommitSha: "sha0"},
+			{RepoId: "github:GithubRepo:1:2", RepoUrl: "https://github.com/apache/incubator-devlake-website", CommitSha: "sha2"},
+		},
+	}
+	records, err := makeDeploymentRecords(1, request, time.Now())
+	assert.Nil(t, err)
+
+	pipeline := records.pipeline
+	assert.Equal(t, "webhook:1:deployment:release-1", pipeline.Id)
+	assert.Equal(t, "deployment release-1", pipeline.Name)
+	assert.Equal(t, devops.SUCCESS, pipeline.Result)
+	assert.Equal(t, devops.STAGING, pipeline.Environment)
+	assert.Equal(t, uint64(600), pipeline.DurationSec)
+
+	assert.Len(t, records.tasks, 2)
+	assert.NotEqual(t, records.tasks[0].Id, records.tasks[1].Id)
+	for _, task := range records.tasks {
+		assert.Equal(t, pipeline.Id, task.PipelineId)
+		assert.Equal(t, devops.DEPLOYMENT, task.Type)
+		assert.Equal(t, "webhook:1", task.CicdScopeId)
+	}
+	assert.Equal(t, "https://github.com/apache/incubator-devlake", records.commits[0].RepoId)
+	assert.Equal(t, "github:GithubRepo:1:2", records.commits[1].RepoId)
+	assert.Equal(t, "sha0", records.deployCommits[0].PrevCommitSha)
+
+	// ids must be stable across retries
+	again, err := makeDeploymentRecords(1, request, time.Now())
+	assert.Nil(t, err)
+	assert.Equal(t, records.tasks[0].Id, again.tasks[0].Id)
+
+	request.DeploymentCommits = append(request.DeploymentCommits, WebhookDeploymentCommitReq{RepoId: "github:GithubRepo:1:2", RepoUrl: "x", CommitSha: "sha3"})
+	_, err = makeDeploymentRecords(1, request, time.Now())
+	assert.NotNil(t, err)
+
+	request.DeploymentCommits = request.DeploymentCommits[:1]
+	request.FinishedDate = &time.Time{}
+	_, err = makeDeploymentRecords(1, request, time.Now())
+	assert.NotNil(t, err)
+}
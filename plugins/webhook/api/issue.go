@@ -91,42 +91,7 @@ func PostIssue(input *core.ApiResourceInput) (*core.ApiResourceOutput, errors.Er
 	}
 
 	db := basicRes.GetDal()
-	domainIssue := &ticket.Issue{
-		DomainEntity: domainlayer.DomainEntity{
-			Id: fmt.Sprintf("%s:%d:%s:%s", "webhook", connection.ID, request.BoardKey, request.IssueKey),
-		},
-		Url:                     request.Url,
-		IssueKey:                request.IssueKey,
-		Title:                   request.Title,
-		Description:             request.Description,
-		EpicKey:                 request.EpicKey,
-		Type:                    request.Type,
-		Status:                  request.Status,
-		OriginalStatus:          request.OriginalStatus,
-		StoryPoint:              request.StoryPoint,
-		ResolutionDate:          request.ResolutionDate,
-		CreatedDate:             request.CreatedDate,
-		UpdatedDate:             request.UpdatedDate,
-		LeadTimeMinutes:         int64(request.LeadTimeMinutes),
-		Priority:                request.Priority,
-		OriginalEstimateMinutes: request.OriginalEstimateMinutes,
-		TimeSpentMinutes:        request.TimeSpentMinutes,
-		TimeRemainingMinutes:    request.TimeRemainingMinutes,
-		CreatorName:             request.CreatorName,
-		AssigneeName:            request.AssigneeName,
-		Severity:                request.Severity,
-		Component:               request.Component,
-	}
-	if request.CreatorId != "" {
-		domainIssue.CreatorId = fmt.Sprintf("%s:%d:%s", "webhook", connection.ID, request.CreatorId)
-	}
-	if request.AssigneeId != "" {
-		domainIssue.AssigneeId = fmt.Sprintf("%s:%d:%s", "webhook", connection.ID, request.AssigneeId)
-	}
-	if request.ParentIssueKey != "" {
-		domainIssue.ParentIssueId = fmt.Sprintf("%s:%d:%s:%s", "webhook", connection.ID, request.BoardKey, request.ParentIssueKey)
-	}
-
+	domainIssue := newDomainIssue(connection.ID, request)
 	domainBoardId := fmt.Sprintf("%s:%d:%s", "webhook", connection.ID, request.BoardKey)
 
 	boardIssue := &ticket.BoardIssue{
@@ -198,3 +163,309 @@ func CloseIssue(input *core.ApiResourceInput) (*core.ApiResourceOutput, errors.E
 	}
 	return &core.ApiResourceOutput{Body: nil, Status: http.StatusOK}, nil
 }
+
+// newDomainIssue converts the request into the domain layer issue
+func newDomainIssue(connectionId uint64, request *WebhookIssueRequest) *ticket.Issue {
+	domainIssue := &ticket.Issue{
+		DomainEntity: domainlayer.DomainEntity{
+			Id: fmt.Sprintf("%s:%d:%s:%s", "webhook", connectionId, request.BoardKey, request.IssueKey),
+		},
+		Url:                     request.Url,
+		IssueKey:                request.IssueKey,
+		Title:                   request.Title,
+		Description:             request.Description,
+		EpicKey:                 request.EpicKey,
+		Type:                    request.Type,
+		Status:                  request.Status,
+		OriginalStatus:          request.OriginalStatus,
+		StoryPoint:              request.StoryPoint,
+		ResolutionDate:          request.ResolutionDate,
+		CreatedDate:             request.CreatedDate,
+		UpdatedDate:             request.UpdatedDate,
+		LeadTimeMinutes:         int64(request.LeadTimeMinutes),
+		Priority:                request.Priority,
+		OriginalEstimateMinutes: request.OriginalEstimateMinutes,
+		TimeSpentMinutes:        request.TimeSpentMinutes,
+		TimeRemainingMinutes:    request.TimeRemainingMinutes,
+		CreatorName:             request.CreatorName,
+		AssigneeName:            request.AssigneeName,
+		Severity:                request.Severity,
+		Component:               request.Component,
+	}
+	if request.CreatorId != "" {
+		domainIssue.CreatorId = fmt.Sprintf("%s:%d:%s", "webhook", connectionId, request.CreatorId)
+	}
+	if request.AssigneeId != "" {
+		domainIssue.AssigneeId = fmt.Sprintf("%s:%d:%s", "webhook", connectionId, request.AssigneeId)
+	}
+	if request.ParentIssueKey != "" {
+		domainIssue.ParentIssueId = fmt.Sprintf("%s:%d:%s:%s", "webhook", connectionId, request.BoardKey, request.ParentIssueKey)
+	}
+	return domainIssue
+}
+
+type WebhookIssueBatchItem struct {
+	WebhookIssueRequest `mapstructure:",squash"`
+	// Labels replaces the labels of the issue if present
+	Labels []string `mapstructure:"labels"`
+	// SprintKeys replaces the sprints of the issue if present, the sprints belong to the board of the issue
+	SprintKeys []string `mapstructure:"sprint_keys"`
+}
+
+type WebhookSprintRequest struct {
+	BoardKey      string     `mapstructure:"board_key" validate:"required"`
+	SprintKey     string     `mapstructure:"sprint_key" validate:"required"`
+	Name          string     `mapstructure:"name" validate:"required"`
+	Url           string     `mapstructure:"url"`
+	Status        string     `mapstructure:"status" validate:"omitempty,oneof=ACTIVE CLOSED FUTURE"`
+	StartedDate   *time.Time `mapstructure:"started_date"`
+	EndedDate     *time.Time `mapstructure:"ended_date"`
+	CompletedDate *time.Time `mapstructure:"completed_date"`
+}
+
+type WebhookIssueKeyRequest struct {
+	BoardKey string `mapstructure:"board_key" validate:"required"`
+	IssueKey string `mapstructure:"issue_key" validate:"required"`
+}
+
+// webhookIssueBatchRecords are the domain layer records of an issue batch
+type webhookIssueBatchRecords struct {
+	boardIds      []string
+	issues        []*ticket.Issue
+	boardIssues   []*ticket.BoardIssue
+	sprints       []*ticket.Sprint
+	boardSprints  []*ticket.BoardSprint
+	issueLabels   []*ticket.IssueLabel
+	sprintIssues  []*ticket.SprintIssue
+	labeledIds    []string
+	sprintedIds   []string
+	issueResults  []*WebhookBatchItemResult
+	sprintResults []*WebhookBatchItemResult
+}
+
+// PostIssuesBatch
+// @Summary receive issues and sprints in batch and save them
+// @Description Receive up to 1000 issues, together with the sprints they belong to, and save them in one transaction.<br/>
+// @Description Each issue is the same as the one of `/plugins/webhook/:connectionId/issues`, plus `labels` and `sprint_keys` which replace the labels and sprints of the issue when present.<br/>
+// @Description Nothing is saved if any item is invalid, the results tell the error of each item.<br/>
+// @Description example: {"sprints":[{"board_key":"DLK","sprint_key":"S1","name":"Sprint 1","status":"ACTIVE","started_date":"2020-01-01T12:00:00+00:00"}],"issues":[{"board_key":"DLK","issue_key":"DLK-1234","title":"a feature from DLK","type":"BUG","status":"TODO","original_status":"created","created_date":"2020-01-01T12:00:00+00:00","labels":["backend"],"sprint_keys":["S1"]}]}
+// @Tags plugins/webhook
+// @Success 200  {object} WebhookBatchResponse
+// @Failure 400  {object} WebhookBatchResponse
+// @Failure 500  {string} errcode.Error "Internal Error"
+// @Router /plugins/webhook/:connectionId/issues/batch [POST]
+func PostIssuesBatch(input *core.ApiResourceInput) (*core.ApiResourceOutput, errors.Error) {
+	connection := &models.WebhookConnection{}
+	err := connectionHelper.First(connection, input.Params)
+	if err != nil {
+		return nil, err
+	}
+	issueItems, err := batchItems(input.Body, "issues")
+	if err != nil {
+		return nil, err
+	}
+	var sprintItems []map[string]interface{}
+	if _, ok := input.Body["sprints"]; ok {
+		sprintItems, err = batchItems(input.Body, "sprints")
+		if err != nil {
+			return nil, err
+		}
+	}
+	records := makeIssueBatchRecords(connection.ID, issueItems, sprintItems)
+	if batchFailed(records.issueResults) || batchFailed(records.sprintResults) {
+		return issueBatchOutput(records), nil
+	}
+
+	err = saveInTransaction(func(tx dal.Dal, saver *batchSaver) errors.Error {
+		// only create the boards that don't exist yet, the existing ones may be updated by other means
+		var existingBoardIds []string
+		err := tx.Pluck("id", &existingBoardIds, dal.From(&ticket.Board{}), dal.Where("id IN ?", records.boardIds))
+		if err != nil {
+			return err
+		}
+		existing := make(map[string]bool, len(existingBoardIds))
+		for _, id := range existingBoardIds {
+			existing[id] = true
+		}
+		for _, boardId := range records.boardIds {
+			if !existing[boardId] {
+				err = saver.add(&ticket.Board{DomainEntity: domainlayer.DomainEntity{Id: boardId}})
+				if err != nil {
+					return err
+				}
+			}
+		}
+		if len(records.labeledIds) > 0 {
+			err = tx.Delete(&ticket.IssueLabel{}, dal.Where("issue_id IN ?", records.labeledIds))
+			if err != nil {
+				return err
+			}
+		}
+		if len(records.sprintedIds) > 0 {
+			err = tx.Delete(&ticket.SprintIssue{}, dal.Where("issue_id IN ?", records.sprintedIds))
+			if err != nil {
+				return err
+			}
+		}
+		for _, group := range []interface{}{records.sprints, records.boardSprints, records.issues, records.boardIssues, records.issueLabels, records.sprintIssues} {
+			err = saver.addAll(group)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return issueBatchOutput(records), nil
+}
+
+// makeIssueBatchRecords decodes and validates all items and converts the valid ones into domain layer records
+func makeIssueBatchRecords(connectionId uint64, issueItems []map[string]interface{}, sprintItems []map[string]interface{}) *webhookIssueBatchRecords {
+	records := &webhookIssueBatchRecords{}
+	boardIds := make(map[string]bool)
+	addBoard := func(boardKey string) string {
+		boardId := fmt.Sprintf("%s:%d:%s", "webhook", connectionId, boardKey)
+		if !boardIds[boardId] {
+			boardIds[boardId] = true
+			records.boardIds = append(records.boardIds, boardId)
+		}
+		return boardId
+	}
+	sprintId := func(boardKey, sprintKey string) string {
+		return fmt.Sprintf("%s:%d:%s:sprint:%s", "webhook", connectionId, boardKey, sprintKey)
+	}
+
+	for i, item := range sprintItems {
+		result := &WebhookBatchItemResult{Index: i}
+		records.sprintResults = append(records.sprintResults, result)
+		request := &WebhookSprintRequest{}
+		if !decodeBatchItem(item, request, result) {
+			continue
+		}
+		boardId := addBoard(request.BoardKey)
+		sprint := &ticket.Sprint{
+			DomainEntity:    domainlayer.DomainEntity{Id: sprintId(request.BoardKey, request.SprintKey)},
+			Name:            request.Name,
+			Url:             request.Url,
+			Status:          request.Status,
+			StartedDate:     request.StartedDate,
+			EndedDate:       request.EndedDate,
+			CompletedDate:   request.CompletedDate,
+			OriginalBoardID: boardId,
+		}
+		result.Id = sprint.Id
+		records.sprints = append(records.sprints, sprint)
+		records.boardSprints = append(records.boardSprints, &ticket.BoardSprint{BoardId: boardId, SprintId: sprint.Id})
+	}
+
+	for i, item := range issueItems {
+		result := &WebhookBatchItemResult{Index: i}
+		records.issueResults = append(records.issueResults, result)
+		request := &WebhookIssueBatchItem{}
+		if !decodeBatchItem(item, request, result) {
+			continue
+		}
+		domainIssue := newDomainIssue(connectionId, &request.WebhookIssueRequest)
+		result.Id = domainIssue.Id
+		records.issues = append(records.issues, domainIssue)
+		records.boardIssues = append(records.boardIssues, &ticket.BoardIssue{
+			BoardId: addBoard(request.BoardKey),
+			IssueId: domainIssue.Id,
+		})
+		if request.Labels != nil {
+			records.labeledIds = append(records.labeledIds, domainIssue.Id)
+			for _, label := range request.Labels {
+				records.issueLabels = append(records.issueLabels, &ticket.IssueLabel{IssueId: domainIssue.Id, LabelName: label})
+			}
+		}
+		if request.SprintKeys != nil {
+			records.sprintedIds = append(records.sprintedIds, domainIssue.Id)
+			for _, sprintKey := range request.SprintKeys {
+				records.sprintIssues = append(records.sprintIssues, &ticket.SprintIssue{
+					SprintId: sprintId(request.BoardKey, sprintKey),
+					IssueId:  domainIssue.Id,
+				})
+			}
+		}
+	}
+	return records
+}
+
+func issueBatchOutput(records *webhookIssueBatchRecords) *core.ApiResourceOutput {
+	output := batchOutput(append(append([]*WebhookBatchItemResult{}, records.issueResults...), records.sprintResults...))
+	output.Body = map[string]interface{}{
+		"success": output.Status == http.StatusOK,
+		"issues":  records.issueResults,
+		"sprints": records.sprintResults,
+	}
+	return output
+}
+
+// CloseIssuesBatch
+// @Summary set the status of issues to DONE in batch
+// @Description Set the status of up to 1000 issues to DONE in one transaction, nothing is changed if any of them is not found.<br/>
+// @Description example: {"issues":[{"board_key":"DLK","issue_key":"DLK-1234"}]}
+// @Tags plugins/webhook
+// @Success 200  {object} WebhookBatchResponse
+// @Failure 400  {object} WebhookBatchResponse
+// @Failure 500  {string} errcode.Error "Internal Error"
+// @Router /plugins/webhook/:connectionId/issues/batch/close [POST]
+func CloseIssuesBatch(input *core.ApiResourceInput) (*core.ApiResourceOutput, errors.Error) {
+	connection := &models.WebhookConnection{}
+	err := connectionHelper.First(connection, input.Params)
+	if err != nil {
+		return nil, err
+	}
+	items, err := batchItems(input.Body, "issues")
+	if err != nil {
+		return nil, err
+	}
+	results := make([]*WebhookBatchItemResult, len(items))
+	issueIds := make([]string, 0, len(items))
+	for i, item := range items {
+		results[i] = &WebhookBatchItemResult{Index: i}
+		request := &WebhookIssueKeyRequest{}
+		if decodeBatchItem(item, request, results[i]) {
+			results[i].Id = fmt.Sprintf("%s:%d:%s:%s", "webhook", connection.ID, request.BoardKey, request.IssueKey)
+			issueIds = append(issueIds, results[i].Id)
+		}
+	}
+	if batchFailed(results) {
+		return batchOutput(results), nil
+	}
+
+	err = saveInTransaction(func(tx dal.Dal, saver *batchSaver) errors.Error {
+		var domainIssues []*ticket.Issue
+		err := tx.All(&domainIssues, dal.Where("id IN ?", issueIds))
+		if err != nil {
+			return err
+		}
+		found := make(map[string]bool, len(domainIssues))
+		for _, domainIssue := range domainIssues {
+			found[domainIssue.Id] = true
+		}
+		for _, result := range results {
+			if !found[result.Id] {
+				result.Error = "issue not found"
+			}
+		}
+		if batchFailed(results) {
+			return nil
+		}
+		for _, domainIssue := range domainIssues {
+			domainIssue.Status = ticket.DONE
+			domainIssue.OriginalStatus = ``
+			err = saver.add(domainIssue)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return batchOutput(results), nil
+}
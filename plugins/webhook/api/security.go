@@ -0,0 +1,271 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/apache/incubator-devlake/errors"
+	"github.com/apache/incubator-devlake/plugins/core"
+	"github.com/apache/incubator-devlake/plugins/core/dal"
+	"github.com/apache/incubator-devlake/plugins/webhook/models"
+)
+
+const (
+	HEADER_API_KEY   = "X-Api-Key"
+	HEADER_TIMESTAMP = "X-Devlake-Timestamp"
+	HEADER_SIGNATURE = "X-Devlake-Signature"
+	// signatures older or newer than the tolerance are rejected, and those within are stored to prevent replays
+	signatureTolerance = 5 * time.Minute
+)
+
+// Secured guards the handlers receiving data from outside and those managing the connection, its api keys and
+// its logs. The checks are enabled by the connection settings, so connections without any api key, signing secret
+// or allowlist accept everyone as they used to, which is how the first api key gets created:
+//   - the client ip must match `IpAllowlist`
+//   - one of the api keys must be sent by `Authorization: Bearer <key>` or `X-Api-Key: <key>`
+//   - with `SigningSecret`, `X-Devlake-Signature` must be `sha256=` followed by the hex encoded
+//     HMAC-SHA256 of `<X-Devlake-Timestamp>.<body>`, where the timestamp is in unix seconds
+func Secured(handler core.ApiResourceHandler) core.ApiResourceHandler {
+	return func(input *core.ApiResourceInput) (*core.ApiResourceOutput, errors.Error) {
+		startedAt := time.Now()
+		connection := &models.WebhookConnection{}
+		err := connectionHelper.First(connection, input.Params)
+		if err != nil {
+			return nil, err
+		}
+		db := basicRes.GetDal()
+		body, err := readRawBody(input)
+		if err != nil {
+			return nil, err
+		}
+		apiKeyId, err := authorize(db, connection, input, body, startedAt)
+		var output *core.ApiResourceOutput
+		if err == nil {
+			output, err = handler(input)
+		}
+		if connection.LogRequests {
+			logRequest(db, connection, input, apiKeyId, len(body), output, err, startedAt)
+		}
+		return output, err
+	}
+}
+
+func authorize(db dal.Dal, connection *models.WebhookConnection, input *core.ApiResourceInput, body []byte, now time.Time) (uint64, errors.Error) {
+	if connection.IpAllowlist != "" {
+		ip := clientIp(input.Request, connection.TrustForwardedFor)
+		allowed, err := ipAllowed(connection.IpAllowlist, ip)
+		if err != nil {
+			return 0, err
+		}
+		if !allowed {
+			return 0, errors.Forbidden.New(fmt.Sprintf("ip %s is not allowed", ip))
+		}
+	}
+	apiKeyId, err := verifyApiKey(db, connection.ID, input.Request, now)
+	if err != nil {
+		return 0, err
+	}
+	if connection.SigningSecret != "" {
+		err = verifySignature(db, connection.ID, connection.SigningSecret, input.Request, body, now)
+		if err != nil {
+			return apiKeyId, err
+		}
+	}
+	return apiKeyId, nil
+}
+
+// verifyApiKey returns the id of the key sent by the request, or 0 if the connection has no key
+func verifyApiKey(db dal.Dal, connectionId uint64, req *http.Request, now time.Time) (uint64, errors.Error) {
+	count, err := db.Count(dal.From(&models.WebhookApiKey{}), dal.Where("connection_id = ?", connectionId))
+	if err != nil {
+		return 0, err
+	}
+	if count == 0 {
+		return 0, nil
+	}
+	key := ""
+	if req != nil {
+		key = req.Header.Get(HEADER_API_KEY)
+		if authorization := req.Header.Get("Authorization"); key == "" && strings.HasPrefix(authorization, "Bearer ") {
+			key = strings.TrimSpace(strings.TrimPrefix(authorization, "Bearer "))
+		}
+	}
+	if key == "" {
+		return 0, errors.Unauthorized.New("api key is required")
+	}
+	apiKey := &models.WebhookApiKey{}
+	err = db.First(apiKey, dal.Where("connection_id = ? AND key_hash = ?", connectionId, hashApiKey(key)))
+	if err != nil {
+		if db.IsErrorNotFound(err) {
+			return 0, errors.Unauthorized.New("invalid api key")
+		}
+		return 0, err
+	}
+	if apiKey.ExpiredAt != nil && apiKey.ExpiredAt.Before(now) {
+		return apiKey.ID, errors.Unauthorized.New("api key expired")
+	}
+	err = db.UpdateColumn(&models.WebhookApiKey{}, "last_used_at", now, dal.Where("id = ?", apiKey.ID))
+	if err != nil {
+		return apiKey.ID, err
+	}
+	return apiKey.ID, nil
+}
+
+func verifySignature(db dal.Dal, connectionId uint64, secret string, req *http.Request, body []byte, now time.Time) errors.Error {
+	if req == nil {
+		return errors.Unauthorized.New("signature is required")
+	}
+	timestamp := req.Header.Get(HEADER_TIMESTAMP)
+	signature := strings.TrimPrefix(req.Header.Get(HEADER_SIGNATURE), "sha256=")
+	if timestamp == "" || signature == "" {
+		return errors.Unauthorized.New(fmt.Sprintf("headers %s and %s are required", HEADER_TIMESTAMP, HEADER_SIGNATURE))
+	}
+	seconds, parseErr := strconv.ParseInt(timestamp, 10, 64)
+	if parseErr != nil {
+		return errors.Unauthorized.New(fmt.Sprintf("invalid %s", HEADER_TIMESTAMP))
+	}
+	signedAt := time.Unix(seconds, 0)
+	if math.Abs(float64(now.Sub(signedAt))) > float64(signatureTolerance) {
+		return errors.Unauthorized.New("request timestamp is outside of the tolerance")
+	}
+	expected := Sign(secret, timestamp, body)
+	if !hmac.Equal([]byte(expected), []byte(strings.ToLower(signature))) {
+		return errors.Unauthorized.New("invalid signature")
+	}
+	return rememberSignature(db, connectionId, expected, signedAt.Add(signatureTolerance), now)
+}
+
+// rememberSignature stores the signature until it expires, the primary key rejects the ones stored before
+func rememberSignature(db dal.Dal, connectionId uint64, signature string, expiredAt time.Time, now time.Time) errors.Error {
+	err := db.Delete(&models.WebhookSignature{}, dal.Where("expired_at < ?", now))
+	if err != nil {
+		return err
+	}
+	err = db.Create(&models.WebhookSignature{
+		ConnectionId: connectionId,
+		Signature:    signature,
+		ExpiredAt:    expiredAt,
+	})
+	if err != nil {
+		if db.IsDuplicationError(err) {
+			return errors.Unauthorized.New("request was replayed")
+		}
+		return err
+	}
+	return nil
+}
+
+// Sign returns the hex encoded HMAC-SHA256 of `<timestamp>.<body>`, the value expected in `X-Devlake-Signature`
+// after the `sha256=` prefix
+func Sign(secret string, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func clientIp(req *http.Request, trustForwardedFor bool) string {
+	if req == nil {
+		return ""
+	}
+	if trustForwardedFor {
+		if forwardedFor := req.Header.Get("X-Forwarded-For"); forwardedFor != "" {
+			return strings.TrimSpace(strings.Split(forwardedFor, ",")[0])
+		}
+	}
+	host, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		return req.RemoteAddr
+	}
+	return host
+}
+
+func ipAllowed(allowlist string, ip string) (bool, errors.Error) {
+	parsedIp := net.ParseIP(ip)
+	if parsedIp == nil {
+		return false, nil
+	}
+	for _, entry := range strings.Split(allowlist, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		if strings.Contains(entry, "/") {
+			_, ipNet, err := net.ParseCIDR(entry)
+			if err != nil {
+				return false, errors.Default.Wrap(err, fmt.Sprintf("invalid cidr %s in the allowlist", entry))
+			}
+			if ipNet.Contains(parsedIp) {
+				return true, nil
+			}
+		} else if allowedIp := net.ParseIP(entry); allowedIp != nil && allowedIp.Equal(parsedIp) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func readRawBody(input *core.ApiResourceInput) ([]byte, errors.Error) {
+	if input.Request == nil || input.Request.Body == nil {
+		return nil, nil
+	}
+	body, err := io.ReadAll(input.Request.Body)
+	if err != nil {
+		return nil, errors.Convert(err)
+	}
+	return body, nil
+}
+
+func logRequest(db dal.Dal, connection *models.WebhookConnection, input *core.ApiResourceInput, apiKeyId uint64, bodySize int, output *core.ApiResourceOutput, err errors.Error, startedAt time.Time) {
+	requestLog := &models.WebhookRequestLog{
+		ConnectionId: connection.ID,
+		ApiKeyId:     apiKeyId,
+		BodySize:     bodySize,
+		StatusCode:   http.StatusOK,
+		DurationMs:   time.Since(startedAt).Milliseconds(),
+	}
+	if input.Request != nil {
+		requestLog.Method = input.Request.Method
+		requestLog.Path = input.Request.URL.Path
+		requestLog.RemoteIp = clientIp(input.Request, connection.TrustForwardedFor)
+	}
+	if err != nil {
+		requestLog.StatusCode = err.GetType().GetHttpCode()
+		if requestLog.StatusCode == 0 {
+			requestLog.StatusCode = http.StatusInternalServerError
+		}
+		requestLog.Error = err.Messages().Format()
+	} else if output != nil && output.Status >= http.StatusContinue {
+		requestLog.StatusCode = output.Status
+	}
+	if logErr := db.Create(requestLog); logErr != nil {
+		basicRes.GetLogger().Error(logErr, "failed to log the request to webhook connection %d", connection.ID)
+	}
+}
@@ -0,0 +1,117 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/apache/incubator-devlake/errors"
+	"github.com/apache/incubator-devlake/mocks"
+	"github.com/apache/incubator-devlake/plugins/core/dal"
+	"github.com/apache/incubator-devlake/plugins/webhook/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestVerifySignature(t *testing.T) {
+	now := time.Now()
+	body := []byte(`{"id":"release-1"}`)
+	newRequest := func(timestamp time.Time, signature string) *http.Request {
+		req := httptest.NewRequest(http.MethodPost, "/plugins/webhook/connections/1/deployments", strings.NewReader(string(body)))
+		req.Header.Set(HEADER_TIMESTAMP, strconv.FormatInt(timestamp.Unix(), 10))
+		req.Header.Set(HEADER_SIGNATURE, signature)
+		return req
+	}
+	signature := "sha256=" + Sign("secret", strconv.FormatInt(now.Unix(), 10), body)
+
+	// the signatures are stored until they expire, the database rejects the ones seen before
+	mockDal := new(mocks.Dal)
+	duplicated := errors.Default.New("Duplicate entry")
+	mockDal.On("Delete", mock.Anything, mock.Anything).Return(nil)
+	mockDal.On("Create", &models.WebhookSignature{
+		ConnectionId: 1,
+		Signature:    strings.TrimPrefix(signature, "sha256="),
+		ExpiredAt:    time.Unix(now.Unix(), 0).Add(signatureTolerance),
+	}, mock.Anything).Return(nil).Once()
+	mockDal.On("Create", mock.Anything, mock.Anything).Return(duplicated).Once()
+	mockDal.On("Create", mock.Anything, mock.Anything).Return(nil).Once()
+	mockDal.On("IsDuplicationError", duplicated).Return(true)
+
+	assert.Nil(t, verifySignature(mockDal, 1, "secret", newRequest(now, signature), body, now))
+	// the same request can not be accepted twice
+	err := verifySignature(mockDal, 1, "secret", newRequest(now, signature), body, now)
+	assert.NotNil(t, err)
+	assert.Equal(t, errors.Unauthorized, err.GetType())
+	// but the same payload for another connection is fine
+	assert.Nil(t, verifySignature(mockDal, 2, "secret", newRequest(now, signature), body, now))
+
+	assert.NotNil(t, verifySignature(mockDal, 3, "another secret", newRequest(now, signature), body, now))
+	assert.NotNil(t, verifySignature(mockDal, 3, "secret", newRequest(now, signature), []byte(`{"id":"release-2"}`), now))
+	assert.NotNil(t, verifySignature(mockDal, 3, "secret", newRequest(now, ""), body, now))
+	assert.NotNil(t, verifySignature(mockDal, 3, "secret", nil, body, now))
+
+	staled := now.Add(-10 * time.Minute)
+	staledSignature := "sha256=" + Sign("secret", strconv.FormatInt(staled.Unix(), 10), body)
+	assert.NotNil(t, verifySignature(mockDal, 3, "secret", newRequest(staled, staledSignature), body, now))
+	mockDal.AssertExpectations(t)
+	mockDal.AssertNumberOfCalls(t, "Create", 3)
+}
+
+func TestRememberSignaturePurgesExpired(t *testing.T) {
+	now := time.Now()
+	mockDal := new(mocks.Dal)
+	mockDal.On("Delete", &models.WebhookSignature{}, []dal.Clause{dal.Where("expired_at < ?", now)}).Return(nil).Once()
+	mockDal.On("Create", mock.Anything, mock.Anything).Return(nil).Once()
+	assert.Nil(t, rememberSignature(mockDal, 1, "a", now.Add(time.Minute), now))
+	mockDal.AssertExpectations(t)
+}
+
+func TestIpAllowed(t *testing.T) {
+	allowlist := "10.0.0.0/8, 192.168.1.10,2001:db8::/32"
+	for ip, expected := range map[string]bool{
+		"10.1.2.3":      true,
+		"192.168.1.10":  true,
+		"192.168.1.11":  false,
+		"2001:db8::1":   true,
+		"not an ip":     false,
+		"172.16.0.1":    false,
+		"::ffff:a00:1":  true,
+		"2001:db9::1":   false,
+		"192.168.1.100": false,
+	} {
+		allowed, err := ipAllowed(allowlist, ip)
+		assert.Nil(t, err)
+		assert.Equal(t, expected, allowed, ip)
+	}
+	_, err := ipAllowed("10.0.0.0/99", "10.0.0.1")
+	assert.NotNil(t, err)
+}
+
+func TestClientIp(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+	req.RemoteAddr = "10.0.0.2:52100"
+	req.Header.Set("X-Forwarded-For", "203.0.113.7, 10.0.0.1")
+	assert.Equal(t, "10.0.0.2", clientIp(req, false))
+	assert.Equal(t, "203.0.113.7", clientIp(req, true))
+	assert.Equal(t, "", clientIp(nil, true))
+}
@@ -22,6 +22,7 @@ import (
 	"github.com/apache/incubator-devlake/plugins/core"
 	"github.com/apache/incubator-devlake/plugins/core/dal"
 	"github.com/apache/incubator-devlake/plugins/webhook/api"
+	"github.com/apache/incubator-devlake/plugins/webhook/models"
 	"github.com/apache/incubator-devlake/plugins/webhook/models/migrationscripts"
 )
 
@@ -44,7 +45,13 @@ func (plugin Webhook) Init(basicRes core.BasicRes) errors.Error {
 }
 
 func (plugin Webhook) GetTablesInfo() []dal.Tabler {
-	return []dal.Tabler{}
+	return []dal.Tabler{
+		&models.WebhookConnection{},
+		&models.WebhookDeploymentCommit{},
+		&models.WebhookApiKey{},
+		&models.WebhookRequestLog{},
+		&models.WebhookSignature{},
+	}
 }
 
 func (plugin Webhook) MakeDataSourcePipelinePlanV200(connectionId uint64, _ []*core.BlueprintScopeV200, _ core.BlueprintSyncPolicy) (pp core.PipelinePlan, sc []core.Scope, err errors.Error) {
@@ -68,23 +75,45 @@ func (plugin Webhook) ApiResources() map[string]map[string]core.ApiResourceHandl
 		},
 		"connections/:connectionId": {
 			"GET":    api.GetConnection,
-			"PATCH":  api.PatchConnection,
-			"DELETE": api.DeleteConnection,
+			"PATCH":  api.Secured(api.PatchConnection),
+			"DELETE": api.Secured(api.DeleteConnection),
+		},
+		"connections/:connectionId/deployments": {
+			"POST": api.Secured(api.PostDeployment),
+		},
+		"connections/:connectionId/api-keys": {
+			"POST": api.Secured(api.PostApiKey),
+			"GET":  api.Secured(api.ListApiKeys),
+		},
+		"connections/:connectionId/api-keys/:keyId": {
+			"DELETE": api.Secured(api.DeleteApiKey),
+		},
+		"connections/:connectionId/logs": {
+			"GET": api.Secured(api.ListRequestLogs),
 		},
 		":connectionId/cicd_tasks": {
-			"POST": api.PostCicdTask,
+			"POST": api.Secured(api.PostCicdTask),
+		},
+		":connectionId/cicd_tasks/batch": {
+			"POST": api.Secured(api.PostCicdTasksBatch),
 		},
 		":connectionId/cicd_pipeline/:pipelineName/finish": {
-			"POST": api.PostPipelineFinish,
+			"POST": api.Secured(api.PostPipelineFinish),
 		},
 		":connectionId/deployments": {
-			"POST": api.PostDeploymentCicdTask,
+			"POST": api.Secured(api.PostDeploymentCicdTask),
 		},
 		":connectionId/issues": {
-			"POST": api.PostIssue,
+			"POST": api.Secured(api.PostIssue),
+		},
+		":connectionId/issues/batch": {
+			"POST": api.Secured(api.PostIssuesBatch),
+		},
+		":connectionId/issues/batch/close": {
+			"POST": api.Secured(api.CloseIssuesBatch),
 		},
 		":connectionId/issue/:boardKey/:issueKey/close": {
-			"POST": api.CloseIssue,
+			"POST": api.Secured(api.CloseIssue),
 		},
 	}
 }
@@ -0,0 +1,40 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import (
+	"time"
+
+	"github.com/apache/incubator-devlake/models/common"
+)
+
+// WebhookApiKey authorizes the requests to a webhook connection, only the sha256 of the key is stored,
+// so the key itself is shown just once on creation. Requests require a key once the connection has any
+type WebhookApiKey struct {
+	common.Model
+	ConnectionId uint64     `gorm:"index" json:"connectionId"`
+	Name         string     `gorm:"type:varchar(100)" json:"name"`
+	KeyPrefix    string     `gorm:"type:varchar(20)" json:"keyPrefix"`
+	KeyHash      string     `gorm:"type:varchar(64);uniqueIndex" json:"-"`
+	ExpiredAt    *time.Time `json:"expiredAt"`
+	LastUsedAt   *time.Time `json:"lastUsedAt"`
+}
+
+func (WebhookApiKey) TableName() string {
+	return "_tool_webhook_api_keys"
+}
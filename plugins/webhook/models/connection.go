@@ -21,6 +21,13 @@ import "github.com/apache/incubator-devlake/plugins/helper"
 
 type WebhookConnection struct {
 	helper.BaseConnection `mapstructure:",squash"`
+	// SigningSecret requires the requests to be signed with HMAC-SHA256 when set
+	SigningSecret string `mapstructure:"signingSecret" json:"signingSecret" encrypt:"yes"`
+	// IpAllowlist is a comma separated list of IPs or CIDRs allowed to call the webhook, empty means anyone
+	IpAllowlist string `mapstructure:"ipAllowlist" json:"ipAllowlist"`
+	// TrustForwardedFor takes the client ip from `X-Forwarded-For`, enable it only behind a trusted proxy
+	TrustForwardedFor bool `mapstructure:"trustForwardedFor" json:"trustForwardedFor"`
+	LogRequests       bool `mapstructure:"logRequests" json:"logRequests"`
 }
 
 func (WebhookConnection) TableName() string {
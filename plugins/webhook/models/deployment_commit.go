@@ -0,0 +1,38 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import "github.com/apache/incubator-devlake/models/common"
+
+// WebhookDeploymentCommit keeps the repos and commits of a deployment posted to the webhook, along with the
+// previously deployed commit reported by the client, which the domain layer has no place for
+type WebhookDeploymentCommit struct {
+	common.NoPKModel
+	ConnectionId  uint64 `gorm:"primaryKey"`
+	DeploymentId  string `gorm:"primaryKey;type:varchar(255)"`
+	RepoId        string `gorm:"primaryKey;type:varchar(255)"`
+	RepoUrl       string
+	Branch        string `gorm:"type:varchar(255)"`
+	CommitSha     string `gorm:"type:varchar(255)"`
+	PrevCommitSha string `gorm:"type:varchar(255)"`
+	PipelineId    string `gorm:"type:varchar(255);index"`
+}
+
+func (WebhookDeploymentCommit) TableName() string {
+	return "_tool_webhook_deployment_commits"
+}
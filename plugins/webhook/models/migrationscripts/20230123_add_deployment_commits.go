@@ -0,0 +1,58 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migrationscripts
+
+import (
+	"github.com/apache/incubator-devlake/errors"
+	"github.com/apache/incubator-devlake/helpers/migrationhelper"
+	"github.com/apache/incubator-devlake/models/migrationscripts/archived"
+	"github.com/apache/incubator-devlake/plugins/core"
+)
+
+type addDeploymentCommits struct{}
+
+type webhookDeploymentCommit20230123 struct {
+	archived.NoPKModel
+	ConnectionId  uint64 `gorm:"primaryKey"`
+	DeploymentId  string `gorm:"primaryKey;type:varchar(255)"`
+	RepoId        string `gorm:"primaryKey;type:varchar(255)"`
+	RepoUrl       string
+	Branch        string `gorm:"type:varchar(255)"`
+	CommitSha     string `gorm:"type:varchar(255)"`
+	PrevCommitSha string `gorm:"type:varchar(255)"`
+	PipelineId    string `gorm:"type:varchar(255);index"`
+}
+
+func (webhookDeploymentCommit20230123) TableName() string {
+	return "_tool_webhook_deployment_commits"
+}
+
+func (*addDeploymentCommits) Up(basicRes core.BasicRes) errors.Error {
+	return migrationhelper.AutoMigrateTables(
+		basicRes,
+		&webhookDeploymentCommit20230123{},
+	)
+}
+
+func (*addDeploymentCommits) Version() uint64 {
+	return 20230123090000
+}
+
+func (*addDeploymentCommits) Name() string {
+	return "add _tool_webhook_deployment_commits table"
+}
@@ -0,0 +1,88 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migrationscripts
+
+import (
+	"time"
+
+	"github.com/apache/incubator-devlake/errors"
+	"github.com/apache/incubator-devlake/helpers/migrationhelper"
+	"github.com/apache/incubator-devlake/models/migrationscripts/archived"
+	"github.com/apache/incubator-devlake/plugins/core"
+)
+
+type addConnectionSecurity struct{}
+
+type webhookConnection20230124 struct {
+	SigningSecret     string
+	IpAllowlist       string
+	TrustForwardedFor bool
+	LogRequests       bool
+}
+
+func (webhookConnection20230124) TableName() string {
+	return "_tool_webhook_connections"
+}
+
+type webhookApiKey20230124 struct {
+	archived.Model
+	ConnectionId uint64 `gorm:"index"`
+	Name         string `gorm:"type:varchar(100)"`
+	KeyPrefix    string `gorm:"type:varchar(20)"`
+	KeyHash      string `gorm:"type:varchar(64);uniqueIndex"`
+	ExpiredAt    *time.Time
+	LastUsedAt   *time.Time
+}
+
+func (webhookApiKey20230124) TableName() string {
+	return "_tool_webhook_api_keys"
+}
+
+type webhookRequestLog20230124 struct {
+	archived.Model
+	ConnectionId uint64 `gorm:"index"`
+	ApiKeyId     uint64
+	Method       string `gorm:"type:varchar(10)"`
+	Path         string `gorm:"type:varchar(255)"`
+	RemoteIp     string `gorm:"type:varchar(64)"`
+	BodySize     int
+	StatusCode   int
+	Error        string
+	DurationMs   int64
+}
+
+func (webhookRequestLog20230124) TableName() string {
+	return "_tool_webhook_request_logs"
+}
+
+func (*addConnectionSecurity) Up(basicRes core.BasicRes) errors.Error {
+	return migrationhelper.AutoMigrateTables(
+		basicRes,
+		&webhookConnection20230124{},
+		&webhookApiKey20230124{},
+		&webhookRequestLog20230124{},
+	)
+}
+
+func (*addConnectionSecurity) Version() uint64 {
+	return 20230124100000
+}
+
+func (*addConnectionSecurity) Name() string {
+	return "add api keys, request signing, ip allowlist and request logs to webhook connections"
+}
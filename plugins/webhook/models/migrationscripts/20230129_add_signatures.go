@@ -0,0 +1,53 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migrationscripts
+
+import (
+	"time"
+
+	"github.com/apache/incubator-devlake/errors"
+	"github.com/apache/incubator-devlake/helpers/migrationhelper"
+	"github.com/apache/incubator-devlake/plugins/core"
+)
+
+type addSignatures struct{}
+
+type webhookSignature20230129 struct {
+	ConnectionId uint64    `gorm:"primaryKey"`
+	Signature    string    `gorm:"primaryKey;type:varchar(64)"`
+	ExpiredAt    time.Time `gorm:"index"`
+}
+
+func (webhookSignature20230129) TableName() string {
+	return "_tool_webhook_signatures"
+}
+
+func (*addSignatures) Up(basicRes core.BasicRes) errors.Error {
+	return migrationhelper.AutoMigrateTables(
+		basicRes,
+		&webhookSignature20230129{},
+	)
+}
+
+func (*addSignatures) Version() uint64 {
+	return 20230129100000
+}
+
+func (*addSignatures) Name() string {
+	return "add _tool_webhook_signatures to reject replayed requests"
+}
@@ -25,5 +25,8 @@ import (
 func All() []core.MigrationScript {
 	return []core.MigrationScript{
 		new(addInitTables),
+		new(addDeploymentCommits),
+		new(addConnectionSecurity),
+		new(addSignatures),
 	}
 }
@@ -0,0 +1,41 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import (
+	"github.com/apache/incubator-devlake/models/common"
+)
+
+// WebhookRequestLog records a request received by a webhook connection with `LogRequests` enabled,
+// rejected ones included
+type WebhookRequestLog struct {
+	common.Model
+	ConnectionId uint64 `gorm:"index" json:"connectionId"`
+	ApiKeyId     uint64 `json:"apiKeyId"`
+	Method       string `gorm:"type:varchar(10)" json:"method"`
+	Path         string `gorm:"type:varchar(255)" json:"path"`
+	RemoteIp     string `gorm:"type:varchar(64)" json:"remoteIp"`
+	BodySize     int    `json:"bodySize"`
+	StatusCode   int    `json:"statusCode"`
+	Error        string `json:"error"`
+	DurationMs   int64  `json:"durationMs"`
+}
+
+func (WebhookRequestLog) TableName() string {
+	return "_tool_webhook_request_logs"
+}
@@ -0,0 +1,32 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import "time"
+
+// WebhookSignature is a request signature accepted by a webhook connection, it is kept until its timestamp falls
+// out of the tolerance so the request can not be replayed, neither after a restart nor to another instance
+type WebhookSignature struct {
+	ConnectionId uint64    `gorm:"primaryKey" json:"connectionId"`
+	Signature    string    `gorm:"primaryKey;type:varchar(64)" json:"signature"`
+	ExpiredAt    time.Time `gorm:"index" json:"expiredAt"`
+}
+
+func (WebhookSignature) TableName() string {
+	return "_tool_webhook_signatures"
+}
@@ -0,0 +1,178 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"fmt"
+
+	"github.com/apache/incubator-devlake/errors"
+	"github.com/apache/incubator-devlake/models/domainlayer"
+	"github.com/apache/incubator-devlake/models/domainlayer/didgen"
+	"github.com/apache/incubator-devlake/models/domainlayer/ticket"
+	"github.com/apache/incubator-devlake/plugins/core"
+	"github.com/apache/incubator-devlake/plugins/core/dal"
+	"github.com/apache/incubator-devlake/plugins/helper"
+	"github.com/apache/incubator-devlake/plugins/zentao/models"
+	"github.com/apache/incubator-devlake/plugins/zentao/tasks"
+	"github.com/apache/incubator-devlake/utils"
+)
+
+// the subtasks which only make sense for one kind of scope
+var productOnlySubtasks = []string{
+	tasks.CollectProductMeta.Name, tasks.ExtractProductMeta.Name, tasks.ConvertProductMeta.Name,
+	tasks.CollectStoryMeta.Name, tasks.ExtractStoryMeta.Name, tasks.ConvertStoryMeta.Name,
+	tasks.CollectBugMeta.Name, tasks.ExtractBugMeta.Name, tasks.ConvertBugMeta.Name,
+}
+var executionOnlySubtasks = []string{
+	tasks.CollectExecutionMeta.Name, tasks.ExtractExecutionMeta.Name, tasks.ConvertExecutionMeta.Name,
+	tasks.CollectTaskMeta.Name, tasks.ExtractTaskMeta.Name, tasks.ConvertTaskMeta.Name,
+}
+
+func MakeDataSourcePipelinePlanV200(subtaskMetas []core.SubTaskMeta, connectionId uint64, bpScopes []*core.BlueprintScopeV200, syncPolicy *core.BlueprintSyncPolicy) (core.PipelinePlan, []core.Scope, errors.Error) {
+	plan := make(core.PipelinePlan, len(bpScopes))
+	plan, err := makeDataSourcePipelinePlanV200(subtaskMetas, plan, bpScopes, connectionId, syncPolicy)
+	if err != nil {
+		return nil, nil, err
+	}
+	scopes, err := makeScopesV200(bpScopes, connectionId)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return plan, scopes, nil
+}
+
+func makeDataSourcePipelinePlanV200(
+	subtaskMetas []core.SubTaskMeta,
+	plan core.PipelinePlan,
+	bpScopes []*core.BlueprintScopeV200,
+	connectionId uint64,
+	syncPolicy *core.BlueprintSyncPolicy,
+) (core.PipelinePlan, errors.Error) {
+	for i, bpScope := range bpScopes {
+		stage := plan[i]
+		if stage == nil {
+			stage = core.PipelineStage{}
+		}
+		// construct task options for zentao
+		options := make(map[string]interface{})
+		options["connectionId"] = connectionId
+		var excludedSubtasks []string
+		kind, scopeId := parseScopeId(bpScope.Id)
+		switch kind {
+		case ScopeProduct:
+			product, err := getProductByScope(connectionId, scopeId)
+			if err != nil {
+				return nil, err
+			}
+			options["productId"] = product.Id
+			options["transformationRuleId"] = product.TransformationRuleId
+			excludedSubtasks = executionOnlySubtasks
+		case ScopeExecution:
+			execution, err := getExecutionByScope(connectionId, scopeId)
+			if err != nil {
+				return nil, err
+			}
+			options["executionId"] = execution.Id
+			options["projectId"] = execution.ProjectId
+			options["transformationRuleId"] = execution.TransformationRuleId
+			excludedSubtasks = productOnlySubtasks
+		default:
+			return nil, errors.BadInput.New(fmt.Sprintf("invalid scope id %s, it should be product/<id> or execution/<id>", bpScope.Id))
+		}
+
+		subtasks, err := helper.MakePipelinePlanSubtasks(subtaskMetas, bpScope.Entities)
+		if err != nil {
+			return nil, err
+		}
+		stage = append(stage, &core.PipelineTask{
+			Plugin:   "zentao",
+			Subtasks: excludeSubtasks(subtasks, excludedSubtasks),
+			Options:  options,
+		})
+		plan[i] = stage
+	}
+
+	return plan, nil
+}
+
+func makeScopesV200(bpScopes []*core.BlueprintScopeV200, connectionId uint64) ([]core.Scope, errors.Error) {
+	scopes := make([]core.Scope, 0)
+	for _, bpScope := range bpScopes {
+		if !utils.StringsContains(bpScope.Entities, core.DOMAIN_TYPE_TICKET) {
+			continue
+		}
+		kind, scopeId := parseScopeId(bpScope.Id)
+		// add board to scopes
+		switch kind {
+		case ScopeProduct:
+			product, err := getProductByScope(connectionId, scopeId)
+			if err != nil {
+				return nil, err
+			}
+			scopes = append(scopes, &ticket.Board{
+				DomainEntity: domainlayer.DomainEntity{
+					Id: didgen.NewDomainIdGenerator(&models.ZentaoProduct{}).Generate(product.ConnectionId, product.Id),
+				},
+				Name: product.Name,
+			})
+		case ScopeExecution:
+			execution, err := getExecutionByScope(connectionId, scopeId)
+			if err != nil {
+				return nil, err
+			}
+			scopes = append(scopes, &ticket.Board{
+				DomainEntity: domainlayer.DomainEntity{
+					Id: didgen.NewDomainIdGenerator(&models.ZentaoExecution{}).Generate(execution.ConnectionId, execution.Id),
+				},
+				Name: execution.Name,
+			})
+		default:
+			return nil, errors.BadInput.New(fmt.Sprintf("invalid scope id %s, it should be product/<id> or execution/<id>", bpScope.Id))
+		}
+	}
+	return scopes, nil
+}
+
+func getProductByScope(connectionId uint64, productId int64) (*models.ZentaoProduct, errors.Error) {
+	product := &models.ZentaoProduct{}
+	err := basicRes.GetDal().First(product, dal.Where(`connection_id = ? AND id = ?`, connectionId, productId))
+	if err != nil {
+		return nil, errors.Default.Wrap(err, fmt.Sprintf("fail to find product %d", productId))
+	}
+	return product, nil
+}
+
+func getExecutionByScope(connectionId uint64, executionId int64) (*models.ZentaoExecution, errors.Error) {
+	execution := &models.ZentaoExecution{}
+	err := basicRes.GetDal().First(execution, dal.Where(`connection_id = ? AND id = ?`, connectionId, executionId))
+	if err != nil {
+		return nil, errors.Default.Wrap(err, fmt.Sprintf("fail to find execution %d", executionId))
+	}
+	return execution, nil
+}
+
+func excludeSubtasks(subtasks []string, excluded []string) []string {
+	result := make([]string, 0, len(subtasks))
+	for _, subtask := range subtasks {
+		if !utils.StringsContains(excluded, subtask) {
+			result = append(result, subtask)
+		}
+	}
+	return result
+}
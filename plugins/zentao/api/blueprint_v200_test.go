@@ -0,0 +1,147 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"testing"
+
+	"github.com/apache/incubator-devlake/mocks"
+	"github.com/apache/incubator-devlake/models/domainlayer"
+	"github.com/apache/incubator-devlake/models/domainlayer/ticket"
+	"github.com/apache/incubator-devlake/plugins/core"
+	"github.com/apache/incubator-devlake/plugins/zentao/models"
+	"github.com/apache/incubator-devlake/plugins/zentao/tasks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestMakeDataSourcePipelinePlanV200(t *testing.T) {
+	mockMeta := mocks.NewPluginMeta(t)
+	mockMeta.On("RootPkgPath").Return("github.com/apache/incubator-devlake/plugins/zentao")
+	err := core.RegisterPlugin("zentao", mockMeta)
+	assert.Nil(t, err)
+	bpScopes := []*core.BlueprintScopeV200{
+		{
+			Entities: []string{core.DOMAIN_TYPE_TICKET},
+			Id:       "product/1",
+		},
+		{
+			Entities: []string{core.DOMAIN_TYPE_TICKET},
+			Id:       "execution/11",
+		},
+	}
+	subtaskMetas := []core.SubTaskMeta{
+		tasks.CollectProductMeta,
+		tasks.CollectExecutionMeta,
+		tasks.CollectStoryMeta,
+		tasks.CollectTaskMeta,
+		tasks.CollectAccountMeta,
+	}
+	syncPolicy := &core.BlueprintSyncPolicy{}
+
+	basicRes = NewMockBasicRes()
+	plan := make(core.PipelinePlan, len(bpScopes))
+	plan, err = makeDataSourcePipelinePlanV200(subtaskMetas, plan, bpScopes, uint64(1), syncPolicy)
+	assert.Nil(t, err)
+	basicRes = NewMockBasicRes()
+	scopes, err := makeScopesV200(bpScopes, uint64(1))
+	assert.Nil(t, err)
+
+	expectPlan := core.PipelinePlan{
+		core.PipelineStage{
+			{
+				Plugin: "zentao",
+				Subtasks: []string{
+					tasks.CollectProductMeta.Name,
+					tasks.CollectStoryMeta.Name,
+					tasks.CollectAccountMeta.Name,
+				},
+				Options: map[string]interface{}{
+					"connectionId":         uint64(1),
+					"productId":            int64(1),
+					"transformationRuleId": uint64(2),
+				},
+			},
+		},
+		core.PipelineStage{
+			{
+				Plugin: "zentao",
+				Subtasks: []string{
+					tasks.CollectExecutionMeta.Name,
+					tasks.CollectTaskMeta.Name,
+					tasks.CollectAccountMeta.Name,
+				},
+				Options: map[string]interface{}{
+					"connectionId":         uint64(1),
+					"executionId":          int64(11),
+					"projectId":            int64(3),
+					"transformationRuleId": uint64(0),
+				},
+			},
+		},
+	}
+	assert.Equal(t, expectPlan, plan)
+
+	expectScopes := []core.Scope{
+		&ticket.Board{
+			DomainEntity: domainlayer.DomainEntity{
+				Id: "zentao:ZentaoProduct:1:1",
+			},
+			Name: "test product",
+		},
+		&ticket.Board{
+			DomainEntity: domainlayer.DomainEntity{
+				Id: "zentao:ZentaoExecution:1:11",
+			},
+			Name: "test execution",
+		},
+	}
+	assert.Equal(t, expectScopes, scopes)
+}
+
+func NewMockBasicRes() *mocks.BasicRes {
+	zentaoProduct := &models.ZentaoProduct{
+		ConnectionId:         1,
+		Id:                   1,
+		Name:                 "test product",
+		TransformationRuleId: 2,
+	}
+	zentaoExecution := &models.ZentaoExecution{
+		ConnectionId: 1,
+		Id:           11,
+		Name:         "test execution",
+		ProjectId:    3,
+	}
+
+	mockRes := new(mocks.BasicRes)
+	mockDal := new(mocks.Dal)
+
+	mockDal.On("First", mock.AnythingOfType("*models.ZentaoProduct"), mock.Anything).Run(func(args mock.Arguments) {
+		dst := args.Get(0).(*models.ZentaoProduct)
+		*dst = *zentaoProduct
+	}).Return(nil)
+	mockDal.On("First", mock.AnythingOfType("*models.ZentaoExecution"), mock.Anything).Run(func(args mock.Arguments) {
+		dst := args.Get(0).(*models.ZentaoExecution)
+		*dst = *zentaoExecution
+	}).Return(nil)
+
+	mockRes.On("GetDal").Return(mockDal)
+	mockRes.On("GetConfig", mock.Anything).Return("")
+
+	return mockRes
+}
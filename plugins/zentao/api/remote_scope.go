@@ -0,0 +1,199 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/apache/incubator-devlake/errors"
+	"github.com/apache/incubator-devlake/plugins/core"
+	"github.com/apache/incubator-devlake/plugins/helper"
+	"github.com/apache/incubator-devlake/plugins/zentao/models"
+)
+
+const (
+	TypeGroup = "group"
+	TypeScope = "scope"
+)
+
+type RemoteScopesChild struct {
+	Type     string      `json:"type"`
+	ParentId *string     `json:"parentId"`
+	Id       string      `json:"id"`
+	Name     string      `json:"name"`
+	Data     interface{} `json:"data"`
+}
+
+type RemoteScopesOutput struct {
+	Children      []RemoteScopesChild `json:"children"`
+	NextPageToken string              `json:"nextPageToken"`
+}
+
+type productsResponse struct {
+	Products []models.ZentaoProductRes `json:"products"`
+}
+
+type projectsResponse struct {
+	Projects []struct {
+		Id   int64  `json:"id"`
+		Name string `json:"name"`
+	} `json:"projects"`
+}
+
+type executionsResponse struct {
+	Executions []models.ZentaoExecutionRes `json:"executions"`
+}
+
+// RemoteScopes list the products and projects of Zentao, or the executions of the project specified by groupId
+// @Summary list products and executions from Zentao
+// @Description list the products (as scopes) and projects (as groups), or the executions of the project when groupId is given
+// @Tags plugins/zentao
+// @Param connectionId path int true "connection ID"
+// @Param groupId query string false "project group ID, i.e. project/1"
+// @Success 200  {object} RemoteScopesOutput
+// @Failure 400  {object} shared.ApiBody "Bad Request"
+// @Failure 500  {object} shared.ApiBody "Internal Error"
+// @Router /plugins/zentao/connections/{connectionId}/remote-scopes [GET]
+func RemoteScopes(input *core.ApiResourceInput) (*core.ApiResourceOutput, errors.Error) {
+	connectionId, _ := strconv.ParseUint(input.Params["connectionId"], 10, 64)
+	if connectionId == 0 {
+		return nil, errors.BadInput.New("invalid connectionId")
+	}
+	connection := &models.ZentaoConnection{}
+	err := connectionHelper.FirstById(connection, connectionId)
+	if err != nil {
+		return nil, err
+	}
+	apiClient, err := newApiClient(connection)
+	if err != nil {
+		return nil, err
+	}
+	groupId := input.Query.Get("groupId")
+	// Zentao returns 20 records per page by default
+	query := url.Values{}
+	query.Set("limit", "1000")
+	output := RemoteScopesOutput{Children: []RemoteScopesChild{}}
+	if groupId == "" {
+		var products productsResponse
+		err = getRemote(apiClient, "products", query, &products)
+		if err != nil {
+			return nil, err
+		}
+		for _, product := range products.Products {
+			output.Children = append(output.Children, RemoteScopesChild{
+				Type: TypeScope,
+				Id:   fmt.Sprintf("%s/%d", ScopeProduct, product.ID),
+				Name: product.Name,
+				Data: &models.ZentaoProduct{
+					ConnectionId: connectionId,
+					Id:           product.ID,
+					Name:         product.Name,
+					Code:         product.Code,
+					Type:         product.Type,
+					Status:       product.Status,
+					Description:  product.Description,
+				},
+			})
+		}
+		var projects projectsResponse
+		err = getRemote(apiClient, "projects", query, &projects)
+		if err != nil {
+			return nil, err
+		}
+		for _, project := range projects.Projects {
+			output.Children = append(output.Children, RemoteScopesChild{
+				Type: TypeGroup,
+				Id:   fmt.Sprintf("project/%d", project.Id),
+				Name: project.Name,
+			})
+		}
+	} else {
+		kind, projectId := parseScopeId(groupId)
+		if kind != "project" || projectId == 0 {
+			return nil, errors.BadInput.New("invalid groupId, it should be project/<id>")
+		}
+		var executions executionsResponse
+		err = getRemote(apiClient, fmt.Sprintf("projects/%d/executions", projectId), query, &executions)
+		if err != nil {
+			return nil, err
+		}
+		for _, execution := range executions.Executions {
+			output.Children = append(output.Children, RemoteScopesChild{
+				Type:     TypeScope,
+				ParentId: &groupId,
+				Id:       fmt.Sprintf("%s/%d", ScopeExecution, execution.ID),
+				Name:     execution.Name,
+				Data: &models.ZentaoExecution{
+					ConnectionId: connectionId,
+					Id:           execution.ID,
+					Project:      projectId,
+					ProjectId:    projectId,
+					Name:         execution.Name,
+					Code:         execution.Code,
+					Type:         execution.Type,
+					Status:       execution.Status,
+					Description:  execution.Description,
+				},
+			})
+		}
+	}
+	return &core.ApiResourceOutput{Body: output, Status: http.StatusOK}, nil
+}
+
+// newApiClient requests an access token the same way as TestConnection and returns a client carrying it
+func newApiClient(connection *models.ZentaoConnection) (*helper.ApiClient, errors.Error) {
+	apiClient, err := helper.NewApiClient(context.TODO(), connection.Endpoint, nil, 0, connection.Proxy, basicRes)
+	if err != nil {
+		return nil, err
+	}
+	tokenReqBody := &models.ApiAccessTokenRequest{
+		Account:  connection.Username,
+		Password: connection.Password,
+	}
+	tokenRes, err := apiClient.Post("/tokens", nil, tokenReqBody, nil)
+	if err != nil {
+		return nil, err
+	}
+	tokenResBody := &models.ApiAccessTokenResponse{}
+	err = helper.UnmarshalResponse(tokenRes, tokenResBody)
+	if err != nil {
+		return nil, err
+	}
+	if tokenResBody.Token == "" {
+		return nil, errors.Default.New("failed to request access token")
+	}
+	apiClient.SetHeaders(map[string]string{
+		"Token": tokenResBody.Token,
+	})
+	return apiClient, nil
+}
+
+func getRemote(apiClient helper.ApiClientGetter, path string, query url.Values, result interface{}) errors.Error {
+	res, err := apiClient.Get(path, query, nil)
+	if err != nil {
+		return err
+	}
+	if res.StatusCode != http.StatusOK {
+		return errors.HttpStatus(res.StatusCode).New(fmt.Sprintf("unexpected status code when requesting %s", path))
+	}
+	return helper.UnmarshalResponse(res, result)
+}
@@ -0,0 +1,359 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/apache/incubator-devlake/errors"
+	"github.com/apache/incubator-devlake/plugins/core"
+	"github.com/apache/incubator-devlake/plugins/core/dal"
+	"github.com/apache/incubator-devlake/plugins/helper"
+	"github.com/apache/incubator-devlake/plugins/zentao/models"
+)
+
+// Zentao has two kinds of scopes, the ids of the scopes are prefixed with the kinds, i.e. product/1 and execution/2
+const (
+	ScopeProduct   = "product"
+	ScopeExecution = "execution"
+)
+
+type apiProduct struct {
+	models.ZentaoProduct
+	TransformationRuleName string `json:"transformationRuleName,omitempty"`
+}
+
+type apiExecution struct {
+	models.ZentaoExecution
+	TransformationRuleName string `json:"transformationRuleName,omitempty"`
+}
+
+type scopeList struct {
+	Products   []apiProduct   `json:"products"`
+	Executions []apiExecution `json:"executions"`
+}
+
+type productReq struct {
+	Data []*models.ZentaoProduct `json:"data"`
+}
+
+type executionReq struct {
+	Data []*models.ZentaoExecution `json:"data"`
+}
+
+// PutProductScope create or update Zentao products
+// @Summary create or update Zentao products
+// @Description Create or update Zentao products
+// @Tags plugins/zentao
+// @Accept application/json
+// @Param connectionId path int true "connection ID"
+// @Param scope body productReq true "json"
+// @Success 200  {object} []models.ZentaoProduct
+// @Failure 400  {object} shared.ApiBody "Bad Request"
+// @Failure 500  {object} shared.ApiBody "Internal Error"
+// @Router /plugins/zentao/connections/{connectionId}/scopes/product [PUT]
+func PutProductScope(input *core.ApiResourceInput) (*core.ApiResourceOutput, errors.Error) {
+	connectionId, _ := extractParam(input.Params, "productId")
+	if connectionId == 0 {
+		return nil, errors.BadInput.New("invalid connectionId")
+	}
+	var products productReq
+	err := helper.DecodeMapStruct(input.Body, &products)
+	if err != nil {
+		return nil, errors.BadInput.Wrap(err, "decoding Zentao product error")
+	}
+	keeper := make(map[int64]struct{})
+	for _, product := range products.Data {
+		if _, ok := keeper[product.Id]; ok {
+			return nil, errors.BadInput.New("duplicated item")
+		}
+		keeper[product.Id] = struct{}{}
+		product.ConnectionId = connectionId
+		if product.Id == 0 {
+			return nil, errors.BadInput.New("invalid productId")
+		}
+	}
+	err = basicRes.GetDal().CreateOrUpdate(products.Data)
+	if err != nil {
+		return nil, errors.Default.Wrap(err, "error on saving ZentaoProduct")
+	}
+	return &core.ApiResourceOutput{Body: products.Data, Status: http.StatusOK}, nil
+}
+
+// PutExecutionScope create or update Zentao executions
+// @Summary create or update Zentao executions
+// @Description Create or update Zentao executions
+// @Tags plugins/zentao
+// @Accept application/json
+// @Param connectionId path int true "connection ID"
+// @Param scope body executionReq true "json"
+// @Success 200  {object} []models.ZentaoExecution
+// @Failure 400  {object} shared.ApiBody "Bad Request"
+// @Failure 500  {object} shared.ApiBody "Internal Error"
+// @Router /plugins/zentao/connections/{connectionId}/scopes/execution [PUT]
+func PutExecutionScope(input *core.ApiResourceInput) (*core.ApiResourceOutput, errors.Error) {
+	connectionId, _ := extractParam(input.Params, "executionId")
+	if connectionId == 0 {
+		return nil, errors.BadInput.New("invalid connectionId")
+	}
+	var executions executionReq
+	err := helper.DecodeMapStruct(input.Body, &executions)
+	if err != nil {
+		return nil, errors.BadInput.Wrap(err, "decoding Zentao execution error")
+	}
+	keeper := make(map[int64]struct{})
+	for _, execution := range executions.Data {
+		if _, ok := keeper[execution.Id]; ok {
+			return nil, errors.BadInput.New("duplicated item")
+		}
+		keeper[execution.Id] = struct{}{}
+		execution.ConnectionId = connectionId
+		if execution.Id == 0 {
+			return nil, errors.BadInput.New("invalid executionId")
+		}
+	}
+	err = basicRes.GetDal().CreateOrUpdate(executions.Data)
+	if err != nil {
+		return nil, errors.Default.Wrap(err, "error on saving ZentaoExecution")
+	}
+	return &core.ApiResourceOutput{Body: executions.Data, Status: http.StatusOK}, nil
+}
+
+// UpdateProductScope patch to Zentao product
+// @Summary patch to Zentao product
+// @Description patch to Zentao product
+// @Tags plugins/zentao
+// @Accept application/json
+// @Param connectionId path int true "connection ID"
+// @Param productId path int true "product ID"
+// @Param scope body models.ZentaoProduct true "json"
+// @Success 200  {object} models.ZentaoProduct
+// @Failure 400  {object} shared.ApiBody "Bad Request"
+// @Failure 500  {object} shared.ApiBody "Internal Error"
+// @Router /plugins/zentao/connections/{connectionId}/scopes/product/{productId} [PATCH]
+func UpdateProductScope(input *core.ApiResourceInput) (*core.ApiResourceOutput, errors.Error) {
+	connectionId, productId := extractParam(input.Params, "productId")
+	if connectionId*uint64(productId) == 0 {
+		return nil, errors.BadInput.New("invalid connectionId or productId")
+	}
+	var product models.ZentaoProduct
+	err := basicRes.GetDal().First(&product, dal.Where("connection_id = ? AND id = ?", connectionId, productId))
+	if err != nil {
+		return nil, errors.Default.Wrap(err, "getting ZentaoProduct error")
+	}
+	err = helper.DecodeMapStruct(input.Body, &product)
+	if err != nil {
+		return nil, errors.Default.Wrap(err, "patch Zentao product error")
+	}
+	// the primary key should not be changed
+	product.ConnectionId = connectionId
+	product.Id = productId
+	err = basicRes.GetDal().Update(product)
+	if err != nil {
+		return nil, errors.Default.Wrap(err, "error on saving ZentaoProduct")
+	}
+	return &core.ApiResourceOutput{Body: product, Status: http.StatusOK}, nil
+}
+
+// UpdateExecutionScope patch to Zentao execution
+// @Summary patch to Zentao execution
+// @Description patch to Zentao execution
+// @Tags plugins/zentao
+// @Accept application/json
+// @Param connectionId path int true "connection ID"
+// @Param executionId path int true "execution ID"
+// @Param scope body models.ZentaoExecution true "json"
+// @Success 200  {object} models.ZentaoExecution
+// @Failure 400  {object} shared.ApiBody "Bad Request"
+// @Failure 500  {object} shared.ApiBody "Internal Error"
+// @Router /plugins/zentao/connections/{connectionId}/scopes/execution/{executionId} [PATCH]
+func UpdateExecutionScope(input *core.ApiResourceInput) (*core.ApiResourceOutput, errors.Error) {
+	connectionId, executionId := extractParam(input.Params, "executionId")
+	if connectionId*uint64(executionId) == 0 {
+		return nil, errors.BadInput.New("invalid connectionId or executionId")
+	}
+	var execution models.ZentaoExecution
+	err := basicRes.GetDal().First(&execution, dal.Where("connection_id = ? AND id = ?", connectionId, executionId))
+	if err != nil {
+		return nil, errors.Default.Wrap(err, "getting ZentaoExecution error")
+	}
+	err = helper.DecodeMapStruct(input.Body, &execution)
+	if err != nil {
+		return nil, errors.Default.Wrap(err, "patch Zentao execution error")
+	}
+	// the primary key should not be changed
+	execution.ConnectionId = connectionId
+	execution.Id = executionId
+	err = basicRes.GetDal().Update(execution)
+	if err != nil {
+		return nil, errors.Default.Wrap(err, "error on saving ZentaoExecution")
+	}
+	return &core.ApiResourceOutput{Body: execution, Status: http.StatusOK}, nil
+}
+
+// GetScopeList get Zentao products and executions
+// @Summary get Zentao products and executions
+// @Description get Zentao products and executions
+// @Tags plugins/zentao
+// @Param connectionId path int true "connection ID"
+// @Param pageSize query int false "page size, default 50"
+// @Param page query int false "page size, default 1"
+// @Success 200  {object} scopeList
+// @Failure 400  {object} shared.ApiBody "Bad Request"
+// @Failure 500  {object} shared.ApiBody "Internal Error"
+// @Router /plugins/zentao/connections/{connectionId}/scopes [GET]
+func GetScopeList(input *core.ApiResourceInput) (*core.ApiResourceOutput, errors.Error) {
+	connectionId, _ := extractParam(input.Params, "")
+	if connectionId == 0 {
+		return nil, errors.BadInput.New("invalid path params")
+	}
+	limit, offset := helper.GetLimitOffset(input.Query, "pageSize", "page")
+	db := basicRes.GetDal()
+	var products []models.ZentaoProduct
+	err := db.All(&products, dal.Where("connection_id = ?", connectionId), dal.Limit(limit), dal.Offset(offset))
+	if err != nil {
+		return nil, err
+	}
+	var executions []models.ZentaoExecution
+	err = db.All(&executions, dal.Where("connection_id = ?", connectionId), dal.Limit(limit), dal.Offset(offset))
+	if err != nil {
+		return nil, err
+	}
+	var ruleIds []uint64
+	for _, product := range products {
+		if product.TransformationRuleId > 0 {
+			ruleIds = append(ruleIds, product.TransformationRuleId)
+		}
+	}
+	for _, execution := range executions {
+		if execution.TransformationRuleId > 0 {
+			ruleIds = append(ruleIds, execution.TransformationRuleId)
+		}
+	}
+	var rules []models.ZentaoTransformationRule
+	if len(ruleIds) > 0 {
+		err = db.All(&rules, dal.Where("id IN (?)", ruleIds))
+		if err != nil {
+			return nil, err
+		}
+	}
+	names := make(map[uint64]string)
+	for _, rule := range rules {
+		names[rule.ID] = rule.Name
+	}
+	result := scopeList{Products: []apiProduct{}, Executions: []apiExecution{}}
+	for _, product := range products {
+		result.Products = append(result.Products, apiProduct{product, names[product.TransformationRuleId]})
+	}
+	for _, execution := range executions {
+		result.Executions = append(result.Executions, apiExecution{execution, names[execution.TransformationRuleId]})
+	}
+	return &core.ApiResourceOutput{Body: result, Status: http.StatusOK}, nil
+}
+
+// GetProductScope get one Zentao product
+// @Summary get one Zentao product
+// @Description get one Zentao product
+// @Tags plugins/zentao
+// @Param connectionId path int true "connection ID"
+// @Param productId path int true "product ID"
+// @Success 200  {object} apiProduct
+// @Failure 400  {object} shared.ApiBody "Bad Request"
+// @Failure 500  {object} shared.ApiBody "Internal Error"
+// @Router /plugins/zentao/connections/{connectionId}/scopes/product/{productId} [GET]
+func GetProductScope(input *core.ApiResourceInput) (*core.ApiResourceOutput, errors.Error) {
+	connectionId, productId := extractParam(input.Params, "productId")
+	if connectionId*uint64(productId) == 0 {
+		return nil, errors.BadInput.New("invalid path params")
+	}
+	var product models.ZentaoProduct
+	db := basicRes.GetDal()
+	err := db.First(&product, dal.Where("connection_id = ? AND id = ?", connectionId, productId))
+	if db.IsErrorNotFound(err) {
+		return nil, errors.NotFound.New("record not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+	ruleName, err := getTransformationRuleName(product.TransformationRuleId)
+	if err != nil {
+		return nil, err
+	}
+	return &core.ApiResourceOutput{Body: apiProduct{product, ruleName}, Status: http.StatusOK}, nil
+}
+
+// GetExecutionScope get one Zentao execution
+// @Summary get one Zentao execution
+// @Description get one Zentao execution
+// @Tags plugins/zentao
+// @Param connectionId path int true "connection ID"
+// @Param executionId path int true "execution ID"
+// @Success 200  {object} apiExecution
+// @Failure 400  {object} shared.ApiBody "Bad Request"
+// @Failure 500  {object} shared.ApiBody "Internal Error"
+// @Router /plugins/zentao/connections/{connectionId}/scopes/execution/{executionId} [GET]
+func GetExecutionScope(input *core.ApiResourceInput) (*core.ApiResourceOutput, errors.Error) {
+	connectionId, executionId := extractParam(input.Params, "executionId")
+	if connectionId*uint64(executionId) == 0 {
+		return nil, errors.BadInput.New("invalid path params")
+	}
+	var execution models.ZentaoExecution
+	db := basicRes.GetDal()
+	err := db.First(&execution, dal.Where("connection_id = ? AND id = ?", connectionId, executionId))
+	if db.IsErrorNotFound(err) {
+		return nil, errors.NotFound.New("record not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+	ruleName, err := getTransformationRuleName(execution.TransformationRuleId)
+	if err != nil {
+		return nil, err
+	}
+	return &core.ApiResourceOutput{Body: apiExecution{execution, ruleName}, Status: http.StatusOK}, nil
+}
+
+func getTransformationRuleName(transformationRuleId uint64) (string, errors.Error) {
+	if transformationRuleId == 0 {
+		return "", nil
+	}
+	var rule models.ZentaoTransformationRule
+	err := basicRes.GetDal().First(&rule, dal.Where("id = ?", transformationRuleId))
+	if err != nil {
+		return "", err
+	}
+	return rule.Name, nil
+}
+
+func extractParam(params map[string]string, scopeIdKey string) (uint64, int64) {
+	connectionId, _ := strconv.ParseUint(params["connectionId"], 10, 64)
+	scopeId, _ := strconv.ParseInt(params[scopeIdKey], 10, 64)
+	return connectionId, scopeId
+}
+
+// parseScopeId splits the scope id like product/1 into the kind and the id
+func parseScopeId(scopeId string) (string, int64) {
+	kind, id, found := strings.Cut(scopeId, "/")
+	if !found {
+		return "", 0
+	}
+	parsedId, _ := strconv.ParseInt(id, 10, 64)
+	return kind, parsedId
+}
@@ -22,6 +22,7 @@ import (
 
 	"github.com/apache/incubator-devlake/errors"
 	"github.com/apache/incubator-devlake/plugins/core"
+	"github.com/apache/incubator-devlake/plugins/core/dal"
 	"github.com/apache/incubator-devlake/plugins/helper"
 	"github.com/apache/incubator-devlake/plugins/zentao/api"
 	"github.com/apache/incubator-devlake/plugins/zentao/models"
@@ -36,9 +37,23 @@ var _ core.PluginTask = (*Zentao)(nil)
 var _ core.PluginApi = (*Zentao)(nil)
 var _ core.PluginBlueprintV100 = (*Zentao)(nil)
 var _ core.CloseablePluginTask = (*Zentao)(nil)
+var _ core.DataSourcePluginBlueprintV200 = (*Zentao)(nil)
+var _ core.PluginSource = (*Zentao)(nil)
 
 type Zentao struct{}
 
+func (plugin Zentao) Connection() interface{} {
+	return &models.ZentaoConnection{}
+}
+
+func (plugin Zentao) Scope() interface{} {
+	return &models.ZentaoProduct{}
+}
+
+func (plugin Zentao) TransformationRule() interface{} {
+	return &models.ZentaoTransformationRule{}
+}
+
 func (plugin Zentao) Description() string {
 	return "collect some Zentao data"
 }
@@ -95,6 +110,11 @@ func (plugin Zentao) PrepareTaskData(taskCtx core.TaskContext, options map[strin
 		return nil, errors.Default.Wrap(err, "unable to get Zentao API client instance: %v")
 	}
 
+	err = EnrichOptions(taskCtx, op)
+	if err != nil {
+		return nil, errors.Default.Wrap(err, "unable to load the transformation rule")
+	}
+
 	return &tasks.ZentaoTaskData{
 		Options:   op,
 		ApiClient: apiClient,
@@ -110,6 +130,10 @@ func (plugin Zentao) MigrationScripts() []core.MigrationScript {
 	return migrationscripts.All()
 }
 
+func (plugin Zentao) MakeDataSourcePipelinePlanV200(connectionId uint64, scopes []*core.BlueprintScopeV200, syncPolicy core.BlueprintSyncPolicy) (pp core.PipelinePlan, sc []core.Scope, err errors.Error) {
+	return api.MakeDataSourcePipelinePlanV200(plugin.SubTaskMetas(), connectionId, scopes, &syncPolicy)
+}
+
 func (plugin Zentao) ApiResources() map[string]map[string]core.ApiResourceHandler {
 	return map[string]map[string]core.ApiResourceHandler{
 		"test": {
@@ -124,6 +148,34 @@ func (plugin Zentao) ApiResources() map[string]map[string]core.ApiResourceHandle
 			"PATCH":  api.PatchConnection,
 			"DELETE": api.DeleteConnection,
 		},
+		"connections/:connectionId/scopes": {
+			"GET": api.GetScopeList,
+		},
+		"connections/:connectionId/scopes/product": {
+			"PUT": api.PutProductScope,
+		},
+		"connections/:connectionId/scopes/execution": {
+			"PUT": api.PutExecutionScope,
+		},
+		"connections/:connectionId/scopes/product/:productId": {
+			"GET":   api.GetProductScope,
+			"PATCH": api.UpdateProductScope,
+		},
+		"connections/:connectionId/scopes/execution/:executionId": {
+			"GET":   api.GetExecutionScope,
+			"PATCH": api.UpdateExecutionScope,
+		},
+		"connections/:connectionId/remote-scopes": {
+			"GET": api.RemoteScopes,
+		},
+		"transformation_rules": {
+			"POST": api.CreateTransformationRule,
+			"GET":  api.GetTransformationRuleList,
+		},
+		"transformation_rules/:id": {
+			"PATCH": api.UpdateTransformationRule,
+			"GET":   api.GetTransformationRule,
+		},
 	}
 }
 
@@ -139,3 +191,35 @@ func (plugin Zentao) Close(taskCtx core.TaskContext) errors.Error {
 	data.ApiClient.Release()
 	return nil
 }
+
+// EnrichOptions loads the transformation rule of the product or execution when it is not given in the options
+func EnrichOptions(taskCtx core.TaskContext, op *tasks.ZentaoOptions) errors.Error {
+	db := taskCtx.GetDal()
+	if op.TransformationRuleId == 0 {
+		var err errors.Error
+		if op.ProductId != 0 {
+			var product models.ZentaoProduct
+			err = db.First(&product, dal.Where("connection_id = ? AND id = ?", op.ConnectionId, op.ProductId))
+			op.TransformationRuleId = product.TransformationRuleId
+		} else if op.ExecutionId != 0 {
+			var execution models.ZentaoExecution
+			err = db.First(&execution, dal.Where("connection_id = ? AND id = ?", op.ConnectionId, op.ExecutionId))
+			op.TransformationRuleId = execution.TransformationRuleId
+		}
+		if err != nil && !db.IsErrorNotFound(err) {
+			return errors.Default.Wrap(err, "fail to find the scope")
+		}
+	}
+	if op.TransformationRules == nil && op.TransformationRuleId != 0 {
+		var transformationRule models.ZentaoTransformationRule
+		err := db.First(&transformationRule, dal.Where("id = ?", op.TransformationRuleId))
+		if err != nil {
+			return errors.BadInput.Wrap(err, "fail to get transformationRule")
+		}
+		op.TransformationRules, err = tasks.MakeTransformationRules(transformationRule)
+		if err != nil {
+			return errors.BadInput.Wrap(err, "fail to make transformationRule")
+		}
+	}
+	return nil
+}
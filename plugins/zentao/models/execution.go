@@ -217,7 +217,9 @@ type ZentaoExecution struct {
 	ProjectId      int64
 	Progress       float64 `json:"progress"`
 	CaseReview     bool    `json:"caseReview"`
-	common.NoPKModel
+	// TransformationRuleId is only set when it was added as a scope
+	TransformationRuleId uint64 `json:"transformationRuleId,omitempty" mapstructure:"transformationRuleId"`
+	common.NoPKModel     `json:"-" mapstructure:"-"`
 }
 
 func (ZentaoExecution) TableName() string {
@@ -0,0 +1,59 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migrationscripts
+
+import (
+	"github.com/apache/incubator-devlake/errors"
+	"github.com/apache/incubator-devlake/helpers/migrationhelper"
+	"github.com/apache/incubator-devlake/plugins/core"
+	"github.com/apache/incubator-devlake/plugins/zentao/models/archived"
+)
+
+type zentaoProduct20230108 struct {
+	TransformationRuleId uint64
+}
+
+func (zentaoProduct20230108) TableName() string {
+	return "_tool_zentao_products"
+}
+
+type zentaoExecution20230108 struct {
+	TransformationRuleId uint64
+}
+
+func (zentaoExecution20230108) TableName() string {
+	return "_tool_zentao_executions"
+}
+
+type addTransformationRule20230108 struct{}
+
+func (*addTransformationRule20230108) Up(basicRes core.BasicRes) errors.Error {
+	return migrationhelper.AutoMigrateTables(basicRes,
+		&zentaoProduct20230108{},
+		&zentaoExecution20230108{},
+		&archived.ZentaoTransformationRule{},
+	)
+}
+
+func (*addTransformationRule20230108) Version() uint64 {
+	return 20230108143510
+}
+
+func (*addTransformationRule20230108) Name() string {
+	return "add table _tool_zentao_transformation_rules, add transformation_rule_id to _tool_zentao_products and _tool_zentao_executions"
+}
@@ -98,7 +98,9 @@ type ZentaoProduct struct {
 	Docs           int                 `json:"docs"`
 	Progress       float64             `json:"progress"`
 	CaseReview     bool                `json:"caseReview"`
-	common.NoPKModel
+	// TransformationRuleId is only set when it was added as a scope
+	TransformationRuleId uint64 `json:"transformationRuleId,omitempty" mapstructure:"transformationRuleId"`
+	common.NoPKModel     `json:"-" mapstructure:"-"`
 }
 
 func (ZentaoProduct) TableName() string {
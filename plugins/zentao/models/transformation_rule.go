@@ -0,0 +1,37 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import (
+	"encoding/json"
+
+	"github.com/apache/incubator-devlake/models/common"
+)
+
+type ZentaoTransformationRule struct {
+	common.Model `mapstructure:"-"`
+	Name         string `mapstructure:"name" json:"name" gorm:"type:varchar(255);index:idx_name_zentao,unique" validate:"required"`
+	// TypeMappings maps the original types of stories/bugs/tasks to the standard types, i.e. {"security": {"standardType": "INCIDENT"}}
+	TypeMappings json.RawMessage `mapstructure:"typeMappings,omitempty" json:"typeMappings"`
+	// StatusMappings maps the standard statuses to the original statuses, i.e. {"DONE": ["closed", "resolved"]}
+	StatusMappings json.RawMessage `mapstructure:"statusMappings,omitempty" json:"statusMappings"`
+}
+
+func (ZentaoTransformationRule) TableName() string {
+	return "_tool_zentao_transformation_rules"
+}
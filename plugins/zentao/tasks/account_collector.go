@@ -79,4 +79,5 @@ var CollectAccountMeta = core.SubTaskMeta{
 	EntryPoint:       CollectAccount,
 	EnabledByDefault: true,
 	Description:      "Collect Account data from Zentao api",
+	DomainTypes:      []string{core.DOMAIN_TYPE_TICKET},
 }
@@ -79,4 +79,5 @@ var CollectBugMeta = core.SubTaskMeta{
 	EntryPoint:       CollectBug,
 	EnabledByDefault: true,
 	Description:      "Collect Bug data from Zentao api",
+	DomainTypes:      []string{core.DOMAIN_TYPE_TICKET},
 }
@@ -43,6 +43,8 @@ var ConvertBugMeta = core.SubTaskMeta{
 func ConvertBug(taskCtx core.SubTaskContext) errors.Error {
 	data := taskCtx.GetData().(*ZentaoTaskData)
 	db := taskCtx.GetDal()
+	stdTypeMappings := getStdTypeMappings(data)
+	stdStatusMappings := getStdStatusMappings(data)
 	bugIdGen := didgen.NewDomainIdGenerator(&models.ZentaoBug{})
 	boardIdGen := didgen.NewDomainIdGenerator(&models.ZentaoProduct{})
 	storyIdGen := didgen.NewDomainIdGenerator(&models.ZentaoStory{})
@@ -96,6 +98,12 @@ func ConvertBug(taskCtx core.SubTaskContext) errors.Error {
 			default:
 				domainEntity.Status = ticket.IN_PROGRESS
 			}
+			if stdType := stdTypeMappings[domainEntity.OriginalType]; stdType != "" {
+				domainEntity.Type = stdType
+			}
+			if stdStatus := stdStatusMappings[domainEntity.OriginalStatus]; stdStatus != "" {
+				domainEntity.Status = stdStatus
+			}
 			if toolEntity.ClosedDate != nil {
 				domainEntity.LeadTimeMinutes = int64(toolEntity.ClosedDate.ToNullableTime().Sub(toolEntity.OpenedDate.ToTime()).Minutes())
 			}
@@ -79,4 +79,5 @@ var CollectDepartmentMeta = core.SubTaskMeta{
 	EntryPoint:       CollectDepartment,
 	EnabledByDefault: true,
 	Description:      "Collect Department data from Zentao api",
+	DomainTypes:      []string{core.DOMAIN_TYPE_TICKET},
 }
@@ -75,4 +75,5 @@ var CollectExecutionMeta = core.SubTaskMeta{
 	EntryPoint:       CollectExecution,
 	EnabledByDefault: true,
 	Description:      "Collect Execution data from Zentao api",
+	DomainTypes:      []string{core.DOMAIN_TYPE_TICKET},
 }
@@ -55,63 +55,64 @@ func ExtractExecutions(taskCtx core.SubTaskContext) errors.Error {
 				return nil, errors.Default.WrapRaw(err)
 			}
 			execution := &models.ZentaoExecution{
-				ConnectionId:   data.Options.ConnectionId,
-				Id:             res.ID,
-				Project:        res.Project,
-				Model:          res.Model,
-				Type:           res.Type,
-				Lifetime:       res.Lifetime,
-				Budget:         res.Budget,
-				BudgetUnit:     res.BudgetUnit,
-				Attribute:      res.Attribute,
-				Percent:        res.Percent,
-				Milestone:      res.Milestone,
-				Output:         res.Output,
-				Auth:           res.Auth,
-				Parent:         res.Parent,
-				Path:           res.Path,
-				Grade:          res.Grade,
-				Name:           res.Name,
-				Code:           res.Code,
-				PlanBegin:      res.PlanBegin,
-				PlanEnd:        res.PlanEnd,
-				RealBegan:      res.RealBegan,
-				RealEnd:        res.RealEnd,
-				Status:         res.Status,
-				SubStatus:      res.SubStatus,
-				Pri:            res.Pri,
-				Description:    res.Description,
-				Version:        res.Version,
-				ParentVersion:  res.ParentVersion,
-				PlanDuration:   res.PlanDuration,
-				RealDuration:   res.RealDuration,
-				OpenedById:     getAccountId(res.OpenedBy),
-				OpenedDate:     res.OpenedDate,
-				OpenedVersion:  res.OpenedVersion,
-				LastEditedById: getAccountId(res.LastEditedBy),
-				LastEditedDate: res.LastEditedDate,
-				ClosedById:     getAccountId(res.ClosedBy),
-				ClosedDate:     res.ClosedDate,
-				CanceledById:   getAccountId(res.CanceledBy),
-				CanceledDate:   res.CanceledDate,
-				SuspendedDate:  res.SuspendedDate,
-				POId:           getAccountId(res.PO),
-				PMId:           getAccountId(res.PM),
-				QDId:           getAccountId(res.QD),
-				RDId:           getAccountId(res.RD),
-				Team:           res.Team,
-				Acl:            res.Acl,
-				OrderIn:        res.OrderIn,
-				Vision:         res.Vision,
-				DisplayCards:   res.DisplayCards,
-				FluidBoard:     res.FluidBoard,
-				Deleted:        res.Deleted,
-				TotalHours:     res.TotalHours,
-				TotalEstimate:  res.TotalEstimate,
-				TotalConsumed:  res.TotalConsumed,
-				TotalLeft:      res.TotalLeft,
-				Progress:       res.Progress,
-				CaseReview:     res.CaseReview,
+				TransformationRuleId: data.Options.TransformationRuleId,
+				ConnectionId:         data.Options.ConnectionId,
+				Id:                   res.ID,
+				Project:              res.Project,
+				Model:                res.Model,
+				Type:                 res.Type,
+				Lifetime:             res.Lifetime,
+				Budget:               res.Budget,
+				BudgetUnit:           res.BudgetUnit,
+				Attribute:            res.Attribute,
+				Percent:              res.Percent,
+				Milestone:            res.Milestone,
+				Output:               res.Output,
+				Auth:                 res.Auth,
+				Parent:               res.Parent,
+				Path:                 res.Path,
+				Grade:                res.Grade,
+				Name:                 res.Name,
+				Code:                 res.Code,
+				PlanBegin:            res.PlanBegin,
+				PlanEnd:              res.PlanEnd,
+				RealBegan:            res.RealBegan,
+				RealEnd:              res.RealEnd,
+				Status:               res.Status,
+				SubStatus:            res.SubStatus,
+				Pri:                  res.Pri,
+				Description:          res.Description,
+				Version:              res.Version,
+				ParentVersion:        res.ParentVersion,
+				PlanDuration:         res.PlanDuration,
+				RealDuration:         res.RealDuration,
+				OpenedById:           getAccountId(res.OpenedBy),
+				OpenedDate:           res.OpenedDate,
+				OpenedVersion:        res.OpenedVersion,
+				LastEditedById:       getAccountId(res.LastEditedBy),
+				LastEditedDate:       res.LastEditedDate,
+				ClosedById:           getAccountId(res.ClosedBy),
+				ClosedDate:           res.ClosedDate,
+				CanceledById:         getAccountId(res.CanceledBy),
+				CanceledDate:         res.CanceledDate,
+				SuspendedDate:        res.SuspendedDate,
+				POId:                 getAccountId(res.PO),
+				PMId:                 getAccountId(res.PM),
+				QDId:                 getAccountId(res.QD),
+				RDId:                 getAccountId(res.RD),
+				Team:                 res.Team,
+				Acl:                  res.Acl,
+				OrderIn:              res.OrderIn,
+				Vision:               res.Vision,
+				DisplayCards:         res.DisplayCards,
+				FluidBoard:           res.FluidBoard,
+				Deleted:              res.Deleted,
+				TotalHours:           res.TotalHours,
+				TotalEstimate:        res.TotalEstimate,
+				TotalConsumed:        res.TotalConsumed,
+				TotalLeft:            res.TotalLeft,
+				Progress:             res.Progress,
+				CaseReview:           res.CaseReview,
 			}
 			results := make([]interface{}, 0)
 			results = append(results, execution)
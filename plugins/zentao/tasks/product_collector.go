@@ -75,4 +75,5 @@ var CollectProductMeta = core.SubTaskMeta{
 	EntryPoint:       CollectProduct,
 	EnabledByDefault: true,
 	Description:      "Collect Product data from Zentao api",
+	DomainTypes:      []string{core.DOMAIN_TYPE_TICKET},
 }
@@ -73,4 +73,5 @@ var CollectProjectMeta = core.SubTaskMeta{
 	EntryPoint:       CollectProject,
 	EnabledByDefault: true,
 	Description:      "Collect Project data from Zentao api",
+	DomainTypes:      []string{core.DOMAIN_TYPE_TICKET},
 }
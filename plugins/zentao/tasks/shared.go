@@ -18,6 +18,8 @@ limitations under the License.
 package tasks
 
 import (
+	"strings"
+
 	"github.com/apache/incubator-devlake/errors"
 	"github.com/apache/incubator-devlake/plugins/zentao/models"
 	"net/http"
@@ -51,3 +53,29 @@ func getAccountName(account *models.ZentaoAccount) string {
 	}
 	return ""
 }
+
+// getStdTypeMappings returns the standard types by the original types
+func getStdTypeMappings(data *ZentaoTaskData) map[string]string {
+	stdTypeMappings := make(map[string]string)
+	if data.Options.TransformationRules == nil {
+		return stdTypeMappings
+	}
+	for userType, stdType := range data.Options.TransformationRules.TypeMappings {
+		stdTypeMappings[userType] = strings.ToUpper(stdType.StandardType)
+	}
+	return stdTypeMappings
+}
+
+// getStdStatusMappings returns the standard statuses by the original statuses
+func getStdStatusMappings(data *ZentaoTaskData) map[string]string {
+	stdStatusMappings := make(map[string]string)
+	if data.Options.TransformationRules == nil {
+		return stdStatusMappings
+	}
+	for stdStatus, originalStatuses := range data.Options.TransformationRules.StatusMappings {
+		for _, originalStatus := range originalStatuses {
+			stdStatusMappings[originalStatus] = strings.ToUpper(stdStatus)
+		}
+	}
+	return stdStatusMappings
+}
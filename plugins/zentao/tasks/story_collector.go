@@ -80,4 +80,5 @@ var CollectStoryMeta = core.SubTaskMeta{
 	EntryPoint:       CollectStory,
 	EnabledByDefault: true,
 	Description:      "Collect Story data from Zentao api",
+	DomainTypes:      []string{core.DOMAIN_TYPE_TICKET},
 }
@@ -43,6 +43,8 @@ var ConvertStoryMeta = core.SubTaskMeta{
 func ConvertStory(taskCtx core.SubTaskContext) errors.Error {
 	data := taskCtx.GetData().(*ZentaoTaskData)
 	db := taskCtx.GetDal()
+	stdTypeMappings := getStdTypeMappings(data)
+	stdStatusMappings := getStdStatusMappings(data)
 	storyIdGen := didgen.NewDomainIdGenerator(&models.ZentaoStory{})
 	boardIdGen := didgen.NewDomainIdGenerator(&models.ZentaoProduct{})
 	cursor, err := db.Cursor(
@@ -97,6 +99,12 @@ func ConvertStory(taskCtx core.SubTaskContext) errors.Error {
 			default:
 				domainEntity.Status = ticket.IN_PROGRESS
 			}
+			if stdType := stdTypeMappings[domainEntity.OriginalType]; stdType != "" {
+				domainEntity.Type = stdType
+			}
+			if stdStatus := stdStatusMappings[domainEntity.OriginalStatus]; stdStatus != "" {
+				domainEntity.Status = stdStatus
+			}
 			if toolEntity.ClosedDate != nil {
 				domainEntity.LeadTimeMinutes = int64(toolEntity.ClosedDate.ToNullableTime().Sub(toolEntity.OpenedDate.ToTime()).Minutes())
 			}
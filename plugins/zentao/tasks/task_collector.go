@@ -78,4 +78,5 @@ var CollectTaskMeta = core.SubTaskMeta{
 	EntryPoint:       CollectTask,
 	EnabledByDefault: true,
 	Description:      "Collect Task data from Zentao api",
+	DomainTypes:      []string{core.DOMAIN_TYPE_TICKET},
 }
@@ -18,8 +18,12 @@ limitations under the License.
 package tasks
 
 import (
+	"encoding/json"
 	"fmt"
+
+	"github.com/apache/incubator-devlake/errors"
 	"github.com/apache/incubator-devlake/plugins/helper"
+	"github.com/apache/incubator-devlake/plugins/zentao/models"
 	"github.com/mitchellh/mapstructure"
 )
 
@@ -41,6 +45,42 @@ type ZentaoOptions struct {
 	ProjectId    int64
 	Tasks        []string `json:"tasks,omitempty"`
 	Since        string
+
+	TransformationRuleId uint64               `json:"transformationRuleId" mapstructure:"transformationRuleId,omitempty"`
+	TransformationRules  *TransformationRules `json:"transformationRules" mapstructure:"transformationRules,omitempty"`
+}
+
+type TypeMapping struct {
+	StandardType string `json:"standardType" mapstructure:"standardType"`
+}
+
+// TypeMappings maps the original types to the standard types
+type TypeMappings map[string]TypeMapping
+
+// StatusMappings maps the standard statuses to the original statuses
+type StatusMappings map[string][]string
+
+type TransformationRules struct {
+	TypeMappings   TypeMappings   `json:"typeMappings" mapstructure:"typeMappings"`
+	StatusMappings StatusMappings `json:"statusMappings" mapstructure:"statusMappings"`
+}
+
+// MakeTransformationRules decodes the mappings stored in the transformation rule
+func MakeTransformationRules(rule models.ZentaoTransformationRule) (*TransformationRules, errors.Error) {
+	result := &TransformationRules{}
+	if len(rule.TypeMappings) > 0 {
+		err := json.Unmarshal(rule.TypeMappings, &result.TypeMappings)
+		if err != nil {
+			return nil, errors.Default.Wrap(err, "unable to unmarshal the typeMappings")
+		}
+	}
+	if len(rule.StatusMappings) > 0 {
+		err := json.Unmarshal(rule.StatusMappings, &result.StatusMappings)
+		if err != nil {
+			return nil, errors.Default.Wrap(err, "unable to unmarshal the statusMappings")
+		}
+	}
+	return result, nil
 }
 
 type ZentaoTaskData struct {
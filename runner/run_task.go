@@ -227,14 +227,21 @@ func RunPluginSubTasks(
 		}
 	}
 
-	taskCtx := helper.NewDefaultTaskContext(ctx, basicRes, task.Plugin, subtasksFlag, progress)
-	if closeablePlugin, ok := pluginTask.(core.CloseablePluginTask); ok {
-		defer closeablePlugin.Close(taskCtx)
-	}
 	options, err := task.GetOptions()
 	if err != nil {
 		return err
 	}
+	// the pipeline id would be recorded along with the domain layer changes if CDC was enabled
+	ctx = helper.ContextWithPipelineId(ctx, task.PipelineId)
+	// tasks submitted by webhooks only process the raw data rows of the deliveries
+	ctx, err = helper.ContextWithRawDataIdsOption(ctx, options)
+	if err != nil {
+		return err
+	}
+	taskCtx := helper.NewDefaultTaskContext(ctx, basicRes, task.Plugin, subtasksFlag, progress)
+	if closeablePlugin, ok := pluginTask.(core.CloseablePluginTask); ok {
+		defer closeablePlugin.Close(taskCtx)
+	}
 	taskData, err := pluginTask.PrepareTaskData(taskCtx, options)
 	if err != nil {
 		return errors.Default.Wrap(err, fmt.Sprintf("error preparing task data for %s", task.Plugin))
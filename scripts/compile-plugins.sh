@@ -53,7 +53,12 @@ PIDS=""
 for PLUG in $PLUGINS; do
     NAME=$(basename $PLUG)
     echo "Building plugin $NAME to bin/plugins/$NAME/$NAME.so"
-    go build -buildmode=plugin "$@" -o $PLUGIN_OUTPUT_DIR/$NAME/$NAME.so $PLUG/*.go &
+    # gitextractor is built with libgit2, it falls back to go-git without the tag
+    TAGS=
+    if [ "$NAME" = "gitextractor" ]; then
+        TAGS="-tags=libgit2"
+    fi
+    go build -buildmode=plugin $TAGS "$@" -o $PLUGIN_OUTPUT_DIR/$NAME/$NAME.so $PLUG/*.go &
     PIDS="$PIDS $!"
 done
 
@@ -20,12 +20,11 @@ package services
 import (
 	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/apache/incubator-devlake/errors"
 	"github.com/apache/incubator-devlake/models"
-	"github.com/apache/incubator-devlake/models/domainlayer/crossdomain"
 	"github.com/apache/incubator-devlake/plugins/core"
-	"github.com/apache/incubator-devlake/plugins/core/dal"
 )
 
 // GeneratePlanJsonV200 generates pipeline plan according v2.0.0 definition
@@ -52,21 +51,10 @@ func GeneratePlanJsonV200(
 	}
 	// refresh project_mapping table to reflect project/scopes relationship
 	if len(projectName) != 0 {
-		err = db.Delete(&crossdomain.ProjectMapping{}, dal.Where("project_name = ?", projectName))
+		err = refreshProjectMapping(db, projectName, scopes, time.Now())
 		if err != nil {
 			return nil, err
 		}
-		for _, scope := range scopes {
-			projectMapping := &crossdomain.ProjectMapping{
-				ProjectName: projectName,
-				Table:       scope.TableName(),
-				RowId:       scope.ScopeId(),
-			}
-			err = db.Create(projectMapping)
-			if err != nil {
-				return nil, err
-			}
-		}
 	}
 	return plan, err
 }
@@ -125,11 +113,17 @@ func genPlanJsonV200(
 		if err != nil {
 			return nil, nil, err
 		}
-		if pluginBp, ok := plugin.(core.MetricPluginBlueprintV200); ok {
-			// If we enable one metric plugin, even if it has nil option, we still process it
-			if len(metricPluginOptJson) == 0 {
-				metricPluginOptJson = json.RawMessage("{}")
+		// If we enable one metric plugin, even if it has nil option, we still process it
+		if len(metricPluginOptJson) == 0 {
+			metricPluginOptJson = json.RawMessage("{}")
+		}
+		if pluginBp, ok := plugin.(core.ScopedMetricPluginBlueprintV200); ok {
+			metricPlans[i], err = pluginBp.MakeScopedMetricPluginPipelinePlanV200(projectName, metricPluginOptJson, scopes)
+			if err != nil {
+				return nil, nil, err
 			}
+			i += 1
+		} else if pluginBp, ok := plugin.(core.MetricPluginBlueprintV200); ok {
 			metricPlans[i], err = pluginBp.MakeMetricPluginPipelinePlanV200(projectName, metricPluginOptJson)
 			if err != nil {
 				return nil, nil, err
@@ -0,0 +1,49 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package services
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/apache/incubator-devlake/helpers/cdc"
+)
+
+// cdcServiceInit starts relaying the events recorded in the outbox (see `CDC_ENABLED`) to the `CDC_PUBLISHERS`,
+// and purging them after the `CDC_RETENTION`
+func cdcServiceInit() {
+	publisherUrls := strings.TrimSpace(cfg.GetString("CDC_PUBLISHERS"))
+	enabled := cfg.GetBool("CDC_ENABLED")
+	if publisherUrls == "" && !enabled {
+		return
+	}
+	if !enabled {
+		log.Warn(nil, "CDC_PUBLISHERS is set but CDC_ENABLED is false, no event would be recorded")
+	}
+	publishers, err := cdc.NewPublishers(publisherUrls)
+	if err != nil {
+		panic(err)
+	}
+	interval := cfg.GetDuration("CDC_PUBLISH_INTERVAL")
+	if interval <= 0 {
+		interval = 5 * time.Second
+	}
+	relay := cdc.NewRelay(db, log.Nested("cdc"), publishers, cfg.GetInt("CDC_PUBLISH_BATCH_SIZE"), cfg.GetDuration("CDC_RETENTION"))
+	go relay.Run(context.Background(), interval)
+}
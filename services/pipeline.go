@@ -60,6 +60,9 @@ func pipelineServiceInit() {
 		notificationService = NewNotificationService(notificationEndpoint, notificationSecret)
 	}
 
+	// change data capture
+	cdcServiceInit()
+
 	// temporal client
 	var temporalUrl = cfg.GetString("TEMPORAL_URL")
 	if temporalUrl != "" {
@@ -107,6 +110,16 @@ func pipelineServiceInit() {
 	}
 	// run pipeline with independent goroutine
 	go RunPipelineInQueue(pipelineMaxParallel)
+	core.RegisterPipelineSubmitter(submitPipeline)
+}
+
+// submitPipeline queues a pipeline on behalf of a plugin, see core.SubmitPipeline
+func submitPipeline(name string, plan core.PipelinePlan, labels []string) (uint64, errors.Error) {
+	dbPipeline, err := CreateDbPipeline(&models.NewPipeline{Name: name, Plan: plan, Labels: labels})
+	if err != nil {
+		return 0, err
+	}
+	return dbPipeline.ID, nil
 }
 
 // CreatePipeline and return the model
@@ -204,6 +204,16 @@ func PatchProject(name string, body map[string]interface{}) (*models.ApiOutputPr
 			return nil, err
 		}
 
+		// ProjectMappingHistory
+		err = tx.UpdateColumn(
+			&crossdomain.ProjectMappingHistory{},
+			"project_name", project.Name,
+			dal.Where("project_name = ?", name),
+		)
+		if err != nil {
+			return nil, err
+		}
+
 		// Blueprint
 		err = tx.UpdateColumn(
 			&models.DbBlueprint{},
@@ -0,0 +1,97 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package services
+
+import (
+	"time"
+
+	"github.com/apache/incubator-devlake/errors"
+	"github.com/apache/incubator-devlake/models/domainlayer/crossdomain"
+	"github.com/apache/incubator-devlake/plugins/core"
+	"github.com/apache/incubator-devlake/plugins/core/dal"
+)
+
+// refreshProjectMapping replaces the `project_mapping` of the project with the scopes, and keeps the history by
+// closing the periods of the removed scopes and opening periods for the added ones in `project_mapping_history`,
+// the period of a scope mapped to the project for the first time is unbounded at the start
+func refreshProjectMapping(tx dal.Dal, projectName string, scopes []core.Scope, now time.Time) errors.Error {
+	err := tx.Delete(&crossdomain.ProjectMapping{}, dal.Where("project_name = ?", projectName))
+	if err != nil {
+		return err
+	}
+	type mappingKey struct {
+		table string
+		rowId string
+	}
+	mappings := make(map[mappingKey]bool, len(scopes))
+	for _, scope := range scopes {
+		key := mappingKey{table: scope.TableName(), rowId: scope.ScopeId()}
+		if mappings[key] {
+			continue
+		}
+		mappings[key] = true
+		err = tx.Create(&crossdomain.ProjectMapping{
+			ProjectName: projectName,
+			Table:       key.table,
+			RowId:       key.rowId,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	var histories []crossdomain.ProjectMappingHistory
+	err = tx.All(&histories, dal.Where("project_name = ?", projectName))
+	if err != nil {
+		return err
+	}
+	mappedBefore := make(map[mappingKey]bool, len(histories))
+	for _, history := range histories {
+		key := mappingKey{table: history.Table, rowId: history.RowId}
+		mappedBefore[key] = true
+		if history.ValidTo != nil {
+			continue
+		}
+		if mappings[key] {
+			// still mapped, nothing changed
+			delete(mappings, key)
+			continue
+		}
+		history.ValidTo = &now
+		err = tx.Update(&history)
+		if err != nil {
+			return err
+		}
+	}
+	for key := range mappings {
+		validFrom := now
+		if !mappedBefore[key] {
+			validFrom = crossdomain.UnboundedValidFrom
+		}
+		err = tx.Create(&crossdomain.ProjectMappingHistory{
+			ProjectName: projectName,
+			Table:       key.table,
+			RowId:       key.rowId,
+			ValidFrom:   validFrom,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/apache/incubator-devlake/mocks"
+	"github.com/apache/incubator-devlake/models/domainlayer"
+	"github.com/apache/incubator-devlake/models/domainlayer/code"
+	"github.com/apache/incubator-devlake/models/domainlayer/crossdomain"
+	"github.com/apache/incubator-devlake/models/domainlayer/ticket"
+	"github.com/apache/incubator-devlake/plugins/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestRefreshProjectMapping(t *testing.T) {
+	removedAt := time.Date(2023, 1, 10, 0, 0, 0, 0, time.UTC)
+	now := time.Date(2023, 1, 20, 0, 0, 0, 0, time.UTC)
+	// repo1 is still mapped, repo2 was mapped and removed before, and board1 is going to be removed
+	histories := []crossdomain.ProjectMappingHistory{
+		{ProjectName: "project1", Table: "repos", RowId: "repo1", ValidFrom: crossdomain.UnboundedValidFrom},
+		{ProjectName: "project1", Table: "repos", RowId: "repo2", ValidFrom: crossdomain.UnboundedValidFrom, ValidTo: &removedAt},
+		{ProjectName: "project1", Table: "boards", RowId: "board1", ValidFrom: crossdomain.UnboundedValidFrom},
+	}
+	var created []*crossdomain.ProjectMappingHistory
+	var updated []*crossdomain.ProjectMappingHistory
+	mockDal := new(mocks.Dal)
+	mockDal.On("Delete", mock.Anything, mock.Anything).Return(nil)
+	mockDal.On("All", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		*args.Get(0).(*[]crossdomain.ProjectMappingHistory) = histories
+	}).Return(nil)
+	mockDal.On("Create", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		if history, ok := args.Get(0).(*crossdomain.ProjectMappingHistory); ok {
+			created = append(created, history)
+		}
+	}).Return(nil)
+	mockDal.On("Update", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		history := *args.Get(0).(*crossdomain.ProjectMappingHistory)
+		updated = append(updated, &history)
+	}).Return(nil)
+
+	scopes := []core.Scope{
+		&code.Repo{DomainEntity: domainlayer.DomainEntity{Id: "repo1"}},
+		&code.Repo{DomainEntity: domainlayer.DomainEntity{Id: "repo2"}},
+		&code.Repo{DomainEntity: domainlayer.DomainEntity{Id: "repo3"}},
+		&ticket.Board{DomainEntity: domainlayer.DomainEntity{Id: "board2"}},
+	}
+	err := refreshProjectMapping(mockDal, "project1", scopes, now)
+	assert.Nil(t, err)
+
+	if assert.Len(t, updated, 1) {
+		assert.Equal(t, "board1", updated[0].RowId)
+		assert.Equal(t, now, *updated[0].ValidTo)
+	}
+	validFrom := make(map[string]time.Time)
+	for _, history := range created {
+		assert.Nil(t, history.ValidTo)
+		validFrom[history.RowId] = history.ValidFrom
+	}
+	// the scopes mapped for the first time are valid since ever, the ones mapped again only from now on
+	assert.Equal(t, map[string]time.Time{
+		"repo2":  now,
+		"repo3":  crossdomain.UnboundedValidFrom,
+		"board2": crossdomain.UnboundedValidFrom,
+	}, validFrom)
+}